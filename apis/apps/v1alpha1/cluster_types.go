@@ -19,7 +19,9 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -80,6 +82,12 @@ type ClusterSpec struct {
 	// +optional
 	Affinity *Affinity `json:"affinity,omitempty"`
 
+	// componentAffinities declares the topology constraints between the components, e.g. co-locating the pods of
+	// a cache component with the ones of the database component, or spreading the pods of two components apart.
+	// They are translated into the pod affinity or anti-affinity of the components, referring to the pods of the others.
+	// +optional
+	ComponentAffinities []ComponentAffinity `json:"componentAffinities,omitempty"`
+
 	// tolerations are attached to tolerate any taint that matches the triple `key,value,effect` using the matching operator `operator`.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
@@ -101,6 +109,25 @@ type ClusterSpec struct {
 	// +optional
 	Monitor ClusterMonitor `json:"monitor,omitempty"`
 
+	// lightweightMode omits the probe and exporter sidecars of all components to save resources,
+	// e.g. for the tiny clusters of edge deployments. The roles of replicas are unknown then,
+	// and the phase of components is derived from the readiness of workloads only.
+	// Switching it will roll the pods of all components.
+	// +optional
+	LightweightMode bool `json:"lightweightMode,omitempty"`
+
+	// imagePullPolicy overrides the image pull policy of all containers of the components.
+	// +kubebuilder:validation:Enum={Always,Never,IfNotPresent}
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// pinImagesByDigest resolves the image tags of the components to digests at the first render,
+	// records them in status.pinnedImages and uses the pinned digests for all subsequent renders,
+	// so the mutation of the tags upstream never changes the running images and upgrades are explicit.
+	// It can also be enabled by the annotation `apps.kubeblocks.io/pin-images-by-digest: "true"`.
+	// +optional
+	PinImagesByDigest bool `json:"pinImagesByDigest,omitempty"`
+
 	// network specifies the configuration of network
 	// +optional
 	Network *ClusterNetwork `json:"network,omitempty"`
@@ -108,6 +135,15 @@ type ClusterSpec struct {
 	// cluster backup configuration.
 	// +optional
 	Backup *ClusterBackup `json:"backup,omitempty"`
+
+	// externalDependencies overrides the external dependencies of the same names declared by the ClusterDefinition,
+	// and the others are appended, e.g. to point the object store to archive the WALs to the one of the cluster.
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ExternalDependencies []ExternalDependency `json:"externalDependencies,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
 }
 
 type ClusterBackup struct {
@@ -209,6 +245,11 @@ type ClusterStatus struct {
 	// Describe current state of cluster API Resource, like warning.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// pinnedImages records the images of the components pinned by digest if spec.pinImagesByDigest is enabled,
+	// the key is the image referenced by tag and the value is the image referenced by digest.
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
 }
 
 // ClusterComponentSpec defines the cluster component spec.
@@ -247,6 +288,22 @@ type ClusterComponentSpec struct {
 	// +optional
 	Monitor bool `json:"monitor,omitempty"`
 
+	// monitorResource specifies the kind of the prometheus-operator resource created to scrape the exporter of the component
+	// when monitor is enabled, ServiceMonitor scrapes the pods through the headless service and PodMonitor scrapes the pods directly.
+	// No resource is created if not set, the metrics are scraped through the monitor annotations of the headless service then.
+	// +optional
+	MonitorResource MonitorResourceKind `json:"monitorResource,omitempty"`
+
+	// monitorScrapeInterval specifies the interval of scraping the exporter by the monitor resource, e.g. 30s, 1m.
+	// The scrape interval of Prometheus is used if not set.
+	// +kubebuilder:validation:Pattern:=`^(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$`
+	// +optional
+	MonitorScrapeInterval string `json:"monitorScrapeInterval,omitempty"`
+
+	// monitorRelabelings specifies the relabeling rules applied to the scrape targets by the monitor resource.
+	// +optional
+	MonitorRelabelings []MonitorRelabelConfig `json:"monitorRelabelings,omitempty"`
+
 	// enabledLogs indicates which log file takes effect in the database cluster.
 	// element is the log type which is defined in cluster definition logConfig.name,
 	// and will set relative variables about this log type in database kernel.
@@ -280,10 +337,53 @@ type ClusterComponentSpec struct {
 	// +patchStrategy=merge,retainKeys
 	VolumeClaimTemplates []ClusterComponentVolumeClaimTemplate `json:"volumeClaimTemplates,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
 
+	// volumeClaimRetentionPolicy defines what happens to the PVCs when their volume claim templates are removed from
+	// the component. Retain keeps the PVCs, and Delete deletes them once the workload has dropped the templates and no pod
+	// mounts them. The PVCs of the removed templates are retained by default.
+	// +kubebuilder:default=Retain
+	// +optional
+	VolumeClaimRetentionPolicy VolumeClaimRetentionPolicyType `json:"volumeClaimRetentionPolicy,omitempty"`
+
+	// tmpfsVolumes defines the memory-medium emptyDir volumes mounted into the containers of the component,
+	// which can be used as tmpfs-backed scratch dirs, e.g. caches.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=name
+	TmpfsVolumes []ClusterComponentTmpfsVolume `json:"tmpfsVolumes,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+
+	// initScriptConfigMap is the name of the ConfigMap holding the bootstrap scripts of the component, e.g. the SQL
+	// files creating the schemas and users. The ConfigMap is mounted at initScriptMountPath of the initScriptContainers,
+	// and the path is passed to the bootstrap process by the env KB_INIT_SCRIPTS_DIR. It's up to the bootstrap process
+	// to run the scripts, typically only once when the data directory is initialized.
+	// +optional
+	InitScriptConfigMap string `json:"initScriptConfigMap,omitempty"`
+
+	// initScriptMountPath is the path to mount the initScriptConfigMap at, it defaults to /kb-init-scripts.
+	// The path is mounted read-only, it shouldn't be a directory the containers write to, e.g. /docker-entrypoint-initdb.d
+	// which some of the engines copy their own scripts into.
+	// +kubebuilder:validation:Pattern=`^/.*`
+	// +optional
+	InitScriptMountPath string `json:"initScriptMountPath,omitempty"`
+
+	// initScriptContainers are the names of the containers and the init containers to mount the initScriptConfigMap
+	// into, only the main container, the first one of the component, is mounted if it's empty.
+	// +listType=set
+	// +optional
+	InitScriptContainers []string `json:"initScriptContainers,omitempty"`
+
 	// Services expose endpoints that can be accessed by clients.
 	// +optional
 	Services []ClusterComponentService `json:"services,omitempty"`
 
+	// sessionAffinity enables the client IP based session affinity of the default service of the component,
+	// which is declared by the component definition, if set to ClientIP. The services declared in services
+	// set their own session affinity. It defaults to None.
+	// +kubebuilder:validation:Enum={ClientIP,None}
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+
 	// switchPolicy defines the strategy for switchover and failover when workloadType is Replication.
 	// +optional
 	SwitchPolicy *ClusterSwitchPolicy `json:"switchPolicy,omitempty"`
@@ -301,11 +401,103 @@ type ClusterComponentSpec struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// schedulerName is the name of the scheduler to schedule the pods of the component, e.g. volcano.
+	// If not specified, the pods will be scheduled by the default scheduler.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// priorityClassName is the name of the PriorityClass to rank the pods of the component at scheduling time,
+	// e.g. to preempt the pods of batch workloads. The preemption policy follows the PriorityClass.
+	// If not specified, the priorityClassName of the podSpec in ClusterDefinition is used.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// evictionPolicy hints how the pods of the component are ranked to evict under node pressure, e.g. evicting the
+	// pods of a proxy component before the ones of the database component.
+	// +optional
+	EvictionPolicy *ComponentEvictionPolicy `json:"evictionPolicy,omitempty"`
+
+	// schedulingPolicy defines the scheduling settings copied verbatim onto the pods of the component, for the cases
+	// beyond what the affinity abstraction offers, e.g. spreading the pods by custom constraints.
+	// +optional
+	SchedulingPolicy *SchedulingPolicy `json:"schedulingPolicy,omitempty"`
+
 	// noCreatePDB defines the PodDisruptionBudget creation behavior and is set to true if creation of PodDisruptionBudget
 	// for this component is not needed. It defaults to false.
 	// +kubebuilder:default=false
 	// +optional
 	NoCreatePDB bool `json:"noCreatePDB,omitempty"`
+
+	// ordinalStart is the number representing the first replica's index of the component, it's used to
+	// derive the pod names and the per-instance hostnames. The replicas will get the ordinals in the range
+	// [ordinalStart, ordinalStart+replicas). It's useful in blue/green style migrations to prevent the new
+	// pod names from colliding with the old ones. It defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	OrdinalStart int32 `json:"ordinalStart,omitempty"`
+
+	// podNamePrefix overrides the prefix of the pod names of the component, which defaults to "<cluster>-<component>".
+	// The pods are named "<podNamePrefix>-<ordinal>", and so are the per-instance hostnames and the PVCs. It's useful
+	// in blue/green style migrations to keep the pod names of the new cluster apart from the old ones in the shared DNS.
+	// It can't be changed once the component is created.
+	// +kubebuilder:validation:MaxLength=52
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([a-z0-9\-]*[a-z0-9])?$`
+	// +optional
+	PodNamePrefix string `json:"podNamePrefix,omitempty"`
+
+	// evictionProtection marks the pods of specified roles, e.g. the current primary, with annotations that
+	// make them resist the voluntary eviction of the descheduler or cluster autoscaler. The annotations are
+	// refreshed as the roles of the pods change.
+	// +optional
+	EvictionProtection *EvictionProtection `json:"evictionProtection,omitempty"`
+
+	// injectCPULimitEnv injects the env var KB_CPU_LIMIT, the CPU limit of the container in whole cores, into the
+	// containers of the component, e.g. to size the thread pools or set GOMAXPROCS by $(KB_CPU_LIMIT). The fractional
+	// limits are rounded up, and the CPU request is used if the limit is not set. It's not injected if neither is set.
+	// +optional
+	InjectCPULimitEnv bool `json:"injectCPULimitEnv,omitempty"`
+
+	// roleServices enables creating a ClusterIP service named <cluster>-<component>-<role> for each role of the component,
+	// in addition to the default service. The services select the pods by the role label, so they always route
+	// to the pods currently playing the role, e.g. for read/write splitting. The services of the component
+	// can't be named after the roles then.
+	// +optional
+	RoleServices bool `json:"roleServices,omitempty"`
+
+	// startupProbe overrides the startup probe of the main container, i.e. the first container, of the component,
+	// which defaults to the one defined in the clusterDefinition. It's useful for the slow-starting databases, as
+	// the liveness and readiness probes are held until the startup probe succeeds, and the container is restarted
+	// only if the startup probe fails in a row for failureThreshold times, i.e. the restart budget of the startup.
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// credentialRotation rotates the password of the connection credential periodically by a RotateCredential
+	// OpsRequest, which changes the password on the engine before updating the secret. The previous password
+	// is kept in the secret for a grace period, and the pods referring to the secret are restarted in a rolling
+	// way to pick up the new password. If multiple components set it, the shortest period wins, as the connection
+	// credential is shared by the components of the cluster.
+	// +optional
+	CredentialRotation *CredentialRotation `json:"credentialRotation,omitempty"`
+
+	// dnsSearchDomains are the extra DNS search domains appended to the pods of the component, e.g.
+	// <namespace>.svc.cluster.local, so the services in other namespaces can be resolved by the short names.
+	// +listType=set
+	// +optional
+	DNSSearchDomains []string `json:"dnsSearchDomains,omitempty"`
+
+	// workloadAnnotations are the annotations set on the workload object of the component itself rather than
+	// on the pod template, e.g. for the GitOps tools to ignore the workload. The keys managed by KubeBlocks
+	// are ignored.
+	// +optional
+	WorkloadAnnotations map[string]string `json:"workloadAnnotations,omitempty"`
+
+	// probeOverrides tunes the timing of the liveness, readiness and startup probes of the containers defined in
+	// the clusterDefinition, e.g. to relax the timeouts on slow storage. Only the timing parameters are overridden,
+	// the handlers are kept as defined.
+	// +listType=map
+	// +listMapKey=containerName
+	// +optional
+	ProbeOverrides []ContainerProbeOverride `json:"probeOverrides,omitempty"`
 }
 
 // GetMinAvailable wraps the 'prefer' value return. As for component replicaCount <= 1, it will return 0,
@@ -324,6 +516,166 @@ func (r *ClusterComponentSpec) GetMinAvailable(prefer *intstr.IntOrString) *ints
 	return prefer
 }
 
+// CredentialRotation defines the schedule to rotate the password of the connection credential.
+type CredentialRotation struct {
+	// period is the interval between two rotations, in the form of <number><unit>, the unit is one of d(days),
+	// h(hours) and m(minutes), e.g. 30d.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^[1-9][0-9]*(d|h|m)$`
+	Period string `json:"period"`
+
+	// gracePeriod is the duration the previous password is kept valid after the rotation, in the same form as
+	// the period. It defaults to 1h.
+	// +kubebuilder:validation:Pattern:=`^[0-9]+(d|h|m)$`
+	// +optional
+	GracePeriod string `json:"gracePeriod,omitempty"`
+
+	// statement is the template of the statement changing the password on the engine, which should keep the
+	// previous password valid for the grace period, such as "ALTER USER $(USERNAME) IDENTIFIED BY '$(PASSWD)'
+	// RETAIN CURRENT PASSWORD" of MySQL. If not specified, the update statement of the system accounts is used,
+	// with which only the new password works on the engine after the rotation.
+	// +optional
+	Statement string `json:"statement,omitempty"`
+}
+
+// defaultCredentialGracePeriod is the default duration the previous password is kept valid.
+const defaultCredentialGracePeriod = time.Hour
+
+// GetPeriod parses the period of the rotation.
+func (r *CredentialRotation) GetPeriod() (time.Duration, error) {
+	return parseRotationDuration(r.Period)
+}
+
+// GetGracePeriod parses the grace period of the rotation, it returns the default if not set.
+func (r *CredentialRotation) GetGracePeriod() (time.Duration, error) {
+	if len(r.GracePeriod) == 0 {
+		return defaultCredentialGracePeriod, nil
+	}
+	return parseRotationDuration(r.GracePeriod)
+}
+
+// GetGracePeriod parses the grace period of the previous password, it returns 0 if not set.
+func (r *RotateCredential) GetGracePeriod() (time.Duration, error) {
+	if len(r.GracePeriod) == 0 {
+		return 0, nil
+	}
+	return parseRotationDuration(r.GracePeriod)
+}
+
+// parseRotationDuration parses the duration in the form of <number><unit>, the unit is one of d, h and m.
+func parseRotationDuration(value string) (time.Duration, error) {
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid duration: %q", value)
+	}
+	n, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid duration: %q", value)
+	}
+	switch value[len(value)-1] {
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, nil
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'm':
+		return time.Duration(n) * time.Minute, nil
+	default:
+		return 0, fmt.Errorf("invalid unit of duration: %q", value)
+	}
+}
+
+// MonitorRelabelConfig is a relabeling rule of Prometheus applied to the scrape targets.
+type MonitorRelabelConfig struct {
+	// sourceLabels selects the values of the existing labels, which are concatenated by the separator.
+	// +optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+
+	// separator is placed between the concatenated values of the source labels, defaults to ';'.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+
+	// targetLabel is the label the result is written to, it's required by the replace and hashmod actions.
+	// +optional
+	TargetLabel string `json:"targetLabel,omitempty"`
+
+	// regex is matched against the concatenated values of the source labels, defaults to '(.*)'.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// modulus is taken of the hash of the concatenated values of the source labels by the hashmod action.
+	// +optional
+	Modulus uint64 `json:"modulus,omitempty"`
+
+	// replacement is written to the target label if the regex matches, defaults to '$1'.
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+
+	// action is performed based on the regex matching, defaults to replace.
+	// +kubebuilder:validation:Enum={replace,keep,drop,hashmod,labelmap,labeldrop,labelkeep}
+	// +optional
+	Action string `json:"action,omitempty"`
+}
+
+// ContainerProbeOverride overrides the timing of the probes of a container.
+type ContainerProbeOverride struct {
+	// containerName is the name of the container defined in the clusterDefinition.
+	// +kubebuilder:validation:Required
+	ContainerName string `json:"containerName"`
+
+	// livenessProbe overrides the timing of the liveness probe.
+	// +optional
+	LivenessProbe *ProbeTimingOverride `json:"livenessProbe,omitempty"`
+
+	// readinessProbe overrides the timing of the readiness probe.
+	// +optional
+	ReadinessProbe *ProbeTimingOverride `json:"readinessProbe,omitempty"`
+
+	// startupProbe overrides the timing of the startup probe.
+	// +optional
+	StartupProbe *ProbeTimingOverride `json:"startupProbe,omitempty"`
+}
+
+// ProbeTimingOverride defines the timing parameters of a probe, the ones not set are kept as defined.
+type ProbeTimingOverride struct {
+	// initialDelaySeconds is the number of seconds after the container has started before the probe is initiated.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// periodSeconds is how often in seconds to perform the probe.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// timeoutSeconds is the number of seconds after which the probe times out.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// failureThreshold is the minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// ApplyTo overrides the timing parameters of the probe.
+func (r *ProbeTimingOverride) ApplyTo(probe *corev1.Probe) {
+	if r == nil || probe == nil {
+		return
+	}
+	if r.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *r.InitialDelaySeconds
+	}
+	if r.PeriodSeconds != nil {
+		probe.PeriodSeconds = *r.PeriodSeconds
+	}
+	if r.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *r.TimeoutSeconds
+	}
+	if r.FailureThreshold != nil {
+		probe.FailureThreshold = *r.FailureThreshold
+	}
+}
+
 type ComponentMessageMap map[string]string
 
 // ClusterComponentStatus records components status.
@@ -353,6 +705,21 @@ type ClusterComponentStatus struct {
 	// +optional
 	PodsReadyTime *metav1.Time `json:"podsReadyTime,omitempty"`
 
+	// podsSummary summarizes the readiness and the failure reasons of the pods of the component,
+	// e.g. "2/3 pods ready, 1 ImagePullBackOff", it's updated in each reconciliation.
+	// +optional
+	PodsSummary string `json:"podsSummary,omitempty"`
+
+	// podNodes records the names of the nodes hosting the pods of the component, keyed by the pod names.
+	// The pods not scheduled yet are omitted.
+	// +optional
+	PodNodes map[string]string `json:"podNodes,omitempty"`
+
+	// sharedNodes lists the nodes hosting multiple pods of the component, which usually means the pod
+	// anti-affinity of the component is not satisfied.
+	// +optional
+	SharedNodes []string `json:"sharedNodes,omitempty"`
+
 	// consensusSetStatus specifies the mapping of role and pod name.
 	// +optional
 	// +kubebuilder:deprecatedversion:warning="This field is deprecated from KB 0.7.0, use MembersStatus instead."
@@ -366,6 +733,128 @@ type ClusterComponentStatus struct {
 	// members' status.
 	// +optional
 	MembersStatus []workloads.MemberStatus `json:"membersStatus,omitempty"`
+
+	// leaderPod is the name of the current leader pod of the Consensus component or the primary pod
+	// of the Replication component, it's updated on failover.
+	// +optional
+	LeaderPod string `json:"leaderPod,omitempty"`
+
+	// paused indicates that the component is paused by the annotation kubeblocks.io/component-paused,
+	// the workloads of the component will not be updated until it is unpaused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// observedGeneration is the most recent generation of the cluster in which the spec change of the component
+	// has been reconciled successfully.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// systemAccounts records the provisioning states of the system accounts of the component.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SystemAccounts []SystemAccountStatus `json:"systemAccounts,omitempty"`
+
+	// configVersions records the versions, i.e. the hashes of the rendered configs, of the configs the pods of
+	// the component are running with, keyed by the pod names. For the components with multiple config specs, the
+	// versions of the config specs are joined by commas in the order of the config specs.
+	// +optional
+	ConfigVersions map[string]string `json:"configVersions,omitempty"`
+
+	// configSynced checks if all pods of the component are running with the latest rendered configs.
+	// +optional
+	ConfigSynced *bool `json:"configSynced,omitempty"`
+
+	// oomKilledContainers records the containers of the component killed by the out-of-memory killer recently.
+	// The component is Abnormal once a container is killed repeatedly within a short window, even if the pods
+	// are restarted in place and become ready again, until the container has been stable for a while.
+	// +optional
+	OOMKilledContainers []OOMKilledContainerStatus `json:"oomKilledContainers,omitempty"`
+
+	// splitBrainSince is the time since which multiple pods of the component claim the primary. The split-brain
+	// is recovered only if it persists for several intervals of the role probe, so a transient overlap of the
+	// roles during a failover is tolerated. It's cleared once a single pod claims the primary.
+	// +optional
+	SplitBrainSince *metav1.Time `json:"splitBrainSince,omitempty"`
+
+	// volumeCapacities records the requested and the actual capacities of the bound PVCs of the replicas,
+	// it's updated in each reconciliation and reflects the progress of the volume expansion.
+	// +optional
+	VolumeCapacities []VolumeCapacityStatus `json:"volumeCapacities,omitempty"`
+}
+
+// VolumeCapacityStatus records the capacities of a bound PVC of a replica.
+type VolumeCapacityStatus struct {
+	// podName is the name of the pod the PVC belongs to.
+	// +kubebuilder:validation:Required
+	PodName string `json:"podName"`
+
+	// volumeClaimTemplateName is the name of the volume claim template the PVC is created from.
+	// +kubebuilder:validation:Required
+	VolumeClaimTemplateName string `json:"volumeClaimTemplateName"`
+
+	// requested is the storage requested by the PVC.
+	// +kubebuilder:validation:Required
+	Requested resource.Quantity `json:"requested"`
+
+	// capacity is the actual capacity of the volume bound to the PVC, it's less than the requested one
+	// until the volume expansion completes.
+	// +kubebuilder:validation:Required
+	Capacity resource.Quantity `json:"capacity"`
+
+	// utilization is the used percentage of the volume reported by the kubelet, it's absent if the volume stats
+	// are unavailable or the utilization check is disabled.
+	// +optional
+	Utilization *int32 `json:"utilization,omitempty"`
+}
+
+// OOMKilledContainerStatus records the out-of-memory kills of a container.
+type OOMKilledContainerStatus struct {
+	// podName is the name of the pod.
+	// +kubebuilder:validation:Required
+	PodName string `json:"podName"`
+
+	// containerName is the name of the container.
+	// +kubebuilder:validation:Required
+	ContainerName string `json:"containerName"`
+
+	// killedTimes are the times of the latest kills of the container.
+	// +optional
+	KilledTimes []metav1.Time `json:"killedTimes,omitempty"`
+
+	// restartCount is the restart count of the container observed last time. The restarts since then are counted
+	// as the kills if the last termination is by the out-of-memory killer, as the kills between two observations
+	// are not visible from the termination states.
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty"`
+
+	// abnormal indicates the container has been killed repeatedly within the window, it's cleared once
+	// the container has not been killed for the stable period.
+	// +optional
+	Abnormal bool `json:"abnormal,omitempty"`
+}
+
+// SystemAccountStatus records the provisioning state of a system account.
+type SystemAccountStatus struct {
+	// name is the name of the system account.
+	// +kubebuilder:validation:Required
+	Name AccountName `json:"name"`
+
+	// phase is the provisioning phase of the account.
+	// +kubebuilder:validation:Required
+	Phase AccountProvisionPhase `json:"phase"`
+
+	// attempts is the number of the provisioning attempts, it restarts from 1 if the secret of the provisioned account is deleted.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// lastAttemptTime is the time of the last provisioning attempt.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// message records the details of the last provisioning attempt.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type ConsensusSetStatus struct {
@@ -427,6 +916,47 @@ type ClusterSwitchPolicy struct {
 	// +kubebuilder:default=Noop
 	// +optional
 	Type SwitchPolicyType `json:"type"`
+
+	// splitBrainRecovery defines how to choose the primary to keep when multiple pods claim the primary,
+	// the others are demoted. NewestDataWins keeps the one promoted in the latest term reported by the role probe,
+	// and LowestOrdinalWins keeps the one with the lowest ordinal. It defaults to NewestDataWins.
+	// +optional
+	SplitBrainRecovery SplitBrainRecoveryPolicy `json:"splitBrainRecovery,omitempty"`
+}
+
+// GetSplitBrainRecovery returns the split-brain recovery policy, it returns the default if not set.
+func (r *ClusterSwitchPolicy) GetSplitBrainRecovery() SplitBrainRecoveryPolicy {
+	if r == nil || len(r.SplitBrainRecovery) == 0 {
+		return NewestDataWins
+	}
+	return r.SplitBrainRecovery
+}
+
+type EvictionProtection struct {
+	// roles are the roles whose pods are protected from eviction.
+	// If not specified, the pods of the leader roles are protected.
+	// +listType=set
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+
+	// annotations are added to the protected pods and removed once the pods are no longer protected.
+	// If not specified, cluster-autoscaler.kubernetes.io/safe-to-evict is set to "false".
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ClusterComponentTmpfsVolume struct {
+	// name of the volume, must be unique in the component.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// sizeLimit is the total amount of memory the volume can use, which must be positive.
+	// +kubebuilder:validation:Required
+	SizeLimit resource.Quantity `json:"sizeLimit"`
+
+	// mountPath is the path within the containers at which the volume should be mounted.
+	// +kubebuilder:validation:Required
+	MountPath string `json:"mountPath"`
 }
 
 type ClusterComponentVolumeClaimTemplate struct {
@@ -436,6 +966,32 @@ type ClusterComponentVolumeClaimTemplate struct {
 	// spec defines the desired characteristics of a volume requested by a pod author.
 	// +optional
 	Spec PersistentVolumeClaimSpec `json:"spec,omitempty"`
+	// ephemeral renders the volume as an ephemeral volume of the pods rather than a PVC of the workload, which
+	// is removed along with the pod. It's rendered as a generic ephemeral volume if the storageClassName is set,
+	// otherwise as an emptyDir volume with the sizeLimit of the requested storage. It's useful for the cache-type
+	// volumes, e.g. of the proxies and query caches, but the data volumes of the stateful workloads may not be
+	// ephemeral. The ephemeral volumes are skipped by the backups.
+	// +optional
+	Ephemeral bool `json:"ephemeral,omitempty"`
+}
+
+// ToEphemeralVolume converts the ephemeral volume claim template to the pod volume.
+func (r *ClusterComponentVolumeClaimTemplate) ToEphemeralVolume() corev1.Volume {
+	volume := corev1.Volume{Name: r.Name}
+	if r.Spec.StorageClassName != nil {
+		volume.Ephemeral = &corev1.EphemeralVolumeSource{
+			VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+				Spec: r.Spec.ToV1PersistentVolumeClaimSpec(),
+			},
+		}
+		return volume
+	}
+	volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	if storage, ok := r.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		sizeLimit := storage.DeepCopy()
+		volume.EmptyDir.SizeLimit = &sizeLimit
+	}
+	return volume
 }
 
 func (r *ClusterComponentVolumeClaimTemplate) toVolumeClaimTemplate() corev1.PersistentVolumeClaimTemplate {
@@ -521,6 +1077,55 @@ type Affinity struct {
 	Tenancy TenancyType `json:"tenancy,omitempty"`
 }
 
+// ComponentAffinity defines the topology constraint of the pods of a component relative to the pods of another component.
+type ComponentAffinity struct {
+	// componentName is the name of the component whose pods are constrained.
+	// +kubebuilder:validation:Required
+	ComponentName string `json:"componentName"`
+
+	// targetComponentName is the name of the component whose pods are referred to.
+	// +kubebuilder:validation:Required
+	TargetComponentName string `json:"targetComponentName"`
+
+	// type is the type of the constraint.
+	// CoLocate means the pods are scheduled into the topology domains where the pods of the target component run.
+	// Spread means the pods are scheduled into the topology domains where no pods of the target component run.
+	// +kubebuilder:validation:Required
+	Type ComponentAffinityType `json:"type"`
+
+	// topologyKey is the key of node labels, nodes that have a label with this key and identical values are
+	// considered to be in the same topology domain.
+	// +kubebuilder:default="kubernetes.io/hostname"
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// required means the constraint must be satisfied to schedule the pods, otherwise it's satisfied preferably.
+	// +kubebuilder:default=false
+	// +optional
+	Required bool `json:"required,omitempty"`
+}
+
+// ComponentEvictionPolicy defines the hints to rank the pods of a component to evict under node pressure.
+type ComponentEvictionPolicy struct {
+	// priority is the rank of the pods to evict, the pods with Low priority are evicted first, and the ones with
+	// High priority are evicted last. It's translated into the PriorityClass of the same rank installed with KubeBlocks,
+	// and ignored if the priorityClassName of the component is specified.
+	// +optional
+	Priority EvictionPriority `json:"priority,omitempty"`
+}
+
+// SchedulingPolicy defines the scheduling settings of the pods of a component.
+type SchedulingPolicy struct {
+	// topologySpreadConstraints describes how the pods spread across the topology domains. They take precedence
+	// over the constraints derived from the affinity when both refer to the same topologyKey.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// nodeName is the name of the node to run the pods on directly, bypassing the scheduler.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+}
+
 // Issuer defines Tls certs issuer
 type Issuer struct {
 	// Name of issuer.
@@ -587,6 +1192,12 @@ type ClusterComponentService struct {
 	// More info: https://kubernetes.io/docs/concepts/services-networking/service/#loadbalancer.
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// sessionAffinity enables the client IP based session affinity for sticky connections if set to ClientIP.
+	// The affinity lasts for the default timeout of the Service, 3 hours. It defaults to None.
+	// +kubebuilder:validation:Enum={ClientIP,None}
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
 }
 
 type ClassDefRef struct {
@@ -698,6 +1309,56 @@ func (r Cluster) IsStatusUpdating() bool {
 	return !r.IsDeleting() && !r.IsUpdating()
 }
 
+// IsComponentPaused checks whether the component compName is paused by the annotation
+// kubeblocks.io/component-paused, whose value is a comma-separated list of component names.
+// The workloads of a paused component are frozen, while its status is still aggregated.
+func (r Cluster) IsComponentPaused(compName string) bool {
+	value, ok := r.Annotations[constant.PausedComponentsAnnotationKey]
+	if !ok {
+		return false
+	}
+	for _, name := range strings.Split(value, ",") {
+		if strings.TrimSpace(name) == compName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetComponentNodePool returns the node pool the pods of the component compName are pinned to by the annotation
+// apps.kubeblocks.io/node-pool, whose value is either a pool for all components, or a comma-separated list of
+// <component>=<pool> pairs. An empty string is returned if the component isn't pinned to any pool.
+func (r Cluster) GetComponentNodePool(compName string) string {
+	value := strings.TrimSpace(r.Annotations[constant.NodePoolAnnotationKey])
+	if value == "" {
+		return ""
+	}
+	if !strings.Contains(value, "=") {
+		return value
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, pool, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(name) == compName {
+			return strings.TrimSpace(pool)
+		}
+	}
+	return ""
+}
+
+// IsClusterDefinitionUpdatePending checks whether the changes of the ClusterDefinition wait for the cluster to adopt them,
+// which happens if the updatePolicy of the ClusterDefinition is Manual and the cluster is rendered against an older
+// generation which isn't adopted by the annotation apps.kubeblocks.io/adopt-cluster-definition-generation yet.
+func (r Cluster) IsClusterDefinitionUpdatePending(clusterDef *ClusterDefinition) bool {
+	if clusterDef == nil || clusterDef.Spec.UpdatePolicy != ManualClusterDefUpdatePolicy {
+		return false
+	}
+	// the cluster is never rendered, or rendered against the current generation.
+	if r.Status.ClusterDefGeneration == 0 || r.Status.ClusterDefGeneration == clusterDef.Generation {
+		return false
+	}
+	return r.Annotations[constant.AdoptClusterDefGenerationAnnotationKey] != strconv.FormatInt(clusterDef.Generation, 10)
+}
+
 // GetVolumeClaimNames gets all PVC names of component compName.
 //
 // r.Spec.GetComponentByName(compName).VolumeClaimTemplates[*].Name will be used if no claimNames provided
@@ -750,6 +1411,75 @@ func (r *Cluster) GetVolumeClaimNames(compName string, claimNames ...string) []s
 	return pvcNames
 }
 
+// GetComponentObjectNamePrefixes returns the prefixes of the names of the objects of the component sharing the
+// namespace with the other components. Every object of the component is named <prefix> or <prefix>-<suffix>:
+//   - <cluster>-<component> for the workload, the client, headless, alternative and role services, and the config,
+//     script and env ConfigMaps.
+//   - the podNamePrefix if specified, for the pods and the underlying StatefulSet.
+//
+// The PVCs are named <volumeClaimTemplate>-<pod name prefix>-<ordinal>, whose prefixes are returned apart as they
+// only collide with the PVCs. The names are kept as they are for compatibility, so the collisions between them
+// are rejected rather than avoided.
+func GetComponentObjectNamePrefixes(clusterName string, comp ClusterComponentSpec) ([]string, []string) {
+	compPrefix := fmt.Sprintf("%s-%s", clusterName, comp.Name)
+	prefixes := []string{compPrefix}
+	podNamePrefix := compPrefix
+	if len(comp.PodNamePrefix) > 0 && comp.PodNamePrefix != compPrefix {
+		podNamePrefix = comp.PodNamePrefix
+		prefixes = append(prefixes, podNamePrefix)
+	}
+	var pvcPrefixes []string
+	for _, vct := range comp.VolumeClaimTemplates {
+		pvcPrefixes = append(pvcPrefixes, fmt.Sprintf("%s-%s", vct.Name, podNamePrefix))
+	}
+	return prefixes, pvcPrefixes
+}
+
+// objectNamePrefixesCollide checks whether the objects named after the prefixes may collide, i.e. the prefixes are
+// the same, or one is the other followed by a suffix. Whatever suffixes the components derive the object names with,
+// e.g. the names of the config templates or the alternative services, the names never collide otherwise.
+func objectNamePrefixesCollide(prefixes, others []string) bool {
+	for _, p1 := range prefixes {
+		for _, p2 := range others {
+			if p1 == p2 || strings.HasPrefix(p1, p2+"-") || strings.HasPrefix(p2, p1+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetComponentNameCollisions returns the components of the other clusters in the namespace, and the other components
+// of the cluster, whose object names may collide with the ones of the components of the cluster, e.g. the component
+// b-proxy of the cluster mysql-a collides with the component a-b-proxy of the cluster mysql, and the component proxy
+// collides with the component proxy-config of the same cluster, as the ConfigMap of the config template config of
+// the former is named after the workload of the latter. The result is keyed by the component names, and the
+// colliding components are in the form of <cluster>/<component>.
+func (r *Cluster) GetComponentNameCollisions(others []Cluster) map[string][]string {
+	clusters := []Cluster{*r}
+	for _, other := range others {
+		if other.Namespace == r.Namespace && other.Name != r.Name {
+			clusters = append(clusters, other)
+		}
+	}
+	collisions := map[string][]string{}
+	for _, comp := range r.Spec.ComponentSpecs {
+		prefixes, pvcPrefixes := GetComponentObjectNamePrefixes(r.Name, comp)
+		for _, other := range clusters {
+			for _, otherComp := range other.Spec.ComponentSpecs {
+				if other.Name == r.Name && otherComp.Name == comp.Name {
+					continue
+				}
+				otherPrefixes, otherPVCPrefixes := GetComponentObjectNamePrefixes(other.Name, otherComp)
+				if objectNamePrefixesCollide(prefixes, otherPrefixes) || objectNamePrefixesCollide(pvcPrefixes, otherPVCPrefixes) {
+					collisions[comp.Name] = append(collisions[comp.Name], other.Name+"/"+otherComp.Name)
+				}
+			}
+		}
+	}
+	return collisions
+}
+
 // GetComponentByName gets component by name.
 func (r ClusterSpec) GetComponentByName(componentName string) *ClusterComponentSpec {
 	for _, v := range r.ComponentSpecs {
@@ -860,6 +1590,10 @@ func (r *ClusterComponentSpec) ToVolumeClaimTemplates() []corev1.PersistentVolum
 	}
 	var ts []corev1.PersistentVolumeClaimTemplate
 	for _, t := range r.VolumeClaimTemplates {
+		// the ephemeral ones are rendered as the pod volumes.
+		if t.Ephemeral {
+			continue
+		}
 		ts = append(ts, t.toVolumeClaimTemplate())
 	}
 	return ts
@@ -124,6 +124,44 @@ var _ = Describe("", func() {
 		Expect(r.IsStatusUpdating()).Should(Equal(true))
 	})
 
+	It("test IsComponentPaused", func() {
+		r := Cluster{}
+		Expect(r.IsComponentPaused("mysql")).Should(BeFalse())
+		r.Annotations = map[string]string{
+			constant.PausedComponentsAnnotationKey: "mysql, proxy",
+		}
+		Expect(r.IsComponentPaused("mysql")).Should(BeTrue())
+		Expect(r.IsComponentPaused("proxy")).Should(BeTrue())
+		Expect(r.IsComponentPaused("redis")).Should(BeFalse())
+	})
+
+	It("test IsClusterDefinitionUpdatePending", func() {
+		clusterDef := &ClusterDefinition{}
+		clusterDef.Generation = 2
+		r := Cluster{}
+		r.Status.ClusterDefGeneration = 1
+
+		By("the changes propagate automatically")
+		Expect(r.IsClusterDefinitionUpdatePending(clusterDef)).Should(BeFalse())
+		clusterDef.Spec.UpdatePolicy = AutomaticClusterDefUpdatePolicy
+		Expect(r.IsClusterDefinitionUpdatePending(clusterDef)).Should(BeFalse())
+
+		By("the changes wait for the cluster to adopt them")
+		clusterDef.Spec.UpdatePolicy = ManualClusterDefUpdatePolicy
+		Expect(r.IsClusterDefinitionUpdatePending(clusterDef)).Should(BeTrue())
+		r.Annotations = map[string]string{constant.AdoptClusterDefGenerationAnnotationKey: "1"}
+		Expect(r.IsClusterDefinitionUpdatePending(clusterDef)).Should(BeTrue())
+		r.Annotations[constant.AdoptClusterDefGenerationAnnotationKey] = "2"
+		Expect(r.IsClusterDefinitionUpdatePending(clusterDef)).Should(BeFalse())
+
+		By("the cluster is never rendered or rendered against the current generation")
+		delete(r.Annotations, constant.AdoptClusterDefGenerationAnnotationKey)
+		r.Status.ClusterDefGeneration = 0
+		Expect(r.IsClusterDefinitionUpdatePending(clusterDef)).Should(BeFalse())
+		r.Status.ClusterDefGeneration = 2
+		Expect(r.IsClusterDefinitionUpdatePending(clusterDef)).Should(BeFalse())
+	})
+
 	It("test GetVolumeClaimNames", func() {
 		r := Cluster{}
 		clusterName := "test-cluster"
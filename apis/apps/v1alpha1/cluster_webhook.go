@@ -18,16 +18,26 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -39,9 +49,107 @@ var clusterlog = logf.Log.WithName("cluster-resource")
 func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(&clusterDefaulter{}).
 		Complete()
 }
 
+// +kubebuilder:webhook:path=/mutate-apps-kubeblocks-io-v1alpha1-cluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps.kubeblocks.io,resources=clusters,verbs=create;update,versions=v1alpha1,name=mcluster.kb.io,admissionReviewVersions=v1
+
+// clusterDefaulter is a CustomDefaulter rather than a Defaulter, since the last cluster of the
+// admission request is required to keep the persisted values unchanged.
+type clusterDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &clusterDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (d *clusterDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*Cluster)
+	if !ok {
+		return fmt.Errorf("expected a Cluster but got a %T", obj)
+	}
+	clusterlog.Info("default", "name", cluster.Name)
+
+	var lastCluster *Cluster
+	if req, err := admission.RequestFromContext(ctx); err == nil && len(req.OldObject.Raw) > 0 {
+		lastCluster = &Cluster{}
+		if err = json.Unmarshal(req.OldObject.Raw, lastCluster); err != nil {
+			return err
+		}
+	}
+	cluster.normalizeVolumeClaimStorages(lastCluster)
+	return nil
+}
+
+// decimalToBinarySuffixes maps the exponents of the decimal SI suffixes to the binary SI suffixes.
+var decimalToBinarySuffixes = map[int]string{3: "Ki", 6: "Mi", 9: "Gi", 12: "Ti", 15: "Pi", 18: "Ei"}
+
+// normalizeVolumeClaimStorages canonicalizes the storage requests of the volume claim templates into the binary
+// units, e.g. 10G to 10Gi. The storage requests equal to the ones of the last cluster are kept as they are,
+// so the persisted non-canonical values don't cause spurious diffs, e.g. volume expansions.
+func (r *Cluster) normalizeVolumeClaimStorages(lastCluster *Cluster) {
+	for i := range r.Spec.ComponentSpecs {
+		component := &r.Spec.ComponentSpecs[i]
+		for j := range component.VolumeClaimTemplates {
+			vct := &component.VolumeClaimTemplates[j]
+			storage, ok := vct.Spec.Resources.Requests[corev1.ResourceStorage]
+			if !ok {
+				continue
+			}
+			if last := getLastVolumeClaimStorage(lastCluster, component.Name, vct.Name); last != nil && last.Cmp(storage) == 0 {
+				continue
+			}
+			vct.Spec.Resources.Requests[corev1.ResourceStorage] = normalizeStorageQuantity(storage)
+		}
+	}
+}
+
+// normalizeStorageQuantity converts the storage quantity written with a decimal SI suffix into the same number
+// with the binary SI suffix, e.g. 1.5G to 1.5Gi, as the binary units are meant for the storage in most cases.
+// The quantities without suffix, with the binary SI suffixes or in the exponent notation are kept as they are.
+func normalizeStorageQuantity(q resource.Quantity) resource.Quantity {
+	if q.Format != resource.DecimalSI || q.Sign() <= 0 {
+		return q
+	}
+	copied := q.DeepCopy()
+	dec := copied.AsDec()
+	// the exponent of the suffix written, e.g. 9 for both 10G and 1.5G
+	exponent := (-int(dec.Scale()) + 2) / 3 * 3
+	binarySuffix, ok := decimalToBinarySuffixes[exponent]
+	if !ok {
+		return q
+	}
+	// bytes = number * 1024^(exponent/3), where number = unscaled * 10^(-scale-exponent)
+	bytes := new(big.Int).Lsh(dec.UnscaledBig(), uint(exponent/3*10))
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent+int(dec.Scale()))), nil)
+	bytes.Add(bytes, new(big.Int).Sub(divisor, big.NewInt(1)))
+	bytes.Quo(bytes, divisor)
+	if !bytes.IsInt64() {
+		return q
+	}
+	clusterlog.V(1).Info("normalize storage quantity", "from", q.String(), "suffix", binarySuffix)
+	return *resource.NewQuantity(bytes.Int64(), resource.BinarySI)
+}
+
+// getLastVolumeClaimStorage gets the storage request of the volume claim template of the last cluster.
+func getLastVolumeClaimStorage(lastCluster *Cluster, componentName, vctName string) *resource.Quantity {
+	if lastCluster == nil {
+		return nil
+	}
+	lastComponent := getLastComponentByName(lastCluster, componentName)
+	if lastComponent == nil {
+		return nil
+	}
+	for _, vct := range lastComponent.VolumeClaimTemplates {
+		if vct.Name != vctName {
+			continue
+		}
+		if storage, ok := vct.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			return &storage
+		}
+	}
+	return nil
+}
+
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:path=/validate-apps-kubeblocks-io-v1alpha1-cluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.kubeblocks.io,resources=clusters,verbs=create;update,versions=v1alpha1,name=vcluster.kb.io,admissionReviewVersions=v1
 
@@ -50,7 +158,7 @@ var _ webhook.Validator = &Cluster{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateCreate() (admission.Warnings, error) {
 	clusterlog.Info("validate create", "name", r.Name)
-	return nil, r.validate()
+	return r.validate(nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -60,10 +168,14 @@ func (r *Cluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error)
 	if lastCluster.Spec.ClusterDefRef != r.Spec.ClusterDefRef {
 		return nil, newInvalidError(ClusterKind, r.Name, "spec.clusterDefinitionRef", "clusterDefinitionRef is immutable, you can not update it. ")
 	}
-	if err := r.validate(); err != nil {
-		return nil, err
+	warnings, err := r.validate(lastCluster)
+	if err != nil {
+		return warnings, err
+	}
+	if err := r.validatePodNamePrefixes(lastCluster); err != nil {
+		return warnings, err
 	}
-	return nil, r.validateVolumeClaimTemplates(lastCluster)
+	return warnings, r.validateVolumeClaimTemplates(lastCluster)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -75,6 +187,19 @@ func (r *Cluster) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
 
+// validatePodNamePrefixes the pod name prefix of the existing components is immutable, the pods and PVCs are named after it.
+func (r *Cluster) validatePodNamePrefixes(lastCluster *Cluster) error {
+	for i, component := range r.Spec.ComponentSpecs {
+		lastComponent := getLastComponentByName(lastCluster, component.Name)
+		if lastComponent == nil || lastComponent.PodNamePrefix == component.PodNamePrefix {
+			continue
+		}
+		return newInvalidError(ClusterKind, r.Name, fmt.Sprintf("spec.components[%d].podNamePrefix", i),
+			"podNamePrefix is immutable, you can not update it. ")
+	}
+	return nil
+}
+
 // validateVolumeClaimTemplates volumeClaimTemplates is forbidden modification except for storage size.
 func (r *Cluster) validateVolumeClaimTemplates(lastCluster *Cluster) error {
 	var allErrs field.ErrorList
@@ -116,15 +241,17 @@ func setVolumeClaimStorageSizeZero(volumeClaimTemplates []ClusterComponentVolume
 	}
 }
 
-// Validate Cluster.spec is legal
-func (r *Cluster) validate() error {
+// Validate Cluster.spec is legal, the lastCluster is nil on creation.
+// The warnings are returned to the client along with the admission response.
+func (r *Cluster) validate(lastCluster *Cluster) (admission.Warnings, error) {
 	var (
 		allErrs    field.ErrorList
+		warnings   admission.Warnings
 		ctx        = context.Background()
 		clusterDef = &ClusterDefinition{}
 	)
 	if webhookMgr == nil {
-		return nil
+		return nil, nil
 	}
 
 	r.validateClusterVersionRef(&allErrs)
@@ -135,15 +262,16 @@ func (r *Cluster) validate() error {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.clusterDefinitionRef"),
 			r.Spec.ClusterDefRef, err.Error()))
 	} else {
-		r.validateComponents(&allErrs, clusterDef)
+		r.validateComponents(&allErrs, &warnings, clusterDef, lastCluster)
 	}
+	r.validateComponentNameCollisions(ctx, &allErrs, lastCluster)
 
 	if len(allErrs) > 0 {
-		return apierrors.NewInvalid(
+		return warnings, apierrors.NewInvalid(
 			schema.GroupKind{Group: APIVersion, Kind: ClusterKind},
 			r.Name, allErrs)
 	}
-	return nil
+	return warnings, nil
 }
 
 // ValidateClusterVersionRef validate spec.clusterVersionRef is legal
@@ -160,7 +288,7 @@ func (r *Cluster) validateClusterVersionRef(allErrs *field.ErrorList) {
 }
 
 // ValidateComponents validate spec.components is legal
-func (r *Cluster) validateComponents(allErrs *field.ErrorList, clusterDef *ClusterDefinition) {
+func (r *Cluster) validateComponents(allErrs *field.ErrorList, warnings *admission.Warnings, clusterDef *ClusterDefinition, lastCluster *Cluster) {
 	var (
 		// invalid component slice
 		invalidComponentDefs = make([]string, 0)
@@ -181,9 +309,21 @@ func (r *Cluster) validateComponents(allErrs *field.ErrorList, clusterDef *Clust
 
 		componentNameMap[v.Name] = struct{}{}
 		r.validateComponentResources(allErrs, v.Resources, i)
+		r.validateComponentPodNames(allErrs, v, i)
+		r.validateComponentTmpfsVolumes(allErrs, v, i)
+		r.validateComponentMonitor(allErrs, v, i)
+		if compDef, ok := componentMap[v.ComponentDefRef]; ok {
+			r.validateComponentReplicas(allErrs, warnings, v, compDef, i)
+			r.validateComponentVolumeClaimSizes(allErrs, v, compDef, lastCluster, i)
+			r.validateComponentVolumeSubPaths(allErrs, v, compDef, i)
+			r.validateComponentPriorityClass(warnings, v, compDef)
+			r.validateComponentProbeOverrides(allErrs, v, compDef, i)
+			r.validateComponentServiceNames(allErrs, v, compDef, i)
+		}
 	}
 
 	r.validateComponentTLSSettings(allErrs)
+	r.validateComponentAffinities(allErrs, componentNameMap)
 
 	if len(invalidComponentDefs) > 0 {
 		*allErrs = append(*allErrs, field.NotFound(field.NewPath("spec.components[*].type"),
@@ -204,6 +344,281 @@ func (r *Cluster) validateComponentResources(allErrs *field.ErrorList, resources
 	}
 }
 
+// validateComponentPodNames validates the pod names derived from the pod name prefix and the ordinal start are valid DNS labels,
+// the pod name is also used as the hostname of the instance.
+func (r *Cluster) validateComponentPodNames(allErrs *field.ErrorList, component ClusterComponentSpec, index int) {
+	if component.Replicas == 0 {
+		return
+	}
+	podNamePrefix := fmt.Sprintf("%s-%s", r.Name, component.Name)
+	path := fmt.Sprintf("spec.components[%d].ordinalStart", index)
+	var value interface{} = component.OrdinalStart
+	if len(component.PodNamePrefix) > 0 {
+		podNamePrefix = component.PodNamePrefix
+		path = fmt.Sprintf("spec.components[%d].podNamePrefix", index)
+		value = component.PodNamePrefix
+	}
+	lastPodName := fmt.Sprintf("%s-%d", podNamePrefix, component.OrdinalStart+component.Replicas-1)
+	if errs := validation.IsDNS1123Label(lastPodName); len(errs) > 0 {
+		*allErrs = append(*allErrs, field.Invalid(field.NewPath(path),
+			value, fmt.Sprintf("the derived pod name %s is invalid: %s", lastPodName, strings.Join(errs, "; "))))
+	}
+}
+
+// validateComponentServiceNames rejects the services named after the roles of the component if the role services
+// are enabled, as the role services are named <cluster>-<component>-<role> as well.
+func (r *Cluster) validateComponentServiceNames(allErrs *field.ErrorList, component ClusterComponentSpec,
+	compDef ClusterComponentDefinition, index int) {
+	if !component.RoleServices {
+		return
+	}
+	roles := compDef.GetRoles()
+	for i, svc := range component.Services {
+		if slices.Contains(roles, svc.Name) {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(fmt.Sprintf("spec.components[%d].services[%d].name", index, i)),
+				svc.Name, "the service name is reserved for the role service since roleServices is enabled"))
+		}
+	}
+}
+
+// validateComponentTmpfsVolumes validates the size limits of the tmpfs volumes are positive.
+func (r *Cluster) validateComponentTmpfsVolumes(allErrs *field.ErrorList, component ClusterComponentSpec, index int) {
+	for i, volume := range component.TmpfsVolumes {
+		if volume.SizeLimit.Sign() <= 0 {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(fmt.Sprintf("spec.components[%d].tmpfsVolumes[%d].sizeLimit", index, i)),
+				volume.SizeLimit.String(), "the size limit of tmpfs volume should be positive"))
+		}
+	}
+}
+
+// validateComponentNameCollisions rejects the components whose object names collide with the ones of the other
+// components in the namespace. Only the newly added components are validated on update, so the clusters
+// provisioned before are not blocked from updating.
+func (r *Cluster) validateComponentNameCollisions(ctx context.Context, allErrs *field.ErrorList, lastCluster *Cluster) {
+	clusterList := &ClusterList{}
+	if err := webhookMgr.client.List(ctx, clusterList, client.InNamespace(r.Namespace)); err != nil {
+		*allErrs = append(*allErrs, field.InternalError(field.NewPath("spec.components"), err))
+		return
+	}
+	r.checkComponentNameCollisions(allErrs, clusterList.Items, lastCluster)
+}
+
+func (r *Cluster) checkComponentNameCollisions(allErrs *field.ErrorList, others []Cluster, lastCluster *Cluster) {
+	collisions := r.GetComponentNameCollisions(others)
+	for i, comp := range r.Spec.ComponentSpecs {
+		if lastCluster != nil && lastCluster.Spec.GetComponentByName(comp.Name) != nil {
+			continue
+		}
+		if colliding, ok := collisions[comp.Name]; ok {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(fmt.Sprintf("spec.components[%d].name", i)), comp.Name,
+				fmt.Sprintf("the names of the component objects collide with the ones of the components %s", strings.Join(colliding, ", "))))
+		}
+	}
+}
+
+// validateComponentAffinities validates the components referred by the component affinities exist,
+// and a component is not constrained relative to itself.
+func (r *Cluster) validateComponentAffinities(allErrs *field.ErrorList, componentNameMap map[string]struct{}) {
+	for i, affinity := range r.Spec.ComponentAffinities {
+		path := fmt.Sprintf("spec.componentAffinities[%d]", i)
+		if _, ok := componentNameMap[affinity.ComponentName]; !ok {
+			*allErrs = append(*allErrs, field.NotFound(field.NewPath(path+".componentName"), affinity.ComponentName))
+		}
+		if _, ok := componentNameMap[affinity.TargetComponentName]; !ok {
+			*allErrs = append(*allErrs, field.NotFound(field.NewPath(path+".targetComponentName"), affinity.TargetComponentName))
+		}
+		if affinity.ComponentName == affinity.TargetComponentName {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(path+".targetComponentName"),
+				affinity.TargetComponentName, "the target component should be another component, use the affinity of the component instead"))
+		}
+	}
+}
+
+// validateComponentReplicas validates the component replicas against the bound derived from the consensusSpec.
+// If the bound is unrestricted, a warning is returned when the voting members can't form an odd-sized quorum.
+func (r *Cluster) validateComponentReplicas(allErrs *field.ErrorList, warnings *admission.Warnings, component ClusterComponentSpec,
+	compDef ClusterComponentDefinition, index int) {
+	if compDef.WorkloadType != Consensus || compDef.ConsensusSpec == nil {
+		return
+	}
+	maxReplicas, bounded := compDef.ConsensusSpec.GetMaxReplicas()
+	if bounded {
+		if component.Replicas > maxReplicas {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(fmt.Sprintf("spec.components[%d].replicas", index)),
+				component.Replicas, fmt.Sprintf("replicas should be no more than %d (leader + followers + learner) defined by the consensusSpec of componentDef %s", maxReplicas, compDef.Name)))
+		}
+		return
+	}
+	if voters := compDef.ConsensusSpec.GetEvenQuorumVoters(component.Replicas); voters > 0 {
+		*warnings = append(*warnings, fmt.Sprintf("component %s has %d voting members (replicas - learners), "+
+			"an odd number is recommended to tolerate the same failures with fewer members", component.Name, voters))
+	}
+}
+
+// validateComponentVolumeClaimSizes validates the storage sizes of the volume claims are no less than the minimum sizes
+// declared by the volumeTypes of the componentDef. The sizes unchanged from the last cluster are skipped, so the existing
+// clusters are not blocked by a raised minimum size.
+func (r *Cluster) validateComponentVolumeClaimSizes(allErrs *field.ErrorList, component ClusterComponentSpec,
+	compDef ClusterComponentDefinition, lastCluster *Cluster, index int) {
+	minSizes := make(map[string]resource.Quantity)
+	for _, volumeType := range compDef.VolumeTypes {
+		if volumeType.MinSize != nil {
+			minSizes[volumeType.Name] = *volumeType.MinSize
+		}
+	}
+	for i, vct := range component.VolumeClaimTemplates {
+		minSize, ok := minSizes[vct.Name]
+		if !ok {
+			continue
+		}
+		storage, ok := vct.Spec.Resources.Requests[corev1.ResourceStorage]
+		if !ok || storage.Cmp(minSize) >= 0 {
+			continue
+		}
+		if last := getLastVolumeClaimStorage(lastCluster, component.Name, vct.Name); last != nil && last.Cmp(storage) == 0 {
+			continue
+		}
+		*allErrs = append(*allErrs, field.Invalid(field.NewPath(fmt.Sprintf("spec.components[%d].volumeClaimTemplates[%d].spec.resources.requests.storage", index, i)),
+			storage.String(), fmt.Sprintf("the storage size of volume %s of component %s should be no less than the minimum size %s", vct.Name, component.Name, minSize.String())))
+	}
+}
+
+// validateComponentVolumeSubPaths validates the volumes of the subPaths declared by the componentDef are claimed
+// by the volumeClaimTemplates of the component, or declared by the podSpec of the componentDef, as the pods mounting
+// the unknown volumes are rejected at creation.
+func (r *Cluster) validateComponentVolumeSubPaths(allErrs *field.ErrorList, component ClusterComponentSpec,
+	compDef ClusterComponentDefinition, index int) {
+	if len(compDef.VolumeSubPaths) == 0 {
+		return
+	}
+	volumes := make(map[string]struct{})
+	for _, vct := range component.VolumeClaimTemplates {
+		volumes[vct.Name] = struct{}{}
+	}
+	if compDef.PodSpec != nil {
+		for _, volume := range compDef.PodSpec.Volumes {
+			volumes[volume.Name] = struct{}{}
+		}
+	}
+	for _, subPath := range compDef.VolumeSubPaths {
+		if _, ok := volumes[subPath.VolumeName]; ok {
+			continue
+		}
+		*allErrs = append(*allErrs, field.Invalid(field.NewPath(fmt.Sprintf("spec.components[%d].volumeClaimTemplates", index)),
+			subPath.VolumeName, fmt.Sprintf("the volume %s to mount the subPath %s at %s is not claimed by the volumeClaimTemplates of component %s",
+				subPath.VolumeName, subPath.SubPath, subPath.MountPath, component.Name)))
+	}
+}
+
+// maxConsensusProbeFailureWindowSeconds is the longest failure window allowed for the liveness and readiness
+// probes of consensus components, beyond which the probes are disabled in effect.
+const maxConsensusProbeFailureWindowSeconds = 300
+
+// monitorScrapeIntervalPattern is the duration format of the scrape interval accepted by prometheus-operator.
+var monitorScrapeIntervalPattern = regexp.MustCompile(`^(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$`)
+
+// validateComponentMonitor validates the scrape interval and the relabeling rules of the monitor resource,
+// the rules rejected by Prometheus would fail the whole scrape config rather than the component only.
+func (r *Cluster) validateComponentMonitor(allErrs *field.ErrorList, component ClusterComponentSpec, index int) {
+	path := fmt.Sprintf("spec.components[%d]", index)
+	if interval := component.MonitorScrapeInterval; len(interval) > 0 {
+		d, err := time.ParseDuration(interval)
+		if !monitorScrapeIntervalPattern.MatchString(interval) || err != nil || d <= 0 {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(path+".monitorScrapeInterval"),
+				interval, "the scrape interval should be a positive duration, e.g. 30s, 1m"))
+		}
+	}
+	for i, relabel := range component.MonitorRelabelings {
+		relabelPath := fmt.Sprintf("%s.monitorRelabelings[%d]", path, i)
+		if _, err := regexp.Compile(relabel.Regex); err != nil {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(relabelPath+".regex"), relabel.Regex, err.Error()))
+		}
+		action := relabel.Action
+		if len(action) == 0 {
+			action = "replace"
+		}
+		if (action == "replace" || action == "hashmod") && len(relabel.TargetLabel) == 0 {
+			*allErrs = append(*allErrs, field.Required(field.NewPath(relabelPath+".targetLabel"),
+				fmt.Sprintf("targetLabel is required by the %s action", action)))
+		}
+		if action == "hashmod" && relabel.Modulus == 0 {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath(relabelPath+".modulus"),
+				relabel.Modulus, "modulus should be positive for the hashmod action"))
+		}
+	}
+}
+
+// validateComponentProbeOverrides validates the containers of the probe overrides are defined in the componentDef.
+// As the failover of consensus components relies on the probes to detect the failures, the failure windows,
+// i.e. periodSeconds * failureThreshold, of the overridden liveness and readiness probes are bounded for them.
+func (r *Cluster) validateComponentProbeOverrides(allErrs *field.ErrorList, component ClusterComponentSpec,
+	compDef ClusterComponentDefinition, index int) {
+	for i, override := range component.ProbeOverrides {
+		path := fmt.Sprintf("spec.components[%d].probeOverrides[%d]", index, i)
+		var container *corev1.Container
+		if compDef.PodSpec != nil {
+			for j := range compDef.PodSpec.Containers {
+				if compDef.PodSpec.Containers[j].Name == override.ContainerName {
+					container = &compDef.PodSpec.Containers[j]
+					break
+				}
+			}
+		}
+		if container == nil {
+			*allErrs = append(*allErrs, field.NotFound(field.NewPath(path+".containerName"), override.ContainerName))
+			continue
+		}
+		if compDef.WorkloadType != Consensus {
+			continue
+		}
+		checkFailureWindow := func(name string, probe *corev1.Probe, timing *ProbeTimingOverride) {
+			if probe == nil || timing == nil {
+				return
+			}
+			merged := probe.DeepCopy()
+			timing.ApplyTo(merged)
+			if window := getProbeFailureWindowSeconds(merged); window > maxConsensusProbeFailureWindowSeconds {
+				*allErrs = append(*allErrs, field.Invalid(field.NewPath(path+"."+name), window,
+					fmt.Sprintf("the failure window (periodSeconds * failureThreshold) of the %s of consensus component %s should be no more than %ds",
+						name, component.Name, maxConsensusProbeFailureWindowSeconds)))
+			}
+		}
+		checkFailureWindow("livenessProbe", container.LivenessProbe, override.LivenessProbe)
+		checkFailureWindow("readinessProbe", container.ReadinessProbe, override.ReadinessProbe)
+	}
+}
+
+// getProbeFailureWindowSeconds returns the seconds a probe takes to be considered failed, the defaults of
+// the kubelet are used if not set.
+func getProbeFailureWindowSeconds(probe *corev1.Probe) int32 {
+	periodSeconds, failureThreshold := probe.PeriodSeconds, probe.FailureThreshold
+	if periodSeconds == 0 {
+		periodSeconds = 10
+	}
+	if failureThreshold == 0 {
+		failureThreshold = 3
+	}
+	return periodSeconds * failureThreshold
+}
+
+// validateComponentPriorityClass checks the PriorityClass of the component exists. It warns rather than rejects
+// if the PriorityClass is missing, since it may be created after the cluster, the pods just can't be created until then.
+func (r *Cluster) validateComponentPriorityClass(warnings *admission.Warnings, component ClusterComponentSpec, compDef ClusterComponentDefinition) {
+	priorityClassName := component.PriorityClassName
+	if len(priorityClassName) == 0 && compDef.PodSpec != nil {
+		priorityClassName = compDef.PodSpec.PriorityClassName
+	}
+	if len(priorityClassName) == 0 || webhookMgr == nil || webhookMgr.client == nil {
+		return
+	}
+	err := webhookMgr.client.Get(context.Background(), types.NamespacedName{Name: priorityClassName}, &schedulingv1.PriorityClass{})
+	if !apierrors.IsNotFound(err) {
+		return
+	}
+	*warnings = append(*warnings, fmt.Sprintf("PriorityClass %s of component %s is not found, "+
+		"the pods of the component can't be created until it's created", priorityClassName, component.Name))
+}
+
 func (r *Cluster) validateComponentTLSSettings(allErrs *field.ErrorList) {
 	for index, component := range r.Spec.ComponentSpecs {
 		if !component.TLS {
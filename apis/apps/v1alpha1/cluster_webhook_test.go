@@ -19,15 +19,21 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var _ = Describe("cluster webhook", func() {
@@ -154,6 +160,608 @@ var _ = Describe("cluster webhook", func() {
 		})
 	})
 
+	Context("pod name validation", func() {
+		It("should reject the ordinal start which makes pod names too long", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			comp := cluster.Spec.ComponentSpecs[0]
+			comp.Replicas = 3
+
+			By("pod names with ordinal start are valid")
+			comp.OrdinalStart = 10
+			var allErrs field.ErrorList
+			cluster.validateComponentPodNames(&allErrs, comp, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("pod names exceed the DNS label length limit")
+			cluster.Name = strings.Repeat("a", 64-len(comp.Name)-len("--12"))
+			cluster.validateComponentPodNames(&allErrs, comp, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].ordinalStart"))
+
+			By("pod names at the DNS label length limit are valid")
+			allErrs = nil
+			comp.OrdinalStart = 7
+			cluster.validateComponentPodNames(&allErrs, comp, 0)
+			Expect(allErrs).Should(BeEmpty())
+		})
+
+		It("should validate the pod names derived from the pod name prefix", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			comp := cluster.Spec.ComponentSpecs[0]
+			comp.Replicas = 3
+
+			By("the pod name prefix replaces the cluster and component names")
+			cluster.Name = strings.Repeat("a", 60)
+			comp.PodNamePrefix = "mysql"
+			var allErrs field.ErrorList
+			cluster.validateComponentPodNames(&allErrs, comp, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("pod names with the pod name prefix exceed the DNS label length limit")
+			comp.PodNamePrefix = strings.Repeat("a", 62)
+			cluster.validateComponentPodNames(&allErrs, comp, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].podNamePrefix"))
+		})
+
+		It("should reject updating the pod name prefix", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			cluster.Spec.ComponentSpecs[0].PodNamePrefix = "mysql"
+			lastCluster := cluster.DeepCopy()
+			Expect(cluster.validatePodNamePrefixes(lastCluster)).Should(Succeed())
+
+			cluster.Spec.ComponentSpecs[0].PodNamePrefix = "mysql-new"
+			err := cluster.validatePodNamePrefixes(lastCluster)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("podNamePrefix is immutable"))
+		})
+	})
+
+	Context("tmpfs volumes validation", func() {
+		It("should reject the non-positive size limits", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			comp := cluster.Spec.ComponentSpecs[0]
+			comp.TmpfsVolumes = []ClusterComponentTmpfsVolume{
+				{Name: "cache", SizeLimit: resource.MustParse("1Gi"), MountPath: "/cache"},
+			}
+
+			By("positive size limit is valid")
+			var allErrs field.ErrorList
+			cluster.validateComponentTmpfsVolumes(&allErrs, comp, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("zero size limit is invalid")
+			comp.TmpfsVolumes = append(comp.TmpfsVolumes, ClusterComponentTmpfsVolume{
+				Name: "scratch", SizeLimit: resource.MustParse("0"), MountPath: "/scratch",
+			})
+			cluster.validateComponentTmpfsVolumes(&allErrs, comp, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].tmpfsVolumes[1].sizeLimit"))
+		})
+	})
+
+	Context("service names validation", func() {
+		It("should reject the services named after the roles if the role services are enabled", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			compDef := ClusterComponentDefinition{WorkloadType: Replication}
+			comp := cluster.Spec.ComponentSpecs[0]
+			comp.Services = []ClusterComponentService{{Name: "vpc"}, {Name: "primary"}}
+
+			By("the role names are free if the role services are disabled")
+			var allErrs field.ErrorList
+			cluster.validateComponentServiceNames(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("the role names are reserved if the role services are enabled")
+			comp.RoleServices = true
+			cluster.validateComponentServiceNames(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].services[1].name"))
+		})
+	})
+
+	Context("monitor validation", func() {
+		It("should reject the invalid scrape intervals and relabeling rules", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			comp := cluster.Spec.ComponentSpecs[0]
+
+			By("the valid scrape intervals")
+			for _, interval := range []string{"30s", "1m", "1m30s", "500ms"} {
+				var allErrs field.ErrorList
+				comp.MonitorScrapeInterval = interval
+				cluster.validateComponentMonitor(&allErrs, comp, 0)
+				Expect(allErrs).Should(BeEmpty(), interval)
+			}
+
+			By("the invalid scrape intervals")
+			for _, interval := range []string{"0s", "30", "1d", "-1m", "1.5m"} {
+				var allErrs field.ErrorList
+				comp.MonitorScrapeInterval = interval
+				cluster.validateComponentMonitor(&allErrs, comp, 0)
+				Expect(allErrs).Should(HaveLen(1), interval)
+				Expect(allErrs[0].Field).Should(Equal("spec.components[0].monitorScrapeInterval"))
+			}
+
+			By("the relabeling rules")
+			comp.MonitorScrapeInterval = ""
+			comp.MonitorRelabelings = []MonitorRelabelConfig{
+				{SourceLabels: []string{"__meta_kubernetes_pod_name"}, TargetLabel: "instance"},
+				{SourceLabels: []string{"__meta_kubernetes_pod_label_role"}, Regex: "leader", Action: "keep"},
+				{SourceLabels: []string{"__address__"}, Action: "hashmod"},
+				{SourceLabels: []string{"__address__"}, Regex: "(", Action: "drop"},
+			}
+			var allErrs field.ErrorList
+			cluster.validateComponentMonitor(&allErrs, comp, 0)
+			Expect(allErrs).Should(HaveLen(3))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].monitorRelabelings[2].targetLabel"))
+			Expect(allErrs[1].Field).Should(Equal("spec.components[0].monitorRelabelings[2].modulus"))
+			Expect(allErrs[2].Field).Should(Equal("spec.components[0].monitorRelabelings[3].regex"))
+		})
+	})
+
+	Context("volume subPaths validation", func() {
+		It("should reject the subPaths of the volumes not claimed by the component", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			comp := cluster.Spec.ComponentSpecs[0]
+			compDef := ClusterComponentDefinition{
+				Name: comp.ComponentDefRef,
+				VolumeSubPaths: []VolumeSubPathMount{
+					{VolumeName: comp.VolumeClaimTemplates[0].Name, SubPath: "data", MountPath: "/data"},
+				},
+			}
+
+			By("the volume is claimed by the volumeClaimTemplate")
+			var allErrs field.ErrorList
+			cluster.validateComponentVolumeSubPaths(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("the volume is declared by the podSpec")
+			compDef.PodSpec = &corev1.PodSpec{Volumes: []corev1.Volume{{Name: "scripts"}}}
+			compDef.VolumeSubPaths = append(compDef.VolumeSubPaths, VolumeSubPathMount{VolumeName: "scripts", SubPath: "init", MountPath: "/init"})
+			cluster.validateComponentVolumeSubPaths(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("the volume is unknown")
+			compDef.VolumeSubPaths = append(compDef.VolumeSubPaths, VolumeSubPathMount{VolumeName: "log", SubPath: "log", MountPath: "/log"})
+			cluster.validateComponentVolumeSubPaths(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].volumeClaimTemplates"))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("volume log"))
+		})
+	})
+
+	Context("component name collisions validation", func() {
+		newCluster := func(name string, compNames ...string) Cluster {
+			cluster := Cluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+			for _, compName := range compNames {
+				cluster.Spec.ComponentSpecs = append(cluster.Spec.ComponentSpecs, ClusterComponentSpec{Name: compName})
+			}
+			return cluster
+		}
+
+		It("should find the known collision patterns", func() {
+			for _, c := range []struct {
+				cluster  Cluster
+				others   []Cluster
+				expected map[string][]string
+			}{
+				{
+					// the cluster name contains the prefix of the component name of the other cluster.
+					cluster:  newCluster("mysql-a", "b-proxy"),
+					others:   []Cluster{newCluster("mysql", "a-b-proxy")},
+					expected: map[string][]string{"b-proxy": {"mysql/a-b-proxy"}},
+				},
+				{
+					// the component name of the other cluster contains the suffix of the cluster name.
+					cluster:  newCluster("mysql", "a-b-proxy"),
+					others:   []Cluster{newCluster("mysql-a", "b-proxy"), newCluster("mysql-a-b", "proxy")},
+					expected: map[string][]string{"a-b-proxy": {"mysql-a/b-proxy", "mysql-a-b/proxy"}},
+				},
+				{
+					// the client service collides with the headless service of the other component.
+					cluster:  newCluster("mysql", "proxy-headless"),
+					others:   []Cluster{newCluster("mysql-proxy", "headless")},
+					expected: map[string][]string{"proxy-headless": {"mysql-proxy/headless"}},
+				},
+				{
+					// the components of the same cluster collide.
+					cluster:  newCluster("mysql", "proxy", "proxy-headless"),
+					expected: map[string][]string{"proxy": {"mysql/proxy-headless"}, "proxy-headless": {"mysql/proxy"}},
+				},
+				{
+					// the ConfigMap of the config template config collides with the workload of the other component.
+					cluster:  newCluster("mysql", "proxy"),
+					others:   []Cluster{newCluster("mysql-proxy", "config")},
+					expected: map[string][]string{"proxy": {"mysql-proxy/config"}},
+				},
+				{
+					// the pods named by the podNamePrefix collide with the pods of the other component.
+					cluster: func() Cluster {
+						cluster := newCluster("mysql-new", "proxy")
+						cluster.Spec.ComponentSpecs[0].PodNamePrefix = "mysql-proxy"
+						return cluster
+					}(),
+					others:   []Cluster{newCluster("mysql", "proxy")},
+					expected: map[string][]string{"proxy": {"mysql/proxy"}},
+				},
+				{
+					// the PVCs may collide, while the other objects don't.
+					cluster: func() Cluster {
+						cluster := newCluster("mysql", "proxy")
+						cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates = []ClusterComponentVolumeClaimTemplate{{Name: "data"}}
+						return cluster
+					}(),
+					others: []Cluster{func() Cluster {
+						cluster := newCluster("proxy", "log")
+						cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates = []ClusterComponentVolumeClaimTemplate{{Name: "data-mysql"}}
+						return cluster
+					}(), func() Cluster {
+						cluster := newCluster("redis", "mysql-proxy")
+						cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates = []ClusterComponentVolumeClaimTemplate{{Name: "data"}}
+						return cluster
+					}()},
+					expected: map[string][]string{"proxy": {"proxy/log"}},
+				},
+				{
+					// the clusters in the other namespaces, and the cluster itself, never collide.
+					cluster: newCluster("mysql-a", "b-proxy"),
+					others: []Cluster{newCluster("mysql-a", "b-proxy"), func() Cluster {
+						cluster := newCluster("mysql", "a-b-proxy")
+						cluster.Namespace = "other"
+						return cluster
+					}()},
+					expected: map[string][]string{},
+				},
+				{
+					cluster:  newCluster("mysql", "mysql", "proxy"),
+					others:   []Cluster{newCluster("mysql-b", "proxy"), newCluster("redis", "mysql-proxy")},
+					expected: map[string][]string{},
+				},
+			} {
+				Expect(c.cluster.GetComponentNameCollisions(c.others)).Should(Equal(c.expected))
+			}
+		})
+
+		It("should validate the newly added components only on update", func() {
+			others := []Cluster{newCluster("mysql", "a-b-proxy")}
+			cluster := newCluster("mysql-a", "b-proxy")
+
+			By("reject the colliding component on create")
+			var allErrs field.ErrorList
+			cluster.checkComponentNameCollisions(&allErrs, others, nil)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].name"))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("mysql/a-b-proxy"))
+
+			By("keep the existing component on update")
+			lastCluster := cluster.DeepCopy()
+			cluster.Spec.ComponentSpecs = append(cluster.Spec.ComponentSpecs, ClusterComponentSpec{Name: "c-proxy"})
+			allErrs = nil
+			cluster.checkComponentNameCollisions(&allErrs, others, lastCluster)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("reject the newly added colliding component on update")
+			others = append(others, newCluster("mysql-a-c", "proxy"))
+			cluster.checkComponentNameCollisions(&allErrs, others, lastCluster)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[1].name"))
+		})
+	})
+
+	Context("component affinities validation", func() {
+		It("should reject the affinities referring to the unknown components or itself", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			componentNameMap := map[string]struct{}{"mysql": {}, "proxy": {}}
+			cluster.Spec.ComponentAffinities = []ComponentAffinity{
+				{ComponentName: "proxy", TargetComponentName: "mysql", Type: CoLocateComponentAffinity},
+			}
+
+			By("affinity between the existing components is valid")
+			var allErrs field.ErrorList
+			cluster.validateComponentAffinities(&allErrs, componentNameMap)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("affinity referring to the unknown component or itself is invalid")
+			cluster.Spec.ComponentAffinities = append(cluster.Spec.ComponentAffinities,
+				ComponentAffinity{ComponentName: "proxy", TargetComponentName: "cache", Type: SpreadComponentAffinity},
+				ComponentAffinity{ComponentName: "mysql", TargetComponentName: "mysql", Type: SpreadComponentAffinity})
+			cluster.validateComponentAffinities(&allErrs, componentNameMap)
+			Expect(allErrs).Should(HaveLen(2))
+			Expect(allErrs[0].Field).Should(Equal("spec.componentAffinities[1].targetComponentName"))
+			Expect(allErrs[1].Field).Should(Equal("spec.componentAffinities[2].targetComponentName"))
+		})
+	})
+
+	Context("consensus replicas validation", func() {
+		var (
+			cluster *Cluster
+			compDef ClusterComponentDefinition
+		)
+
+		int32Ptr := func(i int32) *int32 { return &i }
+
+		BeforeEach(func() {
+			cluster, _ = createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			compDef = ClusterComponentDefinition{
+				Name:          cluster.Spec.ComponentSpecs[0].ComponentDefRef,
+				WorkloadType:  Consensus,
+				ConsensusSpec: NewConsensusSetSpec(),
+			}
+		})
+
+		It("should reject replicas beyond the bound of followers", func() {
+			compDef.ConsensusSpec.Followers = []ConsensusMember{
+				{Name: "follower", AccessMode: Readonly, Replicas: int32Ptr(2)},
+			}
+			comp := cluster.Spec.ComponentSpecs[0]
+
+			By("replicas within the bound")
+			comp.Replicas = 3
+			var (
+				allErrs  field.ErrorList
+				warnings admission.Warnings
+			)
+			cluster.validateComponentReplicas(&allErrs, &warnings, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("replicas beyond the bound")
+			comp.Replicas = 7
+			cluster.validateComponentReplicas(&allErrs, &warnings, comp, compDef, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].replicas"))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("no more than 3"))
+		})
+
+		It("should take learners into account", func() {
+			compDef.ConsensusSpec.Followers = []ConsensusMember{
+				{Name: "follower", AccessMode: Readonly, Replicas: int32Ptr(2)},
+			}
+			compDef.ConsensusSpec.Learner = &ConsensusMember{Name: "learner", AccessMode: Readonly, Replicas: int32Ptr(1)}
+			comp := cluster.Spec.ComponentSpecs[0]
+
+			comp.Replicas = 4
+			var (
+				allErrs  field.ErrorList
+				warnings admission.Warnings
+			)
+			cluster.validateComponentReplicas(&allErrs, &warnings, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			comp.Replicas = 5
+			cluster.validateComponentReplicas(&allErrs, &warnings, comp, compDef, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("no more than 4"))
+		})
+
+		It("should return quorum warnings if followers are in any count", func() {
+			compDef.ConsensusSpec.Followers = []ConsensusMember{
+				{Name: "follower", AccessMode: Readonly},
+			}
+			compDef.ConsensusSpec.Learner = &ConsensusMember{Name: "learner", AccessMode: Readonly, Replicas: int32Ptr(1)}
+			comp := cluster.Spec.ComponentSpecs[0]
+
+			By("odd voting members")
+			comp.Replicas = 4
+			var (
+				allErrs  field.ErrorList
+				warnings admission.Warnings
+			)
+			cluster.validateComponentReplicas(&allErrs, &warnings, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+			Expect(warnings).Should(BeEmpty())
+
+			By("even voting members")
+			comp.Replicas = 7
+			cluster.validateComponentReplicas(&allErrs, &warnings, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+			Expect(warnings).Should(HaveLen(1))
+			Expect(warnings[0]).Should(ContainSubstring("6 voting members"))
+		})
+	})
+
+	Context("probe overrides validation", func() {
+		int32Ptr := func(i int32) *int32 { return &i }
+
+		It("should bound the failure windows of the probes of consensus components", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			compDef := ClusterComponentDefinition{
+				Name:         cluster.Spec.ComponentSpecs[0].ComponentDefRef,
+				WorkloadType: Consensus,
+				PodSpec: &corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "mysql",
+						LivenessProbe: &corev1.Probe{
+							ProbeHandler:     corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(3306)}},
+							PeriodSeconds:    10,
+							FailureThreshold: 3,
+						},
+					}},
+				},
+			}
+			comp := cluster.Spec.ComponentSpecs[0]
+
+			By("relaxing the timeouts within the bound")
+			comp.ProbeOverrides = []ContainerProbeOverride{{
+				ContainerName: "mysql",
+				LivenessProbe: &ProbeTimingOverride{TimeoutSeconds: int32Ptr(10), FailureThreshold: int32Ptr(30)},
+			}}
+			var allErrs field.ErrorList
+			cluster.validateComponentProbeOverrides(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("stretching the failure window beyond the bound")
+			comp.ProbeOverrides[0].LivenessProbe.PeriodSeconds = int32Ptr(3600)
+			cluster.validateComponentProbeOverrides(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].probeOverrides[0].livenessProbe"))
+
+			By("the bound doesn't apply to the other workloads")
+			allErrs = nil
+			compDef.WorkloadType = Stateful
+			cluster.validateComponentProbeOverrides(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("overriding the probes of an unknown container")
+			comp.ProbeOverrides[0].ContainerName = "unknown"
+			cluster.validateComponentProbeOverrides(&allErrs, comp, compDef, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].probeOverrides[0].containerName"))
+		})
+	})
+
+	Context("priority class validation", func() {
+		var (
+			cluster *Cluster
+			compDef ClusterComponentDefinition
+		)
+
+		BeforeEach(func() {
+			cluster, _ = createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			compDef = ClusterComponentDefinition{Name: cluster.Spec.ComponentSpecs[0].ComponentDefRef}
+		})
+
+		It("should warn rather than reject if the priority class is missing", func() {
+			comp := cluster.Spec.ComponentSpecs[0]
+
+			By("no priority class")
+			var warnings admission.Warnings
+			cluster.validateComponentPriorityClass(&warnings, comp, compDef)
+			Expect(warnings).Should(BeEmpty())
+
+			By("the priority class of the componentDef is missing")
+			compDef.PodSpec = &corev1.PodSpec{PriorityClassName: "missing-default-" + randomStr}
+			cluster.validateComponentPriorityClass(&warnings, comp, compDef)
+			Expect(warnings).Should(HaveLen(1))
+			Expect(warnings[0]).Should(And(ContainSubstring(compDef.PodSpec.PriorityClassName), ContainSubstring(comp.Name)))
+
+			By("the priority class of the component overrides the componentDef")
+			priorityClass := &schedulingv1.PriorityClass{Value: 1000000}
+			priorityClass.Name = "high-priority-" + randomStr
+			Expect(k8sClient.Create(ctx, priorityClass)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, priorityClass))).Should(Succeed())
+			})
+			comp.PriorityClassName = priorityClass.Name
+			Eventually(func() admission.Warnings {
+				warnings = nil
+				cluster.validateComponentPriorityClass(&warnings, comp, compDef)
+				return warnings
+			}).Should(BeEmpty())
+		})
+
+		It("should return the warnings along with the admission response", func() {
+			clusterDef, _ := createTestClusterDefinitionObj(clusterDefinitionName)
+			Expect(testCtx.CreateObj(ctx, clusterDef)).Should(Succeed())
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKey{Name: clusterDefinitionName}, &ClusterDefinition{})
+			}).Should(Succeed())
+
+			cluster.Spec.ComponentSpecs[0].PriorityClassName = "missing-" + randomStr
+			warnings, _ := cluster.ValidateCreate()
+			Expect(warnings).Should(ContainElement(ContainSubstring(cluster.Spec.ComponentSpecs[0].PriorityClassName)))
+		})
+	})
+
+	Context("volume claim storages normalization", func() {
+		It("should normalize the decimal SI suffixes into the binary SI suffixes", func() {
+			for from, to := range map[string]string{
+				"10G":  "10Gi",
+				"1.5G": "1536Mi",
+				"500M": "500Mi",
+				"10Gi": "10Gi",
+				"2e9":  "2e9",
+			} {
+				normalized := normalizeStorageQuantity(resource.MustParse(from))
+				Expect(normalized.String()).Should(Equal(to), from)
+				By("normalizing again keeps it unchanged")
+				again := normalizeStorageQuantity(normalized)
+				Expect(again.String()).Should(Equal(to), from)
+			}
+
+			By("plain bytes are kept as they are")
+			plain := normalizeStorageQuantity(resource.MustParse("1000000"))
+			Expect(plain.Format).Should(Equal(resource.DecimalSI))
+			Expect(plain.Value()).Should(BeEquivalentTo(1000000))
+		})
+
+		It("should keep the storages unchanged from the last cluster", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("10G")
+			lastCluster := cluster.DeepCopy()
+
+			cluster.normalizeVolumeClaimStorages(lastCluster)
+			storage := cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(storage.String()).Should(Equal("10G"))
+
+			By("normalizing the changed storages")
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("20G")
+			cluster.normalizeVolumeClaimStorages(lastCluster)
+			storage = cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(storage.String()).Should(Equal("20Gi"))
+		})
+	})
+
+	Context("volume claim sizes validation", func() {
+		It("should reject the storages less than the minimum sizes", func() {
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			minSize := resource.MustParse("1Gi")
+			compDef := ClusterComponentDefinition{
+				Name:        cluster.Spec.ComponentSpecs[0].ComponentDefRef,
+				VolumeTypes: []VolumeTypeSpec{{Name: "data", Type: VolumeTypeData, MinSize: &minSize}},
+			}
+			comp := cluster.Spec.ComponentSpecs[0]
+
+			By("storage no less than the minimum size")
+			var allErrs field.ErrorList
+			cluster.validateComponentVolumeClaimSizes(&allErrs, comp, compDef, nil, 0)
+			Expect(allErrs).Should(BeEmpty())
+
+			By("storage less than the minimum size")
+			lastCluster := cluster.DeepCopy()
+			comp.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("500Mi")
+			cluster.validateComponentVolumeClaimSizes(&allErrs, comp, compDef, lastCluster, 0)
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.components[0].volumeClaimTemplates[0].spec.resources.requests.storage"))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("volume data of component replicasets"))
+
+			By("storage unchanged from the last cluster is skipped")
+			allErrs = nil
+			lastCluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("500Mi")
+			cluster.validateComponentVolumeClaimSizes(&allErrs, comp, compDef, lastCluster, 0)
+			Expect(allErrs).Should(BeEmpty())
+		})
+
+		It("should normalize and validate the storages at admission", func() {
+			By("By creating a clusterDefinition with the minimum size of volume data")
+			clusterDef, _ := createTestClusterDefinitionObj(clusterDefinitionName)
+			minSize := resource.MustParse("1Gi")
+			for i := range clusterDef.Spec.ComponentDefs {
+				if clusterDef.Spec.ComponentDefs[i].Name == "replicasets" {
+					clusterDef.Spec.ComponentDefs[i].VolumeTypes = []VolumeTypeSpec{{Name: "data", Type: VolumeTypeData, MinSize: &minSize}}
+				}
+			}
+			Expect(testCtx.CreateObj(ctx, clusterDef)).Should(Succeed())
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: clusterDefinitionName}, clusterDef)).Should(Succeed())
+
+			clusterVersion := createTestClusterVersionObj(clusterDefinitionName, clusterVersionName)
+			Expect(testCtx.CreateObj(ctx, clusterVersion)).Should(Succeed())
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: clusterVersionName}, clusterVersion)).Should(Succeed())
+
+			By("creating cluster with the storage less than the minimum size")
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("500Mi")
+			Expect(testCtx.CreateObj(ctx, cluster).Error()).Should(ContainSubstring("should be no less than the minimum size 1Gi"))
+
+			By("creating cluster with the storage in the decimal SI suffix")
+			cluster, _ = createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("10G")
+			Expect(testCtx.CreateObj(ctx, cluster)).Should(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).Should(Succeed())
+			storage := cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(storage.String()).Should(Equal("10Gi"))
+		})
+	})
+
 	Context("tls validation", func() {
 		BeforeEach(func() {
 			By("By creating a new clusterDefinition")
@@ -21,10 +21,12 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
 )
 
 // ClusterDefinitionSpec defines the desired state of ClusterDefinition
@@ -63,6 +65,70 @@ type ClusterDefinitionSpec struct {
 	//    connection credential value is 3306.
 	// +optional
 	ConnectionCredential map[string]string `json:"connectionCredential,omitempty"`
+
+	// updatePolicy controls how the changes of the ClusterDefinition propagate to the clusters referring to it.
+	// Automatic renders the clusters against the changes on their next reconciliation.
+	// Manual keeps the workloads of the existing components of a cluster unchanged until the cluster adopts the
+	// new generation by the annotation apps.kubeblocks.io/adopt-cluster-definition-generation.
+	// +kubebuilder:default=Automatic
+	// +optional
+	UpdatePolicy ClusterDefinitionUpdatePolicy `json:"updatePolicy,omitempty"`
+
+	// externalDependencies declares the services out of the clusters which the clusters depend on, e.g. the object
+	// store to archive the WALs. They are probed from a pod of the cluster before it's reported as Running, and the
+	// unreachable ones are surfaced by the ExternalDependencyUnreachable condition of the cluster. The clusters can
+	// override them by spec.externalDependencies.
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ExternalDependencies []ExternalDependency `json:"externalDependencies,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+}
+
+// ExternalDependency declares a service out of the cluster, and how to probe whether it's reachable.
+// +kubebuilder:validation:XValidation:rule="has(self.tcpSocket) != has(self.httpGet)",message="exactly one of tcpSocket and httpGet is required"
+type ExternalDependency struct {
+	// name is the name of the dependency.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// tcpSocket probes the dependency by opening a TCP connection to it.
+	// +optional
+	TCPSocket *ExternalTCPSocketProbe `json:"tcpSocket,omitempty"`
+
+	// httpGet probes the dependency by an HTTP GET request, any status code in [200, 400) indicates it's reachable.
+	// +optional
+	HTTPGet *ExternalHTTPGetProbe `json:"httpGet,omitempty"`
+
+	// timeoutSeconds is the number of seconds after which the probe times out.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// ExternalTCPSocketProbe describes the address of the dependency to open a TCP connection to.
+type ExternalTCPSocketProbe struct {
+	// host is the host name or the IP address of the dependency. The variables $(KB_CLUSTER_NAME), $(KB_NAMESPACE)
+	// and $(KB_CLUSTER_UID_POSTFIX_8) are replaced with the ones of the cluster.
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+
+	// port is the port of the dependency.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Required
+	Port int32 `json:"port"`
+}
+
+// ExternalHTTPGetProbe describes the URL of the dependency to send the HTTP GET request to.
+type ExternalHTTPGetProbe struct {
+	// url is the URL to request, e.g. https://s3.amazonaws.com. The variables $(KB_CLUSTER_NAME), $(KB_NAMESPACE)
+	// and $(KB_CLUSTER_UID_POSTFIX_8) are replaced with the ones of the cluster.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
 }
 
 // SystemAccountSpec specifies information to create system accounts.
@@ -240,6 +306,49 @@ type VolumeTypeSpec struct {
 	// VolumeTypeLog: the volume is for the persistent log storage.
 	// +optional
 	Type VolumeType `json:"type,omitempty"`
+
+	// minSize is the minimum storage size of the volume claim of the volume,
+	// the volume claims of the clusters smaller than it are rejected.
+	// +optional
+	MinSize *resource.Quantity `json:"minSize,omitempty"`
+}
+
+type VolumeSubPathMount struct {
+	// volumeName is the name of the volume to mount, e.g. the name of the volumeClaimTemplate.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([a-z0-9\.\-]*[a-z0-9])?$`
+	VolumeName string `json:"volumeName"`
+
+	// subPath is the path within the volume to mount.
+	// +kubebuilder:validation:Required
+	SubPath string `json:"subPath"`
+
+	// mountPath is the path within the containers at which the subPath should be mounted.
+	// +kubebuilder:validation:Required
+	MountPath string `json:"mountPath"`
+
+	// containers are the names of the containers to mount the subPath into, all the containers if empty.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+}
+
+type PreStopHook struct {
+	// containerName is the name of the container to set the hook, defaults to the main container,
+	// i.e. the first container of the podSpec.
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+
+	// command is executed in the container before it's terminated, it overrides the preStop hook of the container
+	// in the podSpec.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// terminationGracePeriodSeconds is the time for the hook and the shutdown of the container to complete before
+	// the container is killed, the terminationGracePeriodSeconds of the pod is raised to it if lower.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 }
 
 type VolumeProtectionSpec struct {
@@ -272,6 +381,45 @@ type ProtectedVolume struct {
 	HighWatermark *int `json:"highWatermark,omitempty"`
 }
 
+type SharedSecretMount struct {
+	// mountPath is the path within the containers at which the shared secret should be mounted.
+	// +kubebuilder:validation:Required
+	MountPath string `json:"mountPath"`
+
+	// containers are the names of the containers to mount the shared secret.
+	// If it's empty, the shared secret will be mounted to all the containers of the component.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+}
+
+// BackendDiscovery declares the backend components of a component, and where to mount the list of the backends.
+type BackendDiscovery struct {
+	// compDef is the name of the componentDef whose components are the backends.
+	// +kubebuilder:validation:Required
+	CompDef string `json:"compDef"`
+
+	// portName is the name of the container port of the backends to route to.
+	// If it's empty, the first container port of the backends is used.
+	// +optional
+	PortName string `json:"portName,omitempty"`
+
+	// mountPath is the path within the containers at which the ConfigMap listing the backends should be mounted.
+	// The backends are listed in the file named backends, one <host>:<port>:<role> entry per line.
+	// +kubebuilder:validation:Required
+	MountPath string `json:"mountPath"`
+
+	// containers are the names of the containers to mount the ConfigMap listing the backends.
+	// If it's empty, the ConfigMap will be mounted to all the containers of the component.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+
+	// configConstraintRef is the name of the ConfigConstraint whose reloadOptions reload the backends once the file
+	// listing them changes, the same as reloading the configurations by the config manager sidecar.
+	// If it's empty, the pods are expected to watch the file by themselves.
+	// +optional
+	ConfigConstraintRef string `json:"configConstraintRef,omitempty"`
+}
+
 type ServiceRefDeclaration struct {
 	// The name of the service reference declaration.
 	// The service reference can come from an external service that is not part of KubeBlocks, or services provided by other KubeBlocks Cluster objects.
@@ -430,6 +578,11 @@ type ClusterComponentDefinition struct {
 	// +optional
 	VolumeTypes []VolumeTypeSpec `json:"volumeTypes,omitempty"`
 
+	// volumeSubPaths mounts the subPaths of the volumes into the containers, so one volume, e.g. the one claimed
+	// by the volumeClaimTemplate named data, can store the data and the logs on different subPaths.
+	// +optional
+	VolumeSubPaths []VolumeSubPathMount `json:"volumeSubPaths,omitempty"`
+
 	// customLabelSpecs is used for custom label tags which you want to add to the component resources.
 	// +listType=map
 	// +listMapKey=key
@@ -456,6 +609,40 @@ type ClusterComponentDefinition struct {
 	// serviceRefDeclarations is used to declare the service reference of the current component.
 	// +optional
 	ServiceRefDeclarations []ServiceRefDeclaration `json:"serviceRefDeclarations,omitempty"`
+
+	// sharedSecret declares that the component needs the cluster-level shared secret, and where to mount it.
+	// The shared secret is generated once when the cluster is created and never regenerated, it is shared
+	// by all the components declaring it, e.g. as the auth token for replication between components.
+	// +optional
+	SharedSecret *SharedSecretMount `json:"sharedSecret,omitempty"`
+
+	// backendDiscovery declares that the component, e.g. a proxy, routes to the members of the backend components.
+	// The addresses and roles of the members are rendered into a ConfigMap mounted into the pods, which is refreshed
+	// as the members or their roles change, and reloaded by the reloadOptions of the configConstraintRef.
+	// +optional
+	BackendDiscovery *BackendDiscovery `json:"backendDiscovery,omitempty"`
+
+	// vars declares the vars resolved from the cluster and its components, e.g. the service host of another
+	// component, and they are injected into the containers of the component as env vars.
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Vars []ComponentVar `json:"vars,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// minReadySeconds is the minimum number of seconds for which the pods of the component should be ready
+	// without any of their containers crashing, for the component to be reported as Running. It prevents the
+	// component from flapping between Running and Failed when the pods pass the readiness probe briefly and then crash.
+	// Defaults to 0, the component is Running as soon as the pods are ready.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// preStop defines the preStop lifecycle hook of the container for the graceful shutdown of the database
+	// in the container, e.g. `mysqladmin shutdown`.
+	// +optional
+	PreStop *PreStopHook `json:"preStop,omitempty"`
 }
 
 func (r *ClusterComponentDefinition) GetStatefulSetWorkload() StatefulSetWorkload {
@@ -544,6 +731,29 @@ func (r *ClusterComponentDefinition) GetMaxUnavailable() *intstr.IntOrString {
 	panic("unreachable")
 }
 
+// GetRoles returns the roles of the component, which are defined by the rsmSpec,
+// or the consensusSpec and the replicationSpec for the legacy workloads.
+func (r *ClusterComponentDefinition) GetRoles() []string {
+	var roles []string
+	switch {
+	case r.RSMSpec != nil && len(r.RSMSpec.Roles) > 0:
+		for _, role := range r.RSMSpec.Roles {
+			roles = append(roles, role.Name)
+		}
+	case r.WorkloadType == Consensus && r.ConsensusSpec != nil:
+		roles = append(roles, r.ConsensusSpec.Leader.Name)
+		for _, follower := range r.ConsensusSpec.Followers {
+			roles = append(roles, follower.Name)
+		}
+		if r.ConsensusSpec.Learner != nil {
+			roles = append(roles, r.ConsensusSpec.Learner.Name)
+		}
+	case r.WorkloadType == Replication:
+		roles = append(roles, constant.Primary, constant.Secondary)
+	}
+	return roles
+}
+
 func (r *ClusterComponentDefinition) IsStatelessWorkload() bool {
 	return r.WorkloadType == Stateless
 }
@@ -581,9 +791,37 @@ type ServiceSpec struct {
 	// +optional
 	Ports []ServicePort `json:"ports,omitempty" patchStrategy:"merge" patchMergeKey:"port" protobuf:"bytes,1,rep,name=ports"`
 
+	// serviceRoles declares the roles of the pods backing the services of the component, which enables the
+	// engines with custom role names. The services not declared select the pods of the leader role, e.g. the
+	// leader of Consensus and the primary of Replication.
+	// +optional
+	ServiceRoles []ServiceRole `json:"serviceRoles,omitempty"`
+
 	// NOTES: name also need to be key
 }
 
+// ServiceRole declares the role of the pods backing a service of the component.
+type ServiceRole struct {
+	// serviceName is the name of the service declared by the services of the cluster component,
+	// or empty for the default service of the component.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// role is the role of the pods backing the service, it should be one of the roles of the component.
+	// +kubebuilder:validation:Required
+	Role string `json:"role"`
+}
+
+// GetRole returns the role of the pods backing the service serviceName, it's empty if not declared.
+func (r *ServiceSpec) GetRole(serviceName string) string {
+	for _, serviceRole := range r.ServiceRoles {
+		if serviceRole.ServiceName == serviceName {
+			return serviceRole.Role
+		}
+	}
+	return ""
+}
+
 func (r *ServiceSpec) toSVCPorts() []corev1.ServicePort {
 	ports := make([]corev1.ServicePort, 0, len(r.Ports))
 	for _, p := range r.Ports {
@@ -864,6 +1102,49 @@ func (r *ConsensusSetSpec) FinalStsUpdateStrategy() (appsv1.PodManagementPolicyT
 	return appsv1.ParallelPodManagement, s
 }
 
+// GetLearnerReplicas returns the max number of learners, which have no voting right.
+func (r *ConsensusSetSpec) GetLearnerReplicas() int32 {
+	if r == nil || r.Learner == nil || r.Learner.Replicas == nil {
+		return 0
+	}
+	return *r.Learner.Replicas
+}
+
+// GetMaxReplicas returns the max replicas allowed by the roles, i.e.
+// leader.replicas + sum(followers[*].replicas) + learner.replicas.
+// If any follower leaves its replicas unset, the followers take all the remaining replicas,
+// and the bound is unrestricted, which is indicated by the second return value being false.
+func (r *ConsensusSetSpec) GetMaxReplicas() (int32, bool) {
+	if r == nil {
+		return 0, false
+	}
+	// leader.replicas can only be 1, zero means not present.
+	maxReplicas := int32(1)
+	for _, member := range r.Followers {
+		if member.Replicas == nil || *member.Replicas == 0 {
+			return 0, false
+		}
+		maxReplicas += *member.Replicas
+	}
+	return maxReplicas + r.GetLearnerReplicas(), true
+}
+
+// GetEvenQuorumVoters returns the number of the voting members, i.e. the replicas except the learners, if the
+// followers are in any count and the voting members can't form an odd-sized quorum, otherwise it returns zero.
+func (r *ConsensusSetSpec) GetEvenQuorumVoters(replicas int32) int32 {
+	if r == nil {
+		return 0
+	}
+	if _, bounded := r.GetMaxReplicas(); bounded {
+		return 0
+	}
+	voters := replicas - r.GetLearnerReplicas()
+	if voters <= 0 || voters%2 == 1 {
+		return 0
+	}
+	return voters
+}
+
 func NewConsensusSetSpec() *ConsensusSetSpec {
 	return &ConsensusSetSpec{
 		Leader: DefaultLeader,
@@ -1109,6 +1390,18 @@ const (
 	FromHeadlessServiceRef ComponentValueFromType = "HeadlessServiceRef"
 )
 
+// MetaVarField specifies the metadata field of the cluster or a component to select.
+// +enum
+// +kubebuilder:validation:Enum={ClusterName,ClusterNamespace,ComponentName,Replicas}
+type MetaVarField string
+
+const (
+	ClusterNameMetaVarField      MetaVarField = "ClusterName"
+	ClusterNamespaceMetaVarField MetaVarField = "ClusterNamespace"
+	ComponentNameMetaVarField    MetaVarField = "ComponentName"
+	ReplicasMetaVarField         MetaVarField = "Replicas"
+)
+
 // ComponentDefRef is used to select the component and its fields to be referenced.
 type ComponentDefRef struct {
 	// componentDefName is the name of the componentDef to select.
@@ -1168,3 +1461,72 @@ type ComponentValueFrom struct {
 	// +kubebuilder:default=","
 	JoinWith string `json:"joinWith,omitempty"`
 }
+
+// ComponentVar defines a var of the component, which is injected into the containers as an env var.
+type ComponentVar struct {
+	// name is the name of the env var to be injected, and it must be a C identifier.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// valueFrom specifies the source of the var, exactly one of its fields must be set.
+	// +kubebuilder:validation:Required
+	ValueFrom ComponentVarSource `json:"valueFrom"`
+}
+
+type ComponentVarSource struct {
+	// serviceRef selects the host or a port of the service of a component.
+	// +optional
+	ServiceRef *ServiceVarSelector `json:"serviceRef,omitempty"`
+
+	// credentialRef selects a key of the connection credential secret of the cluster,
+	// or of the system account secret of a component.
+	// +optional
+	CredentialRef *CredentialVarSelector `json:"credentialRef,omitempty"`
+
+	// metaRef selects a metadata field of the cluster or a component.
+	// +optional
+	MetaRef *MetaVarSelector `json:"metaRef,omitempty"`
+}
+
+type ServiceVarSelector struct {
+	// compDef is the name of the componentDef, the var is resolved from the service of the component referring to it,
+	// and there must be exactly one such component in the cluster.
+	// +kubebuilder:validation:Required
+	CompDef string `json:"compDef"`
+
+	// service is the name of the service declared in spec.components[*].services of the component to select,
+	// the default service of the component is selected if it's empty.
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// port is the name of the service port to select, the host of the service is selected if it's empty.
+	// +optional
+	Port string `json:"port,omitempty"`
+}
+
+type CredentialVarSelector struct {
+	// compDef is the name of the componentDef, the var is resolved from the system account secret of the component
+	// referring to it. If it's empty, the var is resolved from the connection credential secret of the cluster.
+	// +optional
+	CompDef string `json:"compDef,omitempty"`
+
+	// account is the name of the system account, it's required if compDef is specified.
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// key is the key of the secret to select.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
+type MetaVarSelector struct {
+	// compDef is the name of the componentDef, the var is resolved from the component referring to it.
+	// If it's empty, the var is resolved from the component itself.
+	// +optional
+	CompDef string `json:"compDef,omitempty"`
+
+	// field is the metadata field to select.
+	// +kubebuilder:validation:Required
+	Field MetaVarField `json:"field"`
+}
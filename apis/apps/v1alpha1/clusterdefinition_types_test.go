@@ -131,6 +131,53 @@ var _ = Describe("", func() {
 		Expect(r.GetMaxUnavailable().String()).Should(BeEquivalentTo("49%"))
 	})
 
+	It("test ConsensusSetSpec GetMaxReplicas", func() {
+		int32Ptr := func(i int32) *int32 { return &i }
+		r := NewConsensusSetSpec()
+
+		By("leader only")
+		maxReplicas, bounded := r.GetMaxReplicas()
+		Expect(bounded).Should(BeTrue())
+		Expect(maxReplicas).Should(BeEquivalentTo(1))
+
+		By("bounded followers")
+		r.Followers = []ConsensusMember{
+			{Name: "follower", AccessMode: Readonly, Replicas: int32Ptr(2)},
+		}
+		maxReplicas, bounded = r.GetMaxReplicas()
+		Expect(bounded).Should(BeTrue())
+		Expect(maxReplicas).Should(BeEquivalentTo(3))
+
+		By("bounded followers with learners")
+		r.Learner = &ConsensusMember{Name: "learner", AccessMode: Readonly, Replicas: int32Ptr(2)}
+		maxReplicas, bounded = r.GetMaxReplicas()
+		Expect(bounded).Should(BeTrue())
+		Expect(maxReplicas).Should(BeEquivalentTo(5))
+		Expect(r.GetLearnerReplicas()).Should(BeEquivalentTo(2))
+
+		By("followers in any count")
+		r.Followers = append(r.Followers, ConsensusMember{Name: "witness", AccessMode: None})
+		_, bounded = r.GetMaxReplicas()
+		Expect(bounded).Should(BeFalse())
+	})
+
+	It("test ConsensusSetSpec GetEvenQuorumVoters", func() {
+		int32Ptr := func(i int32) *int32 { return &i }
+		r := NewConsensusSetSpec()
+		r.Followers = []ConsensusMember{
+			{Name: "follower", AccessMode: Readonly, Replicas: int32Ptr(2)},
+		}
+
+		By("bounded followers are not warned")
+		Expect(r.GetEvenQuorumVoters(2)).Should(BeEquivalentTo(0))
+
+		By("followers in any count")
+		r.Followers[0].Replicas = nil
+		r.Learner = &ConsensusMember{Name: "learner", AccessMode: Readonly, Replicas: int32Ptr(1)}
+		Expect(r.GetEvenQuorumVoters(4)).Should(BeEquivalentTo(0))
+		Expect(r.GetEvenQuorumVoters(5)).Should(BeEquivalentTo(4))
+	})
+
 	It("test GetCommonStatefulSpec", func() {
 		r := &ClusterComponentDefinition{}
 		r.WorkloadType = Stateful
@@ -89,6 +89,46 @@ const (
 	CFinishedPhase       ConfigurationPhase = "Finished"
 )
 
+// ConfigDriftPolicy defines how to handle the out-of-band changes of the ConfigMaps rendered from the config templates.
+type ConfigDriftPolicy string
+
+const (
+	// FlagConfigDriftPolicy keeps the changes and reports them by the ConfigDrift condition of the Configuration.
+	FlagConfigDriftPolicy ConfigDriftPolicy = "Flag"
+	// RevertConfigDriftPolicy overwrites the changes with the rendered version.
+	RevertConfigDriftPolicy ConfigDriftPolicy = "Revert"
+)
+
+const (
+	// ConditionTypeConfigDrift the ConfigMaps of the config templates are edited out of band and differ from the rendered version.
+	ConditionTypeConfigDrift = "ConfigDrift"
+
+	ReasonConfigDrifted       = "ConfigDrifted"
+	ReasonConfigDriftReverted = "ConfigDriftReverted"
+)
+
+// ConfigTemplateUpgradePolicy defines how to apply the changes of the config templates brought by a new ClusterVersion.
+// +enum
+// +kubebuilder:validation:Enum={UseExisting,ReplaceWithNew,Manual}
+type ConfigTemplateUpgradePolicy string
+
+const (
+	// UseExistingConfigTemplateUpgradePolicy keeps the current parameters, and only adds the ones introduced by the new template,
+	// the values of the parameters renamed by the new template are carried to the new names.
+	UseExistingConfigTemplateUpgradePolicy ConfigTemplateUpgradePolicy = "UseExisting"
+	// ReplaceWithNewConfigTemplateUpgradePolicy renders the new template again, the parameters set by the reconfiguring operations are dropped.
+	ReplaceWithNewConfigTemplateUpgradePolicy ConfigTemplateUpgradePolicy = "ReplaceWithNew"
+	// ManualConfigTemplateUpgradePolicy keeps the configs unchanged until a reconfiguring operation is performed explicitly.
+	ManualConfigTemplateUpgradePolicy ConfigTemplateUpgradePolicy = "Manual"
+)
+
+const (
+	// ConditionTypePendingConfigUpgrade the config templates are changed by the ClusterVersion, and wait for a reconfiguring operation to apply.
+	ConditionTypePendingConfigUpgrade = "PendingConfigUpgrade"
+
+	ReasonConfigUpgradePending = "ConfigUpgradePending"
+)
+
 type ConfigParams struct {
 	// Data holds the configuration keys and values.
 	// This field exists to work around https://github.com/kubernetes-sigs/kubebuilder/issues/528
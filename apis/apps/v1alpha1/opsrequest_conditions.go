@@ -42,6 +42,8 @@ const (
 	ConditionTypeExpose            = "Exposing"
 	ConditionTypeDataScript        = "ExecuteDataScript"
 	ConditionTypeBackup            = "Backup"
+	ConditionTypeMonitor           = "Monitoring"
+	ConditionTypeRotateCredential  = "RotatingCredential"
 
 	// condition and event reasons
 
@@ -232,6 +234,28 @@ func NewExposingCondition(ops *OpsRequest) *metav1.Condition {
 	}
 }
 
+// NewMonitoringCondition creates a condition that the OpsRequest starts to enable or disable the monitoring of components
+func NewMonitoringCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeMonitor,
+		Status:             metav1.ConditionTrue,
+		Reason:             "MonitorStarted",
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("Start to update the monitor of components in Cluster: %s", ops.Spec.ClusterRef),
+	}
+}
+
+// NewRotatingCredentialCondition creates a condition that the OpsRequest starts to rotate the connection credential
+func NewRotatingCredentialCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeRotateCredential,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RotateCredentialStarted",
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("Start to rotate the connection credential in Cluster: %s", ops.Spec.ClusterRef),
+	}
+}
+
 // NewUpgradingCondition creates a condition that the OpsRequest starts to upgrade the cluster version
 func NewUpgradingCondition(ops *OpsRequest) *metav1.Condition {
 	return &metav1.Condition{
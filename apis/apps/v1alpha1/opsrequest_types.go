@@ -25,12 +25,26 @@ import (
 // TODO: @wangyelei could refactor to ops group
 
 // OpsRequestSpec defines the desired state of OpsRequest
-// +kubebuilder:validation:XValidation:rule="has(self.cancel) && self.cancel ? (self.type in ['VerticalScaling', 'HorizontalScaling']) : true",message="forbidden to cancel the opsRequest which type not in ['VerticalScaling','HorizontalScaling']"
+// +kubebuilder:validation:XValidation:rule="has(self.cancel) && self.cancel ? (has(self.clusterSelector) || self.type in ['VerticalScaling', 'HorizontalScaling']) : true",message="forbidden to cancel the opsRequest which type not in ['VerticalScaling','HorizontalScaling']"
+// +kubebuilder:validation:XValidation:rule="has(self.clusterRef) != has(self.clusterSelector)",message="exactly one of spec.clusterRef and spec.clusterSelector should be set"
 type OpsRequestSpec struct {
 	// clusterRef references clusterDefinition.
-	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.clusterRef"
-	ClusterRef string `json:"clusterRef"`
+	// +optional
+	ClusterRef string `json:"clusterRef,omitempty"`
+
+	// clusterSelector selects the clusters in the namespace to operate, it's mutually exclusive with clusterRef.
+	// The operation fans out into a child OpsRequest for each selected cluster, the clusters are selected once
+	// the OpsRequest starts. Cancelling the OpsRequest cancels the children not running yet.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.clusterSelector"
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// maxConcurrent is the maximum number of the child OpsRequests running at the same time, so the selected
+	// clusters are not operated at once. It only works with clusterSelector, all of them run at once if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
 
 	// cancel defines the action to cancel the Pending/Creating/Running opsRequest, supported types: [VerticalScaling, HorizontalScaling].
 	// once cancel is set to true, this opsRequest will be canceled and modifying this property again will not take effect.
@@ -135,6 +149,20 @@ type OpsRequestSpec struct {
 	// backupSpec defines how to backup the cluster.
 	// +optional
 	BackupSpec *BackupSpec `json:"backupSpec,omitempty"`
+
+	// monitor enables or disables the monitoring of the specified components.
+	// +optional
+	// +patchMergeKey=componentName
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=componentName
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.monitor"
+	MonitorList []Monitor `json:"monitor,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"componentName"`
+
+	// rotateCredential regenerates the password of the connection credential and changes it on the engine.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.rotateCredential"
+	RotateCredential *RotateCredential `json:"rotateCredential,omitempty"`
 }
 
 // ComponentOps defines the common variables of component scope operations.
@@ -164,6 +192,14 @@ type Upgrade struct {
 	// clusterVersionRef references ClusterVersion name.
 	// +kubebuilder:validation:Required
 	ClusterVersionRef string `json:"clusterVersionRef"`
+
+	// upgradePolicy defines how to apply the changes of the config templates brought by the new ClusterVersion.
+	// UseExisting keeps the current parameters and only adds the ones introduced by the new templates,
+	// ReplaceWithNew renders the new templates again and drops the parameters set by the reconfiguring operations,
+	// Manual keeps the configs unchanged until a reconfiguring operation is performed explicitly.
+	// +kubebuilder:default=ReplaceWithNew
+	// +optional
+	UpgradePolicy ConfigTemplateUpgradePolicy `json:"upgradePolicy,omitempty"`
 }
 
 // VerticalScaling defines the variables that need to input when scaling compute resources.
@@ -177,6 +213,11 @@ type VerticalScaling struct {
 	// classDefRef reference class defined in ComponentClassDefinition.
 	// +optional
 	ClassDefRef *ClassDefRef `json:"classDefRef,omitempty"`
+
+	// priorityClassName specifies the name of the PriorityClass of the component pods.
+	// It keeps unchanged if not specified.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // VolumeExpansion defines the variables of volume expansion operation.
@@ -298,6 +339,34 @@ type Expose struct {
 	Services []ClusterComponentService `json:"services"`
 }
 
+type Monitor struct {
+	ComponentOps `json:",inline"`
+
+	// enabled specifies whether to enable the monitoring of the component.
+	// +kubebuilder:validation:Required
+	Enabled bool `json:"enabled"`
+}
+
+// RotateCredential defines how to rotate the connection credential, the password is changed on the primary pod
+// of the component before the connection credential secret is updated.
+type RotateCredential struct {
+	ComponentOps `json:",inline"`
+
+	// statement is the template of the statement changing the password on the engine, such as
+	// "ALTER USER $(USERNAME) IDENTIFIED BY '$(PASSWD)'". If not specified, the update statement of
+	// the system accounts defined in the component definition is used.
+	// +optional
+	Statement string `json:"statement,omitempty"`
+
+	// gracePeriod is the duration the previous password is kept in the connection secret after the rotation,
+	// in the form of <number><unit>, the unit is one of d(days), h(hours) and m(minutes), e.g. 1h. The statement
+	// is expected to keep the previous password valid on the engine for the period as well. If not specified,
+	// the previous password is dropped once the rotation succeeds.
+	// +kubebuilder:validation:Pattern:=`^[0-9]+(d|h|m)$`
+	// +optional
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
 type RestoreFromSpec struct {
 	// use the backup name and component name for restore, support for multiple components' recovery.
 	// +optional
@@ -448,6 +517,15 @@ type OpsRequestStatus struct {
 	// +optional
 	ReconfiguringStatus *ReconfiguringStatus `json:"reconfiguringStatus,omitempty"`
 
+	// cleanedResources records the intermediate resources deleted after the operation failed or was cancelled,
+	// in the format of "<kind>/<name>".
+	// +optional
+	CleanedResources []string `json:"cleanedResources,omitempty"`
+
+	// clusters records the progress of the clusters selected by the clusterSelector.
+	// +optional
+	Clusters []OpsRequestClusterStatus `json:"clusters,omitempty"`
+
 	// conditions describes opsRequest detail status.
 	// +optional
 	// +patchMergeKey=type
@@ -457,6 +535,25 @@ type OpsRequestStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// OpsRequestClusterStatus records the progress of a cluster selected by the clusterSelector.
+type OpsRequestClusterStatus struct {
+	// clusterName is the name of the selected cluster.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// opsRequestName is the name of the child OpsRequest operating the cluster, it's empty if not started yet.
+	// +optional
+	OpsRequestName string `json:"opsRequestName,omitempty"`
+
+	// phase is the phase of the child OpsRequest.
+	// +optional
+	Phase OpsPhase `json:"phase,omitempty"`
+
+	// message describes the failure of the child OpsRequest.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 type ProgressStatusDetail struct {
 	// group describes which group the current object belongs to.
 	// if the objects of a component belong to the same group, we can ignore it.
@@ -506,6 +603,14 @@ type LastComponentConfiguration struct {
 	// +optional
 	Services []ClusterComponentService `json:"services,omitempty"`
 
+	// monitor records the last monitor flag of the component.
+	// +optional
+	Monitor *bool `json:"monitor,omitempty"`
+
+	// priorityClassName records the last priorityClassName of the component.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// targetResources records the affecting target resources information for the component.
 	// resource key is in list of [pods].
 	// +optional
@@ -752,6 +857,34 @@ func (r OpsRequestSpec) GetExposeComponentNameSet() ComponentNameSet {
 	return set
 }
 
+// GetMonitorComponentNameSet gets the component name map with monitor operation.
+func (r OpsRequestSpec) GetMonitorComponentNameSet() ComponentNameSet {
+	set := make(ComponentNameSet)
+	for _, v := range r.MonitorList {
+		set[v.ComponentName] = struct{}{}
+	}
+	return set
+}
+
+// ToMonitorListToMap converts OpsRequest.spec.monitor list to map
+func (r OpsRequestSpec) ToMonitorListToMap() map[string]Monitor {
+	monitorMap := make(map[string]Monitor)
+	for _, v := range r.MonitorList {
+		monitorMap[v.ComponentName] = v
+	}
+	return monitorMap
+}
+
+// GetRotateCredentialComponentNameSet gets the component name map with rotate credential operation.
+func (r OpsRequestSpec) GetRotateCredentialComponentNameSet() ComponentNameSet {
+	if r.RotateCredential == nil {
+		return nil
+	}
+	return ComponentNameSet{
+		r.RotateCredential.ComponentName: {},
+	}
+}
+
 // GetUpgradeComponentNameSet gets the component name map with upgrade operation.
 func (r *OpsRequest) GetUpgradeComponentNameSet() ComponentNameSet {
 	if r == nil || r.Spec.Upgrade == nil {
@@ -785,6 +918,10 @@ func (r *OpsRequest) GetComponentNameSet() ComponentNameSet {
 		return r.Spec.GetSwitchoverComponentNameSet()
 	case DataScriptType:
 		return r.Spec.GetDataScriptComponentNameSet()
+	case MonitorType:
+		return r.Spec.GetMonitorComponentNameSet()
+	case RotateCredentialType:
+		return r.Spec.GetRotateCredentialComponentNameSet()
 	default:
 		return nil
 	}
@@ -29,8 +29,10 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -38,6 +40,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/apecloud/kubeblocks/internal/constant"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
 
 // log is for logging in this package.
@@ -102,11 +105,48 @@ func (r *OpsRequest) IsComplete(phases ...OpsPhase) bool {
 	return slices.Contains([]OpsPhase{OpsCancelledPhase, OpsSucceedPhase, OpsFailedPhase}, phases[0])
 }
 
+// GetOpsRequestAllowedClusterPhases returns the cluster phases in which the OpsRequest of the type is allowed to be
+// created and executed, empty means any phase is allowed. The phases of the types can be overridden by a JSON map
+// configured by OPS_REQUEST_ALLOWED_CLUSTER_PHASES, otherwise the phases registered by the OpsRequest controller
+// in OpsRequestBehaviourMapper are used.
+func GetOpsRequestAllowedClusterPhases(opsType OpsType) ([]ClusterPhase, error) {
+	if value := viper.GetString(constant.CfgKeyOpsRequestAllowedClusterPhases); len(value) > 0 {
+		configuredPhases := map[OpsType][]ClusterPhase{}
+		if err := json.Unmarshal([]byte(value), &configuredPhases); err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", constant.CfgKeyOpsRequestAllowedClusterPhases, err.Error())
+		}
+		if phases, ok := configuredPhases[opsType]; ok {
+			return phases, nil
+		}
+	}
+	return OpsRequestBehaviourMapper[opsType].FromClusterPhases, nil
+}
+
+// ValidateOpsRequestClusterPhase validates whether the OpsRequest of the type is allowed in the cluster phase.
+func ValidateOpsRequestClusterPhase(opsType OpsType, clusterPhase ClusterPhase) error {
+	phases, err := GetOpsRequestAllowedClusterPhases(opsType)
+	if err != nil {
+		return err
+	}
+	if len(phases) == 0 || slices.Contains(phases, clusterPhase) {
+		return nil
+	}
+	return forbiddenClusterPhaseError(opsType, clusterPhase, phases)
+}
+
+func forbiddenClusterPhaseError(opsType OpsType, clusterPhase ClusterPhase, allowedPhases []ClusterPhase) error {
+	return fmt.Errorf("OpsRequest.spec.type=%s is forbidden when Cluster.status.phase=%s, the allowed phases are %v",
+		opsType, clusterPhase, allowedPhases)
+}
+
 // validateClusterPhase validates whether the current cluster state supports the OpsRequest
 func (r *OpsRequest) validateClusterPhase(cluster *Cluster) error {
-	opsBehaviour := OpsRequestBehaviourMapper[r.Spec.Type]
+	allowedPhases, err := GetOpsRequestAllowedClusterPhases(r.Spec.Type)
+	if err != nil {
+		return err
+	}
 	// if the OpsType has no cluster phases, ignore it
-	if len(opsBehaviour.FromClusterPhases) == 0 {
+	if len(allowedPhases) == 0 {
 		return nil
 	}
 	// validate whether existing the same type OpsRequest
@@ -134,11 +174,11 @@ func (r *OpsRequest) validateClusterPhase(cluster *Cluster) error {
 		opsNamesInQueue[i] = v.Name
 	}
 	// check if the opsRequest can be executed in the current cluster phase unless this opsRequest is reentrant.
-	if !slices.Contains(opsBehaviour.FromClusterPhases, cluster.Status.Phase) &&
+	if !slices.Contains(allowedPhases, cluster.Status.Phase) &&
 		!slices.Contains(opsNamesInQueue, r.Name) {
 		// if TTLSecondsBeforeAbort is not set or 0, return error
 		if r.Spec.TTLSecondsBeforeAbort == nil || *r.Spec.TTLSecondsBeforeAbort == 0 {
-			return fmt.Errorf("OpsRequest.spec.type=%s is forbidden when Cluster.status.phase=%s", r.Spec.Type, cluster.Status.Phase)
+			return forbiddenClusterPhaseError(r.Spec.Type, cluster.Status.Phase, allowedPhases)
 		}
 	}
 	return nil
@@ -180,15 +220,41 @@ func (r *OpsRequest) Validate(ctx context.Context,
 		if err := r.validateClusterPhase(cluster); err != nil {
 			return err
 		}
+		if err := r.validatePausedComponents(cluster); err != nil {
+			return err
+		}
 	}
 	return r.validateOps(ctx, k8sClient, cluster)
 }
 
+// validatePausedComponents validates whether the OpsRequest targets the paused components of the cluster.
+func (r *OpsRequest) validatePausedComponents(cluster *Cluster) error {
+	var pausedCompNames []string
+	for compName := range r.GetComponentNameSet() {
+		if cluster.IsComponentPaused(compName) {
+			pausedCompNames = append(pausedCompNames, compName)
+		}
+	}
+	if len(pausedCompNames) == 0 {
+		return nil
+	}
+	slices.Sort(pausedCompNames)
+	return fmt.Errorf("components: %v are paused in Cluster: %s, remove them from the annotation %s first",
+		pausedCompNames, cluster.Name, constant.PausedComponentsAnnotationKey)
+}
+
 // ValidateEntry OpsRequest webhook validate entry
 func (r *OpsRequest) validateEntry(isCreate bool) error {
 	if webhookMgr == nil || webhookMgr.client == nil {
 		return nil
 	}
+	if err := r.validateClusterTarget(); err != nil {
+		return err
+	}
+	// the OpsRequest selecting the clusters is validated against each cluster by its child OpsRequests.
+	if r.Spec.ClusterSelector != nil {
+		return nil
+	}
 	ctx := context.Background()
 	k8sClient := webhookMgr.client
 	cluster, err := r.getCluster(ctx, k8sClient)
@@ -198,6 +264,21 @@ func (r *OpsRequest) validateEntry(isCreate bool) error {
 	return r.Validate(ctx, k8sClient, cluster, isCreate)
 }
 
+// validateClusterTarget validates the OpsRequest targets either a cluster by clusterRef or the clusters by clusterSelector.
+func (r *OpsRequest) validateClusterTarget() error {
+	if (len(r.Spec.ClusterRef) == 0) == (r.Spec.ClusterSelector == nil) {
+		return fmt.Errorf("exactly one of spec.clusterRef and spec.clusterSelector should be set")
+	}
+	if r.Spec.ClusterSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.Spec.ClusterSelector); err != nil {
+			return fmt.Errorf("invalid spec.clusterSelector: %s", err.Error())
+		}
+	} else if r.Spec.MaxConcurrent != nil {
+		return fmt.Errorf("spec.maxConcurrent only works with spec.clusterSelector")
+	}
+	return nil
+}
+
 // validateOps validates ops attributes
 func (r *OpsRequest) validateOps(ctx context.Context,
 	k8sClient client.Client,
@@ -223,10 +304,53 @@ func (r *OpsRequest) validateOps(ctx context.Context,
 		return r.validateSwitchover(ctx, k8sClient, cluster)
 	case DataScriptType:
 		return r.validateDataScript(ctx, k8sClient, cluster)
+	case MonitorType:
+		return r.validateMonitor(cluster)
+	case RotateCredentialType:
+		return r.validateRotateCredential(cluster)
+	case ExposeType:
+		return r.validateExpose(cluster)
 	}
 	return nil
 }
 
+// validateExpose validates spec.expose
+func (r *OpsRequest) validateExpose(cluster *Cluster) error {
+	exposeList := r.Spec.ExposeList
+	if len(exposeList) == 0 {
+		return notEmptyError("spec.expose")
+	}
+
+	compNames := make([]string, len(exposeList))
+	for i, v := range exposeList {
+		compNames[i] = v.ComponentName
+	}
+	return r.checkComponentExistence(cluster, compNames)
+}
+
+// validateMonitor validates spec.monitor
+func (r *OpsRequest) validateMonitor(cluster *Cluster) error {
+	monitorList := r.Spec.MonitorList
+	if len(monitorList) == 0 {
+		return notEmptyError("spec.monitor")
+	}
+
+	compNames := make([]string, len(monitorList))
+	for i, v := range monitorList {
+		compNames[i] = v.ComponentName
+	}
+	return r.checkComponentExistence(cluster, compNames)
+}
+
+// validateRotateCredential validates spec.rotateCredential
+func (r *OpsRequest) validateRotateCredential(cluster *Cluster) error {
+	rotateCredential := r.Spec.RotateCredential
+	if rotateCredential == nil {
+		return notEmptyError("spec.rotateCredential")
+	}
+	return r.checkComponentExistence(cluster, []string{rotateCredential.ComponentName})
+}
+
 // validateUpgrade validates spec.restart
 func (r *OpsRequest) validateRestart(cluster *Cluster) error {
 	restartList := r.Spec.RestartList
@@ -344,7 +468,34 @@ func (r *OpsRequest) validateHorizontalScaling(ctx context.Context, cli client.C
 	for i, v := range horizontalScalingList {
 		componentNames[i] = v.ComponentName
 	}
-	return r.checkComponentExistence(cluster, componentNames)
+	if err := r.checkComponentExistence(cluster, componentNames); err != nil {
+		return err
+	}
+	return r.checkHorizontalScalingReplicas(ctx, cli, cluster)
+}
+
+// checkHorizontalScalingReplicas checks the target replicas against the bound derived from the consensusSpec of the componentDef.
+func (r *OpsRequest) checkHorizontalScalingReplicas(ctx context.Context, cli client.Client, cluster *Cluster) error {
+	if cli == nil || len(cluster.Spec.ClusterDefRef) == 0 {
+		return nil
+	}
+	clusterDef := &ClusterDefinition{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: cluster.Spec.ClusterDefRef}, clusterDef); err != nil {
+		return fmt.Errorf("get clusterDefinition: %s failed, err: %s", cluster.Spec.ClusterDefRef, err.Error())
+	}
+	for i, v := range r.Spec.HorizontalScalingList {
+		compSpec := cluster.Spec.GetComponentByName(v.ComponentName)
+		compDef := clusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef == nil || compDef.WorkloadType != Consensus || compDef.ConsensusSpec == nil {
+			continue
+		}
+		if maxReplicas, bounded := compDef.ConsensusSpec.GetMaxReplicas(); bounded && v.Replicas > maxReplicas {
+			return field.Invalid(field.NewPath("spec", "horizontalScaling").Index(i).Child("replicas"), v.Replicas,
+				fmt.Sprintf("replicas should be no more than %d (leader + followers + learner) defined by the consensusSpec of componentDef %s",
+					maxReplicas, compDef.Name))
+		}
+	}
+	return nil
 }
 
 // validateVolumeExpansion validates volumeExpansion api when spec.type is VolumeExpansion
@@ -550,14 +701,22 @@ func (r *OpsRequest) getSCNameByPvcAndCheckStorageSize(ctx context.Context,
 	if pvc == nil {
 		return nil, nil
 	}
-	previousValue := *pvc.Status.Capacity.Storage()
-	if requestStorage.Cmp(previousValue) < 0 {
-		return nil, fmt.Errorf(`requested storage size of volumeClaimTemplate "%s" can not less than status.capacity.storage "%s" `,
-			vctName, previousValue.String())
+	if err := ValidateVolumeExpansionStorage(vctName, requestStorage, *pvc.Status.Capacity.Storage()); err != nil {
+		return nil, err
 	}
 	return pvc.Spec.StorageClassName, nil
 }
 
+// ValidateVolumeExpansionStorage checks the requested storage size is not less than the current capacity of the volume,
+// as shrinking volumes is not supported. It's shared by the webhook and kbcli to reject the storage decrease consistently.
+func ValidateVolumeExpansionStorage(vctName string, requestStorage, capacity resource.Quantity) error {
+	if requestStorage.Cmp(capacity) < 0 {
+		return fmt.Errorf(`requested storage size of volumeClaimTemplate "%s" can not less than status.capacity.storage "%s" `,
+			vctName, capacity.String())
+	}
+	return nil
+}
+
 // validateDataScript validates the data script.
 func (r *OpsRequest) validateDataScript(ctx context.Context, cli client.Client, cluster *Cluster) error {
 	validateScript := func(spec *ScriptSpec) error {
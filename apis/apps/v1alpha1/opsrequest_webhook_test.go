@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/apecloud/kubeblocks/internal/constant"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
 	// testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 )
 
@@ -518,8 +519,22 @@ var _ = Describe("OpsRequest webhook", func() {
 		}
 		Expect(testCtx.CreateObj(ctx, opsRequest).Error()).To(ContainSubstring(notFoundComponentsString("replicasets1")))
 
-		By("By testing restart. if api is legal, it will create successfully")
+		By("By testing restart when the component is paused")
 		opsRequest.Spec.RestartList[0].ComponentName = componentName
+		pausedCluster := &Cluster{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), pausedCluster)).Should(Succeed())
+		clusterPatch := client.MergeFrom(pausedCluster.DeepCopy())
+		if pausedCluster.Annotations == nil {
+			pausedCluster.Annotations = map[string]string{}
+		}
+		pausedCluster.Annotations[constant.PausedComponentsAnnotationKey] = componentName
+		Expect(k8sClient.Patch(ctx, pausedCluster, clusterPatch)).Should(Succeed())
+		Expect(testCtx.CreateObj(ctx, opsRequest).Error()).To(ContainSubstring("are paused in Cluster"))
+
+		By("By testing restart. if api is legal, it will create successfully")
+		clusterPatch = client.MergeFrom(pausedCluster.DeepCopy())
+		delete(pausedCluster.Annotations, constant.PausedComponentsAnnotationKey)
+		Expect(k8sClient.Patch(ctx, pausedCluster, clusterPatch)).Should(Succeed())
 		Expect(testCtx.CheckedCreateObj(ctx, opsRequest)).Should(Succeed())
 		return opsRequest
 	}
@@ -665,6 +680,124 @@ var _ = Describe("OpsRequest webhook", func() {
 			Expect(testCtx.CheckedCreateObj(ctx, opsRequest)).Should(Succeed())
 		})
 	})
+
+	Context("cluster phase compatibility", func() {
+		BeforeEach(func() {
+			// the phases are registered by the OpsRequest controller
+			for opsType, phases := range map[OpsType][]ClusterPhase{
+				UpgradeType:           GetClusterUpRunningPhases(),
+				VolumeExpansionType:   {RunningClusterPhase, AbnormalClusterPhase},
+				HorizontalScalingType: GetClusterUpRunningPhases(),
+				StartType:             {StoppedClusterPhase},
+				ReconfiguringType:     GetReconfiguringRunningPhases(),
+				DataScriptType:        {RunningClusterPhase},
+			} {
+				OpsRequestBehaviourMapper[opsType] = OpsRequestBehaviour{FromClusterPhases: phases}
+			}
+		})
+
+		It("should allow the OpsRequests only in the allowed cluster phases", func() {
+			testCases := []struct {
+				opsType         OpsType
+				allowedPhases   []ClusterPhase
+				forbiddenPhases []ClusterPhase
+			}{
+				{UpgradeType, []ClusterPhase{RunningClusterPhase, AbnormalClusterPhase}, []ClusterPhase{CreatingClusterPhase, StoppedClusterPhase}},
+				{VolumeExpansionType, []ClusterPhase{RunningClusterPhase, AbnormalClusterPhase}, []ClusterPhase{FailedClusterPhase, CreatingClusterPhase}},
+				{HorizontalScalingType, []ClusterPhase{RunningClusterPhase, FailedClusterPhase}, []ClusterPhase{CreatingClusterPhase, UpdatingClusterPhase}},
+				{StartType, []ClusterPhase{StoppedClusterPhase}, []ClusterPhase{RunningClusterPhase, StoppingClusterPhase}},
+				{ReconfiguringType, []ClusterPhase{RunningClusterPhase, UpdatingClusterPhase}, []ClusterPhase{CreatingClusterPhase, StoppedClusterPhase}},
+				{DataScriptType, []ClusterPhase{RunningClusterPhase}, []ClusterPhase{AbnormalClusterPhase, FailedClusterPhase}},
+			}
+			for _, tc := range testCases {
+				for _, phase := range tc.allowedPhases {
+					Expect(ValidateOpsRequestClusterPhase(tc.opsType, phase)).Should(Succeed())
+				}
+				for _, phase := range tc.forbiddenPhases {
+					Expect(ValidateOpsRequestClusterPhase(tc.opsType, phase)).Should(MatchError(
+						ContainSubstring(fmt.Sprintf("OpsRequest.spec.type=%s is forbidden when Cluster.status.phase=%s", tc.opsType, phase))))
+				}
+			}
+		})
+
+		It("should respect the configured cluster phases", func() {
+			defer viper.Set(constant.CfgKeyOpsRequestAllowedClusterPhases, "")
+
+			viper.Set(constant.CfgKeyOpsRequestAllowedClusterPhases, `{"VolumeExpansion":["Running","Abnormal","Failed"]}`)
+			Expect(ValidateOpsRequestClusterPhase(VolumeExpansionType, FailedClusterPhase)).Should(Succeed())
+			// the types not configured keep the defaults
+			Expect(ValidateOpsRequestClusterPhase(UpgradeType, CreatingClusterPhase)).ShouldNot(Succeed())
+
+			viper.Set(constant.CfgKeyOpsRequestAllowedClusterPhases, "invalid")
+			Expect(ValidateOpsRequestClusterPhase(VolumeExpansionType, RunningClusterPhase)).Should(
+				MatchError(ContainSubstring("invalid " + constant.CfgKeyOpsRequestAllowedClusterPhases)))
+		})
+
+		It("should reject the OpsRequests against the clusters in incompatible phases", func() {
+			By("create a cluster with a consensus component")
+			clusterDef, _ := createTestClusterDefinitionObj(clusterDefinitionName)
+			clusterDef.Spec.ComponentDefs[0].WorkloadType = Consensus
+			clusterDef.Spec.ComponentDefs[0].ConsensusSpec = &ConsensusSetSpec{
+				Leader:    DefaultLeader,
+				Followers: []ConsensusMember{{Name: "follower", AccessMode: Readonly, Replicas: int32Ptr(2)}},
+			}
+			Expect(testCtx.CheckedCreateObj(ctx, clusterDef)).Should(Succeed())
+			clusterVersion := createTestClusterVersionObj(clusterDefinitionName, clusterVersionName)
+			Expect(testCtx.CheckedCreateObj(ctx, clusterVersion)).Should(Succeed())
+			cluster, _ := createTestCluster(clusterDefinitionName, clusterVersionName, clusterName)
+			Expect(testCtx.CheckedCreateObj(ctx, cluster)).Should(Succeed())
+			patchClusterPhase := func(phase ClusterPhase) {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).Should(Succeed())
+				clusterPatch := client.MergeFrom(cluster.DeepCopy())
+				cluster.Status.Phase = phase
+				Expect(k8sClient.Status().Patch(ctx, cluster, clusterPatch)).Should(Succeed())
+			}
+
+			By("upgrade a creating cluster")
+			patchClusterPhase(CreatingClusterPhase)
+			opsRequest := createTestOpsRequest(clusterName, opsRequestName+"-upgrade", UpgradeType)
+			opsRequest.Spec.Upgrade = &Upgrade{ClusterVersionRef: clusterVersionName}
+			Expect(testCtx.CreateObj(ctx, opsRequest).Error()).Should(ContainSubstring("Upgrade is forbidden when Cluster.status.phase=Creating"))
+
+			By("expand the volumes of a failed cluster")
+			patchClusterPhase(FailedClusterPhase)
+			opsRequest = createTestOpsRequest(clusterName, opsRequestName+"-ve", VolumeExpansionType)
+			opsRequest.Spec.VolumeExpansionList = []VolumeExpansion{{
+				ComponentOps:         ComponentOps{ComponentName: componentName},
+				VolumeClaimTemplates: []OpsRequestVolumeClaimTemplate{{Name: "data", Storage: resource.MustParse("2Gi")}},
+			}}
+			Expect(testCtx.CreateObj(ctx, opsRequest).Error()).Should(ContainSubstring("VolumeExpansion is forbidden when Cluster.status.phase=Failed"))
+
+			By("scale out a running cluster beyond the bound of the consensusSpec")
+			patchClusterPhase(RunningClusterPhase)
+			opsRequest = createTestOpsRequest(clusterName, opsRequestName+"-hs", HorizontalScalingType)
+			opsRequest.Spec.HorizontalScalingList = []HorizontalScaling{{
+				ComponentOps: ComponentOps{ComponentName: componentName},
+				Replicas:     5,
+			}}
+			Expect(testCtx.CreateObj(ctx, opsRequest).Error()).Should(ContainSubstring("spec.horizontalScaling[0].replicas"))
+			opsRequest.Spec.HorizontalScalingList[0].Replicas = 3
+			Expect(testCtx.CheckedCreateObj(ctx, opsRequest)).Should(Succeed())
+
+			By("expose a component not in the cluster")
+			opsRequest = createTestOpsRequest(clusterName, opsRequestName+"-expose", ExposeType)
+			opsRequest.Spec.ExposeList = []Expose{{
+				ComponentOps: ComponentOps{ComponentName: "expose-not-exist"},
+				Services:     []ClusterComponentService{{Name: "vpc", ServiceType: corev1.ServiceTypeLoadBalancer}},
+			}}
+			Expect(testCtx.CreateObj(ctx, opsRequest).Error()).Should(ContainSubstring(notFoundComponentsString("expose-not-exist")))
+		})
+	})
+
+	Context("volume expansion storage validation", func() {
+		It("should reject the storage decrease", func() {
+			capacity := resource.MustParse("1Gi")
+			Expect(ValidateVolumeExpansionStorage("data", resource.MustParse("1024Mi"), capacity)).Should(Succeed())
+			Expect(ValidateVolumeExpansionStorage("data", resource.MustParse("2Gi"), capacity)).Should(Succeed())
+			Expect(ValidateVolumeExpansionStorage("data", resource.MustParse("500Mi"), capacity)).
+				Should(MatchError(`requested storage size of volumeClaimTemplate "data" can not less than status.capacity.storage "1Gi" `))
+		})
+	})
 })
 
 func createTestOpsRequest(clusterName, opsRequestName string, opsType OpsType) *OpsRequest {
@@ -169,12 +169,37 @@ const (
 
 const (
 	// define the cluster condition type
-	ConditionTypeHaltRecovery        = "HaltRecovery"        // ConditionTypeHaltRecovery describe Halt recovery processing stage
-	ConditionTypeProvisioningStarted = "ProvisioningStarted" // ConditionTypeProvisioningStarted the operator starts resource provisioning to create or change the cluster
-	ConditionTypeApplyResources      = "ApplyResources"      // ConditionTypeApplyResources the operator start to apply resources to create or change the cluster
-	ConditionTypeReplicasReady       = "ReplicasReady"       // ConditionTypeReplicasReady all pods of components are ready
-	ConditionTypeReady               = "Ready"               // ConditionTypeReady all components are running
-	ConditionTypeSwitchoverPrefix    = "Switchover-"         // ConditionTypeSwitchoverPrefix component status condition of switchover
+	ConditionTypeHaltRecovery          = "HaltRecovery"          // ConditionTypeHaltRecovery describe Halt recovery processing stage
+	ConditionTypeProvisioningStarted   = "ProvisioningStarted"   // ConditionTypeProvisioningStarted the operator starts resource provisioning to create or change the cluster
+	ConditionTypeApplyResources        = "ApplyResources"        // ConditionTypeApplyResources the operator start to apply resources to create or change the cluster
+	ConditionTypeReplicasReady         = "ReplicasReady"         // ConditionTypeReplicasReady all pods of components are ready
+	ConditionTypeReady                 = "Ready"                 // ConditionTypeReady all components are running
+	ConditionTypeSwitchoverPrefix      = "Switchover-"           // ConditionTypeSwitchoverPrefix component status condition of switchover
+	ConditionTypeSchedulingBlocked     = "SchedulingBlocked"     // ConditionTypeSchedulingBlocked pods of components are unschedulable due to the affinity constraints
+	ConditionTypeReducedObservability  = "ReducedObservability"  // ConditionTypeReducedObservability the probe and exporter sidecars are omitted in lightweight mode
+	ConditionTypeImageDigestUnresolved = "ImageDigestUnresolved" // ConditionTypeImageDigestUnresolved the digests of the images of components can't be resolved
+	// ConditionTypeClusterDefinitionUpdatePending the changes of the ClusterDefinition wait for the cluster to adopt them
+	ConditionTypeClusterDefinitionUpdatePending = "ClusterDefinitionUpdatePending"
+	// ConditionTypeVolumeUtilizationHigh the utilization of the PVCs of components exceeds the threshold, and the volumes need expansion
+	ConditionTypeVolumeUtilizationHigh = "VolumeUtilizationHigh"
+	// ConditionTypeInvalidVolumeMount the containers of components mount the volumes claimed by the volume claim templates missing in the components
+	ConditionTypeInvalidVolumeMount = "InvalidVolumeMount"
+	// ConditionTypeConnCredentialRecreated the connection credential secret was deleted and has been recreated
+	ConditionTypeConnCredentialRecreated = "ConnCredentialRecreated"
+	// ConditionTypeExternalDependencyUnreachable the external dependencies declared by the ClusterDefinition are unreachable
+	ConditionTypeExternalDependencyUnreachable = "ExternalDependencyUnreachable"
+	// ConditionTypeSystemAccountsProvisionFailed the provisioning jobs of the system accounts keep failing after the max attempts
+	ConditionTypeSystemAccountsProvisionFailed = "SystemAccountsProvisionFailed"
+)
+
+// ClusterDefinitionUpdatePolicy defines how the changes of the ClusterDefinition propagate to the clusters.
+// +enum
+// +kubebuilder:validation:Enum={Automatic,Manual}
+type ClusterDefinitionUpdatePolicy string
+
+const (
+	AutomaticClusterDefUpdatePolicy ClusterDefinitionUpdatePolicy = "Automatic"
+	ManualClusterDefUpdatePolicy    ClusterDefinitionUpdatePolicy = "Manual"
 )
 
 // Phase defines the ClusterDefinition and ClusterVersion  CR .status.phase
@@ -215,7 +240,7 @@ const (
 
 // OpsType defines operation types.
 // +enum
-// +kubebuilder:validation:Enum={Upgrade,VerticalScaling,VolumeExpansion,HorizontalScaling,Restart,Reconfiguring,Start,Stop,Expose,Switchover,DataScript,Backup}
+// +kubebuilder:validation:Enum={Upgrade,VerticalScaling,VolumeExpansion,HorizontalScaling,Restart,Reconfiguring,Start,Stop,Expose,Switchover,DataScript,Backup,Monitor,RotateCredential}
 type OpsType string
 
 const (
@@ -231,6 +256,8 @@ const (
 	ExposeType            OpsType = "Expose"
 	DataScriptType        OpsType = "DataScript" // DataScriptType the data script operation will execute the data script against the cluster.
 	BackupType            OpsType = "Backup"
+	MonitorType           OpsType = "Monitor"          // MonitorType the monitor operation will enable or disable the monitoring of the components.
+	RotateCredentialType  OpsType = "RotateCredential" // RotateCredentialType the rotate credential operation will regenerate the password of the connection credential.
 )
 
 // ComponentResourceKey defines the resource key of component, such as pod/pvc.
@@ -425,6 +452,20 @@ func (r AccountName) GetAccountID() KBAccountType {
 	return KBAccountInvalid
 }
 
+// AccountProvisionPhase defines the provisioning phase of a system account.
+// +enum
+// +kubebuilder:validation:Enum={InProgress,Provisioned,Failed}
+type AccountProvisionPhase string
+
+const (
+	// AccountProvisionInProgress means the provisioning jobs of the account are running.
+	AccountProvisionInProgress AccountProvisionPhase = "InProgress"
+	// AccountProvisioned means the account is created and its secret is rendered.
+	AccountProvisioned AccountProvisionPhase = "Provisioned"
+	// AccountProvisionFailed means the last provisioning attempt of the account failed, it will be retried later.
+	AccountProvisionFailed AccountProvisionPhase = "Failed"
+)
+
 // LetterCase defines cases to use in password generation.
 // +enum
 type LetterCase string
@@ -547,6 +588,16 @@ const (
 	Noop                  SwitchPolicyType = "Noop"
 )
 
+// SplitBrainRecoveryPolicy defines how to choose the primary to keep when multiple pods claim the primary.
+// +enum
+// +kubebuilder:validation:Enum={NewestDataWins,LowestOrdinalWins}
+type SplitBrainRecoveryPolicy string
+
+const (
+	NewestDataWins    SplitBrainRecoveryPolicy = "NewestDataWins"
+	LowestOrdinalWins SplitBrainRecoveryPolicy = "LowestOrdinalWins"
+)
+
 // SwitchStepRole defines the role to execute the switch command.
 // +enum
 // +kubebuilder:validation:Enum={NewPrimary, OldPrimary, Secondaries}
@@ -568,6 +619,51 @@ const (
 	VolumeTypeLog  VolumeType = "log"
 )
 
+// ComponentAffinityType defines the type of the topology constraint between components.
+// +enum
+// +kubebuilder:validation:Enum={CoLocate,Spread}
+type ComponentAffinityType string
+
+const (
+	CoLocateComponentAffinity ComponentAffinityType = "CoLocate"
+	SpreadComponentAffinity   ComponentAffinityType = "Spread"
+)
+
+// EvictionPriority defines the rank of the pods of a component to evict under node pressure.
+// +enum
+// +kubebuilder:validation:Enum={High,Medium,Low}
+type EvictionPriority string
+
+const (
+	HighEvictionPriority   EvictionPriority = "High"
+	MediumEvictionPriority EvictionPriority = "Medium"
+	LowEvictionPriority    EvictionPriority = "Low"
+)
+
+// MonitorResourceKind defines the kind of the prometheus-operator resource to scrape the metrics of a component.
+// +enum
+// +kubebuilder:validation:Enum={ServiceMonitor,PodMonitor}
+type MonitorResourceKind string
+
+const (
+	// ServiceMonitorKind scrapes the pods through the headless service of the component.
+	ServiceMonitorKind MonitorResourceKind = "ServiceMonitor"
+	// PodMonitorKind scrapes the pods directly, for the exporters exposing the metrics per pod.
+	PodMonitorKind MonitorResourceKind = "PodMonitor"
+)
+
+// VolumeClaimRetentionPolicyType defines what happens to the PVCs whose volume claim templates are removed from the component.
+// +enum
+// +kubebuilder:validation:Enum={Retain,Delete}
+type VolumeClaimRetentionPolicyType string
+
+const (
+	// RetainVolumeClaimRetentionPolicy keeps the PVCs, the data can be recovered by adding the templates back.
+	RetainVolumeClaimRetentionPolicy VolumeClaimRetentionPolicyType = "Retain"
+	// DeleteVolumeClaimRetentionPolicy deletes the PVCs along with the data.
+	DeleteVolumeClaimRetentionPolicy VolumeClaimRetentionPolicyType = "Delete"
+)
+
 // BaseBackupType the base backup type, keep synchronized with the BaseBackupType of the data protection API.
 // +enum
 // +kubebuilder:validation:Enum={full,snapshot}
@@ -62,6 +62,26 @@ func (in *Affinity) DeepCopy() *Affinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendDiscovery) DeepCopyInto(out *BackendDiscovery) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendDiscovery.
+func (in *BackendDiscovery) DeepCopy() *BackendDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupPolicy) DeepCopyInto(out *BackupPolicy) {
 	*out = *in
@@ -408,7 +428,16 @@ func (in *ClusterComponentDefinition) DeepCopyInto(out *ClusterComponentDefiniti
 	if in.VolumeTypes != nil {
 		in, out := &in.VolumeTypes, &out.VolumeTypes
 		*out = make([]VolumeTypeSpec, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeSubPaths != nil {
+		in, out := &in.VolumeSubPaths, &out.VolumeSubPaths
+		*out = make([]VolumeSubPathMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.CustomLabelSpecs != nil {
 		in, out := &in.CustomLabelSpecs, &out.CustomLabelSpecs
@@ -441,6 +470,28 @@ func (in *ClusterComponentDefinition) DeepCopyInto(out *ClusterComponentDefiniti
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SharedSecret != nil {
+		in, out := &in.SharedSecret, &out.SharedSecret
+		*out = new(SharedSecretMount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackendDiscovery != nil {
+		in, out := &in.BackendDiscovery, &out.BackendDiscovery
+		*out = new(BackendDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vars != nil {
+		in, out := &in.Vars, &out.Vars
+		*out = make([]ComponentVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreStop != nil {
+		in, out := &in.PreStop, &out.PreStop
+		*out = new(PreStopHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentDefinition.
@@ -488,6 +539,13 @@ func (in *ClusterComponentSpec) DeepCopyInto(out *ClusterComponentSpec) {
 		*out = make([]ServiceRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.MonitorRelabelings != nil {
+		in, out := &in.MonitorRelabelings, &out.MonitorRelabelings
+		*out = make([]MonitorRelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.EnabledLogs != nil {
 		in, out := &in.EnabledLogs, &out.EnabledLogs
 		*out = make([]string, len(*in))
@@ -513,6 +571,18 @@ func (in *ClusterComponentSpec) DeepCopyInto(out *ClusterComponentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TmpfsVolumes != nil {
+		in, out := &in.TmpfsVolumes, &out.TmpfsVolumes
+		*out = make([]ClusterComponentTmpfsVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitScriptContainers != nil {
+		in, out := &in.InitScriptContainers, &out.InitScriptContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]ClusterComponentService, len(*in))
@@ -530,6 +600,50 @@ func (in *ClusterComponentSpec) DeepCopyInto(out *ClusterComponentSpec) {
 		*out = new(Issuer)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EvictionPolicy != nil {
+		in, out := &in.EvictionPolicy, &out.EvictionPolicy
+		*out = new(ComponentEvictionPolicy)
+		**out = **in
+	}
+	if in.SchedulingPolicy != nil {
+		in, out := &in.SchedulingPolicy, &out.SchedulingPolicy
+		*out = new(SchedulingPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvictionProtection != nil {
+		in, out := &in.EvictionProtection, &out.EvictionProtection
+		*out = new(EvictionProtection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialRotation != nil {
+		in, out := &in.CredentialRotation, &out.CredentialRotation
+		*out = new(CredentialRotation)
+		**out = **in
+	}
+	if in.DNSSearchDomains != nil {
+		in, out := &in.DNSSearchDomains, &out.DNSSearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadAnnotations != nil {
+		in, out := &in.WorkloadAnnotations, &out.WorkloadAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProbeOverrides != nil {
+		in, out := &in.ProbeOverrides, &out.ProbeOverrides
+		*out = make([]ContainerProbeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentSpec.
@@ -561,6 +675,18 @@ func (in *ClusterComponentStatus) DeepCopyInto(out *ClusterComponentStatus) {
 		in, out := &in.PodsReadyTime, &out.PodsReadyTime
 		*out = (*in).DeepCopy()
 	}
+	if in.PodNodes != nil {
+		in, out := &in.PodNodes, &out.PodNodes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SharedNodes != nil {
+		in, out := &in.SharedNodes, &out.SharedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ConsensusSetStatus != nil {
 		in, out := &in.ConsensusSetStatus, &out.ConsensusSetStatus
 		*out = new(ConsensusSetStatus)
@@ -576,6 +702,43 @@ func (in *ClusterComponentStatus) DeepCopyInto(out *ClusterComponentStatus) {
 		*out = make([]workloadsv1alpha1.MemberStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.SystemAccounts != nil {
+		in, out := &in.SystemAccounts, &out.SystemAccounts
+		*out = make([]SystemAccountStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigVersions != nil {
+		in, out := &in.ConfigVersions, &out.ConfigVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConfigSynced != nil {
+		in, out := &in.ConfigSynced, &out.ConfigSynced
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OOMKilledContainers != nil {
+		in, out := &in.OOMKilledContainers, &out.OOMKilledContainers
+		*out = make([]OOMKilledContainerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SplitBrainSince != nil {
+		in, out := &in.SplitBrainSince, &out.SplitBrainSince
+		*out = (*in).DeepCopy()
+	}
+	if in.VolumeCapacities != nil {
+		in, out := &in.VolumeCapacities, &out.VolumeCapacities
+		*out = make([]VolumeCapacityStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentStatus.
@@ -588,6 +751,22 @@ func (in *ClusterComponentStatus) DeepCopy() *ClusterComponentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterComponentTmpfsVolume) DeepCopyInto(out *ClusterComponentTmpfsVolume) {
+	*out = *in
+	out.SizeLimit = in.SizeLimit.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentTmpfsVolume.
+func (in *ClusterComponentTmpfsVolume) DeepCopy() *ClusterComponentTmpfsVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterComponentTmpfsVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterComponentVersion) DeepCopyInto(out *ClusterComponentVersion) {
 	*out = *in
@@ -788,6 +967,13 @@ func (in *ClusterDefinitionSpec) DeepCopyInto(out *ClusterDefinitionSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ExternalDependencies != nil {
+		in, out := &in.ExternalDependencies, &out.ExternalDependencies
+		*out = make([]ExternalDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDefinitionSpec.
@@ -936,6 +1122,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ComponentAffinities != nil {
+		in, out := &in.ComponentAffinities, &out.ComponentAffinities
+		*out = make([]ComponentAffinity, len(*in))
+		copy(*out, *in)
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -961,6 +1152,13 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ClusterBackup)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExternalDependencies != nil {
+		in, out := &in.ExternalDependencies, &out.ExternalDependencies
+		*out = make([]ExternalDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -990,6 +1188,13 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PinnedImages != nil {
+		in, out := &in.PinnedImages, &out.PinnedImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -1193,6 +1398,21 @@ func (in *CommandExecutorItem) DeepCopy() *CommandExecutorItem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentAffinity) DeepCopyInto(out *ComponentAffinity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentAffinity.
+func (in *ComponentAffinity) DeepCopy() *ComponentAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentClass) DeepCopyInto(out *ComponentClass) {
 	*out = *in
@@ -1420,6 +1640,21 @@ func (in *ComponentDefRef) DeepCopy() *ComponentDefRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentEvictionPolicy) DeepCopyInto(out *ComponentEvictionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentEvictionPolicy.
+func (in *ComponentEvictionPolicy) DeepCopy() *ComponentEvictionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentEvictionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ComponentMessageMap) DeepCopyInto(out *ComponentMessageMap) {
 	{
@@ -1639,6 +1874,52 @@ func (in *ComponentValueFrom) DeepCopy() *ComponentValueFrom {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentVar) DeepCopyInto(out *ComponentVar) {
+	*out = *in
+	in.ValueFrom.DeepCopyInto(&out.ValueFrom)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentVar.
+func (in *ComponentVar) DeepCopy() *ComponentVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentVarSource) DeepCopyInto(out *ComponentVarSource) {
+	*out = *in
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(ServiceVarSelector)
+		**out = **in
+	}
+	if in.CredentialRef != nil {
+		in, out := &in.CredentialRef, &out.CredentialRef
+		*out = new(CredentialVarSelector)
+		**out = **in
+	}
+	if in.MetaRef != nil {
+		in, out := &in.MetaRef, &out.MetaRef
+		*out = new(MetaVarSelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentVarSource.
+func (in *ComponentVarSource) DeepCopy() *ComponentVarSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentVarSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigConstraint) DeepCopyInto(out *ConfigConstraint) {
 	*out = *in
@@ -2192,6 +2473,51 @@ func (in *ConsensusSetStatus) DeepCopy() *ConsensusSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerProbeOverride) DeepCopyInto(out *ContainerProbeOverride) {
+	*out = *in
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeTimingOverride)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ProbeTimingOverride)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(ProbeTimingOverride)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerProbeOverride.
+func (in *ContainerProbeOverride) DeepCopy() *ContainerProbeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerProbeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotation) DeepCopyInto(out *CredentialRotation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotation.
+func (in *CredentialRotation) DeepCopy() *CredentialRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CredentialVar) DeepCopyInto(out *CredentialVar) {
 	*out = *in
@@ -2212,6 +2538,21 @@ func (in *CredentialVar) DeepCopy() *CredentialVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialVarSelector) DeepCopyInto(out *CredentialVarSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialVarSelector.
+func (in *CredentialVarSelector) DeepCopy() *CredentialVarSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialVarSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomLabelSpec) DeepCopyInto(out *CustomLabelSpec) {
 	*out = *in
@@ -2280,6 +2621,33 @@ func (in *DownwardAPIOption) DeepCopy() *DownwardAPIOption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvictionProtection) DeepCopyInto(out *EvictionProtection) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvictionProtection.
+func (in *EvictionProtection) DeepCopy() *EvictionProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(EvictionProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExporterConfig) DeepCopyInto(out *ExporterConfig) {
 	*out = *in
@@ -2320,33 +2688,88 @@ func (in *Expose) DeepCopy() *Expose {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FormatterConfig) DeepCopyInto(out *FormatterConfig) {
+func (in *ExternalDependency) DeepCopyInto(out *ExternalDependency) {
 	*out = *in
-	in.FormatterOptions.DeepCopyInto(&out.FormatterOptions)
+	if in.TCPSocket != nil {
+		in, out := &in.TCPSocket, &out.TCPSocket
+		*out = new(ExternalTCPSocketProbe)
+		**out = **in
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(ExternalHTTPGetProbe)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FormatterConfig.
-func (in *FormatterConfig) DeepCopy() *FormatterConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDependency.
+func (in *ExternalDependency) DeepCopy() *ExternalDependency {
 	if in == nil {
 		return nil
 	}
-	out := new(FormatterConfig)
+	out := new(ExternalDependency)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FormatterOptions) DeepCopyInto(out *FormatterOptions) {
+func (in *ExternalHTTPGetProbe) DeepCopyInto(out *ExternalHTTPGetProbe) {
 	*out = *in
-	if in.IniConfig != nil {
-		in, out := &in.IniConfig, &out.IniConfig
-		*out = new(IniConfig)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FormatterOptions.
-func (in *FormatterOptions) DeepCopy() *FormatterOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalHTTPGetProbe.
+func (in *ExternalHTTPGetProbe) DeepCopy() *ExternalHTTPGetProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalHTTPGetProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalTCPSocketProbe) DeepCopyInto(out *ExternalTCPSocketProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalTCPSocketProbe.
+func (in *ExternalTCPSocketProbe) DeepCopy() *ExternalTCPSocketProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalTCPSocketProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FormatterConfig) DeepCopyInto(out *FormatterConfig) {
+	*out = *in
+	in.FormatterOptions.DeepCopyInto(&out.FormatterOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FormatterConfig.
+func (in *FormatterConfig) DeepCopy() *FormatterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FormatterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FormatterOptions) DeepCopyInto(out *FormatterOptions) {
+	*out = *in
+	if in.IniConfig != nil {
+		in, out := &in.IniConfig, &out.IniConfig
+		*out = new(IniConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FormatterOptions.
+func (in *FormatterOptions) DeepCopy() *FormatterOptions {
 	if in == nil {
 		return nil
 	}
@@ -2471,6 +2894,11 @@ func (in *LastComponentConfiguration) DeepCopyInto(out *LastComponentConfigurati
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Monitor != nil {
+		in, out := &in.Monitor, &out.Monitor
+		*out = new(bool)
+		**out = **in
+	}
 	if in.TargetResources != nil {
 		in, out := &in.TargetResources, &out.TargetResources
 		*out = make(map[ComponentResourceKey][]string, len(*in))
@@ -2581,6 +3009,37 @@ func (in *MemoryConstraint) DeepCopy() *MemoryConstraint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetaVarSelector) DeepCopyInto(out *MetaVarSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetaVarSelector.
+func (in *MetaVarSelector) DeepCopy() *MetaVarSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(MetaVarSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Monitor) DeepCopyInto(out *Monitor) {
+	*out = *in
+	out.ComponentOps = in.ComponentOps
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitor.
+func (in *Monitor) DeepCopy() *Monitor {
+	if in == nil {
+		return nil
+	}
+	out := new(Monitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitorConfig) DeepCopyInto(out *MonitorConfig) {
 	*out = *in
@@ -2601,6 +3060,48 @@ func (in *MonitorConfig) DeepCopy() *MonitorConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorRelabelConfig) DeepCopyInto(out *MonitorRelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorRelabelConfig.
+func (in *MonitorRelabelConfig) DeepCopy() *MonitorRelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorRelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OOMKilledContainerStatus) DeepCopyInto(out *OOMKilledContainerStatus) {
+	*out = *in
+	if in.KilledTimes != nil {
+		in, out := &in.KilledTimes, &out.KilledTimes
+		*out = make([]metav1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OOMKilledContainerStatus.
+func (in *OOMKilledContainerStatus) DeepCopy() *OOMKilledContainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OOMKilledContainerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsRecorder) DeepCopyInto(out *OpsRecorder) {
 	*out = *in
@@ -2663,6 +3164,21 @@ func (in *OpsRequestBehaviour) DeepCopy() *OpsRequestBehaviour {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsRequestClusterStatus) DeepCopyInto(out *OpsRequestClusterStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsRequestClusterStatus.
+func (in *OpsRequestClusterStatus) DeepCopy() *OpsRequestClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsRequestClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsRequestComponentStatus) DeepCopyInto(out *OpsRequestComponentStatus) {
 	*out = *in
@@ -2721,6 +3237,16 @@ func (in *OpsRequestList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsRequestSpec) DeepCopyInto(out *OpsRequestSpec) {
 	*out = *in
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxConcurrent != nil {
+		in, out := &in.MaxConcurrent, &out.MaxConcurrent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Upgrade != nil {
 		in, out := &in.Upgrade, &out.Upgrade
 		*out = new(Upgrade)
@@ -2787,6 +3313,16 @@ func (in *OpsRequestSpec) DeepCopyInto(out *OpsRequestSpec) {
 		*out = new(BackupSpec)
 		**out = **in
 	}
+	if in.MonitorList != nil {
+		in, out := &in.MonitorList, &out.MonitorList
+		*out = make([]Monitor, len(*in))
+		copy(*out, *in)
+	}
+	if in.RotateCredential != nil {
+		in, out := &in.RotateCredential, &out.RotateCredential
+		*out = new(RotateCredential)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsRequestSpec.
@@ -2818,6 +3354,16 @@ func (in *OpsRequestStatus) DeepCopyInto(out *OpsRequestStatus) {
 		*out = new(ReconfiguringStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CleanedResources != nil {
+		in, out := &in.CleanedResources, &out.CleanedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]OpsRequestClusterStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -2961,6 +3507,66 @@ func (in *PointInTimeRefSpec) DeepCopy() *PointInTimeRefSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreStopHook) DeepCopyInto(out *PreStopHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreStopHook.
+func (in *PreStopHook) DeepCopy() *PreStopHook {
+	if in == nil {
+		return nil
+	}
+	out := new(PreStopHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTimingOverride) DeepCopyInto(out *ProbeTimingOverride) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTimingOverride.
+func (in *ProbeTimingOverride) DeepCopy() *ProbeTimingOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeTimingOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProgressStatusDetail) DeepCopyInto(out *ProgressStatusDetail) {
 	*out = *in
@@ -3273,6 +3879,22 @@ func (in *RestoreFromSpec) DeepCopy() *RestoreFromSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RotateCredential) DeepCopyInto(out *RotateCredential) {
+	*out = *in
+	out.ComponentOps = in.ComponentOps
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotateCredential.
+func (in *RotateCredential) DeepCopy() *RotateCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(RotateCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchedulePolicy) DeepCopyInto(out *SchedulePolicy) {
 	*out = *in
@@ -3293,6 +3915,28 @@ func (in *SchedulePolicy) DeepCopy() *SchedulePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingPolicy) DeepCopyInto(out *SchedulingPolicy) {
+	*out = *in
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingPolicy.
+func (in *SchedulingPolicy) DeepCopy() *SchedulingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScriptConfig) DeepCopyInto(out *ScriptConfig) {
 	*out = *in
@@ -3578,6 +4222,21 @@ func (in *ServiceRefDeclarationSpec) DeepCopy() *ServiceRefDeclarationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceRole) DeepCopyInto(out *ServiceRole) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceRole.
+func (in *ServiceRole) DeepCopy() *ServiceRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	*out = *in
@@ -3588,6 +4247,11 @@ func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceRoles != nil {
+		in, out := &in.ServiceRoles, &out.ServiceRoles
+		*out = make([]ServiceRole, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpec.
@@ -3600,6 +4264,41 @@ func (in *ServiceSpec) DeepCopy() *ServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceVarSelector) DeepCopyInto(out *ServiceVarSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceVarSelector.
+func (in *ServiceVarSelector) DeepCopy() *ServiceVarSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceVarSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedSecretMount) DeepCopyInto(out *SharedSecretMount) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedSecretMount.
+func (in *SharedSecretMount) DeepCopy() *SharedSecretMount {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedSecretMount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ShellTrigger) DeepCopyInto(out *ShellTrigger) {
 	*out = *in
@@ -3836,6 +4535,25 @@ func (in *SystemAccountSpec) DeepCopy() *SystemAccountSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemAccountStatus) DeepCopyInto(out *SystemAccountStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemAccountStatus.
+func (in *SystemAccountStatus) DeepCopy() *SystemAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSSecretRef) DeepCopyInto(out *TLSSecretRef) {
 	*out = *in
@@ -4047,6 +4765,28 @@ func (in *VerticalScaling) DeepCopy() *VerticalScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeCapacityStatus) DeepCopyInto(out *VolumeCapacityStatus) {
+	*out = *in
+	out.Requested = in.Requested.DeepCopy()
+	out.Capacity = in.Capacity.DeepCopy()
+	if in.Utilization != nil {
+		in, out := &in.Utilization, &out.Utilization
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeCapacityStatus.
+func (in *VolumeCapacityStatus) DeepCopy() *VolumeCapacityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeCapacityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeExpansion) DeepCopyInto(out *VolumeExpansion) {
 	*out = *in
@@ -4092,9 +4832,34 @@ func (in *VolumeProtectionSpec) DeepCopy() *VolumeProtectionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSubPathMount) DeepCopyInto(out *VolumeSubPathMount) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSubPathMount.
+func (in *VolumeSubPathMount) DeepCopy() *VolumeSubPathMount {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSubPathMount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeTypeSpec) DeepCopyInto(out *VolumeTypeSpec) {
 	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeTypeSpec.
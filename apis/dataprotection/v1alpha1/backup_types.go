@@ -125,6 +125,11 @@ type BackupStatus struct {
 	// +optional
 	Target *BackupTarget `json:"target,omitempty"`
 
+	// targetPod records the pod which the backup is taken from, and why it's selected
+	// if the role selected by the backup policy is unavailable.
+	// +optional
+	TargetPod *BackupTargetPodStatus `json:"targetPod,omitempty"`
+
 	// backupMethod records the backup method information for this backup.
 	// Refer to BackupMethod for more details.
 	// +optional
@@ -137,6 +142,38 @@ type BackupStatus struct {
 	// volumeSnapshots records the volume snapshot status for the action.
 	// +optional
 	VolumeSnapshots []VolumeSnapshotStatus `json:"volumeSnapshots,omitempty"`
+
+	// encryption records the encryption information of the backup data.
+	// +optional
+	Encryption *BackupEncryptionStatus `json:"encryption,omitempty"`
+}
+
+// BackupTargetPodStatus records the pod which the backup is taken from.
+type BackupTargetPodStatus struct {
+	// name is the name of the target pod.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// role is the role of the target pod when it's selected.
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// fallbackReason is why the target pod isn't of the role selected by the backup policy,
+	// it's empty if the target pod is of the role.
+	// +optional
+	FallbackReason string `json:"fallbackReason,omitempty"`
+}
+
+// BackupEncryptionStatus records the encryption information of the backup data,
+// the encryption key itself is never recorded.
+type BackupEncryptionStatus struct {
+	// algorithm is the algorithm used to encrypt the backup data.
+	// +optional
+	Algorithm EncryptionAlgorithm `json:"algorithm,omitempty"`
+
+	// keyFingerprint is the fingerprint of the key used to encrypt the backup data.
+	// +optional
+	KeyFingerprint string `json:"keyFingerprint,omitempty"`
 }
 
 // BackupTimeRange records the time range of backed up data, for PITR, this is the
@@ -81,8 +81,29 @@ type PodSelector struct {
 	// - Any: select any one pod that match the labelsSelector.
 	// +kubebuilder:default=Any
 	Strategy PodSelectionStrategy `json:"strategy,omitempty"`
+
+	// fallbackPolicy specifies how to select the target pod if no pod of the role selected
+	// by the labelsSelector is available, it only takes effect with the strategy Any.
+	// Valid values are:
+	// - Strict: select the pod of the role only, the backup fails if there is no such pod.
+	// - PreferRole: fall back to any ready pod matching the labelsSelector except the role.
+	// +kubebuilder:default=Strict
+	// +optional
+	FallbackPolicy TargetFallbackPolicy `json:"fallbackPolicy,omitempty"`
 }
 
+// TargetFallbackPolicy specifies how to select the target pod if the role is unavailable.
+// +kubebuilder:validation:Enum=Strict;PreferRole
+type TargetFallbackPolicy string
+
+const (
+	// TargetFallbackPolicyStrict selects the pod of the role only.
+	TargetFallbackPolicyStrict TargetFallbackPolicy = "Strict"
+
+	// TargetFallbackPolicyPreferRole prefers the pod of the role, and falls back to any ready pod.
+	TargetFallbackPolicyPreferRole TargetFallbackPolicy = "PreferRole"
+)
+
 // PodSelectionStrategy specifies the strategy to select when multiple pods are
 // selected for backup target
 // +kubebuilder:validation:Enum=All;Any
@@ -63,6 +63,32 @@ type BackupRepoSpec struct {
 	// A secret that contains the credentials needed by the storage provider.
 	// +optional
 	Credential *corev1.SecretReference `json:"credential,omitempty"`
+
+	// Specifies the client-side encryption of the backup data stored in the repo.
+	// +optional
+	Encryption *BackupRepoEncryption `json:"encryption,omitempty"`
+}
+
+// EncryptionAlgorithm is an enum type that defines the algorithm used to encrypt the backup data.
+type EncryptionAlgorithm string
+
+const (
+	EncryptionAlgorithmAES128CFB EncryptionAlgorithm = "AES-128-CFB"
+	EncryptionAlgorithmAES192CFB EncryptionAlgorithm = "AES-192-CFB"
+	EncryptionAlgorithmAES256CFB EncryptionAlgorithm = "AES-256-CFB"
+)
+
+// BackupRepoEncryption defines the client-side encryption of the backup data.
+type BackupRepoEncryption struct {
+	// algorithm specifies the algorithm used to encrypt the backup data.
+	// +kubebuilder:validation:Enum={AES-128-CFB,AES-192-CFB,AES-256-CFB}
+	// +kubebuilder:validation:Required
+	Algorithm EncryptionAlgorithm `json:"algorithm"`
+
+	// keySecretRef references the secret that contains the encryption key in the "key" field.
+	// Updating the key only affects the backups created afterwards.
+	// +kubebuilder:validation:Required
+	KeySecretRef corev1.SecretReference `json:"keySecretRef"`
 }
 
 // BackupRepoStatus defines the observed state of BackupRepo
@@ -99,6 +125,10 @@ type BackupRepoStatus struct {
 	// isDefault indicates whether this backup repo is the default one.
 	// +optional
 	IsDefault bool `json:"isDefault,omitempty"`
+
+	// encryptionKeyFingerprint is the fingerprint of the current encryption key.
+	// +optional
+	EncryptionKeyFingerprint string `json:"encryptionKeyFingerprint,omitempty"`
 }
 
 // +genclient
@@ -141,3 +171,7 @@ func (repo *BackupRepo) AccessByMount() bool {
 func (repo *BackupRepo) AccessByTool() bool {
 	return repo.Spec.AccessMethod == AccessMethodTool
 }
+
+func (repo *BackupRepo) EncryptionEnabled() bool {
+	return repo.Spec.Encryption != nil
+}
@@ -293,6 +293,21 @@ func (in *BackupDataActionSpec) DeepCopy() *BackupDataActionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEncryptionStatus) DeepCopyInto(out *BackupEncryptionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEncryptionStatus.
+func (in *BackupEncryptionStatus) DeepCopy() *BackupEncryptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEncryptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupList) DeepCopyInto(out *BackupList) {
 	*out = *in
@@ -515,6 +530,22 @@ func (in *BackupRepo) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRepoEncryption) DeepCopyInto(out *BackupRepoEncryption) {
+	*out = *in
+	out.KeySecretRef = in.KeySecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRepoEncryption.
+func (in *BackupRepoEncryption) DeepCopy() *BackupRepoEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRepoEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupRepoList) DeepCopyInto(out *BackupRepoList) {
 	*out = *in
@@ -563,6 +594,11 @@ func (in *BackupRepoSpec) DeepCopyInto(out *BackupRepoSpec) {
 		*out = new(v1.SecretReference)
 		**out = **in
 	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupRepoEncryption)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRepoSpec.
@@ -755,6 +791,11 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(BackupTarget)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TargetPod != nil {
+		in, out := &in.TargetPod, &out.TargetPod
+		*out = new(BackupTargetPodStatus)
+		**out = **in
+	}
 	if in.BackupMethod != nil {
 		in, out := &in.BackupMethod, &out.BackupMethod
 		*out = new(BackupMethod)
@@ -772,6 +813,11 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = make([]VolumeSnapshotStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupEncryptionStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -814,6 +860,21 @@ func (in *BackupTarget) DeepCopy() *BackupTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTargetPodStatus) DeepCopyInto(out *BackupTargetPodStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTargetPodStatus.
+func (in *BackupTargetPodStatus) DeepCopy() *BackupTargetPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTargetPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupTimeRange) DeepCopyInto(out *BackupTimeRange) {
 	*out = *in
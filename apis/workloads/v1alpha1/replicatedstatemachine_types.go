@@ -116,6 +116,16 @@ type ReplicatedStateMachineSpec struct {
 	// Credential used to connect to DB engine
 	// +optional
 	Credential *Credential `json:"credential,omitempty"`
+
+	// Ordinals controls the numbering of replica indices, it's passed through to the underlying StatefulSet.
+	// The replicas will get the ordinals in the range [ordinals.start, ordinals.start+replicas).
+	// +optional
+	Ordinals *appsv1.StatefulSetOrdinals `json:"ordinals,omitempty"`
+
+	// PodNamePrefix is the name of the underlying StatefulSet, from which the pod names are derived.
+	// It defaults to the name of the ReplicatedStateMachine.
+	// +optional
+	PodNamePrefix string `json:"podNamePrefix,omitempty"`
 }
 
 // ReplicatedStateMachineStatus defines the observed state of ReplicatedStateMachine
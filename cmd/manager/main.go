@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -33,6 +34,8 @@ import (
 	snapshotv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v3/apis/volumesnapshot/v1beta1"
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	"github.com/spf13/pflag"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -57,7 +60,9 @@ import (
 	storagecontrollers "github.com/apecloud/kubeblocks/controllers/storage"
 	workloadscontrollers "github.com/apecloud/kubeblocks/controllers/workloads"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/tracing"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/featuregate"
 	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
 
@@ -108,6 +113,12 @@ func init() {
 	viper.SetDefault(constant.FeatureGateReplicatedStateMachine, true)
 	viper.SetDefault(constant.KBDataScriptClientsImage, "apecloud/kubeblocks-datascript:latest")
 	viper.SetDefault(constant.KubernetesClusterDomainEnv, constant.DefaultDNSDomain)
+	viper.SetDefault(constant.CfgKeyDataPlaneNodeTerminationTaintKeys,
+		"aws-node-termination-handler/spot-itn,cloud.google.com/impending-node-termination")
+	viper.SetDefault(constant.CfgKeyVolumeUtilizationWarningThreshold, 80)
+	viper.SetDefault(constant.CfgKeyVolumeUtilizationCriticalThreshold, 90)
+	viper.SetDefault(constant.CfgKeyVolumeAutoExpansionCooldown, "6h")
+	viper.SetDefault(constant.CfgKeyConnCredentialRecoveryPolicy, "Recover")
 }
 
 type flagName string
@@ -117,6 +128,8 @@ const (
 	metricsAddrFlagKey   flagName = "metrics-bind-address"
 	leaderElectFlagKey   flagName = "leader-elect"
 	leaderElectIDFlagKey flagName = "leader-elect-id"
+	featureGatesFlagKey  flagName = "feature-gates"
+	logLevelsFlagKey     flagName = "log-levels"
 
 	// switch flags key for API groups
 	appsFlagKey       flagName = "apps"
@@ -133,6 +146,46 @@ func (r flagName) viperName() string {
 	return strings.ReplaceAll(r.String(), "-", "_")
 }
 
+// setupFeatureGates sets the feature gates by the legacy settings of the features first,
+// which are overridden by the flag --feature-gates or the env FEATURE_GATES.
+func setupFeatureGates() error {
+	legacySettings := map[featuregate.Feature]string{
+		featuregate.ResourceQuota:                 constant.EnableResourceQuota,
+		featuregate.RecoverVolumeExpansionFailure: constant.CfgRecoverVolumeExpansionFailure,
+	}
+	features := map[string]bool{}
+	for f, key := range legacySettings {
+		if viper.IsSet(key) {
+			features[string(f)] = viper.GetBool(key)
+		}
+	}
+	if err := featuregate.DefaultMutableFeatureGate.SetFromMap(features); err != nil {
+		return err
+	}
+	return featuregate.DefaultMutableFeatureGate.Set(viper.GetString(featureGatesFlagKey.viperName()))
+}
+
+// setupLogLevels overrides the log verbosity of the controllers and the loggers by the flag --log-levels.
+func setupLogLevels(opts *zap.Options) error {
+	overrides, err := intctrlutil.ParseLogLevels(viper.GetString(logLevelsFlagKey.viperName()))
+	if err != nil || len(overrides) == 0 {
+		return err
+	}
+	baseLevel := zapcore.InfoLevel
+	if opts.Development {
+		baseLevel = zapcore.DebugLevel
+	}
+	if opts.Level != nil {
+		baseLevel = zapcore.LevelOf(opts.Level)
+	}
+	// the core is built with the lowest level, and the entries are filtered by the overridden levels.
+	opts.Level = uberzap.NewAtomicLevelAt(intctrlutil.MinLogLevel(baseLevel, overrides))
+	opts.ZapOpts = append(opts.ZapOpts, uberzap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return intctrlutil.NewLevelOverrideCore(core, baseLevel, overrides)
+	}))
+	return nil
+}
+
 func validateRequiredToParseConfigs() error {
 	validateTolerations := func(val string) error {
 		if val == "" {
@@ -191,6 +244,15 @@ func main() {
 		"The leader election ID prefix for controller manager. "+
 			"This ID must be unique to controller manager.")
 
+	flag.String(featureGatesFlagKey.String(), "",
+		"A set of key=value pairs that describe the feature gates of the experimental behaviors. Options are:\n"+
+			strings.Join(featuregate.DefaultFeatureGate.KnownFeatures(), "\n"))
+
+	flag.String(logLevelsFlagKey.String(), "",
+		"A set of name=level pairs that override the log verbosity of the controllers or the loggers, "+
+			"e.g. cluster=debug,opsrequest=2. The name is the name of a controller or a logger, the level is one of "+
+			"'debug', 'info', 'error', or any integer value > 0 which corresponds to custom debug levels of increasing verbosity.")
+
 	flag.Bool(appsFlagKey.String(), true,
 		"Enable the apps controller manager.")
 	flag.Bool(extensionsFlagKey.String(), true,
@@ -223,7 +285,12 @@ func main() {
 	// NOTES:
 	// zap is "Blazing fast, structured, leveled logging in Go.", DON'T event try
 	// to refactor this logging lib to anything else. Check FAQ - https://github.com/uber-go/zap/blob/master/FAQ.md
+	logLevelsErr := setupLogLevels(&opts)
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if logLevelsErr != nil {
+		setupLog.Error(logLevelsErr, "unable to set log levels")
+		os.Exit(1)
+	}
 
 	// Find and read the config file
 	if err := viper.ReadInConfig(); err != nil { // Handle errors reading the config file
@@ -232,9 +299,18 @@ func main() {
 	setupLog.Info(fmt.Sprintf("config file: %s", viper.GetViper().ConfigFileUsed()))
 	viper.OnConfigChange(func(e fsnotify.Event) {
 		setupLog.Info(fmt.Sprintf("config file changed: %s", e.Name))
+		// the gated behaviors query the feature gates on each call, so the changes take effect without restarts.
+		if err := setupFeatureGates(); err != nil {
+			setupLog.Error(err, "unable to set feature gates")
+		}
 	})
 	viper.WatchConfig()
 
+	if err := setupFeatureGates(); err != nil {
+		setupLog.Error(err, "unable to set feature gates")
+		os.Exit(1)
+	}
+
 	metricsAddr = viper.GetString(metricsAddrFlagKey.viperName())
 	probeAddr = viper.GetString(probeAddrFlagKey.viperName())
 	enableLeaderElection = viper.GetBool(leaderElectFlagKey.viperName())
@@ -289,6 +365,15 @@ func main() {
 			os.Exit(1)
 		}
 
+		if err = (&appscontrollers.ClusterAdoptionReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("cluster-adoption-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterAdoption")
+			os.Exit(1)
+		}
+
 		if err = (&appscontrollers.ClusterDefinitionReconciler{
 			Client:   mgr.GetClient(),
 			Scheme:   mgr.GetScheme(),
@@ -316,6 +401,14 @@ func main() {
 			os.Exit(1)
 		}
 
+		if err = (&appscontrollers.HScaleOrphanCleaner{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("hscale-orphan-cleaner"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create runnable", "runnable", "HScaleOrphanCleaner")
+			os.Exit(1)
+		}
+
 		if err = (&configuration.ConfigConstraintReconciler{
 			Client:   mgr.GetClient(),
 			Scheme:   mgr.GetScheme(),
@@ -480,8 +573,20 @@ func main() {
 	}
 	viper.SetDefault(constant.CfgKeyServerInfo, *ver)
 
+	ctx := ctrl.SetupSignalHandler()
+	shutdownTracerProvider, err := tracing.SetupTracerProvider(ctx, viper.GetString(constant.CfgKeyTracingOTLPEndpoint))
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracer provider")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracerProvider(context.Background()); err != nil {
+			setupLog.Error(err, "unable to shut down tracer provider")
+		}
+	}()
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+// ClusterAdoptionReconciler adopts the orphaned child objects back under their clusters.
+//
+// The child objects may be orphaned after the clusters are restored by etcd restores or velero migrations,
+// as the restored clusters get new UIDs while the ownerReferences of the restored objects still point at
+// the old ones, which breaks the garbage collection and the reconciliation of the clusters.
+type ClusterAdoptionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// adoptableObjectLists are the lists of the child object kinds owned by the clusters to adopt, the statefulSets
+// are owned by the RSMs and are left to the RSM controller.
+var adoptableObjectLists = map[string]func() client.ObjectList{
+	constant.RSMKind:                   func() client.ObjectList { return &workloads.ReplicatedStateMachineList{} },
+	constant.ServiceKind:               func() client.ObjectList { return &corev1.ServiceList{} },
+	constant.SecretKind:                func() client.ObjectList { return &corev1.SecretList{} },
+	constant.ConfigMapKind:             func() client.ObjectList { return &corev1.ConfigMapList{} },
+	constant.PersistentVolumeClaimKind: func() client.ObjectList { return &corev1.PersistentVolumeClaimList{} },
+}
+
+var clusterAdoptionsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kubeblocks_cluster_workload_adoptions_total",
+		Help: "The number of the orphaned child objects adopted back under their clusters.",
+	},
+	[]string{"kind"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(clusterAdoptionsCounter)
+}
+
+// Reconcile finds the child objects of the cluster whose owner UIDs don't match the live cluster, and fixes
+// their ownerReferences. The objects are only looked up in the namespace of the cluster, and the ones owned
+// by a cluster with a different name are never adopted.
+func (r *ClusterAdoptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, intctrlutil.LogFieldCluster, req.NamespacedName)
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Req:      req,
+		Log:      logger,
+		Recorder: r.Recorder,
+	}
+
+	cluster := &appsv1alpha1.Cluster{}
+	if err := r.Client.Get(reqCtx.Ctx, reqCtx.Req.NamespacedName, cluster); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return intctrlutil.Reconciled()
+	}
+
+	ml := client.MatchingLabels{
+		constant.AppInstanceLabelKey:  cluster.Name,
+		constant.AppManagedByLabelKey: constant.AppName,
+	}
+	for kind, newList := range adoptableObjectLists {
+		objList := newList()
+		if err := r.Client.List(reqCtx.Ctx, objList, client.InNamespace(cluster.Namespace), ml); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		objs, err := meta.ExtractList(objList)
+		if err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		for _, o := range objs {
+			if err := r.adopt(reqCtx, cluster, kind, o.(client.Object)); err != nil {
+				return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+			}
+		}
+	}
+	return intctrlutil.Reconciled()
+}
+
+// adopt fixes the stale cluster ownerReference of the object. The patch of the object triggers the
+// reconciliation of the cluster as well, as the cluster controller watches the objects it owns.
+func (r *ClusterAdoptionReconciler) adopt(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster, kind string, obj client.Object) error {
+	if obj.GetNamespace() != cluster.Namespace {
+		return nil
+	}
+	refs := obj.GetOwnerReferences()
+	index := -1
+	for i, ref := range refs {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != appsv1alpha1.GroupVersion.Group || ref.Kind != appsv1alpha1.ClusterKind {
+			continue
+		}
+		if ref.Name != cluster.Name || ref.UID == cluster.UID {
+			return nil
+		}
+		index = i
+		break
+	}
+	if index < 0 {
+		return nil
+	}
+
+	reqCtx.Log.Info("adopt orphaned object", "kind", kind, "name", obj.GetName(),
+		"staleUID", refs[index].UID, "uid", cluster.UID)
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	refs[index].APIVersion = appsv1alpha1.GroupVersion.String()
+	refs[index].UID = cluster.UID
+	obj.SetOwnerReferences(refs)
+	if err := r.Client.Patch(reqCtx.Ctx, obj, patch); err != nil {
+		return err
+	}
+	clusterAdoptionsCounter.WithLabelValues(kind).Inc()
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, constant.ReasonAdoptedWorkload,
+		"adopted the orphaned %s %s", kind, obj.GetName())
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+//
+// The restored clusters are adopted when they are created, the child objects are not watched, the lists of them
+// are served by the caches shared with the cluster controller.
+func (r *ClusterAdoptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cluster-adoption").
+		For(&appsv1alpha1.Cluster{}).
+		Complete(r)
+}
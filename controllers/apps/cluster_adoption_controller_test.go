@@ -0,0 +1,178 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("cluster adoption controller", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		compName           = "mysql"
+		staleUID           = types.UID("stale-cluster-uid")
+	)
+
+	var clusterName string
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		testapps.ClearClusterResourcesWithRemoveFinalizerOption(&testCtx)
+
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResources(&testCtx, generics.RSMSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.ServiceSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.ConfigMapSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PersistentVolumeClaimSignature, true, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+		clusterName = "adoption-" + testCtx.GetRandomStr()
+	})
+
+	AfterEach(cleanEnv)
+
+	staleOwner := func(name string) *appsv1alpha1.Cluster {
+		return &appsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name, UID: staleUID}}
+	}
+
+	// restoreCluster creates the cluster after its child objects, as the restored clusters do.
+	restoreCluster := func() *appsv1alpha1.Cluster {
+		return testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDefName, clusterVersionName).
+			Create(&testCtx).GetObject()
+	}
+
+	childLabels := func() map[string]string {
+		return map[string]string{
+			constant.AppInstanceLabelKey:    clusterName,
+			constant.KBAppComponentLabelKey: compName,
+			constant.AppManagedByLabelKey:   constant.AppName,
+		}
+	}
+
+	newChildMeta := func(name string, owner *appsv1alpha1.Cluster) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace: testCtx.DefaultNamespace,
+			Name:      name,
+			Labels:    childLabels(),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: appsv1alpha1.GroupVersion.String(),
+					Kind:       appsv1alpha1.ClusterKind,
+					Name:       owner.Name,
+					UID:        owner.UID,
+				},
+			},
+		}
+	}
+
+	Context("adopt orphaned objects", func() {
+		It("should fix the stale owner UIDs of the child objects", func() {
+			adoptions := testutil.ToFloat64(clusterAdoptionsCounter.WithLabelValues(constant.RSMKind))
+			name := clusterName + "-" + compName
+
+			By("creating the child objects owned by the stale cluster")
+			rsm := testapps.NewRSMFactory(testCtx.DefaultNamespace, name, clusterName, compName).
+				SetOwnerReferences(appsv1alpha1.GroupVersion.String(), appsv1alpha1.ClusterKind, staleOwner(clusterName)).
+				AddContainer(corev1.Container{Name: compName, Image: "mysql"}).
+				Create(&testCtx).GetObject()
+			svc := &corev1.Service{
+				ObjectMeta: newChildMeta(name, staleOwner(clusterName)),
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Name: "mysql", Port: 3306}},
+				},
+			}
+			secret := &corev1.Secret{ObjectMeta: newChildMeta(clusterName+"-conn-credential", staleOwner(clusterName))}
+			cm := &corev1.ConfigMap{ObjectMeta: newChildMeta(name+"-env", staleOwner(clusterName))}
+			pvc := testapps.NewPersistentVolumeClaimFactory(testCtx.DefaultNamespace, "data-"+name+"-0", clusterName, compName, "data").
+				SetOwnerReferences(appsv1alpha1.GroupVersion.String(), appsv1alpha1.ClusterKind, staleOwner(clusterName)).
+				SetStorage("1Gi").
+				Create(&testCtx).GetObject()
+			for _, obj := range []client.Object{svc, secret, cm} {
+				Expect(testCtx.CreateObj(testCtx.Ctx, obj)).Should(Succeed())
+			}
+
+			By("restoring the cluster with a new UID")
+			cluster := restoreCluster()
+
+			By("checking the child objects adopted by the live cluster")
+			checkAdopted := func(g Gomega, obj client.Object) {
+				g.Expect(obj.GetOwnerReferences()).Should(HaveLen(1))
+				g.Expect(obj.GetOwnerReferences()[0].UID).Should(Equal(cluster.UID))
+			}
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(rsm), func(g Gomega, obj *workloads.ReplicatedStateMachine) {
+				checkAdopted(g, obj)
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(svc), func(g Gomega, obj *corev1.Service) {
+				checkAdopted(g, obj)
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(secret), func(g Gomega, obj *corev1.Secret) {
+				checkAdopted(g, obj)
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(cm), func(g Gomega, obj *corev1.ConfigMap) {
+				checkAdopted(g, obj)
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(pvc), func(g Gomega, obj *corev1.PersistentVolumeClaim) {
+				checkAdopted(g, obj)
+			})).Should(Succeed())
+			Eventually(func() float64 {
+				return testutil.ToFloat64(clusterAdoptionsCounter.WithLabelValues(constant.RSMKind))
+			}).Should(Equal(adoptions + 1))
+		})
+
+		It("should not adopt the child objects owned by a cluster with a different name", func() {
+			By("creating a RSM owned by another cluster")
+			rsm := testapps.NewRSMFactory(testCtx.DefaultNamespace, clusterName+"-"+compName, clusterName, compName).
+				SetOwnerReferences(appsv1alpha1.GroupVersion.String(), appsv1alpha1.ClusterKind, staleOwner(clusterName+"-other")).
+				AddContainer(corev1.Container{Name: compName, Image: "mysql"}).
+				Create(&testCtx).GetObject()
+
+			By("restoring the cluster")
+			restoreCluster()
+
+			Consistently(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(rsm), func(g Gomega, obj *workloads.ReplicatedStateMachine) {
+				g.Expect(obj.OwnerReferences).Should(HaveLen(1))
+				g.Expect(obj.OwnerReferences[0].UID).Should(Equal(staleUID))
+			})).Should(Succeed())
+		})
+	})
+})
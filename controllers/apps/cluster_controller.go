@@ -23,6 +23,7 @@ import (
 	"context"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -34,13 +35,14 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/tracing"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
@@ -67,12 +69,16 @@ import (
 // +kubebuilder:rbac:groups=core,resources=resourcequotas/status,verbs=get
 // +kubebuilder:rbac:groups=core,resources=resourcequotas/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims/status,verbs=get
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims/finalizers,verbs=update
 
 // +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;update;patch
 
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=opsrequests,verbs=get;list;create
+
 // +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=replicasets/status,verbs=get
 // +kubebuilder:rbac:groups=apps,resources=replicasets/finalizers,verbs=update
@@ -96,6 +102,10 @@ import (
 
 // read only + watch access
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;list;watch;create;update;patch;delete
 
 // dataprotection get list and delete
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuppolicies,verbs=get;list;create;update;patch;delete;deletecollection
@@ -118,6 +128,14 @@ type ClusterReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// VolumeStatsProvider provides the utilization of the volumes, the kubelet volume stats metrics are collected
+	// from the Prometheus configured by VOLUME_STATS_PROMETHEUS_URL if it's nil.
+	VolumeStatsProvider component.VolumeStatsProvider
+	// ExternalDependencyProber probes the external dependencies of the clusters while none of their pods is available
+	// to probe them by lorry, they are probed from the controller if it's nil.
+	ExternalDependencyProber component.ExternalDependencyProber
+	// externalDependencyProbeCache caches the successful probes of the external dependencies.
+	externalDependencyProbeCache *externalDependencyProbeCache
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -125,11 +143,18 @@ type ClusterReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
-func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartSpan(ctx, "Reconcile Cluster",
+		attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, intctrlutil.LogFieldCluster, req.NamespacedName)
 	reqCtx := intctrlutil.RequestCtx{
 		Ctx:      ctx,
 		Req:      req,
-		Log:      log.FromContext(ctx).WithValues("cluster", req.NamespacedName),
+		Log:      logger,
 		Recorder: r.Recorder,
 	}
 
@@ -177,12 +202,40 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			&ValidateAndLoadRefResourcesTransformer{},
 			// validate config
 			&ValidateEnableLogsTransformer{},
+			// validate the vars of components
+			&ValidateComponentVarsTransformer{},
+			// validate the objects of components are not owned by other clusters
+			&ValidateComponentNamesTransformer{},
+			// validate the volumes mounted by the containers of components are claimed by the volume claim templates
+			&ValidateVolumeMountsTransformer{},
+			// warn the consensus components whose voting members can't form an odd-sized quorum
+			&ValidateComponentReplicasTransformer{},
 			// create cluster connection credential secret object
-			&ClusterCredentialTransformer{},
+			&ClusterCredentialTransformer{Client: r.Client},
+			// record the spec changes of the cluster
+			&ClusterSpecHistoryTransformer{},
+			// pin the images of components by digest before rendering them
+			&ClusterImageDigestTransformer{},
 			// handle restore before ComponentTransformer
 			&RestoreTransformer{Client: r.Client},
 			// create all components objects
 			&ComponentTransformer{Client: r.Client},
+			// generate the cluster-level shared secret for the components declaring it, before creating their workloads
+			&ClusterSharedSecretTransformer{},
+			// create the workloads after the workloads of the components referred by their vars
+			&ComponentVarsTransformer{},
+			// render the members of the backend components into the components declaring backendDiscovery
+			&ComponentBackendDiscoveryTransformer{},
+			// restart pods once their mounted configmaps or secrets change
+			&ComponentConfigChecksumTransformer{},
+			// rotate the password of the connection credential on the schedule of the components
+			&ComponentCredentialRotationTransformer{},
+			// create a service for each role of the components enabling roleServices
+			&ComponentRoleServiceTransformer{},
+			// reconcile the ServiceMonitor or PodMonitor of the components requesting a monitor resource
+			&ComponentMonitorTransformer{},
+			// reconcile the ResourceQuota sized to the components if requested
+			&ClusterResourceQuotaTransformer{},
 			// transform backupPolicyTemplate to backuppolicy.dataprotection.kubeblocks.io
 			// and backupschedule.dataprotection.kubeblocks.io
 			&BackupPolicyTplTransformer{},
@@ -192,6 +245,12 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			&OwnershipTransformer{},
 			// make all workload objects depending on credential secret
 			&SecretTransformer{},
+			// detect the pods which are unschedulable due to the affinity constraints
+			&ClusterSchedulingTransformer{},
+			// warn the volumes with high utilization
+			&ClusterVolumeUtilizationTransformer{Provider: r.VolumeStatsProvider},
+			// hold the cluster from Running until its external dependencies are reachable
+			&ClusterExternalDependencyTransformer{Prober: r.ExternalDependencyProber, cache: r.externalDependencyProbeCache},
 			// update cluster status
 			&ClusterStatusTransformer{},
 			// always safe to put your transformer below
@@ -216,6 +275,24 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if retryDurationMS != 0 {
 		requeueDuration = time.Millisecond * time.Duration(retryDurationMS)
 	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &appsv1alpha1.Cluster{},
+		restartOnChangeIndexKey, indexRestartOnChangeObjects); err != nil {
+		return err
+	}
+	if address := viper.GetString(constant.CfgKeyVolumeStatsPrometheusURL); r.VolumeStatsProvider == nil && address != "" {
+		provider, err := component.NewPrometheusVolumeStatsProvider(address, time.Minute)
+		if err != nil {
+			return err
+		}
+		if err = mgr.Add(provider); err != nil {
+			return err
+		}
+		r.VolumeStatsProvider = provider
+	}
+	if r.ExternalDependencyProber == nil {
+		r.ExternalDependencyProber = component.NewNetExternalDependencyProber()
+	}
+	r.externalDependencyProbeCache = newExternalDependencyProbeCache()
 	// TODO: add filter predicate for core API objects
 	b := ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1alpha1.Cluster{}).
@@ -226,13 +303,16 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&corev1.ResourceQuota{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&dpv1alpha1.BackupPolicy{}).
 		Owns(&dpv1alpha1.BackupSchedule{}).
 		Owns(&dpv1alpha1.Backup{}).
 		Owns(&dpv1alpha1.Restore{}).
 		Owns(&batchv1.Job{}).
-		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.filterClusterResources))
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.filterClusterResources)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.filterRestartOnChangeClusters(restartOnChangeSecretKind))).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.filterRestartOnChangeClusters(restartOnChangeConfigMapKind)))
 
 	if viper.GetBool(constant.EnableRBACManager) {
 		b.Owns(&rbacv1.ClusterRoleBinding{}).
@@ -264,3 +344,42 @@ func (r *ClusterReconciler) filterClusterResources(ctx context.Context, obj clie
 		},
 	}
 }
+
+// filterRestartOnChangeClusters maps the Secrets/ConfigMaps not owned by the clusters, e.g. the user-provided TLS certs,
+// to the clusters referencing them, so the pods can be restarted once they change.
+func (r *ClusterReconciler) filterRestartOnChangeClusters(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		clusterList := &appsv1alpha1.ClusterList{}
+		key := restartOnChangeObject{kind: kind, name: obj.GetName()}.String()
+		if err := r.Client.List(ctx, clusterList, client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{restartOnChangeIndexKey: key}); err != nil {
+			return []reconcile.Request{}
+		}
+		requests := make([]reconcile.Request, 0, len(clusterList.Items))
+		for _, cluster := range clusterList.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)})
+		}
+		return requests
+	}
+}
+
+const restartOnChangeIndexKey = "metadata.restartOnChange"
+
+// indexRestartOnChangeObjects indexes the clusters by the Secrets/ConfigMaps listed in the restart-on-change annotation,
+// and the Secrets of the user-provided TLS certs.
+func indexRestartOnChangeObjects(obj client.Object) []string {
+	cluster, ok := obj.(*appsv1alpha1.Cluster)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, o := range parseRestartOnChangeObjects(cluster) {
+		keys = append(keys, o.String())
+	}
+	for _, comp := range cluster.Spec.ComponentSpecs {
+		if comp.Issuer != nil && comp.Issuer.Name == appsv1alpha1.IssuerUserProvided && comp.Issuer.SecretRef != nil {
+			keys = append(keys, restartOnChangeObject{kind: restartOnChangeSecretKind, name: comp.Issuer.SecretRef.Name}.String())
+		}
+	}
+	return keys
+}
@@ -34,21 +34,29 @@ import (
 
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	"github.com/sethvargo/go-password/password"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slices"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
@@ -56,9 +64,11 @@ import (
 	"github.com/apecloud/kubeblocks/controllers/apps/components"
 	"github.com/apecloud/kubeblocks/internal/common"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 	"github.com/apecloud/kubeblocks/internal/generics"
+	"github.com/apecloud/kubeblocks/internal/testutil"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 	testdp "github.com/apecloud/kubeblocks/internal/testutil/dataprotection"
 	testk8s "github.com/apecloud/kubeblocks/internal/testutil/k8s"
@@ -90,6 +100,18 @@ func (c *mockLorryClient) JoinMember(ctx context.Context) error {
 	return nil
 }
 
+func (c *mockLorryClient) GetOpTimestamp(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (c *mockLorryClient) Demote(ctx context.Context) error {
+	return nil
+}
+
+func (c *mockLorryClient) CheckExternalDependency(ctx context.Context, dependency appsv1alpha1.ExternalDependency) error {
+	return nil
+}
+
 func (c *mockLorryClient) LeaveMember(ctx context.Context) error {
 	var podList corev1.PodList
 	labels := client.MatchingLabels{
@@ -1037,6 +1059,49 @@ var _ = Describe("Cluster Controller", func() {
 		}
 	}
 
+	testImmutableVolumeClaimTemplateChanged := func(compName, compDefName string, storageClass *storagev1.StorageClass) {
+		By("Creating a cluster with VolumeClaimTemplate")
+		pvcSpec := testapps.NewPVCSpec("1Gi")
+		pvcSpec.StorageClassName = &storageClass.Name
+		clusterObj = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefObj.Name, clusterVersionObj.Name).WithRandomName().
+			AddComponent(compName, compDefName).
+			AddVolumeClaimTemplate(testapps.DataVolumeName, pvcSpec).
+			SetReplicas(1).
+			Create(&testCtx).GetObject()
+		clusterKey = client.ObjectKeyFromObject(clusterObj)
+
+		By("Waiting for the cluster controller to create resources completely")
+		waitForCreatingResourceCompletely(clusterKey, compName)
+
+		By("Changing the storage class of the data volume")
+		Expect(testapps.GetAndChangeObj(&testCtx, clusterKey, func(cluster *appsv1alpha1.Cluster) {
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.StorageClassName = pointer.String("changed-sc")
+		})()).ShouldNot(HaveOccurred())
+
+		By("Checking the immutable change surfaced in the ApplyResources condition")
+		Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeApplyResources)
+			g.Expect(condition).ShouldNot(BeNil())
+			g.Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			g.Expect(condition.Message).Should(ContainSubstring("spec.volumeClaimTemplates[data].spec.storageClassName"))
+		})).Should(Succeed())
+
+		By("Checking the volume claim templates of the workload unchanged")
+		rsmList := testk8s.ListAndCheckRSM(&testCtx, clusterKey)
+		Expect(rsmList.Items[0].Spec.VolumeClaimTemplates[0].Spec.StorageClassName).Should(Equal(&storageClass.Name))
+
+		By("Reverting the change")
+		Expect(testapps.GetAndChangeObj(&testCtx, clusterKey, func(cluster *appsv1alpha1.Cluster) {
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec.StorageClassName = &storageClass.Name
+		})()).ShouldNot(HaveOccurred())
+		Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeApplyResources)
+			g.Expect(condition).ShouldNot(BeNil())
+			g.Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		})).Should(Succeed())
+	}
+
 	testVolumeExpansionFailedAndRecover := func(compName, compDefName string) {
 
 		const storageClassName = "test-sc"
@@ -1759,6 +1824,131 @@ var _ = Describe("Cluster Controller", func() {
 		})
 	})
 
+	Context("when creating cluster in lightweight mode", func() {
+		BeforeEach(func() {
+			createAllWorkloadTypesClusterDef()
+		})
+
+		It("should omit the probe sidecars and report the reduced observability", func() {
+			By("Creating a cluster with lightweight mode enabled")
+			clusterObj = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDefObj.Name, clusterVersionObj.Name).WithRandomName().
+				AddComponent(consensusCompName, consensusCompDefName).SetReplicas(3).
+				SetLightweightMode(true).
+				Create(&testCtx).GetObject()
+			clusterKey = client.ObjectKeyFromObject(clusterObj)
+
+			By("Waiting for the cluster controller to create resources completely")
+			waitForCreatingResourceCompletely(clusterKey, consensusCompName)
+
+			By("Checking the pod template has no probe sidecars")
+			Eventually(func(g Gomega) {
+				rsmList := testk8s.ListAndCheckRSM(&testCtx, clusterKey)
+				g.Expect(rsmList.Items).ShouldNot(BeEmpty())
+				rsm := rsmList.Items[0]
+				g.Expect(rsm.Spec.RoleProbe).Should(BeNil())
+				for _, container := range rsm.Spec.Template.Spec.Containers {
+					for _, port := range container.Ports {
+						g.Expect(port.Name).ShouldNot(Equal(constant.ProbeHTTPPortName))
+					}
+				}
+			}).Should(Succeed())
+
+			By("Checking the reduced observability condition")
+			Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeReducedObservability)
+				g.Expect(condition).ShouldNot(BeNil())
+				g.Expect(condition.Reason).Should(Equal(ReasonLightweightMode))
+			})).Should(Succeed())
+		})
+	})
+
+	Context("when tracing is enabled", func() {
+		var exporter *tracetest.InMemoryExporter
+
+		BeforeEach(func() {
+			createAllWorkloadTypesClusterDef(true)
+			exporter = tracetest.NewInMemoryExporter()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+			DeferCleanup(func() {
+				otel.SetTracerProvider(oteltrace.NewNoopTracerProvider())
+			})
+		})
+
+		It("should emit the spans of transformers and plan vertices within the reconcile span", func() {
+			By("Creating a cluster")
+			clusterObj = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDefObj.Name, "").
+				AddComponent(statelessCompName, statelessCompDefName).
+				WithRandomName().Create(&testCtx).GetObject()
+			clusterKey = client.ObjectKeyFromObject(clusterObj)
+			waitForCreatingResourceCompletely(clusterKey, statelessCompName)
+
+			By("Checking the span hierarchy of the cluster reconciliation")
+			Eventually(func(g Gomega) {
+				spans := exporter.GetSpans()
+				reconcileSpanIDs := map[oteltrace.SpanID]bool{}
+				for _, span := range spans {
+					if span.Name != "Reconcile Cluster" {
+						continue
+					}
+					for _, attr := range span.Attributes {
+						if attr.Key == "name" && attr.Value.AsString() == clusterKey.Name {
+							reconcileSpanIDs[span.SpanContext.SpanID()] = true
+						}
+					}
+				}
+				g.Expect(reconcileSpanIDs).ShouldNot(BeEmpty())
+
+				childNames := map[string]bool{}
+				for _, span := range spans {
+					if reconcileSpanIDs[span.Parent.SpanID()] {
+						childNames[span.Name] = true
+					}
+				}
+				g.Expect(childNames).Should(HaveKey("ComponentTransformer"))
+				g.Expect(childNames).Should(HaveKey("ClusterStatusTransformer"))
+				g.Expect(childNames).Should(HaveKey("Apply Vertex"))
+			}).Should(Succeed())
+		})
+	})
+
+	Context("when logging the cluster reconciliation", func() {
+		BeforeEach(func() {
+			createAllWorkloadTypesClusterDef(true)
+		})
+
+		It("should attach the correlation fields to the logs of all transformers within one reconcile", func() {
+			By("Creating a cluster")
+			clusterObj = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDefObj.Name, "").
+				AddComponent(statelessCompName, statelessCompDefName).
+				WithRandomName().Create(&testCtx).GetObject()
+			clusterKey = client.ObjectKeyFromObject(clusterObj)
+			waitForCreatingResourceCompletely(clusterKey, statelessCompName)
+
+			By("Reconciling the cluster with a test log sink")
+			sink, logger := testutil.NewLogSink()
+			reconciler := &ClusterReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: clusterRecorder}
+			_, _ = reconciler.Reconcile(logf.IntoContext(testCtx.Ctx, logger), controllerruntime.Request{NamespacedName: clusterKey})
+
+			By("Checking the fields of the captured log records")
+			records := sink.Records()
+			Expect(records).ShouldNot(BeEmpty())
+			reconcileID := records[0].Fields[intctrlutil.LogFieldReconcileID]
+			Expect(reconcileID).ShouldNot(BeEmpty())
+			hasComponentLog := false
+			for _, record := range records {
+				Expect(record.Fields).Should(HaveKeyWithValue(intctrlutil.LogFieldCluster, clusterKey.String()))
+				Expect(record.Fields).Should(HaveKeyWithValue(intctrlutil.LogFieldReconcileID, reconcileID))
+				if record.Fields[intctrlutil.LogFieldComponent] == statelessCompName {
+					hasComponentLog = true
+				}
+			}
+			Expect(hasComponentLog).Should(BeTrue())
+		})
+	})
+
 	Context("when creating cluster with multiple kinds of components", func() {
 		BeforeEach(func() {
 			cleanEnv()
@@ -1946,8 +2136,14 @@ var _ = Describe("Cluster Controller", func() {
 
 			checkPreservedObjects := func(uid types.UID) (*corev1.PersistentVolumeClaimList, *corev1.SecretList, *corev1.ConfigMapList) {
 				checkObject := func(obj client.Object) {
-					clusterJSON, ok := obj.GetAnnotations()[constant.LastAppliedClusterAnnotationKey]
+					// the last applied cluster is stored in the ConfigMap referred by the annotation
+					Expect(obj.GetAnnotations()).ShouldNot(HaveKey(constant.LastAppliedClusterAnnotationKey))
+					snapshotName, ok := obj.GetAnnotations()[constant.LastAppliedClusterRefAnnotationKey]
 					Expect(ok).Should(BeTrue())
+					Expect(snapshotName).Should(Equal(component.GenerateLastAppliedClusterName(clusterKey.Name)))
+					snapshot := &corev1.ConfigMap{}
+					Expect(k8sClient.Get(testCtx.Ctx, client.ObjectKey{Namespace: clusterKey.Namespace, Name: snapshotName}, snapshot)).Should(Succeed())
+					clusterJSON := snapshot.Data[constant.LastAppliedClusterKey]
 					Expect(clusterJSON).ShouldNot(BeEmpty())
 					lastAppliedCluster := &appsv1alpha1.Cluster{}
 					Expect(json.Unmarshal([]byte(clusterJSON), lastAppliedCluster)).ShouldNot(HaveOccurred())
@@ -2003,6 +2199,106 @@ var _ = Describe("Cluster Controller", func() {
 			checkPreservedObjects(clusterObj.UID)
 		})
 
+		It("should freeze the workloads of the paused component while reconciling the others", func() {
+			createNWaitClusterObj(map[string]string{
+				statelessCompName: statelessCompDefName,
+				statefulCompName:  statefulCompDefName,
+			}, nil)
+
+			getCompRSM := func(g Gomega, compName string) *workloads.ReplicatedStateMachine {
+				rsmList := &workloads.ReplicatedStateMachineList{}
+				g.Expect(testCtx.Cli.List(testCtx.Ctx, rsmList, client.MatchingLabels{
+					constant.AppInstanceLabelKey:    clusterKey.Name,
+					constant.KBAppComponentLabelKey: compName,
+				}, client.InNamespace(clusterKey.Namespace))).Should(Succeed())
+				g.Expect(rsmList.Items).Should(HaveLen(1))
+				return &rsmList.Items[0]
+			}
+			var pausedReplicas int32
+			Eventually(func(g Gomega) {
+				pausedReplicas = *getCompRSM(g, statelessCompName).Spec.Replicas
+			}).Should(Succeed())
+
+			By("pause the stateless component")
+			Expect(testapps.GetAndChangeObj(&testCtx, clusterKey, func(cluster *appsv1alpha1.Cluster) {
+				if cluster.Annotations == nil {
+					cluster.Annotations = map[string]string{}
+				}
+				cluster.Annotations[constant.PausedComponentsAnnotationKey] = statelessCompName
+			})()).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
+				g.Expect(cluster.Status.Components[statelessCompName].Paused).Should(BeTrue())
+				g.Expect(cluster.Status.Components[statefulCompName].Paused).Should(BeFalse())
+			})).Should(Succeed())
+
+			By("change the replicas of both components")
+			updatedReplicas := pausedReplicas + 2
+			Expect(testapps.GetAndChangeObj(&testCtx, clusterKey, func(cluster *appsv1alpha1.Cluster) {
+				for i := range cluster.Spec.ComponentSpecs {
+					cluster.Spec.ComponentSpecs[i].Replicas = updatedReplicas
+				}
+			})()).ShouldNot(HaveOccurred())
+
+			By("check the stateful component is reconciled while the paused one is not")
+			Eventually(func(g Gomega) {
+				g.Expect(*getCompRSM(g, statefulCompName).Spec.Replicas).Should(Equal(updatedReplicas))
+			}).Should(Succeed())
+			Consistently(func(g Gomega) {
+				g.Expect(*getCompRSM(g, statelessCompName).Spec.Replicas).Should(Equal(pausedReplicas))
+			}).Should(Succeed())
+
+			By("unpause the stateless component")
+			Expect(testapps.GetAndChangeObj(&testCtx, clusterKey, func(cluster *appsv1alpha1.Cluster) {
+				delete(cluster.Annotations, constant.PausedComponentsAnnotationKey)
+			})()).ShouldNot(HaveOccurred())
+			Eventually(func(g Gomega) {
+				g.Expect(*getCompRSM(g, statelessCompName).Spec.Replicas).Should(Equal(updatedReplicas))
+			}).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
+				g.Expect(cluster.Status.Components[statelessCompName].Paused).Should(BeFalse())
+			})).Should(Succeed())
+		})
+
+		It("should only advance the observedGeneration of the updated component", func() {
+			createNWaitClusterObj(map[string]string{
+				statelessCompName: statelessCompDefName,
+				statefulCompName:  statefulCompDefName,
+			}, nil)
+
+			By("check the observedGeneration of all components after creation")
+			Eventually(testapps.GetClusterComponentStatus(&testCtx, clusterKey, statelessCompName)).
+				Should(HaveField("ObservedGeneration", BeEquivalentTo(1)))
+			Eventually(testapps.GetClusterComponentStatus(&testCtx, clusterKey, statefulCompName)).
+				Should(HaveField("ObservedGeneration", BeEquivalentTo(1)))
+
+			By("change the replicas of the stateless component only")
+			Expect(testapps.GetAndChangeObj(&testCtx, clusterKey, func(cluster *appsv1alpha1.Cluster) {
+				for i := range cluster.Spec.ComponentSpecs {
+					if cluster.Spec.ComponentSpecs[i].Name == statelessCompName {
+						cluster.Spec.ComponentSpecs[i].Replicas += 1
+					}
+				}
+			})()).ShouldNot(HaveOccurred())
+
+			By("check only the observedGeneration of the stateless component advances")
+			Eventually(testapps.GetClusterObservedGeneration(&testCtx, clusterKey)).Should(BeEquivalentTo(2))
+			Eventually(testapps.GetClusterComponentStatus(&testCtx, clusterKey, statelessCompName)).
+				Should(HaveField("ObservedGeneration", BeEquivalentTo(2)))
+			Consistently(testapps.GetClusterComponentStatus(&testCtx, clusterKey, statefulCompName)).
+				Should(HaveField("ObservedGeneration", BeEquivalentTo(1)))
+
+			By("check the observedGeneration is read from the persisted workloads")
+			for compName, generation := range map[string]string{statelessCompName: "2", statefulCompName: "1"} {
+				rsmList := &workloads.ReplicatedStateMachineList{}
+				Expect(testCtx.Cli.List(testCtx.Ctx, rsmList, client.MatchingLabels{
+					constant.AppInstanceLabelKey:    clusterKey.Name,
+					constant.KBAppComponentLabelKey: compName,
+				}, client.InNamespace(clusterKey.Namespace))).Should(Succeed())
+				Expect(rsmList.Items).Should(HaveLen(1))
+				Expect(rsmList.Items[0].Annotations).Should(HaveKeyWithValue(constant.ComponentSpecGenerationAnnotationKey, generation))
+			}
+		})
+
 		It("should successfully h-scale with multiple components", func() {
 			testk8s.MockEnableVolumeSnapshot(&testCtx, testk8s.DefaultStorageClassName)
 			viper.Set(constant.CfgKeyBackupPVCName, "")
@@ -2034,7 +2330,7 @@ var _ = Describe("Cluster Controller", func() {
 			By("Creating a cluster")
 			clusterObj := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
 				clusterDefObj.Name, clusterVersionObj.Name).
-				AddComponent(compName, compDefName).WithRandomName().SetBackup(backup).
+				AddComponent(compName, compDefName).WithRandomName().SetBackupSpec(backup).
 				Create(&testCtx).GetObject()
 			clusterKey = client.ObjectKeyFromObject(clusterObj)
 
@@ -2248,6 +2544,10 @@ var _ = Describe("Cluster Controller", func() {
 				It("should be able to recover if volume expansion fails", func() {
 					testVolumeExpansionFailedAndRecover(compName, compDefName)
 				})
+
+				It("should surface the immutable changes of the volume claim templates", func() {
+					testImmutableVolumeClaimTemplateChanged(compName, compDefName, mockStorageClass)
+				})
 			})
 
 			Context(fmt.Sprintf("[comp: %s] horizontal scale", compName), func() {
@@ -2384,6 +2684,52 @@ var _ = Describe("Cluster Controller", func() {
 				g.Expect(tmpCluster.Annotations[constant.RestoreFromBackupAnnotationKey]).Should(BeEmpty())
 			})).Should(Succeed())
 		})
+
+		It("test restore cluster from volume snapshot backup", func() {
+			By("mocking a completed backup with volume snapshot")
+			backup := testdp.NewFakeCompletedBackup(&testCtx, "test-vs-backup", testapps.DataVolumeName, true)
+
+			By("creating cluster restored from the backup")
+			replicas := 3
+			clusterObj = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDefObj.Name, clusterVersionObj.Name).WithRandomName().
+				AddComponent(compName, compDefName).
+				SetReplicas(int32(replicas)).
+				AddVolumeClaimTemplate(testapps.DataVolumeName, testapps.NewPVCSpec("1Gi")).
+				SetBackup(true, "7d").
+				SetRestoreFromBackup(backup.Name, compName).
+				Create(&testCtx).GetObject()
+			clusterKey = client.ObjectKeyFromObject(clusterObj)
+
+			By("checking the restore to prepare data from the backup created")
+			ml := client.MatchingLabels{
+				constant.AppInstanceLabelKey:    clusterKey.Name,
+				constant.KBAppComponentLabelKey: compName,
+			}
+			Eventually(func(g Gomega) {
+				restoreList := &dpv1alpha1.RestoreList{}
+				g.Expect(k8sClient.List(testCtx.Ctx, restoreList, ml, client.InNamespace(clusterKey.Namespace))).Should(Succeed())
+				g.Expect(restoreList.Items).Should(HaveLen(1))
+				restore := restoreList.Items[0]
+				g.Expect(restore.Spec.Backup.Name).Should(Equal(backup.Name))
+				g.Expect(restore.Spec.Backup.Namespace).Should(Equal(backup.Namespace))
+				g.Expect(restore.Spec.PrepareDataConfig).ShouldNot(BeNil())
+				templates := restore.Spec.PrepareDataConfig.RestoreVolumeClaimsTemplate.Templates
+				g.Expect(templates).Should(HaveLen(1))
+				g.Expect(templates[0].VolumeSource).Should(Equal(testapps.DataVolumeName))
+			}).Should(Succeed())
+
+			By("mocking the pvcs restored and the restore completed")
+			mockComponentPVCsAndBound(clusterObj.Spec.GetComponentByName(compName), replicas, true, testk8s.DefaultStorageClassName)
+			mockRestoreCompleted(ml)
+
+			By("Waiting for the cluster controller to create resources completely")
+			waitForCreatingResourceCompletely(clusterKey, compName)
+			Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, tmpCluster *appsv1alpha1.Cluster) {
+				g.Expect(*tmpCluster.Spec.Backup.Enabled).Should(BeTrue())
+				g.Expect(tmpCluster.Spec.Backup.RetentionPeriod).Should(BeEquivalentTo("7d"))
+			})).Should(Succeed())
+		})
 	})
 
 	When("creating cluster with workloadType=replication component", func() {
@@ -2581,6 +2927,81 @@ var _ = Describe("Cluster Controller", func() {
 			By("Wait for the cluster to terminate")
 			Eventually(testapps.CheckObjExists(&testCtx, clusterKey, &appsv1alpha1.Cluster{}, false)).Should(Succeed())
 		})
+
+		It("should remove the finalizer when the namespace is terminating", func() {
+			By("Create a namespace to be deleted")
+			namespace := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-terminating-" + testCtx.GetRandomStr(),
+				},
+			}
+			Expect(testCtx.Cli.Create(testCtx.Ctx, namespace)).Should(Succeed())
+			DeferCleanup(func() {
+				// there is no namespace controller in envtest, finalize the namespace manually
+				Eventually(func(g Gomega) {
+					ns := &corev1.Namespace{}
+					err := testCtx.Cli.Get(testCtx.Ctx, client.ObjectKeyFromObject(namespace), ns)
+					if apierrors.IsNotFound(err) {
+						return
+					}
+					g.Expect(err).Should(Succeed())
+					ns.Spec.Finalizers = []corev1.FinalizerName{}
+					clientGo, err := kubernetes.NewForConfig(testEnv.Config)
+					g.Expect(err).Should(Succeed())
+					_, err = clientGo.CoreV1().Namespaces().Finalize(testCtx.Ctx, ns, metav1.UpdateOptions{})
+					g.Expect(err).Should(Succeed())
+				}).Should(Succeed())
+			})
+
+			By("Create a cluster which prevents deletion in the namespace")
+			clusterObj = testapps.NewClusterFactory(namespace.Name, clusterName,
+				clusterDefObj.Name, clusterVersionObj.Name).WithRandomName().
+				AddComponent(consensusCompName, consensusCompDefName).
+				SetReplicas(1).
+				Create(&testCtx).GetObject()
+			clusterKey = client.ObjectKeyFromObject(clusterObj)
+			Eventually(testapps.GetClusterObservedGeneration(&testCtx, clusterKey)).Should(BeEquivalentTo(1))
+			Expect(testapps.GetAndChangeObj(&testCtx, clusterKey, func(cluster *appsv1alpha1.Cluster) {
+				cluster.Spec.TerminationPolicy = appsv1alpha1.DoNotTerminate
+			})()).ShouldNot(HaveOccurred())
+
+			workloadKey := types.NamespacedName{
+				Namespace: clusterKey.Namespace,
+				Name:      clusterKey.Name + "-" + consensusCompName,
+			}
+			Eventually(testapps.CheckObjExists(&testCtx, workloadKey, &workloads.ReplicatedStateMachine{}, true)).Should(Succeed())
+
+			By("Create a backup of the cluster which can't be finalized")
+			backup := testdp.NewBackupFactory(namespace.Name, "test-backup").
+				SetBackupPolicyName("test-backup-policy").
+				SetBackupMethod(testdp.BackupMethodName).
+				SetLabels(map[string]string{constant.AppInstanceLabelKey: clusterKey.Name}).
+				AddFinalizers([]string{dptypes.DataProtectionFinalizerName}).
+				WithRandomName().
+				Create(&testCtx).GetObject()
+
+			By("Delete the cluster and check it's kept by the terminationPolicy")
+			testapps.DeleteObject(&testCtx, clusterKey, &appsv1alpha1.Cluster{})
+			Consistently(testapps.CheckObjExists(&testCtx, clusterKey, &appsv1alpha1.Cluster{}, true)).Should(Succeed())
+
+			By("Delete the namespace")
+			Expect(testCtx.Cli.Delete(testCtx.Ctx, namespace)).Should(Succeed())
+
+			By("Wait for the finalizers of the cluster and its workload to be removed")
+			Eventually(testapps.CheckObjExists(&testCtx, clusterKey, &appsv1alpha1.Cluster{}, false)).Should(Succeed())
+			Eventually(func(g Gomega) {
+				rsm := &workloads.ReplicatedStateMachine{}
+				err := testCtx.Cli.Get(testCtx.Ctx, workloadKey, rsm)
+				if apierrors.IsNotFound(err) {
+					return
+				}
+				g.Expect(err).Should(Succeed())
+				g.Expect(rsm.Finalizers).ShouldNot(ContainElement(constant.DBClusterFinalizerName))
+			}).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, backup *dpv1alpha1.Backup) {
+				g.Expect(backup.Finalizers).ShouldNot(ContainElement(dptypes.DataProtectionFinalizerName))
+			})).Should(Succeed())
+		})
 	})
 })
 
@@ -23,23 +23,28 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	roclient "github.com/apecloud/kubeblocks/internal/controller/client"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	"github.com/apecloud/kubeblocks/internal/controller/tracing"
 	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 )
@@ -62,6 +67,8 @@ type ClusterTransformContext struct {
 	OrigCluster *appsv1alpha1.Cluster
 	ClusterDef  *appsv1alpha1.ClusterDefinition
 	ClusterVer  *appsv1alpha1.ClusterVersion
+	// ForcedTransformers are the transformers to run even if the transformer chain is stopped prematurely
+	ForcedTransformers sets.Set[string]
 }
 
 // clusterPlanBuilder a graph.PlanBuilder implementation for Cluster reconciliation
@@ -81,6 +88,7 @@ type clusterPlan struct {
 }
 
 var _ graph.TransformContext = &ClusterTransformContext{}
+var _ graph.ForcedTransformersContext = &ClusterTransformContext{}
 var _ graph.PlanBuilder = &clusterPlanBuilder{}
 var _ graph.Plan = &clusterPlan{}
 
@@ -102,6 +110,10 @@ func (c *ClusterTransformContext) GetLogger() logr.Logger {
 	return c.Logger
 }
 
+func (c *ClusterTransformContext) GetForcedTransformers() sets.Set[string] {
+	return c.ForcedTransformers
+}
+
 // PlanBuilder implementation
 
 func (c *clusterPlanBuilder) Init() error {
@@ -152,6 +164,8 @@ func (c *clusterPlanBuilder) Build() (graph.Plan, error) {
 		sendWarningEventWithError(c.transCtx.GetRecorder(), c.transCtx.Cluster, ReasonApplyResourcesFailed, err)
 	}()
 
+	c.transCtx.ForcedTransformers = c.getForcedTransformers()
+
 	// new a DAG and apply chain on it
 	dag := graph.NewDAG()
 	err = c.transformers.ApplyTo(c.transCtx, dag)
@@ -160,13 +174,41 @@ func (c *clusterPlanBuilder) Build() (graph.Plan, error) {
 	// construct execution plan
 	plan := &clusterPlan{
 		dag:      dag,
-		walkFunc: c.defaultWalkFuncWithLogging,
+		walkFunc: c.defaultWalkFuncWithTracing,
 		cli:      c.cli,
 		transCtx: c.transCtx,
 	}
 	return plan, err
 }
 
+// getForcedTransformers gets the transformers listed in the annotation cluster.kubeblocks.io/force-run-transformers,
+// the names which don't match any registered transformer are ignored with a warning event.
+func (c *clusterPlanBuilder) getForcedTransformers() sets.Set[string] {
+	forced := sets.New[string]()
+	value, ok := c.transCtx.Cluster.Annotations[forceRunTransformersAnnotationKey]
+	if !ok {
+		return forced
+	}
+	registered := c.transformers.Names()
+	unknown := make([]string, 0)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case len(name) == 0:
+			continue
+		case registered.Has(name):
+			forced.Insert(name)
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 && c.transCtx.GetRecorder() != nil {
+		c.transCtx.GetRecorder().Eventf(c.transCtx.Cluster, corev1.EventTypeWarning, reasonUnknownForcedTransformers,
+			"unknown transformers in annotation %s: %s", forceRunTransformersAnnotationKey, strings.Join(unknown, ","))
+	}
+	return forced
+}
+
 // Plan implementation
 
 func (p *clusterPlan) Execute() error {
@@ -220,6 +262,27 @@ func NewClusterPlanBuilder(ctx intctrlutil.RequestCtx, cli client.Client, req ct
 	}
 }
 
+func (c *clusterPlanBuilder) defaultWalkFuncWithTracing(vertex graph.Vertex) error {
+	_, span := tracing.StartSpan(c.transCtx.Context, "Apply Vertex")
+	// the attributes are only built when the span is sampled, to keep the no-op tracing overhead negligible.
+	if node, ok := vertex.(*ictrltypes.LifecycleVertex); ok && span.IsRecording() && node.Obj != nil {
+		attrs := []attribute.KeyValue{
+			attribute.String("namespace", node.Obj.GetNamespace()),
+			attribute.String("name", node.Obj.GetName()),
+		}
+		if gvk, err := apiutil.GVKForObject(node.Obj, c.cli.Scheme()); err == nil {
+			attrs = append(attrs, attribute.String("kind", gvk.Kind))
+		}
+		if node.Action != nil {
+			attrs = append(attrs, attribute.String("action", string(*node.Action)))
+		}
+		span.SetAttributes(attrs...)
+	}
+	err := c.defaultWalkFuncWithLogging(vertex)
+	tracing.EndSpan(span, err)
+	return err
+}
+
 func (c *clusterPlanBuilder) defaultWalkFuncWithLogging(vertex graph.Vertex) error {
 	node, ok := vertex.(*ictrltypes.LifecycleVertex)
 	err := c.defaultWalkFunc(vertex)
@@ -268,6 +331,9 @@ func (c *clusterPlanBuilder) reconcileObject(node *ictrltypes.LifecycleVertex) e
 			return nil
 		}
 		err := c.cli.Update(c.transCtx.Context, node.Obj)
+		if err != nil && intctrlutil.IsUpdateRejected(err) {
+			return c.newUpdateRejectedError(node.Obj, err)
+		}
 		if err != nil && !apierrors.IsNotFound(err) {
 			return err
 		}
@@ -275,6 +341,9 @@ func (c *clusterPlanBuilder) reconcileObject(node *ictrltypes.LifecycleVertex) e
 		patch := client.MergeFrom(node.ObjCopy)
 		if err := c.cli.Patch(c.transCtx.Context, node.Obj, patch); err != nil && !apierrors.IsNotFound(err) {
 			c.transCtx.Logger.Error(err, fmt.Sprintf("patch %T error", node.ObjCopy))
+			if intctrlutil.IsUpdateRejected(err) {
+				return c.newUpdateRejectedError(node.Obj, err)
+			}
 			return err
 		}
 	case ictrltypes.DELETE:
@@ -309,6 +378,15 @@ func (c *clusterPlanBuilder) reconcileObject(node *ictrltypes.LifecycleVertex) e
 	return nil
 }
 
+// newUpdateRejectedError wraps the update rejected by the API server, with the invalid fields reported by it.
+func (c *clusterPlanBuilder) newUpdateRejectedError(obj client.Object, err error) error {
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, gvkErr := apiutil.GVKForObject(obj, c.cli.Scheme()); gvkErr == nil {
+		kind = gvk.Kind
+	}
+	return intctrlutil.NewUpdateRejectedError(kind, obj.GetName(), nil, err)
+}
+
 func (c *clusterPlanBuilder) reconcileCluster(node *ictrltypes.LifecycleVertex) error {
 	cluster := node.Obj.(*appsv1alpha1.Cluster).DeepCopy()
 	origCluster := node.ObjCopy.(*appsv1alpha1.Cluster)
@@ -346,6 +424,10 @@ func (c *clusterPlanBuilder) emitConditionUpdatingEvent(oldConditions, newCondit
 		if oldCondition == nil && newCondition.Status == metav1.ConditionFalse {
 			return
 		}
+		// the warning event of scheduling blocked is emitted by the ClusterSchedulingTransformer.
+		if newCondition.Type == appsv1alpha1.ConditionTypeSchedulingBlocked {
+			continue
+		}
 		if !reflect.DeepEqual(oldCondition, &newCondition) {
 			eType := corev1.EventTypeNormal
 			if newCondition.Status == metav1.ConditionFalse {
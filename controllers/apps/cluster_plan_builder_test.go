@@ -23,16 +23,26 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	"github.com/apecloud/kubeblocks/internal/generics"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 )
 
+type prematureStopTransformer struct{}
+
+func (t *prematureStopTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	return graph.ErrPrematureStop
+}
+
 var _ = Describe("cluster plan builder test", func() {
 	const (
 		clusterDefName     = "test-clusterdef"
@@ -93,4 +103,42 @@ var _ = Describe("cluster plan builder test", func() {
 			Expect(planBuilder.Init()).Should(Succeed())
 		})
 	})
+
+	Context("test force-run transformers", func() {
+		It("should run the transformers listed in the annotation after the chain is stopped", func() {
+			clusterObj := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDefName, clusterVersionName).WithRandomName().
+				AddAnnotations(forceRunTransformersAnnotationKey, "AssureMetaTransformer, UnknownTransformer").
+				GetObject()
+			Expect(testCtx.Cli.Create(testCtx.Ctx, clusterObj)).Should(Succeed())
+			clusterKey := client.ObjectKeyFromObject(clusterObj)
+			Eventually(testapps.CheckObjExists(&testCtx, clusterKey, &appsv1alpha1.Cluster{}, true)).Should(Succeed())
+			req := ctrl.Request{
+				NamespacedName: clusterKey,
+			}
+			recorder := record.NewFakeRecorder(10)
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx:      testCtx.Ctx,
+				Req:      req,
+				Log:      log.FromContext(ctx).WithValues("cluster", req.NamespacedName),
+				Recorder: recorder,
+			}
+			planBuilder := NewClusterPlanBuilder(reqCtx, testCtx.Cli, req)
+			Expect(planBuilder.Init()).Should(Succeed())
+			// the cluster may have been handled by the cluster controller, reset the meta to be assured
+			transCtx := planBuilder.(*clusterPlanBuilder).transCtx
+			controllerutil.RemoveFinalizer(transCtx.Cluster, constant.DBClusterFinalizerName)
+			delete(transCtx.Cluster.Labels, constant.ClusterDefLabelKey)
+			_, err := planBuilder.AddTransformer(&prematureStopTransformer{}, &AssureMetaTransformer{}).Build()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			By("check the forced transformer has run")
+			Expect(transCtx.ForcedTransformers.UnsortedList()).Should(ConsistOf("AssureMetaTransformer"))
+			Expect(controllerutil.ContainsFinalizer(transCtx.Cluster, constant.DBClusterFinalizerName)).Should(BeTrue())
+			Expect(transCtx.Cluster.Labels).Should(HaveKeyWithValue(constant.ClusterDefLabelKey, clusterDefName))
+
+			By("check the unknown transformer is reported")
+			Expect(recorder.Events).Should(Receive(ContainSubstring(reasonUnknownForcedTransformers)))
+		})
+	})
 })
@@ -21,6 +21,8 @@ package apps
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
@@ -28,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 )
 
@@ -40,6 +43,25 @@ const (
 	ReasonAllReplicasReady      = "AllReplicasReady"      // ReasonAllReplicasReady the pods of components are ready
 	ReasonComponentsNotReady    = "ComponentsNotReady"    // ReasonComponentsNotReady the components of cluster are not ready
 	ReasonClusterReady          = "ClusterReady"          // ReasonClusterReady the components of cluster are ready, the component phase is running
+	ReasonAffinityUnsatisfiable = "AffinityUnsatisfiable" // ReasonAffinityUnsatisfiable the pods of components can't be scheduled as the affinity constraints are unsatisfiable
+	ReasonLightweightMode       = "LightweightMode"       // ReasonLightweightMode the cluster runs in lightweight mode without the probe and exporter sidecars
+	ReasonResolveDigestFailed   = "ResolveDigestFailed"   // ReasonResolveDigestFailed failed to resolve the digests of the images of components
+	// ReasonClusterDefinitionUpdated the ClusterDefinition is updated and the changes wait for the cluster to adopt them
+	ReasonClusterDefinitionUpdated = "ClusterDefinitionUpdated"
+	// ReasonVolumeUtilizationHigh the utilization of the PVCs of components exceeds the threshold
+	ReasonVolumeUtilizationHigh = "VolumeUtilizationHigh"
+	// ReasonVolumeUtilizationCritical the utilization of the PVCs of components exceeds the critical threshold
+	ReasonVolumeUtilizationCritical = "VolumeUtilizationCritical"
+	// ReasonVolumeClaimTemplateNotFound the volume claim templates of the volumes mounted by the containers are missing in the components
+	ReasonVolumeClaimTemplateNotFound = "VolumeClaimTemplateNotFound"
+	// ReasonConnCredentialRecovered the deleted connection credential secret is recovered from the snapshot
+	ReasonConnCredentialRecovered = "ConnCredentialRecovered"
+	// ReasonConnCredentialRegenerated the deleted connection credential secret is recovered from the snapshot, and its password is regenerated
+	ReasonConnCredentialRegenerated = "ConnCredentialRegenerated"
+	// ReasonExternalDependencyProbeFailed the probes of the external dependencies declared by the ClusterDefinition failed
+	ReasonExternalDependencyProbeFailed = "ExternalDependencyProbeFailed"
+	// ReasonMaxAttemptsExceeded the failed jobs are not re-created anymore as they have reached the max attempts
+	ReasonMaxAttemptsExceeded = "MaxAttemptsExceeded"
 )
 
 func setProvisioningStartedCondition(conditions *[]metav1.Condition, clusterName string, clusterGeneration int64, err error) {
@@ -86,8 +108,8 @@ func newFailedProvisioningStartedCondition(err error) metav1.Condition {
 
 func setApplyResourceCondition(conditions *[]metav1.Condition, clusterGeneration int64, err error) {
 	condition := newApplyResourcesCondition(clusterGeneration)
-	// ignore requeue error
-	if err != nil && !intctrlutil.IsRequeueError(err) {
+	// ignore requeue error, except the rejected updates which never succeed until the spec is corrected
+	if err != nil && (!intctrlutil.IsRequeueError(err) || intctrlutil.IsUpdateRejectedError(err)) {
 		condition = newFailedApplyResourcesCondition(err)
 	}
 	meta.SetStatusCondition(conditions, condition)
@@ -136,12 +158,17 @@ func newReplicasNotReadyCondition(notReadyComponentNames map[string]struct{}) me
 	}
 }
 
-// newClusterReadyCondition creates a condition when all components of cluster are running
-func newClusterReadyCondition(clusterName string) metav1.Condition {
+// newClusterReadyCondition creates a condition when all components of cluster are running,
+// stableSince is the time since when the pods of all components have been ready.
+func newClusterReadyCondition(clusterName string, stableSince *metav1.Time) metav1.Condition {
+	message := fmt.Sprintf("Cluster: %s is ready, current phase is Running", clusterName)
+	if stableSince != nil {
+		message += fmt.Sprintf(", stable since %s", stableSince.UTC().Format(time.RFC3339))
+	}
 	return metav1.Condition{
 		Type:    appsv1alpha1.ConditionTypeReady,
 		Status:  metav1.ConditionTrue,
-		Message: fmt.Sprintf("Cluster: %s is ready, current phase is Running", clusterName),
+		Message: message,
 		Reason:  ReasonClusterReady,
 	}
 }
@@ -157,3 +184,101 @@ func newComponentsNotReadyCondition(notReadyComponentNames map[string]struct{})
 		Reason:  ReasonComponentsNotReady,
 	}
 }
+
+// newSchedulingBlockedCondition creates a condition when pods of components are unschedulable due to the affinity constraints
+func newSchedulingBlockedCondition(message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeSchedulingBlocked,
+		Status:  metav1.ConditionTrue,
+		Message: message,
+		Reason:  ReasonAffinityUnsatisfiable,
+	}
+}
+
+// newReducedObservabilityCondition creates a condition when the cluster runs in lightweight mode
+func newReducedObservabilityCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   appsv1alpha1.ConditionTypeReducedObservability,
+		Status: metav1.ConditionTrue,
+		Message: "the probe and exporter sidecars are omitted in lightweight mode, " +
+			"the roles of replicas are unknown and the phase of components is derived from the readiness of workloads only",
+		Reason: ReasonLightweightMode,
+	}
+}
+
+// newExternalDependencyUnreachableCondition creates a condition when the external dependencies of cluster are unreachable
+func newExternalDependencyUnreachableCondition(message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeExternalDependencyUnreachable,
+		Status:  metav1.ConditionTrue,
+		Message: fmt.Sprintf("the external dependencies are unreachable, %s", message),
+		Reason:  ReasonExternalDependencyProbeFailed,
+	}
+}
+
+// newSystemAccountsProvisionFailedCondition creates a condition when the system accounts fail to be provisioned after the max attempts
+func newSystemAccountsProvisionFailedCondition(accounts []string, maxAttempts int32) metav1.Condition {
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeSystemAccountsProvisionFailed,
+		Status:  metav1.ConditionTrue,
+		Message: fmt.Sprintf("failed to provision the system accounts %s after %d attempts", strings.Join(accounts, ","), maxAttempts),
+		Reason:  ReasonMaxAttemptsExceeded,
+	}
+}
+
+// newImageDigestUnresolvedCondition creates a condition when the digests of the images of components can't be resolved
+func newImageDigestUnresolvedCondition(message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeImageDigestUnresolved,
+		Status:  metav1.ConditionTrue,
+		Message: message,
+		Reason:  ReasonResolveDigestFailed,
+	}
+}
+
+// newClusterDefinitionUpdatePendingCondition creates a condition when the changes of the ClusterDefinition wait for the cluster to adopt them
+func newClusterDefinitionUpdatePendingCondition(clusterDef *appsv1alpha1.ClusterDefinition, renderedGeneration int64) metav1.Condition {
+	return metav1.Condition{
+		Type:   appsv1alpha1.ConditionTypeClusterDefinitionUpdatePending,
+		Status: metav1.ConditionTrue,
+		Message: fmt.Sprintf("ClusterDefinition %s is updated to generation %d while the cluster is rendered against generation %d, "+
+			"the workloads of the existing components are kept unchanged until the annotation %s=%d is set on the cluster",
+			clusterDef.Name, clusterDef.Generation, renderedGeneration, constant.AdoptClusterDefGenerationAnnotationKey, clusterDef.Generation),
+		Reason: ReasonClusterDefinitionUpdated,
+	}
+}
+
+// newVolumeUtilizationHighCondition creates a condition when the utilization of the PVCs of components exceeds the threshold,
+// the reason is ReasonVolumeUtilizationCritical if any of them exceeds the critical threshold.
+func newVolumeUtilizationHighCondition(critical bool, message string) metav1.Condition {
+	reason := ReasonVolumeUtilizationHigh
+	if critical {
+		reason = ReasonVolumeUtilizationCritical
+	}
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeVolumeUtilizationHigh,
+		Status:  metav1.ConditionTrue,
+		Message: message,
+		Reason:  reason,
+	}
+}
+
+// newInvalidVolumeMountCondition creates a condition when the volume claim templates of the mounted volumes are missing in the components
+func newInvalidVolumeMountCondition(message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeInvalidVolumeMount,
+		Status:  metav1.ConditionTrue,
+		Message: message,
+		Reason:  ReasonVolumeClaimTemplateNotFound,
+	}
+}
+
+// newConnCredentialRecreatedCondition creates a condition when the deleted connection credential secret is recreated
+func newConnCredentialRecreatedCondition(reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeConnCredentialRecreated,
+		Status:  metav1.ConditionTrue,
+		Message: message,
+		Reason:  reason,
+	}
+}
@@ -51,12 +51,13 @@ import (
 	"github.com/apecloud/kubeblocks/internal/configuration/util"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
 	rsmcore "github.com/apecloud/kubeblocks/internal/controller/rsm"
 	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/featuregate"
 	"github.com/apecloud/kubeblocks/internal/generics"
-	viper "github.com/apecloud/kubeblocks/internal/viperx"
 	lorry "github.com/apecloud/kubeblocks/lorry/client"
 )
 
@@ -82,6 +83,8 @@ type rsmComponent struct {
 	workloadVertex *ictrltypes.LifecycleVertex // DAG vertex of main workload object
 	// runningWorkload can be nil, and the replicas of workload can be nil (zero)
 	runningWorkload *workloads.ReplicatedStateMachine
+	// clusterDefUpdatePending indicates the changes of the ClusterDefinition wait for the cluster to adopt them
+	clusterDefUpdatePending bool
 }
 
 var _ Component = &rsmComponent{}
@@ -91,15 +94,17 @@ func newRSMComponent(cli client.Client,
 	cluster *appsv1alpha1.Cluster,
 	clusterVersion *appsv1alpha1.ClusterVersion,
 	synthesizedComponent *component.SynthesizedComponent,
-	dag *graph.DAG) Component {
+	dag *graph.DAG,
+	clusterDefUpdatePending bool) Component {
 	comp := &rsmComponent{
-		Client:         cli,
-		Recorder:       recorder,
-		Cluster:        cluster,
-		clusterVersion: clusterVersion,
-		component:      synthesizedComponent,
-		dag:            dag,
-		workloadVertex: nil,
+		Client:                  cli,
+		Recorder:                recorder,
+		Cluster:                 cluster,
+		clusterVersion:          clusterVersion,
+		component:               synthesizedComponent,
+		dag:                     dag,
+		workloadVertex:          nil,
+		clusterDefUpdatePending: clusterDefUpdatePending,
 	}
 	return comp
 }
@@ -232,6 +237,10 @@ func (c *rsmComponent) create(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		return err
 	}
 
+	if err := c.adoptPVCs(reqCtx, cli); err != nil {
+		return err
+	}
+
 	if err := c.validateObjectsAction(); err != nil {
 		return err
 	}
@@ -244,7 +253,20 @@ func (c *rsmComponent) update(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		return err
 	}
 
+	// the workloads of a paused component, or a component waiting to adopt the ClusterDefinition changes,
+	// are frozen, and keep the running ones as they are.
+	if c.runningWorkload != nil && c.isFrozen() {
+		c.workloadVertex.Obj = c.runningWorkload
+		c.workloadVertex.Action = ictrltypes.ActionNoopPtr()
+		return c.resolveObjectsAction(reqCtx, cli)
+	}
+
 	if c.runningWorkload != nil {
+		// the volume claim templates of the running workload are immutable, except the storage size and the removal
+		if err := c.checkImmutableVolumeClaimTemplates(); err != nil {
+			return err
+		}
+
 		if err := c.restart(reqCtx, cli); err != nil {
 			return err
 		}
@@ -258,6 +280,15 @@ func (c *rsmComponent) update(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		if err := c.horizontalScale(reqCtx, cli); err != nil {
 			return err
 		}
+
+		// cluster.spec.componentSpecs[*].volumeClaimTemplates, the removed ones
+		if err := c.removeVolumes(reqCtx, cli); err != nil {
+			return err
+		}
+	}
+
+	if err := c.adoptPVCs(reqCtx, cli); err != nil {
+		return err
 	}
 
 	if err := c.updateUnderlyingResources(reqCtx, cli, c.runningWorkload); err != nil {
@@ -275,6 +306,8 @@ func (c *rsmComponent) status(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		return nil
 	}
 
+	c.updateObservedGeneration()
+
 	isDeleting := func() bool {
 		return !c.runningWorkload.DeletionTimestamp.IsZero()
 	}()
@@ -292,6 +325,7 @@ func (c *rsmComponent) status(reqCtx intctrlutil.RequestCtx, cli client.Client,
 	if err != nil {
 		return err
 	}
+	isApplicationReady, notReadyMessage := c.isApplicationReady(pods)
 	isAllConfigSynced := c.isAllConfigSynced(reqCtx, cli)
 	hasFailedPod, messages, err := c.hasFailedPod(reqCtx, cli, pods)
 	if err != nil {
@@ -312,10 +346,10 @@ func (c *rsmComponent) status(reqCtx intctrlutil.RequestCtx, cli client.Client,
 	if err != nil {
 		return err
 	}
-	isInCreatingPhase := func() bool {
-		phase := c.getComponentStatus().Phase
-		return phase == "" || phase == appsv1alpha1.CreatingClusterCompPhase
-	}()
+	isInCreatingPhase := c.isInCreatingPhase()
+	hasOOMKilled, oomKilledMessages, oomRequeueAfter := c.checkOOMKilled(pods, time.Now())
+	hasSplitBrain, splitBrainMessages, splitBrainRequeueAfter := c.checkSplitBrain(reqCtx, pods, time.Now())
+	isMinReadySatisfied, minReadyRequeueAfter := c.isMinReadySatisfied(pods, time.Now())
 
 	updatePodsReady := func(ready bool) {
 		_ = c.updateStatus("", func(status *appsv1alpha1.ClusterComponentStatus) error {
@@ -342,9 +376,19 @@ func (c *rsmComponent) status(reqCtx intctrlutil.RequestCtx, cli client.Client,
 	case isZeroReplica:
 		c.setStatusPhase(appsv1alpha1.StoppedClusterCompPhase, nil, "component is Stopped")
 		podsReady = true
-	case isRunning && isAllConfigSynced && !hasRunningVolumeExpansion:
+	case hasSplitBrain:
+		c.setStatusPhase(appsv1alpha1.AbnormalClusterCompPhase, splitBrainMessages, "component is Abnormal")
+	case hasOOMKilled:
+		c.setStatusPhase(appsv1alpha1.AbnormalClusterCompPhase, oomKilledMessages, "component is Abnormal")
+	case isRunning && isApplicationReady && isAllConfigSynced && !hasRunningVolumeExpansion && isMinReadySatisfied:
 		c.setStatusPhase(appsv1alpha1.RunningClusterCompPhase, nil, "component is Running")
 		podsReady = true
+	case isRunning && isApplicationReady && !hasFailure && !isMinReadySatisfied:
+		c.setStatusPhase(c.getMinReadyPendingPhase(isInCreatingPhase), nil, c.minReadyMessage())
+	case isRunning && !isApplicationReady && !hasFailure && isInCreatingPhase:
+		c.setStatusPhase(appsv1alpha1.CreatingClusterCompPhase, nil, notReadyMessage)
+	case isRunning && !isApplicationReady && !hasFailure:
+		c.setStatusPhase(appsv1alpha1.UpdatingClusterCompPhase, nil, notReadyMessage)
 	case !hasFailure && isInCreatingPhase:
 		c.setStatusPhase(appsv1alpha1.CreatingClusterCompPhase, nil, "Create a new component")
 	case !hasFailure:
@@ -358,6 +402,29 @@ func (c *rsmComponent) status(reqCtx intctrlutil.RequestCtx, cli client.Client,
 
 	c.updateMembersStatus()
 
+	if err := c.updateConfigVersions(reqCtx, cli, pods); err != nil {
+		return err
+	}
+
+	podsSummary := summarizePods(pods, c.component.Replicas)
+	podNodes, sharedNodes := summarizePodNodes(pods)
+	isPaused := c.isPaused()
+	volumeCapacities, err := c.getVolumeCapacities(reqCtx, cli)
+	if err != nil {
+		return err
+	}
+	_ = c.updateStatus("", func(status *appsv1alpha1.ClusterComponentStatus) error {
+		status.PodsSummary = podsSummary
+		status.PodNodes = podNodes
+		status.SharedNodes = sharedNodes
+		status.Paused = isPaused
+		status.VolumeCapacities = volumeCapacities
+		return nil
+	})
+	if c.isFrozen() {
+		return nil
+	}
+
 	// works should continue to be done after spec updated.
 	if err := c.horizontalScale(reqCtx, cli); err != nil {
 		return err
@@ -370,15 +437,164 @@ func (c *rsmComponent) status(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		return err
 	}
 
+	// refresh the eviction protection of pods as their roles change
+	if err := updateEvictionProtectionToPods(reqCtx.Ctx, cli, c.Cluster, c.component, c.runningWorkload.Spec.Roles, c.dag); err != nil {
+		return err
+	}
+
 	// patch the current componentSpec workload's custom labels
 	if err := updateCustomLabelToPods(reqCtx.Ctx, cli, c.Cluster, c.component, c.dag); err != nil {
 		reqCtx.Event(c.Cluster, corev1.EventTypeWarning, "component Workload Controller PatchWorkloadCustomLabelFailed", err.Error())
 		return err
 	}
 
+	// refresh the status once the pods have been ready for minReadySeconds, the out-of-memory kills expire, or the
+	// split-brain persists, even if nothing else changes.
+	var (
+		requeueAfter   time.Duration
+		requeueMessage string
+	)
+	for _, requeue := range []struct {
+		after   time.Duration
+		message string
+	}{
+		{minReadyRequeueAfter, c.minReadyMessage()},
+		{oomRequeueAfter, "waiting for the out-of-memory kills to expire"},
+		{splitBrainRequeueAfter, "checking the split-brain of the primaries"},
+	} {
+		if requeue.after > 0 && (requeueAfter == 0 || requeue.after < requeueAfter) {
+			requeueAfter, requeueMessage = requeue.after, requeue.message
+		}
+	}
+	if requeueAfter > 0 {
+		return intctrlutil.NewDelayedRequeueError(requeueAfter, requeueMessage)
+	}
 	return nil
 }
 
+// isMinReadySatisfied checks whether all the pods have been ready for at least minReadySeconds of the component,
+// the ready time of a pod is reset once its containers crash. It returns the duration after which the pods
+// being ready now will satisfy the window, which is zero if satisfied or some pods are not ready.
+func (c *rsmComponent) isMinReadySatisfied(pods []*corev1.Pod, now time.Time) (bool, time.Duration) {
+	minReadySeconds := c.component.MinReadySeconds
+	if minReadySeconds <= 0 {
+		return true, 0
+	}
+	var requeueAfter time.Duration
+	for _, pod := range pods {
+		condition := intctrlutil.GetPodCondition(&pod.Status, corev1.PodReady)
+		if condition == nil || condition.Status != corev1.ConditionTrue {
+			return false, 0
+		}
+		// the pods whose ready time is unknown are taken as available.
+		if condition.LastTransitionTime.IsZero() {
+			continue
+		}
+		availableAfter := condition.LastTransitionTime.Add(time.Duration(minReadySeconds) * time.Second).Sub(now)
+		if availableAfter > requeueAfter {
+			requeueAfter = availableAfter
+		}
+	}
+	return requeueAfter == 0, requeueAfter
+}
+
+// getMinReadyPendingPhase returns the phase of the component whose pods are ready but not for minReadySeconds yet.
+// The pods restarted out of a rollout, e.g. crashed and passed the readiness again, are not taken as updating.
+func (c *rsmComponent) getMinReadyPendingPhase(isInCreatingPhase bool) appsv1alpha1.ClusterComponentPhase {
+	switch {
+	case isInCreatingPhase:
+		return appsv1alpha1.CreatingClusterCompPhase
+	case isWorkloadUpdating(c.runningWorkload):
+		return appsv1alpha1.UpdatingClusterCompPhase
+	default:
+		return appsv1alpha1.AbnormalClusterCompPhase
+	}
+}
+
+func (c *rsmComponent) minReadyMessage() string {
+	return fmt.Sprintf("waiting for the pods to be ready for at least %d seconds", c.component.MinReadySeconds)
+}
+
+// isInCreatingPhase checks whether the component is still being created for the first time, a rollout of the
+// workload after its first build is treated as updating even if the component hasn't been running yet.
+func (c *rsmComponent) isInCreatingPhase() bool {
+	phase := c.getComponentStatus().Phase
+	if phase != "" && phase != appsv1alpha1.CreatingClusterCompPhase {
+		return false
+	}
+	return !isWorkloadUpdating(c.runningWorkload)
+}
+
+// isWorkloadUpdating checks whether the workload is rolling out a new spec after its first build, that is, the
+// spec change hasn't been sent to the underlying workload yet, or the pods are being replaced.
+func isWorkloadUpdating(rsm *workloads.ReplicatedStateMachine) bool {
+	if rsm == nil || rsm.Status.CurrentGeneration == 0 {
+		return false
+	}
+	if rsm.Status.ObservedGeneration < rsm.Generation || rsm.Status.CurrentGeneration < rsm.Generation {
+		return true
+	}
+	return rsm.Status.CurrentRevision != "" && rsm.Status.CurrentRevision != rsm.Status.UpdateRevision
+}
+
+// updateObservedGeneration records the cluster generation in which the last spec change of the component is reconciled.
+// It's read from the running workload, so it advances only after the workload carrying the spec change is persisted.
+func (c *rsmComponent) updateObservedGeneration() {
+	annotations := c.runningWorkload.GetAnnotations()
+	specHash, err := c.getComponentSpecHash()
+	if err != nil || annotations[constant.ComponentSpecHashAnnotationKey] != specHash {
+		return
+	}
+	generation, err := strconv.ParseInt(annotations[constant.ComponentSpecGenerationAnnotationKey], 10, 64)
+	if err != nil {
+		return
+	}
+	_ = c.updateStatus("", func(status *appsv1alpha1.ClusterComponentStatus) error {
+		status.ObservedGeneration = generation
+		return nil
+	})
+}
+
+// getComponentSpecHash computes the hash of the component spec in the cluster.
+func (c *rsmComponent) getComponentSpecHash() (string, error) {
+	compSpec := c.Cluster.Spec.GetComponentByName(c.GetName())
+	if compSpec == nil {
+		return "", fmt.Errorf("component %s is not found in cluster %s", c.GetName(), c.GetClusterName())
+	}
+	return util.ComputeHash(compSpec)
+}
+
+// stampComponentSpec records the hash of the component spec on the workload, and the cluster generation
+// in which the hash changes, any spec change of the component advances the generation.
+func (c *rsmComponent) stampComponentSpec(rsmObj *workloads.ReplicatedStateMachine) {
+	specHash, err := c.getComponentSpecHash()
+	if err != nil {
+		return
+	}
+	annotations := rsmObj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[constant.ComponentSpecHashAnnotationKey] == specHash &&
+		len(annotations[constant.ComponentSpecGenerationAnnotationKey]) > 0 {
+		return
+	}
+	annotations[constant.ComponentSpecHashAnnotationKey] = specHash
+	annotations[constant.ComponentSpecGenerationAnnotationKey] = strconv.FormatInt(c.Cluster.Generation, 10)
+	rsmObj.SetAnnotations(annotations)
+}
+
+// isPaused checks whether the component is paused, the workloads of a paused component will not be updated.
+func (c *rsmComponent) isPaused() bool {
+	return c.Cluster.IsComponentPaused(c.GetName())
+}
+
+// isFrozen checks whether the workloads of the component are frozen, either the component is paused or the changes
+// of the ClusterDefinition are not adopted by the cluster yet.
+func (c *rsmComponent) isFrozen() bool {
+	return c.isPaused() || c.clusterDefUpdatePending
+}
+
 func (c *rsmComponent) createResource(obj client.Object, parent *ictrltypes.LifecycleVertex) *ictrltypes.LifecycleVertex {
 	return ictrltypes.LifecycleObjectCreate(c.dag, obj, parent)
 }
@@ -515,6 +731,36 @@ func (c *rsmComponent) isRunning(ctx context.Context, cli client.Client, obj cli
 	return rsmcore.IsRSMReady(rsm), nil
 }
 
+// isApplicationReady checks the readiness of the application beyond the pods ready, the consensus component
+// is ready only if all pods have joined the members and the leader has been elected.
+// It returns the message of the reason if not ready.
+func (c *rsmComponent) isApplicationReady(pods []*corev1.Pod) (bool, string) {
+	// the roles are unknown in lightweight mode as the role probe is omitted.
+	if c.component.WorkloadType != appsv1alpha1.Consensus || c.component.LightweightMode || c.runningWorkload == nil {
+		return true, ""
+	}
+	members := map[string]workloads.MemberStatus{}
+	for _, member := range c.runningWorkload.Status.MembersStatus {
+		members[member.PodName] = member
+	}
+	var notJoined []string
+	for _, pod := range pods {
+		if _, ok := members[pod.Name]; !ok {
+			notJoined = append(notJoined, pod.Name)
+		}
+	}
+	if len(notJoined) > 0 {
+		slices.Sort(notJoined)
+		return false, fmt.Sprintf("waiting for the pods %s to join the members", strings.Join(notJoined, ","))
+	}
+	for _, member := range members {
+		if member.IsLeader {
+			return true, ""
+		}
+	}
+	return false, "waiting for the leader to be elected"
+}
+
 // isAvailable tells whether the component is basically available, ether working well or in a fragile state:
 // 1. at least one pod is available
 // 2. with latest revision
@@ -526,8 +772,10 @@ func (c *rsmComponent) isAvailable(reqCtx intctrlutil.RequestCtx, cli client.Cli
 		return false, nil
 	}
 
+	// the roles are unknown in lightweight mode as the role probe is omitted.
 	shouldCheckLeader := func() bool {
-		return c.component.WorkloadType == appsv1alpha1.Consensus || c.component.WorkloadType == appsv1alpha1.Replication
+		return (c.component.WorkloadType == appsv1alpha1.Consensus || c.component.WorkloadType == appsv1alpha1.Replication) &&
+			!c.component.LightweightMode
 	}()
 	hasLeaderRoleLabel := func(pod *corev1.Pod) bool {
 		roleName, ok := pod.Labels[constant.RoleLabelKey]
@@ -572,8 +820,9 @@ func (c *rsmComponent) hasFailedPod(reqCtx intctrlutil.RequestCtx, cli client.Cl
 		messages = msg
 		return true, messages, nil
 	}
-	// check role probe
-	if c.component.WorkloadType != appsv1alpha1.Consensus && c.component.WorkloadType != appsv1alpha1.Replication {
+	// check role probe, which is omitted in lightweight mode
+	if c.component.WorkloadType != appsv1alpha1.Consensus && c.component.WorkloadType != appsv1alpha1.Replication ||
+		c.component.LightweightMode {
 		return false, messages, nil
 	}
 	hasProbeTimeout := false
@@ -598,17 +847,18 @@ func (c *rsmComponent) hasFailedPod(reqCtx intctrlutil.RequestCtx, cli client.Cl
 	return hasProbeTimeout, messages, nil
 }
 
-func (c *rsmComponent) isAllConfigSynced(reqCtx intctrlutil.RequestCtx, cli client.Client) bool {
-	checkFinishedReconfigure := func(cm *corev1.ConfigMap) bool {
-		labels := cm.GetLabels()
-		annotations := cm.GetAnnotations()
-		if len(annotations) == 0 || len(labels) == 0 {
-			return false
-		}
-		hash, _ := util.ComputeHash(cm.Data)
-		return labels[constant.CMInsConfigurationHashLabelKey] == hash
+// isReconfigureFinished checks whether the latest rendered config has been applied by the reconfigure controller.
+func isReconfigureFinished(cm *corev1.ConfigMap) bool {
+	labels := cm.GetLabels()
+	annotations := cm.GetAnnotations()
+	if len(annotations) == 0 || len(labels) == 0 {
+		return false
 	}
+	hash, _ := util.ComputeHash(cm.Data)
+	return labels[constant.CMInsConfigurationHashLabelKey] == hash
+}
 
+func (c *rsmComponent) isAllConfigSynced(reqCtx intctrlutil.RequestCtx, cli client.Client) bool {
 	var (
 		cmKey           client.ObjectKey
 		cmObj           = &corev1.ConfigMap{}
@@ -622,7 +872,7 @@ func (c *rsmComponent) isAllConfigSynced(reqCtx intctrlutil.RequestCtx, cli clie
 		if err := cli.Get(reqCtx.Ctx, cmKey, cmObj); err != nil {
 			return true
 		}
-		if !checkFinishedReconfigure(cmObj) {
+		if !isReconfigureFinished(cmObj) {
 			allConfigSynced = false
 			break
 		}
@@ -630,6 +880,60 @@ func (c *rsmComponent) isAllConfigSynced(reqCtx intctrlutil.RequestCtx, cli clie
 	return allConfigSynced
 }
 
+// updateConfigVersions records the versions of the configs the pods are running with, and whether all the pods
+// are running with the latest rendered configs.
+func (c *rsmComponent) updateConfigVersions(reqCtx intctrlutil.RequestCtx, cli client.Client, pods []*corev1.Pod) error {
+	if len(c.component.ConfigTemplates) == 0 {
+		return nil
+	}
+
+	configVersions := make(map[string][]string, len(pods))
+	latestVersions := make([]string, 0, len(c.component.ConfigTemplates))
+	for _, configSpec := range c.component.ConfigTemplates {
+		cmKey := client.ObjectKey{
+			Namespace: c.GetNamespace(),
+			Name:      cfgcore.GetComponentCfgName(c.GetClusterName(), c.GetName(), configSpec.Name),
+		}
+		cmObj := &corev1.ConfigMap{}
+		if err := cli.Get(reqCtx.Ctx, cmKey, cmObj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		latestVersion, err := util.ComputeHash(cmObj.Data)
+		if err != nil {
+			return err
+		}
+		latestVersions = append(latestVersions, latestVersion)
+
+		// once the latest config is applied, the pods without the version recorded, e.g. the ones created after
+		// the reconfiguring, are running with the latest config, and so are all the pods if the config is reloaded
+		// by the config-manager automatically, which doesn't record the versions to the pods.
+		finished := isReconfigureFinished(cmObj)
+		autoReloaded := finished && cmObj.GetLabels()[constant.CMInsLastReconfigurePhaseKey] == cfgcore.ReconfigureAutoReloadPhase
+		for _, pod := range pods {
+			version := cfgcore.GetPodConfigVersion(pod, configSpec.Name)
+			if autoReloaded || (finished && version == "") {
+				version = latestVersion
+			}
+			configVersions[pod.Name] = append(configVersions[pod.Name], version)
+		}
+	}
+
+	latestVersion := cfgcore.JoinConfigVersions(latestVersions)
+	configSynced := true
+	podConfigVersions := make(map[string]string, len(pods))
+	for podName, versions := range configVersions {
+		podConfigVersions[podName] = cfgcore.JoinConfigVersions(versions)
+		if podConfigVersions[podName] != latestVersion {
+			configSynced = false
+		}
+	}
+	return c.updateStatus("", func(status *appsv1alpha1.ClusterComponentStatus) error {
+		status.ConfigVersions = podConfigVersions
+		status.ConfigSynced = &configSynced
+		return nil
+	})
+}
+
 func (c *rsmComponent) updateMembersStatus() {
 	// get component status
 	componentStatus := c.getComponentStatus()
@@ -680,12 +984,23 @@ func (c *rsmComponent) updateMembersStatus() {
 		return replicationSetStatus
 	}
 
+	getLeaderPod := func(membersStatus []workloads.MemberStatus) string {
+		for _, memberStatus := range membersStatus {
+			if memberStatus.IsLeader {
+				return memberStatus.PodName
+			}
+		}
+		return ""
+	}
+
 	// update members status
 	switch c.component.WorkloadType {
 	case appsv1alpha1.Consensus:
 		componentStatus.ConsensusSetStatus = buildConsensusSetStatus(c.runningWorkload.Status.MembersStatus)
+		componentStatus.LeaderPod = getLeaderPod(c.runningWorkload.Status.MembersStatus)
 	case appsv1alpha1.Replication:
 		componentStatus.ReplicationSetStatus = buildReplicationSetStatus(c.runningWorkload.Status.MembersStatus)
+		componentStatus.LeaderPod = getLeaderPod(c.runningWorkload.Status.MembersStatus)
 	}
 	componentStatus.MembersStatus = slices.Clone(c.runningWorkload.Status.MembersStatus)
 
@@ -771,7 +1086,7 @@ func (c *rsmComponent) expandVolumes(reqCtx intctrlutil.RequestCtx, cli client.C
 		pvc := &corev1.PersistentVolumeClaim{}
 		pvcKey := types.NamespacedName{
 			Namespace: c.GetNamespace(),
-			Name:      fmt.Sprintf("%s-%s-%d", vctName, c.runningWorkload.Name, i),
+			Name:      fmt.Sprintf("%s-%s-%d", vctName, rsmcore.GetStatefulSetName(c.runningWorkload), c.component.OrdinalStart+i),
 		}
 		if err := cli.Get(reqCtx.Ctx, pvcKey, pvc); err != nil {
 			if apierrors.IsNotFound(err) {
@@ -920,7 +1235,7 @@ func (c *rsmComponent) updatePVCSize(reqCtx intctrlutil.RequestCtx, cli client.C
 		updatePVCByRecreateFromStep(pvRestorePolicyStep)
 		return nil
 	}
-	if pvcQuantity := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !viper.GetBool(constant.CfgRecoverVolumeExpansionFailure) &&
+	if pvcQuantity := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !featuregate.Enabled(featuregate.RecoverVolumeExpansionFailure) &&
 		pvcQuantity.Cmp(targetQuantity) == 1 && // check if it's compressing volume
 		targetQuantity.Cmp(*pvc.Status.Capacity.Storage()) >= 0 { // check if target size is greater than or equal to actual size
 		// this branch means we can update pvc size by recreate it
@@ -958,6 +1273,36 @@ func (c *rsmComponent) hasVolumeExpansionRunning(reqCtx intctrlutil.RequestCtx,
 	return running, failed, nil
 }
 
+// getVolumeCapacities returns the requested and the actual capacities of the bound PVCs of the replicas,
+// ordered by the pod names and the volume claim templates.
+func (c *rsmComponent) getVolumeCapacities(reqCtx intctrlutil.RequestCtx, cli client.Client) ([]appsv1alpha1.VolumeCapacityStatus, error) {
+	var capacities []appsv1alpha1.VolumeCapacityStatus
+	for _, vct := range c.runningWorkload.Spec.VolumeClaimTemplates {
+		volumes, err := c.getRunningVolumes(reqCtx, cli, vct.Name, c.runningWorkload)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range volumes {
+			if v.Status.Phase != corev1.ClaimBound {
+				continue
+			}
+			capacities = append(capacities, appsv1alpha1.VolumeCapacityStatus{
+				PodName:                 strings.TrimPrefix(v.Name, vct.Name+"-"),
+				VolumeClaimTemplateName: vct.Name,
+				Requested:               v.Spec.Resources.Requests[corev1.ResourceStorage],
+				Capacity:                v.Status.Capacity[corev1.ResourceStorage],
+			})
+		}
+	}
+	slices.SortFunc(capacities, func(a, b appsv1alpha1.VolumeCapacityStatus) bool {
+		if a.PodName != b.PodName {
+			return a.PodName < b.PodName
+		}
+		return a.VolumeClaimTemplateName < b.VolumeClaimTemplateName
+	})
+	return capacities, nil
+}
+
 func (c *rsmComponent) horizontalScale(reqCtx intctrlutil.RequestCtx, cli client.Client) error {
 	sts := ConvertRSMToSTS(c.runningWorkload)
 	if sts.Status.ReadyReplicas == c.component.Replicas {
@@ -1089,7 +1434,7 @@ func (c *rsmComponent) deletePVCs4ScaleIn(reqCtx intctrlutil.RequestCtx, cli cli
 		for _, vct := range stsObj.Spec.VolumeClaimTemplates {
 			pvcKey := types.NamespacedName{
 				Namespace: stsObj.Namespace,
-				Name:      fmt.Sprintf("%s-%s-%d", vct.Name, stsObj.Name, i),
+				Name:      fmt.Sprintf("%s-%s-%d", vct.Name, stsObj.Name, c.component.OrdinalStart+i),
 			}
 			pvc := corev1.PersistentVolumeClaim{}
 			if err := cli.Get(reqCtx.Ctx, pvcKey, &pvc); err != nil {
@@ -1198,6 +1543,7 @@ func (c *rsmComponent) updateUnderlyingResources(reqCtx intctrlutil.RequestCtx,
 func (c *rsmComponent) createWorkload() {
 	rsmProto := c.workloadVertex.Obj.(*workloads.ReplicatedStateMachine)
 	buildWorkLoadAnnotations(rsmProto, c.Cluster)
+	c.stampComponentSpec(rsmProto)
 	c.workloadVertex.Obj = rsmProto
 	c.workloadVertex.Action = ictrltypes.ActionCreatePtr()
 }
@@ -1215,6 +1561,7 @@ func (c *rsmComponent) updateWorkload(rsmObj *workloads.ReplicatedStateMachine)
 	mergeAnnotations(rsmObjCopy.Annotations, &rsmProto.Annotations)
 	rsmObjCopy.Annotations = rsmProto.Annotations
 	buildWorkLoadAnnotations(rsmObjCopy, c.Cluster)
+	c.stampComponentSpec(rsmObjCopy)
 
 	// keep the original template annotations.
 	// if annotations exist and are replaced, the rsm will be updated.
@@ -1327,6 +1674,244 @@ func (c *rsmComponent) getRunningVolumes(reqCtx intctrlutil.RequestCtx, cli clie
 	return matchedPVCs, nil
 }
 
+// removeVolumes handles the PVCs whose volume claim templates are removed from the component, according to the
+// volume claim retention policy: they are deleted under the Delete policy once they are released by the workload
+// and the pods, and kept untouched under the Retain policy.
+func (c *rsmComponent) removeVolumes(reqCtx intctrlutil.RequestCtx, cli client.Client) error {
+	pvcs, err := c.getRemovedVolumes(reqCtx, cli)
+	if err != nil || len(pvcs) == 0 {
+		return err
+	}
+
+	// PVCs which have been added to the dag, e.g. because of volume expansion.
+	pvcNameSet := sets.New[string]()
+	for _, v := range ictrltypes.FindAll[*corev1.PersistentVolumeClaim](c.dag) {
+		pvcNameSet.Insert(v.(*ictrltypes.LifecycleVertex).Obj.GetName())
+	}
+
+	var (
+		workloadVCTs sets.Set[string]
+		mountedPVCs  sets.Set[string]
+	)
+	if c.component.VolumeClaimRetentionPolicy == appsv1alpha1.DeleteVolumeClaimRetentionPolicy {
+		if workloadVCTs, err = c.getWorkloadVolumeClaimTemplates(reqCtx, cli); err != nil {
+			return err
+		}
+		if mountedPVCs, err = c.getMountedVolumes(reqCtx, cli); err != nil {
+			return err
+		}
+	}
+
+	for _, pvc := range pvcs {
+		if pvcNameSet.Has(pvc.Name) {
+			continue
+		}
+		vctName := pvc.Labels[constant.VolumeClaimTemplateNameLabelKey]
+		if c.component.VolumeClaimRetentionPolicy != appsv1alpha1.DeleteVolumeClaimRetentionPolicy {
+			reqCtx.Log.V(1).Info("retain the PVC of the removed volume claim template", "pvc", pvc.Name, "template", vctName)
+			c.noopResource(pvc, c.workloadVertex)
+			continue
+		}
+		// the StatefulSet recreates the PVCs of its volume claim templates, and the PVCs mounted by the pods are
+		// in use, they are deleted once the workload has dropped the template and the pods have released them.
+		if workloadVCTs.Has(vctName) || mountedPVCs.Has(pvc.Name) {
+			reqCtx.Log.V(1).Info("wait for the PVC of the removed volume claim template to be released", "pvc", pvc.Name, "template", vctName)
+			c.noopResource(pvc, c.workloadVertex)
+			continue
+		}
+		c.deleteResource(pvc, c.workloadVertex)
+		reqCtx.Log.Info("delete the PVC of the removed volume claim template", "pvc", pvc.Name, "template", vctName)
+		if c.Recorder != nil {
+			c.Recorder.Eventf(c.Cluster, corev1.EventTypeNormal, constant.ReasonRemovedVolumeClaimDeleted,
+				"delete the PVC %s of component %s as the volume claim template %s is removed", pvc.Name, c.GetName(), vctName)
+		}
+	}
+	return nil
+}
+
+// checkImmutableVolumeClaimTemplates checks whether the volume claim templates are changed in the way the underlying
+// StatefulSet rejects, the storage size changes are applied by resizing the PVCs, and the PVCs of the removed templates
+// are handled by the retention policy. An UpdateRejectedError with the offending fields is returned otherwise,
+// which is surfaced in the ApplyResources condition and retried at a fixed interval.
+func (c *rsmComponent) checkImmutableVolumeClaimTemplates() error {
+	rsmProto := c.workloadVertex.Obj.(*workloads.ReplicatedStateMachine)
+	fields := getImmutableVolumeClaimTemplateChanges(c.runningWorkload.Spec.VolumeClaimTemplates, rsmProto.Spec.VolumeClaimTemplates)
+	if len(fields) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("the volume claim templates of component %s can only be resized or removed", c.GetName())
+	return intctrlutil.NewUpdateRejectedError(constant.RSMKind, c.runningWorkload.Name, fields, err)
+}
+
+// getImmutableVolumeClaimTemplateChanges gets the paths of the immutable fields of the volume claim templates changed,
+// the templates added are immutable changes as well.
+func getImmutableVolumeClaimTemplateChanges(running, desired []corev1.PersistentVolumeClaim) []string {
+	var fields []string
+	for _, vct := range desired {
+		path := fmt.Sprintf("spec.volumeClaimTemplates[%s]", vct.Name)
+		idx := slices.IndexFunc(running, func(pvc corev1.PersistentVolumeClaim) bool {
+			return pvc.Name == vct.Name
+		})
+		if idx < 0 {
+			fields = append(fields, path)
+			continue
+		}
+		runningSpec, desiredSpec := running[idx].Spec, vct.Spec
+		if !reflect.DeepEqual(runningSpec.StorageClassName, desiredSpec.StorageClassName) {
+			fields = append(fields, path+".spec.storageClassName")
+		}
+		if !reflect.DeepEqual(runningSpec.AccessModes, desiredSpec.AccessModes) {
+			fields = append(fields, path+".spec.accessModes")
+		}
+		if desiredSpec.VolumeMode != nil && !reflect.DeepEqual(runningSpec.VolumeMode, desiredSpec.VolumeMode) {
+			fields = append(fields, path+".spec.volumeMode")
+		}
+	}
+	return fields
+}
+
+// getWorkloadVolumeClaimTemplates returns the names of the volume claim templates of the running workload, including
+// the ones of the underlying StatefulSet whose templates are immutable.
+func (c *rsmComponent) getWorkloadVolumeClaimTemplates(reqCtx intctrlutil.RequestCtx, cli client.Client) (sets.Set[string], error) {
+	vctNameSet := sets.New[string]()
+	for _, vct := range c.runningWorkload.Spec.VolumeClaimTemplates {
+		vctNameSet.Insert(vct.Name)
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := cli.Get(reqCtx.Ctx, client.ObjectKeyFromObject(c.runningWorkload), sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return vctNameSet, nil
+		}
+		return nil, err
+	}
+	for _, vct := range sts.Spec.VolumeClaimTemplates {
+		vctNameSet.Insert(vct.Name)
+	}
+	return vctNameSet, nil
+}
+
+// getMountedVolumes returns the names of the PVCs mounted by the pods of the component.
+func (c *rsmComponent) getMountedVolumes(reqCtx intctrlutil.RequestCtx, cli client.Client) (sets.Set[string], error) {
+	pods, err := listPodOwnedByComponent(reqCtx.Ctx, cli, c.GetNamespace(), c.getMatchingLabels())
+	if err != nil {
+		return nil, err
+	}
+	pvcNameSet := sets.New[string]()
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				pvcNameSet.Insert(volume.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+	return pvcNameSet, nil
+}
+
+// getRemovedVolumes returns the PVCs of the running workload whose volume claim templates are not declared by the
+// component any more, the ones being deleted are excluded.
+func (c *rsmComponent) getRemovedVolumes(reqCtx intctrlutil.RequestCtx, cli client.Client) ([]*corev1.PersistentVolumeClaim, error) {
+	pvcs, err := listObjWithLabelsInNamespace(reqCtx.Ctx, cli, generics.PersistentVolumeClaimSignature, c.GetNamespace(), c.getMatchingLabels())
+	if err != nil {
+		return nil, err
+	}
+	vctNameSet := sets.New[string]()
+	for _, vct := range c.component.VolumeClaimTemplates {
+		vctNameSet.Insert(vct.Name)
+	}
+	removedPVCs := make([]*corev1.PersistentVolumeClaim, 0)
+	for _, pvc := range pvcs {
+		vctName, ok := pvc.Labels[constant.VolumeClaimTemplateNameLabelKey]
+		if !ok || vctNameSet.Has(vctName) || pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if !strings.HasPrefix(pvc.Name, fmt.Sprintf("%s-%s-", vctName, c.runningWorkload.Name)) {
+			continue
+		}
+		removedPVCs = append(removedPVCs, pvc)
+	}
+	return removedPVCs, nil
+}
+
+// adoptPVCs adopts the unowned PVCs pre-created with the names the workload expects, e.g. in the migrations,
+// by labeling them as the ones created by the component, and the ownership is set along with the other objects.
+// The PVCs owned by others, or with the specs conflicting with the volume claim templates, are left untouched.
+func (c *rsmComponent) adoptPVCs(reqCtx intctrlutil.RequestCtx, cli client.Client) error {
+	// PVCs which have been added to the dag, e.g. because of volume expansion.
+	pvcNameSet := sets.New[string]()
+	for _, v := range ictrltypes.FindAll[*corev1.PersistentVolumeClaim](c.dag) {
+		pvcNameSet.Insert(v.(*ictrltypes.LifecycleVertex).Obj.GetName())
+	}
+
+	workloadName := rsmcore.GetStatefulSetName(c.workloadVertex.Obj.(*workloads.ReplicatedStateMachine))
+	for i := range c.component.VolumeClaimTemplates {
+		vct := &c.component.VolumeClaimTemplates[i]
+		for j := int32(0); j < c.component.Replicas; j++ {
+			pvcKey := types.NamespacedName{
+				Namespace: c.GetNamespace(),
+				Name:      fmt.Sprintf("%s-%s-%d", vct.Name, workloadName, c.component.OrdinalStart+j),
+			}
+			if pvcNameSet.Has(pvcKey.Name) {
+				continue
+			}
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := cli.Get(reqCtx.Ctx, pvcKey, pvc); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			// the PVCs created by KubeBlocks or the workloads are labeled already.
+			if _, ok := pvc.Labels[constant.AppInstanceLabelKey]; ok {
+				continue
+			}
+			if reason := getPVCAdoptionConflict(pvc, vct); len(reason) > 0 {
+				reqCtx.Log.Info("skip to adopt the PVC", "pvc", pvc.Name, "reason", reason)
+				if c.Recorder != nil {
+					c.Recorder.Eventf(c.Cluster, corev1.EventTypeWarning, constant.ReasonPVCAdoptionSkipped,
+						"skip to adopt the PVC %s of component %s: %s", pvc.Name, c.GetName(), reason)
+				}
+				continue
+			}
+
+			pvcCopy := pvc.DeepCopy()
+			if pvcCopy.Labels == nil {
+				pvcCopy.Labels = map[string]string{}
+			}
+			for k, v := range factory.BuildCommonLabels(c.Cluster, c.component) {
+				pvcCopy.Labels[k] = v
+			}
+			factory.BuildPersistentVolumeClaimLabels(c.component, pvcCopy, vct.Name)
+			c.patchResource(pvcCopy, pvc, c.workloadVertex)
+			if c.Recorder != nil {
+				c.Recorder.Eventf(c.Cluster, corev1.EventTypeNormal, constant.ReasonAdoptedPVC,
+					"adopted the pre-existing PVC %s for component %s", pvc.Name, c.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+// getPVCAdoptionConflict returns the reason why the PVC can't be adopted for the volume claim template,
+// and an empty reason if they match.
+func getPVCAdoptionConflict(pvc *corev1.PersistentVolumeClaim, vct *corev1.PersistentVolumeClaimTemplate) string {
+	if owner := metav1.GetControllerOf(pvc); owner != nil {
+		return fmt.Sprintf("it's owned by %s %s", owner.Kind, owner.Name)
+	}
+	if vct.Spec.StorageClassName != nil && len(*vct.Spec.StorageClassName) > 0 &&
+		(pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != *vct.Spec.StorageClassName) {
+		return fmt.Sprintf("the storage class doesn't match %s", *vct.Spec.StorageClassName)
+	}
+	for _, mode := range vct.Spec.AccessModes {
+		if !slices.Contains(pvc.Spec.AccessModes, mode) {
+			return fmt.Sprintf("the access mode %s is not supported", mode)
+		}
+	}
+	if vct.Spec.VolumeMode != nil && pvc.Spec.VolumeMode != nil && *vct.Spec.VolumeMode != *pvc.Spec.VolumeMode {
+		return fmt.Sprintf("the volume mode doesn't match %s", *vct.Spec.VolumeMode)
+	}
+	return ""
+}
+
 // hasFailedAndTimedOutPod returns whether the pods of components are still failed after a PodFailedTimeout period.
 func hasFailedAndTimedOutPod(pods []*corev1.Pod) (bool, appsv1alpha1.ComponentMessageMap, time.Duration) {
 	var (
@@ -0,0 +1,172 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+const (
+	// oomKilledThreshold is the number of the out-of-memory kills of a container within oomKilledWindow
+	// to mark the component Abnormal.
+	oomKilledThreshold = 3
+	oomKilledWindow    = 10 * time.Minute
+	// oomKilledStablePeriod is the period a container should run without being killed to clear the Abnormal phase.
+	oomKilledStablePeriod = 30 * time.Minute
+
+	oomKilledReason = "OOMKilled"
+)
+
+// checkOOMKilled tracks the out-of-memory kills of the containers in the component status. The containers killed
+// by the OOM killer are restarted in place, so the workload stays available and the pods become ready again soon,
+// the kills are only observable from the terminated states and the restart counts of the containers.
+// It returns whether any container is killed repeatedly, the messages of these containers, and the duration after
+// which the records should be refreshed, which is zero if no kill is recorded.
+func (c *rsmComponent) checkOOMKilled(pods []*corev1.Pod, now time.Time) (bool, appsv1alpha1.ComponentMessageMap, time.Duration) {
+	recordKey := func(podName, containerName string) string {
+		return podName + "/" + containerName
+	}
+	records := map[string]*appsv1alpha1.OOMKilledContainerStatus{}
+	for _, record := range c.getComponentStatus().OOMKilledContainers {
+		records[recordKey(record.PodName, record.ContainerName)] = record.DeepCopy()
+	}
+
+	podMap := map[string]*corev1.Pod{}
+	for _, pod := range pods {
+		podMap[pod.Name] = pod
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.RestartCount == 0 && containerStatus.State.Terminated == nil {
+				continue
+			}
+			key := recordKey(pod.Name, containerStatus.Name)
+			// the last termination state is older than the current one.
+			for i, terminated := range []*corev1.ContainerStateTerminated{
+				containerStatus.LastTerminationState.Terminated, containerStatus.State.Terminated} {
+				if terminated == nil || terminated.Reason != oomKilledReason || terminated.FinishedAt.IsZero() {
+					continue
+				}
+				record, ok := records[key]
+				if !ok {
+					record = &appsv1alpha1.OOMKilledContainerStatus{PodName: pod.Name, ContainerName: containerStatus.Name}
+					records[key] = record
+				}
+				if n := len(record.KilledTimes); n > 0 && !terminated.FinishedAt.After(record.KilledTimes[n-1].Time) {
+					continue
+				}
+				kills := 1
+				// the container may be killed and restarted several times since the last observation, the restarts
+				// are counted as the kills, at the time of the last one as the earlier times are unknown.
+				if i == 0 && ok && containerStatus.RestartCount > record.RestartCount {
+					kills = int(containerStatus.RestartCount - record.RestartCount)
+				}
+				for ; kills > 0; kills-- {
+					record.KilledTimes = append(record.KilledTimes, terminated.FinishedAt)
+				}
+				if len(record.KilledTimes) > oomKilledThreshold {
+					record.KilledTimes = record.KilledTimes[len(record.KilledTimes)-oomKilledThreshold:]
+				}
+			}
+			if record, ok := records[key]; ok {
+				record.RestartCount = containerStatus.RestartCount
+			}
+		}
+	}
+
+	var (
+		hasOOMKilled bool
+		messages     appsv1alpha1.ComponentMessageMap
+		requeueAfter time.Duration
+		statuses     []appsv1alpha1.OOMKilledContainerStatus
+	)
+	keys := maps.Keys(records)
+	slices.Sort(keys)
+	for _, key := range keys {
+		record := records[key]
+		pod, ok := podMap[record.PodName]
+		if !ok || len(record.KilledTimes) == 0 {
+			continue
+		}
+		killedTimes := 0
+		for _, killedTime := range record.KilledTimes {
+			if now.Sub(killedTime.Time) < oomKilledWindow {
+				killedTimes++
+			}
+		}
+		lastKilledTime := record.KilledTimes[len(record.KilledTimes)-1].Time
+		if !record.Abnormal && killedTimes >= oomKilledThreshold {
+			record.Abnormal = true
+			if c.Recorder != nil {
+				c.Recorder.Eventf(c.Cluster, corev1.EventTypeWarning, constant.ReasonContainerOOMKilled,
+					"%s, consider increasing the memory of component %s by a VerticalScaling OpsRequest",
+					oomKilledMessage(pod, record.ContainerName, len(record.KilledTimes)), c.GetName())
+			}
+		}
+
+		var expireAfter time.Duration
+		if record.Abnormal {
+			expireAfter = lastKilledTime.Add(oomKilledStablePeriod).Sub(now)
+		} else {
+			expireAfter = lastKilledTime.Add(oomKilledWindow).Sub(now)
+		}
+		if expireAfter <= 0 {
+			continue
+		}
+		if requeueAfter == 0 || expireAfter < requeueAfter {
+			requeueAfter = expireAfter
+		}
+		statuses = append(statuses, *record)
+
+		if record.Abnormal {
+			hasOOMKilled = true
+			if messages == nil {
+				messages = appsv1alpha1.ComponentMessageMap{}
+			}
+			messages.SetObjectMessage(constant.PodKind, pod.Name, oomKilledMessage(pod, record.ContainerName, len(record.KilledTimes)))
+		}
+	}
+
+	_ = c.updateStatus("", func(status *appsv1alpha1.ClusterComponentStatus) error {
+		status.OOMKilledContainers = statuses
+		return nil
+	})
+	return hasOOMKilled, messages, requeueAfter
+}
+
+func oomKilledMessage(pod *corev1.Pod, containerName string, killedTimes int) string {
+	memoryLimit := "unlimited"
+	for _, container := range pod.Spec.Containers {
+		if container.Name != containerName {
+			continue
+		}
+		if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			memoryLimit = limit.String()
+		}
+	}
+	return fmt.Sprintf("OOMKilled: container %s of pod %s is killed by the out-of-memory killer %d times recently, the memory limit is %s",
+		containerName, pod.Name, killedTimes, memoryLimit)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package components
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	lorry "github.com/apecloud/kubeblocks/lorry/client"
+)
+
+const (
+	// splitBrainConfirmProbes is the number of the intervals of the role probe the split-brain should persist for
+	// to be recovered, as the roles of the old and the new primaries may overlap transiently during a failover.
+	splitBrainConfirmProbes = 3
+	// defaultRoleProbePeriodSeconds is the period of the role probe if it's not set by the component definition.
+	defaultRoleProbePeriodSeconds = 1
+)
+
+// newLorryClient creates the client of lorry in the pod, which is replaced in the tests.
+var newLorryClient = lorry.NewClient
+
+// checkSplitBrain detects the split-brain of the replication component, i.e. multiple pods claim the primary per
+// the role probe for splitBrainConfirmProbes intervals of the probe, and keeps one of them chosen by the split-brain
+// recovery policy. The others are demoted by lorry in the pods, which turns the engine to the secondary, and the role
+// probe updates the roles of the pods and the services routing to the primary in turn.
+// It returns whether a split-brain is detected, the messages of the demoted pods, and the duration after which the
+// split-brain should be checked again, which is zero if no pods claim the primary together.
+func (c *rsmComponent) checkSplitBrain(reqCtx intctrlutil.RequestCtx, pods []*corev1.Pod, now time.Time) (bool, appsv1alpha1.ComponentMessageMap, time.Duration) {
+	if c.component.WorkloadType != appsv1alpha1.Replication || c.component.LightweightMode {
+		return false, nil, 0
+	}
+	leaderRoles := map[string]bool{}
+	for _, role := range c.runningWorkload.Spec.Roles {
+		if role.IsLeader {
+			leaderRoles[role.Name] = true
+		}
+	}
+	var primaries []*corev1.Pod
+	for _, pod := range pods {
+		// the pods being deleted are leaving anyway.
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if leaderRoles[pod.Labels[constant.RoleLabelKey]] {
+			primaries = append(primaries, pod)
+		}
+	}
+	if len(primaries) < 2 {
+		c.setSplitBrainSince(nil)
+		return false, nil, 0
+	}
+
+	since := c.getComponentStatus().SplitBrainSince
+	if since == nil {
+		since = &metav1.Time{Time: now}
+		c.setSplitBrainSince(since)
+	}
+	confirmPeriod := time.Duration(splitBrainConfirmProbes*c.getRoleProbePeriodSeconds()) * time.Second
+	if wait := since.Add(confirmPeriod).Sub(now); wait > 0 {
+		return false, nil, wait
+	}
+
+	var switchPolicy *appsv1alpha1.ClusterSwitchPolicy
+	if compSpec := c.Cluster.Spec.GetComponentByName(c.GetName()); compSpec != nil {
+		switchPolicy = compSpec.SwitchPolicy
+	}
+	policy := switchPolicy.GetSplitBrainRecovery()
+	messages := appsv1alpha1.ComponentMessageMap{}
+	winner, err := c.choosePrimary(reqCtx, primaries, policy)
+	if err != nil {
+		for _, pod := range primaries {
+			messages.SetObjectMessage(constant.PodKind, pod.Name,
+				fmt.Sprintf("split-brain, failed to choose the primary to keep by the policy %s: %s", policy, err.Error()))
+		}
+		return true, messages, confirmPeriod
+	}
+	for _, loser := range primaries {
+		if loser == winner {
+			continue
+		}
+		if err = c.demotePrimary(reqCtx, loser); err != nil {
+			messages.SetObjectMessage(constant.PodKind, loser.Name,
+				fmt.Sprintf("split-brain with the primary %s, failed to demote it by the policy %s: %s", winner.Name, policy, err.Error()))
+			continue
+		}
+		messages.SetObjectMessage(constant.PodKind, loser.Name,
+			fmt.Sprintf("split-brain with the primary %s, demoted by the policy %s", winner.Name, policy))
+		if c.Recorder != nil {
+			c.Recorder.Eventf(c.Cluster, corev1.EventTypeWarning, constant.ReasonSplitBrainRecovered,
+				"pods %s and %s of component %s both claim the primary, %s is kept and %s is demoted by the policy %s",
+				winner.Name, loser.Name, c.GetName(), winner.Name, loser.Name, policy)
+		}
+	}
+	// check again until the role probe reports the demotions.
+	return true, messages, confirmPeriod
+}
+
+func (c *rsmComponent) setSplitBrainSince(since *metav1.Time) {
+	_ = c.updateStatus("", func(status *appsv1alpha1.ClusterComponentStatus) error {
+		status.SplitBrainSince = since
+		return nil
+	})
+}
+
+func (c *rsmComponent) getRoleProbePeriodSeconds() int {
+	if c.component.Probes != nil && c.component.Probes.RoleProbe != nil && c.component.Probes.RoleProbe.PeriodSeconds > 0 {
+		return int(c.component.Probes.RoleProbe.PeriodSeconds)
+	}
+	return defaultRoleProbePeriodSeconds
+}
+
+// choosePrimary chooses the primary to keep by the policy. The NewestDataWins policy keeps the one with the latest
+// operation applied, which is reported by the engine through lorry, and the ordinals break the ties.
+func (c *rsmComponent) choosePrimary(reqCtx intctrlutil.RequestCtx, primaries []*corev1.Pod,
+	policy appsv1alpha1.SplitBrainRecoveryPolicy) (*corev1.Pod, error) {
+	opTimestamps := map[string]int64{}
+	if policy == appsv1alpha1.NewestDataWins {
+		for _, pod := range primaries {
+			lorryCli, err := newLorryClient(c.component.CharacterType, *pod)
+			if err != nil {
+				return nil, err
+			}
+			if intctrlutil.IsNil(lorryCli) {
+				return nil, fmt.Errorf("no lorry in the pod %s to get the position of the data", pod.Name)
+			}
+			if opTimestamps[pod.Name], err = lorryCli.GetOpTimestamp(reqCtx.Ctx); err != nil {
+				return nil, fmt.Errorf("failed to get the position of the data of the pod %s: %s", pod.Name, err.Error())
+			}
+		}
+	}
+	sorted := append([]*corev1.Pod{}, primaries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if a, b := opTimestamps[sorted[i].Name], opTimestamps[sorted[j].Name]; a != b {
+			return a > b
+		}
+		_, ordinalA := intctrlutil.GetParentNameAndOrdinal(sorted[i])
+		_, ordinalB := intctrlutil.GetParentNameAndOrdinal(sorted[j])
+		return ordinalA < ordinalB
+	})
+	return sorted[0], nil
+}
+
+// demotePrimary demotes the pod claiming the primary by lorry in the pod.
+func (c *rsmComponent) demotePrimary(reqCtx intctrlutil.RequestCtx, pod *corev1.Pod) error {
+	lorryCli, err := newLorryClient(c.component.CharacterType, *pod)
+	if err != nil {
+		return err
+	}
+	if intctrlutil.IsNil(lorryCli) {
+		return fmt.Errorf("no lorry in the pod to demote it")
+	}
+	return lorryCli.Demote(reqCtx.Ctx)
+}
@@ -0,0 +1,1040 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	cfgcore "github.com/apecloud/kubeblocks/internal/configuration/core"
+	"github.com/apecloud/kubeblocks/internal/configuration/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	lorry "github.com/apecloud/kubeblocks/lorry/client"
+)
+
+func TestUpdateMembersStatusLeaderPod(t *testing.T) {
+	const compName = "comp"
+	membersStatus := func(leader string, pods ...string) []workloads.MemberStatus {
+		var members []workloads.MemberStatus
+		for _, pod := range pods {
+			members = append(members, workloads.MemberStatus{
+				PodName:     pod,
+				ReplicaRole: workloads.ReplicaRole{IsLeader: pod == leader, CanVote: true},
+			})
+		}
+		return members
+	}
+
+	for _, workloadType := range []appsv1alpha1.WorkloadType{appsv1alpha1.Consensus, appsv1alpha1.Replication} {
+		c := &rsmComponent{
+			Cluster:         &appsv1alpha1.Cluster{},
+			component:       &component.SynthesizedComponent{Name: compName, WorkloadType: workloadType},
+			runningWorkload: &workloads.ReplicatedStateMachine{},
+		}
+
+		c.runningWorkload.Status.MembersStatus = membersStatus("pod-0", "pod-0", "pod-1", "pod-2")
+		c.updateMembersStatus()
+		if leader := c.Cluster.Status.Components[compName].LeaderPod; leader != "pod-0" {
+			t.Errorf("%s: expected leader pod pod-0, got %q", workloadType, leader)
+		}
+
+		// mock a failover
+		c.runningWorkload.Status.MembersStatus = membersStatus("pod-1", "pod-0", "pod-1", "pod-2")
+		c.updateMembersStatus()
+		if leader := c.Cluster.Status.Components[compName].LeaderPod; leader != "pod-1" {
+			t.Errorf("%s: expected leader pod pod-1 after failover, got %q", workloadType, leader)
+		}
+
+		// no leader during the election
+		c.runningWorkload.Status.MembersStatus = membersStatus("", "pod-0", "pod-1", "pod-2")
+		c.updateMembersStatus()
+		if leader := c.Cluster.Status.Components[compName].LeaderPod; leader != "" {
+			t.Errorf("%s: expected no leader pod, got %q", workloadType, leader)
+		}
+	}
+}
+
+func TestIsApplicationReady(t *testing.T) {
+	pods := func(names ...string) []*corev1.Pod {
+		var podList []*corev1.Pod
+		for _, name := range names {
+			podList = append(podList, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		return podList
+	}
+	members := func(leader string, pods ...string) []workloads.MemberStatus {
+		var memberList []workloads.MemberStatus
+		for _, pod := range pods {
+			memberList = append(memberList, workloads.MemberStatus{
+				PodName:     pod,
+				ReplicaRole: workloads.ReplicaRole{IsLeader: pod == leader, CanVote: true},
+			})
+		}
+		return memberList
+	}
+	tests := []struct {
+		name         string
+		workloadType appsv1alpha1.WorkloadType
+		lightweight  bool
+		members      []workloads.MemberStatus
+		expected     bool
+		message      string
+	}{
+		{
+			name:         "all members joined with the leader elected",
+			workloadType: appsv1alpha1.Consensus,
+			members:      members("pod-1", "pod-0", "pod-1", "pod-2"),
+			expected:     true,
+		},
+		{
+			name:         "pods ready but no leader elected",
+			workloadType: appsv1alpha1.Consensus,
+			members:      members("", "pod-0", "pod-1", "pod-2"),
+			message:      "waiting for the leader to be elected",
+		},
+		{
+			name:         "pods ready but not all joined",
+			workloadType: appsv1alpha1.Consensus,
+			members:      members("pod-0", "pod-0"),
+			message:      "waiting for the pods pod-1,pod-2 to join the members",
+		},
+		{
+			name:         "roles are unknown in lightweight mode",
+			workloadType: appsv1alpha1.Consensus,
+			lightweight:  true,
+			expected:     true,
+		},
+		{
+			name:         "stateful component has no members",
+			workloadType: appsv1alpha1.Stateful,
+			expected:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &rsmComponent{
+				Cluster:         &appsv1alpha1.Cluster{},
+				component:       &component.SynthesizedComponent{Name: "comp", WorkloadType: tt.workloadType, LightweightMode: tt.lightweight},
+				runningWorkload: &workloads.ReplicatedStateMachine{},
+			}
+			c.runningWorkload.Status.MembersStatus = tt.members
+			ready, message := c.isApplicationReady(pods("pod-0", "pod-1", "pod-2"))
+			if ready != tt.expected || message != tt.message {
+				t.Errorf("expected ready %v with message %q, got %v with %q", tt.expected, tt.message, ready, message)
+			}
+		})
+	}
+}
+
+func TestIsFrozen(t *testing.T) {
+	const compName = "comp"
+	c := &rsmComponent{
+		Cluster:   &appsv1alpha1.Cluster{},
+		component: &component.SynthesizedComponent{Name: compName},
+	}
+	if c.isFrozen() {
+		t.Errorf("expect the component not frozen")
+	}
+	// the changes of the ClusterDefinition are not adopted yet.
+	c.clusterDefUpdatePending = true
+	if !c.isFrozen() || c.isPaused() {
+		t.Errorf("expect the component frozen but not paused")
+	}
+	c.clusterDefUpdatePending = false
+	c.Cluster.Annotations = map[string]string{constant.PausedComponentsAnnotationKey: compName}
+	if !c.isFrozen() {
+		t.Errorf("expect the paused component frozen")
+	}
+}
+
+func TestIsInCreatingPhase(t *testing.T) {
+	const compName = "comp"
+	c := &rsmComponent{
+		Cluster:         &appsv1alpha1.Cluster{},
+		component:       &component.SynthesizedComponent{Name: compName},
+		runningWorkload: &workloads.ReplicatedStateMachine{ObjectMeta: metav1.ObjectMeta{Generation: 1}},
+	}
+	setPhase := func(phase appsv1alpha1.ClusterComponentPhase) {
+		_ = c.updateStatus("", func(status *appsv1alpha1.ClusterComponentStatus) error {
+			status.Phase = phase
+			return nil
+		})
+	}
+	rollout := func(generation int64, currentRevision, updateRevision string) {
+		c.runningWorkload.Generation = generation
+		c.runningWorkload.Status.ObservedGeneration = generation
+		c.runningWorkload.Status.CurrentGeneration = generation
+		c.runningWorkload.Status.CurrentRevision = currentRevision
+		c.runningWorkload.Status.UpdateRevision = updateRevision
+	}
+
+	// the first build, the underlying workload is not created yet.
+	if !c.isInCreatingPhase() {
+		t.Error("expected creating before the workload is created")
+	}
+	rollout(1, "rev-1", "rev-1")
+	if !c.isInCreatingPhase() {
+		t.Error("expected creating during the first build")
+	}
+
+	// a spec change during the first build is rolled out.
+	c.runningWorkload.Generation = 2
+	if c.isInCreatingPhase() {
+		t.Error("expected updating once the spec is changed after the first build")
+	}
+	rollout(2, "rev-1", "rev-2")
+	if c.isInCreatingPhase() {
+		t.Error("expected updating while the pods are being replaced")
+	}
+	rollout(2, "rev-2", "rev-2")
+	if !c.isInCreatingPhase() {
+		t.Error("expected creating once the rollout is done but the component is not running yet")
+	}
+
+	// never creating again once the component has been running.
+	setPhase(appsv1alpha1.RunningClusterCompPhase)
+	if c.isInCreatingPhase() {
+		t.Error("expected not creating after the component is running")
+	}
+	rollout(3, "rev-2", "rev-3")
+	setPhase(appsv1alpha1.UpdatingClusterCompPhase)
+	if c.isInCreatingPhase() {
+		t.Error("expected not creating during a rollout after the component is running")
+	}
+}
+
+type configMapGetter struct {
+	client.Client
+	cm *corev1.ConfigMap
+}
+
+func (g *configMapGetter) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	g.cm.DeepCopyInto(obj.(*corev1.ConfigMap))
+	return nil
+}
+
+func TestUpdateConfigVersions(t *testing.T) {
+	const (
+		compName       = "comp"
+		configSpecName = "mysql-config"
+	)
+	cm := &corev1.ConfigMap{Data: map[string]string{"my.cnf": "max_connections=1000"}}
+	latestVersion, _ := util.ComputeHash(cm.Data)
+	cm.Labels = map[string]string{
+		constant.CMInsConfigurationHashLabelKey: latestVersion,
+		constant.CMInsLastReconfigurePhaseKey:   cfgcore.ReconfigureSimplePhase,
+	}
+	cm.Annotations = map[string]string{constant.LastAppliedConfigAnnotationKey: "{}"}
+
+	restartKey := cfgcore.GenerateUniqKeyWithConfig(constant.UpgradeRestartAnnotationKey, configSpecName)
+	var pods []*corev1.Pod
+	for _, name := range []string{"pod-0", "pod-1", "pod-2"} {
+		pods = append(pods, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{restartKey: "old-version"},
+		}})
+	}
+
+	c := &rsmComponent{
+		Cluster: &appsv1alpha1.Cluster{},
+		component: &component.SynthesizedComponent{
+			Name: compName,
+			ConfigTemplates: []appsv1alpha1.ComponentConfigSpec{{
+				ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{Name: configSpecName},
+			}},
+		},
+	}
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background()}
+	cli := &configMapGetter{cm: cm}
+
+	// the pods pick up the latest config one by one.
+	for i, pod := range pods {
+		pod.Annotations[restartKey] = latestVersion
+		if err := c.updateConfigVersions(reqCtx, cli, pods); err != nil {
+			t.Fatalf("failed to update config versions: %v", err)
+		}
+		status := c.Cluster.Status.Components[compName]
+		if version := status.ConfigVersions[pod.Name]; version != latestVersion {
+			t.Errorf("expected config version %q of %s, got %q", latestVersion, pod.Name, version)
+		}
+		expectedSynced := i == len(pods)-1
+		if status.ConfigSynced == nil || *status.ConfigSynced != expectedSynced {
+			t.Errorf("expected config synced %v after %s updated, got %v", expectedSynced, pod.Name, status.ConfigSynced)
+		}
+	}
+}
+
+type pvcGetter struct {
+	client.Client
+	pvcs map[string]*corev1.PersistentVolumeClaim
+}
+
+func (g *pvcGetter) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	pvc, ok := g.pvcs[key.Name]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "persistentvolumeclaims"}, key.Name)
+	}
+	pvc.DeepCopyInto(obj.(*corev1.PersistentVolumeClaim))
+	return nil
+}
+
+func TestAdoptPVCs(t *testing.T) {
+	const (
+		clusterName = "mycluster"
+		compName    = "mysql"
+	)
+	newPVC := func(name, storageClass string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: pointer.String(storageClass),
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			},
+		}
+	}
+	cli := &pvcGetter{pvcs: map[string]*corev1.PersistentVolumeClaim{
+		"data-mycluster-mysql-0": newPVC("data-mycluster-mysql-0", "standard"),
+		"data-mycluster-mysql-1": newPVC("data-mycluster-mysql-1", "local-path"),
+	}}
+
+	rsm := &workloads.ReplicatedStateMachine{ObjectMeta: metav1.ObjectMeta{Name: clusterName + "-" + compName, Namespace: "default"}}
+	dag := graph.NewDAG()
+	recorder := record.NewFakeRecorder(10)
+	c := &rsmComponent{
+		Recorder: recorder,
+		Cluster:  &appsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: "default"}},
+		component: &component.SynthesizedComponent{
+			Name:           compName,
+			ClusterDefName: "apecloud-mysql",
+			Replicas:       3,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaimTemplate{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: pointer.String("standard"),
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				},
+			}},
+		},
+		dag:            dag,
+		workloadVertex: ictrltypes.LifecycleObjectCreate(dag, rsm, nil),
+	}
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background(), Log: logr.Discard()}
+	if err := c.adoptPVCs(reqCtx, cli); err != nil {
+		t.Fatalf("failed to adopt PVCs: %v", err)
+	}
+
+	vertices := ictrltypes.FindAll[*corev1.PersistentVolumeClaim](dag)
+	if len(vertices) != 1 {
+		t.Fatalf("expected only the matching PVC adopted, got %d", len(vertices))
+	}
+	v := vertices[0].(*ictrltypes.LifecycleVertex)
+	if v.Obj.GetName() != "data-mycluster-mysql-0" || *v.Action != ictrltypes.PATCH {
+		t.Errorf("expected to patch the PVC data-mycluster-mysql-0, got %s", v.Obj.GetName())
+	}
+	labels := v.Obj.GetLabels()
+	if labels[constant.AppInstanceLabelKey] != clusterName || labels[constant.KBAppComponentLabelKey] != compName ||
+		labels[constant.VolumeClaimTemplateNameLabelKey] != "data" {
+		t.Errorf("expected the PVC labeled as the one of the component, got %v", labels)
+	}
+
+	events := []string{<-recorder.Events, <-recorder.Events}
+	if !strings.Contains(events[0], constant.ReasonAdoptedPVC) {
+		t.Errorf("expected the adoption event, got %q", events[0])
+	}
+	if !strings.Contains(events[1], constant.ReasonPVCAdoptionSkipped) || !strings.Contains(events[1], "data-mycluster-mysql-1") {
+		t.Errorf("expected the PVC with the conflicting storage class skipped, got %q", events[1])
+	}
+
+	// the labeled PVCs are not adopted again.
+	cli.pvcs["data-mycluster-mysql-0"] = v.Obj.(*corev1.PersistentVolumeClaim)
+	c.dag = graph.NewDAG()
+	c.workloadVertex = ictrltypes.LifecycleObjectCreate(c.dag, rsm, nil)
+	if err := c.adoptPVCs(reqCtx, cli); err != nil {
+		t.Fatalf("failed to adopt PVCs: %v", err)
+	}
+	if len(ictrltypes.FindAll[*corev1.PersistentVolumeClaim](c.dag)) != 0 {
+		t.Error("expected no PVC adopted again")
+	}
+}
+
+type volumeLister struct {
+	client.Client
+	pvcs []corev1.PersistentVolumeClaim
+	pods []corev1.Pod
+	sts  *appsv1.StatefulSet
+}
+
+func (l *volumeLister) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	switch list := list.(type) {
+	case *corev1.PersistentVolumeClaimList:
+		list.Items = append([]corev1.PersistentVolumeClaim{}, l.pvcs...)
+	case *corev1.PodList:
+		list.Items = append([]corev1.Pod{}, l.pods...)
+	}
+	return nil
+}
+
+func (l *volumeLister) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	sts, ok := obj.(*appsv1.StatefulSet)
+	if !ok || l.sts == nil {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "statefulsets"}, key.Name)
+	}
+	l.sts.DeepCopyInto(sts)
+	return nil
+}
+
+func TestRemoveVolumes(t *testing.T) {
+	const (
+		clusterName = "mycluster"
+		compName    = "mysql"
+	)
+	newPVC := func(vctName string, ordinal int) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-%s-%d", vctName, clusterName, compName, ordinal),
+			Namespace: "default",
+			Labels:    map[string]string{constant.VolumeClaimTemplateNameLabelKey: vctName},
+		}}
+	}
+	newPod := func(ordinal int, vctNames ...string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-%d", clusterName, compName, ordinal),
+			Namespace: "default",
+		}}
+		for _, vctName := range vctNames {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+				Name: vctName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: fmt.Sprintf("%s-%s-%s-%d", vctName, clusterName, compName, ordinal),
+					},
+				},
+			})
+		}
+		return pod
+	}
+	newVCTs := func(vctNames ...string) []corev1.PersistentVolumeClaim {
+		vcts := make([]corev1.PersistentVolumeClaim, 0, len(vctNames))
+		for _, vctName := range vctNames {
+			vcts = append(vcts, corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: vctName}})
+		}
+		return vcts
+	}
+	workloadMeta := metav1.ObjectMeta{Name: clusterName + "-" + compName, Namespace: "default"}
+	// the log volume claim template is removed from the RSM, while the underlying StatefulSet keeps it.
+	cli := &volumeLister{
+		pvcs: []corev1.PersistentVolumeClaim{newPVC("data", 0), newPVC("data", 1), newPVC("log", 0), newPVC("log", 1)},
+		pods: []corev1.Pod{newPod(0, "data", "log"), newPod(1, "data", "log")},
+		sts: &appsv1.StatefulSet{
+			ObjectMeta: workloadMeta,
+			Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: newVCTs("data", "log")},
+		},
+	}
+
+	rsm := &workloads.ReplicatedStateMachine{
+		ObjectMeta: workloadMeta,
+		Spec:       workloads.ReplicatedStateMachineSpec{VolumeClaimTemplates: newVCTs("data")},
+	}
+	newComponent := func(policy appsv1alpha1.VolumeClaimRetentionPolicyType) (*rsmComponent, *record.FakeRecorder) {
+		dag := graph.NewDAG()
+		recorder := record.NewFakeRecorder(10)
+		return &rsmComponent{
+			Recorder: recorder,
+			Cluster:  &appsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: "default"}},
+			component: &component.SynthesizedComponent{
+				Name:     compName,
+				Replicas: 2,
+				// the log volume claim template is removed.
+				VolumeClaimTemplates:       []corev1.PersistentVolumeClaimTemplate{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+				VolumeClaimRetentionPolicy: policy,
+			},
+			dag:             dag,
+			workloadVertex:  ictrltypes.LifecycleObjectUpdate(dag, rsm, nil),
+			runningWorkload: rsm,
+		}, recorder
+	}
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background(), Log: logr.Discard()}
+	removeVolumes := func(policy appsv1alpha1.VolumeClaimRetentionPolicyType) (map[string]ictrltypes.LifecycleAction, *record.FakeRecorder) {
+		c, recorder := newComponent(policy)
+		if err := c.removeVolumes(reqCtx, cli); err != nil {
+			t.Fatalf("failed to remove volumes: %v", err)
+		}
+		actions := map[string]ictrltypes.LifecycleAction{}
+		for _, v := range ictrltypes.FindAll[*corev1.PersistentVolumeClaim](c.dag) {
+			v := v.(*ictrltypes.LifecycleVertex)
+			if !strings.HasPrefix(v.Obj.GetName(), "log-") {
+				t.Errorf("policy %q: expected to handle the PVCs of the removed template only, got %s", policy, v.Obj.GetName())
+			}
+			actions[v.Obj.GetName()] = *v.Action
+		}
+		return actions, recorder
+	}
+	log0, log1 := newPVC("log", 0).Name, newPVC("log", 1).Name
+
+	for _, policy := range []appsv1alpha1.VolumeClaimRetentionPolicyType{"", appsv1alpha1.RetainVolumeClaimRetentionPolicy} {
+		actions, recorder := removeVolumes(policy)
+		if len(actions) != 2 || actions[log0] != ictrltypes.NOOP || actions[log1] != ictrltypes.NOOP {
+			t.Errorf("policy %q: expected the PVCs of the removed template retained, got %v", policy, actions)
+		}
+		if len(recorder.Events) != 0 {
+			t.Errorf("policy %q: expected no event, got %d", policy, len(recorder.Events))
+		}
+	}
+
+	// the StatefulSet still has the volume claim template, it would recreate the PVCs.
+	actions, recorder := removeVolumes(appsv1alpha1.DeleteVolumeClaimRetentionPolicy)
+	if len(actions) != 2 || actions[log0] != ictrltypes.NOOP || actions[log1] != ictrltypes.NOOP {
+		t.Errorf("expected the PVCs retained until the StatefulSet drops the template, got %v", actions)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("expected no event, got %d", len(recorder.Events))
+	}
+
+	// the StatefulSet drops the template, while the pod 0 still mounts the PVC.
+	cli.sts.Spec.VolumeClaimTemplates = newVCTs("data")
+	cli.pods = []corev1.Pod{newPod(0, "data", "log"), newPod(1, "data")}
+	actions, recorder = removeVolumes(appsv1alpha1.DeleteVolumeClaimRetentionPolicy)
+	if len(actions) != 2 || actions[log0] != ictrltypes.NOOP || actions[log1] != ictrltypes.DELETE {
+		t.Errorf("expected only the PVC released by the pods deleted, got %v", actions)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, constant.ReasonRemovedVolumeClaimDeleted) || !strings.Contains(event, log1) {
+		t.Errorf("expected the PVC deletion event of %s, got %q", log1, event)
+	}
+
+	// all the pods release the PVCs.
+	cli.pods = []corev1.Pod{newPod(0, "data"), newPod(1, "data")}
+	actions, recorder = removeVolumes(appsv1alpha1.DeleteVolumeClaimRetentionPolicy)
+	if len(actions) != 2 || actions[log0] != ictrltypes.DELETE || actions[log1] != ictrltypes.DELETE {
+		t.Errorf("expected the PVCs of the removed template deleted, got %v", actions)
+	}
+	for i := 0; i < 2; i++ {
+		if event := <-recorder.Events; !strings.Contains(event, constant.ReasonRemovedVolumeClaimDeleted) {
+			t.Errorf("expected the PVC deletion event, got %q", event)
+		}
+	}
+
+	// the PVCs being deleted are not handled again.
+	now := metav1.Now()
+	for i := range cli.pvcs {
+		if strings.HasPrefix(cli.pvcs[i].Name, "log-") {
+			cli.pvcs[i].DeletionTimestamp = &now
+		}
+	}
+	if actions, _ = removeVolumes(appsv1alpha1.DeleteVolumeClaimRetentionPolicy); len(actions) != 0 {
+		t.Errorf("expected no PVC deleted again, got %v", actions)
+	}
+}
+
+func TestCheckOOMKilled(t *testing.T) {
+	const compName = "comp"
+	recorder := record.NewFakeRecorder(10)
+	c := &rsmComponent{
+		Cluster:         &appsv1alpha1.Cluster{},
+		component:       &component.SynthesizedComponent{Name: compName},
+		runningWorkload: &workloads.ReplicatedStateMachine{},
+		Recorder:        recorder,
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-0"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "mysql",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			}},
+		},
+	}
+	// oomKilled mocks the container restarted in place after being killed at the time.
+	oomKilled := func(killedAt time.Time) {
+		statuses := pod.Status.ContainerStatuses
+		if len(statuses) == 0 {
+			statuses = []corev1.ContainerStatus{{Name: "mysql"}}
+		}
+		statuses[0].RestartCount++
+		statuses[0].Ready = true
+		statuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(killedAt)}}
+		statuses[0].LastTerminationState = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", FinishedAt: metav1.NewTime(killedAt)},
+		}
+		pod.Status.ContainerStatuses = statuses
+	}
+
+	now := time.Now().Truncate(time.Second)
+	killAndCheck := func(killedAt time.Time) (bool, appsv1alpha1.ComponentMessageMap, time.Duration) {
+		oomKilled(killedAt)
+		return c.checkOOMKilled([]*corev1.Pod{pod}, now)
+	}
+	hasOOMKilled, _, requeueAfter := killAndCheck(now.Add(-3 * time.Minute))
+	if hasOOMKilled {
+		t.Error("expected not abnormal with the kills below the threshold")
+	}
+	if requeueAfter <= 0 {
+		t.Error("expected to requeue while the kills are recorded")
+	}
+	// the kill observed by the previous reconciliation is not counted again.
+	hasOOMKilled, _, _ = c.checkOOMKilled([]*corev1.Pod{pod}, now)
+	if hasOOMKilled || len(c.Cluster.Status.Components[compName].OOMKilledContainers[0].KilledTimes) != 1 {
+		t.Errorf("expected the kill recorded once, got %v", c.Cluster.Status.Components[compName].OOMKilledContainers)
+	}
+
+	killAndCheck(now.Add(-2 * time.Minute))
+	hasOOMKilled, messages, requeueAfter := killAndCheck(now.Add(-time.Minute))
+	if !hasOOMKilled {
+		t.Fatal("expected abnormal after the container is killed repeatedly within the window")
+	}
+	message := messages["Pod/pod-0"]
+	if !strings.Contains(message, "OOMKilled") || !strings.Contains(message, "mysql") || !strings.Contains(message, "1Gi") {
+		t.Errorf("expected the message with the container and its memory limit, got %q", message)
+	}
+	if requeueAfter != oomKilledStablePeriod-time.Minute {
+		t.Errorf("expected to requeue after the stable period, got %v", requeueAfter)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, constant.ReasonContainerOOMKilled) || !strings.Contains(event, "VerticalScaling") {
+			t.Errorf("expected the warning event suggesting a vertical scale, got %q", event)
+		}
+	default:
+		t.Error("expected the warning event")
+	}
+
+	// keep abnormal even if the container is ready again, until the stable period elapses.
+	hasOOMKilled, _, _ = c.checkOOMKilled([]*corev1.Pod{pod}, now.Add(oomKilledWindow))
+	if !hasOOMKilled {
+		t.Error("expected abnormal within the stable period")
+	}
+	if len(recorder.Events) != 0 {
+		t.Error("expected the warning event emitted only once")
+	}
+	hasOOMKilled, _, requeueAfter = c.checkOOMKilled([]*corev1.Pod{pod}, now.Add(oomKilledStablePeriod))
+	if hasOOMKilled || requeueAfter != 0 {
+		t.Errorf("expected cleared after the stable period, got abnormal %v and requeue after %v", hasOOMKilled, requeueAfter)
+	}
+	if len(c.Cluster.Status.Components[compName].OOMKilledContainers) != 0 {
+		t.Errorf("expected the records cleared, got %v", c.Cluster.Status.Components[compName].OOMKilledContainers)
+	}
+}
+
+func TestCheckOOMKilledByRestartCount(t *testing.T) {
+	const compName = "comp"
+	c := &rsmComponent{
+		Cluster:         &appsv1alpha1.Cluster{},
+		component:       &component.SynthesizedComponent{Name: compName},
+		runningWorkload: &workloads.ReplicatedStateMachine{},
+	}
+	now := time.Now().Truncate(time.Second)
+	oomKilledPod := func(restartCount int32, killedAt time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-0"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "mysql"}}},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "mysql",
+					RestartCount: restartCount,
+					State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(killedAt)}},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", FinishedAt: metav1.NewTime(killedAt)},
+					},
+				}},
+			},
+		}
+	}
+
+	if hasOOMKilled, _, _ := c.checkOOMKilled([]*corev1.Pod{oomKilledPod(1, now.Add(-3*time.Minute))}, now); hasOOMKilled {
+		t.Fatal("expected not abnormal with the first kill")
+	}
+	if restartCount := c.Cluster.Status.Components[compName].OOMKilledContainers[0].RestartCount; restartCount != 1 {
+		t.Errorf("expected the restart count observed, got %d", restartCount)
+	}
+	// the container is killed twice between the observations, only the last kill is visible from the termination state.
+	hasOOMKilled, _, _ := c.checkOOMKilled([]*corev1.Pod{oomKilledPod(3, now.Add(-time.Minute))}, now)
+	if !hasOOMKilled {
+		t.Errorf("expected abnormal with the kills counted from the restart count, got %v",
+			c.Cluster.Status.Components[compName].OOMKilledContainers)
+	}
+}
+
+var _ = Describe("component min ready test", func() {
+	var (
+		c   *rsmComponent
+		pod *corev1.Pod
+		now time.Time
+	)
+
+	setReady := func(ready bool, at time.Time) {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(at)}}
+	}
+
+	BeforeEach(func() {
+		c = &rsmComponent{
+			Cluster:         &appsv1alpha1.Cluster{},
+			component:       &component.SynthesizedComponent{Name: "comp", MinReadySeconds: 30},
+			runningWorkload: &workloads.ReplicatedStateMachine{},
+		}
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-0"}}
+		now = time.Now().Truncate(time.Second)
+	})
+
+	Context("min ready seconds", func() {
+		It("should be satisfied only after the pods have been ready for minReadySeconds", func() {
+			setReady(true, now)
+			satisfied, requeueAfter := c.isMinReadySatisfied([]*corev1.Pod{pod}, now.Add(10*time.Second))
+			Expect(satisfied).Should(BeFalse())
+			Expect(requeueAfter).Should(Equal(20 * time.Second))
+
+			By("the pod crashes within the window, the component never reports Running in between")
+			setReady(false, now.Add(15*time.Second))
+			satisfied, requeueAfter = c.isMinReadySatisfied([]*corev1.Pod{pod}, now.Add(15*time.Second))
+			Expect(satisfied).Should(BeFalse())
+			Expect(requeueAfter).Should(BeZero())
+
+			By("the window restarts once the pod is ready again")
+			setReady(true, now.Add(20*time.Second))
+			satisfied, requeueAfter = c.isMinReadySatisfied([]*corev1.Pod{pod}, now.Add(40*time.Second))
+			Expect(satisfied).Should(BeFalse())
+			Expect(requeueAfter).Should(Equal(10 * time.Second))
+			satisfied, _ = c.isMinReadySatisfied([]*corev1.Pod{pod}, now.Add(50*time.Second))
+			Expect(satisfied).Should(BeTrue())
+		})
+
+		It("should be satisfied as soon as the pods are ready by default", func() {
+			c.component.MinReadySeconds = 0
+			setReady(true, now)
+			satisfied, _ := c.isMinReadySatisfied([]*corev1.Pod{pod}, now)
+			Expect(satisfied).Should(BeTrue())
+		})
+
+		It("should not report the pods restarted out of a rollout as updating", func() {
+			By("the pods are created for the first time")
+			Expect(c.getMinReadyPendingPhase(true)).Should(Equal(appsv1alpha1.CreatingClusterCompPhase))
+
+			By("the pods are replaced by a rollout")
+			c.runningWorkload.Generation = 2
+			c.runningWorkload.Status.ObservedGeneration = 2
+			c.runningWorkload.Status.CurrentGeneration = 2
+			c.runningWorkload.Status.CurrentRevision = "rev-1"
+			c.runningWorkload.Status.UpdateRevision = "rev-2"
+			Expect(c.getMinReadyPendingPhase(false)).Should(Equal(appsv1alpha1.UpdatingClusterCompPhase))
+
+			By("a pod crashes and passes the readiness again")
+			c.runningWorkload.Status.CurrentRevision = "rev-2"
+			Expect(c.getMinReadyPendingPhase(false)).Should(Equal(appsv1alpha1.AbnormalClusterCompPhase))
+		})
+	})
+})
+
+// fakeLorryClient mocks lorry in the pods, keyed by the name of the pod.
+type fakeLorryClient struct {
+	opTimestamps map[string]int64
+	demoted      map[string]bool
+	pod          string
+}
+
+func (f *fakeLorryClient) JoinMember(_ context.Context) error {
+	return nil
+}
+
+func (f *fakeLorryClient) LeaveMember(_ context.Context) error {
+	return nil
+}
+
+func (f *fakeLorryClient) GetOpTimestamp(_ context.Context) (int64, error) {
+	opTimestamp, ok := f.opTimestamps[f.pod]
+	if !ok {
+		return 0, fmt.Errorf("unknown pod %s", f.pod)
+	}
+	return opTimestamp, nil
+}
+
+func (f *fakeLorryClient) Demote(_ context.Context) error {
+	f.demoted[f.pod] = true
+	return nil
+}
+
+func (f *fakeLorryClient) CheckExternalDependency(_ context.Context, _ appsv1alpha1.ExternalDependency) error {
+	return nil
+}
+
+func TestCheckSplitBrain(t *testing.T) {
+	const (
+		clusterName = "mycluster"
+		compName    = "redis"
+	)
+	var (
+		reqCtx       = intctrlutil.RequestCtx{Ctx: context.Background()}
+		opTimestamps = map[string]int64{}
+		demoted      = map[string]bool{}
+	)
+	newLorryClient = func(_ string, pod corev1.Pod) (lorry.Client, error) {
+		return &fakeLorryClient{opTimestamps: opTimestamps, demoted: demoted, pod: pod.Name}, nil
+	}
+	defer func() { newLorryClient = lorry.NewClient }()
+
+	newPod := func(ordinal int, role string, opTimestamp int64) *corev1.Pod {
+		name := fmt.Sprintf("%s-%s-%d", clusterName, compName, ordinal)
+		opTimestamps[name] = opTimestamp
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{constant.RoleLabelKey: role},
+			},
+		}
+	}
+	newComponent := func(policy appsv1alpha1.SplitBrainRecoveryPolicy, splitBrainSince *metav1.Time) *rsmComponent {
+		cluster := &appsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+			Spec: appsv1alpha1.ClusterSpec{
+				ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{{Name: compName}},
+			},
+			Status: appsv1alpha1.ClusterStatus{
+				Components: map[string]appsv1alpha1.ClusterComponentStatus{
+					compName: {SplitBrainSince: splitBrainSince},
+				},
+			},
+		}
+		if len(policy) > 0 {
+			cluster.Spec.ComponentSpecs[0].SwitchPolicy = &appsv1alpha1.ClusterSwitchPolicy{
+				Type:               appsv1alpha1.Noop,
+				SplitBrainRecovery: policy,
+			}
+		}
+		return &rsmComponent{
+			Cluster: cluster,
+			component: &component.SynthesizedComponent{
+				Name:          compName,
+				CharacterType: compName,
+				WorkloadType:  appsv1alpha1.Replication,
+				Probes: &appsv1alpha1.ClusterDefinitionProbes{
+					RoleProbe: &appsv1alpha1.ClusterDefinitionProbe{PeriodSeconds: 2},
+				},
+			},
+			runningWorkload: &workloads.ReplicatedStateMachine{
+				Spec: workloads.ReplicatedStateMachineSpec{
+					Roles: []workloads.ReplicaRole{
+						{Name: constant.Primary, IsLeader: true},
+						{Name: constant.Secondary},
+					},
+				},
+			},
+			dag:      graph.NewDAG(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+	demotedPods := func() []string {
+		var names []string
+		for name := range demoted {
+			names = append(names, name)
+		}
+		return names
+	}
+	reset := func() {
+		for name := range demoted {
+			delete(demoted, name)
+		}
+	}
+
+	// the old primary pod-0 comes back from a partition after pod-1 is promoted and applies newer operations.
+	pods := []*corev1.Pod{
+		newPod(0, constant.Primary, 100),
+		newPod(1, constant.Primary, 200),
+		newPod(2, constant.Secondary, 200),
+	}
+	now := time.Now()
+	// the split-brain persists for the 3 intervals of the role probe.
+	confirmed := &metav1.Time{Time: now.Add(-6 * time.Second)}
+
+	t.Run("split-brain not persisting", func(t *testing.T) {
+		reset()
+		c := newComponent("", nil)
+		hasSplitBrain, _, requeueAfter := c.checkSplitBrain(reqCtx, pods, now)
+		if hasSplitBrain {
+			t.Error("expected the split-brain not recovered before it persists")
+		}
+		if requeueAfter != 6*time.Second {
+			t.Errorf("expected to check again after 6s, got %s", requeueAfter)
+		}
+		since := c.getComponentStatus().SplitBrainSince
+		if since == nil || !since.Time.Equal(now) {
+			t.Errorf("expected the split-brain recorded since %s, got %v", now, since)
+		}
+		if len(demoted) != 0 {
+			t.Errorf("expected no pod demoted, got %v", demotedPods())
+		}
+	})
+
+	cases := []struct {
+		name    string
+		policy  appsv1alpha1.SplitBrainRecoveryPolicy
+		demoted string
+	}{
+		{"newest data wins by default", "", pods[0].Name},
+		{"newest data wins", appsv1alpha1.NewestDataWins, pods[0].Name},
+		{"lowest ordinal wins", appsv1alpha1.LowestOrdinalWins, pods[1].Name},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reset()
+			c := newComponent(tc.policy, confirmed)
+			hasSplitBrain, messages, requeueAfter := c.checkSplitBrain(reqCtx, pods, now)
+			if !hasSplitBrain {
+				t.Fatal("expected the split-brain detected")
+			}
+			if requeueAfter <= 0 {
+				t.Error("expected to check again until the demotion is reported")
+			}
+			if names := demotedPods(); len(names) != 1 || names[0] != tc.demoted {
+				t.Errorf("expected %s demoted, got %v", tc.demoted, names)
+			}
+			if len(ictrltypes.FindAll[*corev1.Pod](c.dag)) != 0 {
+				t.Error("expected no pod deleted")
+			}
+			if _, ok := messages["Pod/"+tc.demoted]; !ok {
+				t.Errorf("expected the message of the demoted pod, got %v", messages)
+			}
+			recorder := c.Recorder.(*record.FakeRecorder)
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, constant.ReasonSplitBrainRecovered) {
+					t.Errorf("expected the split-brain event, got %q", event)
+				}
+			default:
+				t.Error("expected the warning event")
+			}
+		})
+	}
+
+	t.Run("the position of the data unknown", func(t *testing.T) {
+		reset()
+		unknown := newPod(3, constant.Primary, 0)
+		delete(opTimestamps, unknown.Name)
+		c := newComponent(appsv1alpha1.NewestDataWins, confirmed)
+		hasSplitBrain, messages, _ := c.checkSplitBrain(reqCtx, []*corev1.Pod{pods[0], unknown}, now)
+		if !hasSplitBrain {
+			t.Fatal("expected the split-brain detected")
+		}
+		if len(demoted) != 0 {
+			t.Errorf("expected no pod demoted, got %v", demotedPods())
+		}
+		if _, ok := messages["Pod/"+unknown.Name]; !ok {
+			t.Errorf("expected the message of the pod, got %v", messages)
+		}
+	})
+
+	t.Run("single primary", func(t *testing.T) {
+		reset()
+		c := newComponent("", confirmed)
+		if hasSplitBrain, _, _ := c.checkSplitBrain(reqCtx, []*corev1.Pod{pods[1], pods[2]}, now); hasSplitBrain {
+			t.Error("expected no split-brain with a single primary")
+		}
+		if len(demoted) != 0 {
+			t.Error("expected no pod demoted")
+		}
+		if c.getComponentStatus().SplitBrainSince != nil {
+			t.Error("expected the split-brain cleared")
+		}
+	})
+
+	t.Run("the primary being deleted", func(t *testing.T) {
+		reset()
+		c := newComponent("", confirmed)
+		deleting := pods[0].DeepCopy()
+		deletedAt := metav1.Now()
+		deleting.DeletionTimestamp = &deletedAt
+		if hasSplitBrain, _, _ := c.checkSplitBrain(reqCtx, []*corev1.Pod{deleting, pods[1], pods[2]}, now); hasSplitBrain {
+			t.Error("expected the pod being deleted ignored")
+		}
+	})
+}
+
+func TestGetImmutableVolumeClaimTemplateChanges(t *testing.T) {
+	newVCT := func(name, storageClass, size string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: pointer.String(storageClass),
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+	running := []corev1.PersistentVolumeClaim{newVCT("data", "standard", "1Gi"), newVCT("log", "standard", "1Gi")}
+	tests := []struct {
+		name     string
+		desired  []corev1.PersistentVolumeClaim
+		expected []string
+	}{
+		{
+			name:    "resized",
+			desired: []corev1.PersistentVolumeClaim{newVCT("data", "standard", "2Gi"), newVCT("log", "standard", "1Gi")},
+		},
+		{
+			name:    "removed",
+			desired: []corev1.PersistentVolumeClaim{newVCT("data", "standard", "1Gi")},
+		},
+		{
+			name:     "storage class changed",
+			desired:  []corev1.PersistentVolumeClaim{newVCT("data", "fast", "1Gi"), newVCT("log", "standard", "1Gi")},
+			expected: []string{"spec.volumeClaimTemplates[data].spec.storageClassName"},
+		},
+		{
+			name: "added",
+			desired: []corev1.PersistentVolumeClaim{newVCT("data", "standard", "1Gi"), newVCT("log", "standard", "1Gi"),
+				newVCT("backup", "standard", "1Gi")},
+			expected: []string{"spec.volumeClaimTemplates[backup]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := getImmutableVolumeClaimTemplateChanges(running, tt.desired)
+			if strings.Join(fields, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected fields %v, got %v", tt.expected, fields)
+			}
+		})
+	}
+}
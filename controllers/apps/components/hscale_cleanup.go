@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package components
+
+import (
+	"context"
+	"fmt"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
+)
+
+// GetHScaleTmpResourceLabels returns the labels of the intermediate resources created by the data clone
+// of the HorizontalScaling OpsRequest.
+func GetHScaleTmpResourceLabels(opsName string) client.MatchingLabels {
+	return client.MatchingLabels{
+		constant.OpsRequestNameLabelKey: opsName,
+		constant.OpsRequestTypeLabelKey: string(appsv1alpha1.HorizontalScalingType),
+	}
+}
+
+// CleanupHScaleTmpResources deletes the intermediate resources created by the data clone of the HorizontalScaling
+// OpsRequest, including the backups, restores, volume snapshots and the PVCs which are not bound yet. The bound PVCs
+// are never touched as they may have been used by the pods.
+// It returns the resources cleaned in the format of "<kind>/<name>".
+func CleanupHScaleTmpResources(ctx context.Context, cli client.Client, namespace, opsName string) ([]string, error) {
+	var (
+		cleaned    []string
+		listOpts   = []client.ListOption{client.InNamespace(namespace), GetHScaleTmpResourceLabels(opsName)}
+		deleteObjs = func(kind string, objs []client.Object) error {
+			for _, obj := range objs {
+				if obj.GetDeletionTimestamp().IsZero() {
+					if err := intctrlutil.BackgroundDeleteObject(cli, ctx, obj); err != nil {
+						return err
+					}
+				}
+				cleaned = append(cleaned, fmt.Sprintf("%s/%s", kind, obj.GetName()))
+			}
+			return nil
+		}
+	)
+
+	restoreList := &dpv1alpha1.RestoreList{}
+	if err := cli.List(ctx, restoreList, listOpts...); err != nil {
+		return nil, err
+	}
+	restores := make([]client.Object, 0, len(restoreList.Items))
+	for i := range restoreList.Items {
+		restores = append(restores, &restoreList.Items[i])
+	}
+	if err := deleteObjs(dptypes.RestoreKind, restores); err != nil {
+		return nil, err
+	}
+
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, listOpts...); err != nil {
+		return nil, err
+	}
+	backups := make([]client.Object, 0, len(backupList.Items))
+	for i := range backupList.Items {
+		backups = append(backups, &backupList.Items[i])
+	}
+	if err := deleteObjs(dptypes.BackupKind, backups); err != nil {
+		return nil, err
+	}
+
+	// the volume snapshots inherit the labels of the backups, they are deleted directly instead of waiting
+	// for the backups to be deleted, in case the backups are stuck.
+	vsCli := &intctrlutil.VolumeSnapshotCompatClient{Client: cli, Ctx: ctx}
+	snapshotList := &snapshotv1.VolumeSnapshotList{}
+	if err := vsCli.List(snapshotList, listOpts...); err != nil {
+		return nil, err
+	}
+	for i := range snapshotList.Items {
+		snapshot := &snapshotList.Items[i]
+		if controllerutil.ContainsFinalizer(snapshot, dptypes.DataProtectionFinalizerName) {
+			patch := snapshot.DeepCopy()
+			controllerutil.RemoveFinalizer(snapshot, dptypes.DataProtectionFinalizerName)
+			if err := vsCli.Patch(snapshot, patch); err != nil {
+				return nil, err
+			}
+		}
+		if snapshot.DeletionTimestamp.IsZero() {
+			if err := vsCli.Delete(snapshot); err != nil {
+				return nil, err
+			}
+		}
+		cleaned = append(cleaned, fmt.Sprintf("%s/%s", constant.VolumeSnapshotKind, snapshot.Name))
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := cli.List(ctx, pvcList, listOpts...); err != nil {
+		return nil, err
+	}
+	pvcs := make([]client.Object, 0, len(pvcList.Items))
+	for i := range pvcList.Items {
+		if pvcList.Items[i].Status.Phase == corev1.ClaimBound {
+			continue
+		}
+		pvcs = append(pvcs, &pvcList.Items[i])
+	}
+	if err := deleteObjs(constant.PersistentVolumeClaimKind, pvcs); err != nil {
+		return nil, err
+	}
+	return cleaned, nil
+}
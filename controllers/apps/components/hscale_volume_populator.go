@@ -21,6 +21,7 @@ package components
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
@@ -73,31 +74,21 @@ func newDataClone(reqCtx intctrlutil.RequestCtx,
 	if component == nil {
 		return nil, nil
 	}
+	base := baseDataClone{
+		reqCtx:    reqCtx,
+		cli:       cli,
+		cluster:   cluster,
+		component: component,
+		stsObj:    stsObj,
+		stsProto:  stsProto,
+		key:       key,
+		opsName:   getHScaleOpsName(cluster),
+	}
 	if component.HorizontalScalePolicy == nil {
-		return &dummyDataClone{
-			baseDataClone{
-				reqCtx:    reqCtx,
-				cli:       cli,
-				cluster:   cluster,
-				component: component,
-				stsObj:    stsObj,
-				stsProto:  stsProto,
-				key:       key,
-			},
-		}, nil
+		return &dummyDataClone{base}, nil
 	}
 	if component.HorizontalScalePolicy.Type == appsv1alpha1.HScaleDataClonePolicyCloneVolume {
-		return &backupDataClone{
-			baseDataClone{
-				reqCtx:    reqCtx,
-				cli:       cli,
-				cluster:   cluster,
-				component: component,
-				stsObj:    stsObj,
-				stsProto:  stsProto,
-				key:       key,
-			},
-		}, nil
+		return &backupDataClone{base}, nil
 	}
 	// TODO: how about policy None and Snapshot?
 	return nil, nil
@@ -111,6 +102,9 @@ type baseDataClone struct {
 	stsObj    *appsv1.StatefulSet
 	stsProto  *appsv1.StatefulSet
 	key       types.NamespacedName
+	// opsName is the name of the HorizontalScaling OpsRequest which triggers the data clone, it's empty if the
+	// replicas are changed without an OpsRequest.
+	opsName string
 }
 
 func (d *baseDataClone) cloneData(realDataClone dataClone) ([]client.Object, error) {
@@ -183,7 +177,7 @@ func (d *baseDataClone) checkAllPVCsExist() (bool, error) {
 		for _, vct := range d.component.VolumeClaimTemplates {
 			pvcKey := types.NamespacedName{
 				Namespace: d.stsObj.Namespace,
-				Name:      fmt.Sprintf("%s-%s-%d", vct.Name, d.stsObj.Name, i),
+				Name:      fmt.Sprintf("%s-%s-%d", vct.Name, d.stsObj.Name, d.component.OrdinalStart+i),
 			}
 			// check pvc existence
 			pvcExists, err := d.isPVCExists(pvcKey)
@@ -228,7 +222,7 @@ func (d *baseDataClone) createPVCs(vcts []*corev1.PersistentVolumeClaimTemplate)
 		for _, vct := range vcts {
 			pvcKey := types.NamespacedName{
 				Namespace: d.stsObj.Namespace,
-				Name:      fmt.Sprintf("%s-%s-%d", vct.Name, d.stsObj.Name, i),
+				Name:      fmt.Sprintf("%s-%s-%d", vct.Name, d.stsObj.Name, d.component.OrdinalStart+i),
 			}
 			if exist, err := d.isPVCExists(pvcKey); err != nil {
 				return nil, err
@@ -236,6 +230,7 @@ func (d *baseDataClone) createPVCs(vcts []*corev1.PersistentVolumeClaimTemplate)
 				continue
 			}
 			pvc := factory.BuildPVC(d.cluster, d.component, vct, pvcKey, "")
+			pvc.Labels = d.setOpsLabels(pvc.Labels)
 			objs = append(objs, pvc)
 		}
 	}
@@ -250,6 +245,20 @@ func (d *baseDataClone) getBRLabels() map[string]string {
 	}
 }
 
+// setOpsLabels labels the intermediate objects of the data clone with the HorizontalScaling OpsRequest,
+// so they can be found and cleaned up after the OpsRequest failed or was cancelled.
+func (d *baseDataClone) setOpsLabels(labels map[string]string) map[string]string {
+	if len(d.opsName) == 0 {
+		return labels
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[constant.OpsRequestNameLabelKey] = d.opsName
+	labels[constant.OpsRequestTypeLabelKey] = string(appsv1alpha1.HorizontalScalingType)
+	return labels
+}
+
 type dummyDataClone struct {
 	baseDataClone
 }
@@ -356,6 +365,8 @@ func (d *backupDataClone) backup() ([]client.Object, error) {
 		return nil, fmt.Errorf("more than one backup methods found in backup policy %s", backupPolicy.Name)
 	}
 	backup := factory.BuildBackup(d.cluster, d.component, backupPolicy.Name, d.key, backupMethods[0])
+	// the labels are inherited by the volume snapshots created by the backup.
+	backup.Labels = d.setOpsLabels(backup.Labels)
 	objs = append(objs, backup)
 	return objs, nil
 }
@@ -384,11 +395,15 @@ func (d *backupDataClone) restore(startingIndex int32) ([]client.Object, error)
 	if err := d.cli.Get(d.reqCtx.Ctx, d.key, backup); err != nil {
 		return nil, err
 	}
-	restoreMGR := plan.NewRestoreManager(d.reqCtx.Ctx, d.cli, d.cluster, nil, d.getBRLabels(), int32(1), startingIndex)
+	restoreMGR := plan.NewRestoreManager(d.reqCtx.Ctx, d.cli, d.cluster, nil, d.setOpsLabels(d.getBRLabels()), int32(1), startingIndex)
 	restore, err := restoreMGR.BuildPrepareDataRestore(d.component, backup)
 	if err != nil || restore == nil {
 		return nil, err
 	}
+	templates := restore.Spec.PrepareDataConfig.RestoreVolumeClaimsTemplate.Templates
+	for i := range templates {
+		templates[i].Labels = d.setOpsLabels(templates[i].Labels)
+	}
 	return []client.Object{restore}, nil
 }
 
@@ -405,6 +420,24 @@ func (d *backupDataClone) checkRestoreStatus(startingIndex int32) (backupStatus,
 	return backupStatusProcessing, nil
 }
 
+// getHScaleOpsName returns the name of the running HorizontalScaling OpsRequest of the cluster.
+func getHScaleOpsName(cluster *appsv1alpha1.Cluster) string {
+	value, ok := cluster.Annotations[constant.OpsRequestAnnotationKey]
+	if !ok {
+		return ""
+	}
+	var opsRecorders []appsv1alpha1.OpsRecorder
+	if err := json.Unmarshal([]byte(value), &opsRecorders); err != nil {
+		return ""
+	}
+	for _, recorder := range opsRecorders {
+		if recorder.Type == appsv1alpha1.HorizontalScalingType {
+			return recorder.Name
+		}
+	}
+	return ""
+}
+
 // getBackupPolicyFromTemplate gets backup policy from template policy template.
 func getBackupPolicyFromTemplate(reqCtx intctrlutil.RequestCtx,
 	cli client.Client,
@@ -443,6 +476,14 @@ func backupVCT(component *component.SynthesizedComponent) *corev1.PersistentVolu
 	return &vct
 }
 
+// getOrdinalStart returns the ordinal of the first pod of the StatefulSet.
+func getOrdinalStart(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.Ordinals == nil {
+		return 0
+	}
+	return sts.Spec.Ordinals.Start
+}
+
 func isVolumeSnapshotEnabled(ctx context.Context, cli client.Client,
 	sts *appsv1.StatefulSet, vct *corev1.PersistentVolumeClaimTemplate) (bool, error) {
 	if sts == nil || vct == nil {
@@ -450,7 +491,7 @@ func isVolumeSnapshotEnabled(ctx context.Context, cli client.Client,
 	}
 	pvcKey := types.NamespacedName{
 		Namespace: sts.Namespace,
-		Name:      fmt.Sprintf("%s-%s-%d", vct.Name, sts.Name, 0),
+		Name:      fmt.Sprintf("%s-%s-%d", vct.Name, sts.Name, getOrdinalStart(sts)),
 	}
 	pvc := corev1.PersistentVolumeClaim{}
 	if err := cli.Get(ctx, pvcKey, &pvc); err != nil {
@@ -97,7 +97,8 @@ func NewComponent(reqCtx intctrlutil.RequestCtx,
 		return nil, nil
 	}
 
-	return newRSMComponent(cli, reqCtx.Recorder, cluster, version, synthesizedComp, dag), nil
+	return newRSMComponent(cli, reqCtx.Recorder, cluster, version, synthesizedComp, dag,
+		cluster.IsClusterDefinitionUpdatePending(definition)), nil
 }
 
 func getClassManager(ctx context.Context, cli types2.ReadonlyClient, cluster *appsv1alpha1.Cluster) (*class.Manager, error) {
@@ -28,11 +28,13 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -43,6 +45,7 @@ import (
 	componentutil "github.com/apecloud/kubeblocks/internal/controller/component"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
 	"github.com/apecloud/kubeblocks/internal/controller/model"
+	rsmcore "github.com/apecloud/kubeblocks/internal/controller/rsm"
 	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	"github.com/apecloud/kubeblocks/internal/generics"
@@ -143,6 +146,109 @@ func IsFailedOrAbnormal(phase appsv1alpha1.ClusterComponentPhase) bool {
 		appsv1alpha1.AbnormalClusterCompPhase}, phase) != -1
 }
 
+// summarizePods summarizes the readiness of the pods of the component into a human readable string,
+// e.g. "2/3 pods ready, 1 ImagePullBackOff", the pods not ready are counted by the reasons they are stuck in.
+func summarizePods(pods []*corev1.Pod, replicas int32) string {
+	ready := 0
+	counts := map[string]int{}
+	for _, pod := range pods {
+		if intctrlutil.PodIsReady(pod) {
+			ready++
+			continue
+		}
+		counts[getPodNotReadyReason(pod)]++
+	}
+	total := int(replicas)
+	if len(pods) > total {
+		total = len(pods)
+	}
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	// the most common reasons come first
+	slices.SortFunc(reasons, func(a, b string) bool {
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+	items := []string{fmt.Sprintf("%d/%d pods ready", ready, total)}
+	for _, reason := range reasons {
+		items = append(items, fmt.Sprintf("%d %s", counts[reason], reason))
+	}
+	return strings.Join(items, ", ")
+}
+
+// summarizePodNodes returns the nodes hosting the pods keyed by the pod names, and the nodes hosting multiple pods.
+func summarizePodNodes(pods []*corev1.Pod) (map[string]string, []string) {
+	podNodes := map[string]string{}
+	podsOnNode := map[string]int{}
+	for _, pod := range pods {
+		if len(pod.Spec.NodeName) == 0 {
+			continue
+		}
+		podNodes[pod.Name] = pod.Spec.NodeName
+		podsOnNode[pod.Spec.NodeName]++
+	}
+	var sharedNodes []string
+	for node, count := range podsOnNode {
+		if count > 1 {
+			sharedNodes = append(sharedNodes, node)
+		}
+	}
+	slices.Sort(sharedNodes)
+	if len(podNodes) == 0 {
+		podNodes = nil
+	}
+	return podNodes, sharedNodes
+}
+
+// getPodNotReadyReason gets the reason why the pod is not ready in the way kubectl shows it,
+// the waiting or failed containers take precedence over the conditions of the pod.
+func getPodNotReadyReason(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		if len(pod.Status.Reason) > 0 {
+			return pod.Status.Reason
+		}
+		return string(corev1.PodFailed)
+	}
+	containerReason := func(status corev1.ContainerStatus) string {
+		switch {
+		case status.State.Waiting != nil && len(status.State.Waiting.Reason) > 0:
+			return status.State.Waiting.Reason
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+			if len(status.State.Terminated.Reason) > 0 {
+				return status.State.Terminated.Reason
+			}
+			return "Error"
+		}
+		return ""
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if reason := containerReason(status); len(reason) > 0 {
+			return "Init:" + reason
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if reason := containerReason(status); len(reason) > 0 {
+			return reason
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && len(condition.Reason) > 0 {
+			return condition.Reason
+		}
+	}
+	if pod.Status.Phase == corev1.PodPending || len(pod.Status.Phase) == 0 {
+		return string(corev1.PodPending)
+	}
+	return "NotReady"
+}
+
 // getComponentMatchLabels gets the labels for matching the cluster component
 func getComponentMatchLabels(clusterName, componentName string) map[string]string {
 	return client.MatchingLabels{
@@ -487,7 +593,7 @@ func ConvertRSMToSTS(rsm *workloads.ReplicatedStateMachine) *appsv1.StatefulSet
 	if rsm == nil {
 		return nil
 	}
-	sts := builder.NewStatefulSetBuilder(rsm.Namespace, rsm.Name).
+	sts := builder.NewStatefulSetBuilder(rsm.Namespace, rsmcore.GetStatefulSetName(rsm)).
 		SetUID(rsm.UID).
 		AddLabelsInMap(rsm.Labels).
 		AddAnnotationsInMap(rsm.Annotations).
@@ -498,6 +604,7 @@ func ConvertRSMToSTS(rsm *workloads.ReplicatedStateMachine) *appsv1.StatefulSet
 		SetVolumeClaimTemplates(rsm.Spec.VolumeClaimTemplates...).
 		SetPodManagementPolicy(rsm.Spec.PodManagementPolicy).
 		SetUpdateStrategy(rsm.Spec.UpdateStrategy).
+		SetOrdinals(rsm.Spec.Ordinals).
 		GetObject()
 	sts.Generation = rsm.Generation
 	sts.Status = rsm.Status.StatefulSetStatus
@@ -620,6 +727,115 @@ func updateComponentInfoToPods(
 	return nil
 }
 
+// updateEvictionProtectionToPods adds the eviction protection annotations to the pods of the protected roles,
+// and removes them from the pods that are no longer protected, e.g. after a switchover or the protection is turned off.
+// The keys of the annotations added are tracked in the pod, so the stale ones are removed even if they are changed.
+func updateEvictionProtectionToPods(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	component *componentutil.SynthesizedComponent,
+	roles []workloads.ReplicaRole,
+	dag *graph.DAG) error {
+	if cluster == nil || component == nil {
+		return nil
+	}
+	protectedRoles := make(map[string]bool)
+	protectionAnnotations := map[string]string{}
+	if component.EvictionProtection != nil {
+		for _, role := range component.EvictionProtection.Roles {
+			protectedRoles[strings.ToLower(role)] = true
+		}
+		if len(protectedRoles) == 0 {
+			for _, role := range roles {
+				if role.IsLeader {
+					protectedRoles[strings.ToLower(role.Name)] = true
+				}
+			}
+		}
+		protectionAnnotations = component.EvictionProtection.Annotations
+		if len(protectionAnnotations) == 0 {
+			protectionAnnotations = map[string]string{constant.SafeToEvictAnnotationKey: "false"}
+		}
+	}
+	protectionKeys := maps.Keys(protectionAnnotations)
+	slices.Sort(protectionKeys)
+
+	podList := corev1.PodList{}
+	ml := client.MatchingLabels{
+		constant.AppInstanceLabelKey:    cluster.GetName(),
+		constant.KBAppComponentLabelKey: component.Name,
+	}
+	if err := cli.List(ctx, &podList, client.InNamespace(cluster.Namespace), ml); err != nil {
+		return err
+	}
+	// list all pods in dag
+	podVertices := ictrltypes.FindAll[*corev1.Pod](dag)
+
+	// updateAnnotations returns whether the annotations of the object are changed.
+	updateAnnotations := func(obj client.Object, protected bool) bool {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		changed := false
+		// remove the annotations added before which are no longer desired.
+		for _, k := range strings.Split(annotations[constant.EvictionProtectionAnnotationsKey], ",") {
+			if _, ok := protectionAnnotations[k]; len(k) == 0 || (protected && ok) {
+				continue
+			}
+			if _, ok := annotations[k]; ok {
+				delete(annotations, k)
+				changed = true
+			}
+		}
+		for k, v := range protectionAnnotations {
+			value, ok := annotations[k]
+			switch {
+			case protected && (!ok || value != v):
+				annotations[k] = v
+				changed = true
+			case !protected && ok && value == v:
+				delete(annotations, k)
+				changed = true
+			}
+		}
+		trackedKeys := ""
+		if protected {
+			trackedKeys = strings.Join(protectionKeys, ",")
+		}
+		if annotations[constant.EvictionProtectionAnnotationsKey] != trackedKeys {
+			if len(trackedKeys) == 0 {
+				delete(annotations, constant.EvictionProtectionAnnotationsKey)
+			} else {
+				annotations[constant.EvictionProtectionAnnotationsKey] = trackedKeys
+			}
+			changed = true
+		}
+		obj.SetAnnotations(annotations)
+		return changed
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		protected := protectedRoles[strings.ToLower(pod.Labels[constant.RoleLabelKey])]
+		idx := slices.IndexFunc(podVertices, func(vertex graph.Vertex) bool {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			return v.Obj.GetName() == pod.Name
+		})
+		// pod already in dag, merge annotations
+		if idx >= 0 {
+			v, _ := podVertices[idx].(*ictrltypes.LifecycleVertex)
+			updateAnnotations(v.Obj, protected)
+			continue
+		}
+		// pod not in dag, add a new vertex if changed
+		if updateAnnotations(pod, protected) {
+			dag.AddVertex(&ictrltypes.LifecycleVertex{Obj: pod, Action: ictrltypes.ActionUpdatePtr()})
+		}
+	}
+	return nil
+}
+
 // updateCustomLabelToPods updates custom label to pods
 func updateCustomLabelToPods(ctx context.Context,
 	cli client.Client,
@@ -735,7 +951,7 @@ func IsComponentPodsWithLatestRevision(ctx context.Context, cli client.Client,
 	}
 	// check whether the underlying workload(sts) has sent the latest template to pods
 	sts := &appsv1.StatefulSet{}
-	if err := cli.Get(ctx, client.ObjectKeyFromObject(rsm), sts); err != nil {
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: rsm.Namespace, Name: rsmcore.GetStatefulSetName(rsm)}, sts); err != nil {
 		return false, err
 	}
 	if sts.Status.ObservedGeneration != sts.Generation {
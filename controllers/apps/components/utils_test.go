@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	"github.com/apecloud/kubeblocks/internal/controller/builder"
 	"github.com/apecloud/kubeblocks/internal/controller/component"
@@ -50,6 +51,112 @@ func TestIsFailedOrAbnormal(t *testing.T) {
 	}
 }
 
+func TestSummarizePods(t *testing.T) {
+	readyPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	waitingPod := func(reason string, init bool) *corev1.Pod {
+		status := corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}
+		pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}}
+		if init {
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{status}
+		} else {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{status}
+		}
+		return pod
+	}
+	unschedulablePod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:   corev1.PodScheduled,
+				Status: corev1.ConditionFalse,
+				Reason: corev1.PodReasonUnschedulable,
+			}},
+		},
+	}
+	crashedPod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			}},
+		},
+	}
+	notReadyPod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	terminatingPod := readyPod()
+	terminatingPod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	evictedPod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}}
+
+	tests := []struct {
+		name     string
+		pods     []*corev1.Pod
+		replicas int32
+		expected string
+	}{
+		{"all ready", []*corev1.Pod{readyPod(), readyPod(), readyPod()}, 3, "3/3 pods ready"},
+		{"no pods", nil, 3, "0/3 pods ready"},
+		{"image pull back-off", []*corev1.Pod{readyPod(), readyPod(), waitingPod("ImagePullBackOff", false)}, 3,
+			"2/3 pods ready, 1 ImagePullBackOff"},
+		{"mixed reasons sorted by count", []*corev1.Pod{waitingPod("CrashLoopBackOff", false), unschedulablePod,
+			waitingPod("CrashLoopBackOff", false), waitingPod("ErrImagePull", true)}, 4,
+			"0/4 pods ready, 2 CrashLoopBackOff, 1 Init:ErrImagePull, 1 Unschedulable"},
+		{"terminated and readiness failed", []*corev1.Pod{readyPod(), crashedPod, notReadyPod}, 3,
+			"1/3 pods ready, 1 Error, 1 NotReady"},
+		{"scaling in", []*corev1.Pod{readyPod(), readyPod(), terminatingPod}, 2, "2/3 pods ready, 1 Terminating"},
+		{"failed pod", []*corev1.Pod{readyPod(), evictedPod}, 2, "1/2 pods ready, 1 Evicted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if summary := summarizePods(tt.pods, tt.replicas); summary != tt.expected {
+				t.Errorf("expected %q, but got %q", tt.expected, summary)
+			}
+		})
+	}
+}
+
+func TestSummarizePodNodes(t *testing.T) {
+	newPod := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	podNodes, sharedNodes := summarizePodNodes([]*corev1.Pod{newPod("pod-0", "node-a"), newPod("pod-1", "node-b"), newPod("pod-2", "")})
+	if !reflect.DeepEqual(podNodes, map[string]string{"pod-0": "node-a", "pod-1": "node-b"}) {
+		t.Errorf("unexpected pod nodes: %v", podNodes)
+	}
+	if len(sharedNodes) != 0 {
+		t.Errorf("expected no shared nodes, but got %v", sharedNodes)
+	}
+
+	// the co-located pods are flagged.
+	podNodes, sharedNodes = summarizePodNodes([]*corev1.Pod{newPod("pod-0", "node-b"), newPod("pod-1", "node-a"),
+		newPod("pod-2", "node-b"), newPod("pod-3", "node-a"), newPod("pod-4", "node-c")})
+	if len(podNodes) != 5 || podNodes["pod-2"] != "node-b" {
+		t.Errorf("unexpected pod nodes: %v", podNodes)
+	}
+	if !reflect.DeepEqual(sharedNodes, []string{"node-a", "node-b"}) {
+		t.Errorf("expected shared nodes [node-a node-b], but got %v", sharedNodes)
+	}
+
+	podNodes, sharedNodes = summarizePodNodes(nil)
+	if podNodes != nil || sharedNodes != nil {
+		t.Errorf("expected nothing reported for no pods")
+	}
+}
+
 func TestIsProbeTimeout(t *testing.T) {
 	podsReadyTime := &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
 	compDef := &appsv1alpha1.ClusterComponentDefinition{
@@ -254,6 +361,133 @@ var _ = Describe("Component", func() {
 			})
 		})
 	})
+
+	Context("updateEvictionProtectionToPods func", func() {
+		It("should only protect the pods of the leader role", func() {
+			_, _, cluster := testapps.InitClusterWithHybridComps(&testCtx, clusterDefName,
+				clusterVersionName, clusterName, statelessCompName, "stateful", consensusCompName)
+			sts := testapps.MockConsensusComponentStatefulSet(&testCtx, clusterName, consensusCompName)
+			pods := testapps.MockConsensusComponentPods(&testCtx, sts, clusterName, consensusCompName)
+			roles := []workloads.ReplicaRole{
+				{Name: "leader", IsLeader: true, CanVote: true},
+				{Name: "follower", IsLeader: false, CanVote: true},
+			}
+			comp := &component.SynthesizedComponent{
+				Name:               consensusCompName,
+				EvictionProtection: &appsv1alpha1.EvictionProtection{},
+			}
+
+			By("mark a stale protection on a follower pod")
+			var follower *corev1.Pod
+			for _, pod := range pods {
+				if pod.Labels[constant.RoleLabelKey] != "leader" {
+					follower = pod
+					break
+				}
+			}
+			Expect(follower).ShouldNot(BeNil())
+			Expect(testapps.ChangeObj(&testCtx, follower, func(pod *corev1.Pod) {
+				if pod.Annotations == nil {
+					pod.Annotations = map[string]string{}
+				}
+				pod.Annotations[constant.SafeToEvictAnnotationKey] = "false"
+			})).Should(Succeed())
+
+			dag := graph.NewDAG()
+			Expect(updateEvictionProtectionToPods(testCtx.Ctx, k8sClient, cluster, comp, roles, dag)).Should(Succeed())
+			annotated := map[string]map[string]string{}
+			for _, vertex := range types.FindAll[*corev1.Pod](dag) {
+				v, _ := vertex.(*types.LifecycleVertex)
+				annotated[v.Obj.GetName()] = v.Obj.GetAnnotations()
+			}
+			for _, pod := range pods {
+				if pod.Labels[constant.RoleLabelKey] == "leader" {
+					Expect(annotated).Should(HaveKey(pod.Name))
+					Expect(annotated[pod.Name]).Should(HaveKeyWithValue(constant.SafeToEvictAnnotationKey, "false"))
+					continue
+				}
+				if pod.Name == follower.Name {
+					Expect(annotated).Should(HaveKey(pod.Name))
+					Expect(annotated[pod.Name]).ShouldNot(HaveKey(constant.SafeToEvictAnnotationKey))
+					continue
+				}
+				Expect(annotated).ShouldNot(HaveKey(pod.Name))
+			}
+		})
+
+		It("should remove the stale protection once it's turned off", func() {
+			_, _, cluster := testapps.InitClusterWithHybridComps(&testCtx, clusterDefName,
+				clusterVersionName, clusterName, statelessCompName, "stateful", consensusCompName)
+			sts := testapps.MockConsensusComponentStatefulSet(&testCtx, clusterName, consensusCompName)
+			pods := testapps.MockConsensusComponentPods(&testCtx, sts, clusterName, consensusCompName)
+			roles := []workloads.ReplicaRole{
+				{Name: "leader", IsLeader: true, CanVote: true},
+				{Name: "follower", IsLeader: false, CanVote: true},
+			}
+			const protectionKey = "descheduler.alpha.kubernetes.io/prevent-eviction"
+			comp := &component.SynthesizedComponent{
+				Name: consensusCompName,
+				EvictionProtection: &appsv1alpha1.EvictionProtection{
+					Annotations: map[string]string{protectionKey: "true"},
+				},
+			}
+
+			// reconcile applies the annotations of the pods in the DAG and returns them.
+			reconcile := func() map[string]map[string]string {
+				dag := graph.NewDAG()
+				Expect(updateEvictionProtectionToPods(testCtx.Ctx, k8sClient, cluster, comp, roles, dag)).Should(Succeed())
+				annotated := map[string]map[string]string{}
+				for _, vertex := range types.FindAll[*corev1.Pod](dag) {
+					v, _ := vertex.(*types.LifecycleVertex)
+					pod, _ := v.Obj.(*corev1.Pod)
+					Expect(k8sClient.Update(testCtx.Ctx, pod)).Should(Succeed())
+					annotated[pod.Name] = pod.Annotations
+				}
+				for name, annotations := range annotated {
+					Eventually(testapps.CheckObj(&testCtx, client.ObjectKey{Namespace: cluster.Namespace, Name: name},
+						func(g Gomega, pod *corev1.Pod) {
+							g.Expect(pod.Annotations).Should(HaveLen(len(annotations)))
+							for k, v := range annotations {
+								g.Expect(pod.Annotations).Should(HaveKeyWithValue(k, v))
+							}
+						})).Should(Succeed())
+				}
+				return annotated
+			}
+
+			var leader *corev1.Pod
+			for _, pod := range pods {
+				if pod.Labels[constant.RoleLabelKey] == "leader" {
+					leader = pod
+					break
+				}
+			}
+			Expect(leader).ShouldNot(BeNil())
+
+			By("protect the leader pod with the custom annotation")
+			annotated := reconcile()
+			Expect(annotated).Should(HaveKey(leader.Name))
+			Expect(annotated[leader.Name]).Should(HaveKeyWithValue(protectionKey, "true"))
+			Expect(annotated[leader.Name]).Should(HaveKeyWithValue(constant.EvictionProtectionAnnotationsKey, protectionKey))
+
+			By("change the protection annotation, the former one is removed")
+			comp.EvictionProtection.Annotations = nil
+			annotated = reconcile()
+			Expect(annotated).Should(HaveKey(leader.Name))
+			Expect(annotated[leader.Name]).ShouldNot(HaveKey(protectionKey))
+			Expect(annotated[leader.Name]).Should(HaveKeyWithValue(constant.SafeToEvictAnnotationKey, "false"))
+
+			By("turn off the protection, all the annotations are removed")
+			comp.EvictionProtection = nil
+			annotated = reconcile()
+			Expect(annotated).Should(HaveKey(leader.Name))
+			Expect(annotated[leader.Name]).ShouldNot(HaveKey(constant.SafeToEvictAnnotationKey))
+			Expect(annotated[leader.Name]).ShouldNot(HaveKey(constant.EvictionProtectionAnnotationsKey))
+
+			By("nothing to update afterwards")
+			Expect(reconcile()).Should(BeEmpty())
+		})
+	})
 })
 
 var _ = Describe("Component utils test", func() {
@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configuration
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+// reconcileConfigDrift checks whether the ConfigMap of the config template is edited out of band, and handles
+// the drift following the policy of the cluster: Flag keeps the changes, Revert overwrites them with the rendered version.
+// It returns whether the drift is kept.
+func (r *ConfigurationReconciler) reconcileConfigDrift(taskCtx TaskContext,
+	task Task,
+	synthesizedComp *component.SynthesizedComponent,
+	revision string) (bool, error) {
+	fetcher := taskCtx.fetcher
+	item := taskCtx.configuration.Spec.GetConfigurationItem(task.Name)
+	if item == nil || item.ConfigSpec == nil {
+		return false, nil
+	}
+	// fetch the ConfigMap again as it may be updated by the task.
+	if err := fetcher.ConfigMap(item.Name).Complete(); err != nil {
+		return false, err
+	}
+	configMap := fetcher.ConfigMapObj
+	// the ConfigMap waiting to be rendered with the new parameters is not drifted.
+	if !intctrlutil.IsApplyConfigChanged(configMap, *item) || !intctrlutil.IsConfigDrifted(configMap) {
+		return false, nil
+	}
+	if intctrlutil.GetConfigDriftPolicy(fetcher.ClusterObj) != appsv1alpha1.RevertConfigDriftPolicy {
+		return true, nil
+	}
+	if err := revertConfigDrift(fetcher, *item, task.Status, synthesizedComp, revision); err != nil {
+		return false, err
+	}
+	r.Recorder.Eventf(taskCtx.configuration, corev1.EventTypeNormal, appsv1alpha1.ReasonConfigDriftReverted,
+		"the out-of-band changes of ConfigMap %s are reverted to the rendered version", configMap.Name)
+	return false, nil
+}
+
+// syncConfigDriftCondition sets the ConfigDrift condition if any ConfigMap of the config templates is drifted, or removes it.
+func (r *ConfigurationReconciler) syncConfigDriftCondition(configuration *appsv1alpha1.Configuration, driftedItems []string) {
+	if len(driftedItems) == 0 {
+		meta.RemoveStatusCondition(&configuration.Status.Conditions, appsv1alpha1.ConditionTypeConfigDrift)
+		return
+	}
+	message := fmt.Sprintf("the ConfigMaps of config templates [%s] are edited out of band and differ from the rendered version",
+		strings.Join(driftedItems, ","))
+	condition := meta.FindStatusCondition(configuration.Status.Conditions, appsv1alpha1.ConditionTypeConfigDrift)
+	if condition == nil || condition.Message != message {
+		r.Recorder.Event(configuration, corev1.EventTypeWarning, appsv1alpha1.ReasonConfigDrifted, message)
+	}
+	meta.SetStatusCondition(&configuration.Status.Conditions, metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeConfigDrift,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: configuration.Generation,
+		Reason:             appsv1alpha1.ReasonConfigDrifted,
+		Message:            message,
+	})
+}
@@ -159,13 +159,23 @@ func (r *ConfigurationReconciler) runTasks(taskCtx TaskContext, tasks []Task) (e
 	// TODO manager multiple version
 	patch := client.MergeFrom(configuration.DeepCopy())
 	revision := strconv.FormatInt(configuration.GetGeneration(), 10)
+	var driftedItems []string
 	for _, task := range tasks {
 		task.Status.UpdateRevision = revision
 		if err := task.Do(taskCtx.fetcher, synthesizedComp, revision); err != nil {
 			errs = append(errs, err)
 			continue
 		}
+		drifted, err := r.reconcileConfigDrift(taskCtx, task, synthesizedComp, revision)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if drifted {
+			driftedItems = append(driftedItems, task.Name)
+		}
 	}
+	r.syncConfigDriftCondition(configuration, driftedItems)
 
 	configuration.Status.Message = ""
 	if len(errs) > 0 {
@@ -25,11 +25,15 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/configuration/core"
 	cfgutil "github.com/apecloud/kubeblocks/internal/configuration/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 )
@@ -115,4 +119,94 @@ var _ = Describe("Configuration Controller", func() {
 		})
 	})
 
+	Context("When the ConfigMap is edited out of band", func() {
+		const editedSuffix = "\n# edited out of band\n"
+
+		var (
+			cfgKey client.ObjectKey
+			cmKey  client.ObjectKey
+		)
+
+		// mockEditedConfigMap waits for the configuration to be rendered, and edits the rendered ConfigMap directly.
+		mockEditedConfigMap := func(policy appsv1alpha1.ConfigDriftPolicy) map[string]string {
+			_, _, clusterObj, clusterVersionObj, synthesizedComp := mockReconcileResource()
+			cfgKey = client.ObjectKey{
+				Name:      core.GenerateComponentConfigurationName(clusterName, statefulCompName),
+				Namespace: testCtx.DefaultNamespace,
+			}
+			cmKey = client.ObjectKey{
+				Name:      core.GetComponentCfgName(clusterName, statefulCompName, configSpecName),
+				Namespace: testCtx.DefaultNamespace,
+			}
+
+			By("set the config drift policy of the cluster")
+			Expect(testapps.GetAndChangeObj(&testCtx, client.ObjectKeyFromObject(clusterObj), func(cluster *appsv1alpha1.Cluster) {
+				if cluster.Annotations == nil {
+					cluster.Annotations = map[string]string{}
+				}
+				cluster.Annotations[constant.ConfigDriftPolicyAnnotationKey] = string(policy)
+			})()).Should(Succeed())
+
+			Expect(initConfiguration(&intctrlutil.ResourceCtx{
+				Client:        k8sClient,
+				Context:       ctx,
+				Namespace:     testCtx.DefaultNamespace,
+				ClusterName:   clusterName,
+				ComponentName: statefulCompName,
+			}, synthesizedComp, clusterObj, clusterVersionObj)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, cfgKey, func(g Gomega, cfg *appsv1alpha1.Configuration) {
+				itemStatus := cfg.Status.GetItemStatus(configSpecName)
+				g.Expect(itemStatus).ShouldNot(BeNil())
+				g.Expect(itemStatus.Phase).Should(BeEquivalentTo(appsv1alpha1.CFinishedPhase))
+			}), time.Second*60, time.Second*1).Should(Succeed())
+
+			By("edit the rendered ConfigMap directly")
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, cmKey, cm)).Should(Succeed())
+			renderedData := cm.Data
+			Expect(testapps.GetAndChangeObj(&testCtx, cmKey, func(cm *corev1.ConfigMap) {
+				for key := range cm.Data {
+					cm.Data[key] += editedSuffix
+				}
+			})()).Should(Succeed())
+			return renderedData
+		}
+
+		It("should revert the changes in Revert mode", func() {
+			renderedData := mockEditedConfigMap(appsv1alpha1.RevertConfigDriftPolicy)
+
+			Eventually(testapps.CheckObj(&testCtx, cmKey, func(g Gomega, cm *corev1.ConfigMap) {
+				g.Expect(cm.Data).Should(Equal(renderedData))
+				g.Expect(intctrlutil.IsConfigDrifted(cm)).Should(BeFalse())
+			}), time.Second*60, time.Second*1).Should(Succeed())
+			Consistently(testapps.CheckObj(&testCtx, cfgKey, func(g Gomega, cfg *appsv1alpha1.Configuration) {
+				g.Expect(meta.FindStatusCondition(cfg.Status.Conditions, appsv1alpha1.ConditionTypeConfigDrift)).Should(BeNil())
+			})).Should(Succeed())
+		})
+
+		It("should keep the changes and flag the drift in Flag mode", func() {
+			renderedData := mockEditedConfigMap(appsv1alpha1.FlagConfigDriftPolicy)
+
+			Eventually(testapps.CheckObj(&testCtx, cfgKey, func(g Gomega, cfg *appsv1alpha1.Configuration) {
+				condition := meta.FindStatusCondition(cfg.Status.Conditions, appsv1alpha1.ConditionTypeConfigDrift)
+				g.Expect(condition).ShouldNot(BeNil())
+				g.Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+				g.Expect(condition.Message).Should(ContainSubstring(configSpecName))
+			}), time.Second*60, time.Second*1).Should(Succeed())
+			Consistently(testapps.CheckObj(&testCtx, cmKey, func(g Gomega, cm *corev1.ConfigMap) {
+				for key, value := range renderedData {
+					g.Expect(cm.Data[key]).Should(Equal(value + editedSuffix))
+				}
+			})).Should(Succeed())
+
+			By("restore the ConfigMap to clear the drift")
+			Expect(testapps.GetAndChangeObj(&testCtx, cmKey, func(cm *corev1.ConfigMap) {
+				cm.Data = renderedData
+			})()).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, cfgKey, func(g Gomega, cfg *appsv1alpha1.Configuration) {
+				g.Expect(meta.FindStatusCondition(cfg.Status.Conditions, appsv1alpha1.ConditionTypeConfigDrift)).Should(BeNil())
+			}), time.Second*60, time.Second*1).Should(Succeed())
+		})
+	})
+
 })
@@ -105,6 +105,30 @@ func syncImpl(fetcher *Task,
 	return
 }
 
+// revertConfigDrift renders the config template again to overwrite the out-of-band changes of the ConfigMap.
+func revertConfigDrift(fetcher *Task,
+	item appsv1alpha1.ConfigurationItemDetail,
+	status *appsv1alpha1.ConfigurationItemDetailStatus,
+	component *component.SynthesizedComponent,
+	revision string) error {
+	return configuration.NewReconcilePipeline(configuration.ReconcileCtx{
+		ResourceCtx: fetcher.ResourceCtx,
+		Cluster:     fetcher.ClusterObj,
+		ClusterVer:  fetcher.ClusterVerObj,
+		Component:   component,
+		PodSpec:     component.PodSpec,
+	}, item, status, item.ConfigSpec).
+		ConfigMap(item.Name).
+		ConfigConstraints(item.ConfigSpec.ConfigConstraintRef).
+		ForceRerender().
+		PrepareForTemplate().
+		RerenderTemplate().
+		ApplyParameters().
+		UpdateConfigVersion(revision).
+		Sync().
+		Complete()
+}
+
 func syncStatus(configMap *corev1.ConfigMap, status *appsv1alpha1.ConfigurationItemDetailStatus) (err error) {
 	annotations := configMap.GetAnnotations()
 	// status.CurrentRevision = GetCurrentRevision(annotations)
@@ -34,6 +34,10 @@ const (
 	// If debugClusterAnnotationKey = 'on',
 	// logs will be recorded in more details, and some ephemeral pods (esp. those created by jobs) will retain after execution.
 	debugClusterAnnotationKey = "cluster.kubeblocks.io/debug"
+	// forceRunTransformersAnnotationKey is used to diagnose a stuck reconciliation.
+	// Its value is a comma-separated list of transformer names, e.g. AssureMetaTransformer,
+	// and the listed transformers run even if the transformer chain is stopped prematurely.
+	forceRunTransformersAnnotationKey = "cluster.kubeblocks.io/force-run-transformers"
 
 	// annotations values
 	// lifecycleDeletePVCAnnotation = "delete-pvc"
@@ -46,6 +50,10 @@ const (
 	reasonOpsDoActionFailed           = "DoActionFailed"
 )
 
+const (
+	reasonUnknownForcedTransformers = "UnknownForcedTransformers"
+)
+
 const (
 	trueVal = "true"
 )
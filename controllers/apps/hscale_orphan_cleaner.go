@@ -0,0 +1,163 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/controllers/apps/components"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+const defaultHScaleOrphanScanInterval = 10 * time.Minute
+
+// HScaleOrphanCleaner scans the intermediate resources left by the data clone of the HorizontalScaling OpsRequests
+// periodically, and cleans up the ones whose OpsRequest is deleted or completed. It catches the leftovers missed
+// by the OpsRequest controller, e.g. the operator crashed before the OpsRequest failed.
+type HScaleOrphanCleaner struct {
+	client.Client
+	Recorder record.EventRecorder
+	// Interval is the interval between the scans, it defaults to 10 minutes.
+	Interval time.Duration
+}
+
+var _ manager.Runnable = &HScaleOrphanCleaner{}
+
+// SetupWithManager sets up the cleaner to run with the Manager.
+func (r *HScaleOrphanCleaner) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(r)
+}
+
+// Start runs the scans until the context is done, it only runs on the leader.
+func (r *HScaleOrphanCleaner) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval == 0 {
+		interval = defaultHScaleOrphanScanInterval
+	}
+	logger := log.FromContext(ctx).WithName("hscale-orphan-cleaner")
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.scan(ctx); err != nil {
+			logger.Error(err, "failed to clean up the orphaned resources of horizontal scaling")
+		}
+	}, interval)
+	return nil
+}
+
+// scan finds the OpsRequests referred by the intermediate resources, and cleans up the resources of
+// the OpsRequests which are deleted or completed.
+func (r *HScaleOrphanCleaner) scan(ctx context.Context) error {
+	selector, err := labels.Parse(fmt.Sprintf("%s,%s=%s", constant.OpsRequestNameLabelKey,
+		constant.OpsRequestTypeLabelKey, appsv1alpha1.HorizontalScalingType))
+	if err != nil {
+		return err
+	}
+	listOpts := client.MatchingLabelsSelector{Selector: selector}
+	opsKeys := map[types.NamespacedName]struct{}{}
+	addOpsKey := func(obj client.Object) {
+		opsKeys[types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetLabels()[constant.OpsRequestNameLabelKey]}] = struct{}{}
+	}
+
+	backupList := &dpv1alpha1.BackupList{}
+	if err = r.List(ctx, backupList, listOpts); err != nil {
+		return err
+	}
+	for i := range backupList.Items {
+		addOpsKey(&backupList.Items[i])
+	}
+	restoreList := &dpv1alpha1.RestoreList{}
+	if err = r.List(ctx, restoreList, listOpts); err != nil {
+		return err
+	}
+	for i := range restoreList.Items {
+		addOpsKey(&restoreList.Items[i])
+	}
+	vsCli := &intctrlutil.VolumeSnapshotCompatClient{Client: r.Client, Ctx: ctx}
+	snapshotList := &snapshotv1.VolumeSnapshotList{}
+	if err = vsCli.List(snapshotList, listOpts); err != nil {
+		return err
+	}
+	for i := range snapshotList.Items {
+		addOpsKey(&snapshotList.Items[i])
+	}
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err = r.List(ctx, pvcList, listOpts); err != nil {
+		return err
+	}
+	for i := range pvcList.Items {
+		// the bound PVCs are kept, there is no need to check their OpsRequests.
+		if pvcList.Items[i].Status.Phase != corev1.ClaimBound {
+			addOpsKey(&pvcList.Items[i])
+		}
+	}
+
+	for opsKey := range opsKeys {
+		if err = r.cleanupOrphans(ctx, opsKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupOrphans cleans up the intermediate resources of the OpsRequest if it's deleted or completed.
+func (r *HScaleOrphanCleaner) cleanupOrphans(ctx context.Context, opsKey types.NamespacedName) error {
+	opsRequest := &appsv1alpha1.OpsRequest{}
+	if err := r.Get(ctx, opsKey, opsRequest); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		opsRequest = nil
+	} else if !opsRequest.IsComplete() {
+		return nil
+	}
+	cleaned, err := components.CleanupHScaleTmpResources(ctx, r.Client, opsKey.Namespace, opsKey.Name)
+	if err != nil || len(cleaned) == 0 || opsRequest == nil {
+		return err
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(opsRequest, corev1.EventTypeNormal, "OrphanResourcesCleaned",
+			"the orphaned resources of the horizontal scaling are cleaned: %s", strings.Join(cleaned, ", "))
+	}
+	patch := client.MergeFrom(opsRequest.DeepCopy())
+	for _, v := range cleaned {
+		if !slices.Contains(opsRequest.Status.CleanedResources, v) {
+			opsRequest.Status.CleanedResources = append(opsRequest.Status.CleanedResources, v)
+		}
+	}
+	return r.Status().Patch(ctx, opsRequest, patch)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apecloud/kubeblocks/controllers/apps/components"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("horizontal scaling orphan cleaner test", func() {
+	const (
+		compName = "mysql"
+	)
+
+	var (
+		ctx         = context.Background()
+		clusterName string
+		opsName     string
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResources(&testCtx, generics.PersistentVolumeClaimSignature, inNS, ml)
+		clusterName = "cluster-for-orphan-" + testCtx.GetRandomStr()
+		opsName = "hscale-for-orphan-" + testCtx.GetRandomStr()
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	It("should clean up the leftovers of the deleted OpsRequest", func() {
+		opsLabels := components.GetHScaleTmpResourceLabels(opsName)
+
+		By("mock the leftovers of the data clone after the operator crashed")
+		boundPVCName := fmt.Sprintf("%s-%s-%s-%d", testapps.DataVolumeName, clusterName, compName, 1)
+		boundPVC := testapps.NewPersistentVolumeClaimFactory(testCtx.DefaultNamespace, boundPVCName, clusterName,
+			compName, testapps.DataVolumeName).AddLabelsInMap(opsLabels).SetStorage("1Gi").CheckedCreate(&testCtx).GetObject()
+		Expect(testapps.ChangeObjStatus(&testCtx, boundPVC, func() {
+			boundPVC.Status.Phase = corev1.ClaimBound
+		})).Should(Succeed())
+		unboundPVCName := fmt.Sprintf("%s-%s-%s-%d", testapps.DataVolumeName, clusterName, compName, 2)
+		testapps.NewPersistentVolumeClaimFactory(testCtx.DefaultNamespace, unboundPVCName, clusterName,
+			compName, testapps.DataVolumeName).AddLabelsInMap(opsLabels).SetStorage("1Gi").CheckedCreate(&testCtx)
+		snapshotName := fmt.Sprintf("%s-%s-scaling-0", clusterName, compName)
+		sourcePVCName := fmt.Sprintf("%s-%s-%s-%d", testapps.DataVolumeName, clusterName, compName, 0)
+		testapps.CreateK8sResource(&testCtx, &snapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       snapshotName,
+				Namespace:  testCtx.DefaultNamespace,
+				Labels:     opsLabels,
+				Finalizers: []string{dptypes.DataProtectionFinalizerName},
+			},
+			Spec: snapshotv1.VolumeSnapshotSpec{
+				Source: snapshotv1.VolumeSnapshotSource{PersistentVolumeClaimName: &sourcePVCName},
+			},
+		})
+
+		By("scan the orphans")
+		cleaner := &HScaleOrphanCleaner{Client: k8sClient}
+		Expect(cleaner.scan(ctx)).Should(Succeed())
+
+		By("check the snapshot and the unbound PVC are deleted, and the bound PVC is kept")
+		Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: snapshotName},
+			&snapshotv1.VolumeSnapshot{}, false)).Should(Succeed())
+		Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: unboundPVCName},
+			&corev1.PersistentVolumeClaim{}, false)).Should(Succeed())
+		Consistently(testapps.CheckObjExists(&testCtx, client.ObjectKeyFromObject(boundPVC),
+			&corev1.PersistentVolumeClaim{}, true)).Should(Succeed())
+	})
+})
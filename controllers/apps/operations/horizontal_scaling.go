@@ -22,6 +22,7 @@ package operations
 import (
 	"time"
 
+	"golang.org/x/exp/slices"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -82,7 +83,32 @@ func (hs horizontalScalingOpsHandler) ReconcileAction(reqCtx intctrlutil.Request
 		compStatus *appsv1alpha1.OpsRequestComponentStatus) (int32, int32, error) {
 		return handleComponentProgressForScalingReplicas(reqCtx, cli, opsRes, pgRes, compStatus, hs.getExpectReplicas)
 	}
-	return reconcileActionWithComponentOps(reqCtx, cli, opsRes, "", handleComponentProgress)
+	opsPhase, requeueAfter, err := reconcileActionWithComponentOps(reqCtx, cli, opsRes, "", handleComponentProgress)
+	// clean up the intermediate resources of the data clone if the OpsRequest failed or was cancelled.
+	if opsPhase == appsv1alpha1.OpsFailedPhase ||
+		(opsPhase == appsv1alpha1.OpsSucceedPhase && opsRes.OpsRequest.Status.Phase == appsv1alpha1.OpsCancellingPhase) {
+		if cleanupErr := hs.cleanupTmpResources(reqCtx, cli, opsRes); cleanupErr != nil {
+			return appsv1alpha1.OpsRunningPhase, 0, cleanupErr
+		}
+	}
+	return opsPhase, requeueAfter, err
+}
+
+// cleanupTmpResources deletes the volume snapshots and the unbound PVCs left by the data clone of the OpsRequest,
+// and records the cleaned resources to the OpsRequest.status.cleanedResources.
+func (hs horizontalScalingOpsHandler) cleanupTmpResources(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	opsRequest := opsRes.OpsRequest
+	cleaned, err := components.CleanupHScaleTmpResources(reqCtx.Ctx, cli, opsRequest.Namespace, opsRequest.Name)
+	if err != nil || len(cleaned) == 0 {
+		return err
+	}
+	patch := client.MergeFrom(opsRequest.DeepCopy())
+	for _, v := range cleaned {
+		if !slices.Contains(opsRequest.Status.CleanedResources, v) {
+			opsRequest.Status.CleanedResources = append(opsRequest.Status.CleanedResources, v)
+		}
+	}
+	return cli.Status().Patch(reqCtx.Ctx, opsRequest, patch)
 }
 
 // SaveLastConfiguration records last configuration to the OpsRequest.status.lastConfiguration
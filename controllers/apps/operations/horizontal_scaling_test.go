@@ -25,14 +25,18 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/controllers/apps/components"
 	opsutil "github.com/apecloud/kubeblocks/controllers/apps/operations/util"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 	"github.com/apecloud/kubeblocks/internal/generics"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 	testk8s "github.com/apecloud/kubeblocks/internal/testutil/k8s"
@@ -192,6 +196,62 @@ var _ = Describe("HorizontalScaling OpsRequest", func() {
 			mockConsensusCompToRunning(opsRes)
 			checkCancelledSucceed(reqCtx, opsRes)
 		})
+
+		It("test cleaning up the intermediate resources after HScale opsRequest failed", func() {
+			reqCtx := intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+			opsRes, _ := commonHScaleConsensusCompTest(reqCtx, 5)
+			opsLabels := components.GetHScaleTmpResourceLabels(opsRes.OpsRequest.Name)
+
+			By("mock the data clone is interrupted with one PVC bound and one PVC not bound")
+			boundPVCName := fmt.Sprintf("%s-%s-%s-%d", testapps.DataVolumeName, clusterName, consensusComp, 3)
+			boundPVC := testapps.NewPersistentVolumeClaimFactory(testCtx.DefaultNamespace, boundPVCName, clusterName,
+				consensusComp, testapps.DataVolumeName).AddLabelsInMap(opsLabels).SetStorage("1Gi").CheckedCreate(&testCtx).GetObject()
+			Expect(testapps.ChangeObjStatus(&testCtx, boundPVC, func() {
+				boundPVC.Status.Phase = corev1.ClaimBound
+			})).Should(Succeed())
+			unboundPVCName := fmt.Sprintf("%s-%s-%s-%d", testapps.DataVolumeName, clusterName, consensusComp, 4)
+			testapps.NewPersistentVolumeClaimFactory(testCtx.DefaultNamespace, unboundPVCName, clusterName,
+				consensusComp, testapps.DataVolumeName).AddLabelsInMap(opsLabels).SetStorage("1Gi").CheckedCreate(&testCtx)
+			snapshotName := fmt.Sprintf("%s-%s-scaling-0", clusterName, consensusComp)
+			sourcePVCName := fmt.Sprintf("%s-%s-%s-%d", testapps.DataVolumeName, clusterName, consensusComp, 0)
+			testapps.CreateK8sResource(&testCtx, &snapshotv1.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       snapshotName,
+					Namespace:  testCtx.DefaultNamespace,
+					Labels:     opsLabels,
+					Finalizers: []string{dptypes.DataProtectionFinalizerName},
+				},
+				Spec: snapshotv1.VolumeSnapshotSpec{
+					Source: snapshotv1.VolumeSnapshotSource{PersistentVolumeClaimName: &sourcePVCName},
+				},
+			})
+
+			By("mock the component failed and the failure lasts over the timeout")
+			compStatus := opsRes.Cluster.Status.Components[consensusComp]
+			compStatus.Phase = appsv1alpha1.FailedClusterCompPhase
+			opsRes.Cluster.Status.SetComponentStatus(consensusComp, compStatus)
+			_, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			opsCompStatus := opsRes.OpsRequest.Status.Components[consensusComp]
+			opsCompStatus.LastFailedTime = metav1.Time{Time: opsCompStatus.LastFailedTime.Add(-1 * componentFailedTimeout).Add(-1 * time.Second)}
+			opsRes.OpsRequest.Status.Components[consensusComp] = opsCompStatus
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(opsRes.OpsRequest.Status.Phase).Should(Equal(appsv1alpha1.OpsFailedPhase))
+
+			By("expect for the snapshot and the unbound PVC are cleaned and recorded in the opsRequest status")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest), func(g Gomega, ops *appsv1alpha1.OpsRequest) {
+				g.Expect(ops.Status.CleanedResources).Should(ConsistOf(
+					fmt.Sprintf("%s/%s", constant.VolumeSnapshotKind, snapshotName),
+					fmt.Sprintf("%s/%s", constant.PersistentVolumeClaimKind, unboundPVCName)))
+			})).Should(Succeed())
+			Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: snapshotName},
+				&snapshotv1.VolumeSnapshot{}, false)).Should(Succeed())
+			Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: unboundPVCName},
+				&corev1.PersistentVolumeClaim{}, false)).Should(Succeed())
+			Consistently(testapps.CheckObjExists(&testCtx, client.ObjectKeyFromObject(boundPVC),
+				&corev1.PersistentVolumeClaim{}, true)).Should(Succeed())
+		})
 	})
 })
 
@@ -0,0 +1,110 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+type monitorOpsHandler struct{}
+
+var _ OpsHandler = monitorOpsHandler{}
+
+func init() {
+	monitorBehaviour := OpsBehaviour{
+		// if cluster is Abnormal or Failed, new opsRequest may repair it.
+		// TODO: we should add "force" flag for these opsRequest.
+		FromClusterPhases:                  appsv1alpha1.GetClusterUpRunningPhases(),
+		ToClusterPhase:                     appsv1alpha1.UpdatingClusterPhase,
+		OpsHandler:                         monitorOpsHandler{},
+		ProcessingReasonInClusterCondition: ProcessingReasonMonitoring,
+	}
+
+	opsMgr := GetOpsManager()
+	opsMgr.RegisterOps(appsv1alpha1.MonitorType, monitorBehaviour)
+}
+
+// ActionStartedCondition the started condition when handle the monitor request.
+func (m monitorOpsHandler) ActionStartedCondition(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*metav1.Condition, error) {
+	return appsv1alpha1.NewMonitoringCondition(opsRes.OpsRequest), nil
+}
+
+// Action flips the monitor flag of the cluster components, the pods are rolled out
+// following the update strategy of the components.
+func (m monitorOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	if isMonitorInDesiredState(opsRes.OpsRequest) {
+		return nil
+	}
+	monitorMap := opsRes.OpsRequest.Spec.ToMonitorListToMap()
+	for index, component := range opsRes.Cluster.Spec.ComponentSpecs {
+		monitor, ok := monitorMap[component.Name]
+		if !ok {
+			continue
+		}
+		opsRes.Cluster.Spec.ComponentSpecs[index].Monitor = monitor.Enabled
+	}
+	return cli.Update(reqCtx.Ctx, opsRes.Cluster)
+}
+
+// ReconcileAction will be performed when action is done and loops till OpsRequest.status.phase is Succeed/Failed.
+// the Reconcile function for monitor opsRequest.
+func (m monitorOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (appsv1alpha1.OpsPhase, time.Duration, error) {
+	// nothing to roll out if the components are already in the desired state.
+	if isMonitorInDesiredState(opsRes.OpsRequest) {
+		return appsv1alpha1.OpsSucceedPhase, 0, nil
+	}
+	return reconcileActionWithComponentOps(reqCtx, cli, opsRes, "update monitor", handleComponentStatusProgress)
+}
+
+// SaveLastConfiguration records last configuration to the OpsRequest.status.lastConfiguration
+func (m monitorOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	componentNameSet := opsRes.OpsRequest.GetComponentNameSet()
+	lastComponentInfo := map[string]appsv1alpha1.LastComponentConfiguration{}
+	for _, v := range opsRes.Cluster.Spec.ComponentSpecs {
+		if _, ok := componentNameSet[v.Name]; !ok {
+			continue
+		}
+		monitor := v.Monitor
+		lastComponentInfo[v.Name] = appsv1alpha1.LastComponentConfiguration{
+			Monitor: &monitor,
+		}
+	}
+	opsRes.OpsRequest.Status.LastConfiguration.Components = lastComponentInfo
+	return nil
+}
+
+// isMonitorInDesiredState checks whether the monitor flags of the components are already the desired ones
+// before the opsRequest takes effect.
+func isMonitorInDesiredState(opsRequest *appsv1alpha1.OpsRequest) bool {
+	lastCompInfos := opsRequest.Status.LastConfiguration.Components
+	for _, v := range opsRequest.Spec.MonitorList {
+		lastConfig, ok := lastCompInfos[v.ComponentName]
+		if !ok || lastConfig.Monitor == nil || *lastConfig.Monitor != v.Enabled {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("Monitor OpsRequest", func() {
+
+	var (
+		randomStr             = testCtx.GetRandomStr()
+		clusterDefinitionName = "cluster-definition-for-ops-" + randomStr
+		clusterVersionName    = "clusterversion-for-ops-" + randomStr
+		clusterName           = "cluster-for-ops-" + randomStr
+	)
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		// delete cluster(and all dependent sub-resources), clusterversion and clusterdef
+		testapps.ClearClusterResources(&testCtx)
+
+		// delete rest resources
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	Context("Test OpsRequest", func() {
+		var (
+			opsRes *OpsResource
+			reqCtx intctrlutil.RequestCtx
+		)
+		BeforeEach(func() {
+			By("init operations resources ")
+			opsRes, _, _ = initOperationsResources(clusterDefinitionName, clusterVersionName, clusterName)
+			reqCtx = intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+		})
+
+		testMonitor := func(opsName string, enabled bool) {
+			By("create Monitor opsRequest")
+			opsRes.OpsRequest = createMonitorOpsObj(clusterName, opsName, enabled)
+			mockComponentIsOperating(opsRes.Cluster, appsv1alpha1.UpdatingClusterCompPhase, consensusComp)
+
+			By("mock Monitor OpsRequest is Creating")
+			_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(appsv1alpha1.OpsCreatingPhase))
+			Expect(*opsRes.OpsRequest.Status.LastConfiguration.Components[consensusComp].Monitor).Should(Equal(!enabled))
+
+			By("test monitor action and the monitor of the component is flipped")
+			mHandler := monitorOpsHandler{}
+			Expect(mHandler.Action(reqCtx, k8sClient, opsRes)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.Cluster),
+				func(g Gomega, fetched *appsv1alpha1.Cluster) {
+					g.Expect(fetched.Spec.GetComponentByName(consensusComp).Monitor).Should(Equal(enabled))
+				})).Should(Succeed())
+
+			By("test monitor reconcile function waits for the rollout")
+			phase, _, err := mHandler.ReconcileAction(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(phase).Should(Equal(appsv1alpha1.OpsRunningPhase))
+		}
+
+		It("Test enabling the monitor by OpsRequest", func() {
+			testMonitor("monitor-enable-ops-"+randomStr, true)
+		})
+
+		It("Test disabling the monitor by OpsRequest", func() {
+			Expect(testapps.ChangeObj(&testCtx, opsRes.Cluster, func(cluster *appsv1alpha1.Cluster) {
+				for i := range cluster.Spec.ComponentSpecs {
+					cluster.Spec.ComponentSpecs[i].Monitor = true
+				}
+			})).Should(Succeed())
+			testMonitor("monitor-disable-ops-"+randomStr, false)
+		})
+
+		It("Test the Monitor OpsRequest completes instantly if already in the desired state", func() {
+			By("create Monitor opsRequest with the current monitor of the component")
+			enabled := opsRes.Cluster.Spec.GetComponentByName(consensusComp).Monitor
+			opsRes.OpsRequest = createMonitorOpsObj(clusterName, "monitor-noop-ops-"+randomStr, enabled)
+			_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(appsv1alpha1.OpsCreatingPhase))
+
+			By("expect the cluster is not updated and the opsRequest succeeds")
+			generation := opsRes.Cluster.Generation
+			mHandler := monitorOpsHandler{}
+			Expect(mHandler.Action(reqCtx, k8sClient, opsRes)).Should(Succeed())
+			Expect(opsRes.Cluster.Generation).Should(Equal(generation))
+			phase, _, err := mHandler.ReconcileAction(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(phase).Should(Equal(appsv1alpha1.OpsSucceedPhase))
+		})
+	})
+})
+
+func createMonitorOpsObj(clusterName, monitorOpsName string, enabled bool) *appsv1alpha1.OpsRequest {
+	ops := testapps.NewOpsRequestObj(monitorOpsName, testCtx.DefaultNamespace,
+		clusterName, appsv1alpha1.MonitorType)
+	ops.Spec.MonitorList = []appsv1alpha1.Monitor{
+		{ComponentOps: appsv1alpha1.ComponentOps{ComponentName: consensusComp}, Enabled: enabled},
+	}
+	return testapps.CreateOpsRequest(ctx, testCtx, ops)
+}
@@ -65,7 +65,7 @@ func (opsMgr *OpsManager) Do(reqCtx intctrlutil.RequestCtx, cli client.Client, o
 	}
 	// validate entry condition for OpsRequest
 	if opsRequest.Status.Phase == appsv1alpha1.OpsPendingPhase {
-		if err = validateOpsWaitingPhase(opsRes.Cluster, opsRequest, opsBehaviour); err != nil {
+		if err = validateOpsWaitingPhase(opsRes.Cluster, opsRequest); err != nil {
 			// check if the error is caused by WaitForClusterPhaseErr  error
 			if _, ok := err.(*WaitForClusterPhaseErr); ok {
 				return intctrlutil.ResultToP(intctrlutil.RequeueAfter(time.Second, reqCtx.Log, ""))
@@ -435,12 +435,17 @@ func cancelComponentOps(ctx context.Context,
 
 // validateOpsWaitingPhase validates whether the current cluster phase is expected, and whether the waiting time exceeds the limit.
 // only requests with `Pending` phase will be validated.
-func validateOpsWaitingPhase(cluster *appsv1alpha1.Cluster, ops *appsv1alpha1.OpsRequest, opsBehaviour OpsBehaviour) error {
-	if len(opsBehaviour.FromClusterPhases) == 0 || ops.Status.Phase != appsv1alpha1.OpsPendingPhase {
+func validateOpsWaitingPhase(cluster *appsv1alpha1.Cluster, ops *appsv1alpha1.OpsRequest) error {
+	if ops.Status.Phase != appsv1alpha1.OpsPendingPhase {
 		return nil
 	}
+	// the phases registered by the OpsBehaviour can be overridden by the configuration, the same as the webhook.
+	fromClusterPhases, err := appsv1alpha1.GetOpsRequestAllowedClusterPhases(ops.Spec.Type)
+	if err != nil || len(fromClusterPhases) == 0 {
+		return err
+	}
 	// check if the opsRequest can be executed in the current cluster phase unless this opsRequest is reentrant.
-	if !slices.Contains(opsBehaviour.FromClusterPhases, cluster.Status.Phase) {
+	if !slices.Contains(fromClusterPhases, cluster.Status.Phase) {
 		// check if entry-condition is met
 		// if the cluster is not in the expected phase, we should wait for it for up to TTLSecondsBeforeAbort seconds.
 		// if len(opsRecorder) == 0 && !slices.Contains(opsBehaviour.FromClusterPhases, cluster.Status.Phase) {
@@ -452,7 +457,7 @@ func validateOpsWaitingPhase(cluster *appsv1alpha1.Cluster, ops *appsv1alpha1.Op
 		return &WaitForClusterPhaseErr{
 			clusterName:   cluster.Name,
 			currentPhase:  cluster.Status.Phase,
-			expectedPhase: opsBehaviour.FromClusterPhases,
+			expectedPhase: fromClusterPhases,
 		}
 	}
 	return nil
@@ -153,6 +153,10 @@ func (p *pipeline) doMergeImpl(parameters appsv1alpha1.ConfigurationItem) error
 	}
 
 	configSpec := p.configSpec
+	// the config template changed by the ClusterVersion is applied by the explicit reconfiguring operation
+	if item.ConfigSpec != nil && item.ConfigSpec.TemplateRef != configSpec.TemplateRef {
+		item.ConfigSpec = configSpec.DeepCopy()
+	}
 	if item.ConfigFileParams == nil {
 		item.ConfigFileParams = make(map[string]appsv1alpha1.ConfigParams)
 	}
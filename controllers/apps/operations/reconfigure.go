@@ -20,14 +20,20 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package operations
 
 import (
+	"fmt"
+	"reflect"
 	"time"
 
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/configuration/core"
+	"github.com/apecloud/kubeblocks/internal/configuration/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 )
 
@@ -219,6 +225,14 @@ func (r *reconfigureAction) syncReconfigureOperatorStatus(ctx intctrlutil.Reques
 		return appsv1alpha1.OpsRunningPhase, nil
 	}
 
+	latestVersion, err := util.ComputeHash(fetcher.ConfigMapObj.Data)
+	if err != nil {
+		return appsv1alpha1.OpsRunningPhase, err
+	}
+	if err := syncConfigVersionsProgress(ctx, cli, opsRes, ops.Reconfigure.ComponentName, latestVersion); err != nil {
+		return appsv1alpha1.OpsRunningPhase, err
+	}
+
 	switch intctrlutil.GetConfigSpecReconcilePhase(fetcher.ConfigMapObj, *item, fetcher.ConfigurationObj.Status.GetItemStatus(configSpec.Name)) {
 	default:
 		return appsv1alpha1.OpsRunningPhase, nil
@@ -229,6 +243,44 @@ func (r *reconfigureAction) syncReconfigureOperatorStatus(ctx intctrlutil.Reques
 	}
 }
 
+// syncConfigVersionsProgress syncs the progressDetails of the reconfiguring component with the config versions
+// the pods are running with, which are recorded in the component status.
+func syncConfigVersionsProgress(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, componentName, latestVersion string) error {
+	clusterCompStatus, ok := opsRes.Cluster.Status.Components[componentName]
+	if !ok || len(clusterCompStatus.ConfigVersions) == 0 {
+		return nil
+	}
+
+	opsRequest := opsRes.OpsRequest
+	patch := client.MergeFrom(opsRequest.DeepCopy())
+	oldOpsRequestStatus := opsRequest.Status.DeepCopy()
+	if opsRequest.Status.Components == nil {
+		opsRequest.Status.Components = map[string]appsv1alpha1.OpsRequestComponentStatus{}
+	}
+	compStatus := opsRequest.Status.Components[componentName]
+	podNames := maps.Keys(clusterCompStatus.ConfigVersions)
+	slices.Sort(podNames)
+	var syncedCount int
+	for _, podName := range podNames {
+		progressDetail := appsv1alpha1.ProgressStatusDetail{ObjectKey: getProgressObjectKey(constant.PodKind, podName)}
+		if core.IsConfigVersionSynced(clusterCompStatus.ConfigVersions[podName], latestVersion) {
+			syncedCount++
+			progressDetail.Status = appsv1alpha1.SucceedProgressStatus
+			progressDetail.Message = getProgressSucceedMessage("reconfigure", progressDetail.ObjectKey, componentName)
+		} else {
+			progressDetail.Status = appsv1alpha1.ProcessingProgressStatus
+			progressDetail.Message = getProgressProcessingMessage("reconfigure", progressDetail.ObjectKey, componentName)
+		}
+		setComponentStatusProgressDetail(opsRes.Recorder, opsRequest, &compStatus.ProgressDetails, progressDetail)
+	}
+	opsRequest.Status.Components[componentName] = compStatus
+	opsRequest.Status.Progress = fmt.Sprintf("%d/%d", syncedCount, len(podNames))
+	if reflect.DeepEqual(opsRequest.Status, *oldOpsRequestStatus) {
+		return nil
+	}
+	return cli.Status().Patch(reqCtx.Ctx, opsRequest, patch)
+}
+
 func isExpectedPhase(condition metav1.Condition, expectedTypes []string, expectedStatus metav1.ConditionStatus) bool {
 	for _, t := range expectedTypes {
 		if t == condition.Type && condition.Status == expectedStatus {
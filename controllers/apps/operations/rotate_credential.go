@@ -0,0 +1,612 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	componetutil "github.com/apecloud/kubeblocks/internal/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+const (
+	// the envs of the statement and the endpoint, which are consistent with the jobs provisioning the system accounts,
+	// so that the cmdExecutorConfig of the system accounts can be reused to change the password.
+	rotateCredentialStmtEnvName     = "KB_ACCOUNT_STATEMENT"
+	rotateCredentialEndpointEnvName = "KB_ACCOUNT_ENDPOINT"
+
+	rotateCredentialJobPrefix = "kb-rotate-credential"
+	// rotateCredentialUpdateAction changes the password of the engine to the new one.
+	rotateCredentialUpdateAction = "update"
+	// rotateCredentialRollbackAction changes the password of the engine back to the old one.
+	rotateCredentialRollbackAction = "rollback"
+
+	RotateCredentialSecretKey  = "Secret"
+	RotateCredentialRestartKey = "Restart"
+)
+
+type rotateCredentialOpsHandler struct{}
+
+var _ OpsHandler = rotateCredentialOpsHandler{}
+
+func init() {
+	rotateCredentialBehaviour := OpsBehaviour{
+		FromClusterPhases:                  []appsv1alpha1.ClusterPhase{appsv1alpha1.RunningClusterPhase},
+		ToClusterPhase:                     appsv1alpha1.UpdatingClusterPhase,
+		OpsHandler:                         rotateCredentialOpsHandler{},
+		ProcessingReasonInClusterCondition: ProcessingReasonRotatingCredential,
+	}
+
+	opsMgr := GetOpsManager()
+	opsMgr.RegisterOps(appsv1alpha1.RotateCredentialType, rotateCredentialBehaviour)
+}
+
+// ActionStartedCondition the started condition when handle the rotate credential request.
+func (r rotateCredentialOpsHandler) ActionStartedCondition(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*metav1.Condition, error) {
+	return appsv1alpha1.NewRotatingCredentialCondition(opsRes.OpsRequest), nil
+}
+
+// Action generates the new password into a staging secret owned by the opsRequest, and creates the job
+// changing the password on the primary pod of the component. The connection secret is not touched until
+// the job succeeds, so the clients keep working with the old password in the meantime.
+func (r rotateCredentialOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	var (
+		cluster = opsRes.Cluster
+		ops     = opsRes.OpsRequest
+	)
+	compSpec := cluster.Spec.GetComponentByName(ops.Spec.RotateCredential.ComponentName)
+	if compSpec == nil {
+		// we have checked component exists in validation, so this should not happen
+		return &FastFaileError{message: fmt.Sprintf("component %s not found in cluster %s", ops.Spec.RotateCredential.ComponentName, cluster.Name)}
+	}
+	compDef, err := appsv1alpha1.GetComponentDefByCluster(reqCtx.Ctx, cli, *cluster, compSpec.ComponentDefRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &FastFaileError{message: err.Error()}
+		}
+		return err
+	}
+	sysAccounts := compDef.SystemAccounts
+	if sysAccounts == nil || sysAccounts.CmdExecutorConfig == nil {
+		return &FastFaileError{message: fmt.Sprintf("componentDef %s does not define the cmdExecutorConfig to change the password", compDef.Name)}
+	}
+	connSecret := &corev1.Secret{}
+	if err = cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: cluster.Namespace,
+		Name: componetutil.GenerateConnCredential(cluster.Name)}, connSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &FastFaileError{message: err.Error()}
+		}
+		return err
+	}
+	if _, err = ops.Spec.RotateCredential.GetGracePeriod(); err != nil {
+		return &FastFaileError{message: err.Error()}
+	}
+	username := string(connSecret.Data[constant.AccountNameForSecret])
+	if len(getRotateCredentialStatement(ops.Spec.RotateCredential, sysAccounts, username)) == 0 {
+		return &FastFaileError{message: fmt.Sprintf("no statement to change the password of account %s in componentDef %s", username, compDef.Name)}
+	}
+	stagingSecret, err := getOrCreateRotateCredentialSecret(reqCtx, cli, opsRes, connSecret, sysAccounts.PasswordConfig)
+	if err != nil {
+		return err
+	}
+	job, err := buildRotateCredentialJob(reqCtx, cli, opsRes, compSpec, compDef, rotateCredentialUpdateAction,
+		connSecret.Name, stagingSecret.Data[constant.AccountPasswdForSecret])
+	if err != nil {
+		return err
+	}
+	if err = cli.Create(reqCtx.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// ReconcileAction will be performed when action is done and loops till OpsRequest.status.phase is Succeed/Failed.
+// the Reconcile function for rotate credential opsRequest. It proceeds step by step:
+// 1. waits for the job changing the password on the engine, and rolls the password back if the job fails.
+// 2. switches the connection secret to the new password, and keeps the old one for the grace period if specified.
+// 3. restarts the components referring to the connection secret, including the component itself and the proxies.
+func (r rotateCredentialOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (appsv1alpha1.OpsPhase, time.Duration, error) {
+	var (
+		ops                 = opsRes.OpsRequest
+		cluster             = opsRes.Cluster
+		compName            = ops.Spec.RotateCredential.ComponentName
+		oldOpsRequestStatus = ops.Status.DeepCopy()
+	)
+	patch := client.MergeFrom(ops.DeepCopy())
+	if ops.Status.Components == nil {
+		ops.Status.Components = map[string]appsv1alpha1.OpsRequestComponentStatus{}
+	}
+	phase, requeueAfter, err := handleRotateCredentialProgress(reqCtx, cli, opsRes, compName)
+	if phase == appsv1alpha1.OpsSucceedPhase {
+		for _, action := range []string{rotateCredentialUpdateAction, rotateCredentialRollbackAction} {
+			if cleanErr := cleanJobByName(reqCtx.Ctx, cli, cluster, genRotateCredentialJobName(ops.Name, action)); cleanErr != nil && !apierrors.IsNotFound(cleanErr) {
+				return "", 0, cleanErr
+			}
+		}
+	}
+	if !reflect.DeepEqual(*oldOpsRequestStatus, ops.Status) {
+		if patchErr := cli.Status().Patch(reqCtx.Ctx, ops, patch); patchErr != nil {
+			return "", 0, patchErr
+		}
+	}
+	return phase, requeueAfter, err
+}
+
+// SaveLastConfiguration this operation does not change Cluster.spec, empty implementation here.
+func (r rotateCredentialOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	return nil
+}
+
+// handleRotateCredentialProgress updates the progressDetails of each step, and returns the phase of the opsRequest.
+func handleRotateCredentialProgress(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, compName string) (appsv1alpha1.OpsPhase, time.Duration, error) {
+	var (
+		ops     = opsRes.OpsRequest
+		cluster = opsRes.Cluster
+	)
+	connSecret := &corev1.Secret{}
+	if err := cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: cluster.Namespace,
+		Name: componetutil.GenerateConnCredential(cluster.Name)}, connSecret); err != nil {
+		return "", 0, err
+	}
+	stagingSecret := &corev1.Secret{}
+	if err := cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: cluster.Namespace,
+		Name: genRotateCredentialSecretName(ops.Name)}, stagingSecret); err != nil {
+		return "", 0, err
+	}
+	newPasswd := stagingSecret.Data[constant.AccountPasswdForSecret]
+	secretDetail := appsv1alpha1.ProgressStatusDetail{
+		ObjectKey: getProgressObjectKey(RotateCredentialSecretKey, connSecret.Name),
+		Status:    appsv1alpha1.PendingProgressStatus,
+		Message:   fmt.Sprintf("waiting for the password to be changed on component %s", compName),
+	}
+
+	// step 1: change the password on the engine.
+	updateJobName := genRotateCredentialJobName(ops.Name, rotateCredentialUpdateAction)
+	jobDetail := appsv1alpha1.ProgressStatusDetail{
+		ObjectKey: getProgressObjectKey(constant.JobKind, updateJobName),
+	}
+	// the secret is switched only after the job succeeded, so a switched secret means the job is done,
+	// even if the job has been cleaned up.
+	if string(connSecret.Data[constant.AccountPasswdForSecret]) != string(newPasswd) {
+		finished, succeed, err := getRotateCredentialJobResult(reqCtx, cli, cluster, updateJobName)
+		if err != nil {
+			return "", 0, err
+		}
+		if !finished {
+			jobDetail.SetStatusAndMessage(appsv1alpha1.ProcessingProgressStatus,
+				fmt.Sprintf("changing the password on the primary pod of component %s by job %s", compName, updateJobName))
+			setRotateCredentialProgressDetails(reqCtx, opsRes, compName, appsv1alpha1.UpdatingClusterCompPhase, jobDetail, secretDetail)
+			return appsv1alpha1.OpsRunningPhase, 5 * time.Second, nil
+		}
+		if !succeed {
+			jobDetail.SetStatusAndMessage(appsv1alpha1.FailedProgressStatus,
+				fmt.Sprintf("failed to change the password on component %s by job %s", compName, updateJobName))
+			return rollbackRotateCredential(reqCtx, cli, opsRes, compName, connSecret, jobDetail, secretDetail)
+		}
+		jobDetail.SetStatusAndMessage(appsv1alpha1.SucceedProgressStatus,
+			fmt.Sprintf("the password has been changed on component %s", compName))
+
+		// step 2: switch the connection secret to the new password.
+		connSecretPatch := client.MergeFrom(connSecret.DeepCopy())
+		// the grace period has been validated in the action.
+		gracePeriod, _ := ops.Spec.RotateCredential.GetGracePeriod()
+		switchConnCredential(connSecret, newPasswd, gracePeriod, time.Now())
+		if err = cli.Patch(reqCtx.Ctx, connSecret, connSecretPatch); err != nil {
+			return "", 0, err
+		}
+	} else {
+		jobDetail.SetStatusAndMessage(appsv1alpha1.SucceedProgressStatus,
+			fmt.Sprintf("the password has been changed on component %s", compName))
+	}
+	secretDetail.SetStatusAndMessage(appsv1alpha1.SucceedProgressStatus,
+		fmt.Sprintf("the connection secret %s has been updated with the new password", connSecret.Name))
+	setRotateCredentialProgressDetails(reqCtx, opsRes, compName, appsv1alpha1.UpdatingClusterCompPhase, jobDetail, secretDetail)
+
+	// step 3: restart the consumers of the connection secret.
+	// the restart time is aligned with the rotation time, so the workloads are not restarted again for the rotation
+	// by the ComponentCredentialRotationTransformer.
+	restartTime, ok := connSecret.Annotations[constant.CredentialRotatedAtAnnotationKey]
+	if !ok {
+		restartTime = ops.Status.StartTimestamp.Format(time.RFC3339)
+	}
+	completedCount, expectCount, err := restartRotateCredentialConsumers(reqCtx, cli, opsRes, compName, connSecret.Name, restartTime)
+	if err != nil {
+		return "", 0, err
+	}
+	// the engine and the secret steps are counted in the progress as well.
+	ops.Status.Progress = fmt.Sprintf("%d/%d", completedCount+2, expectCount+2)
+	if completedCount != expectCount {
+		return appsv1alpha1.OpsRunningPhase, 5 * time.Second, nil
+	}
+	setRotateCredentialProgressDetails(reqCtx, opsRes, compName, appsv1alpha1.RunningClusterCompPhase)
+	return appsv1alpha1.OpsSucceedPhase, 0, nil
+}
+
+// rollbackRotateCredential changes the password on the engine back to the old one, which is still kept in
+// the connection secret. The job authenticates with the old credential, as the failed job has not applied
+// the new password in the common case. The opsRequest fails after the rollback job is finished.
+func rollbackRotateCredential(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	compName string,
+	connSecret *corev1.Secret,
+	jobDetail, secretDetail appsv1alpha1.ProgressStatusDetail) (appsv1alpha1.OpsPhase, time.Duration, error) {
+	var (
+		ops     = opsRes.OpsRequest
+		cluster = opsRes.Cluster
+	)
+	rollbackJobName := genRotateCredentialJobName(ops.Name, rotateCredentialRollbackAction)
+	rollbackDetail := appsv1alpha1.ProgressStatusDetail{
+		ObjectKey: getProgressObjectKey(constant.JobKind, rollbackJobName),
+	}
+	secretDetail.Message = fmt.Sprintf("the connection secret %s is kept with the old password", connSecret.Name)
+	finished, succeed, err := getRotateCredentialJobResult(reqCtx, cli, cluster, rollbackJobName)
+	if apierrors.IsNotFound(err) {
+		compSpec := cluster.Spec.GetComponentByName(compName)
+		compDef, err := appsv1alpha1.GetComponentDefByCluster(reqCtx.Ctx, cli, *cluster, compSpec.ComponentDefRef)
+		if err != nil {
+			return "", 0, err
+		}
+		job, err := buildRotateCredentialJob(reqCtx, cli, opsRes, compSpec, compDef, rotateCredentialRollbackAction,
+			connSecret.Name, connSecret.Data[constant.AccountPasswdForSecret])
+		if err != nil {
+			return "", 0, err
+		}
+		if err = cli.Create(reqCtx.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return "", 0, err
+		}
+	} else if err != nil {
+		return "", 0, err
+	}
+	if !finished {
+		rollbackDetail.SetStatusAndMessage(appsv1alpha1.ProcessingProgressStatus,
+			fmt.Sprintf("rolling back the password on component %s by job %s", compName, rollbackJobName))
+		setRotateCredentialProgressDetails(reqCtx, opsRes, compName, appsv1alpha1.UpdatingClusterCompPhase, jobDetail, secretDetail, rollbackDetail)
+		return appsv1alpha1.OpsRunningPhase, 5 * time.Second, nil
+	}
+	if succeed {
+		rollbackDetail.SetStatusAndMessage(appsv1alpha1.SucceedProgressStatus,
+			fmt.Sprintf("the password has been rolled back on component %s", compName))
+	} else {
+		// the engine may have not applied the new password at all, which is the common case.
+		rollbackDetail.SetStatusAndMessage(appsv1alpha1.FailedProgressStatus,
+			fmt.Sprintf("failed to roll back the password on component %s by job %s", compName, rollbackJobName))
+	}
+	secretDetail.Status = appsv1alpha1.FailedProgressStatus
+	setRotateCredentialProgressDetails(reqCtx, opsRes, compName, appsv1alpha1.RunningClusterCompPhase, jobDetail, secretDetail, rollbackDetail)
+	return appsv1alpha1.OpsFailedPhase, 0, fmt.Errorf("failed to change the password on component %s, "+
+		"the connection secret %s is kept with the old password, please check the job %s", compName, connSecret.Name,
+		genRotateCredentialJobName(ops.Name, rotateCredentialUpdateAction))
+}
+
+// restartRotateCredentialConsumers restarts the workloads referring to the connection secret, including the workload
+// of the component itself, as the envs and the mounted files of the secret are not refreshed in place,
+// and returns the restarted count.
+func restartRotateCredentialConsumers(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	compName, secretName, restartTime string) (int, int, error) {
+	var (
+		cluster        = opsRes.Cluster
+		completedCount int
+		expectCount    int
+	)
+	rsmList := &workloads.ReplicatedStateMachineList{}
+	if err := cli.List(reqCtx.Ctx, rsmList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{constant.AppInstanceLabelKey: cluster.Name}); err != nil {
+		return 0, 0, err
+	}
+	for i := range rsmList.Items {
+		rsm := &rsmList.Items[i]
+		consumer := rsm.Labels[constant.KBAppComponentLabelKey]
+		if !intctrlutil.PodSpecRefersToSecret(&rsm.Spec.Template.Spec, secretName) {
+			continue
+		}
+		expectCount++
+		restartDetail := appsv1alpha1.ProgressStatusDetail{
+			Group:     consumer,
+			ObjectKey: getProgressObjectKey(RotateCredentialRestartKey, rsm.Name),
+			Status:    appsv1alpha1.ProcessingProgressStatus,
+			Message:   fmt.Sprintf("restarting component %s to load the new password", consumer),
+		}
+		if rsm.Spec.Template.Annotations == nil {
+			rsm.Spec.Template.Annotations = map[string]string{}
+		}
+		if rsm.Spec.Template.Annotations[constant.RestartAnnotationKey] != restartTime {
+			rsm.Spec.Template.Annotations[constant.RestartAnnotationKey] = restartTime
+			if err := cli.Update(reqCtx.Ctx, rsm); err != nil {
+				return 0, 0, err
+			}
+		} else if isRSMRolledOut(rsm) {
+			completedCount++
+			restartDetail.SetStatusAndMessage(appsv1alpha1.SucceedProgressStatus,
+				fmt.Sprintf("component %s has been restarted", consumer))
+		}
+		setRotateCredentialProgressDetails(reqCtx, opsRes, compName, appsv1alpha1.UpdatingClusterCompPhase, restartDetail)
+	}
+	return completedCount, expectCount, nil
+}
+
+// switchConnCredential switches the connection secret to the new password and records the rotation time, the old
+// password is kept as the previous password until the grace period expires, which is dropped by the
+// ComponentCredentialRotationTransformer.
+func switchConnCredential(connSecret *corev1.Secret, newPasswd []byte, gracePeriod time.Duration, now time.Time) {
+	if connSecret.Annotations == nil {
+		connSecret.Annotations = map[string]string{}
+	}
+	if gracePeriod > 0 {
+		connSecret.Data[constant.AccountPreviousPasswdForSecret] = connSecret.Data[constant.AccountPasswdForSecret]
+		connSecret.Annotations[constant.PreviousCredentialExpireAtAnnotationKey] = now.Add(gracePeriod).Format(time.RFC3339)
+	} else {
+		delete(connSecret.Data, constant.AccountPreviousPasswdForSecret)
+		delete(connSecret.Annotations, constant.PreviousCredentialExpireAtAnnotationKey)
+	}
+	connSecret.Data[constant.AccountPasswdForSecret] = newPasswd
+	connSecret.Annotations[constant.CredentialRotatedAtAnnotationKey] = now.Format(time.RFC3339)
+}
+
+// setRotateCredentialProgressDetails sets the progress details of the component.
+func setRotateCredentialProgressDetails(reqCtx intctrlutil.RequestCtx,
+	opsRes *OpsResource,
+	compName string,
+	phase appsv1alpha1.ClusterComponentPhase,
+	progressDetails ...appsv1alpha1.ProgressStatusDetail) {
+	ops := opsRes.OpsRequest
+	compStatus := ops.Status.Components[compName]
+	for _, progressDetail := range progressDetails {
+		setComponentStatusProgressDetail(reqCtx.Recorder, ops, &compStatus.ProgressDetails, progressDetail)
+	}
+	compStatus.Phase = phase
+	ops.Status.Components[compName] = compStatus
+}
+
+// getRotateCredentialJobResult returns whether the job is finished and succeed.
+func getRotateCredentialJobResult(reqCtx intctrlutil.RequestCtx, cli client.Client, cluster *appsv1alpha1.Cluster, jobName string) (bool, bool, error) {
+	job := &batchv1.Job{}
+	if err := cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: jobName}, job); err != nil {
+		return false, false, err
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return true, true, nil
+		case batchv1.JobFailed:
+			return true, false, nil
+		}
+	}
+	return false, false, nil
+}
+
+// getOrCreateRotateCredentialSecret gets the staging secret of the new password, or generates the password
+// and creates the secret if not exists, so that the retries of the action use the same password.
+func getOrCreateRotateCredentialSecret(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	connSecret *corev1.Secret,
+	passwdConfig appsv1alpha1.PasswordConfig) (*corev1.Secret, error) {
+	var (
+		ops     = opsRes.OpsRequest
+		cluster = opsRes.Cluster
+	)
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: cluster.Namespace, Name: genRotateCredentialSecretName(ops.Name)}
+	if err := cli.Get(reqCtx.Ctx, secretKey, secret); err == nil {
+		return secret, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	passwd, err := intctrlutil.GeneratePassword(passwdConfig)
+	if err != nil {
+		return nil, &FastFaileError{message: err.Error()}
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretKey.Name,
+			Namespace: secretKey.Namespace,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    cluster.Name,
+				constant.AppManagedByLabelKey:   constant.AppName,
+				constant.OpsRequestNameLabelKey: ops.Name,
+			},
+		},
+		Data: map[string][]byte{
+			constant.AccountNameForSecret:   connSecret.Data[constant.AccountNameForSecret],
+			constant.AccountPasswdForSecret: []byte(passwd),
+		},
+	}
+	scheme, _ := appsv1alpha1.SchemeBuilder.Build()
+	if err = controllerutil.SetOwnerReference(ops, secret, scheme); err != nil {
+		return nil, err
+	}
+	if err = cli.Create(reqCtx.Ctx, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// buildRotateCredentialJob builds the job executing the statement on the primary pod of the component,
+// with the cmdExecutorConfig of the system accounts authenticating by the username and password of authSecretName.
+func buildRotateCredentialJob(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	compSpec *appsv1alpha1.ClusterComponentSpec,
+	compDef *appsv1alpha1.ClusterComponentDefinition,
+	action string,
+	authSecretName string,
+	passwd []byte) (*batchv1.Job, error) {
+	var (
+		ops     = opsRes.OpsRequest
+		cluster = opsRes.Cluster
+	)
+	sysAccounts := compDef.SystemAccounts
+	connSecret := &corev1.Secret{}
+	if err := cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: cluster.Namespace,
+		Name: componetutil.GenerateConnCredential(cluster.Name)}, connSecret); err != nil {
+		return nil, err
+	}
+	endpoint, err := getRotateCredentialEndpoint(reqCtx, cli, cluster, compSpec, compDef)
+	if err != nil {
+		return nil, err
+	}
+	username := string(connSecret.Data[constant.AccountNameForSecret])
+	namedVars := map[string]string{
+		"$(USERNAME)": username,
+		"$(PASSWD)":   string(passwd),
+	}
+	stmt := componetutil.ReplaceNamedVars(namedVars, getRotateCredentialStatement(ops.Spec.RotateCredential, sysAccounts, username), -1, true)
+	envs := []corev1.EnvVar{
+		{Name: rotateCredentialStmtEnvName, Value: stmt},
+		{Name: rotateCredentialEndpointEnvName, Value: endpoint},
+	}
+	for _, env := range sysAccounts.CmdExecutorConfig.Env {
+		env = *env.DeepCopy()
+		// authenticate with the specified secret instead of the connection secret.
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil &&
+			(env.ValueFrom.SecretKeyRef.Name == constant.KBConnCredentialPlaceHolder || env.ValueFrom.SecretKeyRef.Name == connSecret.Name) {
+			env.ValueFrom.SecretKeyRef.Name = authSecretName
+		}
+		envs = append(envs, env)
+	}
+
+	jobName := genRotateCredentialJobName(ops.Name, action)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    cluster.Name,
+				constant.KBAppComponentLabelKey: compSpec.Name,
+				constant.AppManagedByLabelKey:   constant.AppName,
+				constant.OpsRequestNameLabelKey: ops.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			// set backoff limit to 0, so that the failed statement will not be retried, and rolled back instead.
+			BackoffLimit: pointer.Int32(0),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            rotateCredentialJobPrefix,
+							Image:           sysAccounts.CmdExecutorConfig.Image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         sysAccounts.CmdExecutorConfig.Command,
+							Args:            sysAccounts.CmdExecutorConfig.Args,
+							Env:             envs,
+						},
+					},
+				},
+			},
+		},
+	}
+	tolerations, err := componetutil.BuildTolerations(cluster, compSpec)
+	if err != nil {
+		return nil, err
+	}
+	job.Spec.Template.Spec.Tolerations = tolerations
+	scheme, _ := appsv1alpha1.SchemeBuilder.Build()
+	if err = controllerutil.SetOwnerReference(ops, job, scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// getRotateCredentialEndpoint returns the IP of the primary or leader pod, or the service of the component
+// if it has no primary.
+func getRotateCredentialEndpoint(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	compSpec *appsv1alpha1.ClusterComponentSpec,
+	compDef *appsv1alpha1.ClusterComponentDefinition) (string, error) {
+	if !slices.Contains(getSupportSwitchoverWorkload(), compDef.WorkloadType) {
+		return getTargetService(reqCtx, cli, client.ObjectKeyFromObject(cluster), compSpec.Name)
+	}
+	pod, err := getPrimaryOrLeaderPod(reqCtx.Ctx, cli, *cluster, compSpec.Name, compSpec.ComponentDefRef)
+	if err != nil {
+		return "", err
+	}
+	return pod.Status.PodIP, nil
+}
+
+// getRotateCredentialStatement returns the statement template in the opsRequest, or the update statement
+// of the system account named after the username. The connection credential is the superuser of the engine,
+// such as root, which is not a system account, so the update statement of the admin account is used instead.
+func getRotateCredentialStatement(rotateCredential *appsv1alpha1.RotateCredential,
+	sysAccounts *appsv1alpha1.SystemAccountSpec,
+	username string) string {
+	if len(rotateCredential.Statement) > 0 {
+		return rotateCredential.Statement
+	}
+	getUpdateStatement := func(accountName appsv1alpha1.AccountName) string {
+		for _, account := range sysAccounts.Accounts {
+			stmts := account.ProvisionPolicy.Statements
+			if account.Name == accountName && stmts != nil {
+				return stmts.UpdateStatement
+			}
+		}
+		return ""
+	}
+	if stmt := getUpdateStatement(appsv1alpha1.AccountName(username)); len(stmt) > 0 {
+		return stmt
+	}
+	return getUpdateStatement(appsv1alpha1.AdminAccount)
+}
+
+// isRSMRolledOut checks whether all pods of the rsm are updated and ready.
+func isRSMRolledOut(rsm *workloads.ReplicatedStateMachine) bool {
+	replicas := int32(1)
+	if rsm.Spec.Replicas != nil {
+		replicas = *rsm.Spec.Replicas
+	}
+	return rsm.Status.ObservedGeneration >= rsm.Generation &&
+		rsm.Status.UpdatedReplicas == replicas && rsm.Status.ReadyReplicas == replicas
+}
+
+func genRotateCredentialSecretName(opsName string) string {
+	return fmt.Sprintf("%s-credential", opsName)
+}
+
+func genRotateCredentialJobName(opsName, action string) string {
+	jobName := fmt.Sprintf("%s-%s-%s", rotateCredentialJobPrefix, opsName, action)
+	if len(jobName) > 63 {
+		// keep the action suffix distinguishable.
+		jobName = jobName[:63-len(action)-1] + "-" + action
+	}
+	return jobName
+}
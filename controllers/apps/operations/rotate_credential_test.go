@@ -0,0 +1,346 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	componetutil "github.com/apecloud/kubeblocks/internal/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("RotateCredential OpsRequest", func() {
+	const oldPasswd = "old-password"
+
+	var (
+		randomStr          = testCtx.GetRandomStr()
+		clusterVersionName = "cluster-version-for-ops-" + randomStr
+		clusterName        = "cluster-for-ops-" + randomStr
+		reqCtx             intctrlutil.RequestCtx
+		opsRes             *OpsResource
+		connSecretKey      types.NamespacedName
+		proxyRSM           *workloads.ReplicatedStateMachine
+		mysqlRSM           *workloads.ReplicatedStateMachine
+	)
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		// delete cluster(and all dependent sub-resources), clusterversion and clusterdef
+		testapps.ClearClusterResources(&testCtx)
+
+		// delete rest resources
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.RSMSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, client.HasLabels{constant.OpsRequestNameLabelKey})
+		testapps.ClearResources(&testCtx, generics.JobSignature, inNS, client.HasLabels{constant.OpsRequestNameLabelKey})
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	patchJobStatus := func(jobName string, jobStatus batchv1.JobConditionType) {
+		Eventually(testapps.GetAndChangeObjStatus(&testCtx, types.NamespacedName{Name: jobName, Namespace: testCtx.DefaultNamespace},
+			func(job *batchv1.Job) {
+				job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: jobStatus, Status: corev1.ConditionTrue})
+			})).Should(Succeed())
+	}
+
+	checkConnPassword := func(expected string) {
+		Eventually(testapps.CheckObj(&testCtx, connSecretKey, func(g Gomega, secret *corev1.Secret) {
+			g.Expect(string(secret.Data[constant.AccountPasswdForSecret])).Should(Equal(expected))
+		})).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		reqCtx = intctrlutil.RequestCtx{
+			Ctx:      testCtx.Ctx,
+			Recorder: k8sManager.GetEventRecorderFor("opsrequest-controller"),
+		}
+
+		By("Create a clusterDefinition obj with systemAccounts.")
+		sysAccounts := &appsv1alpha1.SystemAccountSpec{
+			CmdExecutorConfig: &appsv1alpha1.CmdExecutorConfig{
+				CommandExecutorEnvItem: appsv1alpha1.CommandExecutorEnvItem{
+					Image: testapps.ApeCloudMySQLImage,
+					Env: []corev1.EnvVar{{
+						Name: "MYSQL_PWD",
+						ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: constant.KBConnCredentialPlaceHolder},
+							Key:                  constant.AccountPasswdForSecret,
+						}},
+					}},
+				},
+				CommandExecutorItem: appsv1alpha1.CommandExecutorItem{
+					Command: []string{"mysql", "-h$(KB_ACCOUNT_ENDPOINT)", "-e", "$(KB_ACCOUNT_STATEMENT)"},
+				},
+			},
+			PasswordConfig: appsv1alpha1.PasswordConfig{Length: 16, NumDigits: 4},
+			Accounts: []appsv1alpha1.SystemAccountConfig{{
+				Name: appsv1alpha1.ProbeAccount,
+				ProvisionPolicy: appsv1alpha1.ProvisionPolicy{
+					Type:  appsv1alpha1.CreateByStmt,
+					Scope: appsv1alpha1.AnyPods,
+					Statements: &appsv1alpha1.ProvisionStatements{
+						CreationStatement: `CREATE USER IF NOT EXISTS $(USERNAME) IDENTIFIED BY '$(PASSWD)';`,
+						UpdateStatement:   `SET PASSWORD FOR $(USERNAME) = '$(PASSWD)';`,
+					},
+				},
+			}, {
+				Name: appsv1alpha1.AdminAccount,
+				ProvisionPolicy: appsv1alpha1.ProvisionPolicy{
+					Type:  appsv1alpha1.CreateByStmt,
+					Scope: appsv1alpha1.AnyPods,
+					Statements: &appsv1alpha1.ProvisionStatements{
+						CreationStatement: `CREATE USER IF NOT EXISTS $(USERNAME) IDENTIFIED BY '$(PASSWD)';`,
+						UpdateStatement:   `ALTER USER $(USERNAME) IDENTIFIED BY '$(PASSWD)';`,
+					},
+				},
+			}},
+		}
+		clusterDefObj := testapps.NewClusterDefFactory(consensusComp).
+			AddComponentDef(testapps.StatelessNginxComponent, statelessComp).
+			AddComponentDef(testapps.ConsensusMySQLComponent, consensusComp).
+			AddSystemAccountSpec(sysAccounts).
+			Create(&testCtx).GetObject()
+		clusterVersionObj := testapps.NewClusterVersionFactory(clusterVersionName, clusterDefObj.GetName()).
+			AddComponentVersion(statelessComp).AddContainerShort(testapps.DefaultNginxContainerName, testapps.NginxImage).
+			AddComponentVersion(consensusComp).AddContainerShort(testapps.DefaultMySQLContainerName, testapps.ApeCloudMySQLImage).
+			Create(&testCtx).GetObject()
+
+		By("Create a cluster with the connection secret and the leader pod.")
+		clusterObj := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefObj.Name, clusterVersionObj.Name).WithRandomName().
+			AddComponent(statelessComp, statelessComp).
+			AddComponent(consensusComp, consensusComp).
+			SetReplicas(1).
+			Create(&testCtx).GetObject()
+		connSecretKey = types.NamespacedName{Namespace: testCtx.DefaultNamespace, Name: componetutil.GenerateConnCredential(clusterObj.Name)}
+		testapps.CreateK8sResource(&testCtx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      connSecretKey.Name,
+				Namespace: connSecretKey.Namespace,
+				Labels:    map[string]string{constant.AppInstanceLabelKey: clusterObj.Name},
+			},
+			Data: map[string][]byte{
+				constant.AccountNameForSecret:   []byte("root"),
+				constant.AccountPasswdForSecret: []byte(oldPasswd),
+			},
+		})
+		testapps.NewPodFactory(testCtx.DefaultNamespace, fmt.Sprintf("%s-%s-0", clusterObj.Name, consensusComp)).
+			AddContainer(corev1.Container{Name: testapps.DefaultMySQLContainerName, Image: testapps.ApeCloudMySQLImage}).
+			AddAppInstanceLabel(clusterObj.Name).
+			AddAppComponentLabel(consensusComp).
+			AddAppManagedByLabel().
+			AddRoleLabel(constant.Leader).
+			Create(&testCtx)
+
+		By("Create the rsms of the proxy component and the component itself referring to the connection secret.")
+		passwordEnv := func(name string) corev1.EnvVar {
+			return corev1.EnvVar{
+				Name: name,
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: connSecretKey.Name},
+					Key:                  constant.AccountPasswdForSecret,
+				}},
+			}
+		}
+		proxyRSM = testapps.NewRSMFactory(testCtx.DefaultNamespace, clusterObj.Name+"-"+statelessComp, clusterObj.Name, statelessComp).
+			SetReplicas(1).
+			AddContainer(corev1.Container{
+				Name:  testapps.DefaultNginxContainerName,
+				Image: testapps.NginxImage,
+				Env:   []corev1.EnvVar{passwordEnv("BACKEND_PASSWORD")},
+			}).Create(&testCtx).GetObject()
+		mysqlRSM = testapps.NewRSMFactory(testCtx.DefaultNamespace, clusterObj.Name+"-"+consensusComp, clusterObj.Name, consensusComp).
+			SetReplicas(1).
+			AddContainer(corev1.Container{
+				Name:  testapps.DefaultMySQLContainerName,
+				Image: testapps.ApeCloudMySQLImage,
+				Env:   []corev1.EnvVar{passwordEnv("MYSQL_ROOT_PASSWORD")},
+			}).Create(&testCtx).GetObject()
+
+		By("mock cluster is Running")
+		Expect(testapps.ChangeObjStatus(&testCtx, clusterObj, func() {
+			clusterObj.Status.Phase = appsv1alpha1.RunningClusterPhase
+			clusterObj.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{
+				statelessComp: {Phase: appsv1alpha1.RunningClusterCompPhase},
+				consensusComp: {Phase: appsv1alpha1.RunningClusterCompPhase},
+			}
+		})).Should(Succeed())
+
+		By("create and start the rotateCredential opsRequest")
+		ops := testapps.NewOpsRequestObj("ops-rotate-credential-"+testCtx.GetRandomStr(), testCtx.DefaultNamespace,
+			clusterObj.Name, appsv1alpha1.RotateCredentialType)
+		ops.Spec.RotateCredential = &appsv1alpha1.RotateCredential{
+			ComponentOps: appsv1alpha1.ComponentOps{ComponentName: consensusComp},
+			GracePeriod:  "1h",
+		}
+		opsRes = &OpsResource{
+			Cluster:    clusterObj,
+			OpsRequest: testapps.CreateOpsRequest(ctx, testCtx, ops),
+			Recorder:   k8sManager.GetEventRecorderFor("opsrequest-controller"),
+		}
+		_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(testapps.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(appsv1alpha1.OpsCreatingPhase))
+		_, err = GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	getNewPassword := func() string {
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testCtx.DefaultNamespace,
+			Name: genRotateCredentialSecretName(opsRes.OpsRequest.Name)}, secret)).Should(Succeed())
+		return string(secret.Data[constant.AccountPasswdForSecret])
+	}
+
+	It("switches the connection secret and restarts the consumers after the password changed", func() {
+		By("the update statement of the admin account is used for the connection credential")
+		newPasswd := getNewPassword()
+		Expect(newPasswd).Should(HaveLen(16))
+		updateJobName := genRotateCredentialJobName(opsRes.OpsRequest.Name, rotateCredentialUpdateAction)
+		Eventually(testapps.CheckObj(&testCtx, types.NamespacedName{Namespace: testCtx.DefaultNamespace, Name: updateJobName},
+			func(g Gomega, job *batchv1.Job) {
+				envs := job.Spec.Template.Spec.Containers[0].Env
+				g.Expect(envs[0].Value).Should(Equal(fmt.Sprintf("ALTER USER root IDENTIFIED BY '%s';", newPasswd)))
+				g.Expect(envs[2].ValueFrom.SecretKeyRef.Name).Should(Equal(connSecretKey.Name))
+			})).Should(Succeed())
+
+		By("the connection secret is kept until the job succeeds")
+		_, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+		Expect(err).ShouldNot(HaveOccurred())
+		checkConnPassword(oldPasswd)
+
+		By("mock the job succeeds")
+		patchJobStatus(updateJobName, batchv1.JobComplete)
+		_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+		Expect(err).ShouldNot(HaveOccurred())
+		checkConnPassword(newPasswd)
+
+		By("both the new and the old passwords are valid within the grace period")
+		var rotatedAt string
+		Eventually(testapps.CheckObj(&testCtx, connSecretKey, func(g Gomega, secret *corev1.Secret) {
+			g.Expect(string(secret.Data[constant.AccountPreviousPasswdForSecret])).Should(Equal(oldPasswd))
+			rotatedAt = secret.Annotations[constant.CredentialRotatedAtAnnotationKey]
+			rotatedTime, err := time.Parse(time.RFC3339, rotatedAt)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			expireAt, err := time.Parse(time.RFC3339, secret.Annotations[constant.PreviousCredentialExpireAtAnnotationKey])
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(expireAt.Sub(rotatedTime)).Should(Equal(time.Hour))
+		})).Should(Succeed())
+		By("both the proxy and the component itself are restarted")
+		for _, rsm := range []*workloads.ReplicatedStateMachine{proxyRSM, mysqlRSM} {
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(rsm), func(g Gomega, rsm *workloads.ReplicatedStateMachine) {
+				g.Expect(rsm.Spec.Template.Annotations[constant.RestartAnnotationKey]).Should(Equal(rotatedAt))
+			})).Should(Succeed())
+		}
+		Eventually(testapps.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(appsv1alpha1.OpsRunningPhase))
+
+		By("mock the proxy and the component rolled out")
+		for _, rsm := range []*workloads.ReplicatedStateMachine{proxyRSM, mysqlRSM} {
+			Expect(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(rsm), func(rsm *workloads.ReplicatedStateMachine) {
+				rsm.Status.ObservedGeneration = rsm.Generation
+				rsm.Status.Replicas = 1
+				rsm.Status.UpdatedReplicas = 1
+				rsm.Status.ReadyReplicas = 1
+			})()).Should(Succeed())
+		}
+		_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(testapps.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(appsv1alpha1.OpsSucceedPhase))
+		Expect(opsRes.OpsRequest.Status.Progress).Should(Equal("4/4"))
+	})
+
+	It("rolls the password back and keeps the connection secret if the job fails", func() {
+		newPasswd := getNewPassword()
+		updateJobName := genRotateCredentialJobName(opsRes.OpsRequest.Name, rotateCredentialUpdateAction)
+		rollbackJobName := genRotateCredentialJobName(opsRes.OpsRequest.Name, rotateCredentialRollbackAction)
+
+		By("mock the job fails")
+		patchJobStatus(updateJobName, batchv1.JobFailed)
+		_, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+		Expect(err).ShouldNot(HaveOccurred())
+		checkConnPassword(oldPasswd)
+
+		By("the rollback job authenticates with the old credential")
+		Eventually(testapps.CheckObj(&testCtx, types.NamespacedName{Namespace: testCtx.DefaultNamespace, Name: rollbackJobName},
+			func(g Gomega, job *batchv1.Job) {
+				envs := job.Spec.Template.Spec.Containers[0].Env
+				g.Expect(envs[0].Value).Should(Equal(fmt.Sprintf("ALTER USER root IDENTIFIED BY '%s';", oldPasswd)))
+				g.Expect(envs[2].ValueFrom.SecretKeyRef.Name).Should(Equal(connSecretKey.Name))
+			})).Should(Succeed())
+		Eventually(testapps.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(appsv1alpha1.OpsRunningPhase))
+
+		By("mock the rollback job succeeds")
+		patchJobStatus(rollbackJobName, batchv1.JobComplete)
+		_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(testapps.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(appsv1alpha1.OpsFailedPhase))
+		checkConnPassword(oldPasswd)
+		Expect(newPasswd).ShouldNot(Equal(oldPasswd))
+		Eventually(testapps.CheckObj(&testCtx, connSecretKey, func(g Gomega, secret *corev1.Secret) {
+			g.Expect(secret.Data).ShouldNot(HaveKey(constant.AccountPreviousPasswdForSecret))
+		})).Should(Succeed())
+		for _, rsm := range []*workloads.ReplicatedStateMachine{proxyRSM, mysqlRSM} {
+			Consistently(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(rsm), func(g Gomega, rsm *workloads.ReplicatedStateMachine) {
+				g.Expect(rsm.Spec.Template.Annotations).ShouldNot(HaveKey(constant.RestartAnnotationKey))
+			})).Should(Succeed())
+		}
+	})
+
+	It("drops the previous password without a grace period", func() {
+		secret := &corev1.Secret{Data: map[string][]byte{
+			constant.AccountPasswdForSecret:         []byte("new-password"),
+			constant.AccountPreviousPasswdForSecret: []byte(oldPasswd),
+		}}
+		now := time.Now()
+		switchConnCredential(secret, []byte("newer-password"), 0, now)
+		Expect(string(secret.Data[constant.AccountPasswdForSecret])).Should(Equal("newer-password"))
+		Expect(secret.Data).ShouldNot(HaveKey(constant.AccountPreviousPasswdForSecret))
+		Expect(secret.Annotations).ShouldNot(HaveKey(constant.PreviousCredentialExpireAtAnnotationKey))
+		Expect(secret.Annotations[constant.CredentialRotatedAtAnnotationKey]).Should(Equal(now.Format(time.RFC3339)))
+	})
+})
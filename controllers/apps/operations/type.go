@@ -105,4 +105,8 @@ const (
 	ProcessingReasonSwitchovering = "Switchovering"
 	// ProcessingReasonBackup is the reason of the "OpsRequestProcessed" condition for the backup opsRequest processing in cluster.
 	ProcessingReasonBackup = "Backup"
+	// ProcessingReasonMonitoring is the reason of the "OpsRequestProcessed" condition for the monitor opsRequest processing in cluster.
+	ProcessingReasonMonitoring = "Monitoring"
+	// ProcessingReasonRotatingCredential is the reason of the "OpsRequestProcessed" condition for the rotate credential opsRequest processing in cluster.
+	ProcessingReasonRotatingCredential = "RotatingCredential"
 )
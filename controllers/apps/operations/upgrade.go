@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 )
 
@@ -54,9 +55,19 @@ func (u upgradeOpsHandler) ActionStartedCondition(reqCtx intctrlutil.RequestCtx,
 	return appsv1alpha1.NewHorizontalScalingCondition(opsRes.OpsRequest), nil
 }
 
-// Action modifies Cluster.spec.clusterVersionRef with opsRequest.spec.upgrade.clusterVersionRef
+// Action modifies Cluster.spec.clusterVersionRef with opsRequest.spec.upgrade.clusterVersionRef,
+// and records the policy to apply the config template changes of the new ClusterVersion to the cluster.
 func (u upgradeOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
-	opsRes.Cluster.Spec.ClusterVersionRef = opsRes.OpsRequest.Spec.Upgrade.ClusterVersionRef
+	upgradeSpec := opsRes.OpsRequest.Spec.Upgrade
+	opsRes.Cluster.Spec.ClusterVersionRef = upgradeSpec.ClusterVersionRef
+	upgradePolicy := upgradeSpec.UpgradePolicy
+	if upgradePolicy == "" {
+		upgradePolicy = appsv1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy
+	}
+	if opsRes.Cluster.Annotations == nil {
+		opsRes.Cluster.Annotations = map[string]string{}
+	}
+	opsRes.Cluster.Annotations[constant.ConfigTemplateUpgradePolicyAnnotationKey] = string(upgradePolicy)
 	return cli.Update(reqCtx.Ctx, opsRes.Cluster)
 }
 
@@ -64,13 +64,19 @@ func (vs verticalScalingHandler) Action(reqCtx intctrlutil.RequestCtx, cli clien
 			continue
 		}
 		// TODO: support specify class object name in the Class field
-		if verticalScaling.ClassDefRef != nil {
+		switch {
+		case verticalScaling.ClassDefRef != nil:
 			component.ClassDefRef = verticalScaling.ClassDefRef
-		} else {
+		case len(verticalScaling.PriorityClassName) > 0 && len(verticalScaling.Requests) == 0 && len(verticalScaling.Limits) == 0:
+			// only the priority class is changed, keep the resources unchanged
+		default:
 			// clear old class ref
 			component.ClassDefRef = &appsv1alpha1.ClassDefRef{}
 			component.Resources = verticalScaling.ResourceRequirements
 		}
+		if len(verticalScaling.PriorityClassName) > 0 {
+			component.PriorityClassName = verticalScaling.PriorityClassName
+		}
 		opsRes.Cluster.Spec.ComponentSpecs[index] = component
 	}
 	return cli.Update(reqCtx.Ctx, opsRes.Cluster)
@@ -92,6 +98,7 @@ func (vs verticalScalingHandler) SaveLastConfiguration(reqCtx intctrlutil.Reques
 		}
 		lastConfiguration := appsv1alpha1.LastComponentConfiguration{
 			ResourceRequirements: v.Resources,
+			PriorityClassName:    v.PriorityClassName,
 		}
 		if v.ClassDefRef != nil {
 			lastConfiguration.ClassDefRef = v.ClassDefRef
@@ -106,6 +113,7 @@ func (vs verticalScalingHandler) SaveLastConfiguration(reqCtx intctrlutil.Reques
 func (vs verticalScalingHandler) Cancel(reqCxt intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
 	return cancelComponentOps(reqCxt.Ctx, cli, opsRes, func(lastConfig *appsv1alpha1.LastComponentConfiguration, comp *appsv1alpha1.ClusterComponentSpec) error {
 		comp.Resources = lastConfig.ResourceRequirements
+		comp.PriorityClassName = lastConfig.PriorityClassName
 		if lastConfig.ClassDefRef != nil {
 			comp.ClassDefRef = lastConfig.ClassDefRef
 		}
@@ -51,7 +51,9 @@ const (
 func init() {
 	// the volume expansion operation only supports online expansion now
 	volumeExpansionBehaviour := OpsBehaviour{
-		OpsHandler: volumeExpansionOpsHandler{},
+		// the volumes of the failed or creating clusters can't be resized safely
+		FromClusterPhases: []appsv1alpha1.ClusterPhase{appsv1alpha1.RunningClusterPhase, appsv1alpha1.AbnormalClusterPhase},
+		OpsHandler:        volumeExpansionOpsHandler{},
 	}
 
 	opsMgr := GetOpsManager()
@@ -35,7 +35,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -62,15 +61,17 @@ type OpsRequestReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
 func (r *OpsRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, intctrlutil.LogFieldOpsRequest, req.NamespacedName)
 	reqCtx := intctrlutil.RequestCtx{
 		Ctx:      ctx,
 		Req:      req,
-		Log:      log.FromContext(ctx).WithValues("opsRequest", req.NamespacedName),
+		Log:      logger,
 		Recorder: r.Recorder,
 	}
 	opsCtrlHandler := &opsControllerHandler{}
 	return opsCtrlHandler.Handle(reqCtx, &operations.OpsResource{Recorder: r.Recorder},
 		r.fetchOpsRequest,
+		r.handleFleetOpsRequest,
 		r.handleDeletion,
 		r.fetchCluster,
 		r.addClusterLabelAndSetOwnerReference,
@@ -83,6 +84,7 @@ func (r *OpsRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 func (r *OpsRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1alpha1.OpsRequest{}).
+		Owns(&appsv1alpha1.OpsRequest{}).
 		Watches(&appsv1alpha1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.parseAllOpsRequest)).
 		Watches(&dpv1alpha1.Backup{}, handler.EnqueueRequestsFromMapFunc(r.parseBackupOpsRequest)).
 		Complete(r)
@@ -330,7 +332,13 @@ type opsControllerHandler struct {
 func (h *opsControllerHandler) Handle(reqCtx intctrlutil.RequestCtx,
 	opsRes *operations.OpsResource,
 	steps ...opsRequestStep) (ctrl.Result, error) {
+	withClusterLog := false
 	for _, step := range steps {
+		// correlate the logs with the cluster once it's fetched.
+		if !withClusterLog && opsRes.Cluster != nil {
+			reqCtx = reqCtx.WithLogValues(intctrlutil.LogFieldCluster, client.ObjectKeyFromObject(opsRes.Cluster))
+			withClusterLog = true
+		}
 		res, err := step(reqCtx, opsRes)
 		if res != nil {
 			return *res, err
@@ -0,0 +1,349 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/controllers/apps/operations"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+// handleFleetOpsRequest handles the OpsRequest which selects the clusters by spec.clusterSelector.
+// it fans out a child OpsRequest for each selected cluster, and the parent only tracks the progress of the children.
+func (r *OpsRequestReconciler) handleFleetOpsRequest(reqCtx intctrlutil.RequestCtx, opsRes *operations.OpsResource) (*ctrl.Result, error) {
+	opsRequest := opsRes.OpsRequest
+	if opsRequest.Spec.ClusterSelector == nil {
+		return nil, nil
+	}
+	// the child OpsRequests are garbage collected with the parent.
+	if !opsRequest.DeletionTimestamp.IsZero() {
+		return intctrlutil.ResultToP(intctrlutil.Reconciled())
+	}
+	switch opsRequest.Status.Phase {
+	case appsv1alpha1.OpsSucceedPhase:
+		return r.handleSucceedOpsRequest(reqCtx, opsRequest)
+	case appsv1alpha1.OpsFailedPhase, appsv1alpha1.OpsCancelledPhase:
+		return intctrlutil.ResultToP(intctrlutil.Reconciled())
+	}
+
+	patch := client.MergeFrom(opsRequest.DeepCopy())
+	if len(opsRequest.Status.Phase) == 0 {
+		clusters, err := r.selectClusters(reqCtx, opsRequest)
+		if err != nil {
+			return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
+		}
+		if len(clusters) == 0 {
+			opsRequest.Status.Phase = appsv1alpha1.OpsFailedPhase
+			opsRequest.Status.CompletionTimestamp = metav1.Now()
+			opsRequest.SetStatusCondition(*appsv1alpha1.NewValidateFailedCondition(appsv1alpha1.ReasonClusterNotFound,
+				"no cluster is selected by spec.clusterSelector"))
+			return r.patchFleetOpsStatus(reqCtx, opsRequest, patch)
+		}
+		for _, cluster := range clusters {
+			opsRequest.Status.Clusters = append(opsRequest.Status.Clusters, appsv1alpha1.OpsRequestClusterStatus{
+				ClusterName: cluster.Name,
+				Phase:       appsv1alpha1.OpsPendingPhase,
+			})
+		}
+		opsRequest.Status.StartTimestamp = metav1.Now()
+		condition := appsv1alpha1.NewProgressingCondition(opsRequest)
+		condition.Message = fmt.Sprintf("Start to process the OpsRequest: %s in %d clusters", opsRequest.Name, len(clusters))
+		opsRequest.SetStatusCondition(*condition)
+	}
+
+	children, err := r.listChildOpsRequests(reqCtx, opsRequest)
+	if err != nil {
+		return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
+	}
+	toStart, toCancel := syncFleetOpsRequestStatus(opsRequest, children)
+	for _, child := range toCancel {
+		if err = r.Client.Delete(reqCtx.Ctx, child); err != nil && !apierrors.IsNotFound(err) {
+			return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
+		}
+	}
+	for _, clusterName := range toStart {
+		child, err := r.createChildOpsRequest(reqCtx, opsRequest, clusterName)
+		// the child rejected by the admission fails the cluster only, the other clusters go on.
+		if err != nil && intctrlutil.IsUpdateRejected(err) {
+			rejectFleetCluster(opsRequest, clusterName, err.Error())
+			continue
+		}
+		if err != nil {
+			return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
+		}
+		for i := range opsRequest.Status.Clusters {
+			if opsRequest.Status.Clusters[i].ClusterName == clusterName {
+				opsRequest.Status.Clusters[i].OpsRequestName = child.Name
+			}
+		}
+	}
+	if opsRequest.Spec.Cancel && opsRequest.Status.CancelTimestamp.IsZero() {
+		opsRequest.Status.CancelTimestamp = metav1.Now()
+		opsRequest.SetStatusCondition(*appsv1alpha1.NewCancelingCondition(opsRequest))
+	}
+	if opsRequest.IsComplete() {
+		opsRequest.Status.CompletionTimestamp = metav1.Now()
+		opsRequest.SetStatusCondition(*newFleetCompletedCondition(opsRequest))
+		r.Recorder.Eventf(opsRequest, corev1.EventTypeNormal, string(opsRequest.Status.Phase),
+			"OpsRequest %s is %s on %s clusters", opsRequest.Name, opsRequest.Status.Phase, opsRequest.Status.Progress)
+	}
+	return r.patchFleetOpsStatus(reqCtx, opsRequest, patch)
+}
+
+// patchFleetOpsStatus patches the status of the parent OpsRequest if changed.
+// operations.PatchOpsStatus is not used here, as the parent OpsRequest does not refer to any cluster.
+func (r *OpsRequestReconciler) patchFleetOpsStatus(reqCtx intctrlutil.RequestCtx,
+	opsRequest *appsv1alpha1.OpsRequest,
+	patch client.Patch) (*ctrl.Result, error) {
+	if err := r.Client.Status().Patch(reqCtx.Ctx, opsRequest, patch); err != nil {
+		return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
+	}
+	return intctrlutil.ResultToP(intctrlutil.Reconciled())
+}
+
+// selectClusters lists the clusters selected by spec.clusterSelector in the namespace of the OpsRequest.
+func (r *OpsRequestReconciler) selectClusters(reqCtx intctrlutil.RequestCtx, opsRequest *appsv1alpha1.OpsRequest) ([]appsv1alpha1.Cluster, error) {
+	selector, err := metav1.LabelSelectorAsSelector(opsRequest.Spec.ClusterSelector)
+	if err != nil {
+		return nil, err
+	}
+	clusterList := &appsv1alpha1.ClusterList{}
+	if err = r.Client.List(reqCtx.Ctx, clusterList, client.InNamespace(opsRequest.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	clusters := clusterList.Items
+	slices.SortFunc(clusters, func(a, b appsv1alpha1.Cluster) bool {
+		return a.Name < b.Name
+	})
+	return clusters, nil
+}
+
+// listChildOpsRequests lists the child OpsRequests fanned out by the parent OpsRequest.
+func (r *OpsRequestReconciler) listChildOpsRequests(reqCtx intctrlutil.RequestCtx, opsRequest *appsv1alpha1.OpsRequest) ([]appsv1alpha1.OpsRequest, error) {
+	opsRequestList := &appsv1alpha1.OpsRequestList{}
+	if err := r.Client.List(reqCtx.Ctx, opsRequestList, client.InNamespace(opsRequest.Namespace),
+		client.MatchingLabels{constant.OpsRequestParentLabelKey: opsRequest.Name}); err != nil {
+		return nil, err
+	}
+	children := make([]appsv1alpha1.OpsRequest, 0, len(opsRequestList.Items))
+	for i := range opsRequestList.Items {
+		if metav1.IsControlledBy(&opsRequestList.Items[i], opsRequest) {
+			children = append(children, opsRequestList.Items[i])
+		}
+	}
+	return children, nil
+}
+
+// createChildOpsRequest creates the child OpsRequest of the parent OpsRequest for the cluster.
+func (r *OpsRequestReconciler) createChildOpsRequest(reqCtx intctrlutil.RequestCtx,
+	opsRequest *appsv1alpha1.OpsRequest,
+	clusterName string) (*appsv1alpha1.OpsRequest, error) {
+	cluster := &appsv1alpha1.Cluster{}
+	if err := r.Client.Get(reqCtx.Ctx, client.ObjectKey{Namespace: opsRequest.Namespace, Name: clusterName}, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		// the child OpsRequest will fail by itself if the cluster has been deleted.
+		cluster = nil
+	}
+	child := buildFleetChildOpsRequest(opsRequest, cluster, clusterName)
+	if err := controllerutil.SetControllerReference(opsRequest, child, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Client.Create(reqCtx.Ctx, child); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return child, nil
+}
+
+// buildFleetChildOpsRequest builds the child OpsRequest operating the cluster from the spec of the parent OpsRequest.
+// the cluster is nil if it doesn't exist anymore.
+func buildFleetChildOpsRequest(opsRequest *appsv1alpha1.OpsRequest, cluster *appsv1alpha1.Cluster, clusterName string) *appsv1alpha1.OpsRequest {
+	spec := opsRequest.Spec.DeepCopy()
+	spec.ClusterRef = clusterName
+	spec.ClusterSelector = nil
+	spec.MaxConcurrent = nil
+	spec.Cancel = false
+	// the children are deleted with the parent.
+	spec.TTLSecondsAfterSucceed = 0
+	// restart all the components of each cluster if not specified, as the component names vary between clusters.
+	if spec.Type == appsv1alpha1.RestartType && len(spec.RestartList) == 0 && cluster != nil {
+		for _, comp := range cluster.Spec.ComponentSpecs {
+			spec.RestartList = append(spec.RestartList, appsv1alpha1.ComponentOps{ComponentName: comp.Name})
+		}
+	}
+	return &appsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", opsRequest.Name, clusterName),
+			Namespace: opsRequest.Namespace,
+			Labels: map[string]string{
+				constant.OpsRequestParentLabelKey: opsRequest.Name,
+				constant.AppInstanceLabelKey:      clusterName,
+				constant.OpsRequestTypeLabelKey:   string(spec.Type),
+			},
+		},
+		Spec: *spec,
+	}
+}
+
+// syncFleetOpsRequestStatus syncs status.clusters from the child OpsRequests, and then the phase and progress of the
+// parent OpsRequest from status.clusters. it returns the clusters to start within spec.maxConcurrent, and the children
+// which should be deleted as the parent is cancelled before they are running.
+func syncFleetOpsRequestStatus(opsRequest *appsv1alpha1.OpsRequest,
+	children []appsv1alpha1.OpsRequest) ([]string, []*appsv1alpha1.OpsRequest) {
+	childMap := map[string]*appsv1alpha1.OpsRequest{}
+	for i := range children {
+		childMap[children[i].Spec.ClusterRef] = &children[i]
+	}
+	var (
+		cancelling = opsRequest.Spec.Cancel
+		running    int
+		pending    []string
+		toCancel   []*appsv1alpha1.OpsRequest
+	)
+	for i := range opsRequest.Status.Clusters {
+		status := &opsRequest.Status.Clusters[i]
+		child, ok := childMap[status.ClusterName]
+		if ok && !opsRequest.IsComplete(status.Phase) {
+			status.OpsRequestName = child.Name
+			status.Phase = child.Status.Phase
+			if len(status.Phase) == 0 {
+				status.Phase = appsv1alpha1.OpsPendingPhase
+			}
+			if status.Phase == appsv1alpha1.OpsFailedPhase {
+				status.Message = getOpsRequestFailureMessage(child)
+			}
+		}
+		switch {
+		case opsRequest.IsComplete(status.Phase):
+		case cancelling && len(status.OpsRequestName) == 0:
+			status.Phase = appsv1alpha1.OpsCancelledPhase
+		case cancelling && ok && status.Phase == appsv1alpha1.OpsPendingPhase:
+			toCancel = append(toCancel, child)
+			status.Phase = appsv1alpha1.OpsCancelledPhase
+		case len(status.OpsRequestName) == 0:
+			pending = append(pending, status.ClusterName)
+		default:
+			running++
+		}
+	}
+
+	var toStart []string
+	for _, clusterName := range pending {
+		if opsRequest.Spec.MaxConcurrent != nil && running >= int(*opsRequest.Spec.MaxConcurrent) {
+			break
+		}
+		toStart = append(toStart, clusterName)
+		running++
+	}
+	syncFleetOpsRequestPhase(opsRequest)
+	return toStart, toCancel
+}
+
+// syncFleetOpsRequestPhase syncs the phase and progress of the parent OpsRequest from status.clusters.
+func syncFleetOpsRequestPhase(opsRequest *appsv1alpha1.OpsRequest) {
+	var (
+		completed int
+		total     = len(opsRequest.Status.Clusters)
+	)
+	for _, status := range opsRequest.Status.Clusters {
+		if opsRequest.IsComplete(status.Phase) {
+			completed++
+		}
+	}
+	opsRequest.Status.Progress = fmt.Sprintf("%d/%d", completed, total)
+	cancelling := opsRequest.Spec.Cancel
+	switch {
+	case completed < total && cancelling:
+		opsRequest.Status.Phase = appsv1alpha1.OpsCancellingPhase
+	case completed < total:
+		opsRequest.Status.Phase = appsv1alpha1.OpsRunningPhase
+	case cancelling:
+		opsRequest.Status.Phase = appsv1alpha1.OpsCancelledPhase
+	case len(getFailedClusters(opsRequest)) > 0:
+		opsRequest.Status.Phase = appsv1alpha1.OpsFailedPhase
+	default:
+		opsRequest.Status.Phase = appsv1alpha1.OpsSucceedPhase
+	}
+}
+
+// rejectFleetCluster fails the cluster whose child OpsRequest is rejected at the admission with the message.
+func rejectFleetCluster(opsRequest *appsv1alpha1.OpsRequest, clusterName, message string) {
+	for i := range opsRequest.Status.Clusters {
+		status := &opsRequest.Status.Clusters[i]
+		if status.ClusterName == clusterName {
+			status.Phase = appsv1alpha1.OpsFailedPhase
+			status.Message = fmt.Sprintf("failed to create the OpsRequest for the cluster: %s", message)
+		}
+	}
+	syncFleetOpsRequestPhase(opsRequest)
+}
+
+// newFleetCompletedCondition creates the condition of the completed parent OpsRequest.
+func newFleetCompletedCondition(opsRequest *appsv1alpha1.OpsRequest) *metav1.Condition {
+	switch opsRequest.Status.Phase {
+	case appsv1alpha1.OpsCancelledPhase:
+		return appsv1alpha1.NewCancelSucceedCondition(opsRequest.Name)
+	case appsv1alpha1.OpsFailedPhase:
+		return appsv1alpha1.NewFailedCondition(opsRequest, fmt.Errorf("failed to process the OpsRequest: %s in clusters: %s",
+			opsRequest.Name, strings.Join(getFailedClusters(opsRequest), ", ")))
+	default:
+		condition := appsv1alpha1.NewSucceedCondition(opsRequest)
+		condition.Message = fmt.Sprintf("Successfully processed the OpsRequest: %s in %d clusters",
+			opsRequest.Name, len(opsRequest.Status.Clusters))
+		return condition
+	}
+}
+
+// getFailedClusters gets the clusters whose child OpsRequest failed.
+func getFailedClusters(opsRequest *appsv1alpha1.OpsRequest) []string {
+	var failed []string
+	for _, status := range opsRequest.Status.Clusters {
+		if status.Phase == appsv1alpha1.OpsFailedPhase {
+			failed = append(failed, status.ClusterName)
+		}
+	}
+	return failed
+}
+
+// getOpsRequestFailureMessage gets the message of the condition which failed the OpsRequest.
+func getOpsRequestFailureMessage(opsRequest *appsv1alpha1.OpsRequest) string {
+	for _, conditionType := range []string{appsv1alpha1.ConditionTypeFailed, appsv1alpha1.ConditionTypeValidated} {
+		condition := meta.FindStatusCondition(opsRequest.Status.Conditions, conditionType)
+		if condition != nil && condition.Status == metav1.ConditionFalse {
+			return condition.Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+func TestSyncFleetOpsRequestStatus(t *testing.T) {
+	newFleetOps := func() *appsv1alpha1.OpsRequest {
+		return &appsv1alpha1.OpsRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "restart-all", Namespace: "default"},
+			Spec: appsv1alpha1.OpsRequestSpec{
+				Type:            appsv1alpha1.RestartType,
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "test"}},
+				MaxConcurrent:   pointer.Int32(2),
+			},
+			Status: appsv1alpha1.OpsRequestStatus{
+				Phase: appsv1alpha1.OpsRunningPhase,
+				Clusters: []appsv1alpha1.OpsRequestClusterStatus{
+					{ClusterName: "cluster-a", Phase: appsv1alpha1.OpsPendingPhase},
+					{ClusterName: "cluster-b", Phase: appsv1alpha1.OpsPendingPhase},
+					{ClusterName: "cluster-c", Phase: appsv1alpha1.OpsPendingPhase},
+				},
+			},
+		}
+	}
+	newChild := func(parent *appsv1alpha1.OpsRequest, clusterName string, phase appsv1alpha1.OpsPhase) appsv1alpha1.OpsRequest {
+		child := buildFleetChildOpsRequest(parent, nil, clusterName)
+		child.Status.Phase = phase
+		if phase == appsv1alpha1.OpsFailedPhase {
+			child.Status.Conditions = []metav1.Condition{
+				*appsv1alpha1.NewFailedCondition(child, nil),
+			}
+		}
+		return *child
+	}
+	startClusters := func(ops *appsv1alpha1.OpsRequest, clusterNames []string) {
+		for _, name := range clusterNames {
+			for i := range ops.Status.Clusters {
+				if ops.Status.Clusters[i].ClusterName == name {
+					ops.Status.Clusters[i].OpsRequestName = ops.Name + "-" + name
+				}
+			}
+		}
+	}
+
+	// three clusters are operated two by two, and the failure of one cluster fails the parent.
+	ops := newFleetOps()
+	toStart, _ := syncFleetOpsRequestStatus(ops, nil)
+	if !reflect.DeepEqual(toStart, []string{"cluster-a", "cluster-b"}) {
+		t.Fatalf("expect to start cluster-a and cluster-b within maxConcurrent, but got %v", toStart)
+	}
+	if ops.Status.Progress != "0/3" || ops.Status.Phase != appsv1alpha1.OpsRunningPhase {
+		t.Fatalf("expect the OpsRequest running with progress 0/3, but got %s %s", ops.Status.Phase, ops.Status.Progress)
+	}
+	startClusters(ops, toStart)
+
+	children := []appsv1alpha1.OpsRequest{
+		newChild(ops, "cluster-a", appsv1alpha1.OpsSucceedPhase),
+		newChild(ops, "cluster-b", appsv1alpha1.OpsRunningPhase),
+	}
+	toStart, _ = syncFleetOpsRequestStatus(ops, children)
+	if !reflect.DeepEqual(toStart, []string{"cluster-c"}) {
+		t.Fatalf("expect to start cluster-c after cluster-a succeed, but got %v", toStart)
+	}
+	if ops.Status.Progress != "1/3" {
+		t.Fatalf("expect the progress 1/3, but got %s", ops.Status.Progress)
+	}
+	startClusters(ops, toStart)
+
+	children = []appsv1alpha1.OpsRequest{
+		newChild(ops, "cluster-a", appsv1alpha1.OpsSucceedPhase),
+		newChild(ops, "cluster-b", appsv1alpha1.OpsFailedPhase),
+		newChild(ops, "cluster-c", appsv1alpha1.OpsSucceedPhase),
+	}
+	toStart, _ = syncFleetOpsRequestStatus(ops, children)
+	if len(toStart) != 0 {
+		t.Fatalf("expect no cluster to start, but got %v", toStart)
+	}
+	if ops.Status.Progress != "3/3" || ops.Status.Phase != appsv1alpha1.OpsFailedPhase {
+		t.Fatalf("expect the OpsRequest failed with progress 3/3, but got %s %s", ops.Status.Phase, ops.Status.Progress)
+	}
+	if failed := getFailedClusters(ops); !reflect.DeepEqual(failed, []string{"cluster-b"}) {
+		t.Fatalf("expect cluster-b failed, but got %v", failed)
+	}
+	if ops.Status.Clusters[1].Message == "" {
+		t.Errorf("expect the failure message of cluster-b")
+	}
+	if condition := newFleetCompletedCondition(ops); !strings.Contains(condition.Message, "cluster-b") {
+		t.Errorf("expect the failed cluster listed in the condition, but got %s", condition.Message)
+	}
+
+	// the child rejected at the admission fails its cluster, and the others go on.
+	ops = newFleetOps()
+	toStart, _ = syncFleetOpsRequestStatus(ops, nil)
+	startClusters(ops, toStart[:1])
+	rejectFleetCluster(ops, "cluster-b", "admission webhook denied the request")
+	if status := ops.Status.Clusters[1]; status.Phase != appsv1alpha1.OpsFailedPhase ||
+		!strings.Contains(status.Message, "admission webhook denied the request") {
+		t.Fatalf("expect cluster-b failed with the admission message, but got %s %s", status.Phase, status.Message)
+	}
+	if ops.Status.Progress != "1/3" || ops.Status.Phase != appsv1alpha1.OpsRunningPhase {
+		t.Fatalf("expect the OpsRequest running with progress 1/3, but got %s %s", ops.Status.Phase, ops.Status.Progress)
+	}
+	children = []appsv1alpha1.OpsRequest{newChild(ops, "cluster-a", appsv1alpha1.OpsRunningPhase)}
+	toStart, _ = syncFleetOpsRequestStatus(ops, children)
+	if !reflect.DeepEqual(toStart, []string{"cluster-c"}) {
+		t.Fatalf("expect to start cluster-c after cluster-b rejected, but got %v", toStart)
+	}
+
+	// cancelling the parent cancels the children not running yet.
+	ops = newFleetOps()
+	ops.Spec.MaxConcurrent = pointer.Int32(1)
+	startClusters(ops, []string{"cluster-a", "cluster-b"})
+	ops.Spec.Cancel = true
+	children = []appsv1alpha1.OpsRequest{
+		newChild(ops, "cluster-a", appsv1alpha1.OpsRunningPhase),
+		newChild(ops, "cluster-b", appsv1alpha1.OpsPendingPhase),
+	}
+	toStart, toCancel := syncFleetOpsRequestStatus(ops, children)
+	if len(toStart) != 0 {
+		t.Fatalf("expect no cluster to start after cancelled, but got %v", toStart)
+	}
+	if len(toCancel) != 1 || toCancel[0].Spec.ClusterRef != "cluster-b" {
+		t.Fatalf("expect to cancel the pending child of cluster-b, but got %v", toCancel)
+	}
+	if ops.Status.Phase != appsv1alpha1.OpsCancellingPhase || ops.Status.Clusters[2].Phase != appsv1alpha1.OpsCancelledPhase {
+		t.Fatalf("expect the OpsRequest cancelling and cluster-c cancelled, but got %s %s", ops.Status.Phase, ops.Status.Clusters[2].Phase)
+	}
+	children = []appsv1alpha1.OpsRequest{newChild(ops, "cluster-a", appsv1alpha1.OpsSucceedPhase)}
+	_, _ = syncFleetOpsRequestStatus(ops, children)
+	if ops.Status.Phase != appsv1alpha1.OpsCancelledPhase || ops.Status.Progress != "3/3" {
+		t.Fatalf("expect the OpsRequest cancelled with progress 3/3, but got %s %s", ops.Status.Phase, ops.Status.Progress)
+	}
+}
+
+func TestBuildFleetChildOpsRequest(t *testing.T) {
+	parent := &appsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart-all", Namespace: "default"},
+		Spec: appsv1alpha1.OpsRequestSpec{
+			Type:                   appsv1alpha1.RestartType,
+			ClusterSelector:        &metav1.LabelSelector{MatchLabels: map[string]string{"env": "test"}},
+			MaxConcurrent:          pointer.Int32(1),
+			TTLSecondsAfterSucceed: 60,
+		},
+	}
+	cluster := &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Namespace: "default"},
+		Spec: appsv1alpha1.ClusterSpec{
+			ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{{Name: "mysql"}, {Name: "proxy"}},
+		},
+	}
+	child := buildFleetChildOpsRequest(parent, cluster, cluster.Name)
+	if child.Name != "restart-all-cluster-a" || child.Labels[constant.OpsRequestParentLabelKey] != parent.Name {
+		t.Fatalf("unexpected child OpsRequest %s with labels %v", child.Name, child.Labels)
+	}
+	if child.Spec.ClusterRef != cluster.Name || child.Spec.ClusterSelector != nil || child.Spec.MaxConcurrent != nil {
+		t.Fatalf("expect the child OpsRequest refer to the cluster only")
+	}
+	if child.Spec.TTLSecondsAfterSucceed != 0 {
+		t.Errorf("expect the child OpsRequest deleted with the parent")
+	}
+	if len(child.Spec.RestartList) != 2 {
+		t.Errorf("expect all the components of the cluster restarted, but got %v", child.Spec.RestartList)
+	}
+}
@@ -22,15 +22,19 @@ package apps
 import (
 	"context"
 	"fmt"
-	"strings"
+	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +42,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	opsutil "github.com/apecloud/kubeblocks/controllers/apps/operations/util"
@@ -90,6 +95,13 @@ const (
 	systemAccountjobPrefix               = "sysacc"
 )
 
+// The accounts failed to provision are retried with exponential backoff, up to the max attempts.
+const (
+	systemAccountMaxAttempts    string = "SYSACCOUNT_MAX_ATTEMPTS"
+	systemAccountRetryBaseDelay string = "SYSACCOUNT_RETRY_BASE_DELAY"
+	systemAccountRetryMaxDelay  string = "SYSACCOUNT_RETRY_MAX_DELAY"
+)
+
 var (
 	// systemAccountLog is a logger during runtime
 	systemAccountLog logr.Logger
@@ -97,6 +109,9 @@ var (
 
 func init() {
 	viper.SetDefault(systemAccountsDebugMode, false)
+	viper.SetDefault(systemAccountMaxAttempts, 5)
+	viper.SetDefault(systemAccountRetryBaseDelay, 10*time.Second)
+	viper.SetDefault(systemAccountRetryMaxDelay, 5*time.Minute)
 	systemAccountLog = log.Log.WithName("systemAccountRuntime")
 }
 
@@ -120,10 +135,11 @@ func init() {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
 func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, intctrlutil.LogFieldCluster, req.NamespacedName)
 	reqCtx := intctrlutil.RequestCtx{
 		Ctx:      ctx,
 		Req:      req,
-		Log:      log.FromContext(ctx).WithValues("cluster", req.NamespacedName),
+		Log:      logger,
 		Recorder: r.Recorder,
 	}
 	reqCtx.Log.V(1).Info("reconcile", "cluster", req.NamespacedName)
@@ -174,6 +190,14 @@ func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	componentVersions := clusterVersion.Spec.GetDefNameMappingComponents()
 
+	// the provisioning states of accounts are tracked in the component status.
+	origCluster := cluster.DeepCopy()
+	// the min delay to retry the failed accounts.
+	var retryAfter time.Duration
+	// the accounts given up after the max attempts, in form of component/account.
+	var exhaustedAccounts []string
+	maxAttempts := viper.GetInt32(systemAccountMaxAttempts)
+
 	// process accounts for each component
 	processAccountsForComponent := func(compDef *appsv1alpha1.ClusterComponentDefinition, compDecl *appsv1alpha1.ClusterComponentSpec,
 		svcEP *corev1.Endpoints, headlessEP *corev1.Endpoints) error {
@@ -225,6 +249,33 @@ func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				continue
 			}
 
+			// the account has neither secret nor running job now. It is provisioned for the first time, retried if
+			// the last attempt failed, or re-provisioned from scratch if its secret was deleted.
+			var (
+				attempts        int32
+				lastAttemptTime *metav1.Time
+			)
+			accountStatus := getSystemAccountStatus(cluster, compDecl.Name, account.Name)
+			if accountStatus != nil {
+				lastAttemptTime = accountStatus.LastAttemptTime
+				if accountStatus.Phase != appsv1alpha1.AccountProvisioned {
+					attempts = accountStatus.Attempts
+				}
+			}
+			if attempts >= maxAttempts {
+				reqCtx.Log.V(1).Info("give up provisioning account after max attempts", "account", account.Name, "attempts", attempts)
+				exhaustedAccounts = append(exhaustedAccounts, fmt.Sprintf("%s/%s", compDecl.Name, account.Name))
+				continue
+			}
+			if attempts > 0 && lastAttemptTime != nil {
+				if delay := getRetryDelay(attempts) - time.Since(lastAttemptTime.Time); delay > 0 {
+					if retryAfter == 0 || delay < retryAfter {
+						retryAfter = delay
+					}
+					continue
+				}
+			}
+
 			strategy := reCreate
 			if detectedEngineFacts&accountID != 0 {
 				strategy = inPlaceUpdate
@@ -239,9 +290,17 @@ func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 					engine = newCustomizedEngine(execConfig, cluster, compDecl.Name)
 				}
 				reqCtx.Log.V(1).Info("create account by stmt", "cluster", req.NamespacedName, "account", account.Name, "strategy", strategy)
-				if err := r.createByStmt(reqCtx, cluster, compDef, compKey, engine, account, svcEP, headlessEP, strategy); err != nil {
+				if err := r.createByStmt(reqCtx, cluster, compDef, compKey, engine, account, svcEP, headlessEP, strategy, attempts+1, lastAttemptTime); err != nil {
 					return err
 				}
+				// truncate to seconds as it is serialized, to keep the job names consistent across reconciliations.
+				attemptTime := metav1.Now().Rfc3339Copy()
+				setSystemAccountStatus(cluster, compDecl.Name, appsv1alpha1.SystemAccountStatus{
+					Name:            account.Name,
+					Phase:           appsv1alpha1.AccountProvisionInProgress,
+					Attempts:        attempts + 1,
+					LastAttemptTime: &attemptTime,
+				})
 			case appsv1alpha1.ReferToExisting:
 				if err := r.createByReferringToExisting(reqCtx, cluster, compKey, account); err != nil {
 					return err
@@ -280,9 +339,30 @@ func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	// surface the terminal failure of the accounts, the condition is kept until all components are reconciled.
+	if len(exhaustedAccounts) > 0 {
+		cond := newSystemAccountsProvisionFailedCondition(exhaustedAccounts, maxAttempts)
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, cond.Type) {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, cond.Reason, cond.Message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, cond)
+	} else if reconcileCounter == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeSystemAccountsProvisionFailed)
+	}
+
+	if !reflect.DeepEqual(origCluster.Status, cluster.Status) {
+		patch := client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{})
+		if err := r.Client.Status().Patch(reqCtx.Ctx, cluster, patch); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+
 	if reconcileCounter > 0 {
 		return intctrlutil.Requeue(reqCtx.Log, "Not all components have been reconciled. Requeue request.")
 	}
+	if retryAfter > 0 {
+		return intctrlutil.RequeueAfter(retryAfter, reqCtx.Log, "Retry the failed accounts later.")
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -301,24 +381,23 @@ func (r *SystemAccountReconciler) createByStmt(reqCtx intctrlutil.RequestCtx,
 	compKey componentUniqueKey,
 	engine *customizedEngine,
 	account appsv1alpha1.SystemAccountConfig,
-	svcEP *corev1.Endpoints, headlessEP *corev1.Endpoints, strategy updateStrategy) error {
+	svcEP *corev1.Endpoints, headlessEP *corev1.Endpoints, strategy updateStrategy, attempt int32, lastAttemptTime *metav1.Time) error {
 	policy := account.ProvisionPolicy
 
-	generateJobName := func() string {
-		// render a job object, named after account name
-		randSuffix := rand.String(5)
-		fullJobName := strings.Join([]string{systemAccountjobPrefix, compKey.clusterName, compKey.componentName, string(account.Name), randSuffix}, "-")
-		if len(fullJobName) > 63 {
-			return systemAccountjobPrefix + "-" + string(account.Name) + "-" + randSuffix
-		} else {
-			return fullJobName
-		}
-	}
-
 	stmts, passwd := getCreationStmtForAccount(compKey, compDef.SystemAccounts.PasswordConfig, account, strategy)
 
 	for _, ep := range retrieveEndpoints(policy.Scope, svcEP, headlessEP) {
-		job := renderJob(generateJobName(), engine, compKey, stmts, ep)
+		// the job name is deterministic, skip it if it has been created by a concurrent reconciliation.
+		jobName := generateJobName(compKey, account.Name, attempt, lastAttemptTime, ep)
+		exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client,
+			types.NamespacedName{Namespace: compKey.namespace, Name: jobName}, &batchv1.Job{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		job := renderJob(jobName, engine, compKey, stmts, ep)
 		controllerutil.AddFinalizer(job, constant.DBClusterFinalizerName)
 		if job.Annotations == nil {
 			job.Annotations = map[string]string{}
@@ -334,7 +413,7 @@ func (r *SystemAccountReconciler) createByStmt(reqCtx intctrlutil.RequestCtx,
 			return err
 		}
 		// create job
-		if err := r.Client.Create(reqCtx.Ctx, job); err != nil {
+		if err := r.Client.Create(reqCtx.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
 			return err
 		}
 		reqCtx.Log.V(1).Info("created job", "job", job.Name, "passwd", passwd)
@@ -403,11 +482,18 @@ func (r *SystemAccountReconciler) getAccountFacts(reqCtx intctrlutil.RequestCtx,
 		return appsv1alpha1.KBAccountInvalid, err
 	}
 
-	// get all running jobs
+	// get all running jobs, the finished ones are tracked by the account status.
 	jobs := &batchv1.JobList{}
 	if err := r.Client.List(reqCtx.Ctx, jobs, client.InNamespace(key.namespace), ml); err != nil {
 		return appsv1alpha1.KBAccountInvalid, err
 	}
+	runningJobs := make([]batchv1.Job, 0, len(jobs.Items))
+	for _, job := range jobs.Items {
+		if !isJobFinished(&job) {
+			runningJobs = append(runningJobs, job)
+		}
+	}
+	jobs.Items = runningJobs
 
 	detectedFacts := getAcctFromSecretAndJobs(secrets, jobs)
 	reqCtx.Log.V(1).Info("Detected account facts", "facts", detectedFacts)
@@ -519,10 +605,17 @@ func (r *SystemAccountReconciler) jobCompletionHandler() *handler.Funcs {
 				return
 			}
 
+			// trigger the reconciliation to retry the failed account or to mark the cluster as reconciled.
+			defer q.Add(reconcile.Request{NamespacedName: clusterKey})
+
 			if containsJobCondition(*job, job.Status.Conditions, batchv1.JobFailed, corev1.ConditionTrue) {
 				logger.V(1).Info("job failed", "job", job.Name)
 				r.Recorder.Eventf(cluster, corev1.EventTypeNormal, SysAcctCreate,
 					"Failed to create accounts for cluster: %s, component: %s, accounts: %s", cluster.Name, componentName, accountName)
+				msg := fmt.Sprintf("job %s failed", job.Name)
+				if err := r.updateAccountStatus(clusterKey, componentName, appsv1alpha1.AccountName(accountName), appsv1alpha1.AccountProvisionFailed, msg); err != nil {
+					logger.Error(err, "failed to update account status", "account", accountName)
+				}
 				return
 			}
 
@@ -539,10 +632,14 @@ func (r *SystemAccountReconciler) jobCompletionHandler() *handler.Funcs {
 				return
 			}
 
-			if err := r.Client.Create(context.TODO(), secret); err != nil {
+			// the secret may have been created by another job of the account.
+			if err := r.Client.Create(context.TODO(), secret); err != nil && !apierrors.IsAlreadyExists(err) {
 				logger.Error(err, "failed to create secret", "secret", secret.Name)
 				return
 			}
+			if err := r.updateAccountStatus(clusterKey, componentName, appsv1alpha1.AccountName(accountName), appsv1alpha1.AccountProvisioned, ""); err != nil {
+				logger.Error(err, "failed to update account status", "account", accountName)
+			}
 
 			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, SysAcctCreate,
 				"Created accounts for cluster: %s, component: %s, accounts: %s", cluster.Name, componentName, accountName)
@@ -550,6 +647,33 @@ func (r *SystemAccountReconciler) jobCompletionHandler() *handler.Funcs {
 	}
 }
 
+// updateAccountStatus updates the provisioning phase of the account with the result of a job.
+// An account is provisioned once any of its jobs succeeds, and the failures of the other jobs will not override it.
+func (r *SystemAccountReconciler) updateAccountStatus(clusterKey types.NamespacedName, compName string,
+	accountName appsv1alpha1.AccountName, phase appsv1alpha1.AccountProvisionPhase, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &appsv1alpha1.Cluster{}
+		if err := r.Client.Get(context.TODO(), clusterKey, cluster); err != nil {
+			return err
+		}
+		status := getSystemAccountStatus(cluster, compName, accountName)
+		if status == nil {
+			status = &appsv1alpha1.SystemAccountStatus{Name: accountName}
+		}
+		if status.Phase == appsv1alpha1.AccountProvisioned && phase == appsv1alpha1.AccountProvisionFailed {
+			return nil
+		}
+		if status.Phase == phase && status.Message == message {
+			return nil
+		}
+		patch := client.MergeFromWithOptions(cluster.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		status.Phase = phase
+		status.Message = message
+		setSystemAccountStatus(cluster, compName, *status)
+		return r.Client.Status().Patch(context.TODO(), cluster, patch)
+	})
+}
+
 // existsOperations checks if the cluster is doing operations
 func existsOperations(cluster *appsv1alpha1.Cluster) bool {
 	opsRequestMap, _ := opsutil.GetOpsRequestSliceFromCluster(cluster)
@@ -22,9 +22,11 @@ package apps
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -39,6 +41,7 @@ import (
 	"github.com/apecloud/kubeblocks/internal/generics"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 	testk8s "github.com/apecloud/kubeblocks/internal/testutil/k8s"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
 
 var _ = Describe("SystemAccount Controller", func() {
@@ -586,6 +589,174 @@ var _ = Describe("SystemAccount Controller", func() {
 		})
 	}) // end of context
 
+	Context("When Provisioning Fails", func() {
+		var (
+			clustersMap    map[string]types.NamespacedName
+			mysqlTestCases map[string]*sysAcctTestCase
+		)
+
+		BeforeEach(func() {
+			cleanEnv()
+			DeferCleanup(cleanEnv)
+
+			// retry the failed accounts soon
+			viper.Set(systemAccountRetryBaseDelay, time.Second)
+			DeferCleanup(viper.Set, systemAccountRetryBaseDelay, 10*time.Second)
+
+			// setup testcase
+			mysqlTestCases = map[string]*sysAcctTestCase{
+				"wesql-with-accts": {
+					componentName:   mysqlCompName,
+					componentDefRef: mysqlCompDefName,
+					accounts:        getAllSysAccounts(),
+				},
+			}
+			clustersMap = initSysAccountTestsAndCluster(mysqlTestCases)
+		})
+
+		listRunningJobs := func(g Gomega, namespace string, ml client.MatchingLabels) []batchv1.Job {
+			jobs := &batchv1.JobList{}
+			g.Expect(k8sClient.List(ctx, jobs, client.InNamespace(namespace), ml)).To(Succeed())
+			runningJobs := make([]batchv1.Job, 0)
+			for i := range jobs.Items {
+				if !isJobFinished(&jobs.Items[i]) {
+					runningJobs = append(runningJobs, jobs.Items[i])
+				}
+			}
+			return runningJobs
+		}
+
+		mockJobsFinished := func(jobs []batchv1.Job, condType batchv1.JobConditionType) {
+			for i := range jobs {
+				job := &jobs[i]
+				Expect(testapps.ChangeObjStatus(&testCtx, job, func() {
+					job.Status.Conditions = []batchv1.JobCondition{{
+						Type:   condType,
+						Status: corev1.ConditionTrue,
+					}}
+				})).To(Succeed())
+			}
+		}
+
+		checkAccountStatus := func(clusterKey types.NamespacedName, compName string, account appsv1alpha1.AccountName,
+			phase appsv1alpha1.AccountProvisionPhase, attempts int32) {
+			Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
+				status := getSystemAccountStatus(cluster, compName, account)
+				g.Expect(status).ShouldNot(BeNil())
+				g.Expect(status.Phase).Should(Equal(phase))
+				g.Expect(status.Attempts).Should(Equal(attempts))
+			})).Should(Succeed())
+		}
+
+		It("Should retry the failed account and re-provision the account whose secret is deleted", func() {
+			testCase := mysqlTestCases["wesql-with-accts"]
+			clusterKey := clustersMap["wesql-with-accts"]
+			patchClusterToRunning(clusterKey, testCase.componentName)
+
+			By("Pick an account created by jobs")
+			var account appsv1alpha1.AccountName
+			for _, acc := range testCase.accounts {
+				if testCase.resourceMap[acc].jobNum > 0 {
+					account = acc
+					break
+				}
+			}
+			Expect(account).ShouldNot(BeEmpty())
+			jobNum := testCase.resourceMap[account].jobNum
+			ml := getLabelsForSecretsAndJobs(componentUniqueKey{
+				namespace:     clusterKey.Namespace,
+				clusterName:   clusterKey.Name,
+				componentName: testCase.componentName})
+			ml[constant.ClusterAccountLabelKey] = string(account)
+
+			var jobs []batchv1.Job
+			Eventually(func(g Gomega) {
+				jobs = listRunningJobs(g, clusterKey.Namespace, ml)
+				g.Expect(jobs).Should(HaveLen(jobNum))
+			}).Should(Succeed())
+			checkAccountStatus(clusterKey, testCase.componentName, account, appsv1alpha1.AccountProvisionInProgress, 1)
+
+			By("Mock the jobs failed, the account should be retried")
+			mockJobsFinished(jobs, batchv1.JobFailed)
+			failedJobNames := make([]string, 0, len(jobs))
+			for _, job := range jobs {
+				failedJobNames = append(failedJobNames, job.Name)
+			}
+			Eventually(func(g Gomega) {
+				jobs = listRunningJobs(g, clusterKey.Namespace, ml)
+				g.Expect(jobs).Should(HaveLen(jobNum))
+				for _, job := range jobs {
+					g.Expect(failedJobNames).ShouldNot(ContainElement(job.Name))
+				}
+			}).Should(Succeed())
+			checkAccountStatus(clusterKey, testCase.componentName, account, appsv1alpha1.AccountProvisionInProgress, 2)
+
+			By("Mock the retried jobs succeeded, the secret should be created")
+			mockJobsFinished(jobs, batchv1.JobComplete)
+			secret := &corev1.Secret{}
+			Eventually(func(g Gomega) {
+				secrets := &corev1.SecretList{}
+				g.Expect(k8sClient.List(ctx, secrets, client.InNamespace(clusterKey.Namespace), ml)).To(Succeed())
+				g.Expect(secrets.Items).Should(HaveLen(1))
+				secret = &secrets.Items[0]
+			}).Should(Succeed())
+			checkAccountStatus(clusterKey, testCase.componentName, account, appsv1alpha1.AccountProvisioned, 2)
+
+			By("Delete the secret, the account should be re-provisioned")
+			Expect(testapps.ChangeObj(&testCtx, secret, func(obj *corev1.Secret) {
+				obj.Finalizers = nil
+			})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(listRunningJobs(g, clusterKey.Namespace, ml)).Should(HaveLen(jobNum))
+			}).Should(Succeed())
+			checkAccountStatus(clusterKey, testCase.componentName, account, appsv1alpha1.AccountProvisionInProgress, 1)
+		})
+
+		It("Should surface the terminal failure after the max attempts", func() {
+			viper.Set(systemAccountMaxAttempts, 1)
+			DeferCleanup(viper.Set, systemAccountMaxAttempts, 5)
+
+			testCase := mysqlTestCases["wesql-with-accts"]
+			clusterKey := clustersMap["wesql-with-accts"]
+			patchClusterToRunning(clusterKey, testCase.componentName)
+
+			By("Pick an account created by jobs")
+			var account appsv1alpha1.AccountName
+			for _, acc := range testCase.accounts {
+				if testCase.resourceMap[acc].jobNum > 0 {
+					account = acc
+					break
+				}
+			}
+			Expect(account).ShouldNot(BeEmpty())
+			ml := getLabelsForSecretsAndJobs(componentUniqueKey{
+				namespace:     clusterKey.Namespace,
+				clusterName:   clusterKey.Name,
+				componentName: testCase.componentName})
+			ml[constant.ClusterAccountLabelKey] = string(account)
+
+			var jobs []batchv1.Job
+			Eventually(func(g Gomega) {
+				jobs = listRunningJobs(g, clusterKey.Namespace, ml)
+				g.Expect(jobs).Should(HaveLen(testCase.resourceMap[account].jobNum))
+			}).Should(Succeed())
+
+			By("Mock the jobs failed, the account should not be retried anymore")
+			mockJobsFinished(jobs, batchv1.JobFailed)
+			checkAccountStatus(clusterKey, testCase.componentName, account, appsv1alpha1.AccountProvisionFailed, 1)
+			Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
+				cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeSystemAccountsProvisionFailed)
+				g.Expect(cond).ShouldNot(BeNil())
+				g.Expect(cond.Reason).Should(Equal(ReasonMaxAttemptsExceeded))
+				g.Expect(cond.Message).Should(ContainSubstring(string(account)))
+			})).Should(Succeed())
+			Consistently(func(g Gomega) {
+				g.Expect(listRunningJobs(g, clusterKey.Namespace, ml)).Should(BeEmpty())
+			}).Should(Succeed())
+		})
+	})
+
 	Context("When Update Cluster", func() {
 		var (
 			clustersMap    map[string]types.NamespacedName
@@ -20,10 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"fmt"
+	"hash/fnv"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sethvargo/go-password/password"
+	"golang.org/x/exp/slices"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -351,3 +355,76 @@ func completeExecConfig(execConfig *appsv1alpha1.CmdExecutorConfig, version *app
 		execConfig.Env = sysAccountSpec.CmdExecutorConfig.Env
 	}
 }
+
+// generateJobName returns a deterministic name of the job provisioning the account on the endpoint, so that the
+// reconciliations observing the same account status will not create duplicate jobs for the same account.
+// The jobs of each attempt are distinguished by the attempt number and the time of the previous attempt.
+func generateJobName(key componentUniqueKey, account appsv1alpha1.AccountName, attempt int32,
+	lastAttemptTime *metav1.Time, endpoint string) string {
+	seed := []string{key.clusterName, key.componentName, endpoint}
+	if lastAttemptTime != nil {
+		seed = append(seed, lastAttemptTime.UTC().Format(time.RFC3339))
+	}
+	hasher := fnv.New32a()
+	hasher.Write([]byte(strings.Join(seed, "/")))
+	suffix := fmt.Sprintf("%d-%08x", attempt, hasher.Sum32())
+	fullJobName := strings.Join([]string{systemAccountjobPrefix, key.clusterName, key.componentName, string(account), suffix}, "-")
+	if len(fullJobName) > 63 {
+		return strings.Join([]string{systemAccountjobPrefix, string(account), suffix}, "-")
+	}
+	return fullJobName
+}
+
+// isJobFinished checks whether the job has completed or failed.
+func isJobFinished(job *batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// getSystemAccountStatus returns a copy of the provisioning status of the account, or nil if not found.
+func getSystemAccountStatus(cluster *appsv1alpha1.Cluster, compName string, account appsv1alpha1.AccountName) *appsv1alpha1.SystemAccountStatus {
+	compStatus, ok := cluster.Status.Components[compName]
+	if !ok {
+		return nil
+	}
+	for i := range compStatus.SystemAccounts {
+		if compStatus.SystemAccounts[i].Name == account {
+			return compStatus.SystemAccounts[i].DeepCopy()
+		}
+	}
+	return nil
+}
+
+// setSystemAccountStatus sets the provisioning status of the account into the component status.
+func setSystemAccountStatus(cluster *appsv1alpha1.Cluster, compName string, status appsv1alpha1.SystemAccountStatus) {
+	if cluster.Status.Components == nil {
+		cluster.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{}
+	}
+	compStatus := cluster.Status.Components[compName]
+	idx := slices.IndexFunc(compStatus.SystemAccounts, func(s appsv1alpha1.SystemAccountStatus) bool {
+		return s.Name == status.Name
+	})
+	if idx < 0 {
+		compStatus.SystemAccounts = append(compStatus.SystemAccounts, status)
+	} else {
+		compStatus.SystemAccounts[idx] = status
+	}
+	cluster.Status.Components[compName] = compStatus
+}
+
+// getRetryDelay returns the exponential backoff delay before retrying an account after the given attempts.
+func getRetryDelay(attempts int32) time.Duration {
+	delay := viper.GetDuration(systemAccountRetryBaseDelay)
+	maxDelay := viper.GetDuration(systemAccountRetryMaxDelay)
+	for i := int32(1); i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
@@ -24,9 +24,11 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
@@ -445,3 +447,59 @@ func TestMergeSystemAccountConfig(t *testing.T) {
 	assert.Len(t, accountConfig.Env, 1)
 	assert.Contains(t, accountConfig.Env, testEnv)
 }
+
+func TestGenerateJobName(t *testing.T) {
+	compKey := componentUniqueKey{
+		namespace:     "default",
+		clusterName:   "cluster",
+		componentName: "mysql",
+	}
+	endpoint := "10.0.0.1"
+	lastAttemptTime := metav1.NewTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+
+	// the job name is deterministic for the same attempt
+	name := generateJobName(compKey, appsv1alpha1.AdminAccount, 1, nil, endpoint)
+	assert.Equal(t, name, generateJobName(compKey, appsv1alpha1.AdminAccount, 1, nil, endpoint))
+	assert.True(t, strings.HasPrefix(name, "sysacc-cluster-mysql-kbadmin-1-"))
+
+	// the jobs of different attempts, endpoints or rounds have different names
+	assert.NotEqual(t, name, generateJobName(compKey, appsv1alpha1.AdminAccount, 2, nil, endpoint))
+	assert.NotEqual(t, name, generateJobName(compKey, appsv1alpha1.AdminAccount, 1, nil, "10.0.0.2"))
+	assert.NotEqual(t, name, generateJobName(compKey, appsv1alpha1.AdminAccount, 1, &lastAttemptTime, endpoint))
+
+	// the job name is shortened if too long
+	compKey.clusterName = strings.Repeat("a", 63)
+	name = generateJobName(compKey, appsv1alpha1.AdminAccount, 1, nil, endpoint)
+	assert.LessOrEqual(t, len(name), 63)
+	assert.True(t, strings.HasPrefix(name, "sysacc-kbadmin-1-"))
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	baseDelay := viper.GetDuration(systemAccountRetryBaseDelay)
+	assert.Equal(t, baseDelay, getRetryDelay(1))
+	assert.Equal(t, 2*baseDelay, getRetryDelay(2))
+	assert.Equal(t, 4*baseDelay, getRetryDelay(3))
+	assert.Equal(t, viper.GetDuration(systemAccountRetryMaxDelay), getRetryDelay(100))
+}
+
+func TestSystemAccountStatus(t *testing.T) {
+	cluster := &appsv1alpha1.Cluster{}
+	assert.Nil(t, getSystemAccountStatus(cluster, "mysql", appsv1alpha1.AdminAccount))
+
+	setSystemAccountStatus(cluster, "mysql", appsv1alpha1.SystemAccountStatus{
+		Name:     appsv1alpha1.AdminAccount,
+		Phase:    appsv1alpha1.AccountProvisionFailed,
+		Attempts: 1,
+	})
+	setSystemAccountStatus(cluster, "mysql", appsv1alpha1.SystemAccountStatus{
+		Name:     appsv1alpha1.AdminAccount,
+		Phase:    appsv1alpha1.AccountProvisionInProgress,
+		Attempts: 2,
+	})
+	assert.Len(t, cluster.Status.Components["mysql"].SystemAccounts, 1)
+	status := getSystemAccountStatus(cluster, "mysql", appsv1alpha1.AdminAccount)
+	assert.NotNil(t, status)
+	assert.Equal(t, appsv1alpha1.AccountProvisionInProgress, status.Phase)
+	assert.Equal(t, int32(2), status.Attempts)
+	assert.Nil(t, getSystemAccountStatus(cluster, "mysql", appsv1alpha1.ProbeAccount))
+}
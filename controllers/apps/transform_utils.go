@@ -107,8 +107,8 @@ func sendWarningEventWithError(
 	cluster *appsv1alpha1.Cluster,
 	reason string,
 	err error) {
-	// ignore requeue error
-	if err == nil || intctrlutil.IsRequeueError(err) {
+	// ignore requeue error, except the rejected updates
+	if err == nil || (intctrlutil.IsRequeueError(err) && !intctrlutil.IsUpdateRejectedError(err)) {
 		return
 	}
 	controllerErr := intctrlutil.UnwrapControllerError(err)
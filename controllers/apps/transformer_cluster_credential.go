@@ -20,18 +20,59 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
 	"github.com/apecloud/kubeblocks/internal/controller/component"
 	"github.com/apecloud/kubeblocks/internal/controller/factory"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
 	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
+)
+
+const (
+	// connCredentialRecoverPolicy recovers the deleted connection credential secret from the snapshot.
+	connCredentialRecoverPolicy = "Recover"
+	// connCredentialRegeneratePolicy recovers the deleted connection credential secret from the snapshot, and
+	// regenerates its password by a RotateCredential OpsRequest, which changes it on the engine as well.
+	connCredentialRegeneratePolicy = "Regenerate"
+
+	// connCredentialEncryptionKeySecretName is the name of the secret in the namespace of the controller, which
+	// holds the key encrypting the snapshots of the connection credentials.
+	connCredentialEncryptionKeySecretName = "kubeblocks-conn-credential-encryption-key"
+	connCredentialEncryptionKey           = "key"
 )
 
-// ClusterCredentialTransformer creates the connection credential secret
-type ClusterCredentialTransformer struct{}
+// connCredentialSnapshotAnnotations are the annotations of the connection credential kept in the snapshot,
+// which record the state of the credential rotation.
+var connCredentialSnapshotAnnotations = []string{
+	constant.CredentialRotatedAtAnnotationKey,
+	constant.PreviousCredentialExpireAtAnnotationKey,
+}
+
+// ClusterCredentialTransformer creates the connection credential secret, and keeps a snapshot of it which follows
+// its changes, e.g. by the credential rotations. The data of the snapshot is encrypted by the key kept in the namespace
+// of the controller, so it's not readable by the users of the cluster namespace. Once the secret is deleted while the
+// snapshot exists, it's recovered from the snapshot, and its password is regenerated as well if the policy configured
+// by CONN_CREDENTIAL_RECOVERY_POLICY is Regenerate. The ConnCredentialRecreated condition and a warning event record
+// the incident.
+type ClusterCredentialTransformer struct {
+	client.Client
+}
 
 var _ graph.Transformer = &ClusterCredentialTransformer{}
 
@@ -75,8 +116,253 @@ func (c *ClusterCredentialTransformer) Transform(ctx graph.TransformContext, dag
 	if synthesizedComponent != nil {
 		secret := factory.BuildConnCredential(transCtx.ClusterDef, cluster, synthesizedComponent)
 		if secret != nil {
-			ictrltypes.LifecycleObjectCreate(dag, secret, root)
+			return c.reconcileConnCredential(transCtx, dag, root, secret)
+		}
+	}
+	return nil
+}
+
+func (c *ClusterCredentialTransformer) reconcileConnCredential(transCtx *ClusterTransformContext, dag *graph.DAG,
+	root *ictrltypes.LifecycleVertex, proto *corev1.Secret) error {
+	cluster := transCtx.Cluster
+	secret := &corev1.Secret{}
+	secretExists, err := getSecretIfExists(transCtx, client.ObjectKeyFromObject(proto), secret)
+	if err != nil {
+		return err
+	}
+	snapshot := &corev1.Secret{}
+	snapshotKey := client.ObjectKey{Namespace: cluster.Namespace, Name: component.GenerateConnCredentialSnapshotName(cluster.Name)}
+	snapshotExists, err := getSecretIfExists(transCtx, snapshotKey, snapshot)
+	if err != nil {
+		return err
+	}
+	if !secretExists && !snapshotExists {
+		// the secret is created for the first time.
+		ictrltypes.LifecycleObjectCreate(dag, proto, root)
+		return nil
+	}
+
+	encryptionKey, err := c.getConnCredentialEncryptionKey(transCtx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case secretExists && !snapshotExists:
+		snapshot, err = buildConnCredentialSnapshot(secret, snapshotKey, encryptionKey)
+		if err != nil {
+			return err
+		}
+		ictrltypes.LifecycleObjectCreate(dag, snapshot, root)
+	case secretExists:
+		// the snapshot is re-encrypted only if the credential changes, or it's not readable by the key.
+		plain := snapshot.DeepCopy()
+		plain.Data, err = decryptConnCredential(encryptionKey, snapshot.Data)
+		plainCopy := plain.DeepCopy()
+		syncConnCredentialSnapshot(secret, plain)
+		if err == nil && reflect.DeepEqual(plainCopy, plain) {
+			return nil
+		}
+		snapshotCopy := snapshot.DeepCopy()
+		snapshot.Annotations = plain.Annotations
+		if snapshot.Data, err = encryptConnCredential(encryptionKey, plain.Data); err != nil {
+			return err
+		}
+		ictrltypes.LifecycleObjectPatch(dag, snapshot, snapshotCopy, root)
+	default:
+		// the secret was deleted, the snapshot is synced once the secret is recreated.
+		data, err := decryptConnCredential(encryptionKey, snapshot.Data)
+		if err != nil {
+			return fmt.Errorf("failed to recover the connection credential secret %s from the snapshot %s: %s",
+				proto.Name, snapshot.Name, err.Error())
+		}
+		snapshot.Data = data
+		condition := recreateConnCredential(proto, snapshot)
+		condition.ObservedGeneration = cluster.Generation
+		transCtx.EventRecorder.Event(cluster, corev1.EventTypeWarning, condition.Reason, condition.Message)
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+		ictrltypes.LifecycleObjectCreate(dag, proto, root)
+	}
+	return nil
+}
+
+// recreateConnCredential recovers the deleted secret from the decrypted snapshot, and requests the regeneration of
+// its password if the policy is Regenerate. The password is recovered in both cases, as it's the one the engine
+// accepts until the RotateCredential OpsRequest changes it. It returns the condition recording the recreation.
+func recreateConnCredential(secret, snapshot *corev1.Secret) metav1.Condition {
+	secret.StringData = nil
+	syncConnCredentialSnapshot(snapshot, secret)
+	if viper.GetString(constant.CfgKeyConnCredentialRecoveryPolicy) == connCredentialRegeneratePolicy {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[constant.CredentialRegenerateAtAnnotationKey] = time.Now().Format(time.RFC3339)
+		return newConnCredentialRecreatedCondition(ReasonConnCredentialRegenerated,
+			fmt.Sprintf("the connection credential secret %s was deleted, it's recovered from the snapshot %s "+
+				"and its password is regenerated by a RotateCredential OpsRequest", secret.Name, snapshot.Name))
+	}
+	return newConnCredentialRecreatedCondition(ReasonConnCredentialRecovered,
+		fmt.Sprintf("the connection credential secret %s was deleted, it's recovered from the snapshot %s",
+			secret.Name, snapshot.Name))
+}
+
+func buildConnCredentialSnapshot(secret *corev1.Secret, key client.ObjectKey, encryptionKey []byte) (*corev1.Secret, error) {
+	snapshot := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    map[string]string{},
+		},
+	}
+	for k, v := range secret.Labels {
+		snapshot.Labels[k] = v
+	}
+	syncConnCredentialSnapshot(secret, snapshot)
+	data, err := encryptConnCredential(encryptionKey, snapshot.Data)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Data = data
+	return snapshot, nil
+}
+
+// syncConnCredentialSnapshot copies the data and the rotation annotations of the credential from src to dst.
+func syncConnCredentialSnapshot(src, dst *corev1.Secret) {
+	dst.Data = nil
+	for k, v := range src.Data {
+		if dst.Data == nil {
+			dst.Data = map[string][]byte{}
+		}
+		dst.Data[k] = v
+	}
+	for _, key := range connCredentialSnapshotAnnotations {
+		value, ok := src.Annotations[key]
+		switch {
+		case ok:
+			if dst.Annotations == nil {
+				dst.Annotations = map[string]string{}
+			}
+			dst.Annotations[key] = value
+		case dst.Annotations != nil:
+			delete(dst.Annotations, key)
+		}
+	}
+}
+
+// getConnCredentialEncryptionKey gets the key encrypting the snapshots of the connection credentials, it's generated
+// on the first use. The key is created directly rather than by the plan, as the snapshots of all the clusters share it.
+func (c *ClusterCredentialTransformer) getConnCredentialEncryptionKey(transCtx *ClusterTransformContext) ([]byte, error) {
+	secret := &corev1.Secret{}
+	secretKey := client.ObjectKey{Namespace: viper.GetString(constant.CfgKeyCtrlrMgrNS), Name: connCredentialEncryptionKeySecretName}
+	exists, err := getSecretIfExists(transCtx, secretKey, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		key := make([]byte, 32)
+		if _, err = rand.Read(key); err != nil {
+			return nil, err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: secretKey.Namespace,
+				Name:      secretKey.Name,
+				Labels:    map[string]string{constant.AppManagedByLabelKey: constant.AppName},
+			},
+			Data: map[string][]byte{connCredentialEncryptionKey: key},
+		}
+		if err = c.Client.Create(transCtx.Context, secret); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return nil, err
+			}
+			// the key was created by another reconciliation in the meantime.
+			if err = transCtx.Client.Get(transCtx.Context, secretKey, secret); err != nil {
+				return nil, err
+			}
+		}
+	}
+	key := secret.Data[connCredentialEncryptionKey]
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid key in the secret %s/%s, it must be 32 bytes", secretKey.Namespace, secretKey.Name)
+	}
+	return key, nil
+}
+
+// encryptConnCredential encrypts the values of the data by AES-GCM, the nonce is prepended to the cipher text.
+func encryptConnCredential(key []byte, data map[string][]byte) (map[string][]byte, error) {
+	gcm, err := newConnCredentialCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	var encrypted map[string][]byte
+	for k, v := range data {
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err = rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		if encrypted == nil {
+			encrypted = map[string][]byte{}
+		}
+		encrypted[k] = gcm.Seal(nonce, nonce, v, nil)
+	}
+	return encrypted, nil
+}
+
+// decryptConnCredential decrypts the values of the data encrypted by encryptConnCredential.
+func decryptConnCredential(key []byte, data map[string][]byte) (map[string][]byte, error) {
+	gcm, err := newConnCredentialCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	var decrypted map[string][]byte
+	for k, v := range data {
+		if len(v) < gcm.NonceSize() {
+			return nil, fmt.Errorf("invalid encrypted value of key %s", k)
+		}
+		value, err := gcm.Open(nil, v[:gcm.NonceSize()], v[gcm.NonceSize():], nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the value of key %s: %s", k, err.Error())
+		}
+		if decrypted == nil {
+			decrypted = map[string][]byte{}
+		}
+		decrypted[k] = value
+	}
+	return decrypted, nil
+}
+
+func newConnCredentialCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// getConnCredentialComponent returns the component the connection credential is built for, which is the first one
+// of the component definitions providing the service, nil if none of them is in the cluster.
+func getConnCredentialComponent(cluster *appsv1alpha1.Cluster, clusterDef *appsv1alpha1.ClusterDefinition) *appsv1alpha1.ClusterComponentSpec {
+	if clusterDef == nil {
+		return nil
+	}
+	compSpecMap := cluster.Spec.GetDefNameMappingComponents()
+	for _, compDef := range clusterDef.Spec.ComponentDefs {
+		if compDef.Service == nil {
+			continue
 		}
+		if comps := compSpecMap[compDef.Name]; len(comps) > 0 {
+			return cluster.Spec.GetComponentByName(comps[0].Name)
+		}
+		return nil
 	}
 	return nil
 }
+
+func getSecretIfExists(transCtx *ClusterTransformContext, key client.ObjectKey, secret *corev1.Secret) (bool, error) {
+	if err := transCtx.Client.Get(transCtx.Context, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
+)
+
+var _ = Describe("cluster credential transformer test.", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+	)
+
+	var (
+		ctx         context.Context
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+		recorder    *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterDef := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, mysqlCompDefName).
+			GetObject()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			GetObject()
+		recorder = record.NewFakeRecorder(10)
+		transCtx = &ClusterTransformContext{
+			Context:       ctx,
+			Client:        k8sClient,
+			EventRecorder: recorder,
+			Logger:        logf.FromContext(ctx).WithValues("transformer-cluster-credential-test", testCtx.DefaultNamespace),
+			Cluster:       cluster,
+			ClusterDef:    clusterDef,
+		}
+		transformer = &ClusterCredentialTransformer{Client: k8sClient}
+		viper.Set(constant.CfgKeyConnCredentialRecoveryPolicy, connCredentialRecoverPolicy)
+		viper.Set(constant.CfgKeyCtrlrMgrNS, testCtx.DefaultNamespace)
+	})
+
+	AfterEach(func() {
+		viper.Set(constant.CfgKeyConnCredentialRecoveryPolicy, connCredentialRecoverPolicy)
+	})
+
+	// reconcile runs the transformer, and creates the secrets to create in the DAG as the plan does.
+	reconcile := func() map[string]*corev1.Secret {
+		dag := graph.NewDAG()
+		ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+		secrets := map[string]*corev1.Secret{}
+		for _, vertex := range ictrltypes.FindAll[*corev1.Secret](dag) {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			secret, _ := v.Obj.(*corev1.Secret)
+			secrets[secret.Name] = secret
+			switch *v.Action {
+			case ictrltypes.CREATE:
+				Expect(k8sClient.Create(ctx, secret.DeepCopy())).Should(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).Should(Succeed())
+				})
+			case ictrltypes.PATCH:
+				Expect(k8sClient.Patch(ctx, secret, client.MergeFrom(v.ObjCopy))).Should(Succeed())
+			}
+		}
+		return secrets
+	}
+
+	getSecret := func(name string) *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: name}, secret)).Should(Succeed())
+		return secret
+	}
+
+	deleteSecret := func(name string) {
+		secret := getSecret(name)
+		Expect(k8sClient.Delete(ctx, secret)).Should(Succeed())
+		Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKeyFromObject(secret), &corev1.Secret{}, false)).Should(Succeed())
+	}
+
+	// getSnapshotData returns the decrypted data of the snapshot.
+	getSnapshotData := func(name string) map[string][]byte {
+		key, err := (&ClusterCredentialTransformer{Client: k8sClient}).getConnCredentialEncryptionKey(transCtx)
+		Expect(err).ShouldNot(HaveOccurred())
+		data, err := decryptConnCredential(key, getSecret(name).Data)
+		Expect(err).ShouldNot(HaveOccurred())
+		return data
+	}
+
+	// provision creates the connection credential secret and its snapshot.
+	provision := func() (string, string) {
+		secretName := component.GenerateConnCredential(cluster.Name)
+		snapshotName := component.GenerateConnCredentialSnapshotName(cluster.Name)
+
+		By("create the connection credential secret")
+		secrets := reconcile()
+		Expect(secrets).Should(HaveKey(secretName))
+		Expect(secrets).ShouldNot(HaveKey(snapshotName))
+		Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: secretName},
+			&corev1.Secret{}, true)).Should(Succeed())
+
+		By("take the snapshot of the secret")
+		secrets = reconcile()
+		Expect(secrets).Should(HaveKey(snapshotName))
+		Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: snapshotName},
+			&corev1.Secret{}, true)).Should(Succeed())
+		Expect(getSnapshotData(snapshotName)).Should(Equal(getSecret(secretName).Data))
+		By("check the password is not kept in plaintext by the snapshot")
+		passwd := getSecret(secretName).Data[constant.AccountPasswdForSecret]
+		Expect(getSecret(snapshotName).Data[constant.AccountPasswdForSecret]).ShouldNot(ContainSubstring(string(passwd)))
+		Expect(recorder.Events).Should(BeEmpty())
+		return secretName, snapshotName
+	}
+
+	Context("connection credential recreation", func() {
+		It("should sync the snapshot with the changes of the secret", func() {
+			secretName, snapshotName := provision()
+
+			secret := getSecret(secretName)
+			patch := client.MergeFrom(secret.DeepCopy())
+			secret.Data[constant.AccountPasswdForSecret] = []byte("rotated-password")
+			secret.Annotations = map[string]string{constant.CredentialRotatedAtAnnotationKey: time.Now().Format(time.RFC3339)}
+			Expect(k8sClient.Patch(ctx, secret, patch)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(secret), func(g Gomega, s *corev1.Secret) {
+				g.Expect(string(s.Data[constant.AccountPasswdForSecret])).Should(Equal("rotated-password"))
+			})).Should(Succeed())
+
+			reconcile()
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: snapshotName},
+				func(g Gomega, s *corev1.Secret) {
+					g.Expect(s.Annotations).Should(HaveKey(constant.CredentialRotatedAtAnnotationKey))
+				})).Should(Succeed())
+			Expect(string(getSnapshotData(snapshotName)[constant.AccountPasswdForSecret])).Should(Equal("rotated-password"))
+
+			By("no patch of the snapshot if the credential is unchanged")
+			Expect(reconcile()).ShouldNot(HaveKey(snapshotName))
+		})
+
+		It("should recover the deleted secret from the snapshot", func() {
+			secretName, snapshotName := provision()
+			origData := getSecret(secretName).Data
+
+			deleteSecret(secretName)
+			secrets := reconcile()
+			Expect(secrets).Should(HaveKey(secretName))
+			Expect(getSecret(secretName).Data).Should(Equal(origData))
+
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeConnCredentialRecreated)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Reason).Should(Equal(ReasonConnCredentialRecovered))
+			Expect(cond.Message).Should(ContainSubstring(snapshotName))
+			Expect(recorder.Events).Should(Receive(And(ContainSubstring(corev1.EventTypeWarning), ContainSubstring(ReasonConnCredentialRecovered))))
+		})
+
+		It("should recover the deleted secret and request the regeneration of its password", func() {
+			viper.Set(constant.CfgKeyConnCredentialRecoveryPolicy, connCredentialRegeneratePolicy)
+			secretName, _ := provision()
+			origData := getSecret(secretName).Data
+
+			deleteSecret(secretName)
+			secrets := reconcile()
+			Expect(secrets).Should(HaveKey(secretName))
+			secret := getSecret(secretName)
+			// the password is changed by the RotateCredential OpsRequest, which authenticates with the recovered one.
+			Expect(secret.Data).Should(Equal(origData))
+			Expect(secret.Annotations).Should(HaveKey(constant.CredentialRegenerateAtAnnotationKey))
+
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeConnCredentialRecreated)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Reason).Should(Equal(ReasonConnCredentialRegenerated))
+			Expect(recorder.Events).Should(Receive(And(ContainSubstring(corev1.EventTypeWarning), ContainSubstring(ReasonConnCredentialRegenerated))))
+		})
+	})
+})
@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -36,8 +37,10 @@ import (
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
 	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 )
 
 // ClusterDeletionTransformer handles cluster deletion
@@ -59,6 +62,16 @@ func (t *ClusterDeletionTransformer) Transform(ctx graph.TransformContext, dag *
 		return err
 	}
 
+	// the namespace is being deleted, all the namespaced sub-resources will be removed along with it,
+	// waiting for them may leave the namespace Terminating forever.
+	terminating, err := isNamespaceTerminating(transCtx, cluster.Namespace)
+	if err != nil {
+		return err
+	}
+	if terminating {
+		return t.cleanupInTerminatingNamespace(transCtx, dag, root)
+	}
+
 	// list all kinds to be deleted based on v1alpha1.TerminationPolicyType
 	var toDeleteNamespacedKinds, toDeleteNonNamespacedKinds []client.ObjectList
 	var toPreserveKinds []client.ObjectList
@@ -98,20 +111,18 @@ func (t *ClusterDeletionTransformer) Transform(ctx graph.TransformContext, dag *
 		if err != nil {
 			return err
 		}
-		// construct cluster spec JSON string
-		clusterSpec := cluster.DeepCopy()
-		clusterSpec.ObjectMeta = metav1.ObjectMeta{
-			Name: cluster.GetName(),
-			UID:  cluster.GetUID(),
-		}
-		clusterSpec.Status = appsv1alpha1.ClusterStatus{}
-		b, err := json.Marshal(*clusterSpec)
+		snapshot, err := buildLastAppliedClusterSnapshot(cluster)
 		if err != nil {
 			return err
 		}
-		clusterJSON := string(b)
+		snapshotFound := false
 		for _, o := range objs {
 			origObj := o.DeepCopyObject().(client.Object)
+			// refresh the snapshot left by the previous halt
+			if cm, ok := o.(*corev1.ConfigMap); ok && cm.Name == snapshot.Name {
+				cm.Data = snapshot.Data
+				snapshotFound = true
+			}
 			controllerutil.RemoveFinalizer(o, constant.DBClusterFinalizerName)
 			ownerRefs := o.GetOwnerReferences()
 			for i, ref := range ownerRefs {
@@ -123,17 +134,14 @@ func (t *ClusterDeletionTransformer) Transform(ctx graph.TransformContext, dag *
 				break
 			}
 			o.SetOwnerReferences(ownerRefs)
-			annot := o.GetAnnotations()
-			if annot == nil {
-				annot = map[string]string{}
-			}
-			// annotated last-applied Cluster spec
-			annot[constant.LastAppliedClusterAnnotationKey] = clusterJSON
-			o.SetAnnotations(annot)
+			annotateLastAppliedCluster(o, snapshot.Name)
 			vertex := &ictrltypes.LifecycleVertex{Obj: o, ObjCopy: origObj, Action: ictrltypes.ActionUpdatePtr()}
 			dag.AddVertex(vertex)
 			dag.Connect(root, vertex)
 		}
+		if !snapshotFound {
+			ictrltypes.LifecycleObjectCreate(dag, snapshot, root)
+		}
 		return nil
 	}
 	// handle preserved objects update vertex
@@ -185,6 +193,97 @@ func (t *ClusterDeletionTransformer) Transform(ctx graph.TransformContext, dag *
 	return graph.ErrPrematureStop
 }
 
+// cleanupInTerminatingNamespace does the best-effort cleanup of a cluster in a terminating namespace:
+// the termination policy, the preserved resources and the retained backups are skipped, the finalizers of
+// KubeBlocks are removed from the namespaced sub-resources, which are left to the namespace controller to delete,
+// the non-namespaced sub-resources are deleted, and the cluster is deleted without waiting for them.
+func (t *ClusterDeletionTransformer) cleanupInTerminatingNamespace(transCtx *ClusterTransformContext,
+	dag *graph.DAG, root *ictrltypes.LifecycleVertex) error {
+	cluster := transCtx.OrigCluster
+	transCtx.Logger.Info("namespace is terminating, skip the terminationPolicy, preserving resources and retained backups, "+
+		"remove the finalizers of the sub-resources and the cluster without waiting for them deleted",
+		"namespace", cluster.Namespace, "terminationPolicy", cluster.Spec.TerminationPolicy)
+	if cluster.Spec.TerminationPolicy == appsv1alpha1.DoNotTerminate {
+		transCtx.EventRecorder.Eventf(cluster, corev1.EventTypeWarning, "DoNotTerminate",
+			"spec.terminationPolicy %s is bypassed since the namespace %s is terminating.",
+			cluster.Spec.TerminationPolicy, cluster.Namespace)
+	}
+	transCtx.EventRecorder.Eventf(cluster, corev1.EventTypeWarning, constant.ReasonDeletingCR,
+		"Namespace %s is terminating, skip terminationPolicy %s and clean up %s: %s in best effort",
+		cluster.Namespace, cluster.Spec.TerminationPolicy, strings.ToLower(cluster.GetObjectKind().GroupVersionKind().Kind), cluster.GetName())
+
+	ml := getAppInstanceML(*cluster)
+	namespacedKinds, nonNamespacedKinds := kindsForWipeOut()
+	namespacedObjs, err := getClusterOwningNamespacedObjects(transCtx, *cluster, ml, namespacedKinds)
+	if err != nil {
+		return err
+	}
+	for _, o := range namespacedObjs {
+		origObj := o.DeepCopyObject().(client.Object)
+		// the backups can't be finalized as the jobs deleting the backup data can't be created in the namespace.
+		removed := controllerutil.RemoveFinalizer(o, constant.DBClusterFinalizerName)
+		removed = controllerutil.RemoveFinalizer(o, dptypes.DataProtectionFinalizerName) || removed
+		if !removed {
+			continue
+		}
+		vertex := &ictrltypes.LifecycleVertex{Obj: o, ObjCopy: origObj, Action: ictrltypes.ActionPatchPtr()}
+		dag.AddVertex(vertex)
+		dag.Connect(root, vertex)
+	}
+	nonNamespacedObjs, err := getClusterOwningNonNamespacedObjects(transCtx, *cluster, ml, nonNamespacedKinds)
+	if err != nil {
+		return err
+	}
+	for _, o := range nonNamespacedObjs {
+		vertex := &ictrltypes.LifecycleVertex{Obj: o, Action: ictrltypes.ActionDeletePtr()}
+		dag.AddVertex(vertex)
+		dag.Connect(root, vertex)
+	}
+	root.Action = ictrltypes.ActionDeletePtr()
+	return graph.ErrPrematureStop
+}
+
+// isNamespaceTerminating checks whether the namespace is being deleted or has been deleted.
+func isNamespaceTerminating(transCtx *ClusterTransformContext, namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := transCtx.Client.Get(transCtx.Context, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return !ns.GetDeletionTimestamp().IsZero(), nil
+}
+
+// buildLastAppliedClusterSnapshot builds the ConfigMap storing the last-applied cluster spec. The spec is kept out of
+// the annotations of the preserved objects, which may exceed the annotation size limit for the clusters with many components.
+func buildLastAppliedClusterSnapshot(cluster *appsv1alpha1.Cluster) (*corev1.ConfigMap, error) {
+	// construct cluster spec JSON string
+	clusterSpec := cluster.DeepCopy()
+	clusterSpec.ObjectMeta = metav1.ObjectMeta{
+		Name: cluster.GetName(),
+		UID:  cluster.GetUID(),
+	}
+	clusterSpec.Status = appsv1alpha1.ClusterStatus{}
+	b, err := json.Marshal(*clusterSpec)
+	if err != nil {
+		return nil, err
+	}
+	return factory.BuildLastAppliedCluster(cluster, string(b)), nil
+}
+
+// annotateLastAppliedCluster refers the preserved object to the last-applied cluster snapshot,
+// and drops the inline last-applied cluster annotated by the prior versions.
+func annotateLastAppliedCluster(obj client.Object, snapshotName string) {
+	annot := obj.GetAnnotations()
+	if annot == nil {
+		annot = map[string]string{}
+	}
+	delete(annot, constant.LastAppliedClusterAnnotationKey)
+	annot[constant.LastAppliedClusterRefAnnotationKey] = snapshotName
+	obj.SetAnnotations(annot)
+}
+
 func kindsForDoNotTerminate() ([]client.ObjectList, []client.ObjectList) {
 	return []client.ObjectList{}, []client.ObjectList{}
 }
@@ -195,6 +294,7 @@ func kindsForHalt() ([]client.ObjectList, []client.ObjectList) {
 		&policyv1.PodDisruptionBudgetList{},
 		&corev1.ServiceAccountList{},
 		&rbacv1.RoleBindingList{},
+		&corev1.ResourceQuotaList{},
 	}
 	nonNamespacedKindsPlus := []client.ObjectList{
 		&rbacv1.ClusterRoleBindingList{},
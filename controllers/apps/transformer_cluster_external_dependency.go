@@ -0,0 +1,264 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	lorry "github.com/apecloud/kubeblocks/lorry/client"
+)
+
+const (
+	// externalDependencyProbeInterval is the interval to probe the unreachable external dependencies again.
+	externalDependencyProbeInterval = 30 * time.Second
+	// externalDependencyProbeCacheTTL is the period in which a successful probe is reused instead of probing again.
+	externalDependencyProbeCacheTTL = 5 * time.Minute
+)
+
+// newLorryClient creates the client of lorry in the pod, which is replaced in the tests.
+var newLorryClient = lorry.NewClient
+
+// ClusterExternalDependencyTransformer probes the external dependencies declared by the ClusterDefinition and
+// overridden by the cluster, e.g. the object store to archive the WALs. It sets the ExternalDependencyUnreachable
+// condition if any of them is unreachable, which holds the cluster from being reported as Running, and probes them
+// again periodically until they are reachable.
+//
+// The dependencies are probed by lorry in an available pod of the cluster, so they are probed with the network view
+// of the pods, e.g. the network policies and the DNS of the namespace. Prober probes them instead while none of the
+// pods is available, e.g. while the cluster is being created.
+type ClusterExternalDependencyTransformer struct {
+	Prober component.ExternalDependencyProber
+	// cache caches the successful probes across the reconciliations, the dependencies are probed on every
+	// reconciliation if it's nil.
+	cache *externalDependencyProbeCache
+}
+
+var _ graph.Transformer = &ClusterExternalDependencyTransformer{}
+
+func (t *ClusterExternalDependencyTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() || t.Prober == nil || transCtx.ClusterDef == nil {
+		return nil
+	}
+	dependencies := buildExternalDependencies(cluster, transCtx.ClusterDef)
+	if len(dependencies) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeExternalDependencyUnreachable)
+		return nil
+	}
+
+	now := time.Now()
+	keys := make([]string, len(dependencies))
+	var pending []int
+	for i := range dependencies {
+		keys[i] = getExternalDependencyProbeKey(cluster, dependencies[i])
+		if !t.cache.reachable(keys[i], now) {
+			pending = append(pending, i)
+		}
+	}
+
+	errs := make([]error, len(dependencies))
+	if len(pending) > 0 {
+		prober := t.getProber(transCtx)
+		// probe the dependencies concurrently, so the reconciliation is blocked by the slowest probe only.
+		var wg sync.WaitGroup
+		for _, i := range pending {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if errs[i] = prober.Probe(transCtx.Context, dependencies[i]); errs[i] == nil {
+					t.cache.succeed(keys[i], now)
+				}
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	var unreachable []string
+	for i, err := range errs {
+		if err != nil {
+			unreachable = append(unreachable, fmt.Sprintf("%s: %s", dependencies[i].Name, err.Error()))
+		}
+	}
+	if len(unreachable) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeExternalDependencyUnreachable)
+		return nil
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, newExternalDependencyUnreachableCondition(strings.Join(unreachable, "; ")))
+	return intctrlutil.NewDelayedRequeueError(externalDependencyProbeInterval, "waiting for the external dependencies to be reachable")
+}
+
+// getProber gets the prober in the first available pod of the cluster with lorry, and falls back to Prober if there
+// is none.
+func (t *ClusterExternalDependencyTransformer) getProber(transCtx *ClusterTransformContext) component.ExternalDependencyProber {
+	cluster := transCtx.Cluster
+	podList := &corev1.PodList{}
+	if err := transCtx.Client.List(transCtx.Context, podList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{constant.AppInstanceLabelKey: cluster.Name}); err != nil {
+		transCtx.Logger.Error(err, "failed to list the pods to probe the external dependencies from")
+		return t.Prober
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !intctrlutil.IsAvailable(pod, 0) {
+			continue
+		}
+		compDef := transCtx.ClusterDef.GetComponentDefByName(cluster.Spec.GetComponentDefRefName(pod.Labels[constant.KBAppComponentLabelKey]))
+		if compDef == nil || compDef.CharacterType == "" {
+			continue
+		}
+		lorryCli, err := newLorryClient(compDef.CharacterType, *pod)
+		if err != nil || intctrlutil.IsNil(lorryCli) {
+			continue
+		}
+		return &lorryExternalDependencyProber{cli: lorryCli, fallback: t.Prober}
+	}
+	return t.Prober
+}
+
+// lorryExternalDependencyProber probes the external dependencies by lorry in a pod of the cluster, and falls back
+// to the prober of the controller if lorry doesn't implement it yet.
+type lorryExternalDependencyProber struct {
+	cli      lorry.Client
+	fallback component.ExternalDependencyProber
+}
+
+var _ component.ExternalDependencyProber = &lorryExternalDependencyProber{}
+
+func (p *lorryExternalDependencyProber) Probe(ctx context.Context, dependency appsv1alpha1.ExternalDependency) error {
+	err := p.cli.CheckExternalDependency(ctx, dependency)
+	if errors.Is(err, lorry.ErrNotImplemented) {
+		return p.fallback.Probe(ctx, dependency)
+	}
+	return err
+}
+
+// buildExternalDependencies merges the external dependencies declared by the ClusterDefinition with the ones of the
+// cluster, which override the ones of the same names, and replaces the variables of the cluster in their addresses.
+func buildExternalDependencies(cluster *appsv1alpha1.Cluster, clusterDef *appsv1alpha1.ClusterDefinition) []appsv1alpha1.ExternalDependency {
+	overrides := map[string]appsv1alpha1.ExternalDependency{}
+	for _, dependency := range cluster.Spec.ExternalDependencies {
+		overrides[dependency.Name] = dependency
+	}
+	var dependencies []appsv1alpha1.ExternalDependency
+	for _, dependency := range clusterDef.Spec.ExternalDependencies {
+		if override, ok := overrides[dependency.Name]; ok {
+			dependency = override
+			delete(overrides, dependency.Name)
+		}
+		dependencies = append(dependencies, *dependency.DeepCopy())
+	}
+	for _, dependency := range cluster.Spec.ExternalDependencies {
+		if _, ok := overrides[dependency.Name]; ok {
+			dependencies = append(dependencies, *dependency.DeepCopy())
+		}
+	}
+
+	clusterUID := string(cluster.UID)
+	if len(clusterUID) > 8 {
+		clusterUID = clusterUID[len(clusterUID)-8:]
+	}
+	namedValuesMap := map[string]string{
+		constant.KBClusterNamePlaceHolder:        cluster.Name,
+		constant.KBNamespacePlaceHolder:          cluster.Namespace,
+		constant.KBClusterUIDPostfix8PlaceHolder: clusterUID,
+	}
+	for i := range dependencies {
+		if tcpSocket := dependencies[i].TCPSocket; tcpSocket != nil {
+			tcpSocket.Host = component.ReplaceNamedVars(namedValuesMap, tcpSocket.Host, -1, true)
+		}
+		if httpGet := dependencies[i].HTTPGet; httpGet != nil {
+			httpGet.URL = component.ReplaceNamedVars(namedValuesMap, httpGet.URL, -1, true)
+		}
+	}
+	return dependencies
+}
+
+// getExternalDependencyProbeKey gets the key of the probe of the dependency in the cache, the probes of the clusters
+// are cached separately, as they are probed from the pods of the clusters.
+func getExternalDependencyProbeKey(cluster *appsv1alpha1.Cluster, dependency appsv1alpha1.ExternalDependency) string {
+	var address string
+	switch {
+	case dependency.TCPSocket != nil:
+		address = net.JoinHostPort(dependency.TCPSocket.Host, strconv.Itoa(int(dependency.TCPSocket.Port)))
+	case dependency.HTTPGet != nil:
+		address = dependency.HTTPGet.URL
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", cluster.Namespace, cluster.Name, dependency.Name, address)
+}
+
+// externalDependencyProbeCache caches the successful probes of the external dependencies, so the reachable ones are
+// not probed on every reconciliation. The unreachable ones are not cached and probed until they are reachable.
+type externalDependencyProbeCache struct {
+	sync.Mutex
+	succeeded map[string]time.Time
+}
+
+func newExternalDependencyProbeCache() *externalDependencyProbeCache {
+	return &externalDependencyProbeCache{succeeded: map[string]time.Time{}}
+}
+
+// reachable checks whether the dependency of the key is probed successfully in the TTL.
+func (c *externalDependencyProbeCache) reachable(key string, now time.Time) bool {
+	if c == nil {
+		return false
+	}
+	c.Lock()
+	defer c.Unlock()
+	probed, ok := c.succeeded[key]
+	return ok && now.Sub(probed) < externalDependencyProbeCacheTTL
+}
+
+// succeed records the successful probe of the dependency of the key, and evicts the expired ones, e.g. of the
+// deleted clusters.
+func (c *externalDependencyProbeCache) succeed(key string, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	for k, probed := range c.succeeded {
+		if now.Sub(probed) >= externalDependencyProbeCacheTTL {
+			delete(c.succeeded, k)
+		}
+	}
+	c.succeeded[key] = now
+}
+
+// isExternalDependencyUnreachable checks whether any of the external dependencies of the cluster is unreachable.
+func isExternalDependencyUnreachable(cluster *appsv1alpha1.Cluster) bool {
+	return meta.IsStatusConditionTrue(cluster.Status.Conditions, appsv1alpha1.ConditionTypeExternalDependencyUnreachable)
+}
@@ -0,0 +1,258 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+	lorry "github.com/apecloud/kubeblocks/lorry/client"
+)
+
+type fakeExternalDependencyProber struct {
+	sync.Mutex
+	unreachable map[string]bool
+	probed      []appsv1alpha1.ExternalDependency
+}
+
+func (p *fakeExternalDependencyProber) Probe(_ context.Context, dependency appsv1alpha1.ExternalDependency) error {
+	p.Lock()
+	defer p.Unlock()
+	p.probed = append(p.probed, dependency)
+	if p.unreachable[dependency.Name] {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (p *fakeExternalDependencyProber) probedNames() []string {
+	p.Lock()
+	defer p.Unlock()
+	var names []string
+	for _, dependency := range p.probed {
+		names = append(names, dependency.Name)
+	}
+	return names
+}
+
+// fakeExternalDependencyLorryClient mocks lorry in the pods to probe the external dependencies.
+type fakeExternalDependencyLorryClient struct {
+	mockLorryClient
+	prober *fakeExternalDependencyProber
+}
+
+func (c *fakeExternalDependencyLorryClient) CheckExternalDependency(ctx context.Context, dependency appsv1alpha1.ExternalDependency) error {
+	return c.prober.Probe(ctx, dependency)
+}
+
+var _ = Describe("cluster external dependency transformer test.", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		objectStoreName    = "object-store"
+		registryName       = "registry"
+	)
+
+	var (
+		transCtx    *ClusterTransformContext
+		transformer *ClusterExternalDependencyTransformer
+		prober      *fakeExternalDependencyProber
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+		ctx := context.Background()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			SetReplicas(1).
+			GetObject()
+		clusterDef := &appsv1alpha1.ClusterDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterDefName},
+			Spec: appsv1alpha1.ClusterDefinitionSpec{
+				ComponentDefs: []appsv1alpha1.ClusterComponentDefinition{
+					{Name: mysqlCompDefName, CharacterType: "mysql"},
+				},
+				ExternalDependencies: []appsv1alpha1.ExternalDependency{
+					{
+						Name:    objectStoreName,
+						HTTPGet: &appsv1alpha1.ExternalHTTPGetProbe{URL: "http://minio.storage.svc:9000/minio/health/live"},
+					},
+					{
+						Name:      registryName,
+						TCPSocket: &appsv1alpha1.ExternalTCPSocketProbe{Host: "registry.example.com", Port: 443},
+					},
+				},
+			},
+		}
+		transCtx = &ClusterTransformContext{
+			Context:     ctx,
+			Client:      k8sClient,
+			Logger:      logf.FromContext(ctx).WithValues("transformer-external-dependency-test", testCtx.DefaultNamespace),
+			Cluster:     cluster,
+			OrigCluster: cluster.DeepCopy(),
+			ClusterDef:  clusterDef,
+		}
+		prober = &fakeExternalDependencyProber{unreachable: map[string]bool{}}
+		transformer = &ClusterExternalDependencyTransformer{Prober: prober}
+	})
+
+	Context("external dependency unreachable condition", func() {
+		It("sets the condition and requeues if any dependency is unreachable", func() {
+			prober.unreachable[objectStoreName] = true
+
+			err := transformer.Transform(transCtx, graph.NewDAG())
+			Expect(err).Should(HaveOccurred())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeExternalDependencyUnreachable)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).Should(Equal(ReasonExternalDependencyProbeFailed))
+			Expect(cond.Message).Should(ContainSubstring(objectStoreName))
+			Expect(cond.Message).ShouldNot(ContainSubstring(registryName))
+		})
+
+		It("removes the condition once all dependencies are reachable", func() {
+			prober.unreachable[registryName] = true
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			Expect(isExternalDependencyUnreachable(cluster)).Should(BeTrue())
+
+			delete(prober.unreachable, registryName)
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions,
+				appsv1alpha1.ConditionTypeExternalDependencyUnreachable)).Should(BeNil())
+		})
+
+		It("holds the cluster from being Running while a dependency is unreachable", func() {
+			prober.unreachable[objectStoreName] = true
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+
+			cluster.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{
+				mysqlCompName: {Phase: appsv1alpha1.RunningClusterCompPhase},
+			}
+			statusTransformer := &ClusterStatusTransformer{}
+			statusTransformer.reconcileClusterPhase(cluster)
+			Expect(cluster.Status.Phase).Should(Equal(appsv1alpha1.CreatingClusterPhase))
+
+			delete(prober.unreachable, objectStoreName)
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			statusTransformer.reconcileClusterPhase(cluster)
+			Expect(cluster.Status.Phase).Should(Equal(appsv1alpha1.RunningClusterPhase))
+		})
+	})
+
+	Context("external dependencies of the cluster", func() {
+		It("probes the dependencies overridden by the cluster with the variables of the cluster replaced", func() {
+			cluster.Spec.ExternalDependencies = []appsv1alpha1.ExternalDependency{
+				{
+					Name:    objectStoreName,
+					HTTPGet: &appsv1alpha1.ExternalHTTPGetProbe{URL: "http://$(KB_CLUSTER_NAME)-minio.$(KB_NAMESPACE).svc:9000/minio/health/live"},
+				},
+				{
+					Name:      "dns",
+					TCPSocket: &appsv1alpha1.ExternalTCPSocketProbe{Host: "kube-dns.kube-system.svc", Port: 53},
+				},
+			}
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(prober.probedNames()).Should(ConsistOf(objectStoreName, registryName, "dns"))
+			for _, dependency := range prober.probed {
+				if dependency.Name == objectStoreName {
+					Expect(dependency.HTTPGet.URL).Should(Equal(fmt.Sprintf("http://%s-minio.%s.svc:9000/minio/health/live",
+						cluster.Name, cluster.Namespace)))
+				}
+			}
+			By("the ClusterDefinition is kept as is")
+			Expect(transCtx.ClusterDef.Spec.ExternalDependencies[0].HTTPGet.URL).Should(ContainSubstring("minio.storage.svc"))
+		})
+
+		It("reuses the successful probes and probes the unreachable ones again", func() {
+			transformer.cache = newExternalDependencyProbeCache()
+			prober.unreachable[registryName] = true
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			Expect(prober.probedNames()).Should(ConsistOf(objectStoreName, registryName))
+
+			delete(prober.unreachable, registryName)
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(prober.probedNames()).Should(ConsistOf(objectStoreName, registryName, registryName))
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(prober.probed).Should(HaveLen(3))
+
+			By("the successful probes expire after the TTL")
+			key := getExternalDependencyProbeKey(cluster, transCtx.ClusterDef.Spec.ExternalDependencies[0])
+			Expect(transformer.cache.reachable(key, time.Now().Add(externalDependencyProbeCacheTTL))).Should(BeFalse())
+		})
+
+		It("probes the dependencies by lorry in an available pod of the cluster", func() {
+			podProber := &fakeExternalDependencyProber{unreachable: map[string]bool{objectStoreName: true}}
+			newLorryClient = func(_ string, _ corev1.Pod) (lorry.Client, error) {
+				return &fakeExternalDependencyLorryClient{prober: podProber}, nil
+			}
+			defer func() { newLorryClient = lorry.NewClient }()
+
+			By("probe from the controller while no pod is available")
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(prober.probed).Should(HaveLen(2))
+			Expect(podProber.probed).Should(BeEmpty())
+
+			By("probe from the available pod")
+			pod := testapps.NewPodFactory(testCtx.DefaultNamespace, cluster.Name+"-"+mysqlCompName+"-0").
+				AddAppInstanceLabel(cluster.Name).
+				AddAppComponentLabel(mysqlCompName).
+				AddContainer(corev1.Container{Name: testapps.DefaultMySQLContainerName, Image: testapps.ApeCloudMySQLImage}).
+				CheckedCreate(&testCtx).GetObject()
+			patch := client.MergeFrom(pod.DeepCopy())
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Patch(testCtx.Ctx, pod, patch)).Should(Succeed())
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			Expect(podProber.probedNames()).Should(ConsistOf(objectStoreName, registryName))
+			Expect(prober.probed).Should(HaveLen(2))
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions,
+				appsv1alpha1.ConditionTypeExternalDependencyUnreachable).Message).Should(ContainSubstring(objectStoreName))
+		})
+	})
+})
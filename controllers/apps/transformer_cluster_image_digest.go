@@ -0,0 +1,157 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+)
+
+const (
+	// defaultResolveDigestTimeout is the timeout of each request to the registries when resolving the digests.
+	defaultResolveDigestTimeout = 10 * time.Second
+	// resolvedDigestTTL is the duration the resolved digests are cached for.
+	resolvedDigestTTL = 10 * time.Minute
+	// resolveDigestInitialBackoff and resolveDigestMaxBackoff bound the backoff of the images failed to resolve.
+	resolveDigestInitialBackoff = 10 * time.Second
+	resolveDigestMaxBackoff     = 10 * time.Minute
+)
+
+// defaultImageDigestResolver is shared by all the reconciliations, so the registries are requested only when
+// the images are not cached or backing off.
+var defaultImageDigestResolver = component.NewCachedDigestResolver(
+	component.NewRegistryDigestResolver(defaultResolveDigestTimeout),
+	resolvedDigestTTL, resolveDigestInitialBackoff, resolveDigestMaxBackoff)
+
+// ClusterImageDigestTransformer resolves the images of the components to digests if spec.pinImagesByDigest
+// or the annotation apps.kubeblocks.io/pin-images-by-digest is enabled,
+// and records them in status.pinnedImages, which are used by the components for all subsequent renders.
+// The registries are authenticated with the imagePullSecrets of the pods of the components.
+// The images failed to resolve are rendered by tag and retried once their backoff expires,
+// and the ImageDigestUnresolved condition is set without blocking the other components.
+type ClusterImageDigestTransformer struct {
+	// Resolver resolves the image tags to digests, the shared cached registry API is used if it's nil.
+	Resolver component.ImageDigestResolver
+}
+
+var _ graph.Transformer = &ClusterImageDigestTransformer{}
+
+func (t *ClusterImageDigestTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+	if !component.IsPinImagesByDigestEnabled(cluster) {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)
+		return nil
+	}
+	resolver := t.Resolver
+	if resolver == nil {
+		resolver = defaultImageDigestResolver
+	}
+
+	var (
+		failedComps []string
+		errMsgs     []string
+	)
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef == nil {
+			continue
+		}
+		var compVer *appsv1alpha1.ClusterComponentVersion
+		if transCtx.ClusterVer != nil {
+			compVer = transCtx.ClusterVer.Spec.GetDefNameMappingComponents()[compSpec.ComponentDefRef]
+		}
+		var creds component.RegistryCredentials
+		for _, image := range component.GetComponentImages(compDef, compVer) {
+			if _, ok := cluster.Status.PinnedImages[image]; ok {
+				continue
+			}
+			if creds == nil {
+				secrets, err := t.getImagePullSecrets(transCtx, compDef)
+				if err != nil {
+					return err
+				}
+				creds = component.ParseImagePullSecrets(secrets)
+			}
+			digest, err := resolver.Resolve(transCtx.Context, image, creds)
+			if err != nil {
+				if !slices.Contains(failedComps, compSpec.Name) {
+					failedComps = append(failedComps, compSpec.Name)
+				}
+				errMsgs = append(errMsgs, err.Error())
+				continue
+			}
+			if cluster.Status.PinnedImages == nil {
+				cluster.Status.PinnedImages = map[string]string{}
+			}
+			cluster.Status.PinnedImages[image] = component.PinImage(image, digest)
+		}
+	}
+
+	if len(failedComps) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)
+		return nil
+	}
+	condition := newImageDigestUnresolvedCondition(fmt.Sprintf("failed to resolve the image digests of components %v: %s",
+		failedComps, strings.Join(errMsgs, "; ")))
+	condition.ObservedGeneration = cluster.Generation
+	origCondition := meta.FindStatusCondition(transCtx.OrigCluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)
+	if origCondition == nil || origCondition.Message != condition.Message {
+		transCtx.EventRecorder.Event(cluster, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	return nil
+}
+
+// getImagePullSecrets returns the imagePullSecrets of the pods of the component, the absent ones are ignored
+// as the kubelet does.
+func (t *ClusterImageDigestTransformer) getImagePullSecrets(transCtx *ClusterTransformContext,
+	compDef *appsv1alpha1.ClusterComponentDefinition) ([]corev1.Secret, error) {
+	if compDef.PodSpec == nil {
+		return nil, nil
+	}
+	var secrets []corev1.Secret
+	for _, ref := range compDef.PodSpec.ImagePullSecrets {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: transCtx.Cluster.Namespace, Name: ref.Name}
+		if err := transCtx.Client.Get(transCtx.Context, key, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		secrets = append(secrets, *secret)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+// stubDigestResolver resolves the images by the digests in memory, the images absent are failed to resolve,
+// and the private images require the credentials of their registries.
+type stubDigestResolver struct {
+	digests map[string]string
+	private map[string]component.RegistryAuth
+}
+
+func (r *stubDigestResolver) Resolve(_ context.Context, image string, creds component.RegistryCredentials) (string, error) {
+	digest, ok := r.digests[image]
+	if !ok {
+		return "", fmt.Errorf("manifest unknown: %s", image)
+	}
+	if required, ok := r.private[image]; ok {
+		registry, _, _ := component.ParseImage(image)
+		if auth, _ := creds.Get(registry); auth.Username != required.Username || auth.Password != required.Password {
+			return "", fmt.Errorf("unauthorized: %s", image)
+		}
+	}
+	return digest, nil
+}
+
+var _ = Describe("cluster image digest transformer test.", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		nginxCompName      = "nginx"
+		nginxCompDefName   = "nginx"
+		mysqlDigest        = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		mysqlDigestMutated = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+		nginxDigest        = "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+	)
+
+	var (
+		transCtx    *ClusterTransformContext
+		transformer *ClusterImageDigestTransformer
+		resolver    *stubDigestResolver
+		cluster     *appsv1alpha1.Cluster
+		recorder    *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		clusterDef := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.StatefulMySQLComponent, mysqlCompDefName).
+			AddComponentDef(testapps.StatelessNginxComponent, nginxCompDefName).
+			GetObject()
+		clusterVersion := testapps.NewClusterVersionFactory(clusterVersionName, clusterDefName).
+			AddComponentVersion(mysqlCompDefName).
+			AddContainerShort(testapps.DefaultMySQLContainerName, testapps.ApeCloudMySQLImage).
+			AddComponentVersion(nginxCompDefName).
+			AddContainerShort(testapps.DefaultNginxContainerName, testapps.NginxImage).
+			GetObject()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			AddComponent(nginxCompName, nginxCompDefName).
+			GetObject()
+		cluster.Spec.PinImagesByDigest = true
+		recorder = record.NewFakeRecorder(10)
+		transCtx = &ClusterTransformContext{
+			Context:       ctx,
+			Client:        k8sClient,
+			EventRecorder: recorder,
+			Logger:        logf.FromContext(ctx).WithValues("transformer-image-digest-test", testCtx.DefaultNamespace),
+			Cluster:       cluster,
+			OrigCluster:   cluster.DeepCopy(),
+			ClusterDef:    clusterDef,
+			ClusterVer:    clusterVersion,
+		}
+		resolver = &stubDigestResolver{
+			digests: map[string]string{
+				testapps.ApeCloudMySQLImage: mysqlDigest,
+				testapps.NginxImage:         nginxDigest,
+			},
+			private: map[string]component.RegistryAuth{},
+		}
+		transformer = &ClusterImageDigestTransformer{Resolver: resolver}
+	})
+
+	buildComponent := func(compName string) *component.SynthesizedComponent {
+		compSpec := cluster.Spec.GetComponentByName(compName)
+		synthesizedComp, err := component.BuildComponent(intctrlutil.RequestCtx{Ctx: transCtx.Context, Log: transCtx.Logger},
+			nil, cluster, transCtx.ClusterDef, transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef), compSpec, nil,
+			transCtx.ClusterVer.Spec.GetDefNameMappingComponents()[compSpec.ComponentDefRef])
+		Expect(err).Should(Succeed())
+		return synthesizedComp
+	}
+
+	getImage := func(comp *component.SynthesizedComponent, containerName string) string {
+		for _, c := range comp.PodSpec.Containers {
+			if c.Name == containerName {
+				return c.Image
+			}
+		}
+		return ""
+	}
+
+	Context("pin images by digest", func() {
+		It("should render the images by digest and survive the tag mutation upstream", func() {
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			pinnedMySQLImage := "docker.io/apecloud/apecloud-mysql-server@" + mysqlDigest
+			Expect(cluster.Status.PinnedImages).Should(HaveKeyWithValue(testapps.ApeCloudMySQLImage, pinnedMySQLImage))
+			Expect(cluster.Status.PinnedImages).Should(HaveKeyWithValue(testapps.NginxImage, "nginx@"+nginxDigest))
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)).Should(BeNil())
+			Expect(getImage(buildComponent(mysqlCompName), testapps.DefaultMySQLContainerName)).Should(Equal(pinnedMySQLImage))
+
+			By("mutate the tag upstream")
+			resolver.digests[testapps.ApeCloudMySQLImage] = mysqlDigestMutated
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(cluster.Status.PinnedImages).Should(HaveKeyWithValue(testapps.ApeCloudMySQLImage, pinnedMySQLImage))
+			Expect(getImage(buildComponent(mysqlCompName), testapps.DefaultMySQLContainerName)).Should(Equal(pinnedMySQLImage))
+		})
+
+		It("should set the condition without blocking the other components if failed to resolve", func() {
+			delete(resolver.digests, testapps.NginxImage)
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).Should(Equal(ReasonResolveDigestFailed))
+			Expect(cond.Message).Should(ContainSubstring(nginxCompName))
+			Expect(cond.Message).ShouldNot(ContainSubstring("[" + mysqlCompName))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(ReasonResolveDigestFailed)))
+			Expect(getImage(buildComponent(mysqlCompName), testapps.DefaultMySQLContainerName)).Should(HaveSuffix(mysqlDigest))
+			Expect(getImage(buildComponent(nginxCompName), testapps.DefaultNginxContainerName)).Should(Equal(testapps.NginxImage))
+
+			By("remove the condition once resolved")
+			resolver.digests[testapps.NginxImage] = nginxDigest
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)).Should(BeNil())
+			Expect(getImage(buildComponent(nginxCompName), testapps.DefaultNginxContainerName)).Should(Equal("nginx@" + nginxDigest))
+		})
+
+		It("should pin the images by digest if enabled by the annotation", func() {
+			cluster.Spec.PinImagesByDigest = false
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(cluster.Status.PinnedImages).Should(BeEmpty())
+			Expect(getImage(buildComponent(nginxCompName), testapps.DefaultNginxContainerName)).Should(Equal(testapps.NginxImage))
+
+			By("opt in by the annotation")
+			cluster.Annotations = map[string]string{constant.PinImagesByDigestAnnotationKey: "true"}
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(cluster.Status.PinnedImages).Should(HaveKeyWithValue(testapps.NginxImage, "nginx@"+nginxDigest))
+			for _, c := range buildComponent(nginxCompName).PodSpec.Containers {
+				if c.Name == testapps.DefaultNginxContainerName {
+					Expect(c.Image).Should(MatchRegexp(`@sha256:[0-9a-f]{64}$`))
+				}
+			}
+			Expect(getImage(buildComponent(mysqlCompName), testapps.DefaultMySQLContainerName)).Should(HaveSuffix("@" + mysqlDigest))
+		})
+
+		It("should authenticate to the registries with the image pull secrets of the pods", func() {
+			const secretName = "registry-credential"
+			auth := component.RegistryAuth{Username: "user", Password: "passwd"}
+			resolver.private[testapps.NginxImage] = auth
+
+			By("failing to resolve the private image without the credential")
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(cluster.Status.PinnedImages).ShouldNot(HaveKey(testapps.NginxImage))
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)).ShouldNot(BeNil())
+
+			By("referring to the image pull secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: secretName},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"dXNlcjpwYXNzd2Q="}}}`),
+				},
+			}
+			Expect(testCtx.CheckedCreateObj(testCtx.Ctx, secret)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(testCtx.Ctx, secret)).Should(Succeed())
+			})
+			compDef := transCtx.ClusterDef.GetComponentDefByName(nginxCompDefName)
+			compDef.PodSpec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: secretName}}
+			Eventually(func(g Gomega) {
+				g.Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+				g.Expect(cluster.Status.PinnedImages).Should(HaveKeyWithValue(testapps.NginxImage, "nginx@"+nginxDigest))
+			}).Should(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeImageDigestUnresolved)).Should(BeNil())
+		})
+
+		It("should override the image pull policy of all containers", func() {
+			cluster.Spec.PinImagesByDigest = false
+			cluster.Spec.ImagePullPolicy = corev1.PullNever
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(cluster.Status.PinnedImages).Should(BeEmpty())
+			comp := buildComponent(nginxCompName)
+			Expect(getImage(comp, testapps.DefaultNginxContainerName)).Should(Equal(testapps.NginxImage))
+			for _, c := range append(comp.PodSpec.InitContainers, comp.PodSpec.Containers...) {
+				Expect(c.ImagePullPolicy).Should(Equal(corev1.PullNever))
+			}
+		})
+	})
+})
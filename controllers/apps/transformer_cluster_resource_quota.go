@@ -0,0 +1,205 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	"github.com/apecloud/kubeblocks/internal/featuregate"
+)
+
+// quotaComputeResources are the compute resources of the containers summed into the ResourceQuota.
+var quotaComputeResources = []struct {
+	name      corev1.ResourceName
+	container corev1.ResourceName
+	limits    bool
+}{
+	{corev1.ResourceRequestsCPU, corev1.ResourceCPU, false},
+	{corev1.ResourceRequestsMemory, corev1.ResourceMemory, false},
+	{corev1.ResourceLimitsCPU, corev1.ResourceCPU, true},
+	{corev1.ResourceLimitsMemory, corev1.ResourceMemory, true},
+}
+
+// ClusterResourceQuotaTransformer reconciles a ResourceQuota sized to the components of the cluster requesting it
+// by the annotation apps.kubeblocks.io/resource-quota, so the tenants can't exceed their allocation by manual edits.
+// It's gated by the ResourceQuota feature, and it's intended for the namespaces dedicated to one cluster,
+// as all the ResourceQuotas in a namespace are enforced together.
+type ClusterResourceQuotaTransformer struct{}
+
+var _ graph.Transformer = &ClusterResourceQuotaTransformer{}
+
+func (t *ClusterResourceQuotaTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return err
+	}
+
+	var rsmList []*workloads.ReplicatedStateMachine
+	for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+		v, _ := vertex.(*ictrltypes.LifecycleVertex)
+		if v.Action != nil && *v.Action == ictrltypes.DELETE {
+			continue
+		}
+		rsm, _ := v.Obj.(*workloads.ReplicatedStateMachine)
+		rsmList = append(rsmList, rsm)
+	}
+	proto := factory.BuildResourceQuota(cluster, buildResourceQuotaHard(rsmList))
+
+	quota := &corev1.ResourceQuota{}
+	if err = transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(proto), quota); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		quota = nil
+	}
+
+	if !isResourceQuotaRequested(cluster) {
+		if quota != nil {
+			ictrltypes.LifecycleObjectDelete(dag, quota, root)
+		}
+		return nil
+	}
+
+	var quotaVertex *ictrltypes.LifecycleVertex
+	switch {
+	case quota == nil:
+		quotaVertex = ictrltypes.LifecycleObjectCreate(dag, proto, root)
+	case !isResourceListEqual(quota.Spec.Hard, proto.Spec.Hard):
+		quotaCopy := quota.DeepCopy()
+		quotaCopy.Spec.Hard = proto.Spec.Hard
+		quotaVertex = ictrltypes.LifecycleObjectUpdate(dag, quotaCopy, root)
+	default:
+		return nil
+	}
+	// the quota must be raised before the workloads scale out.
+	for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+		dag.Connect(vertex, quotaVertex)
+	}
+	return nil
+}
+
+// isResourceQuotaRequested checks whether the cluster requests a ResourceQuota and the feature is enabled.
+func isResourceQuotaRequested(cluster *appsv1alpha1.Cluster) bool {
+	return featuregate.Enabled(featuregate.ResourceQuota) &&
+		strings.EqualFold(cluster.Annotations[constant.ResourceQuotaAnnotationKey], "true")
+}
+
+// buildResourceQuotaHard sums the pods, the compute resources and the storage of the workloads.
+// A compute resource is summed only if all containers specify it, otherwise the pods without it would be rejected.
+func buildResourceQuotaHard(rsmList []*workloads.ReplicatedStateMachine) corev1.ResourceList {
+	hard := corev1.ResourceList{}
+	add := func(name corev1.ResourceName, quantity resource.Quantity, replicas int64) {
+		total := hard[name]
+		for i := int64(0); i < replicas; i++ {
+			total.Add(quantity)
+		}
+		hard[name] = total
+	}
+	unspecified := map[corev1.ResourceName]bool{}
+	for _, rsm := range rsmList {
+		replicas := int64(1)
+		if rsm.Spec.Replicas != nil {
+			replicas = int64(*rsm.Spec.Replicas)
+		}
+		add(corev1.ResourcePods, *resource.NewQuantity(1, resource.DecimalSI), replicas)
+		podSpec := rsm.Spec.Template.Spec
+		for _, r := range quotaComputeResources {
+			quantity, ok := getPodResource(podSpec, r.container, r.limits)
+			if !ok {
+				unspecified[r.name] = true
+				continue
+			}
+			add(r.name, quantity, replicas)
+		}
+		for _, vct := range rsm.Spec.VolumeClaimTemplates {
+			if storage, ok := vct.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+				add(corev1.ResourceRequestsStorage, storage, replicas)
+			}
+		}
+	}
+	for name := range unspecified {
+		delete(hard, name)
+	}
+	return hard
+}
+
+// getPodResource computes the effective resource of the pod as the scheduler does,
+// which is the larger one of the sum of the containers and the max of the init containers.
+func getPodResource(podSpec corev1.PodSpec, name corev1.ResourceName, limits bool) (resource.Quantity, bool) {
+	get := func(c corev1.Container) (resource.Quantity, bool) {
+		if limits {
+			q, ok := c.Resources.Limits[name]
+			return q, ok
+		}
+		// the requests default to the limits if not specified.
+		if q, ok := c.Resources.Requests[name]; ok {
+			return q, ok
+		}
+		q, ok := c.Resources.Limits[name]
+		return q, ok
+	}
+	sum := resource.Quantity{}
+	for _, c := range podSpec.Containers {
+		q, ok := get(c)
+		if !ok {
+			return sum, false
+		}
+		sum.Add(q)
+	}
+	for _, c := range podSpec.InitContainers {
+		q, ok := get(c)
+		if !ok {
+			return sum, false
+		}
+		if q.Cmp(sum) > 0 {
+			sum = q.DeepCopy()
+		}
+	}
+	return sum, true
+}
+
+func isResourceListEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, qa := range a {
+		qb, ok := b[name]
+		if !ok || qa.Cmp(qb) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/builder"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	"github.com/apecloud/kubeblocks/internal/featuregate"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("cluster resource quota transformer test.", func() {
+	const (
+		clusterName        = "test-cluster-quota"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		nginxCompName      = "nginx"
+		nginxCompDefName   = "nginx"
+	)
+
+	var (
+		ctx         context.Context
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+		replicas    map[string]int32
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddAnnotations(constant.ResourceQuotaAnnotationKey, "true").
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			AddComponent(nginxCompName, nginxCompDefName).
+			GetObject()
+		transCtx = &ClusterTransformContext{
+			Context: ctx,
+			Client:  k8sClient,
+			Logger:  logf.FromContext(ctx).WithValues("transformer-resource-quota-test", testCtx.DefaultNamespace),
+			Cluster: cluster,
+		}
+		transformer = &ClusterResourceQuotaTransformer{}
+		replicas = map[string]int32{mysqlCompName: 3, nginxCompName: 2}
+		Expect(featuregate.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(featuregate.ResourceQuota): true})).Should(Succeed())
+		DeferCleanup(func() {
+			Expect(featuregate.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(featuregate.ResourceQuota): false})).Should(Succeed())
+		})
+	})
+
+	resources := func(cpu, memory string) corev1.ResourceRequirements {
+		list := corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+		return corev1.ResourceRequirements{Limits: list, Requests: list}
+	}
+
+	mockDAG := func() *graph.DAG {
+		dag := graph.NewDAG()
+		root := ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		mysql := builder.NewReplicatedStateMachineBuilder(testCtx.DefaultNamespace, clusterName+"-"+mysqlCompName).
+			SetReplicas(replicas[mysqlCompName]).
+			SetTemplate(corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "init", Resources: resources("1", "1Gi")}},
+					Containers: []corev1.Container{
+						{Name: testapps.DefaultMySQLContainerName, Resources: resources("1", "2Gi")},
+						{Name: "lorry", Resources: resources("500m", "512Mi")},
+					},
+				},
+			}).
+			SetVolumeClaimTemplates(corev1.PersistentVolumeClaim{
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}).
+			GetObject()
+		nginx := builder.NewReplicatedStateMachineBuilder(testCtx.DefaultNamespace, clusterName+"-"+nginxCompName).
+			SetReplicas(replicas[nginxCompName]).
+			SetTemplate(corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: testapps.DefaultNginxContainerName, Resources: resources("250m", "256Mi")}},
+				},
+			}).
+			GetObject()
+		ictrltypes.LifecycleObjectUpdate(dag, mysql, root)
+		ictrltypes.LifecycleObjectUpdate(dag, nginx, root)
+		return dag
+	}
+
+	findQuota := func(dag *graph.DAG, action ictrltypes.LifecycleAction) *corev1.ResourceQuota {
+		for _, vertex := range ictrltypes.FindAll[*corev1.ResourceQuota](dag) {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			if *v.Action == action {
+				quota, _ := v.Obj.(*corev1.ResourceQuota)
+				return quota
+			}
+		}
+		return nil
+	}
+
+	expectHard := func(quota *corev1.ResourceQuota, name corev1.ResourceName, quantity string) {
+		q, ok := quota.Spec.Hard[name]
+		Expect(ok).Should(BeTrue())
+		Expect(q.Cmp(resource.MustParse(quantity))).Should(BeZero(), "%s: %s", name, q.String())
+	}
+
+	Context("resource quota", func() {
+		It("should create the quota with the summed limits of components and update it when components scale", func() {
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			quota := findQuota(dag, ictrltypes.CREATE)
+			Expect(quota).ShouldNot(BeNil())
+			Expect(quota.Labels).Should(HaveKeyWithValue(constant.AppInstanceLabelKey, clusterName))
+			// mysql: 3 * (1.5 cpu, 2.5Gi), nginx: 2 * (250m, 256Mi)
+			expectHard(quota, corev1.ResourceLimitsCPU, "5")
+			expectHard(quota, corev1.ResourceLimitsMemory, "8Gi")
+			expectHard(quota, corev1.ResourceRequestsCPU, "5")
+			expectHard(quota, corev1.ResourceRequestsMemory, "8Gi")
+			expectHard(quota, corev1.ResourceRequestsStorage, "30Gi")
+			expectHard(quota, corev1.ResourcePods, "5")
+			Expect(k8sClient.Create(ctx, quota)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, quota)).Should(Succeed())
+			})
+
+			By("no update if the components are unchanged")
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(ictrltypes.FindAll[*corev1.ResourceQuota](dag)).Should(BeEmpty())
+
+			By("scale out the mysql component")
+			replicas[mysqlCompName] = 5
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			quota = findQuota(dag, ictrltypes.UPDATE)
+			Expect(quota).ShouldNot(BeNil())
+			expectHard(quota, corev1.ResourceLimitsCPU, "8")
+			expectHard(quota, corev1.ResourceLimitsMemory, "13Gi")
+			expectHard(quota, corev1.ResourceRequestsStorage, "50Gi")
+			expectHard(quota, corev1.ResourcePods, "7")
+
+			By("revert the manual edits")
+			replicas[mysqlCompName] = 3
+			quota.Spec.Hard[corev1.ResourceLimitsCPU] = resource.MustParse("100")
+			Expect(k8sClient.Update(ctx, quota)).Should(Succeed())
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			quota = findQuota(dag, ictrltypes.UPDATE)
+			Expect(quota).ShouldNot(BeNil())
+			expectHard(quota, corev1.ResourceLimitsCPU, "5")
+
+			By("delete the quota once the feature is disabled")
+			Expect(featuregate.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(featuregate.ResourceQuota): false})).Should(Succeed())
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(findQuota(dag, ictrltypes.DELETE)).ShouldNot(BeNil())
+		})
+
+		It("should not create the quota if the cluster doesn't request it", func() {
+			cluster.Annotations = nil
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(ictrltypes.FindAll[*corev1.ResourceQuota](dag)).Should(BeEmpty())
+		})
+
+		It("should omit the compute resources not specified by all containers", func() {
+			dag := mockDAG()
+			for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+				rsm, _ := vertex.(*ictrltypes.LifecycleVertex).Obj.(*workloads.ReplicatedStateMachine)
+				if rsm.Name == clusterName+"-"+nginxCompName {
+					rsm.Spec.Template.Spec.Containers[0].Resources.Limits = nil
+				}
+			}
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			quota := findQuota(dag, ictrltypes.CREATE)
+			Expect(quota).ShouldNot(BeNil())
+			Expect(quota.Spec.Hard).ShouldNot(HaveKey(corev1.ResourceLimitsCPU))
+			Expect(quota.Spec.Hard).ShouldNot(HaveKey(corev1.ResourceLimitsMemory))
+			expectHard(quota, corev1.ResourceRequestsCPU, "5")
+		})
+	})
+})
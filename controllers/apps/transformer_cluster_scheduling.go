@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+// defaultSchedulingBlockedThreshold is the default duration that pods stay unschedulable before the scheduling is considered as blocked.
+const defaultSchedulingBlockedThreshold = time.Minute
+
+// ClusterSchedulingTransformer detects the pods which stay unschedulable due to the affinity constraints,
+// sets the SchedulingBlocked condition and emits a warning event to identify the constraint.
+type ClusterSchedulingTransformer struct {
+	// Threshold is the duration that pods stay unschedulable before the scheduling is considered as blocked,
+	// defaultSchedulingBlockedThreshold is used if it's zero.
+	Threshold time.Duration
+}
+
+var _ graph.Transformer = &ClusterSchedulingTransformer{}
+
+func (t *ClusterSchedulingTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+	threshold := t.Threshold
+	if threshold == 0 {
+		threshold = defaultSchedulingBlockedThreshold
+	}
+
+	podList := &corev1.PodList{}
+	if err := transCtx.Client.List(transCtx.Context, podList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			constant.AppManagedByLabelKey: constant.AppName,
+			constant.AppInstanceLabelKey:  cluster.Name,
+		}); err != nil {
+		return err
+	}
+
+	var (
+		blockedPods  []string
+		constraints  []string
+		message      string
+		requeueAfter time.Duration
+	)
+	for _, pod := range podList.Items {
+		cond := intctrlutil.GetPodCondition(&pod.Status, corev1.PodScheduled)
+		if cond == nil || cond.Status != corev1.ConditionFalse || cond.Reason != corev1.PodReasonUnschedulable {
+			continue
+		}
+		constraint := getUnsatisfiableAffinity(cond.Message)
+		if len(constraint) == 0 {
+			continue
+		}
+		if elapsed := time.Since(cond.LastTransitionTime.Time); elapsed < threshold {
+			if requeueAfter == 0 || threshold-elapsed < requeueAfter {
+				requeueAfter = threshold - elapsed
+			}
+			continue
+		}
+		blockedPods = append(blockedPods, fmt.Sprintf("%s(%s)", pod.Name, pod.Labels[constant.KBAppComponentLabelKey]))
+		if !slices.Contains(constraints, constraint) {
+			constraints = append(constraints, constraint)
+		}
+		if len(message) == 0 {
+			message = cond.Message
+		}
+	}
+
+	if len(blockedPods) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeSchedulingBlocked)
+	} else {
+		slices.Sort(blockedPods)
+		slices.Sort(constraints)
+		condition := newSchedulingBlockedCondition(fmt.Sprintf("pods %v are unschedulable for more than %s due to the %s constraints: %s",
+			blockedPods, threshold, strings.Join(constraints, ", "), message))
+		condition.ObservedGeneration = cluster.Generation
+		origCondition := meta.FindStatusCondition(transCtx.OrigCluster.Status.Conditions, appsv1alpha1.ConditionTypeSchedulingBlocked)
+		if origCondition == nil || origCondition.Message != condition.Message {
+			transCtx.EventRecorder.Event(cluster, corev1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	}
+
+	// check the pods again once they have stayed unschedulable for the threshold.
+	if requeueAfter > 0 {
+		return intctrlutil.NewDelayedRequeueError(requeueAfter, "waiting for the unschedulable pods")
+	}
+	return nil
+}
+
+// getUnsatisfiableAffinity gets the affinity constraint which makes the pod unschedulable from the scheduling message,
+// e.g. "0/3 nodes are available: 3 node(s) didn't match pod anti-affinity rules."
+func getUnsatisfiableAffinity(message string) string {
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "anti-affinity"):
+		return "pod anti-affinity"
+	case strings.Contains(message, "pod affinity"):
+		return "pod affinity"
+	case strings.Contains(message, "node affinity"):
+		return "node affinity"
+	default:
+		return ""
+	}
+}
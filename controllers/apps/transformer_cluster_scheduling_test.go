@@ -0,0 +1,149 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("cluster scheduling transformer test.", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		antiAffinityMsg    = "0/3 nodes are available: 3 node(s) didn't match pod anti-affinity rules."
+	)
+
+	var (
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+		recorder    *record.FakeRecorder
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+		ctx := context.Background()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			SetReplicas(3).
+			GetObject()
+		recorder = record.NewFakeRecorder(10)
+		transCtx = &ClusterTransformContext{
+			Context:       ctx,
+			Client:        k8sClient,
+			EventRecorder: recorder,
+			Logger:        logf.FromContext(ctx).WithValues("transformer-scheduling-test", testCtx.DefaultNamespace),
+			Cluster:       cluster,
+			OrigCluster:   cluster.DeepCopy(),
+		}
+		transformer = &ClusterSchedulingTransformer{Threshold: time.Minute}
+	})
+
+	AfterEach(cleanEnv)
+
+	mockUnschedulablePod := func(name, message string, since time.Time) {
+		pod := testapps.NewPodFactory(testCtx.DefaultNamespace, cluster.Name+"-"+mysqlCompName+"-"+name).
+			AddAppInstanceLabel(cluster.Name).
+			AddAppComponentLabel(mysqlCompName).
+			AddAppManagedByLabel().
+			AddContainer(corev1.Container{Name: testapps.DefaultMySQLContainerName, Image: testapps.ApeCloudMySQLImage}).
+			Create(&testCtx).
+			GetObject()
+		Expect(testapps.ChangeObjStatus(&testCtx, pod, func() {
+			pod.Status.Conditions = []corev1.PodCondition{
+				{
+					Type:               corev1.PodScheduled,
+					Status:             corev1.ConditionFalse,
+					Reason:             corev1.PodReasonUnschedulable,
+					Message:            message,
+					LastTransitionTime: metav1.NewTime(since),
+				},
+			}
+		})).Should(Succeed())
+	}
+
+	Context("scheduling blocked condition", func() {
+		It("should wait for the pods unschedulable within the threshold", func() {
+			mockUnschedulablePod("0", antiAffinityMsg, time.Now())
+			err := transformer.Transform(transCtx, graph.NewDAG())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeSchedulingBlocked)).Should(BeNil())
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+
+		It("should ignore the pods unschedulable due to other reasons", func() {
+			mockUnschedulablePod("0", "0/3 nodes are available: 3 Insufficient cpu.", time.Now().Add(-time.Hour))
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeSchedulingBlocked)).Should(BeNil())
+		})
+
+		It("should set the condition and emit the event once pods stay unschedulable due to affinity", func() {
+			mockUnschedulablePod("0", antiAffinityMsg, time.Now().Add(-time.Hour))
+			mockUnschedulablePod("1", antiAffinityMsg, time.Now().Add(-time.Hour))
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeSchedulingBlocked)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).Should(Equal(ReasonAffinityUnsatisfiable))
+			Expect(cond.Message).Should(ContainSubstring("pod anti-affinity"))
+			Expect(cond.Message).Should(ContainSubstring(cluster.Name + "-" + mysqlCompName + "-1"))
+			Expect(recorder.Events).Should(Receive(And(ContainSubstring(corev1.EventTypeWarning), ContainSubstring(ReasonAffinityUnsatisfiable))))
+
+			By("no duplicated event for the unchanged condition")
+			transCtx.OrigCluster = cluster.DeepCopy()
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(recorder.Events).Should(BeEmpty())
+
+			By("remove the condition once the pods are scheduled")
+			cleanEnv()
+			Eventually(func(g Gomega) {
+				g.Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+				g.Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeSchedulingBlocked)).Should(BeNil())
+			}).Should(Succeed())
+		})
+	})
+})
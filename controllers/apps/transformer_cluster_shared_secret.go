@@ -0,0 +1,94 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+)
+
+// ClusterSharedSecretTransformer generates the cluster-level shared secret on the first reconciliation, which is
+// mounted into the components declaring it in the cluster definition by the component builder.
+// The shared secret is never regenerated once it's created.
+type ClusterSharedSecretTransformer struct{}
+
+var _ graph.Transformer = &ClusterSharedSecretTransformer{}
+
+func (t *ClusterSharedSecretTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	compNames := make(map[string]bool)
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef != nil && compDef.SharedSecret != nil {
+			compNames[compSpec.Name] = true
+		}
+	}
+	if len(compNames) == 0 {
+		return nil
+	}
+
+	secretVertex, err := t.createSharedSecretIfNotExist(transCtx, dag)
+	if err != nil || secretVertex == nil {
+		return err
+	}
+	// the pods mounting the shared secret can't start without it, create the workloads after the secret.
+	for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+		rsm, _ := vertex.(*ictrltypes.LifecycleVertex).Obj.(*workloads.ReplicatedStateMachine)
+		if compNames[rsm.Labels[constant.KBAppComponentLabelKey]] {
+			dag.Connect(vertex, secretVertex)
+		}
+	}
+	return nil
+}
+
+// createSharedSecretIfNotExist puts the shared secret into the DAG only if it doesn't exist,
+// so the token is generated only once.
+func (t *ClusterSharedSecretTransformer) createSharedSecretIfNotExist(transCtx *ClusterTransformContext,
+	dag *graph.DAG) (*ictrltypes.LifecycleVertex, error) {
+	cluster := transCtx.Cluster
+	secretKey := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      component.GenerateSharedSecretName(cluster.Name),
+	}
+	if err := transCtx.Client.Get(transCtx.Context, secretKey, &corev1.Secret{}); err == nil {
+		return nil, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return nil, err
+	}
+	return ictrltypes.LifecycleObjectCreate(dag, factory.BuildSharedSecret(transCtx.ClusterDef, cluster), root), nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("cluster shared secret transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		proxyCompName      = "proxy"
+		proxyCompDefName   = "proxy"
+		nginxCompName      = "nginx"
+		nginxCompDefName   = "nginx"
+		mountPath          = "/etc/shared-secret"
+	)
+
+	var (
+		ctx         context.Context
+		transCtx    graph.TransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterDef := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, mysqlCompDefName).
+			SetSharedSecret(&appsv1alpha1.SharedSecretMount{MountPath: mountPath}).
+			AddComponentDef(testapps.StatelessNginxComponent, proxyCompDefName).
+			SetSharedSecret(&appsv1alpha1.SharedSecretMount{MountPath: mountPath, Containers: []string{"sidecar"}}).
+			AddComponentDef(testapps.StatelessNginxComponent, nginxCompDefName).
+			GetObject()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			AddComponent(proxyCompName, proxyCompDefName).
+			AddComponent(nginxCompName, nginxCompDefName).
+			GetObject()
+		transCtx = &ClusterTransformContext{
+			Context:    ctx,
+			Client:     k8sClient,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-shared-secret-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+		transformer = &ClusterSharedSecretTransformer{}
+	})
+
+	mockDAG := func() (*graph.DAG, map[string]*workloads.ReplicatedStateMachine) {
+		dag := graph.NewDAG()
+		root := ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		rsmList := make(map[string]*workloads.ReplicatedStateMachine)
+		for _, compName := range []string{mysqlCompName, proxyCompName, nginxCompName} {
+			rsm := &workloads.ReplicatedStateMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testCtx.DefaultNamespace,
+					Name:      clusterName + "-" + compName,
+					Labels:    map[string]string{constant.KBAppComponentLabelKey: compName},
+				},
+				Spec: workloads.ReplicatedStateMachineSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: compName}, {Name: "sidecar"}},
+						},
+					},
+				},
+			}
+			ictrltypes.LifecycleObjectCreate(dag, rsm, root)
+			rsmList[compName] = rsm
+		}
+		return dag, rsmList
+	}
+
+	findSharedSecrets := func(dag *graph.DAG) []*corev1.Secret {
+		var secrets []*corev1.Secret
+		for _, vertex := range ictrltypes.FindAll[*corev1.Secret](dag) {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			secret, _ := v.Obj.(*corev1.Secret)
+			if secret.Name == component.GenerateSharedSecretName(clusterName) {
+				secrets = append(secrets, secret)
+			}
+		}
+		return secrets
+	}
+
+	walkOrder := func(dag *graph.DAG) []string {
+		var names []string
+		walkFunc := func(v graph.Vertex) error {
+			vertex, _ := v.(*ictrltypes.LifecycleVertex)
+			switch vertex.Obj.(type) {
+			case *workloads.ReplicatedStateMachine, *corev1.Secret:
+				names = append(names, vertex.Obj.GetName())
+			}
+			return nil
+		}
+		less := func(v1, v2 graph.Vertex) bool {
+			o1, _ := v1.(*ictrltypes.LifecycleVertex)
+			o2, _ := v2.(*ictrltypes.LifecycleVertex)
+			return o1.Obj.GetName() < o2.Obj.GetName()
+		}
+		Expect(dag.WalkReverseTopoOrder(walkFunc, less)).Should(Succeed())
+		return names
+	}
+
+	Context("cluster shared secret", func() {
+		It("should generate the shared secret on the first reconciliation", func() {
+			dag, _ := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			secrets := findSharedSecrets(dag)
+			Expect(secrets).Should(HaveLen(1))
+			Expect(secrets[0].Namespace).Should(Equal(testCtx.DefaultNamespace))
+			Expect(secrets[0].StringData[constant.SharedSecretTokenKey]).ShouldNot(BeEmpty())
+		})
+
+		It("should create the workloads of the components declaring it after the shared secret", func() {
+			dag, _ := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+
+			order := walkOrder(dag)
+			Expect(order).Should(HaveLen(4))
+			indexOf := func(name string) int {
+				return slices.Index(order, name)
+			}
+			secretIndex := indexOf(component.GenerateSharedSecretName(clusterName))
+			Expect(secretIndex).Should(BeNumerically("<", indexOf(clusterName+"-"+mysqlCompName)))
+			Expect(secretIndex).Should(BeNumerically("<", indexOf(clusterName+"-"+proxyCompName)))
+		})
+
+		It("should not regenerate the shared secret once it's created", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testCtx.DefaultNamespace,
+					Name:      component.GenerateSharedSecretName(clusterName),
+				},
+				StringData: map[string]string{constant.SharedSecretTokenKey: "existing-token"},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, secret)).Should(Succeed())
+			})
+
+			dag, _ := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(findSharedSecrets(dag)).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	cfgutil "github.com/apecloud/kubeblocks/internal/configuration/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+)
+
+// ClusterSpecHistoryTransformer records the spec changes of the cluster in the spec history Secret.
+// It compares the hash of the incoming spec to the one of the stored snapshot, and appends a field-level
+// diff summary of the change to the history, only the latest @Limit entries are kept.
+// The snapshot is the whole spec which may carry sensitive values, so it's stored in a Secret.
+type ClusterSpecHistoryTransformer struct {
+	Limit int
+}
+
+var _ graph.Transformer = &ClusterSpecHistoryTransformer{}
+
+func (t *ClusterSpecHistoryTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	snapshot, err := json.Marshal(cluster.Spec)
+	if err != nil {
+		return err
+	}
+	snapshotHash, err := cfgutil.ComputeHash(cluster.Spec)
+	if err != nil {
+		return err
+	}
+
+	historyKey := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      component.GenerateSpecHistoryName(cluster.Name),
+	}
+	secret := &corev1.Secret{}
+	found := true
+	if err = transCtx.Client.Get(transCtx.Context, historyKey, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		found = false
+	}
+
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		// the history recorded in the ConfigMap by the former versions is migrated to the Secret.
+		legacyData, err := t.migrateLegacyHistory(transCtx, dag, root)
+		if err != nil {
+			return err
+		}
+		// no history for the creation, take the snapshot only.
+		history, err := factory.BuildSpecHistory(transCtx.ClusterDef, cluster, string(snapshot), snapshotHash, nil)
+		if err != nil {
+			return err
+		}
+		if legacyData == nil {
+			ictrltypes.LifecycleObjectCreate(dag, history, root)
+			return nil
+		}
+		secret = history
+		delete(secret.Annotations, constant.SpecSnapshotHashAnnotationKey)
+		secret.Data = legacyData
+	}
+
+	if secret.Annotations[constant.SpecSnapshotHashAnnotationKey] == snapshotHash {
+		return nil
+	}
+
+	secretCopy := secret.DeepCopy()
+	history, err := t.appendHistory(cluster, secret)
+	if err != nil {
+		return err
+	}
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[constant.SpecSnapshotHashAnnotationKey] = snapshotHash
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[constant.SpecSnapshotKey] = snapshot
+	secret.Data[constant.SpecHistoryKey] = historyJSON
+	if !found {
+		ictrltypes.LifecycleObjectCreate(dag, secret, root)
+		return nil
+	}
+	// secrets are immutable in the DAG, patch it instead.
+	ictrltypes.LifecycleObjectPatch(dag, secret, secretCopy, root)
+	return nil
+}
+
+// migrateLegacyHistory deletes the spec history ConfigMap recorded by the former versions, and returns its data.
+func (t *ClusterSpecHistoryTransformer) migrateLegacyHistory(transCtx *ClusterTransformContext,
+	dag *graph.DAG, root *ictrltypes.LifecycleVertex) (map[string][]byte, error) {
+	cluster := transCtx.Cluster
+	cm := &corev1.ConfigMap{}
+	cmKey := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      component.GenerateSpecHistoryName(cluster.Name),
+	}
+	if err := transCtx.Client.Get(transCtx.Context, cmKey, cm); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	ictrltypes.LifecycleObjectDelete(dag, cm, root)
+	data := make(map[string][]byte, len(cm.Data))
+	for key, value := range cm.Data {
+		data[key] = []byte(value)
+	}
+	return data, nil
+}
+
+// appendHistory appends the diff between the stored snapshot and the current spec to the history.
+func (t *ClusterSpecHistoryTransformer) appendHistory(cluster *appsv1alpha1.Cluster,
+	secret *corev1.Secret) ([]component.SpecHistoryEntry, error) {
+	var history []component.SpecHistoryEntry
+	if data, ok := secret.Data[constant.SpecHistoryKey]; ok && len(data) > 0 {
+		if err := json.Unmarshal(data, &history); err != nil {
+			return nil, err
+		}
+	}
+	lastSpec := &appsv1alpha1.ClusterSpec{}
+	if data, ok := secret.Data[constant.SpecSnapshotKey]; ok && len(data) > 0 {
+		if err := json.Unmarshal(data, lastSpec); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := component.DiffClusterSpec(lastSpec, &cluster.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return history, nil
+	}
+	entry := component.SpecHistoryEntry{
+		Timestamp:  metav1.Now(),
+		Generation: cluster.Generation,
+		Changes:    changes,
+	}
+	limit := t.Limit
+	if limit <= 0 {
+		limit = component.DefaultSpecHistoryLimit
+	}
+	return component.AppendSpecHistory(history, entry, limit), nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("cluster spec history transformer test.", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+	)
+
+	var (
+		ctx         context.Context
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterDef := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, mysqlCompDefName).
+			GetObject()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			SetReplicas(3).
+			GetObject()
+		cluster.Generation = 1
+		transCtx = &ClusterTransformContext{
+			Context:    ctx,
+			Client:     k8sClient,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-spec-history-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+		transformer = &ClusterSpecHistoryTransformer{Limit: 2}
+	})
+
+	// reconcile runs the transformer and applies the spec history Secret.
+	reconcile := func() *corev1.Secret {
+		dag := graph.NewDAG()
+		ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+		for _, vertex := range ictrltypes.FindAll[*corev1.ConfigMap](dag) {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			Expect(*v.Action).Should(Equal(ictrltypes.DELETE))
+			Expect(k8sClient.Delete(ctx, v.Obj)).Should(Succeed())
+		}
+		vertices := ictrltypes.FindAll[*corev1.Secret](dag)
+		if len(vertices) == 0 {
+			return nil
+		}
+		Expect(vertices).Should(HaveLen(1))
+		v, _ := vertices[0].(*ictrltypes.LifecycleVertex)
+		secret, _ := v.Obj.(*corev1.Secret)
+		switch *v.Action {
+		case ictrltypes.CREATE:
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).Should(Succeed())
+			})
+		case ictrltypes.PATCH:
+			Expect(k8sClient.Patch(ctx, secret, client.MergeFrom(v.ObjCopy))).Should(Succeed())
+		}
+		// wait for the cache to be synced
+		Eventually(func(g Gomega) {
+			obj := &corev1.Secret{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(secret), obj)).Should(Succeed())
+			g.Expect(obj.Annotations[constant.SpecSnapshotHashAnnotationKey]).Should(Equal(secret.Annotations[constant.SpecSnapshotHashAnnotationKey]))
+		}).Should(Succeed())
+		return secret
+	}
+
+	getHistory := func(secret *corev1.Secret) []component.SpecHistoryEntry {
+		var history []component.SpecHistoryEntry
+		Expect(json.Unmarshal(secret.Data[constant.SpecHistoryKey], &history)).Should(Succeed())
+		return history
+	}
+
+	editCluster := func(f func(spec *appsv1alpha1.ClusterSpec)) {
+		f(&cluster.Spec)
+		cluster.Generation++
+	}
+
+	Context("cluster spec history", func() {
+		It("should record the spec changes", func() {
+			By("take the snapshot only on creation")
+			secret := reconcile()
+			Expect(secret).ShouldNot(BeNil())
+			Expect(secret.Name).Should(Equal(component.GenerateSpecHistoryName(cluster.Name)))
+			Expect(getHistory(secret)).Should(BeEmpty())
+
+			By("no history if the spec isn't changed")
+			Expect(reconcile()).Should(BeNil())
+
+			By("the first edit")
+			editCluster(func(spec *appsv1alpha1.ClusterSpec) {
+				spec.ComponentSpecs[0].Replicas = 5
+			})
+			history := getHistory(reconcile())
+			Expect(history).Should(HaveLen(1))
+			Expect(history[0].Generation).Should(BeEquivalentTo(2))
+			Expect(history[0].Changes).Should(Equal([]string{"componentSpecs[mysql].replicas: 3 -> 5"}))
+
+			By("the second edit")
+			editCluster(func(spec *appsv1alpha1.ClusterSpec) {
+				spec.TerminationPolicy = appsv1alpha1.Delete
+			})
+			history = getHistory(reconcile())
+			Expect(history).Should(HaveLen(2))
+			Expect(history[1].Generation).Should(BeEquivalentTo(3))
+			Expect(history[1].Changes).Should(Equal([]string{"terminationPolicy: WipeOut -> Delete"}))
+
+			By("the third edit prunes the oldest entry")
+			editCluster(func(spec *appsv1alpha1.ClusterSpec) {
+				spec.ComponentSpecs[0].Replicas = 1
+			})
+			history = getHistory(reconcile())
+			Expect(history).Should(HaveLen(2))
+			Expect(history[0].Generation).Should(BeEquivalentTo(3))
+			Expect(history[1].Generation).Should(BeEquivalentTo(4))
+			Expect(history[1].Changes).Should(Equal([]string{"componentSpecs[mysql].replicas: 5 -> 1"}))
+		})
+
+		It("should migrate the history recorded in the ConfigMap", func() {
+			oldSpec := cluster.Spec.DeepCopy()
+			oldSpec.ComponentSpecs[0].Replicas = 1
+			snapshot, err := json.Marshal(oldSpec)
+			Expect(err).Should(Succeed())
+			history, err := json.Marshal([]component.SpecHistoryEntry{{Generation: 1, Changes: []string{"terminationPolicy: Delete -> WipeOut"}}})
+			Expect(err).Should(Succeed())
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: cluster.Namespace,
+					Name:      component.GenerateSpecHistoryName(cluster.Name),
+				},
+				Data: map[string]string{
+					constant.SpecSnapshotKey: string(snapshot),
+					constant.SpecHistoryKey:  string(history),
+				},
+			}
+			Expect(k8sClient.Create(ctx, cm)).Should(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})).Should(Succeed())
+			}).Should(Succeed())
+
+			By("the legacy ConfigMap is deleted, and its history is carried over to the Secret")
+			cluster.Generation = 2
+			migrated := getHistory(reconcile())
+			Expect(migrated).Should(HaveLen(2))
+			Expect(migrated[0].Changes).Should(Equal([]string{"terminationPolicy: Delete -> WipeOut"}))
+			Expect(migrated[1].Generation).Should(BeEquivalentTo(2))
+			Expect(migrated[1].Changes).Should(Equal([]string{"componentSpecs[mysql].replicas: 1 -> 3"}))
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+				g.Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+			}).Should(Succeed())
+		})
+	})
+})
@@ -22,7 +22,9 @@ package apps
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
@@ -49,16 +51,17 @@ func (t *ClusterStatusTransformer) Transform(ctx graph.TransformContext, dag *gr
 
 	updateObservedGeneration := func() {
 		cluster.Status.ObservedGeneration = cluster.Generation
-		cluster.Status.ClusterDefGeneration = transCtx.ClusterDef.Generation
 	}
 
 	switch {
 	case origCluster.IsUpdating():
 		transCtx.Logger.Info(fmt.Sprintf("update cluster status after applying resources, generation: %d", cluster.Generation))
 		updateObservedGeneration()
+		t.syncClusterDefinitionUpdate(transCtx, cluster)
 		rootVertex.Action = ictrltypes.ActionStatusPtr()
 	case origCluster.IsStatusUpdating():
 		defer func() { rootVertex.Action = ictrltypes.ActionPtr(ictrltypes.STATUS) }()
+		t.syncClusterDefinitionUpdate(transCtx, cluster)
 		// reconcile the phase and conditions of the Cluster.status
 		if err := t.reconcileClusterStatus(cluster); err != nil {
 			return err
@@ -89,6 +92,14 @@ func (t *ClusterStatusTransformer) reconcileClusterPhase(cluster *appsv1alpha1.C
 		}
 		return false
 	}
+	// the components throttled by the creation concurrency are not created yet, take them as creating.
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		if _, ok := cluster.Status.Components[compSpec.Name]; !ok {
+			isAllComponentRunning = false
+			isAllComponentStopped = false
+			isAllComponentFailed = false
+		}
+	}
 	for _, status := range cluster.Status.Components {
 		phase := status.Phase
 		if !isPhaseIn(phase, appsv1alpha1.CreatingClusterCompPhase) {
@@ -114,6 +125,14 @@ func (t *ClusterStatusTransformer) reconcileClusterPhase(cluster *appsv1alpha1.C
 	}
 
 	switch {
+	case isAllComponentRunning && isExternalDependencyUnreachable(cluster):
+		// the cluster is not reported as Running until its external dependencies are reachable.
+		switch cluster.Status.Phase {
+		case appsv1alpha1.RunningClusterPhase:
+			cluster.Status.Phase = appsv1alpha1.AbnormalClusterPhase
+		case "":
+			cluster.Status.Phase = appsv1alpha1.CreatingClusterPhase
+		}
 	case isAllComponentRunning:
 		if cluster.Status.Phase != appsv1alpha1.RunningClusterPhase {
 			t.syncClusterPhaseToRunning(cluster)
@@ -137,6 +156,9 @@ func (t *ClusterStatusTransformer) reconcileClusterPhase(cluster *appsv1alpha1.C
 
 // reconcileClusterStatus reconciles phase and conditions of the Cluster.status.
 func (t *ClusterStatusTransformer) reconcileClusterStatus(cluster *appsv1alpha1.Cluster) error {
+	// handle the reduced observability condition.
+	t.syncReducedObservabilityCondition(cluster)
+
 	if len(cluster.Status.Components) == 0 {
 		return nil
 	}
@@ -204,10 +226,44 @@ func (t *ClusterStatusTransformer) syncReadyConditionForCluster(cluster *appsv1a
 	}
 }
 
+// syncReducedObservabilityCondition syncs the cluster conditions with ReducedObservability type.
+func (t *ClusterStatusTransformer) syncReducedObservabilityCondition(cluster *appsv1alpha1.Cluster) {
+	if cluster.Spec.LightweightMode {
+		meta.SetStatusCondition(&cluster.Status.Conditions, newReducedObservabilityCondition())
+	} else {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeReducedObservability)
+	}
+}
+
+// syncClusterDefinitionUpdate records the generation of the ClusterDefinition the cluster is rendered against,
+// and syncs the cluster conditions with ClusterDefinitionUpdatePending type if the changes of the ClusterDefinition
+// wait for the cluster to adopt them.
+func (t *ClusterStatusTransformer) syncClusterDefinitionUpdate(transCtx *ClusterTransformContext, cluster *appsv1alpha1.Cluster) {
+	clusterDef := transCtx.ClusterDef
+	if !cluster.IsClusterDefinitionUpdatePending(clusterDef) {
+		cluster.Status.ClusterDefGeneration = clusterDef.Generation
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeClusterDefinitionUpdatePending)
+		return
+	}
+	condition := newClusterDefinitionUpdatePendingCondition(clusterDef, cluster.Status.ClusterDefGeneration)
+	if oldCondition := meta.FindStatusCondition(cluster.Status.Conditions, condition.Type); oldCondition == nil || oldCondition.Message != condition.Message {
+		transCtx.EventRecorder.Event(cluster, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
 // syncClusterPhaseToRunning syncs the cluster phase to Running.
 func (t *ClusterStatusTransformer) syncClusterPhaseToRunning(cluster *appsv1alpha1.Cluster) {
 	cluster.Status.Phase = appsv1alpha1.RunningClusterPhase
-	meta.SetStatusCondition(&cluster.Status.Conditions, newClusterReadyCondition(cluster.Name))
+	// the components are Running once their pods have been ready for minReadySeconds, the latest one is
+	// the time since when the cluster is stable.
+	var stableSince *metav1.Time
+	for _, status := range cluster.Status.Components {
+		if status.PodsReadyTime != nil && (stableSince == nil || stableSince.Before(status.PodsReadyTime)) {
+			stableSince = status.PodsReadyTime
+		}
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, newClusterReadyCondition(cluster.Name, stableSince))
 }
 
 // syncClusterPhaseToStopped syncs the cluster phase to Stopped.
@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+func TestSyncClusterDefinitionUpdate(t *testing.T) {
+	newTransCtx := func(updatePolicy appsv1alpha1.ClusterDefinitionUpdatePolicy) (*ClusterTransformContext, *record.FakeRecorder) {
+		recorder := record.NewFakeRecorder(10)
+		return &ClusterTransformContext{
+			EventRecorder: recorder,
+			ClusterDef: &appsv1alpha1.ClusterDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-clusterdef", Generation: 2},
+				Spec:       appsv1alpha1.ClusterDefinitionSpec{UpdatePolicy: updatePolicy},
+			},
+			Cluster: &appsv1alpha1.Cluster{
+				Status: appsv1alpha1.ClusterStatus{ClusterDefGeneration: 1},
+			},
+		}, recorder
+	}
+	transformer := &ClusterStatusTransformer{}
+
+	// the changes propagate to the cluster on its next reconciliation.
+	transCtx, recorder := newTransCtx(appsv1alpha1.AutomaticClusterDefUpdatePolicy)
+	cluster := transCtx.Cluster
+	transformer.syncClusterDefinitionUpdate(transCtx, cluster)
+	if cluster.Status.ClusterDefGeneration != 2 {
+		t.Errorf("expect the cluster rendered against generation 2, but got %d", cluster.Status.ClusterDefGeneration)
+	}
+	if meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeClusterDefinitionUpdatePending) != nil {
+		t.Errorf("expect no pending ClusterDefinition update condition")
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("expect no event, but got %d", len(recorder.Events))
+	}
+
+	// the changes wait for the cluster to adopt them.
+	transCtx, recorder = newTransCtx(appsv1alpha1.ManualClusterDefUpdatePolicy)
+	cluster = transCtx.Cluster
+	for i := 0; i < 2; i++ {
+		transformer.syncClusterDefinitionUpdate(transCtx, cluster)
+	}
+	if cluster.Status.ClusterDefGeneration != 1 {
+		t.Errorf("expect the cluster rendered against generation 1, but got %d", cluster.Status.ClusterDefGeneration)
+	}
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, appsv1alpha1.ConditionTypeClusterDefinitionUpdatePending) {
+		t.Errorf("expect the pending ClusterDefinition update condition")
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expect the event emitted once, but got %d", len(recorder.Events))
+	}
+
+	// adopt the new generation.
+	cluster.Annotations = map[string]string{constant.AdoptClusterDefGenerationAnnotationKey: "2"}
+	transformer.syncClusterDefinitionUpdate(transCtx, cluster)
+	if cluster.Status.ClusterDefGeneration != 2 {
+		t.Errorf("expect the cluster rendered against generation 2, but got %d", cluster.Status.ClusterDefGeneration)
+	}
+	if meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeClusterDefinitionUpdatePending) != nil {
+		t.Errorf("expect the pending ClusterDefinition update condition removed")
+	}
+}
+
+var _ = Describe("cluster status transformer test.", func() {
+	Context("cluster ready condition", func() {
+		It("should report the cluster stable since the latest component is ready", func() {
+			earlier := metav1.NewTime(time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC))
+			later := metav1.NewTime(time.Date(2023, 10, 1, 9, 0, 0, 0, time.UTC))
+			cluster := &appsv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				Status: appsv1alpha1.ClusterStatus{
+					Components: map[string]appsv1alpha1.ClusterComponentStatus{
+						"mysql": {Phase: appsv1alpha1.RunningClusterCompPhase, PodsReadyTime: &earlier},
+						"proxy": {Phase: appsv1alpha1.RunningClusterCompPhase, PodsReadyTime: &later},
+					},
+				},
+			}
+			(&ClusterStatusTransformer{}).syncClusterPhaseToRunning(cluster)
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeReady)
+			Expect(condition).ShouldNot(BeNil())
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).Should(ContainSubstring("stable since 2023-10-01T09:00:00Z"))
+		})
+	})
+})
@@ -0,0 +1,375 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
+)
+
+const (
+	// volumeUtilizationCheckInterval is the interval to check the utilization of the volumes again.
+	volumeUtilizationCheckInterval = 5 * time.Minute
+	// volumeAutoExpansionPercent is the percentage of the actual capacity by which the volumes are expanded automatically.
+	volumeAutoExpansionPercent = 50
+)
+
+// ClusterVolumeUtilizationTransformer checks the utilization of the PVCs of the components and records it in the
+// status of the components. It sets the VolumeUtilizationHigh condition and emits a warning event to prompt the
+// volume expansion if any of them exceeds the configured warning threshold, and the reason of the condition turns
+// to VolumeUtilizationCritical once any of them exceeds the critical threshold. The critical volumes are expanded
+// automatically by a VolumeExpansion OpsRequest if the annotation apps.kubeblocks.io/volume-auto-expansion is
+// enabled on the cluster, up to the configured max size and the max storage of the resource constraints, and once
+// per the configured cooldown, which is tracked by the annotation apps.kubeblocks.io/last-volume-auto-expansion.
+type ClusterVolumeUtilizationTransformer struct {
+	Provider component.VolumeStatsProvider
+}
+
+var _ graph.Transformer = &ClusterVolumeUtilizationTransformer{}
+
+func (t *ClusterVolumeUtilizationTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() || t.Provider == nil {
+		return nil
+	}
+	threshold := viper.GetInt(constant.CfgKeyVolumeUtilizationWarningThreshold)
+	if threshold <= 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)
+		return nil
+	}
+	criticalThreshold := viper.GetInt(constant.CfgKeyVolumeUtilizationCriticalThreshold)
+
+	podList := &corev1.PodList{}
+	if err := transCtx.Client.List(transCtx.Context, podList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			constant.AppManagedByLabelKey: constant.AppName,
+			constant.AppInstanceLabelKey:  cluster.Name,
+		}); err != nil {
+		return err
+	}
+
+	var (
+		monitored    bool
+		highPVCs     []string
+		criticalPVCs []string
+		// the PVCs of the critical volumes of the components, keyed by the components and the volume claim templates
+		criticalVolumes = map[string]map[string][]string{}
+	)
+	for _, pod := range podList.Items {
+		compName := pod.Labels[constant.KBAppComponentLabelKey]
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			// the usages are collected in the background, the ones not collected yet are checked next time.
+			usage, ok := t.Provider.GetPVCUsage(types.NamespacedName{Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName})
+			if !ok {
+				continue
+			}
+			monitored = true
+			utilization := usage.Utilization()
+			recordVolumeUtilization(cluster, compName, pod.Name, volume.Name, utilization)
+			if utilization <= threshold {
+				continue
+			}
+			highPVCs = append(highPVCs, fmt.Sprintf("%s(%s): %d%%", volume.PersistentVolumeClaim.ClaimName, compName, utilization))
+			if criticalThreshold > 0 && utilization > criticalThreshold {
+				criticalPVCs = append(criticalPVCs, volume.PersistentVolumeClaim.ClaimName)
+				if criticalVolumes[compName] == nil {
+					criticalVolumes[compName] = map[string][]string{}
+				}
+				criticalVolumes[compName][volume.Name] = append(criticalVolumes[compName][volume.Name], volume.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+
+	if len(highPVCs) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)
+	} else {
+		autoExpansion := isVolumeAutoExpansionEnabled(cluster)
+		slices.Sort(highPVCs)
+		message := fmt.Sprintf("the utilization of PVCs %v exceeds %d%%, please expand the volumes", highPVCs, threshold)
+		if len(criticalPVCs) > 0 {
+			slices.Sort(criticalPVCs)
+			message += fmt.Sprintf(", PVCs %v exceed the critical threshold %d%%", criticalPVCs, criticalThreshold)
+			if autoExpansion {
+				message += " and are being expanded automatically"
+			} else {
+				message += fmt.Sprintf(", or set the annotation %s=true on the cluster to expand them automatically",
+					constant.VolumeAutoExpansionAnnotationKey)
+			}
+		}
+		condition := newVolumeUtilizationHighCondition(len(criticalPVCs) > 0, message)
+		condition.ObservedGeneration = cluster.Generation
+		// the utilization changes all the time, only the trip and the escalation of the condition are recorded as events.
+		origCondition := meta.FindStatusCondition(transCtx.OrigCluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)
+		if origCondition == nil || origCondition.Reason != condition.Reason {
+			transCtx.EventRecorder.Event(cluster, corev1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+
+		if autoExpansion && len(criticalVolumes) > 0 {
+			if err := t.expandVolumes(transCtx, dag, criticalVolumes); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the volumes keep being written, check them periodically.
+	if monitored {
+		return intctrlutil.NewDelayedRequeueError(volumeUtilizationCheckInterval, "checking the utilization of the volumes")
+	}
+	return nil
+}
+
+// expandVolumes creates a VolumeExpansion OpsRequest to expand the critical volumes of the components by
+// volumeAutoExpansionPercent of their actual capacities, capped by the max storage of the components. The volumes
+// being resized are skipped, and no volume is expanded within the cooldown after the last auto-expansion, or while
+// a VolumeExpansion OpsRequest of the cluster is in progress.
+func (t *ClusterVolumeUtilizationTransformer) expandVolumes(transCtx *ClusterTransformContext, dag *graph.DAG,
+	criticalVolumes map[string]map[string][]string) error {
+	cluster := transCtx.Cluster
+	if lastExpansion, err := time.Parse(time.RFC3339, cluster.Annotations[constant.LastVolumeAutoExpansionAnnotationKey]); err == nil {
+		if cooldown := viper.GetDuration(constant.CfgKeyVolumeAutoExpansionCooldown); time.Since(lastExpansion) < cooldown {
+			transCtx.Logger.Info("skip the volume auto-expansion in the cooldown", "lastExpansion", lastExpansion, "cooldown", cooldown)
+			return nil
+		}
+	}
+	opsList := &appsv1alpha1.OpsRequestList{}
+	if err := transCtx.Client.List(transCtx.Context, opsList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			constant.AppInstanceLabelKey:    cluster.Name,
+			constant.OpsRequestTypeLabelKey: string(appsv1alpha1.VolumeExpansionType),
+		}); err != nil {
+		return err
+	}
+	for _, ops := range opsList.Items {
+		if !ops.IsComplete() {
+			transCtx.Logger.Info("skip the volume auto-expansion as the VolumeExpansion OpsRequest is in progress", "ops", ops.Name)
+			return nil
+		}
+	}
+
+	var expansions []appsv1alpha1.VolumeExpansion
+	compNames := maps.Keys(criticalVolumes)
+	sort.Strings(compNames)
+	for _, compName := range compNames {
+		compSpec := cluster.Spec.GetComponentByName(compName)
+		if compSpec == nil {
+			continue
+		}
+		maxStorage, err := t.getMaxStorage(transCtx, compSpec)
+		if err != nil {
+			return err
+		}
+		expansion := appsv1alpha1.VolumeExpansion{ComponentOps: appsv1alpha1.ComponentOps{ComponentName: compName}}
+		for _, vct := range compSpec.VolumeClaimTemplates {
+			pvcNames, ok := criticalVolumes[compName][vct.Name]
+			if !ok {
+				continue
+			}
+			storage, ok := vct.Spec.Resources.Requests[corev1.ResourceStorage]
+			if !ok || storage.IsZero() {
+				continue
+			}
+			capacity, resizing, err := t.getVolumeCapacity(transCtx, pvcNames)
+			if err != nil {
+				return err
+			}
+			if resizing || capacity.IsZero() {
+				transCtx.Logger.Info("skip the volume auto-expansion as the volumes are being resized", "component", compName, "volume", vct.Name)
+				continue
+			}
+			target := expandedStorage(capacity)
+			if maxStorage != nil && target.Cmp(*maxStorage) > 0 {
+				target = *maxStorage
+			}
+			if target.Cmp(storage) <= 0 {
+				transCtx.Logger.Info("skip the volume auto-expansion as the volumes reach the max storage", "component", compName,
+					"volume", vct.Name, "storage", storage.String())
+				continue
+			}
+			expansion.VolumeClaimTemplates = append(expansion.VolumeClaimTemplates, appsv1alpha1.OpsRequestVolumeClaimTemplate{
+				Name:    vct.Name,
+				Storage: target,
+			})
+		}
+		if len(expansion.VolumeClaimTemplates) > 0 {
+			expansions = append(expansions, expansion)
+		}
+	}
+	if len(expansions) == 0 {
+		return nil
+	}
+
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return err
+	}
+	ops := &appsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    cluster.Namespace,
+			GenerateName: fmt.Sprintf("%s-volume-auto-expansion-", cluster.Name),
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    cluster.Name,
+				constant.OpsRequestTypeLabelKey: string(appsv1alpha1.VolumeExpansionType),
+			},
+		},
+		Spec: appsv1alpha1.OpsRequestSpec{
+			ClusterRef:          cluster.Name,
+			Type:                appsv1alpha1.VolumeExpansionType,
+			VolumeExpansionList: expansions,
+		},
+	}
+	ictrltypes.LifecycleObjectCreate(dag, ops, root)
+	// the cooldown starts from the expansion, which is persisted with the cluster.
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[constant.LastVolumeAutoExpansionAnnotationKey] = time.Now().UTC().Format(time.RFC3339)
+
+	var targets []string
+	for _, expansion := range expansions {
+		for _, vct := range expansion.VolumeClaimTemplates {
+			targets = append(targets, fmt.Sprintf("%s/%s: %s", expansion.ComponentName, vct.Name, vct.Storage.String()))
+		}
+	}
+	transCtx.EventRecorder.Event(cluster, corev1.EventTypeNormal, ReasonVolumeUtilizationCritical,
+		fmt.Sprintf("expanding the volumes %s automatically", strings.Join(targets, ", ")))
+	return nil
+}
+
+// getVolumeCapacity returns the largest actual capacity of the PVCs, and whether any of them is being resized, i.e.
+// the requested storage is not satisfied yet, or the file system is waiting to be resized.
+func (t *ClusterVolumeUtilizationTransformer) getVolumeCapacity(transCtx *ClusterTransformContext,
+	pvcNames []string) (resource.Quantity, bool, error) {
+	var capacity resource.Quantity
+	for _, pvcName := range pvcNames {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvcKey := types.NamespacedName{Namespace: transCtx.Cluster.Namespace, Name: pvcName}
+		if err := transCtx.Client.Get(transCtx.Context, pvcKey, pvc); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return capacity, false, err
+		}
+		pvcCapacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		if pvc.Spec.Resources.Requests.Storage().Cmp(pvcCapacity) > 0 || slices.ContainsFunc(pvc.Status.Conditions,
+			func(cond corev1.PersistentVolumeClaimCondition) bool {
+				return cond.Status == corev1.ConditionTrue &&
+					(cond.Type == corev1.PersistentVolumeClaimResizing || cond.Type == corev1.PersistentVolumeClaimFileSystemResizePending)
+			}) {
+			return capacity, true, nil
+		}
+		if pvcCapacity.Cmp(capacity) > 0 {
+			capacity = pvcCapacity
+		}
+	}
+	return capacity, false, nil
+}
+
+// getMaxStorage returns the max storage the volumes of the component are expanded to automatically, which is the
+// smaller one of the configured max size and the max storage of the resource constraints of the component, nil if
+// neither limits it. The storage fits any of the constraint rules matching the resources of the component,
+// so the largest max storage of them applies.
+func (t *ClusterVolumeUtilizationTransformer) getMaxStorage(transCtx *ClusterTransformContext,
+	compSpec *appsv1alpha1.ClusterComponentSpec) (*resource.Quantity, error) {
+	var maxStorage *resource.Quantity
+	if value := viper.GetString(constant.CfgKeyVolumeAutoExpansionMaxSize); len(value) > 0 {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", constant.CfgKeyVolumeAutoExpansionMaxSize, value, err)
+		}
+		maxStorage = &quantity
+	}
+
+	constraintList := &appsv1alpha1.ComponentResourceConstraintList{}
+	if err := transCtx.Client.List(transCtx.Context, constraintList); err != nil {
+		return nil, err
+	}
+	var (
+		constraintMax *resource.Quantity
+		unlimited     bool
+	)
+	for _, constraint := range constraintList.Items {
+		for _, rule := range constraint.FindMatchingRules(transCtx.Cluster.Spec.ClusterDefRef, compSpec.ComponentDefRef,
+			compSpec.Resources.Requests) {
+			switch {
+			case rule.Storage.Max == nil:
+				unlimited = true
+			case constraintMax == nil || rule.Storage.Max.Cmp(*constraintMax) > 0:
+				constraintMax = rule.Storage.Max
+			}
+		}
+	}
+	if !unlimited && constraintMax != nil && (maxStorage == nil || constraintMax.Cmp(*maxStorage) < 0) {
+		maxStorage = constraintMax
+	}
+	return maxStorage, nil
+}
+
+// recordVolumeUtilization records the utilization of the volume in the volume capacities of the component status.
+func recordVolumeUtilization(cluster *appsv1alpha1.Cluster, compName, podName, vctName string, utilization int) {
+	compStatus, ok := cluster.Status.Components[compName]
+	if !ok {
+		return
+	}
+	for i := range compStatus.VolumeCapacities {
+		capacity := &compStatus.VolumeCapacities[i]
+		if capacity.PodName == podName && capacity.VolumeClaimTemplateName == vctName {
+			value := int32(utilization)
+			capacity.Utilization = &value
+		}
+	}
+	cluster.Status.Components[compName] = compStatus
+}
+
+// expandedStorage returns the capacity expanded by volumeAutoExpansionPercent, rounded up to Gi.
+func expandedStorage(capacity resource.Quantity) resource.Quantity {
+	const gi = int64(1024 * 1024 * 1024)
+	size := capacity.Value() + capacity.Value()*volumeAutoExpansionPercent/100
+	return *resource.NewQuantity((size+gi-1)/gi*gi, resource.BinarySI)
+}
+
+// isVolumeAutoExpansionEnabled checks if the volumes of the cluster are expanded automatically.
+func isVolumeAutoExpansionEnabled(cluster *appsv1alpha1.Cluster) bool {
+	return strings.EqualFold(cluster.Annotations[constant.VolumeAutoExpansionAnnotationKey], "true")
+}
@@ -0,0 +1,364 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
+)
+
+type fakeVolumeStatsProvider struct {
+	usages map[types.NamespacedName]component.VolumeUsage
+}
+
+func (p *fakeVolumeStatsProvider) GetPVCUsage(pvc types.NamespacedName) (component.VolumeUsage, bool) {
+	usage, ok := p.usages[pvc]
+	return usage, ok
+}
+
+var _ = Describe("cluster volume utilization transformer test.", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		nodeName           = "node-0"
+		capacity           = int64(10 * 1024 * 1024 * 1024)
+	)
+
+	var (
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		provider    *fakeVolumeStatsProvider
+		cluster     *appsv1alpha1.Cluster
+		recorder    *record.FakeRecorder
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.PersistentVolumeClaimSignature, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+		ctx := context.Background()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			SetReplicas(1).
+			GetObject()
+		recorder = record.NewFakeRecorder(10)
+		transCtx = &ClusterTransformContext{
+			Context:       ctx,
+			Client:        k8sClient,
+			EventRecorder: recorder,
+			Logger:        logf.FromContext(ctx).WithValues("transformer-volume-utilization-test", testCtx.DefaultNamespace),
+			Cluster:       cluster,
+			OrigCluster:   cluster.DeepCopy(),
+		}
+		provider = &fakeVolumeStatsProvider{}
+		transformer = &ClusterVolumeUtilizationTransformer{Provider: provider}
+		viper.Set(constant.CfgKeyVolumeUtilizationWarningThreshold, 85)
+		viper.Set(constant.CfgKeyVolumeUtilizationCriticalThreshold, 0)
+	})
+
+	AfterEach(func() {
+		cleanEnv()
+		viper.Set(constant.CfgKeyVolumeUtilizationWarningThreshold, 85)
+		viper.Set(constant.CfgKeyVolumeUtilizationCriticalThreshold, 0)
+		viper.Set(constant.CfgKeyVolumeAutoExpansionMaxSize, "")
+		viper.Set(constant.CfgKeyVolumeAutoExpansionCooldown, 0)
+	})
+
+	mockPodWithPVC := func(pvcName string) {
+		testapps.NewPodFactory(testCtx.DefaultNamespace, cluster.Name+"-"+mysqlCompName+"-0").
+			AddAppInstanceLabel(cluster.Name).
+			AddAppComponentLabel(mysqlCompName).
+			AddAppManagedByLabel().
+			AddNodeName(nodeName).
+			AddVolume(corev1.Volume{
+				Name: testapps.DataVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+				},
+			}).
+			AddContainer(corev1.Container{Name: testapps.DefaultMySQLContainerName, Image: testapps.ApeCloudMySQLImage}).
+			Create(&testCtx)
+	}
+
+	mockUtilization := func(pvcName string, utilization int64) {
+		provider.usages = map[types.NamespacedName]component.VolumeUsage{
+			{Namespace: testCtx.DefaultNamespace, Name: pvcName}: {
+				UsedBytes:     capacity / 100 * utilization,
+				CapacityBytes: capacity,
+			},
+		}
+	}
+
+	Context("volume utilization high condition", func() {
+		var pvcName string
+
+		BeforeEach(func() {
+			pvcName = testapps.DataVolumeName + "-" + cluster.Name + "-" + mysqlCompName + "-0"
+			mockPodWithPVC(pvcName)
+		})
+
+		It("should not warn the volumes under the threshold", func() {
+			mockUtilization(pvcName, 80)
+			err := transformer.Transform(transCtx, graph.NewDAG())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)).Should(BeNil())
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+
+		It("should set the condition and emit the event once the utilization exceeds the threshold", func() {
+			mockUtilization(pvcName, 90)
+			err := transformer.Transform(transCtx, graph.NewDAG())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).Should(Equal(ReasonVolumeUtilizationHigh))
+			Expect(cond.Message).Should(ContainSubstring(pvcName + "(" + mysqlCompName + "): 90%"))
+			Expect(recorder.Events).Should(Receive(And(ContainSubstring(corev1.EventTypeWarning), ContainSubstring(ReasonVolumeUtilizationHigh))))
+
+			By("no duplicated event while the condition holds")
+			transCtx.OrigCluster = cluster.DeepCopy()
+			mockUtilization(pvcName, 92)
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			Expect(recorder.Events).Should(BeEmpty())
+
+			By("remove the condition once the volume is expanded")
+			provider.usages[types.NamespacedName{Namespace: testCtx.DefaultNamespace, Name: pvcName}] = component.VolumeUsage{
+				UsedBytes:     capacity / 100 * 92,
+				CapacityBytes: capacity * 2,
+			}
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)).Should(BeNil())
+		})
+
+		It("should respect the configured threshold", func() {
+			mockUtilization(pvcName, 80)
+			viper.Set(constant.CfgKeyVolumeUtilizationWarningThreshold, 75)
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)).ShouldNot(BeNil())
+
+			By("disable the warning with threshold 0")
+			viper.Set(constant.CfgKeyVolumeUtilizationWarningThreshold, 0)
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)).Should(BeNil())
+		})
+
+		It("should record the utilization in the component status", func() {
+			cluster.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{
+				mysqlCompName: {
+					VolumeCapacities: []appsv1alpha1.VolumeCapacityStatus{{
+						PodName:                 cluster.Name + "-" + mysqlCompName + "-0",
+						VolumeClaimTemplateName: testapps.DataVolumeName,
+						Requested:               resource.MustParse("10Gi"),
+						Capacity:                resource.MustParse("10Gi"),
+					}},
+				},
+			}
+			mockUtilization(pvcName, 42)
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			utilization := cluster.Status.Components[mysqlCompName].VolumeCapacities[0].Utilization
+			Expect(utilization).ShouldNot(BeNil())
+			Expect(*utilization).Should(BeEquivalentTo(42))
+		})
+
+		It("should escalate the condition once the utilization exceeds the critical threshold", func() {
+			viper.Set(constant.CfgKeyVolumeUtilizationCriticalThreshold, 90)
+			mockUtilization(pvcName, 88)
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Reason).Should(Equal(ReasonVolumeUtilizationHigh))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(ReasonVolumeUtilizationHigh)))
+
+			By("emit the event again as the condition escalates")
+			transCtx.OrigCluster = cluster.DeepCopy()
+			mockUtilization(pvcName, 95)
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, graph.NewDAG()))).Should(BeTrue())
+			cond = meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeVolumeUtilizationHigh)
+			Expect(cond.Reason).Should(Equal(ReasonVolumeUtilizationCritical))
+			Expect(cond.Message).Should(ContainSubstring(constant.VolumeAutoExpansionAnnotationKey))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(ReasonVolumeUtilizationCritical)))
+		})
+	})
+
+	Context("volume auto-expansion", func() {
+		var pvcName string
+
+		BeforeEach(func() {
+			pvcName = testapps.DataVolumeName + "-" + cluster.Name + "-" + mysqlCompName + "-0"
+			mockPodWithPVC(pvcName)
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates = []appsv1alpha1.ClusterComponentVolumeClaimTemplate{{
+				Name: testapps.DataVolumeName,
+				Spec: testapps.NewPVCSpec("10Gi"),
+			}}
+			cluster.Annotations = map[string]string{constant.VolumeAutoExpansionAnnotationKey: "true"}
+			viper.Set(constant.CfgKeyVolumeUtilizationCriticalThreshold, 90)
+		})
+
+		mockPVC := func(requested, capacity string) {
+			pvc := testapps.NewPersistentVolumeClaimFactory(testCtx.DefaultNamespace, pvcName, cluster.Name,
+				mysqlCompName, testapps.DataVolumeName).
+				SetStorage(requested).
+				Create(&testCtx).
+				GetObject()
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(pvc), func(pvc *corev1.PersistentVolumeClaim) {
+				pvc.Status.Phase = corev1.ClaimBound
+				pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+			})).Should(Succeed())
+		}
+
+		getExpandedStorage := func(dag *graph.DAG) string {
+			vertices := ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)
+			Expect(vertices).Should(HaveLen(1))
+			ops := vertices[0].(*ictrltypes.LifecycleVertex).Obj.(*appsv1alpha1.OpsRequest)
+			Expect(ops.Spec.VolumeExpansionList).Should(HaveLen(1))
+			Expect(ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates).Should(HaveLen(1))
+			return ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates[0].Storage.String()
+		}
+
+		mockDAG := func() *graph.DAG {
+			dag := graph.NewDAG()
+			ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+			return dag
+		}
+
+		It("should not expand the volumes under the critical threshold", func() {
+			mockUtilization(pvcName, 88)
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)).Should(BeEmpty())
+		})
+
+		It("should create the VolumeExpansion OpsRequest for the critical volumes", func() {
+			mockPVC("10Gi", "10Gi")
+			mockUtilization(pvcName, 95)
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			vertices := ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)
+			Expect(vertices).Should(HaveLen(1))
+			ops := vertices[0].(*ictrltypes.LifecycleVertex).Obj.(*appsv1alpha1.OpsRequest)
+			Expect(ops.Spec.Type).Should(Equal(appsv1alpha1.VolumeExpansionType))
+			Expect(ops.Spec.ClusterRef).Should(Equal(cluster.Name))
+			Expect(ops.Spec.VolumeExpansionList).Should(HaveLen(1))
+			Expect(ops.Spec.VolumeExpansionList[0].ComponentName).Should(Equal(mysqlCompName))
+			Expect(ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates).Should(HaveLen(1))
+			Expect(ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates[0].Name).Should(Equal(testapps.DataVolumeName))
+			Expect(ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates[0].Storage.String()).Should(Equal("15Gi"))
+			Expect(cluster.Annotations).Should(HaveKey(constant.LastVolumeAutoExpansionAnnotationKey))
+		})
+
+		It("should expand the volumes from their actual capacities", func() {
+			mockPVC("10Gi", "12Gi")
+			mockUtilization(pvcName, 95)
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(getExpandedStorage(dag)).Should(Equal("18Gi"))
+		})
+
+		It("should not expand the volumes being resized", func() {
+			mockPVC("15Gi", "10Gi")
+			mockUtilization(pvcName, 95)
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)).Should(BeEmpty())
+		})
+
+		It("should expand the volumes up to the max size", func() {
+			mockPVC("10Gi", "10Gi")
+			mockUtilization(pvcName, 95)
+			viper.Set(constant.CfgKeyVolumeAutoExpansionMaxSize, "12Gi")
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(getExpandedStorage(dag)).Should(Equal("12Gi"))
+
+			By("no expansion once the volumes reach the max size")
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Spec = testapps.NewPVCSpec("12Gi")
+			cluster.Annotations = map[string]string{constant.VolumeAutoExpansionAnnotationKey: "true"}
+			dag = mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)).Should(BeEmpty())
+		})
+
+		It("should not expand the volumes in the cooldown", func() {
+			mockPVC("10Gi", "10Gi")
+			mockUtilization(pvcName, 95)
+			viper.Set(constant.CfgKeyVolumeAutoExpansionCooldown, "1h")
+			cluster.Annotations[constant.LastVolumeAutoExpansionAnnotationKey] = time.Now().Add(-30 * time.Minute).UTC().Format(time.RFC3339)
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)).Should(BeEmpty())
+
+			By("expand the volumes once the cooldown passes")
+			cluster.Annotations[constant.LastVolumeAutoExpansionAnnotationKey] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+			dag = mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(getExpandedStorage(dag)).Should(Equal("15Gi"))
+		})
+
+		It("should not expand the volumes while a VolumeExpansion OpsRequest is in progress", func() {
+			testapps.CreateOpsRequest(testCtx.Ctx, testCtx,
+				testapps.NewOpsRequestObj("expansion-"+cluster.Name, testCtx.DefaultNamespace, cluster.Name, appsv1alpha1.VolumeExpansionType))
+			mockUtilization(pvcName, 95)
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)).Should(BeEmpty())
+		})
+
+		It("should not expand the volumes if the auto-expansion is disabled", func() {
+			cluster.Annotations = nil
+			mockUtilization(pvcName, 95)
+			dag := mockDAG()
+			Expect(intctrlutil.IsDelayedRequeueError(transformer.Transform(transCtx, dag))).Should(BeTrue())
+			Expect(ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag)).Should(BeEmpty())
+		})
+	})
+})
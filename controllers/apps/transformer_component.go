@@ -20,11 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/controllers/apps/components"
+	"github.com/apecloud/kubeblocks/internal/constant"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
 	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
 	ictrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
@@ -99,29 +104,22 @@ func (c *ComponentTransformer) transform4SpecUpdate(reqCtx ictrlutil.RequestCtx,
 	updateSet := compProto.Intersection(compStatus)
 	deleteSet := compStatus.Difference(compProto)
 
-	for compName := range createSet {
-		dag := graph.NewDAG()
-		comp, err := components.NewComponent(reqCtx, c.Client, clusterDef, clusterVer, cluster, compName, dag)
-		if err != nil {
-			return err
-		}
-		if comp == nil {
-			continue
-		}
-		if err := comp.Create(reqCtx, c.Client); err != nil {
-			return err
-		}
-		*dags = append(*dags, dag)
+	createList, pendingList, err := throttleComponentCreation(cluster, createSet)
+	if err != nil {
+		return err
+	}
+	if err := c.createComponents(reqCtx, clusterDef, clusterVer, cluster, createList, dags); err != nil {
+		return err
 	}
 
 	for compName := range deleteSet {
 		dag := graph.NewDAG()
-		comp, err := components.NewComponent(reqCtx, c.Client, clusterDef, clusterVer, cluster, compName, dag)
+		compReqCtx, comp, err := c.newComponent(reqCtx, clusterDef, clusterVer, cluster, compName, dag)
 		if err != nil {
 			return err
 		}
 		if comp != nil {
-			if err := comp.Delete(reqCtx, c.Client); err != nil {
+			if err := comp.Delete(compReqCtx, c.Client); err != nil {
 				return err
 			}
 		}
@@ -130,29 +128,47 @@ func (c *ComponentTransformer) transform4SpecUpdate(reqCtx ictrlutil.RequestCtx,
 
 	for compName := range updateSet {
 		dag := graph.NewDAG()
-		comp, err := components.NewComponent(reqCtx, c.Client, clusterDef, clusterVer, cluster, compName, dag)
+		compReqCtx, comp, err := c.newComponent(reqCtx, clusterDef, clusterVer, cluster, compName, dag)
 		if err != nil {
 			return err
 		}
-		if err := comp.Update(reqCtx, c.Client); err != nil {
+		if err := comp.Update(compReqCtx, c.Client); err != nil {
 			return err
 		}
 		*dags = append(*dags, dag)
 	}
 
-	return nil
+	return pendingComponentsError(pendingList)
 }
 
 func (c *ComponentTransformer) transform4StatusUpdate(reqCtx ictrlutil.RequestCtx, clusterDef *appsv1alpha1.ClusterDefinition,
 	clusterVer *appsv1alpha1.ClusterVersion, cluster *appsv1alpha1.Cluster, dags *[]*graph.DAG) error {
-	var delayedError error
+	// the components throttled by the creation concurrency are created once the previous ones are running.
+	pendingSet := sets.New[string]()
 	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		if _, ok := cluster.Status.Components[compSpec.Name]; !ok {
+			pendingSet.Insert(compSpec.Name)
+		}
+	}
+	createList, pendingList, err := throttleComponentCreation(cluster, pendingSet)
+	if err != nil {
+		return err
+	}
+	if err := c.createComponents(reqCtx, clusterDef, clusterVer, cluster, createList, dags); err != nil {
+		return err
+	}
+
+	delayedError := pendingComponentsError(pendingList)
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		if pendingSet.Has(compSpec.Name) {
+			continue
+		}
 		dag := graph.NewDAG()
-		comp, err := components.NewComponent(reqCtx, c.Client, clusterDef, clusterVer, cluster, compSpec.Name, dag)
+		compReqCtx, comp, err := c.newComponent(reqCtx, clusterDef, clusterVer, cluster, compSpec.Name, dag)
 		if err != nil {
 			return err
 		}
-		if err := comp.Status(reqCtx, c.Client); err != nil {
+		if err := comp.Status(compReqCtx, c.Client); err != nil {
 			if !ictrlutil.IsDelayedRequeueError(err) {
 				return err
 			}
@@ -164,3 +180,89 @@ func (c *ComponentTransformer) transform4StatusUpdate(reqCtx ictrlutil.RequestCt
 	}
 	return delayedError
 }
+
+func (c *ComponentTransformer) createComponents(reqCtx ictrlutil.RequestCtx, clusterDef *appsv1alpha1.ClusterDefinition,
+	clusterVer *appsv1alpha1.ClusterVersion, cluster *appsv1alpha1.Cluster, compNames []string, dags *[]*graph.DAG) error {
+	for _, compName := range compNames {
+		dag := graph.NewDAG()
+		compReqCtx, comp, err := c.newComponent(reqCtx, clusterDef, clusterVer, cluster, compName, dag)
+		if err != nil {
+			return err
+		}
+		if comp == nil {
+			continue
+		}
+		if err := comp.Create(compReqCtx, c.Client); err != nil {
+			return err
+		}
+		*dags = append(*dags, dag)
+	}
+	return nil
+}
+
+// throttleComponentCreation splits the components to create into the ones to create right now and the ones to wait,
+// following the order of the component specs. The number of components being created concurrently is limited by the
+// annotation apps.kubeblocks.io/component-creation-concurrency of the cluster, to avoid overwhelming the nodes by
+// bringing up all the pods of a large cluster at once. A component is being created until it's running.
+func throttleComponentCreation(cluster *appsv1alpha1.Cluster, createSet sets.Set[string]) ([]string, []string, error) {
+	createList := make([]string, 0, createSet.Len())
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		if createSet.Has(compSpec.Name) {
+			createList = append(createList, compSpec.Name)
+		}
+	}
+	// the component specs are generated from the cluster definition if not specified.
+	if len(createList) != createSet.Len() {
+		createList = sets.List(createSet)
+	}
+
+	concurrency, err := getComponentCreationConcurrency(cluster)
+	if err != nil || concurrency == 0 {
+		return createList, nil, err
+	}
+	creating := 0
+	for _, status := range cluster.Status.Components {
+		if status.Phase == "" || status.Phase == appsv1alpha1.CreatingClusterCompPhase {
+			creating++
+		}
+	}
+	available := concurrency - creating
+	if available <= 0 {
+		return nil, createList, nil
+	}
+	if available >= len(createList) {
+		return createList, nil, nil
+	}
+	return createList[:available], createList[available:], nil
+}
+
+// getComponentCreationConcurrency returns the max number of components to create concurrently, zero means no limit.
+func getComponentCreationConcurrency(cluster *appsv1alpha1.Cluster) (int, error) {
+	value, ok := cluster.Annotations[constant.ComponentCreationConcurrencyAnnotationKey]
+	if !ok {
+		return 0, nil
+	}
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency <= 0 {
+		return 0, fmt.Errorf("invalid annotation %s: %s, it should be a positive integer",
+			constant.ComponentCreationConcurrencyAnnotationKey, value)
+	}
+	return concurrency, nil
+}
+
+func pendingComponentsError(pendingList []string) error {
+	if len(pendingList) == 0 {
+		return nil
+	}
+	return ictrlutil.NewDelayedRequeueError(requeueDuration,
+		fmt.Sprintf("waiting for the creation of components %s", strings.Join(pendingList, ",")))
+}
+
+// newComponent builds the component, the logger of the returned RequestCtx carries the component name.
+func (c *ComponentTransformer) newComponent(reqCtx ictrlutil.RequestCtx, clusterDef *appsv1alpha1.ClusterDefinition,
+	clusterVer *appsv1alpha1.ClusterVersion, cluster *appsv1alpha1.Cluster, compName string, dag *graph.DAG) (ictrlutil.RequestCtx, components.Component, error) {
+	reqCtx = reqCtx.WithLogValues(ictrlutil.LogFieldComponent, compName)
+	reqCtx.Log.V(1).Info("reconcile component")
+	comp, err := components.NewComponent(reqCtx, c.Client, clusterDef, clusterVer, cluster, compName, dag)
+	return reqCtx, comp, err
+}
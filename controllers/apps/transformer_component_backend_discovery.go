@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	cfgutil "github.com/apecloud/kubeblocks/internal/configuration/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+)
+
+// ComponentBackendDiscoveryTransformer renders the members of the backend components, with their addresses and roles,
+// into a ConfigMap mounted into the pods of the components declaring backendDiscovery, e.g. the proxies splitting
+// the reads and writes. The ConfigMap is refreshed as the membersStatus of the backend components changes, e.g. on
+// a failover or a scaling. The pods are not restarted, the mounted ConfigMap is refreshed by the kubelet in place,
+// and the config manager sidecar watching it reloads the backends by the reloadOptions of the configConstraintRef.
+type ComponentBackendDiscoveryTransformer struct{}
+
+var _ graph.Transformer = &ComponentBackendDiscoveryTransformer{}
+
+func (t *ComponentBackendDiscoveryTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	discoveries := make(map[string]*appsv1alpha1.BackendDiscovery)
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef != nil && compDef.BackendDiscovery != nil {
+			discoveries[compSpec.Name] = compDef.BackendDiscovery
+		}
+	}
+	if len(discoveries) == 0 {
+		return nil
+	}
+
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return err
+	}
+	compNames := maps.Keys(discoveries)
+	slices.Sort(compNames)
+	for _, compName := range compNames {
+		if err = t.reconcileBackends(transCtx, dag, root, compName, discoveries[compName]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileBackends creates or updates the ConfigMap listing the backends of the component once they change.
+func (t *ComponentBackendDiscoveryTransformer) reconcileBackends(transCtx *ClusterTransformContext, dag *graph.DAG,
+	root *ictrltypes.LifecycleVertex, compName string, discovery *appsv1alpha1.BackendDiscovery) error {
+	cluster := transCtx.Cluster
+	backends := buildBackends(cluster, transCtx.ClusterDef, discovery)
+	checksum, err := cfgutil.ComputeHash(backends)
+	if err != nil {
+		return err
+	}
+	proto := factory.BuildBackendsConfigMap(cluster, compName, backends, checksum)
+
+	cm := &corev1.ConfigMap{}
+	if err = transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(proto), cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		ictrltypes.LifecycleObjectCreate(dag, proto, root)
+	} else if cm.Annotations[constant.BackendsChecksumAnnotationKey] != checksum {
+		cmCopy := cm.DeepCopy()
+		if cm.Annotations == nil {
+			cm.Annotations = map[string]string{}
+		}
+		cm.Annotations[constant.BackendsChecksumAnnotationKey] = checksum
+		cm.Data = proto.Data
+		ictrltypes.LifecycleObjectPatch(dag, cm, cmCopy, root)
+	}
+	return nil
+}
+
+// buildBackends lists the members of the backend components in the <host>:<port>:<role> form, one entry per line.
+// The members are the ones reported by the membersStatus of the backend components, which follows their role changes.
+func buildBackends(cluster *appsv1alpha1.Cluster, clusterDef *appsv1alpha1.ClusterDefinition,
+	discovery *appsv1alpha1.BackendDiscovery) string {
+	port := getBackendPort(clusterDef.GetComponentDefByName(discovery.CompDef), discovery.PortName)
+	var entries []string
+	for _, compSpec := range cluster.Spec.GetDefNameMappingComponents()[discovery.CompDef] {
+		for _, member := range cluster.Status.Components[compSpec.Name].MembersStatus {
+			host := fmt.Sprintf("%s.%s-%s-headless.%s.svc", member.PodName, cluster.Name, compSpec.Name, cluster.Namespace)
+			entries = append(entries, fmt.Sprintf("%s:%d:%s", host, port, member.ReplicaRole.Name))
+		}
+	}
+	slices.Sort(entries)
+	return strings.Join(entries, "\n")
+}
+
+// getBackendPort returns the container port of the backends with the name, or the first one if the name is empty.
+func getBackendPort(compDef *appsv1alpha1.ClusterComponentDefinition, portName string) int32 {
+	if compDef == nil || compDef.PodSpec == nil {
+		return 0
+	}
+	for _, container := range compDef.PodSpec.Containers {
+		for _, port := range container.Ports {
+			if len(portName) == 0 || port.Name == portName {
+				return port.ContainerPort
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("component backend discovery transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		proxyCompName      = "proxy"
+		proxyCompDefName   = "proxy"
+		backendsMountPath  = "/etc/proxy/backends"
+	)
+
+	var (
+		ctx         context.Context
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	setMemberRoles := func(roles ...string) {
+		var members []workloads.MemberStatus
+		for i, role := range roles {
+			members = append(members, workloads.MemberStatus{
+				PodName:     fmt.Sprintf("%s-%s-%d", clusterName, mysqlCompName, i),
+				ReplicaRole: workloads.ReplicaRole{Name: role},
+			})
+		}
+		cluster.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{
+			mysqlCompName: {MembersStatus: members},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterDef := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, mysqlCompDefName).
+			AddComponentDef(testapps.StatelessNginxComponent, proxyCompDefName).
+			SetBackendDiscovery(&appsv1alpha1.BackendDiscovery{
+				CompDef:   mysqlCompDefName,
+				PortName:  "mysql",
+				MountPath: backendsMountPath,
+			}).
+			GetObject()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			AddComponent(proxyCompName, proxyCompDefName).
+			GetObject()
+		setMemberRoles("leader", "follower", "follower")
+		transCtx = &ClusterTransformContext{
+			Context:    ctx,
+			Client:     k8sClient,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-backend-discovery-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+		transformer = &ComponentBackendDiscoveryTransformer{}
+	})
+
+	mockDAG := func() *graph.DAG {
+		dag := graph.NewDAG()
+		ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		return dag
+	}
+
+	findConfigMapVertex := func(dag *graph.DAG) *ictrltypes.LifecycleVertex {
+		for _, vertex := range ictrltypes.FindAll[*corev1.ConfigMap](dag) {
+			return vertex.(*ictrltypes.LifecycleVertex)
+		}
+		return nil
+	}
+
+	backendHost := func(ordinal int) string {
+		return fmt.Sprintf("%s-%s-%d.%s-%s-headless.%s.svc", clusterName, mysqlCompName, ordinal,
+			clusterName, mysqlCompName, testCtx.DefaultNamespace)
+	}
+
+	Context("backend discovery", func() {
+		It("should refresh the backends on role changes", func() {
+			By("render the backends")
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			cmVertex := findConfigMapVertex(dag)
+			Expect(cmVertex).ShouldNot(BeNil())
+			Expect(*cmVertex.Action).Should(Equal(ictrltypes.CREATE))
+			cm, _ := cmVertex.Obj.(*corev1.ConfigMap)
+			Expect(cm.Name).Should(Equal(component.GenerateBackendsConfigMapName(clusterName, proxyCompName)))
+			Expect(cm.Data[constant.BackendsKey]).Should(Equal(fmt.Sprintf("%s:3306:leader\n%s:3306:follower\n%s:3306:follower",
+				backendHost(0), backendHost(1), backendHost(2))))
+			checksum := cm.Annotations[constant.BackendsChecksumAnnotationKey]
+			Expect(checksum).ShouldNot(BeEmpty())
+
+			By("apply the backends")
+			Expect(k8sClient.Create(ctx, cm)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, cm)).Should(Succeed())
+			})
+
+			By("nothing to do if the members don't change")
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(findConfigMapVertex(dag)).Should(BeNil())
+
+			By("refresh the backends once the leader changes")
+			setMemberRoles("follower", "leader", "follower")
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			cmVertex = findConfigMapVertex(dag)
+			Expect(cmVertex).ShouldNot(BeNil())
+			Expect(*cmVertex.Action).Should(Equal(ictrltypes.PATCH))
+			cm, _ = cmVertex.Obj.(*corev1.ConfigMap)
+			Expect(cm.Data[constant.BackendsKey]).Should(ContainSubstring(backendHost(1) + ":3306:leader"))
+			Expect(cm.Annotations[constant.BackendsChecksumAnnotationKey]).ShouldNot(Equal(checksum))
+		})
+	})
+})
@@ -0,0 +1,337 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	cfgutil "github.com/apecloud/kubeblocks/internal/configuration/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+)
+
+// ComponentConfigChecksumTransformer computes a checksum of all ConfigMaps/Secrets mounted by the pods of a component,
+// and writes it into the pod template annotations of the workload, so the pods will be restarted once they change.
+// ConfigMaps rendered from config templates are excluded, as they are handled by the reconfiguring policies.
+// The Secrets/ConfigMaps referenced by the env of the containers are included as well, except the env ConfigMaps
+// generated by KubeBlocks, which carry the membership of the replicas and change without the need of restarting.
+// The Secrets/ConfigMaps listed in the restart-on-change annotation of the cluster are included as well,
+// even if they are not mounted, e.g. referenced by the env of the containers.
+type ComponentConfigChecksumTransformer struct{}
+
+var _ graph.Transformer = &ComponentConfigChecksumTransformer{}
+
+func (t *ComponentConfigChecksumTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	if transCtx.Cluster.IsDeleting() {
+		return nil
+	}
+
+	for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+		v, _ := vertex.(*ictrltypes.LifecycleVertex)
+		if v.Immutable || v.Action == nil {
+			continue
+		}
+		rsm, _ := v.Obj.(*workloads.ReplicatedStateMachine)
+		// the workloads of paused components are frozen.
+		if transCtx.Cluster.IsComponentPaused(rsm.Labels[constant.KBAppComponentLabelKey]) {
+			continue
+		}
+		checksum, err := buildMountedConfigChecksum(transCtx, dag, rsm)
+		if err != nil {
+			return err
+		}
+		switch *v.Action {
+		case ictrltypes.CREATE:
+			setMountedConfigChecksum(&rsm.Spec.Template, checksum)
+		case ictrltypes.UPDATE:
+			// the annotations of the running workload and its pod template are kept by the update.
+			applyMountedConfigChecksum(rsm, checksum)
+		case ictrltypes.NOOP:
+			runningRSM := &workloads.ReplicatedStateMachine{}
+			if err := transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(rsm), runningRSM); err != nil {
+				if apierrors.IsNotFound(err) {
+					// the other workloads are still to be checked.
+					continue
+				}
+				return err
+			}
+			objCopy := runningRSM.DeepCopy()
+			if !applyMountedConfigChecksum(runningRSM, checksum) {
+				continue
+			}
+			v.ObjCopy = objCopy
+			v.Obj = runningRSM
+			v.Action = ictrltypes.ActionPatchPtr()
+		}
+	}
+	return nil
+}
+
+// buildMountedConfigChecksum computes the checksum of the ConfigMaps/Secrets mounted by the pods,
+// the objects in the DAG take precedence over the ones in the cluster, as they are the desired ones.
+func buildMountedConfigChecksum(transCtx *ClusterTransformContext, dag *graph.DAG, rsm *workloads.ReplicatedStateMachine) (string, error) {
+	configMaps, secrets := getMountedConfigMapsAndSecrets(rsm.Spec.Template.Spec.Volumes)
+	envConfigMaps, envSecrets := getEnvReferencedConfigMapsAndSecrets(&rsm.Spec.Template.Spec)
+	restartConfigMaps, restartSecrets := getRestartOnChangeConfigMapsAndSecrets(transCtx.Cluster)
+	secrets = append(append(secrets, envSecrets...), restartSecrets...)
+	data := make(map[string]any, len(configMaps)+len(envConfigMaps)+len(secrets)+len(restartConfigMaps))
+	addConfigMap := func(name string, skipRendered, skipGenerated bool) error {
+		if _, ok := data["ConfigMap/"+name]; ok {
+			return nil
+		}
+		cm := &corev1.ConfigMap{}
+		found, err := getMountedObject(transCtx, dag, client.ObjectKey{Namespace: rsm.Namespace, Name: name}, cm)
+		if err != nil {
+			return err
+		}
+		// the backends are reloaded in place by the config manager sidecar, see ComponentBackendDiscoveryTransformer.
+		_, isBackends := cm.Annotations[constant.BackendsChecksumAnnotationKey]
+		if !found || (skipRendered && (len(cm.Labels[constant.CMConfigurationTypeLabelKey]) > 0 || isBackends)) {
+			return nil
+		}
+		if skipGenerated && isGeneratedEnvConfigMap(cm) {
+			return nil
+		}
+		data["ConfigMap/"+name] = map[string]any{"data": cm.Data, "binaryData": cm.BinaryData}
+		return nil
+	}
+	for _, name := range configMaps {
+		if err := addConfigMap(name, true, false); err != nil {
+			return "", err
+		}
+	}
+	for _, name := range envConfigMaps {
+		if err := addConfigMap(name, true, true); err != nil {
+			return "", err
+		}
+	}
+	// the ConfigMaps listed explicitly are watched even if they are rendered from config templates.
+	for _, name := range restartConfigMaps {
+		if err := addConfigMap(name, false, false); err != nil {
+			return "", err
+		}
+	}
+	for _, name := range secrets {
+		secret := &corev1.Secret{}
+		found, err := getMountedObject(transCtx, dag, client.ObjectKey{Namespace: rsm.Namespace, Name: name}, secret)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			continue
+		}
+		secretData := make(map[string][]byte, len(secret.Data)+len(secret.StringData))
+		for k, v := range secret.Data {
+			secretData[k] = v
+		}
+		// stringData takes precedence over data, the same as the API server does.
+		for k, v := range secret.StringData {
+			secretData[k] = []byte(v)
+		}
+		data["Secret/"+name] = secretData
+	}
+	return cfgutil.ComputeHash(data)
+}
+
+func getMountedConfigMapsAndSecrets(volumes []corev1.Volume) ([]string, []string) {
+	var configMaps, secrets []string
+	for _, volume := range volumes {
+		switch {
+		case volume.ConfigMap != nil:
+			configMaps = append(configMaps, volume.ConfigMap.Name)
+		case volume.Secret != nil:
+			secrets = append(secrets, volume.Secret.SecretName)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps = append(configMaps, source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					secrets = append(secrets, source.Secret.Name)
+				}
+			}
+		}
+	}
+	return configMaps, secrets
+}
+
+// getEnvReferencedConfigMapsAndSecrets returns the ConfigMaps/Secrets referenced by the envFrom and env of the containers.
+func getEnvReferencedConfigMapsAndSecrets(podSpec *corev1.PodSpec) ([]string, []string) {
+	var configMaps, secrets []string
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMaps = append(configMaps, envFrom.ConfigMapRef.Name)
+			}
+			if envFrom.SecretRef != nil {
+				secrets = append(secrets, envFrom.SecretRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps = append(configMaps, env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets = append(secrets, env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+	return configMaps, secrets
+}
+
+// isGeneratedEnvConfigMap checks whether the ConfigMap is the env ConfigMap generated by KubeBlocks for the component or the rsm.
+func isGeneratedEnvConfigMap(cm *corev1.ConfigMap) bool {
+	if cm.Labels[constant.AppConfigTypeLabelKey] == "kubeblocks-env" {
+		return true
+	}
+	if !strings.HasSuffix(cm.Name, "-env") {
+		return false
+	}
+	if owner := metav1.GetControllerOf(cm); owner != nil && owner.Kind == constant.RSMKind {
+		return true
+	}
+	return cm.Labels[constant.AppManagedByLabelKey] == constant.AppName
+}
+
+// getRestartOnChangeConfigMapsAndSecrets parses the restart-on-change annotation of the cluster,
+// the entries are in the form of [<kind>/]<name>, and the kind defaults to Secret if omitted.
+func getRestartOnChangeConfigMapsAndSecrets(cluster *appsv1alpha1.Cluster) ([]string, []string) {
+	var configMaps, secrets []string
+	for _, obj := range parseRestartOnChangeObjects(cluster) {
+		switch obj.kind {
+		case restartOnChangeConfigMapKind:
+			configMaps = append(configMaps, obj.name)
+		case restartOnChangeSecretKind:
+			secrets = append(secrets, obj.name)
+		}
+	}
+	return configMaps, secrets
+}
+
+const (
+	restartOnChangeConfigMapKind = "ConfigMap"
+	restartOnChangeSecretKind    = "Secret"
+)
+
+type restartOnChangeObject struct {
+	kind string
+	name string
+}
+
+func (o restartOnChangeObject) String() string {
+	return o.kind + "/" + o.name
+}
+
+func parseRestartOnChangeObjects(cluster *appsv1alpha1.Cluster) []restartOnChangeObject {
+	value, ok := cluster.Annotations[constant.RestartOnChangeAnnotationKey]
+	if !ok {
+		return nil
+	}
+	var objects []restartOnChangeObject
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		kind, name, found := strings.Cut(entry, "/")
+		if !found {
+			objects = append(objects, restartOnChangeObject{kind: restartOnChangeSecretKind, name: entry})
+			continue
+		}
+		switch strings.ToLower(kind) {
+		case "configmap", "configmaps", "cm":
+			objects = append(objects, restartOnChangeObject{kind: restartOnChangeConfigMapKind, name: name})
+		case "secret", "secrets":
+			objects = append(objects, restartOnChangeObject{kind: restartOnChangeSecretKind, name: name})
+		}
+	}
+	return objects
+}
+
+// getMountedObject gets the object from the DAG if it's going to be created or updated, otherwise from the cluster.
+func getMountedObject(transCtx *ClusterTransformContext, dag *graph.DAG, key client.ObjectKey, obj client.Object) (bool, error) {
+	for _, vertex := range dag.Vertices() {
+		v, _ := vertex.(*ictrltypes.LifecycleVertex)
+		if v.Action == nil || *v.Action == ictrltypes.DELETE || client.ObjectKeyFromObject(v.Obj) != key {
+			continue
+		}
+		switch o := obj.(type) {
+		case *corev1.ConfigMap:
+			if cm, ok := v.Obj.(*corev1.ConfigMap); ok {
+				cm.DeepCopyInto(o)
+				return true, nil
+			}
+		case *corev1.Secret:
+			if secret, ok := v.Obj.(*corev1.Secret); ok {
+				secret.DeepCopyInto(o)
+				return true, nil
+			}
+		}
+	}
+	if err := transCtx.Client.Get(transCtx.Context, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// applyMountedConfigChecksum records the checksum on the workload and returns whether it's changed.
+// The workloads created without the checksum record it as the baseline in their own annotations,
+// the pod template is only touched, and the pods are restarted, once the mounted objects change afterwards.
+func applyMountedConfigChecksum(rsm *workloads.ReplicatedStateMachine, checksum string) bool {
+	lastChecksum, ok := rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]
+	if !ok {
+		lastChecksum, ok = rsm.Annotations[constant.MountedConfigChecksumAnnotationKey]
+	}
+	if ok && lastChecksum == checksum {
+		return false
+	}
+	if ok {
+		setMountedConfigChecksum(&rsm.Spec.Template, checksum)
+	} else {
+		if rsm.Annotations == nil {
+			rsm.Annotations = map[string]string{}
+		}
+		rsm.Annotations[constant.MountedConfigChecksumAnnotationKey] = checksum
+	}
+	return true
+}
+
+func setMountedConfigChecksum(template *corev1.PodTemplateSpec, checksum string) {
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[constant.MountedConfigChecksumAnnotationKey] = checksum
+}
@@ -0,0 +1,293 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("component config checksum transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		compName           = "compName"
+		compDefName        = "compDefName"
+		secretName         = "test-cluster-compname-secret"
+	)
+
+	var (
+		transCtx    graph.TransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddComponent(compName, compDefName).
+			GetObject()
+		transCtx = &ClusterTransformContext{
+			Context: ctx,
+			Client:  k8sClient,
+			Logger:  logf.FromContext(ctx).WithValues("transformer-config-checksum-test", testCtx.DefaultNamespace),
+			Cluster: cluster,
+		}
+		transformer = &ComponentConfigChecksumTransformer{}
+	})
+
+	mockDAGWithSecret := func(secretData string) (*graph.DAG, *workloads.ReplicatedStateMachine) {
+		dag := graph.NewDAG()
+		root := ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: secretName},
+			StringData: map[string]string{"password": secretData},
+		}
+		ictrltypes.LifecycleObjectCreate(dag, secret, root)
+		rsm := &workloads.ReplicatedStateMachine{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: clusterName + "-" + compName},
+			Spec: workloads.ReplicatedStateMachineSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{
+							{
+								Name: "secret",
+								VolumeSource: corev1.VolumeSource{
+									Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		ictrltypes.LifecycleObjectCreate(dag, rsm, root)
+		return dag, rsm
+	}
+
+	Context("mounted config checksum", func() {
+		It("should bump the checksum once the secret changes", func() {
+			dag, rsm := mockDAGWithSecret("foo")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			checksum := rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]
+			Expect(checksum).ShouldNot(BeEmpty())
+
+			By("reconcile with the unchanged secret")
+			dag, rsm = mockDAGWithSecret("foo")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).Should(Equal(checksum))
+
+			By("reconcile with the changed secret")
+			dag, rsm = mockDAGWithSecret("bar")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).ShouldNot(Equal(checksum))
+		})
+
+		It("should bump the checksum once the secret listed in the restart-on-change annotation rotates", func() {
+			By("create the user-provided secret")
+			userSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: "user-provided-cert"},
+				StringData: map[string]string{"tls.crt": "foo"},
+			}
+			Expect(testCtx.Create(testCtx.Ctx, userSecret)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(testCtx.Ctx, userSecret))).Should(Succeed())
+			})
+			cluster.Annotations = map[string]string{constant.RestartOnChangeAnnotationKey: "secret/" + userSecret.Name}
+			Expect(indexRestartOnChangeObjects(cluster)).Should(ConsistOf("Secret/" + userSecret.Name))
+
+			dag, rsm := mockDAGWithSecret("foo")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			checksum := rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]
+			Expect(checksum).ShouldNot(BeEmpty())
+
+			By("rotate the user-provided secret")
+			Expect(testapps.ChangeObj(&testCtx, userSecret, func(secret *corev1.Secret) {
+				secret.StringData = map[string]string{"tls.crt": "bar"}
+			})).Should(Succeed())
+			Eventually(func(g Gomega) {
+				dag, rsm = mockDAGWithSecret("foo")
+				g.Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+				g.Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).ShouldNot(Equal(checksum))
+			}).Should(Succeed())
+		})
+
+		It("should bump the checksum once the secret referenced by the env changes", func() {
+			mockDAGWithEnvSecret := func(secretData string) (*graph.DAG, *workloads.ReplicatedStateMachine) {
+				dag, rsm := mockDAGWithSecret("foo")
+				envSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: "test-cluster-conn-credential"},
+					StringData: map[string]string{"password": secretData},
+				}
+				ictrltypes.LifecycleObjectCreate(dag, envSecret, nil)
+				envConfigMap := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testCtx.DefaultNamespace,
+						Name:      rsm.Name + "-env",
+						Labels:    map[string]string{constant.AppConfigTypeLabelKey: "kubeblocks-env"},
+					},
+					Data: map[string]string{"KB_REPLICA_COUNT": secretData},
+				}
+				ictrltypes.LifecycleObjectCreate(dag, envConfigMap, nil)
+				rsm.Spec.Template.Spec.Containers = []corev1.Container{{
+					Name: "mysql",
+					EnvFrom: []corev1.EnvFromSource{{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: envConfigMap.Name}},
+					}},
+					Env: []corev1.EnvVar{{
+						Name: "MYSQL_ROOT_PASSWORD",
+						ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: envSecret.Name},
+							Key:                  "password",
+						}},
+					}},
+				}}
+				return dag, rsm
+			}
+
+			dag, rsm := mockDAGWithEnvSecret("foo")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			checksum := rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]
+			Expect(checksum).ShouldNot(BeEmpty())
+
+			By("the env ConfigMap generated by KubeBlocks is excluded")
+			envConfigMaps, envSecrets := getEnvReferencedConfigMapsAndSecrets(&rsm.Spec.Template.Spec)
+			Expect(envConfigMaps).Should(Equal([]string{rsm.Name + "-env"}))
+			Expect(envSecrets).Should(Equal([]string{"test-cluster-conn-credential"}))
+			envConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: rsm.Name + "-env",
+				Labels: map[string]string{constant.AppConfigTypeLabelKey: "kubeblocks-env"}}}
+			Expect(isGeneratedEnvConfigMap(envConfigMap)).Should(BeTrue())
+
+			By("reconcile with the changed secret")
+			dag, rsm = mockDAGWithEnvSecret("bar")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).ShouldNot(Equal(checksum))
+		})
+
+		It("should record the baseline for the workloads created without the checksum", func() {
+			By("create the workload without the checksum")
+			runningRSM := testapps.NewRSMFactory(testCtx.DefaultNamespace, clusterName+"-"+compName, clusterName, compName).
+				SetReplicas(1).
+				AddContainer(corev1.Container{Name: testapps.DefaultNginxContainerName, Image: testapps.NginxImage}).
+				AddVolume(corev1.Volume{
+					Name:         "secret",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName}},
+				}).
+				Create(&testCtx).GetObject()
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(testCtx.Ctx, runningRSM))).Should(Succeed())
+			})
+
+			mockNoopDAG := func(secretData string) (*graph.DAG, *ictrltypes.LifecycleVertex) {
+				dag, _ := mockDAGWithSecret(secretData)
+				rsm := &workloads.ReplicatedStateMachine{}
+				Expect(k8sClient.Get(testCtx.Ctx, client.ObjectKeyFromObject(runningRSM), rsm)).Should(Succeed())
+				for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+					v, _ := vertex.(*ictrltypes.LifecycleVertex)
+					v.Obj = rsm
+					v.Action = ictrltypes.ActionNoopPtr()
+					return dag, v
+				}
+				return nil, nil
+			}
+
+			By("the baseline is recorded without restarting the pods")
+			dag, v := mockNoopDAG("foo")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(*v.Action).Should(Equal(ictrltypes.PATCH))
+			rsm, _ := v.Obj.(*workloads.ReplicatedStateMachine)
+			checksum := rsm.Annotations[constant.MountedConfigChecksumAnnotationKey]
+			Expect(checksum).ShouldNot(BeEmpty())
+			Expect(rsm.Spec.Template.Annotations).ShouldNot(HaveKey(constant.MountedConfigChecksumAnnotationKey))
+			Expect(testapps.ChangeObj(&testCtx, runningRSM, func(obj *workloads.ReplicatedStateMachine) {
+				obj.Annotations[constant.MountedConfigChecksumAnnotationKey] = checksum
+			})).Should(Succeed())
+
+			By("reconcile with the unchanged secret")
+			dag, v = mockNoopDAG("foo")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(*v.Action).Should(Equal(ictrltypes.NOOP))
+
+			By("reconcile with the changed secret, the pods are restarted")
+			dag, v = mockNoopDAG("bar")
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(*v.Action).Should(Equal(ictrltypes.PATCH))
+			rsm, _ = v.Obj.(*workloads.ReplicatedStateMachine)
+			Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).ShouldNot(BeEmpty())
+			Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).ShouldNot(Equal(checksum))
+
+			By("the workloads absent in the cluster don't stop checking the others")
+			dag, v = mockNoopDAG("baz")
+			root, err := ictrltypes.FindRootVertex(dag)
+			Expect(err).Should(Succeed())
+			absentRSM := &workloads.ReplicatedStateMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: clusterName + "-absent"},
+			}
+			ictrltypes.LifecycleObjectNoop(dag, absentRSM, root)
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(*v.Action).Should(Equal(ictrltypes.PATCH))
+		})
+
+		It("should record the baseline for the updated workloads without the checksum", func() {
+			mockUpdateDAG := func(secretData string, annotations, templateAnnotations map[string]string) *workloads.ReplicatedStateMachine {
+				dag, rsm := mockDAGWithSecret(secretData)
+				rsm.Annotations = annotations
+				rsm.Spec.Template.Annotations = templateAnnotations
+				for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+					vertex.(*ictrltypes.LifecycleVertex).Action = ictrltypes.ActionUpdatePtr()
+				}
+				Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+				return rsm
+			}
+
+			By("the baseline is recorded without touching the pod template")
+			rsm := mockUpdateDAG("foo", nil, nil)
+			checksum := rsm.Annotations[constant.MountedConfigChecksumAnnotationKey]
+			Expect(checksum).ShouldNot(BeEmpty())
+			Expect(rsm.Spec.Template.Annotations).ShouldNot(HaveKey(constant.MountedConfigChecksumAnnotationKey))
+
+			By("update with the unchanged secret")
+			baseline := map[string]string{constant.MountedConfigChecksumAnnotationKey: checksum}
+			rsm = mockUpdateDAG("foo", baseline, nil)
+			Expect(rsm.Spec.Template.Annotations).ShouldNot(HaveKey(constant.MountedConfigChecksumAnnotationKey))
+
+			By("update with the changed secret, the pods are restarted")
+			rsm = mockUpdateDAG("bar", baseline, nil)
+			Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).ShouldNot(BeEmpty())
+			Expect(rsm.Spec.Template.Annotations[constant.MountedConfigChecksumAnnotationKey]).ShouldNot(Equal(checksum))
+		})
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+// ComponentCredentialRotationTransformer rotates the password of the connection credential on the schedule set by
+// the components, by creating a RotateCredential OpsRequest which changes the password on the engine first, and then
+// switches the secret to the new password with the previous one kept until the grace period expires, so the clients
+// have a window to switch to the new one. The OpsRequest restarts the workloads referring to the secret to pick up
+// the new password, and their pods are rolled one by one following the update strategy of the workloads.
+// The password is rotated by the OpsRequest as well once its regeneration is requested, e.g. on the deletion of the
+// secret, which is recorded by the annotation apps.kubeblocks.io/credential-regenerate-at.
+type ComponentCredentialRotationTransformer struct{}
+
+var _ graph.Transformer = &ComponentCredentialRotationTransformer{}
+
+func (t *ComponentCredentialRotationTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	compSpec, period, gracePeriod, err := getCredentialRotationSchedule(cluster)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := client.ObjectKey{Namespace: cluster.Namespace, Name: component.GenerateConnCredential(cluster.Name)}
+	if err = transCtx.Client.Get(transCtx.Context, secretKey, secret); err != nil {
+		// the secret will be created by the ClusterCredentialTransformer.
+		return client.IgnoreNotFound(err)
+	}
+	regenerateAt, regenerating := getCredentialRegenerateAt(secret)
+	if compSpec == nil && !regenerating {
+		return nil
+	}
+
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	secretCopy := secret.DeepCopy()
+	if regenerating && !getCredentialRotatedAt(secret).Before(regenerateAt) {
+		// the password has been regenerated by the rotation.
+		delete(secret.Annotations, constant.CredentialRegenerateAtAnnotationKey)
+		regenerating = false
+	}
+	expireAt, hasPrevious := getPreviousCredentialExpireAt(secret)
+	if hasPrevious && !now.Before(expireAt) {
+		expirePreviousCredential(secret)
+		hasPrevious = false
+	}
+	if !reflect.DeepEqual(secretCopy, secret) {
+		ictrltypes.LifecycleObjectPatch(dag, secret, secretCopy, root)
+	}
+
+	if regenerating {
+		return t.regenerateCredential(transCtx, dag, root, compSpec, regenerateAt)
+	}
+	if compSpec == nil {
+		return nil
+	}
+	next := getCredentialRotatedAt(secret).Add(period)
+	if !now.Before(next) {
+		rotating, err := t.rotateCredential(transCtx, dag, root, compSpec, next, gracePeriod,
+			fmt.Sprintf("rotating the password of the connection credential on the schedule of component %s", compSpec.Name))
+		if err != nil {
+			return err
+		}
+		if rotating {
+			// check the progress of the OpsRequest later.
+			next = now.Add(time.Minute)
+		} else {
+			// the rotation of this period has been attempted but failed, retry it on the next period.
+			next = next.Add(period)
+		}
+	}
+
+	// reconcile again on the next rotation or the expiration of the previous password, whichever comes first.
+	if hasPrevious && expireAt.Before(next) {
+		next = expireAt
+	}
+	return intctrlutil.NewDelayedRequeueError(next.Sub(now), "waiting for the next credential rotation")
+}
+
+// regenerateCredential rotates the password requested to be regenerated at regenerateAt without a grace period, as
+// the previous one is not trusted anymore. The password is changed on the component rotating the credential on
+// schedule, or the component the credential is built for.
+func (t *ComponentCredentialRotationTransformer) regenerateCredential(transCtx *ClusterTransformContext,
+	dag *graph.DAG,
+	root *ictrltypes.LifecycleVertex,
+	compSpec *appsv1alpha1.ClusterComponentSpec,
+	regenerateAt time.Time) error {
+	if compSpec == nil {
+		compSpec = getConnCredentialComponent(transCtx.Cluster, transCtx.ClusterDef)
+	}
+	if compSpec == nil {
+		return nil
+	}
+	rotating, err := t.rotateCredential(transCtx, dag, root, compSpec, regenerateAt, 0,
+		"regenerating the password of the connection credential on its recreation")
+	if err != nil || !rotating {
+		// the failed regeneration is recorded by the OpsRequest, rotate the credential manually to retry it.
+		return err
+	}
+	return intctrlutil.NewDelayedRequeueError(time.Minute, "waiting for the regeneration of the credential")
+}
+
+// rotateCredential creates the RotateCredential OpsRequest for the rotation due at dueAt, unless one of the cluster
+// is in progress. It returns false if the rotation has been attempted since dueAt and failed, the failed OpsRequest
+// is kept for the troubleshooting.
+func (t *ComponentCredentialRotationTransformer) rotateCredential(transCtx *ClusterTransformContext,
+	dag *graph.DAG,
+	root *ictrltypes.LifecycleVertex,
+	compSpec *appsv1alpha1.ClusterComponentSpec,
+	dueAt time.Time,
+	gracePeriod time.Duration,
+	message string) (bool, error) {
+	cluster := transCtx.Cluster
+	opsList := &appsv1alpha1.OpsRequestList{}
+	if err := transCtx.Client.List(transCtx.Context, opsList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			constant.AppInstanceLabelKey:    cluster.Name,
+			constant.OpsRequestTypeLabelKey: string(appsv1alpha1.RotateCredentialType),
+		}); err != nil {
+		return false, err
+	}
+	for _, ops := range opsList.Items {
+		if !ops.IsComplete() {
+			return true, nil
+		}
+		if ops.Status.Phase != appsv1alpha1.OpsSucceedPhase && !ops.CreationTimestamp.Time.Before(dueAt) {
+			return false, nil
+		}
+	}
+	// the cluster is not ready for the OpsRequest, rotate it once the cluster is running.
+	if cluster.Status.Phase != appsv1alpha1.RunningClusterPhase {
+		return true, nil
+	}
+
+	var statement string
+	if compSpec.CredentialRotation != nil {
+		statement = compSpec.CredentialRotation.Statement
+	}
+	ops := &appsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    cluster.Namespace,
+			GenerateName: fmt.Sprintf("%s-credential-rotation-", cluster.Name),
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    cluster.Name,
+				constant.OpsRequestTypeLabelKey: string(appsv1alpha1.RotateCredentialType),
+			},
+		},
+		Spec: appsv1alpha1.OpsRequestSpec{
+			ClusterRef: cluster.Name,
+			Type:       appsv1alpha1.RotateCredentialType,
+			RotateCredential: &appsv1alpha1.RotateCredential{
+				ComponentOps: appsv1alpha1.ComponentOps{ComponentName: compSpec.Name},
+				Statement:    statement,
+				GracePeriod:  fmt.Sprintf("%dm", int64(gracePeriod/time.Minute)),
+			},
+		},
+	}
+	ictrltypes.LifecycleObjectCreate(dag, ops, root)
+	transCtx.EventRecorder.Event(cluster, corev1.EventTypeNormal, "CredentialRotating", message)
+	return true, nil
+}
+
+// getCredentialRotationSchedule returns the component with the shortest rotation period and its schedule,
+// the component is nil if none of the components rotates the credential.
+func getCredentialRotationSchedule(cluster *appsv1alpha1.Cluster) (*appsv1alpha1.ClusterComponentSpec, time.Duration, time.Duration, error) {
+	var (
+		compSpec    *appsv1alpha1.ClusterComponentSpec
+		period      time.Duration
+		gracePeriod time.Duration
+	)
+	for i, spec := range cluster.Spec.ComponentSpecs {
+		if spec.CredentialRotation == nil {
+			continue
+		}
+		p, err := spec.CredentialRotation.GetPeriod()
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid credential rotation of component %s: %s", spec.Name, err.Error())
+		}
+		g, err := spec.CredentialRotation.GetGracePeriod()
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid credential rotation of component %s: %s", spec.Name, err.Error())
+		}
+		if compSpec == nil || p < period {
+			compSpec, period, gracePeriod = &cluster.Spec.ComponentSpecs[i], p, g
+		}
+	}
+	return compSpec, period, gracePeriod, nil
+}
+
+// getCredentialRotatedAt returns the time of the last rotation, it defaults to the creation time of the secret.
+func getCredentialRotatedAt(secret *corev1.Secret) time.Time {
+	if value, ok := secret.Annotations[constant.CredentialRotatedAtAnnotationKey]; ok {
+		if rotatedAt, err := time.Parse(time.RFC3339, value); err == nil {
+			return rotatedAt
+		}
+	}
+	return secret.CreationTimestamp.Time
+}
+
+// getCredentialRegenerateAt returns the time the regeneration of the password is requested at, and whether it's requested.
+func getCredentialRegenerateAt(secret *corev1.Secret) (time.Time, bool) {
+	value, ok := secret.Annotations[constant.CredentialRegenerateAtAnnotationKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	regenerateAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// regenerate the password requested without a valid time right away.
+		return secret.CreationTimestamp.Time, true
+	}
+	return regenerateAt, true
+}
+
+// getPreviousCredentialExpireAt returns the expiration time of the previous password, and whether it's kept in the secret.
+func getPreviousCredentialExpireAt(secret *corev1.Secret) (time.Time, bool) {
+	if _, ok := secret.Data[constant.AccountPreviousPasswdForSecret]; !ok {
+		return time.Time{}, false
+	}
+	expireAt, err := time.Parse(time.RFC3339, secret.Annotations[constant.PreviousCredentialExpireAtAnnotationKey])
+	if err != nil {
+		// expire the previous password without a valid expiration time right away.
+		return time.Time{}, true
+	}
+	return expireAt, true
+}
+
+func expirePreviousCredential(secret *corev1.Secret) {
+	delete(secret.Data, constant.AccountPreviousPasswdForSecret)
+	delete(secret.Annotations, constant.PreviousCredentialExpireAtAnnotationKey)
+}
@@ -0,0 +1,296 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("component credential rotation transformer test.", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		oldPassword        = "old-password"
+	)
+
+	var (
+		ctx         context.Context
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+		recorder    *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterDef := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, mysqlCompDefName).
+			GetObject()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			SetCredentialRotation("1h").
+			GetObject()
+		recorder = record.NewFakeRecorder(10)
+		transCtx = &ClusterTransformContext{
+			Context:       ctx,
+			Client:        k8sClient,
+			EventRecorder: recorder,
+			Logger:        logf.FromContext(ctx).WithValues("transformer-credential-rotation-test", testCtx.DefaultNamespace),
+			Cluster:       cluster,
+			ClusterDef:    clusterDef,
+		}
+		transformer = &ComponentCredentialRotationTransformer{}
+	})
+
+	createObj := func(obj client.Object) {
+		Expect(k8sClient.Create(ctx, obj)).Should(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, obj))).Should(Succeed())
+		})
+	}
+
+	mockConnCredential := func(annotations map[string]string, data map[string]string) {
+		createObj(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   testCtx.DefaultNamespace,
+				Name:        component.GenerateConnCredential(cluster.Name),
+				Annotations: annotations,
+			},
+			StringData: data,
+		})
+	}
+
+	// mockDAG creates the workload referring to the connection credential by env, and puts it into the DAG unchanged.
+	mockDAG := func() (*graph.DAG, *ictrltypes.LifecycleVertex) {
+		rsm := &workloads.ReplicatedStateMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      cluster.Name + "-" + mysqlCompName,
+				Labels:    map[string]string{constant.KBAppComponentLabelKey: mysqlCompName},
+			},
+			Spec: workloads.ReplicatedStateMachineSpec{
+				ServiceName: cluster.Name + "-" + mysqlCompName + "-headless",
+				Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{constant.KBAppComponentLabelKey: mysqlCompName}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{constant.KBAppComponentLabelKey: mysqlCompName}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:  testapps.DefaultMySQLContainerName,
+							Image: testapps.ApeCloudMySQLImage,
+							Env: []corev1.EnvVar{{
+								Name: "MYSQL_ROOT_PASSWORD",
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: component.GenerateConnCredential(cluster.Name)},
+										Key:                  constant.AccountPasswdForSecret,
+									},
+								},
+							}},
+						}},
+					},
+				},
+			},
+		}
+		createObj(rsm.DeepCopy())
+		dag := graph.NewDAG()
+		root := ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		return dag, ictrltypes.LifecycleObjectNoop(dag, rsm, root)
+	}
+
+	findSecretPatch := func(dag *graph.DAG) *corev1.Secret {
+		for _, vertex := range ictrltypes.FindAll[*corev1.Secret](dag) {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			if *v.Action == ictrltypes.PATCH {
+				secret, _ := v.Obj.(*corev1.Secret)
+				return secret
+			}
+		}
+		return nil
+	}
+
+	Context("credential rotation", func() {
+		findOpsCreate := func(dag *graph.DAG) *appsv1alpha1.OpsRequest {
+			for _, vertex := range ictrltypes.FindAll[*appsv1alpha1.OpsRequest](dag) {
+				v, _ := vertex.(*ictrltypes.LifecycleVertex)
+				if *v.Action == ictrltypes.CREATE {
+					ops, _ := v.Obj.(*appsv1alpha1.OpsRequest)
+					return ops
+				}
+			}
+			return nil
+		}
+
+		mockRotateCredentialOps := func(phase appsv1alpha1.OpsPhase) {
+			ops := testapps.NewOpsRequestObj("rotate-credential-"+testCtx.GetRandomStr(), testCtx.DefaultNamespace,
+				cluster.Name, appsv1alpha1.RotateCredentialType)
+			ops.Spec.RotateCredential = &appsv1alpha1.RotateCredential{ComponentOps: appsv1alpha1.ComponentOps{ComponentName: mysqlCompName}}
+			createObj(ops)
+			Expect(testapps.ChangeObjStatus(&testCtx, ops, func() { ops.Status.Phase = phase })).Should(Succeed())
+		}
+
+		It("should rotate the password by a RotateCredential OpsRequest once the period elapses", func() {
+			lastRotatedAt := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+			mockConnCredential(map[string]string{constant.CredentialRotatedAtAnnotationKey: lastRotatedAt},
+				map[string]string{constant.AccountNameForSecret: "root", constant.AccountPasswdForSecret: oldPassword})
+			dag, rsmVertex := mockDAG()
+			cluster.Status.Phase = appsv1alpha1.RunningClusterPhase
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+
+			By("check the OpsRequest changing the password on the engine is created")
+			ops := findOpsCreate(dag)
+			Expect(ops).ShouldNot(BeNil())
+			Expect(ops.Spec.Type).Should(Equal(appsv1alpha1.RotateCredentialType))
+			Expect(ops.Spec.RotateCredential.ComponentName).Should(Equal(mysqlCompName))
+			Expect(ops.Spec.RotateCredential.GracePeriod).Should(Equal("60m"))
+			Expect(ops.Labels).Should(HaveKeyWithValue(constant.OpsRequestTypeLabelKey, string(appsv1alpha1.RotateCredentialType)))
+			Expect(recorder.Events).Should(Receive(ContainSubstring("CredentialRotating")))
+
+			By("check the secret and the workload are left to the OpsRequest")
+			Expect(findSecretPatch(dag)).Should(BeNil())
+			Expect(*rsmVertex.Action).Should(Equal(ictrltypes.NOOP))
+		})
+
+		It("should not create another OpsRequest while the rotation is in progress", func() {
+			mockConnCredential(map[string]string{constant.CredentialRotatedAtAnnotationKey: time.Now().Add(-2 * time.Hour).Format(time.RFC3339)},
+				map[string]string{constant.AccountNameForSecret: "root", constant.AccountPasswdForSecret: oldPassword})
+			cluster.Status.Phase = appsv1alpha1.RunningClusterPhase
+			mockRotateCredentialOps(appsv1alpha1.OpsRunningPhase)
+			dag := graph.NewDAG()
+			ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(findOpsCreate(dag)).Should(BeNil())
+		})
+
+		It("should retry the failed rotation on the next period", func() {
+			mockConnCredential(map[string]string{constant.CredentialRotatedAtAnnotationKey: time.Now().Add(-90 * time.Minute).Format(time.RFC3339)},
+				map[string]string{constant.AccountNameForSecret: "root", constant.AccountPasswdForSecret: oldPassword})
+			cluster.Status.Phase = appsv1alpha1.RunningClusterPhase
+			mockRotateCredentialOps(appsv1alpha1.OpsFailedPhase)
+
+			dag := graph.NewDAG()
+			ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(findOpsCreate(dag)).Should(BeNil())
+			// the next period is due in 30 minutes.
+			Expect(err.(intctrlutil.RequeueError).RequeueAfter()).Should(BeNumerically("~", 30*time.Minute, time.Minute))
+		})
+
+		It("should keep both passwords valid within the grace period", func() {
+			mockConnCredential(map[string]string{
+				constant.CredentialRotatedAtAnnotationKey:        time.Now().Add(-time.Minute).Format(time.RFC3339),
+				constant.PreviousCredentialExpireAtAnnotationKey: time.Now().Add(time.Minute).Format(time.RFC3339),
+			}, map[string]string{
+				constant.AccountPasswdForSecret:         "new-password",
+				constant.AccountPreviousPasswdForSecret: oldPassword,
+			})
+			dag := graph.NewDAG()
+			ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			// requeue on the expiration of the previous password rather than the next rotation.
+			Expect(err.(intctrlutil.RequeueError).RequeueAfter()).Should(BeNumerically("<=", time.Minute))
+			Expect(findSecretPatch(dag)).Should(BeNil())
+		})
+
+		It("should drop the previous password once the grace period expires", func() {
+			mockConnCredential(map[string]string{
+				constant.CredentialRotatedAtAnnotationKey:        time.Now().Add(-10 * time.Minute).Format(time.RFC3339),
+				constant.PreviousCredentialExpireAtAnnotationKey: time.Now().Add(-time.Minute).Format(time.RFC3339),
+			}, map[string]string{
+				constant.AccountPasswdForSecret:         "new-password",
+				constant.AccountPreviousPasswdForSecret: oldPassword,
+			})
+			dag := graph.NewDAG()
+			ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			secret := findSecretPatch(dag)
+			Expect(secret).ShouldNot(BeNil())
+			Expect(string(secret.Data[constant.AccountPasswdForSecret])).Should(Equal("new-password"))
+			Expect(secret.Data).ShouldNot(HaveKey(constant.AccountPreviousPasswdForSecret))
+			Expect(secret.Annotations).ShouldNot(HaveKey(constant.PreviousCredentialExpireAtAnnotationKey))
+		})
+
+		It("should regenerate the password by a RotateCredential OpsRequest without the grace period", func() {
+			cluster.Spec.ComponentSpecs[0].CredentialRotation = nil
+			mockConnCredential(map[string]string{
+				constant.CredentialRegenerateAtAnnotationKey: time.Now().Add(-time.Minute).Format(time.RFC3339),
+			}, map[string]string{constant.AccountNameForSecret: "root", constant.AccountPasswdForSecret: oldPassword})
+			dag, rsmVertex := mockDAG()
+			cluster.Status.Phase = appsv1alpha1.RunningClusterPhase
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			ops := findOpsCreate(dag)
+			Expect(ops).ShouldNot(BeNil())
+			Expect(ops.Spec.RotateCredential.ComponentName).Should(Equal(mysqlCompName))
+			Expect(ops.Spec.RotateCredential.GracePeriod).Should(Equal("0m"))
+			Expect(recorder.Events).Should(Receive(ContainSubstring("CredentialRotating")))
+
+			By("check the workload is left to the OpsRequest")
+			Expect(findSecretPatch(dag)).Should(BeNil())
+			Expect(*rsmVertex.Action).Should(Equal(ictrltypes.NOOP))
+		})
+
+		It("should clear the regeneration request once the password is rotated", func() {
+			cluster.Spec.ComponentSpecs[0].CredentialRotation = nil
+			mockConnCredential(map[string]string{
+				constant.CredentialRegenerateAtAnnotationKey: time.Now().Add(-10 * time.Minute).Format(time.RFC3339),
+				constant.CredentialRotatedAtAnnotationKey:    time.Now().Add(-time.Minute).Format(time.RFC3339),
+			}, map[string]string{constant.AccountNameForSecret: "root", constant.AccountPasswdForSecret: "new-password"})
+			dag := graph.NewDAG()
+			ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(findOpsCreate(dag)).Should(BeNil())
+			secret := findSecretPatch(dag)
+			Expect(secret).ShouldNot(BeNil())
+			Expect(secret.Annotations).ShouldNot(HaveKey(constant.CredentialRegenerateAtAnnotationKey))
+		})
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+)
+
+// ComponentMonitorTransformer reconciles the ServiceMonitor or PodMonitor of prometheus-operator for the components
+// enabling monitor with a monitorResource, the resource of the other kind is deleted once the kind is switched,
+// and both are deleted once monitor is disabled. The resources are handled as unstructured objects, so the
+// prometheus-operator CRDs are required only by the clusters requesting them.
+type ComponentMonitorTransformer struct{}
+
+var _ graph.Transformer = &ComponentMonitorTransformer{}
+
+var monitorResourceKinds = []appsv1alpha1.MonitorResourceKind{
+	appsv1alpha1.ServiceMonitorKind,
+	appsv1alpha1.PodMonitorKind,
+}
+
+func (t *ComponentMonitorTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return err
+	}
+	for i := range cluster.Spec.ComponentSpecs {
+		if err = t.reconcileMonitorResource(transCtx, dag, root, &cluster.Spec.ComponentSpecs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ComponentMonitorTransformer) reconcileMonitorResource(transCtx *ClusterTransformContext, dag *graph.DAG,
+	root *ictrltypes.LifecycleVertex, compSpec *appsv1alpha1.ClusterComponentSpec) error {
+	var proto *unstructured.Unstructured
+	if len(compSpec.MonitorResource) > 0 {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef != nil {
+			// the builtIn monitor is scraped by the agent, there is no exporter to scrape.
+			if monitor := component.GetMonitorConfig(compDef, compSpec); monitor.Enable && !monitor.BuiltIn {
+				proto = factory.BuildMonitorResource(transCtx.Cluster, compSpec, monitor)
+			}
+		}
+	}
+
+	for _, kind := range monitorResourceKinds {
+		desired := proto != nil && proto.GetKind() == string(kind)
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(factory.MonitorResourceGVK(kind))
+		key := types.NamespacedName{Namespace: transCtx.Cluster.Namespace, Name: fmt.Sprintf("%s-%s", transCtx.Cluster.Name, compSpec.Name)}
+		err := transCtx.Client.Get(transCtx.Context, key, obj)
+		switch {
+		case meta.IsNoMatchError(err):
+			if desired {
+				return fmt.Errorf("the %s of component %s requires the CRDs of prometheus-operator: %w", kind, compSpec.Name, err)
+			}
+		case apierrors.IsNotFound(err):
+			if desired {
+				ictrltypes.LifecycleObjectCreate(dag, proto, root)
+			}
+		case err != nil:
+			return err
+		case !desired:
+			ictrltypes.LifecycleObjectDelete(dag, obj, root)
+		default:
+			objCopy := obj.DeepCopy()
+			labels := objCopy.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			mergeMap(labels, proto.GetLabels())
+			objCopy.SetLabels(labels)
+			objCopy.Object["spec"] = proto.Object["spec"]
+			if !reflect.DeepEqual(obj, objCopy) {
+				ictrltypes.LifecycleObjectUpdate(dag, objCopy, root)
+			}
+		}
+	}
+	return nil
+}
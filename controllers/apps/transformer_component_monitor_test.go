@@ -0,0 +1,256 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	roclient "github.com/apecloud/kubeblocks/internal/controller/client"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+// fakeMonitorReader serves the monitor resources from memory, the prometheus-operator CRDs are not installed in the test env.
+type fakeMonitorReader struct {
+	roclient.ReadonlyClient
+	crdMissing bool
+	objects    map[string]*unstructured.Unstructured
+}
+
+func (r *fakeMonitorReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return r.ReadonlyClient.Get(ctx, key, obj, opts...)
+	}
+	gvk := u.GroupVersionKind()
+	if r.crdMissing {
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+	stored, ok := r.objects[gvk.Kind+"/"+key.Name]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, key.Name)
+	}
+	stored.DeepCopyInto(u)
+	return nil
+}
+
+var _ = Describe("component monitor transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		scrapePort         = 9104
+	)
+
+	var (
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		reader      *fakeMonitorReader
+		clusterDef  *appsv1alpha1.ClusterDefinition
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		clusterDef = testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, mysqlCompDefName).
+			GetObject()
+		clusterDef.Spec.ComponentDefs[0].Monitor = &appsv1alpha1.MonitorConfig{
+			Exporter: &appsv1alpha1.ExporterConfig{ScrapePort: intstr.FromInt(scrapePort), ScrapePath: "/metrics"},
+		}
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			SetMonitor(true).
+			SetMonitorResource(appsv1alpha1.ServiceMonitorKind).
+			GetObject()
+		reader = &fakeMonitorReader{ReadonlyClient: k8sClient, objects: map[string]*unstructured.Unstructured{}}
+		transCtx = &ClusterTransformContext{
+			Context:    ctx,
+			Client:     reader,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-monitor-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+		transformer = &ComponentMonitorTransformer{}
+	})
+
+	mockDAG := func() *graph.DAG {
+		dag := graph.NewDAG()
+		ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		return dag
+	}
+
+	findMonitorResources := func(dag *graph.DAG, action ictrltypes.LifecycleAction) map[string]*unstructured.Unstructured {
+		objs := make(map[string]*unstructured.Unstructured)
+		for _, vertex := range ictrltypes.FindAll[*unstructured.Unstructured](dag) {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			if *v.Action == action {
+				obj, _ := v.Obj.(*unstructured.Unstructured)
+				objs[obj.GetKind()] = obj
+			}
+		}
+		return objs
+	}
+
+	monitorConfig := &component.MonitorConfig{Enable: true, ScrapePort: scrapePort, ScrapePath: "/metrics"}
+
+	storeMonitorResource := func(obj *unstructured.Unstructured) {
+		reader.objects[obj.GetKind()+"/"+obj.GetName()] = obj
+	}
+
+	Context("monitor resources", func() {
+		It("should create the ServiceMonitor scraping the headless service", func() {
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			created := findMonitorResources(dag, ictrltypes.CREATE)
+			Expect(created).Should(HaveLen(1))
+			obj := created[string(appsv1alpha1.ServiceMonitorKind)]
+			Expect(obj).ShouldNot(BeNil())
+			Expect(obj.GetAPIVersion()).Should(Equal("monitoring.coreos.com/v1"))
+			Expect(obj.GetName()).Should(Equal(clusterName + "-" + mysqlCompName))
+
+			matchLabels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+			Expect(matchLabels).Should(HaveKeyWithValue(constant.AppInstanceLabelKey, clusterName))
+			Expect(matchLabels).Should(HaveKeyWithValue(constant.KBAppComponentLabelKey, mysqlCompName))
+			endpoints, _, _ := unstructured.NestedSlice(obj.Object, "spec", "endpoints")
+			Expect(endpoints).Should(HaveLen(1))
+			endpoint := endpoints[0].(map[string]interface{})
+			Expect(endpoint).Should(HaveKeyWithValue("targetPort", int64(scrapePort)))
+			Expect(endpoint).Should(HaveKeyWithValue("path", "/metrics"))
+			Expect(endpoint["relabelings"]).Should(ContainElement(HaveKeyWithValue("regex", clusterName+"-"+mysqlCompName+"-headless")))
+		})
+
+		It("should apply the scrape interval and the relabeling rules to the ServiceMonitor", func() {
+			relabel := appsv1alpha1.MonitorRelabelConfig{
+				SourceLabels: []string{"__meta_kubernetes_pod_label_" + constant.RoleLabelKey},
+				TargetLabel:  "role",
+			}
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDefName, clusterVersionName).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				SetMonitor(true).
+				SetMonitorResource(appsv1alpha1.ServiceMonitorKind).
+				SetMonitorScrapeInterval("15s").
+				AddMonitorRelabel(relabel).
+				GetObject()
+			transCtx.Cluster = cluster
+
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			obj := findMonitorResources(dag, ictrltypes.CREATE)[string(appsv1alpha1.ServiceMonitorKind)]
+			Expect(obj).ShouldNot(BeNil())
+			endpoints, _, _ := unstructured.NestedSlice(obj.Object, "spec", "endpoints")
+			Expect(endpoints).Should(HaveLen(1))
+			endpoint := endpoints[0].(map[string]interface{})
+			Expect(endpoint).Should(HaveKeyWithValue("interval", "15s"))
+			relabelings := endpoint["relabelings"].([]interface{})
+			Expect(relabelings).Should(HaveLen(2))
+			By("the headless service is kept first, the rules are appended in order")
+			Expect(relabelings[0]).Should(HaveKeyWithValue("action", "keep"))
+			Expect(relabelings[1]).Should(HaveKeyWithValue("targetLabel", "role"))
+			Expect(relabelings[1]).Should(HaveKeyWithValue("sourceLabels", ConsistOf(relabel.SourceLabels[0])))
+
+			By("the ServiceMonitor is updated once the interval is changed")
+			storeMonitorResource(obj)
+			cluster.Spec.ComponentSpecs[0].MonitorScrapeInterval = "1m"
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			updated := findMonitorResources(dag, ictrltypes.UPDATE)[string(appsv1alpha1.ServiceMonitorKind)]
+			Expect(updated).ShouldNot(BeNil())
+			endpoints, _, _ = unstructured.NestedSlice(updated.Object, "spec", "endpoints")
+			Expect(endpoints[0]).Should(HaveKeyWithValue("interval", "1m"))
+		})
+
+		It("should create the PodMonitor scraping the pods directly", func() {
+			cluster.Spec.ComponentSpecs[0].MonitorResource = appsv1alpha1.PodMonitorKind
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			created := findMonitorResources(dag, ictrltypes.CREATE)
+			Expect(created).Should(HaveLen(1))
+			obj := created[string(appsv1alpha1.PodMonitorKind)]
+			Expect(obj).ShouldNot(BeNil())
+
+			matchLabels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+			Expect(matchLabels).Should(HaveKeyWithValue(constant.KBAppComponentLabelKey, mysqlCompName))
+			endpoints, _, _ := unstructured.NestedSlice(obj.Object, "spec", "podMetricsEndpoints")
+			Expect(endpoints).Should(HaveLen(1))
+			Expect(endpoints[0]).Should(HaveKeyWithValue("targetPort", int64(scrapePort)))
+		})
+
+		It("should replace the ServiceMonitor with the PodMonitor once the kind is switched", func() {
+			storeMonitorResource(factory.BuildMonitorResource(cluster, &cluster.Spec.ComponentSpecs[0],
+				monitorConfig))
+
+			By("the ServiceMonitor unchanged is kept")
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(ictrltypes.FindAll[*unstructured.Unstructured](dag)).Should(BeEmpty())
+
+			By("switch to the PodMonitor")
+			cluster.Spec.ComponentSpecs[0].MonitorResource = appsv1alpha1.PodMonitorKind
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(findMonitorResources(dag, ictrltypes.CREATE)).Should(HaveKey(string(appsv1alpha1.PodMonitorKind)))
+			Expect(findMonitorResources(dag, ictrltypes.DELETE)).Should(HaveKey(string(appsv1alpha1.ServiceMonitorKind)))
+		})
+
+		It("should remove the monitor resources once monitor is disabled", func() {
+			cluster.Spec.ComponentSpecs[0].MonitorResource = appsv1alpha1.PodMonitorKind
+			storeMonitorResource(factory.BuildMonitorResource(cluster, &cluster.Spec.ComponentSpecs[0],
+				monitorConfig))
+
+			cluster.Spec.ComponentSpecs[0].Monitor = false
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			deleted := findMonitorResources(dag, ictrltypes.DELETE)
+			Expect(deleted).Should(HaveLen(1))
+			Expect(deleted).Should(HaveKey(string(appsv1alpha1.PodMonitorKind)))
+		})
+
+		It("should fail if the prometheus-operator CRDs are missing only when a monitor resource is requested", func() {
+			reader.crdMissing = true
+			Expect(transformer.Transform(transCtx, mockDAG())).ShouldNot(Succeed())
+
+			cluster.Spec.ComponentSpecs[0].MonitorResource = ""
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(ictrltypes.FindAll[*unstructured.Unstructured](dag)).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,128 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"reflect"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+)
+
+// ComponentRoleServiceTransformer reconciles a service for each role of the components enabling roleServices,
+// in addition to the default service. The role services select the pods by the role label, so they follow
+// the role changes of the pods, e.g. a failover. The role services are deleted once roleServices is disabled.
+type ComponentRoleServiceTransformer struct{}
+
+var _ graph.Transformer = &ComponentRoleServiceTransformer{}
+
+func (t *ComponentRoleServiceTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	root, err := ictrltypes.FindRootVertex(dag)
+	if err != nil {
+		return err
+	}
+	for i := range cluster.Spec.ComponentSpecs {
+		if err = t.reconcileRoleServices(transCtx, dag, root, &cluster.Spec.ComponentSpecs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ComponentRoleServiceTransformer) reconcileRoleServices(transCtx *ClusterTransformContext, dag *graph.DAG,
+	root *ictrltypes.LifecycleVertex, compSpec *appsv1alpha1.ClusterComponentSpec) error {
+	cluster := transCtx.Cluster
+	protoServices := make(map[string]*corev1.Service)
+	if compSpec.RoleServices {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef != nil && compDef.Service != nil {
+			for _, role := range compDef.GetRoles() {
+				svc := factory.BuildRoleService(cluster, compDef, compSpec.Name, role)
+				protoServices[svc.Name] = svc
+			}
+		}
+	}
+
+	services := &corev1.ServiceList{}
+	if err := transCtx.Client.List(transCtx.Context, services, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			constant.AppInstanceLabelKey:    cluster.Name,
+			constant.KBAppComponentLabelKey: compSpec.Name,
+		}, client.HasLabels{constant.RoleLabelKey}); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		svc := &services.Items[i]
+		proto, ok := protoServices[svc.Name]
+		if !ok {
+			ictrltypes.LifecycleObjectDelete(dag, svc, root)
+			continue
+		}
+		delete(protoServices, svc.Name)
+
+		svcCopy := svc.DeepCopy()
+		if svcCopy.Labels == nil {
+			svcCopy.Labels = map[string]string{}
+		}
+		maps.Copy(svcCopy.Labels, proto.Labels)
+		svcCopy.Spec.Selector = proto.Spec.Selector
+		svcCopy.Spec.Ports = defaultServicePorts(proto.Spec.Ports)
+		if !reflect.DeepEqual(svc, svcCopy) {
+			ictrltypes.LifecycleObjectUpdate(dag, svcCopy, root)
+		}
+	}
+
+	names := maps.Keys(protoServices)
+	slices.Sort(names)
+	for _, name := range names {
+		ictrltypes.LifecycleObjectCreate(dag, protoServices[name], root)
+	}
+	return nil
+}
+
+// defaultServicePorts fills the defaults of the service ports as the API server does,
+// so the role services are not updated repeatedly.
+func defaultServicePorts(ports []corev1.ServicePort) []corev1.ServicePort {
+	ports = slices.Clone(ports)
+	for i := range ports {
+		if ports[i].Protocol == "" {
+			ports[i].Protocol = corev1.ProtocolTCP
+		}
+		if ports[i].TargetPort == (intstr.IntOrString{}) {
+			ports[i].TargetPort = intstr.FromInt(int(ports[i].Port))
+		}
+	}
+	return ports
+}
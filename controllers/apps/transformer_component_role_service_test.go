@@ -0,0 +1,231 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/factory"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("component role service transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+		redisCompName      = "redis"
+		redisCompDefName   = "redis"
+		nginxCompName      = "nginx"
+		nginxCompDefName   = "nginx"
+	)
+
+	var (
+		ctx         context.Context
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		clusterDef  *appsv1alpha1.ClusterDefinition
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterDef = testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, mysqlCompDefName).
+			AddComponentDef(testapps.ReplicationRedisComponent, redisCompDefName).
+			AddComponentDef(testapps.StatelessNginxComponent, nginxCompDefName).
+			GetObject()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddComponent(mysqlCompName, mysqlCompDefName).
+			SetRoleServices(true).
+			AddComponent(redisCompName, redisCompDefName).
+			SetRoleServices(true).
+			AddComponent(nginxCompName, nginxCompDefName).
+			SetRoleServices(true).
+			GetObject()
+		transCtx = &ClusterTransformContext{
+			Context:    ctx,
+			Client:     k8sClient,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-role-service-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+		transformer = &ComponentRoleServiceTransformer{}
+	})
+
+	mockDAG := func() *graph.DAG {
+		dag := graph.NewDAG()
+		ictrltypes.LifecycleObjectCreate(dag, cluster, nil)
+		return dag
+	}
+
+	findServices := func(dag *graph.DAG, action ictrltypes.LifecycleAction) map[string]*corev1.Service {
+		services := make(map[string]*corev1.Service)
+		for _, vertex := range ictrltypes.FindAll[*corev1.Service](dag) {
+			v, _ := vertex.(*ictrltypes.LifecycleVertex)
+			if *v.Action == action {
+				svc, _ := v.Obj.(*corev1.Service)
+				services[svc.Name] = svc
+			}
+		}
+		return services
+	}
+
+	roleServiceName := func(compName, role string) string {
+		return component.GenerateRoleServiceName(clusterName, compName, role)
+	}
+
+	createRoleService := func(compName, compDefName, role string) *corev1.Service {
+		svc := factory.BuildRoleService(cluster, clusterDef.GetComponentDefByName(compDefName), compName, role)
+		Expect(k8sClient.Create(ctx, svc)).Should(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, svc)).Should(Succeed())
+		})
+		return svc
+	}
+
+	Context("role services", func() {
+		It("should create a service for each role of the components", func() {
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			services := findServices(dag, ictrltypes.CREATE)
+			Expect(services).Should(HaveLen(4))
+
+			for compName, roles := range map[string][]string{
+				mysqlCompName: {"leader", "follower"},
+				redisCompName: {constant.Primary, constant.Secondary},
+			} {
+				for _, role := range roles {
+					svc, ok := services[roleServiceName(compName, role)]
+					Expect(ok).Should(BeTrue())
+					Expect(svc.Namespace).Should(Equal(testCtx.DefaultNamespace))
+					Expect(svc.Spec.Type).Should(Equal(corev1.ServiceTypeClusterIP))
+					Expect(svc.Spec.Ports).ShouldNot(BeEmpty())
+					Expect(svc.Spec.Selector).Should(HaveKeyWithValue(constant.AppInstanceLabelKey, clusterName))
+					Expect(svc.Spec.Selector).Should(HaveKeyWithValue(constant.KBAppComponentLabelKey, compName))
+					Expect(svc.Spec.Selector).Should(HaveKeyWithValue(constant.RoleLabelKey, role))
+				}
+			}
+		})
+
+		It("should not create role services if roleServices is disabled", func() {
+			for i := range cluster.Spec.ComponentSpecs {
+				cluster.Spec.ComponentSpecs[i].RoleServices = false
+			}
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(ictrltypes.FindAll[*corev1.Service](dag)).Should(BeEmpty())
+		})
+
+		It("should update the changed role services and delete the disabled ones", func() {
+			primary := createRoleService(redisCompName, redisCompDefName, constant.Primary)
+			primary.Spec.Ports[0].Port += 1
+			Expect(k8sClient.Update(ctx, primary)).Should(Succeed())
+			createRoleService(redisCompName, redisCompDefName, constant.Secondary)
+			createRoleService(mysqlCompName, mysqlCompDefName, "leader")
+
+			By("update the changed role service only")
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			updated := findServices(dag, ictrltypes.UPDATE)
+			Expect(updated).Should(HaveLen(1))
+			svc := updated[roleServiceName(redisCompName, constant.Primary)]
+			Expect(svc).ShouldNot(BeNil())
+			Expect(svc.Spec.Ports[0].Port).Should(Equal(primary.Spec.Ports[0].Port - 1))
+			created := findServices(dag, ictrltypes.CREATE)
+			Expect(created).Should(HaveLen(1))
+			Expect(created).Should(HaveKey(roleServiceName(mysqlCompName, "follower")))
+
+			By("delete the role services of the component disabling roleServices")
+			cluster.Spec.ComponentSpecs[1].RoleServices = false
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			deleted := findServices(dag, ictrltypes.DELETE)
+			Expect(deleted).Should(HaveLen(2))
+			Expect(deleted).Should(HaveKey(roleServiceName(redisCompName, constant.Primary)))
+			Expect(deleted).Should(HaveKey(roleServiceName(redisCompName, constant.Secondary)))
+		})
+
+		It("should route to the new primary after a failover", func() {
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			services := findServices(dag, ictrltypes.CREATE)
+			primarySvc := services[roleServiceName(redisCompName, constant.Primary)]
+			secondarySvc := services[roleServiceName(redisCompName, constant.Secondary)]
+			Expect(primarySvc).ShouldNot(BeNil())
+			Expect(secondarySvc).ShouldNot(BeNil())
+
+			pods := make([]*corev1.Pod, 2)
+			for i := range pods {
+				pods[i] = &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testCtx.DefaultNamespace,
+						Name:      fmt.Sprintf("%s-%s-%d", clusterName, redisCompName, i),
+						Labels: map[string]string{
+							constant.AppManagedByLabelKey:   constant.AppName,
+							constant.AppNameLabelKey:        clusterDefName,
+							constant.AppInstanceLabelKey:    clusterName,
+							constant.KBAppComponentLabelKey: redisCompName,
+							constant.RoleLabelKey:           constant.Secondary,
+						},
+					},
+				}
+			}
+			selectedPods := func(svc *corev1.Service) []string {
+				var names []string
+				selector := labels.SelectorFromSet(svc.Spec.Selector)
+				for _, pod := range pods {
+					if selector.Matches(labels.Set(pod.Labels)) {
+						names = append(names, pod.Name)
+					}
+				}
+				return names
+			}
+
+			By("the first pod is the primary")
+			pods[0].Labels[constant.RoleLabelKey] = constant.Primary
+			Expect(selectedPods(primarySvc)).Should(Equal([]string{pods[0].Name}))
+			Expect(selectedPods(secondarySvc)).Should(Equal([]string{pods[1].Name}))
+
+			By("the second pod becomes the primary after a failover")
+			pods[0].Labels[constant.RoleLabelKey] = constant.Secondary
+			pods[1].Labels[constant.RoleLabelKey] = constant.Primary
+			Expect(selectedPods(primarySvc)).Should(Equal([]string{pods[1].Name}))
+			Expect(selectedPods(secondarySvc)).Should(Equal([]string{pods[0].Name}))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+func TestThrottleComponentCreation(t *testing.T) {
+	cluster := &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{constant.ComponentCreationConcurrencyAnnotationKey: "2"},
+		},
+		Spec: appsv1alpha1.ClusterSpec{
+			ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{
+				{Name: "comp-0"}, {Name: "comp-1"}, {Name: "comp-2"}, {Name: "comp-3"}, {Name: "comp-4"},
+			},
+		},
+		Status: appsv1alpha1.ClusterStatus{Components: map[string]appsv1alpha1.ClusterComponentStatus{}},
+	}
+	pendingSet := func() sets.Set[string] {
+		pending := sets.New[string]()
+		for _, compSpec := range cluster.Spec.ComponentSpecs {
+			if _, ok := cluster.Status.Components[compSpec.Name]; !ok {
+				pending.Insert(compSpec.Name)
+			}
+		}
+		return pending
+	}
+	setPhase := func(phase appsv1alpha1.ClusterComponentPhase, compNames ...string) {
+		for _, compName := range compNames {
+			cluster.Status.Components[compName] = appsv1alpha1.ClusterComponentStatus{Phase: phase}
+		}
+	}
+
+	// the components are brought up in batches following the order of the specs.
+	expectedBatches := [][]string{{"comp-0", "comp-1"}, {"comp-2", "comp-3"}, {"comp-4"}}
+	for i, expected := range expectedBatches {
+		createList, pendingList, err := throttleComponentCreation(cluster, pendingSet())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(createList, expected) {
+			t.Fatalf("batch %d: expected to create %v, got %v", i, expected, createList)
+		}
+		if len(createList)+len(pendingList) != pendingSet().Len() {
+			t.Errorf("batch %d: expected the rest components pending, got %v", i, pendingList)
+		}
+		setPhase("", createList...)
+
+		// nothing more is created while the batch is being created.
+		if createList, _, _ = throttleComponentCreation(cluster, pendingSet()); pendingSet().Len() > 0 && len(createList) != 0 {
+			t.Errorf("batch %d: expected no more components created before the batch is running, got %v", i, createList)
+		}
+		setPhase(appsv1alpha1.CreatingClusterCompPhase, expected...)
+		if createList, _, _ = throttleComponentCreation(cluster, pendingSet()); pendingSet().Len() > 0 && len(createList) != 0 {
+			t.Errorf("batch %d: expected no more components created before the batch is running, got %v", i, createList)
+		}
+
+		// a slot is released once a component of the batch is running.
+		setPhase(appsv1alpha1.RunningClusterCompPhase, expected[0])
+		if createList, _, _ = throttleComponentCreation(cluster, pendingSet()); pendingSet().Len() > 0 && len(createList) != 1 {
+			t.Errorf("batch %d: expected one more component created once a slot is released, got %v", i, createList)
+		}
+		setPhase(appsv1alpha1.RunningClusterCompPhase, expected...)
+	}
+	if pendingSet().Len() != 0 {
+		t.Errorf("expected all components created, got pending %v", sets.List(pendingSet()))
+	}
+
+	// no limit without the annotation.
+	delete(cluster.Annotations, constant.ComponentCreationConcurrencyAnnotationKey)
+	cluster.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{}
+	if createList, pendingList, _ := throttleComponentCreation(cluster, pendingSet()); len(createList) != 5 || len(pendingList) != 0 {
+		t.Errorf("expected all components created at once, got %v", createList)
+	}
+
+	cluster.Annotations[constant.ComponentCreationConcurrencyAnnotationKey] = "0"
+	if _, _, err := throttleComponentCreation(cluster, pendingSet()); err == nil {
+		t.Error("expected error with the invalid concurrency")
+	}
+}
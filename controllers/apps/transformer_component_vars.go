@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+)
+
+// ComponentVarsTransformer makes the workload of a component depending on the workloads of the components
+// whose services are referred by its vars, and on the secrets referred by its credential vars,
+// so the referred services and secrets are created first.
+type ComponentVarsTransformer struct{}
+
+var _ graph.Transformer = &ComponentVarsTransformer{}
+
+func (t *ComponentVarsTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	workloadVertices := make(map[string]*ictrltypes.LifecycleVertex)
+	for _, vertex := range ictrltypes.FindAll[*workloads.ReplicatedStateMachine](dag) {
+		v, _ := vertex.(*ictrltypes.LifecycleVertex)
+		if v.Action != nil && *v.Action == ictrltypes.DELETE {
+			continue
+		}
+		workloadVertices[v.Obj.GetLabels()[constant.KBAppComponentLabelKey]] = v
+	}
+
+	secretVertices := make(map[string]*ictrltypes.LifecycleVertex)
+	for _, vertex := range ictrltypes.FindAll[*corev1.Secret](dag) {
+		v, _ := vertex.(*ictrltypes.LifecycleVertex)
+		if v.Action != nil && *v.Action == ictrltypes.DELETE {
+			continue
+		}
+		secretVertices[v.Obj.GetName()] = v
+	}
+
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef == nil || len(compDef.Vars) == 0 {
+			continue
+		}
+		vertex, ok := workloadVertices[compSpec.Name]
+		if !ok {
+			continue
+		}
+		for _, dependency := range component.GetComponentVarDependencies(cluster, compDef, compSpec.Name) {
+			if dependencyVertex, ok := workloadVertices[dependency]; ok {
+				dag.Connect(vertex, dependencyVertex)
+			}
+		}
+		for _, secretName := range component.GetComponentVarSecrets(cluster, transCtx.ClusterDef, compDef) {
+			if secretVertex, ok := secretVertices[secretName]; ok {
+				dag.Connect(vertex, secretVertex)
+			}
+		}
+	}
+	return nil
+}
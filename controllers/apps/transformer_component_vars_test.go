@@ -0,0 +1,145 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	ictrltypes "github.com/apecloud/kubeblocks/internal/controller/types"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("component vars transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		backendCompName    = "backend"
+		backendCompDefName = "backend"
+		metaCompName       = "metadata"
+		metaCompDefName    = "metadata"
+	)
+
+	var (
+		transCtx    graph.TransformContext
+		transformer graph.Transformer
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		clusterDef := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.StatelessNginxComponent, backendCompDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, metaCompDefName).
+			GetObject()
+		// the backend component refers to the service of the metadata component
+		clusterDef.Spec.ComponentDefs[0].Vars = []appsv1alpha1.ComponentVar{
+			{
+				Name:      "METADATA_HOST",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: metaCompDefName}},
+			},
+			{
+				Name:      "ROOT_PASSWORD",
+				ValueFrom: appsv1alpha1.ComponentVarSource{CredentialRef: &appsv1alpha1.CredentialVarSelector{Key: "password"}},
+			},
+		}
+		cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddComponent(backendCompName, backendCompDefName).
+			AddComponent(metaCompName, metaCompDefName).
+			GetObject()
+		transCtx = &ClusterTransformContext{
+			Context:    ctx,
+			Client:     k8sClient,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-component-vars-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+		transformer = &ComponentVarsTransformer{}
+	})
+
+	mockDAG := func() *graph.DAG {
+		dag := graph.NewDAG()
+		root := ictrltypes.LifecycleObjectCreate(dag, transCtx.(*ClusterTransformContext).Cluster, nil)
+		for _, compName := range []string{backendCompName, metaCompName} {
+			rsm := &workloads.ReplicatedStateMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testCtx.DefaultNamespace,
+					Name:      clusterName + "-" + compName,
+					Labels:    map[string]string{constant.KBAppComponentLabelKey: compName},
+				},
+			}
+			ictrltypes.LifecycleObjectCreate(dag, rsm, root)
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      component.GenerateConnCredential(clusterName),
+			},
+		}
+		ictrltypes.LifecycleObjectCreate(dag, secret, root)
+		return dag
+	}
+
+	walkOrder := func(dag *graph.DAG) []string {
+		var names []string
+		walkFunc := func(v graph.Vertex) error {
+			vertex, _ := v.(*ictrltypes.LifecycleVertex)
+			switch vertex.Obj.(type) {
+			case *workloads.ReplicatedStateMachine, *corev1.Secret:
+				names = append(names, vertex.Obj.GetName())
+			}
+			return nil
+		}
+		less := func(v1, v2 graph.Vertex) bool {
+			o1, _ := v1.(*ictrltypes.LifecycleVertex)
+			o2, _ := v2.(*ictrltypes.LifecycleVertex)
+			return o1.Obj.GetName() < o2.Obj.GetName()
+		}
+		Expect(dag.WalkReverseTopoOrder(walkFunc, less)).Should(Succeed())
+		return names
+	}
+
+	Context("component vars", func() {
+		It("should create the referred component before the referring one", func() {
+			dag := mockDAG()
+			By("the workloads are created in name order without the dependencies")
+			connCredentialName := component.GenerateConnCredential(clusterName)
+			Expect(walkOrder(dag)).Should(Equal([]string{clusterName + "-" + backendCompName, connCredentialName, clusterName + "-" + metaCompName}))
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			By("the referred workload and secret are created first with the dependencies")
+			order := walkOrder(dag)
+			Expect(order).Should(HaveLen(3))
+			Expect(order[2]).Should(Equal(clusterName + "-" + backendCompName))
+		})
+	})
+})
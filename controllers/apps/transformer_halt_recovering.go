@@ -76,9 +76,12 @@ func (t *HaltRecoveryTransformer) Transform(ctx graph.TransformContext, dag *gra
 		return graph.ErrPrematureStop
 	}
 
-	// halt recovering from last applied record stored in pvc's annotation
-	l, ok := pvcList.Items[0].Annotations[constant.LastAppliedClusterAnnotationKey]
-	if !ok || l == "" {
+	// halt recovering from last applied record referred by pvc's annotation
+	lc, err := getLastAppliedCluster(transCtx, &pvcList.Items[0])
+	if err != nil {
+		return newRequeueError(requeueDuration, err.Error())
+	}
+	if lc == nil {
 		return emitError(metav1.Condition{
 			Type:   appsv1alpha1.ConditionTypeHaltRecovery,
 			Reason: "UncleanedResources",
@@ -87,11 +90,6 @@ func (t *HaltRecoveryTransformer) Transform(ctx graph.TransformContext, dag *gra
 		})
 	}
 
-	lc := &appsv1alpha1.Cluster{}
-	if err := json.Unmarshal([]byte(l), lc); err != nil {
-		return newRequeueError(requeueDuration, err.Error())
-	}
-
 	// skip if same cluster UID
 	if lc.UID == cluster.UID {
 		return nil
@@ -212,3 +210,26 @@ func (t *HaltRecoveryTransformer) Transform(ctx graph.TransformContext, dag *gra
 }
 
 var _ graph.Transformer = &HaltRecoveryTransformer{}
+
+// getLastAppliedCluster loads the last applied cluster from the snapshot ConfigMap referred by the annotation of
+// the preserved object, the cluster annotated inline by the prior versions is still supported.
+// nil is returned if no last applied cluster is found.
+func getLastAppliedCluster(transCtx *ClusterTransformContext, obj client.Object) (*appsv1alpha1.Cluster, error) {
+	annotations := obj.GetAnnotations()
+	data := annotations[constant.LastAppliedClusterAnnotationKey]
+	if name := annotations[constant.LastAppliedClusterRefAnnotationKey]; len(name) > 0 {
+		cm := &corev1.ConfigMap{}
+		if err := transCtx.Client.Get(transCtx.Context, client.ObjectKey{Namespace: obj.GetNamespace(), Name: name}, cm); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		data = cm.Data[constant.LastAppliedClusterKey]
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	lc := &appsv1alpha1.Cluster{}
+	if err := json.Unmarshal([]byte(data), lc); err != nil {
+		return nil, err
+	}
+	return lc, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("last applied cluster snapshot test", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompDefName   = "mysql"
+	)
+
+	var (
+		ctx      context.Context
+		transCtx *ClusterTransformContext
+		cluster  *appsv1alpha1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName().
+			AddComponent("mysql", mysqlCompDefName).
+			SetReplicas(3).
+			GetObject()
+		cluster.UID = types.UID("test-uid")
+		transCtx = &ClusterTransformContext{
+			Context: ctx,
+			Client:  k8sClient,
+			Logger:  logf.FromContext(ctx).WithValues("last-applied-cluster-test", testCtx.DefaultNamespace),
+			Cluster: cluster,
+		}
+	})
+
+	newPVC := func(annotations map[string]string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Namespace = cluster.Namespace
+		pvc.Name = "data-" + cluster.Name + "-mysql-0"
+		pvc.Annotations = annotations
+		return pvc
+	}
+
+	It("should read the last applied cluster annotated inline by the prior versions", func() {
+		snapshot, err := buildLastAppliedClusterSnapshot(cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		pvc := newPVC(map[string]string{
+			constant.LastAppliedClusterAnnotationKey: snapshot.Data[constant.LastAppliedClusterKey],
+		})
+		lc, err := getLastAppliedCluster(transCtx, pvc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lc).ShouldNot(BeNil())
+		Expect(lc.UID).Should(Equal(cluster.UID))
+		Expect(lc.Spec.ComponentSpecs).Should(HaveLen(1))
+
+		By("migrate the preserved object to refer to the snapshot")
+		annotateLastAppliedCluster(pvc, snapshot.Name)
+		Expect(pvc.Annotations).ShouldNot(HaveKey(constant.LastAppliedClusterAnnotationKey))
+		Expect(pvc.Annotations).Should(HaveKeyWithValue(constant.LastAppliedClusterRefAnnotationKey, snapshot.Name))
+	})
+
+	It("should load the last applied cluster from the referred snapshot", func() {
+		pvc := newPVC(map[string]string{
+			constant.LastAppliedClusterRefAnnotationKey: component.GenerateLastAppliedClusterName(cluster.Name),
+		})
+
+		By("the snapshot is not found")
+		lc, err := getLastAppliedCluster(transCtx, pvc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lc).Should(BeNil())
+
+		By("create the snapshot")
+		snapshot, err := buildLastAppliedClusterSnapshot(cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(snapshot.Name).Should(Equal(component.GenerateLastAppliedClusterName(cluster.Name)))
+		Expect(snapshot.Labels).Should(HaveKeyWithValue(constant.AppInstanceLabelKey, cluster.Name))
+		Expect(k8sClient.Create(ctx, snapshot)).Should(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, snapshot))).Should(Succeed())
+		})
+		Eventually(func(g Gomega) {
+			lc, err = getLastAppliedCluster(transCtx, pvc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(lc).ShouldNot(BeNil())
+		}).Should(Succeed())
+		Expect(lc.UID).Should(Equal(cluster.UID))
+		Expect(lc.Spec.ComponentSpecs[0].Replicas).Should(BeEquivalentTo(3))
+	})
+
+	It("should keep the annotations of the preserved objects of a 50-component cluster under the size limit", func() {
+		factory := testapps.NewClusterFactory(testCtx.DefaultNamespace, "test-cluster",
+			clusterDefName, clusterVersionName).WithRandomName()
+		for i := 0; i < 50; i++ {
+			factory.AddComponent(fmt.Sprintf("mysql-%d", i), mysqlCompDefName).
+				SetReplicas(3).
+				AddVolumeClaimTemplate(testapps.DataVolumeName, testapps.NewPVCSpec("10Gi")).
+				AddVolumeClaimTemplate(testapps.LogVolumeName, testapps.NewPVCSpec("1Gi"))
+		}
+		bigCluster := factory.GetObject()
+
+		snapshot, err := buildLastAppliedClusterSnapshot(bigCluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		lc := &appsv1alpha1.Cluster{}
+		Expect(json.Unmarshal([]byte(snapshot.Data[constant.LastAppliedClusterKey]), lc)).Should(Succeed())
+		Expect(lc.Spec.ComponentSpecs).Should(HaveLen(50))
+
+		pvc := newPVC(map[string]string{
+			// annotated by the prior versions
+			constant.LastAppliedClusterAnnotationKey: snapshot.Data[constant.LastAppliedClusterKey],
+		})
+		annotateLastAppliedCluster(pvc, snapshot.Name)
+		Expect(apivalidation.ValidateAnnotations(pvc.Annotations, field.NewPath("metadata", "annotations"))).Should(BeEmpty())
+		size := 0
+		for k, v := range pvc.Annotations {
+			size += len(k) + len(v)
+		}
+		Expect(size).Should(BeNumerically("<", len(snapshot.Data[constant.LastAppliedClusterKey])))
+		Expect(size).Should(BeNumerically("<", apivalidation.TotalAnnotationSizeLimitB))
+	})
+})
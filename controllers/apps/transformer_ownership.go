@@ -21,6 +21,7 @@ package apps
 
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -49,6 +50,17 @@ func (f *OwnershipTransformer) Transform(ctx graph.TransformContext, dag *graph.
 		if _, ok := v.Obj.(*rbacv1.ClusterRoleBinding); ok {
 			continue
 		}
+		// the monitor resources are garbage collected along with the cluster, no finalizer is added since the
+		// deletion of the cluster doesn't list the kinds, whose CRDs may not be installed.
+		if _, ok := v.Obj.(*unstructured.Unstructured); ok {
+			if err := controllerutil.SetControllerReference(rootVertex.Obj, v.Obj, rscheme); err != nil {
+				if _, ok := err.(*controllerutil.AlreadyOwnedError); ok {
+					continue
+				}
+				return err
+			}
+			continue
+		}
 		if err := intctrlutil.SetOwnership(rootVertex.Obj, v.Obj, rscheme, constant.DBClusterFinalizerName); err != nil {
 			if _, ok := err.(*controllerutil.AlreadyOwnedError); ok {
 				continue
@@ -293,7 +293,10 @@ func buildServiceAccounts(transCtx *ClusterTransformContext, componentSpecs []ap
 	for _, compSpec := range componentSpecs {
 		serviceAccountName := compSpec.ServiceAccountName
 		if serviceAccountName == "" {
-			if !isProbesEnabled(clusterDef, &compSpec) && !isVolumeProtectionEnabled(clusterDef, &compSpec) && !isDataProtectionEnabled(backupPolicyTPL, &compSpec) {
+			// the lorry sidecars are omitted in lightweight mode.
+			isLorryEnabled := !cluster.Spec.LightweightMode &&
+				(isProbesEnabled(clusterDef, &compSpec) || isVolumeProtectionEnabled(clusterDef, &compSpec))
+			if !isLorryEnabled && !isDataProtectionEnabled(backupPolicyTPL, &compSpec) {
 				continue
 			}
 			serviceAccountName = "kb-" + cluster.Name
@@ -0,0 +1,187 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	cfgcore "github.com/apecloud/kubeblocks/internal/configuration/core"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+)
+
+// ValidateComponentNamesTransformer validates the objects of the components are not owned by the components
+// of other clusters. The objects are named after <cluster>-<component>, so the cluster mysql-a with the component
+// b-proxy and the cluster mysql with the component a-b-proxy would take over the objects of each other, and so would
+// the component proxy with the config template config and the component proxy-config of the same cluster.
+// The webhook rejects such clusters, this transformer guards the clusters admitted without the webhook, and the ones
+// admitted before, by checking every object name derived from the components. Only the objects labeled with or
+// controlled by the others are rejected, the unowned ones are left to be adopted by the components.
+//
+// The names are not made collision-proof, e.g. by a hash suffix, as the names of the pods, services and PVCs are the
+// DNS names and the volume bindings the applications and the existing clusters rely on. The collisions are rejected
+// at admission and reconciliation instead.
+type ValidateComponentNamesTransformer struct{}
+
+var _ graph.Transformer = &ValidateComponentNamesTransformer{}
+
+func (t *ValidateComponentNamesTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	err := t.validateComponentObjects(transCtx, cluster)
+	setProvisioningStartedCondition(&cluster.Status.Conditions, cluster.Name, cluster.Generation, err)
+	if err != nil {
+		return newRequeueError(requeueDuration, err.Error())
+	}
+
+	return nil
+}
+
+func (t *ValidateComponentNamesTransformer) validateComponentObjects(transCtx *ClusterTransformContext,
+	cluster *appsv1alpha1.Cluster) error {
+	for _, comp := range cluster.Spec.ComponentSpecs {
+		for _, obj := range t.getComponentObjects(transCtx, cluster, comp) {
+			if err := transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(obj), obj); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			if owner := t.getOtherOwner(obj, cluster, comp); len(owner) > 0 {
+				return fmt.Errorf("the object %s of component %s is owned by %s, rename the component",
+					obj.GetName(), comp.Name, owner)
+			}
+		}
+	}
+	return nil
+}
+
+// getOtherOwner returns the owner of the object if it's clearly owned by another cluster or component, i.e. labeled
+// with them or controlled by others, and an empty string otherwise. The objects with neither the labels nor the
+// controller, e.g. the pre-created PVCs, are left to be adopted by the component.
+func (t *ValidateComponentNamesTransformer) getOtherOwner(obj client.Object,
+	cluster *appsv1alpha1.Cluster, comp appsv1alpha1.ClusterComponentSpec) string {
+	labels := obj.GetLabels()
+	clusterName, compName := labels[constant.AppInstanceLabelKey], labels[constant.KBAppComponentLabelKey]
+	switch {
+	case len(clusterName) > 0 && clusterName != cluster.Name:
+		if len(compName) > 0 {
+			return fmt.Sprintf("the component %s of cluster %s", compName, clusterName)
+		}
+		return fmt.Sprintf("the cluster %s", clusterName)
+	case len(clusterName) > 0:
+		if len(compName) > 0 && compName != comp.Name {
+			return fmt.Sprintf("the component %s of the cluster", compName)
+		}
+		return ""
+	}
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return ""
+	}
+	workloadName := fmt.Sprintf("%s-%s", cluster.Name, comp.Name)
+	if (owner.Kind == appsv1alpha1.ClusterKind && owner.Name == cluster.Name) ||
+		owner.Name == workloadName || (len(comp.PodNamePrefix) > 0 && owner.Name == comp.PodNamePrefix) {
+		return ""
+	}
+	return fmt.Sprintf("the %s %s", owner.Kind, owner.Name)
+}
+
+// getComponentObjects returns the objects derived from the component by their names, i.e. the workload, the client,
+// headless, alternative and role services, the config, script and env ConfigMaps, the pods and the PVCs.
+func (t *ValidateComponentNamesTransformer) getComponentObjects(transCtx *ClusterTransformContext,
+	cluster *appsv1alpha1.Cluster, comp appsv1alpha1.ClusterComponentSpec) []client.Object {
+	newObj := func(obj client.Object, name string) client.Object {
+		obj.SetNamespace(cluster.Namespace)
+		obj.SetName(name)
+		return obj
+	}
+
+	workloadName := fmt.Sprintf("%s-%s", cluster.Name, comp.Name)
+	objs := []client.Object{
+		newObj(&workloads.ReplicatedStateMachine{}, workloadName),
+		newObj(&corev1.Service{}, component.GenerateComponentServiceName(cluster.Name, comp.Name, "")),
+		newObj(&corev1.Service{}, workloadName+"-headless"),
+		newObj(&corev1.ConfigMap{}, component.GenerateComponentEnvName(cluster.Name, comp.Name)),
+	}
+	for _, svc := range comp.Services {
+		objs = append(objs, newObj(&corev1.Service{}, component.GenerateComponentServiceName(cluster.Name, comp.Name, svc.Name)))
+	}
+	if comp.RoleServices && transCtx.ClusterDef != nil {
+		if compDef := transCtx.ClusterDef.GetComponentDefByName(comp.ComponentDefRef); compDef != nil {
+			for _, role := range compDef.GetRoles() {
+				objs = append(objs, newObj(&corev1.Service{}, component.GenerateRoleServiceName(cluster.Name, comp.Name, role)))
+			}
+		}
+	}
+	for _, tplName := range t.getTemplateNames(transCtx, comp) {
+		objs = append(objs, newObj(&corev1.ConfigMap{}, cfgcore.GetComponentCfgName(cluster.Name, comp.Name, tplName)))
+	}
+
+	podNamePrefix := workloadName
+	if len(comp.PodNamePrefix) > 0 {
+		podNamePrefix = comp.PodNamePrefix
+	}
+	for i := int32(0); i < comp.Replicas; i++ {
+		podName := fmt.Sprintf("%s-%d", podNamePrefix, comp.OrdinalStart+i)
+		objs = append(objs, newObj(&corev1.Pod{}, podName))
+		for _, vct := range comp.VolumeClaimTemplates {
+			objs = append(objs, newObj(&corev1.PersistentVolumeClaim{}, fmt.Sprintf("%s-%s", vct.Name, podName)))
+		}
+	}
+	return objs
+}
+
+// getTemplateNames returns the names of the config and script templates of the component, which name the ConfigMaps
+// rendered from them.
+func (t *ValidateComponentNamesTransformer) getTemplateNames(transCtx *ClusterTransformContext,
+	comp appsv1alpha1.ClusterComponentSpec) []string {
+	var tplNames []string
+	if transCtx.ClusterDef != nil {
+		if compDef := transCtx.ClusterDef.GetComponentDefByName(comp.ComponentDefRef); compDef != nil {
+			for _, tpl := range compDef.ConfigSpecs {
+				tplNames = append(tplNames, tpl.Name)
+			}
+			for _, tpl := range compDef.ScriptSpecs {
+				tplNames = append(tplNames, tpl.Name)
+			}
+		}
+	}
+	if transCtx.ClusterVer != nil {
+		if compVer, ok := transCtx.ClusterVer.Spec.GetDefNameMappingComponents()[comp.ComponentDefRef]; ok {
+			for _, tpl := range compVer.ConfigSpecs {
+				tplNames = append(tplNames, tpl.Name)
+			}
+		}
+	}
+	return tplNames
+}
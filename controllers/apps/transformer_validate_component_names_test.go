@@ -0,0 +1,202 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	"github.com/apecloud/kubeblocks/internal/generics"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("validate component names transformer test.", func() {
+	const (
+		clusterName        = "mysql-a"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		proxyCompName      = "b-proxy"
+		proxyCompDefName   = "proxy"
+		configTplName      = "config"
+	)
+
+	var (
+		transCtx    *ClusterTransformContext
+		transformer graph.Transformer
+		cluster     *appsv1alpha1.Cluster
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResources(&testCtx, generics.ServiceSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.ConfigMapSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.PersistentVolumeClaimSignature, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+		ctx := context.Background()
+		cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+			clusterDefName, clusterVersionName).
+			AddComponent(proxyCompName, proxyCompDefName).
+			SetReplicas(1).
+			AddVolumeClaimTemplate("data", testapps.NewPVCSpec("1Gi")).
+			GetObject()
+		clusterDef := &appsv1alpha1.ClusterDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterDefName},
+			Spec: appsv1alpha1.ClusterDefinitionSpec{
+				ComponentDefs: []appsv1alpha1.ClusterComponentDefinition{{
+					Name:        proxyCompDefName,
+					ConfigSpecs: []appsv1alpha1.ComponentConfigSpec{{ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{Name: configTplName}}},
+				}},
+			},
+		}
+		transCtx = &ClusterTransformContext{
+			Context:    ctx,
+			Client:     k8sClient,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-validate-component-names-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+		transformer = &ValidateComponentNamesTransformer{}
+	})
+
+	AfterEach(cleanEnv)
+
+	mockService := func(clusterName, compName string) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      clusterName + "-" + compName,
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    clusterName,
+					constant.KBAppComponentLabelKey: compName,
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "proxy", Port: 6379}},
+			},
+		}
+		Expect(testCtx.Create(testCtx.Ctx, svc)).Should(Succeed())
+	}
+
+	mockConfigMap := func(name, clusterName, compName string) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      name,
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    clusterName,
+					constant.KBAppComponentLabelKey: compName,
+				},
+			},
+		}
+		Expect(testCtx.Create(testCtx.Ctx, cm)).Should(Succeed())
+	}
+
+	Context("component object names", func() {
+		It("should pass if the objects are owned by the component", func() {
+			mockService(clusterName, proxyCompName)
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+		})
+
+		It("should requeue if the objects are owned by the component of another cluster", func() {
+			mockService("mysql", "a-b-proxy")
+			err := transformer.Transform(transCtx, graph.NewDAG())
+			Expect(intctrlutil.IsRequeueError(err)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring("a-b-proxy"))
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeProvisioningStarted)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+		})
+
+		It("should requeue if the ConfigMap of the config template is owned by the other component", func() {
+			configCMName := clusterName + "-" + proxyCompName + "-" + configTplName
+			By("the ConfigMap is owned by the component")
+			mockConfigMap(configCMName, clusterName, proxyCompName)
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+
+			By("the ConfigMap is the one of the config template of the component proxy of the cluster mysql-a-b")
+			Expect(testCtx.Cli.Delete(testCtx.Ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: configCMName},
+			})).Should(Succeed())
+			mockConfigMap(configCMName, clusterName+"-b", "proxy")
+			err := transformer.Transform(transCtx, graph.NewDAG())
+			Expect(intctrlutil.IsRequeueError(err)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring(configCMName))
+		})
+
+		It("should pass if the objects are neither labeled nor controlled, which are left to be adopted", func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testCtx.DefaultNamespace,
+					Name:      "data-" + clusterName + "-" + proxyCompName + "-0",
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			Expect(testCtx.Create(testCtx.Ctx, pvc)).Should(Succeed())
+			Expect(transformer.Transform(transCtx, graph.NewDAG())).Should(Succeed())
+		})
+
+		It("should requeue if the unlabeled objects are controlled by another cluster", func() {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testCtx.DefaultNamespace,
+					Name:      clusterName + "-" + proxyCompName,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: appsv1alpha1.GroupVersion.String(),
+						Kind:       appsv1alpha1.ClusterKind,
+						Name:       "mysql",
+						UID:        "00000000-0000-0000-0000-000000000000",
+						Controller: pointer.Bool(true),
+					}},
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Name: "proxy", Port: 6379}},
+				},
+			}
+			Expect(testCtx.Create(testCtx.Ctx, svc)).Should(Succeed())
+			err := transformer.Transform(transCtx, graph.NewDAG())
+			Expect(intctrlutil.IsRequeueError(err)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring("the Cluster mysql"))
+		})
+	})
+})
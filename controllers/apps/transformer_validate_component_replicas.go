@@ -0,0 +1,55 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+)
+
+// ValidateComponentReplicasTransformer warns the consensus components whose followers are in any count, and whose
+// voting members, i.e. the replicas except the learners, can't form an odd-sized quorum. The cluster webhook returns
+// the same warning at admission, and the event is recorded once the spec of the cluster changes.
+type ValidateComponentReplicasTransformer struct{}
+
+var _ graph.Transformer = &ValidateComponentReplicasTransformer{}
+
+func (t *ValidateComponentReplicasTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() || !transCtx.OrigCluster.IsUpdating() {
+		return nil
+	}
+
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef == nil || compDef.WorkloadType != appsv1alpha1.Consensus {
+			continue
+		}
+		if voters := compDef.ConsensusSpec.GetEvenQuorumVoters(compSpec.Replicas); voters > 0 {
+			transCtx.EventRecorder.Eventf(cluster, corev1.EventTypeWarning, "EvenQuorumMembers",
+				"component %s has %d voting members (replicas - learners), an odd number is recommended to tolerate the same failures with fewer members",
+				compSpec.Name, voters)
+		}
+	}
+	return nil
+}
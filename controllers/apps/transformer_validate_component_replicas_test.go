@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("validate component replicas transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		consensusCompName  = "consensus"
+		consensusCompDef   = "consensus"
+	)
+
+	var (
+		transformer graph.Transformer
+		clusterDef  *appsv1alpha1.ClusterDefinition
+		recorder    *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		// the followers of the consensus component are in any count.
+		clusterDef = testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.ConsensusMySQLComponent, consensusCompDef).
+			GetObject()
+		recorder = record.NewFakeRecorder(10)
+		transformer = &ValidateComponentReplicasTransformer{}
+	})
+
+	newTransCtx := func(replicas int32, generation int64) *ClusterTransformContext {
+		ctx := context.Background()
+		cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDefName, clusterVersionName).
+			AddComponent(consensusCompName, consensusCompDef).
+			SetReplicas(replicas).
+			GetObject()
+		cluster.Generation = generation
+		cluster.Status.ObservedGeneration = 1
+		return &ClusterTransformContext{
+			Context:       ctx,
+			Client:        k8sClient,
+			EventRecorder: recorder,
+			Logger:        logf.FromContext(ctx).WithValues("transformer-validate-component-replicas-test", testCtx.DefaultNamespace),
+			Cluster:       cluster,
+			OrigCluster:   cluster.DeepCopy(),
+			ClusterDef:    clusterDef,
+		}
+	}
+
+	Context("voting members of consensus components", func() {
+		It("should warn the even voting members once the spec changes", func() {
+			By("odd voting members")
+			Expect(transformer.Transform(newTransCtx(3, 2), graph.NewDAG())).Should(Succeed())
+			Expect(recorder.Events).Should(BeEmpty())
+
+			By("even voting members")
+			Expect(transformer.Transform(newTransCtx(4, 2), graph.NewDAG())).Should(Succeed())
+			Expect(recorder.Events).Should(HaveLen(1))
+			Expect(<-recorder.Events).Should(ContainSubstring("EvenQuorumMembers"))
+
+			By("the observed spec is not warned again")
+			Expect(transformer.Transform(newTransCtx(4, 1), graph.NewDAG())).Should(Succeed())
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,107 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+)
+
+// ValidateVolumeMountsTransformer validates the volumes mounted by the containers of the components are claimed by
+// the volume claim templates of the components. The volumes declared by the volumeTypes and volumeSubPaths of the
+// component definitions are expected to be claimed, otherwise the containers mount nothing persistent and the backups
+// of the volumes are broken. The components without any volume claim template run on emptyDir volumes by design,
+// and are skipped. The InvalidVolumeMount condition names the missing volume claim templates.
+type ValidateVolumeMountsTransformer struct{}
+
+var _ graph.Transformer = &ValidateVolumeMountsTransformer{}
+
+func (t *ValidateVolumeMountsTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*ClusterTransformContext)
+	cluster := transCtx.Cluster
+	if cluster.IsDeleting() {
+		return nil
+	}
+
+	var errMsgs []string
+	for _, compSpec := range cluster.Spec.ComponentSpecs {
+		compDef := transCtx.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef == nil {
+			continue
+		}
+		if missing := missingVolumeClaimTemplates(compDef, &compSpec); len(missing) > 0 {
+			errMsgs = append(errMsgs, fmt.Sprintf("the volumes %v mounted by component %s are not claimed by its volumeClaimTemplates",
+				missing, compSpec.Name))
+		}
+	}
+
+	if len(errMsgs) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, appsv1alpha1.ConditionTypeInvalidVolumeMount)
+		return nil
+	}
+	condition := newInvalidVolumeMountCondition(strings.Join(errMsgs, "; "))
+	condition.ObservedGeneration = cluster.Generation
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	return newRequeueError(requeueDuration, condition.Message)
+}
+
+// missingVolumeClaimTemplates returns the names of the volumes which are mounted by the containers and expected to be
+// claimed, but neither claimed by the volume claim templates nor provided by the pod spec or the tmpfs volumes.
+func missingVolumeClaimTemplates(compDef *appsv1alpha1.ClusterComponentDefinition,
+	compSpec *appsv1alpha1.ClusterComponentSpec) []string {
+	if compDef.PodSpec == nil || len(compSpec.VolumeClaimTemplates) == 0 {
+		return nil
+	}
+
+	claimed := func(name string) bool {
+		return slices.ContainsFunc(compDef.VolumeTypes, func(v appsv1alpha1.VolumeTypeSpec) bool { return v.Name == name }) ||
+			slices.ContainsFunc(compDef.VolumeSubPaths, func(v appsv1alpha1.VolumeSubPathMount) bool { return v.VolumeName == name })
+	}
+	provided := func(name string) bool {
+		return slices.ContainsFunc(compSpec.VolumeClaimTemplates, func(v appsv1alpha1.ClusterComponentVolumeClaimTemplate) bool { return v.Name == name }) ||
+			slices.ContainsFunc(compSpec.TmpfsVolumes, func(v appsv1alpha1.ClusterComponentTmpfsVolume) bool { return v.Name == name }) ||
+			slices.ContainsFunc(compDef.PodSpec.Volumes, func(v corev1.Volume) bool { return v.Name == name })
+	}
+
+	var missing []string
+	check := func(name string) {
+		if claimed(name) && !provided(name) && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+	for _, containers := range [][]corev1.Container{compDef.PodSpec.InitContainers, compDef.PodSpec.Containers} {
+		for _, container := range containers {
+			for _, volumeMount := range container.VolumeMounts {
+				check(volumeMount.Name)
+			}
+		}
+	}
+	for _, subPath := range compDef.VolumeSubPaths {
+		check(subPath.VolumeName)
+	}
+	return missing
+}
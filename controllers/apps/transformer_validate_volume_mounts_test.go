@@ -0,0 +1,103 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("validate volume mounts transformer test.", func() {
+	const (
+		clusterName        = "test-cluster"
+		clusterDefName     = "test-clusterdef"
+		clusterVersionName = "test-clusterversion"
+		mysqlCompName      = "mysql"
+		mysqlCompDefName   = "mysql"
+	)
+
+	var (
+		transformer graph.Transformer
+		clusterDef  *appsv1alpha1.ClusterDefinition
+	)
+
+	BeforeEach(func() {
+		clusterDef = testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.StatefulMySQLComponent, mysqlCompDefName).
+			GetObject()
+		transformer = &ValidateVolumeMountsTransformer{}
+	})
+
+	newTransCtx := func(cluster *appsv1alpha1.Cluster) *ClusterTransformContext {
+		ctx := context.Background()
+		return &ClusterTransformContext{
+			Context:    ctx,
+			Client:     k8sClient,
+			Logger:     logf.FromContext(ctx).WithValues("transformer-validate-volume-mounts-test", testCtx.DefaultNamespace),
+			Cluster:    cluster,
+			ClusterDef: clusterDef,
+		}
+	}
+
+	Context("volume mounts of components", func() {
+		It("should pass if the mounted volumes are claimed by the volume claim templates", func() {
+			cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDefName, clusterVersionName).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				AddVolumeClaimTemplate(testapps.DataVolumeName, testapps.NewPVCSpec("1Gi")).
+				GetObject()
+			meta.SetStatusCondition(&cluster.Status.Conditions, newInvalidVolumeMountCondition("stale"))
+			Expect(transformer.Transform(newTransCtx(cluster), graph.NewDAG())).Should(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeInvalidVolumeMount)).Should(BeNil())
+		})
+
+		It("should pass if the component has no volume claim templates", func() {
+			cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDefName, clusterVersionName).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				GetObject()
+			Expect(transformer.Transform(newTransCtx(cluster), graph.NewDAG())).Should(Succeed())
+		})
+
+		It("should requeue if the volume claim template of a mounted volume is missing", func() {
+			cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDefName, clusterVersionName).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				AddVolumeClaimTemplate(testapps.LogVolumeName, testapps.NewPVCSpec("1Gi")).
+				GetObject()
+			err := transformer.Transform(newTransCtx(cluster), graph.NewDAG())
+			Expect(intctrlutil.IsRequeueError(err)).Should(BeTrue())
+			cond := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeInvalidVolumeMount)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).Should(Equal(ReasonVolumeClaimTemplateNotFound))
+			Expect(cond.Message).Should(ContainSubstring("[%s]", testapps.DataVolumeName))
+			Expect(cond.Message).Should(ContainSubstring(mysqlCompName))
+		})
+	})
+})
@@ -42,7 +42,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
@@ -81,10 +80,11 @@ type BackupReconciler struct {
 // move the current state of the backup closer to the desired state.
 func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// setup common request context
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, "backup", req.NamespacedName)
 	reqCtx := intctrlutil.RequestCtx{
 		Ctx:      ctx,
 		Req:      req,
-		Log:      log.FromContext(ctx).WithValues("backup", req.NamespacedName),
+		Log:      logger,
 		Recorder: r.Recorder,
 	}
 
@@ -245,7 +245,7 @@ func (r *BackupReconciler) prepareBackupRequest(
 		return nil, err
 	}
 
-	targetPods, err := getTargetPods(reqCtx, r.Client,
+	targetPods, fallbackReason, err := getTargetPods(reqCtx, r.Client,
 		backup.Annotations[dataProtectionBackupTargetPodKey], backupPolicy)
 	if err != nil || len(targetPods) == 0 {
 		return nil, fmt.Errorf("failed to get target pods by backup policy %s/%s",
@@ -295,6 +295,7 @@ func (r *BackupReconciler) prepareBackupRequest(
 
 	request.BackupMethod = backupMethod
 	request.TargetPods = targetPods
+	request.TargetPodFallbackReason = fallbackReason
 	return request, nil
 }
 
@@ -319,10 +320,24 @@ func (r *BackupReconciler) handleBackupRepo(request *dpbackup.Request) error {
 		return client.IgnoreNotFound(err)
 	}
 
-	// backupRepo PVC exists, record the PVC name
-	if err == nil {
-		request.BackupRepoPVC = pvc
+	// the secret of the current encryption key should be present too for a new backup,
+	// otherwise wait for the backup repo controller to create it.
+	if repo.EncryptionEnabled() && request.Status.Encryption == nil {
+		fingerprint := repo.Status.EncryptionKeyFingerprint
+		if fingerprint == "" {
+			return dperrors.NewBackupEncryptionKeyNotReady(repo.Name)
+		}
+		secretKey := client.ObjectKey{
+			Namespace: request.Req.Namespace,
+			Name:      dputils.GetEncryptionKeySecretName(repo.Name, fingerprint),
+		}
+		if err = r.Client.Get(request.Ctx, secretKey, &corev1.Secret{}); err != nil {
+			return client.IgnoreNotFound(err)
+		}
 	}
+
+	// backupRepo PVC exists, record the PVC name
+	request.BackupRepoPVC = pvc
 	return nil
 }
 
@@ -332,9 +347,24 @@ func (r *BackupReconciler) patchBackupStatus(
 	request.Status.FormatVersion = dpbackup.FormatVersion
 	request.Status.Path = dpbackup.BuildBackupPath(request.Backup, request.BackupPolicy.Spec.PathPrefix)
 	request.Status.Target = request.BackupPolicy.Spec.Target
+	targetPod := request.TargetPods[0]
+	request.Status.TargetPod = &dpv1alpha1.BackupTargetPodStatus{
+		Name:           targetPod.Name,
+		Role:           targetPod.Labels[constant.RoleLabelKey],
+		FallbackReason: request.TargetPodFallbackReason,
+	}
+	if request.TargetPodFallbackReason != "" {
+		r.Recorder.Event(request.Backup, corev1.EventTypeWarning, "TargetPodFallback", request.TargetPodFallbackReason)
+	}
 	request.Status.BackupMethod = request.BackupMethod
 	request.Status.PersistentVolumeClaimName = request.BackupRepoPVC.Name
 	request.Status.BackupRepoName = request.BackupRepo.Name
+	if request.BackupRepo.EncryptionEnabled() {
+		request.Status.Encryption = &dpv1alpha1.BackupEncryptionStatus{
+			Algorithm:      request.BackupRepo.Spec.Encryption.Algorithm,
+			KeyFingerprint: request.BackupRepo.Status.EncryptionKeyFingerprint,
+		}
+	}
 
 	// init action status
 	actions, err := request.BuildActions()
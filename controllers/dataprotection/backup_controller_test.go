@@ -67,6 +67,8 @@ var _ = Describe("Backup Controller test", func() {
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupPolicySignature, true, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.JobSignature, true, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PersistentVolumeClaimSignature, true, inNS)
+		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, client.HasLabels{dataProtectionBackupRepoKey})
 
 		// non-namespaced
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.ActionSetSignature, true, ml)
@@ -345,6 +347,71 @@ var _ = Describe("Backup Controller test", func() {
 	})
 
 	When("with exceptional settings", func() {
+		Context("selects the target pod by role", func() {
+			followerPodKey := client.ObjectKey{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      testdp.ClusterName + "-" + testdp.ComponentName + "-1",
+			}
+
+			setTargetRole := func(role string, fallbackPolicy dpv1alpha1.TargetFallbackPolicy) {
+				Eventually(testapps.GetAndChangeObj(&testCtx, client.ObjectKeyFromObject(backupPolicy),
+					func(bp *dpv1alpha1.BackupPolicy) {
+						bp.Spec.Target.PodSelector.MatchLabels[constant.RoleLabelKey] = role
+						bp.Spec.Target.PodSelector.FallbackPolicy = fallbackPolicy
+					})).Should(Succeed())
+			}
+
+			mockPodReady := func(podKey client.ObjectKey) {
+				Eventually(testapps.GetAndChangeObjStatus(&testCtx, podKey, func(pod *corev1.Pod) {
+					pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+				})).Should(Succeed())
+			}
+
+			It("should select the available pod of the preferred role", func() {
+				setTargetRole(constant.Follower, dpv1alpha1.TargetFallbackPolicyPreferRole)
+				Eventually(testapps.GetAndChangeObj(&testCtx, followerPodKey, func(pod *corev1.Pod) {
+					pod.Labels[constant.RoleLabelKey] = constant.Follower
+				})).Should(Succeed())
+				mockPodReady(followerPodKey)
+				mockPodReady(client.ObjectKeyFromObject(targetPod))
+
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.TargetPod).Should(Equal(&dpv1alpha1.BackupTargetPodStatus{
+						Name: followerPodKey.Name,
+						Role: constant.Follower,
+					}))
+				})).Should(Succeed())
+			})
+
+			It("should fall back to the ready pod of the other roles if the preferred role is unavailable", func() {
+				setTargetRole(constant.Follower, dpv1alpha1.TargetFallbackPolicyPreferRole)
+				mockPodReady(client.ObjectKeyFromObject(targetPod))
+
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Annotations[dataProtectionBackupTargetPodKey]).Should(Equal(targetPod.Name))
+					g.Expect(fetched.Status.TargetPod).ShouldNot(BeNil())
+					g.Expect(fetched.Status.TargetPod.Name).Should(Equal(targetPod.Name))
+					g.Expect(fetched.Status.TargetPod.Role).Should(Equal(constant.Leader))
+					g.Expect(fetched.Status.TargetPod.FallbackReason).Should(ContainSubstring("no available pod of role " + constant.Follower))
+				})).Should(Succeed())
+			})
+
+			It("should fail if the role is unavailable with the strict fallback policy", func() {
+				setTargetRole(constant.Follower, dpv1alpha1.TargetFallbackPolicyStrict)
+				mockPodReady(client.ObjectKeyFromObject(targetPod))
+
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.TargetPod).Should(BeNil())
+				})).Should(Succeed())
+			})
+		})
+
 		Context("creates a backup with non-existent backup policy", func() {
 			var backupKey types.NamespacedName
 			BeforeEach(func() {
@@ -514,4 +581,114 @@ var _ = Describe("Backup Controller test", func() {
 			})
 		})
 	})
+
+	When("with an encrypted backup repo", func() {
+		const (
+			keySecretName = "test-encryption-key"
+			encryptionKey = "test-key"
+		)
+
+		BeforeEach(func() {
+			By("creating the encryption key secret")
+			obj := &corev1.Secret{}
+			obj.Name = keySecretName
+			obj.Namespace = testCtx.DefaultNamespace
+			obj.StringData = map[string]string{dptypes.EncryptionKeySecretKey: encryptionKey}
+			testapps.CreateK8sResource(&testCtx, obj)
+
+			By("creating an actionSet")
+			_ = testdp.NewFakeActionSet(&testCtx)
+
+			By("creating storage provider")
+			_ = testdp.NewFakeStorageProvider(&testCtx, nil)
+
+			By("creating an encrypted backup repo")
+			_, _ = testdp.NewFakeBackupRepo(&testCtx, func(repo *dpv1alpha1.BackupRepo) {
+				repo.Spec.Encryption = &dpv1alpha1.BackupRepoEncryption{
+					Algorithm: dpv1alpha1.EncryptionAlgorithmAES256CFB,
+					KeySecretRef: corev1.SecretReference{
+						Name:      keySecretName,
+						Namespace: testCtx.DefaultNamespace,
+					},
+				}
+			})
+
+			By("creating a backupPolicy")
+			_ = testdp.NewFakeBackupPolicy(&testCtx, nil)
+		})
+
+		checkBackupEncryption := func(backup *dpv1alpha1.Backup, fingerprint string) {
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+				g.Expect(fetched.Status.Encryption).ShouldNot(BeNil())
+				g.Expect(fetched.Status.Encryption.Algorithm).Should(Equal(dpv1alpha1.EncryptionAlgorithmAES256CFB))
+				g.Expect(fetched.Status.Encryption.KeyFingerprint).Should(Equal(fingerprint))
+			})).Should(Succeed())
+		}
+
+		checkBackupJobEncryption := func(backup *dpv1alpha1.Backup, fingerprint string) {
+			secretName := dputils.GetEncryptionKeySecretName(testdp.BackupRepoName, fingerprint)
+			jobKey := client.ObjectKey{
+				Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix),
+				Namespace: backup.Namespace,
+			}
+			Eventually(testapps.CheckObj(&testCtx, jobKey, func(g Gomega, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				g.Expect(podSpec.Volumes).Should(ContainElement(dputils.BuildEncryptionKeyVolume(secretName)))
+				g.Expect(podSpec.Containers[0].VolumeMounts).Should(ContainElement(dputils.BuildEncryptionKeyVolumeMount()))
+				g.Expect(podSpec.Containers[0].Env).Should(ContainElements(dputils.BuildEncryptionEnv(
+					&dpv1alpha1.BackupEncryptionStatus{
+						Algorithm:      dpv1alpha1.EncryptionAlgorithmAES256CFB,
+						KeyFingerprint: fingerprint,
+					})))
+			})).Should(Succeed())
+		}
+
+		It("should project the encryption key into the backup job", func() {
+			fingerprint := dputils.GetEncryptionKeyFingerprint([]byte(encryptionKey))
+			backup := testdp.NewFakeBackup(&testCtx, nil)
+
+			By("checking the encryption status of the backup")
+			checkBackupEncryption(backup, fingerprint)
+
+			By("checking the encryption key secret in the namespace of the backup")
+			secretKey := client.ObjectKey{
+				Name:      dputils.GetEncryptionKeySecretName(testdp.BackupRepoName, fingerprint),
+				Namespace: backup.Namespace,
+			}
+			Eventually(testapps.CheckObj(&testCtx, secretKey, func(g Gomega, secret *corev1.Secret) {
+				g.Expect(secret.Data[dptypes.EncryptionKeySecretKey]).Should(BeEquivalentTo(encryptionKey))
+			})).Should(Succeed())
+
+			By("checking the volume, volume mount and env of the backup job")
+			checkBackupJobEncryption(backup, fingerprint)
+		})
+
+		It("should only use the rotated key for new backups", func() {
+			oldFingerprint := dputils.GetEncryptionKeyFingerprint([]byte(encryptionKey))
+			backup := testdp.NewFakeBackup(&testCtx, nil)
+			checkBackupEncryption(backup, oldFingerprint)
+
+			By("rotating the encryption key")
+			newKey := "test-key-rotated"
+			newFingerprint := dputils.GetEncryptionKeyFingerprint([]byte(newKey))
+			Eventually(testapps.GetAndChangeObj(&testCtx, client.ObjectKey{Name: keySecretName, Namespace: testCtx.DefaultNamespace},
+				func(secret *corev1.Secret) {
+					secret.Data[dptypes.EncryptionKeySecretKey] = []byte(newKey)
+				})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKey{Name: testdp.BackupRepoName}, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
+				g.Expect(repo.Status.EncryptionKeyFingerprint).Should(Equal(newFingerprint))
+			})).Should(Succeed())
+
+			By("checking the existing backup still uses the old key")
+			checkBackupEncryption(backup, oldFingerprint)
+			checkBackupJobEncryption(backup, oldFingerprint)
+
+			By("checking the new backup uses the rotated key")
+			backup2 := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+				backup.Name += "2"
+			})
+			checkBackupEncryption(backup2, newFingerprint)
+			checkBackupJobEncryption(backup2, newFingerprint)
+		})
+	})
 })
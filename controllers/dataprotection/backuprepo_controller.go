@@ -55,6 +55,7 @@ import (
 	"github.com/apecloud/kubeblocks/internal/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/internal/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/internal/generics"
 	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
@@ -65,8 +66,9 @@ type BackupRepoReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
-	secretRefMapper   refObjectMapper
-	providerRefMapper refObjectMapper
+	secretRefMapper        refObjectMapper
+	providerRefMapper      refObjectMapper
+	encryptionKeyRefMapper refObjectMapper
 }
 
 // full access on BackupRepos
@@ -124,6 +126,14 @@ func (r *BackupRepoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		})
 	}
 	r.providerRefMapper.setRef(repo, types.NamespacedName{Name: repo.Spec.StorageProviderRef})
+	if repo.EncryptionEnabled() {
+		r.encryptionKeyRefMapper.setRef(repo, types.NamespacedName{
+			Name:      repo.Spec.Encryption.KeySecretRef.Name,
+			Namespace: repo.Spec.Encryption.KeySecretRef.Namespace,
+		})
+	} else {
+		r.encryptionKeyRefMapper.removeRef(repo)
+	}
 
 	// check storage provider
 	provider, err := r.checkStorageProvider(reqCtx, repo)
@@ -167,6 +177,14 @@ func (r *BackupRepoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			"failed to check tool config")
 	}
 
+	// check encryption key
+	err = r.checkEncryptionKey(reqCtx, repo)
+	if err != nil {
+		_ = r.updateStatus(reqCtx, repo)
+		return checkedRequeueWithError(err, reqCtx.Log,
+			"failed to check encryption key")
+	}
+
 	// TODO: implement pre-check logic
 	//  1. try to create a PVC and observe its status
 	//  2. create a pre-check job, mount with the PVC and check job status
@@ -198,7 +216,8 @@ func (r *BackupRepoReconciler) updateStatus(reqCtx intctrlutil.RequestCtx, repo
 			meta.IsStatusConditionTrue(repo.Status.Conditions, ConditionTypeParametersChecked) &&
 			meta.IsStatusConditionTrue(repo.Status.Conditions, ConditionTypeStorageClassCreated) &&
 			meta.IsStatusConditionTrue(repo.Status.Conditions, ConditionTypePVCTemplateChecked) &&
-			meta.IsStatusConditionTrue(repo.Status.Conditions, ConditionTypeToolConfigChecked) {
+			meta.IsStatusConditionTrue(repo.Status.Conditions, ConditionTypeToolConfigChecked) &&
+			meta.IsStatusConditionTrue(repo.Status.Conditions, ConditionTypeEncryptionKeyChecked) {
 			phase = dpv1alpha1.BackupRepoReady
 		}
 		repo.Status.Phase = phase
@@ -537,6 +556,54 @@ func (r *BackupRepoReconciler) checkAndUpdateToolConfig(reqCtx intctrlutil.Reque
 	return nil
 }
 
+func (r *BackupRepoReconciler) checkEncryptionKey(reqCtx intctrlutil.RequestCtx,
+	repo *dpv1alpha1.BackupRepo) (err error) {
+
+	reason := ReasonUnknownError
+	defer func() {
+		r.updateConditionInDefer(reqCtx, repo, ConditionTypeEncryptionKeyChecked, reason, &err)
+	}()
+
+	fingerprint := ""
+	if repo.EncryptionEnabled() {
+		var key []byte
+		key, err = r.getEncryptionKey(reqCtx, repo)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				reason = ReasonEncryptionKeyNotFound
+			}
+			return err
+		}
+		fingerprint = dputils.GetEncryptionKeyFingerprint(key)
+	}
+	// a new fingerprint means the key has been rotated, only the backups created
+	// afterwards will use the new key.
+	if repo.Status.EncryptionKeyFingerprint != fingerprint {
+		patch := client.MergeFrom(repo.DeepCopy())
+		repo.Status.EncryptionKeyFingerprint = fingerprint
+		if err = r.Client.Status().Patch(reqCtx.Ctx, repo, patch); err != nil {
+			return err
+		}
+	}
+	reason = ReasonEncryptionKeyChecked
+	return nil
+}
+
+// getEncryptionKey gets the encryption key from the secret referenced by the repo.
+func (r *BackupRepoReconciler) getEncryptionKey(reqCtx intctrlutil.RequestCtx,
+	repo *dpv1alpha1.BackupRepo) ([]byte, error) {
+	ref := repo.Spec.Encryption.KeySecretRef
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(reqCtx.Ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, err
+	}
+	key := secret.Data[dptypes.EncryptionKeySecretKey]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("the key %q of secret %s/%s is empty", dptypes.EncryptionKeySecretKey, ref.Namespace, ref.Name)
+	}
+	return key, nil
+}
+
 func (r *BackupRepoReconciler) constructPVCByTemplate(
 	renderCtx renderContext, pvc *corev1.PersistentVolumeClaim,
 	repo *dpv1alpha1.BackupRepo, tmpl string) error {
@@ -588,6 +655,13 @@ func (r *BackupRepoReconciler) prepareForAssociatedBackups(
 	// return any error to reconcile the repo
 	var retErr error
 	for _, backup := range backups {
+		if repo.EncryptionEnabled() {
+			if err := r.checkOrCreateEncryptionKeySecret(reqCtx, repo, backup.Namespace); err != nil {
+				reqCtx.Log.Error(err, "failed to check or create encryption key secret", "namespace", backup.Namespace)
+				retErr = err
+				continue
+			}
+		}
 		switch {
 		case repo.AccessByMount():
 			if err := r.checkOrCreatePVC(reqCtx, renderCtx, repo, provider, backup.Namespace); err != nil {
@@ -712,6 +786,42 @@ func (r *BackupRepoReconciler) checkOrCreateToolConfigSecret(
 	return err
 }
 
+func (r *BackupRepoReconciler) checkOrCreateEncryptionKeySecret(
+	reqCtx intctrlutil.RequestCtx, repo *dpv1alpha1.BackupRepo, namespace string) error {
+
+	key, err := r.getEncryptionKey(reqCtx, repo)
+	if err != nil {
+		return err
+	}
+	fingerprint := dputils.GetEncryptionKeyFingerprint(key)
+	if fingerprint != repo.Status.EncryptionKeyFingerprint {
+		return fmt.Errorf("the encryption key of backup repo %s has changed, wait for it to be checked", repo.Name)
+	}
+
+	// the secret is named after the key fingerprint, so the existing backups
+	// keep using their own keys after the key is rotated.
+	secret := &corev1.Secret{}
+	secret.Name = dputils.GetEncryptionKeySecretName(repo.Name, fingerprint)
+	secret.Namespace = namespace
+	_, err = createObjectIfNotExist(reqCtx.Ctx, r.Client, secret,
+		func() error {
+			secret.Data = map[string][]byte{
+				dptypes.EncryptionKeySecretKey: key,
+			}
+			// add a referencing label
+			secret.Labels = map[string]string{
+				dataProtectionBackupRepoKey:      repo.Name,
+				dataProtectionIsEncryptionKeyKey: trueVal,
+			}
+			if err := controllerutil.SetControllerReference(repo, secret, r.Scheme); err != nil {
+				return fmt.Errorf("failed to set owner reference: %w", err)
+			}
+			return nil
+		})
+
+	return err
+}
+
 func (r *BackupRepoReconciler) collectParameters(
 	reqCtx intctrlutil.RequestCtx, repo *dpv1alpha1.BackupRepo) (map[string]string, error) {
 	values := make(map[string]string)
@@ -910,7 +1020,7 @@ func (r *BackupRepoReconciler) mapSecretToRepos(ctx context.Context, obj client.
 	}
 
 	// get repos which is referencing this secret
-	return r.secretRefMapper.mapToRequests(obj)
+	return append(r.secretRefMapper.mapToRequests(obj), r.encryptionKeyRefMapper.mapToRequests(obj)...)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -49,6 +49,7 @@ const (
 	dataProtectionBackupRepoKey          = "dataprotection.kubeblocks.io/backup-repo-name"
 	dataProtectionWaitRepoPreparationKey = "dataprotection.kubeblocks.io/wait-repo-preparation"
 	dataProtectionIsToolConfigKey        = "dataprotection.kubeblocks.io/is-tool-config"
+	dataProtectionIsEncryptionKeyKey     = "dataprotection.kubeblocks.io/is-encryption-key"
 
 	// annotation keys
 	dataProtectionSecretTemplateMD5AnnotationKey        = "dataprotection.kubeblocks.io/secret-template-md5"
@@ -65,6 +66,7 @@ const (
 	ConditionTypeStorageClassCreated   = "StorageClassCreated"
 	ConditionTypePVCTemplateChecked    = "PVCTemplateChecked"
 	ConditionTypeToolConfigChecked     = "ToolConfigSecretChecked"
+	ConditionTypeEncryptionKeyChecked  = "EncryptionKeyChecked"
 	ConditionTypeDerivedObjectsDeleted = "DerivedObjectsDeleted"
 
 	// condition reasons
@@ -81,6 +83,8 @@ const (
 	ReasonStorageClassCreated       = "StorageClassCreated"
 	ReasonPVCTemplateChecked        = "PVCTemplateChecked"
 	ReasonToolConfigChecked         = "ToolConfigChecked"
+	ReasonEncryptionKeyChecked      = "EncryptionKeyChecked"
+	ReasonEncryptionKeyNotFound     = "EncryptionKeyNotFound"
 	ReasonHaveAssociatedBackups     = "HaveAssociatedBackups"
 	ReasonHaveResidualPVCs          = "HaveResidualPVCs"
 	ReasonDerivedObjectsDeleted     = "DerivedObjectsDeleted"
@@ -85,58 +85,133 @@ func getBackupMethodByName(name string, backupPolicy *dpv1alpha1.BackupPolicy) *
 // getTargetPods gets the target pods by BackupPolicy. If podName is not empty,
 // it will return the pod which name is podName. Otherwise, it will return the
 // pods which are selected by BackupPolicy selector and strategy.
+// If no pod of the selected role is available and the fallback policy is PreferRole,
+// a ready pod of any role is returned with the reason of the fallback.
 func getTargetPods(reqCtx intctrlutil.RequestCtx,
 	cli client.Client, podName string,
-	backupPolicy *dpv1alpha1.BackupPolicy) ([]*corev1.Pod, error) {
+	backupPolicy *dpv1alpha1.BackupPolicy) ([]*corev1.Pod, string, error) {
 	selector := backupPolicy.Spec.Target.PodSelector
 	if selector == nil || selector.LabelSelector == nil {
-		return nil, nil
+		return nil, "", nil
+	}
+
+	pods, err := listTargetPods(reqCtx, cli, selector.LabelSelector)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fallback := selector.Strategy == dpv1alpha1.PodSelectionStrategyAny &&
+		selector.FallbackPolicy == dpv1alpha1.TargetFallbackPolicyPreferRole
+	if len(pods) == 0 && !fallback {
+		return nil, "", fmt.Errorf("failed to find target pods by backup policy %s/%s",
+			backupPolicy.Namespace, backupPolicy.Name)
+	}
+
+	if pod := getPodByName(pods, podName); pod != nil {
+		return []*corev1.Pod{pod}, "", nil
+	}
+
+	var targetPods []*corev1.Pod
+	switch selector.Strategy {
+	case dpv1alpha1.PodSelectionStrategyAny:
+		// prefer the ready pod, and always return the first one by name
+		if pod := getFirstReadyPod(pods); pod != nil {
+			return []*corev1.Pod{pod}, "", nil
+		}
+		if !fallback {
+			targetPods = append(targetPods, pods[0])
+			break
+		}
+		return getFallbackTargetPods(reqCtx, cli, podName, backupPolicy)
+	case dpv1alpha1.PodSelectionStrategyAll:
+		targetPods = pods
+	}
+
+	return targetPods, "", nil
+}
+
+// getFallbackTargetPods gets a ready pod matching the selector of BackupPolicy except the role,
+// it's used if no pod of the role is available.
+func getFallbackTargetPods(reqCtx intctrlutil.RequestCtx,
+	cli client.Client, podName string,
+	backupPolicy *dpv1alpha1.BackupPolicy) ([]*corev1.Pod, string, error) {
+	selector := backupPolicy.Spec.Target.PodSelector
+	role, labelSelector := removeRoleFromLabelSelector(selector.LabelSelector)
+	pods, err := listTargetPods(reqCtx, cli, labelSelector)
+	if err != nil {
+		return nil, "", err
+	}
+	pod := getPodByName(pods, podName)
+	if pod == nil || !intctrlutil.PodIsReady(pod) {
+		pod = getFirstReadyPod(pods)
 	}
+	if pod == nil {
+		return nil, "", fmt.Errorf("failed to find available target pods by backup policy %s/%s, "+
+			"neither of role %s nor of the other roles", backupPolicy.Namespace, backupPolicy.Name, role)
+	}
+	return []*corev1.Pod{pod}, fmt.Sprintf("no available pod of role %s, fall back to the pod of role %s",
+		role, pod.Labels[constant.RoleLabelKey]), nil
+}
 
-	labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+// listTargetPods lists the pods selected by the label selector, sorted by name.
+func listTargetPods(reqCtx intctrlutil.RequestCtx,
+	cli client.Client, selector *metav1.LabelSelector) ([]*corev1.Pod, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
 	if err != nil {
 		return nil, err
 	}
-	pods := &corev1.PodList{}
-	if err = cli.List(reqCtx.Ctx, pods,
+	podList := &corev1.PodList{}
+	if err = cli.List(reqCtx.Ctx, podList,
 		client.InNamespace(reqCtx.Req.Namespace),
 		client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
 		return nil, err
 	}
-
-	if len(pods.Items) == 0 {
-		return nil, fmt.Errorf("failed to find target pods by backup policy %s/%s",
-			backupPolicy.Namespace, backupPolicy.Name)
+	sort.Sort(intctrlutil.ByPodName(podList.Items))
+	pods := make([]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pods[i] = &podList.Items[i]
 	}
+	return pods, nil
+}
 
-	var targetPods []*corev1.Pod
-	if podName != "" {
-		for _, pod := range pods.Items {
-			if pod.Name == podName {
-				targetPods = append(targetPods, &pod)
-				break
-			}
+// removeRoleFromLabelSelector returns the role selected and the label selector without the role.
+func removeRoleFromLabelSelector(selector *metav1.LabelSelector) (string, *metav1.LabelSelector) {
+	selector = selector.DeepCopy()
+	role := selector.MatchLabels[constant.RoleLabelKey]
+	delete(selector.MatchLabels, constant.RoleLabelKey)
+	var expressions []metav1.LabelSelectorRequirement
+	for _, expr := range selector.MatchExpressions {
+		if expr.Key != constant.RoleLabelKey {
+			expressions = append(expressions, expr)
+			continue
 		}
-		if len(targetPods) > 0 {
-			return targetPods, nil
+		if len(role) == 0 {
+			role = strings.Join(expr.Values, ",")
 		}
 	}
+	selector.MatchExpressions = expressions
+	return role, selector
+}
 
-	strategy := selector.Strategy
-	sort.Sort(intctrlutil.ByPodName(pods.Items))
-	// if pod selection strategy is Any, always return first pod
-	switch strategy {
-	case dpv1alpha1.PodSelectionStrategyAny:
-		if len(pods.Items) > 0 {
-			targetPods = append(targetPods, &pods.Items[0])
-		}
-	case dpv1alpha1.PodSelectionStrategyAll:
-		for i := range pods.Items {
-			targetPods = append(targetPods, &pods.Items[i])
+func getPodByName(pods []*corev1.Pod, podName string) *corev1.Pod {
+	if podName == "" {
+		return nil
+	}
+	for _, pod := range pods {
+		if pod.Name == podName {
+			return pod
 		}
 	}
+	return nil
+}
 
-	return targetPods, nil
+func getFirstReadyPod(pods []*corev1.Pod) *corev1.Pod {
+	for _, pod := range pods {
+		if intctrlutil.PodIsReady(pod) {
+			return pod
+		}
+	}
+	return nil
 }
 
 // getCluster gets the cluster and will ignore the error.
@@ -28,7 +28,6 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 )
@@ -49,10 +48,11 @@ type EventReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
 func (r *EventReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, "event", req.NamespacedName)
 	reqCtx := intctrlutil.RequestCtx{
 		Ctx: ctx,
 		Req: req,
-		Log: log.FromContext(ctx).WithValues("event", req.NamespacedName),
+		Log: logger,
 	}
 
 	reqCtx.Log.V(1).Info("event watcher")
@@ -29,7 +29,6 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
@@ -54,10 +53,11 @@ var PersistentVolumeClaimHandlerMap = map[string]HandlePersistentVolumeClaim{}
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
 func (r *PersistentVolumeClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, "PersistentVolumeClaim", req.NamespacedName)
 	reqCtx := intctrlutil.RequestCtx{
 		Ctx: ctx,
 		Req: req,
-		Log: log.FromContext(ctx).WithValues("PersistentVolumeClaim", req.NamespacedName),
+		Log: logger,
 	}
 
 	reqCtx.Log.V(1).Info("PersistentVolumeClaim watcher")
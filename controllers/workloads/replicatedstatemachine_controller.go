@@ -22,14 +22,18 @@ package workloads
 import (
 	"context"
 
+	"golang.org/x/exp/slices"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlhandler "sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
@@ -50,6 +54,7 @@ type ReplicatedStateMachineReconciler struct {
 //+kubebuilder:rbac:groups=workloads.kubeblocks.io,resources=replicatedstatemachines,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=workloads.kubeblocks.io,resources=replicatedstatemachines/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=workloads.kubeblocks.io,resources=replicatedstatemachines/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -61,10 +66,11 @@ type ReplicatedStateMachineReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.1/pkg/reconcile
 func (r *ReplicatedStateMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := intctrlutil.NewReconcileLogger(ctx, "ReplicatedStateMachine", req.NamespacedName)
 	reqCtx := intctrlutil.RequestCtx{
 		Ctx:      ctx,
 		Req:      req,
-		Log:      log.FromContext(ctx).WithValues("ReplicatedStateMachine", req.NamespacedName),
+		Log:      logger,
 		Recorder: r.Recorder,
 	}
 
@@ -112,6 +118,8 @@ func (r *ReplicatedStateMachineReconciler) Reconcile(ctx context.Context, req ct
 			&rsm.UpdateStrategyTransformer{},
 			// handle member reconfiguration
 			&rsm.MemberReconfigurationTransformer{},
+			// move the members off the terminating nodes
+			&rsm.NodeTerminationTransformer{},
 			// always safe to put your transformer below
 		).
 		Build()
@@ -140,6 +148,18 @@ func (r *ReplicatedStateMachineReconciler) SetupWithManager(mgr ctrl.Manager) er
 		Scheme:  *r.Scheme,
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, podNodeNameIndexKey,
+		func(obj client.Object) []string {
+			pod, _ := obj.(*corev1.Pod)
+			if pod == nil || len(pod.Spec.NodeName) == 0 {
+				return nil
+			}
+			return []string{pod.Spec.NodeName}
+		}); err != nil {
+		return err
+	}
+	nodeHandler := ctrlhandler.EnqueueRequestsFromMapFunc(r.findRSMsOnTerminatingNode)
+
 	if viper.GetBool(rsm.FeatureGateRSMCompatibilityMode) {
 		nameLabels := []string{constant.AppInstanceLabelKey, constant.KBAppComponentLabelKey}
 		delegatorFinder := handler.NewDelegatorFinder(&workloads.ReplicatedStateMachine{}, nameLabels)
@@ -153,6 +173,7 @@ func (r *ReplicatedStateMachineReconciler) SetupWithManager(mgr ctrl.Manager) er
 			Watches(&appsv1.StatefulSet{}, stsHandler).
 			Watches(&batchv1.Job{}, jobHandler).
 			Watches(&corev1.Pod{}, podHandler).
+			Watches(&corev1.Node{}, nodeHandler).
 			Complete(r)
 	}
 
@@ -164,5 +185,34 @@ func (r *ReplicatedStateMachineReconciler) SetupWithManager(mgr ctrl.Manager) er
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&batchv1.Job{}).
 		Watches(&corev1.Pod{}, podHandler).
+		Watches(&corev1.Node{}, nodeHandler).
 		Complete(r)
 }
+
+const podNodeNameIndexKey = "spec.nodeName"
+
+// findRSMsOnTerminatingNode maps the terminating node to the rsms having members on it, so they can be moved off
+// before the node is terminated.
+func (r *ReplicatedStateMachineReconciler) findRSMsOnTerminatingNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	node, ok := obj.(*corev1.Node)
+	if !ok || !rsm.IsNodeTerminating(node, rsm.GetNodeTerminationTaintKeys()) {
+		return nil
+	}
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.MatchingFields{podNodeNameIndexKey: node.Name}); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil || owner.Kind != "StatefulSet" {
+			continue
+		}
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: rsm.GetRSMNameOfPod(pod)}}
+		if !slices.Contains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
@@ -45,6 +45,12 @@ type ExtendAnalyze struct {
 	// Taint is to Determine the matching between the taint and toleration
 	// +optional
 	Taint *KBTaintAnalyze `json:"taint,omitempty"`
+	// VolumeSnapshotClass is to determine the presence of CSI volume snapshot class
+	// +optional
+	VolumeSnapshotClass *KBVolumeSnapshotClassAnalyze `json:"volumeSnapshotClass,omitempty"`
+	// Webhook is to determine the health of the KubeBlocks webhooks in a running installation
+	// +optional
+	Webhook *KBWebhookAnalyze `json:"webhook,omitempty"`
 }
 
 type HostUtility struct {
@@ -121,6 +127,29 @@ type KBTaintAnalyze struct {
 	TolerationsMap map[string][]v1.Toleration `json:"tolerations"`
 }
 
+// KBVolumeSnapshotClassAnalyze checks the presence of VolumeSnapshotClass, which is required by backup snapshot and data clone
+type KBVolumeSnapshotClassAnalyze struct {
+	// AnalyzeMeta is defined in troubleshoot.sh
+	troubleshoot.AnalyzeMeta `json:",inline"`
+	// Outcomes are expected user defined results.
+	// +kubebuilder:validation:Required
+	Outcomes []*troubleshoot.Outcome `json:"outcomes"`
+	// StorageClassName is the StorageClass whose provisioner the VolumeSnapshotClass driver should match,
+	// the default StorageClass is used if it's not set.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+}
+
+// KBWebhookAnalyze checks whether the KubeBlocks webhooks point at ready services, whether the admission requests
+// round-trip and whether the webhook certificates are about to expire
+type KBWebhookAnalyze struct {
+	// AnalyzeMeta is defined in troubleshoot.sh
+	troubleshoot.AnalyzeMeta `json:",inline"`
+	// Outcomes are expected user defined results.
+	// +kubebuilder:validation:Required
+	Outcomes []*troubleshoot.Outcome `json:"outcomes"`
+}
+
 type ExtendHostAnalyze struct {
 	// HostUtility is to analyze the presence of target utility
 	// +optional
@@ -123,6 +123,16 @@ func (in *ExtendAnalyze) DeepCopyInto(out *ExtendAnalyze) {
 		*out = new(KBTaintAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VolumeSnapshotClass != nil {
+		in, out := &in.VolumeSnapshotClass, &out.VolumeSnapshotClass
+		*out = new(KBVolumeSnapshotClassAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(KBWebhookAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendAnalyze.
@@ -427,6 +437,60 @@ func (in *KBTaintAnalyze) DeepCopy() *KBTaintAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KBVolumeSnapshotClassAnalyze) DeepCopyInto(out *KBVolumeSnapshotClassAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*troubleshootv1beta2.Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(troubleshootv1beta2.Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KBVolumeSnapshotClassAnalyze.
+func (in *KBVolumeSnapshotClassAnalyze) DeepCopy() *KBVolumeSnapshotClassAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(KBVolumeSnapshotClassAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KBWebhookAnalyze) DeepCopyInto(out *KBWebhookAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*troubleshootv1beta2.Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(troubleshootv1beta2.Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KBWebhookAnalyze.
+func (in *KBWebhookAnalyze) DeepCopy() *KBWebhookAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(KBWebhookAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Preflight) DeepCopyInto(out *Preflight) {
 	*out = *in
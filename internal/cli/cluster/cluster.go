@@ -267,6 +267,8 @@ func (o *ClusterObjects) GetClusterInfo() *ClusterInfo {
 		TerminationPolicy: string(c.Spec.TerminationPolicy),
 		Status:            string(c.Status.Phase),
 		CreatedTime:       util.TimeFormat(&c.CreationTimestamp),
+		Age:               util.GetHumanReadableDuration(c.CreationTimestamp, metav1.Time{}),
+		Components:        len(c.Spec.ComponentSpecs),
 		InternalEP:        types.None,
 		ExternalEP:        types.None,
 		Labels:            util.CombineLabels(c.Labels),
@@ -344,6 +344,9 @@ func (info *CompInfo) InferPodName() (string, error) {
 	if info.ComponentStatus.Phase != appsv1alpha1.RunningClusterCompPhase || !*info.ComponentStatus.PodsReady {
 		return "", fmt.Errorf("component is not ready, please try later")
 	}
+	if len(info.ComponentStatus.LeaderPod) > 0 {
+		return info.ComponentStatus.LeaderPod, nil
+	}
 	if info.ComponentStatus.ConsensusSetStatus != nil {
 		return info.ComponentStatus.ConsensusSetStatus.Leader.Pod, nil
 	}
@@ -20,10 +20,18 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package cluster
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/cli/testing"
 )
 
 var _ = Describe("printer", func() {
@@ -63,5 +71,53 @@ var _ = Describe("printer", func() {
 		It("print instance info", func() {
 			Expect(printObjs(NewPrinter(os.Stdout, PrintInstances, nil), objs)).Should(Succeed())
 		})
+
+		It("print the phase, age and component count columns of clusters", func() {
+			newClusterObjs := func(name string, phase appsv1alpha1.ClusterPhase, age time.Duration, compNum int) *ClusterObjects {
+				clusterObjs := FakeClusterObjs()
+				clusterObjs.Cluster = testing.FakeCluster(name, testing.Namespace)
+				clusterObjs.Cluster.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+				clusterObjs.Cluster.Status.Phase = phase
+				for i := 1; i < compNum; i++ {
+					comp := clusterObjs.Cluster.Spec.ComponentSpecs[0].DeepCopy()
+					comp.Name = fmt.Sprintf("%s-%d", comp.Name, i)
+					clusterObjs.Cluster.Spec.ComponentSpecs = append(clusterObjs.Cluster.Spec.ComponentSpecs, *comp)
+				}
+				return clusterObjs
+			}
+			clusters := []*ClusterObjects{
+				newClusterObjs("mysql", appsv1alpha1.RunningClusterPhase, 2*time.Hour, 1),
+				newClusterObjs("redis", appsv1alpha1.AbnormalClusterPhase, 3*24*time.Hour, 2),
+			}
+			render := func(printType PrintType) [][]string {
+				out := &bytes.Buffer{}
+				p := NewPrinter(out, printType, nil)
+				for _, objs := range clusters {
+					p.AddRow(objs)
+				}
+				p.Print()
+				var rows [][]string
+				for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+					rows = append(rows, strings.Fields(line))
+				}
+				return rows
+			}
+
+			rows := render(PrintClusters)
+			Expect(rows).Should(HaveLen(3))
+			Expect(rows[0]).Should(Equal([]string{"NAME", "NAMESPACE", "CLUSTER-DEFINITION", "VERSION", "TERMINATION-POLICY", "STATUS", "CREATED-TIME", "AGE"}))
+			Expect(rows[1][:6]).Should(Equal([]string{"mysql", testing.Namespace, testing.ClusterDefName, testing.ClusterVersionName, string(appsv1alpha1.WipeOut), string(appsv1alpha1.RunningClusterPhase)}))
+			Expect(rows[1][len(rows[1])-1]).Should(Equal("120m"))
+			Expect(rows[2][:6]).Should(Equal([]string{"redis", testing.Namespace, testing.ClusterDefName, testing.ClusterVersionName, string(appsv1alpha1.WipeOut), string(appsv1alpha1.AbnormalClusterPhase)}))
+			Expect(rows[2][len(rows[2])-1]).Should(Equal("3d"))
+
+			rows = render(PrintWide)
+			Expect(rows).Should(HaveLen(3))
+			Expect(rows[0]).Should(Equal([]string{"NAME", "NAMESPACE", "CLUSTER-DEFINITION", "VERSION", "TERMINATION-POLICY", "STATUS", "COMPONENTS", "INTERNAL-ENDPOINTS", "EXTERNAL-ENDPOINTS", "CREATED-TIME", "AGE"}))
+			Expect(rows[1][6]).Should(Equal("1"))
+			Expect(rows[1][len(rows[1])-1]).Should(Equal("120m"))
+			Expect(rows[2][6]).Should(Equal("2"))
+			Expect(rows[2][len(rows[2])-1]).Should(Equal("3d"))
+		})
 	})
 })
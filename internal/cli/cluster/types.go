@@ -53,6 +53,8 @@ type ClusterInfo struct {
 	InternalEP        string `json:"internalEP,omitempty"`
 	ExternalEP        string `json:"externalEP,omitempty"`
 	CreatedTime       string `json:"age,omitempty"`
+	Age               string `json:"elapsed,omitempty"`
+	Components        int    `json:"components,omitempty"`
 	Labels            string `json:"labels,omitempty"`
 }
 
@@ -198,19 +198,8 @@ func (o *CreateOptions) run() error {
 		}
 		classNames = append(classNames, item.Name)
 
-		if len(rules) == 0 {
-			continue
-		}
-
-		match := false
-		for _, rule := range rules {
-			if rule.ValidateResources(item.ToResourceRequirements().Requests) {
-				match = true
-				break
-			}
-		}
-		if !match {
-			return fmt.Errorf("class %s does not conform to its constraints", item.Name)
+		if err = validateClassConstraints(item, rules); err != nil {
+			return err
 		}
 	}
 
@@ -265,6 +254,59 @@ func (o *CreateOptions) run() error {
 	return nil
 }
 
+// validateClassConstraints checks if the class conforms to any of the constraint rules, the error
+// tells which check of every rule the class failed.
+func validateClassConstraints(cls *v1alpha1.ComponentClass, rules []v1alpha1.ResourceConstraintRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	var reasons []string
+	for i := range rules {
+		reason := checkClassConstraint(cls, &rules[i])
+		if reason == "" {
+			return nil
+		}
+		reasons = append(reasons, fmt.Sprintf("rule %s: %s", rules[i].Name, reason))
+	}
+	return fmt.Errorf("class %s does not conform to its constraints, %s", cls.Name, strings.Join(reasons, "; "))
+}
+
+// checkClassConstraint returns the reason why the class violates the rule, or empty if it conforms to the rule.
+func checkClassConstraint(cls *v1alpha1.ComponentClass, rule *v1alpha1.ResourceConstraintRule) string {
+	cpu, memory := cls.CPU, cls.Memory
+	if !rule.ValidateCPU(&cpu) {
+		c := rule.CPU
+		switch {
+		case c.Min != nil && c.Min.Cmp(cpu) > 0:
+			return fmt.Sprintf("cpu %s is less than the minimum %s", cpu.String(), c.Min.String())
+		case c.Max != nil && c.Max.Cmp(cpu) < 0:
+			return fmt.Sprintf("cpu %s is greater than the maximum %s", cpu.String(), c.Max.String())
+		case len(c.Slots) > 0:
+			var slots []string
+			for _, slot := range c.Slots {
+				slots = append(slots, slot.String())
+			}
+			return fmt.Sprintf("cpu %s is not one of the slots [%s]", cpu.String(), strings.Join(slots, ","))
+		case c.Step != nil:
+			return fmt.Sprintf("cpu %s is not a multiple of the step %s", cpu.String(), c.Step.String())
+		default:
+			return fmt.Sprintf("cpu %s is invalid", cpu.String())
+		}
+	}
+	if !rule.ValidateMemory(&cpu, &memory) {
+		m := rule.Memory
+		switch {
+		case m.SizePerCPU != nil && !m.SizePerCPU.IsZero():
+			return fmt.Sprintf("memory %s does not match %s per cpu core", normalizeMemory(memory), m.SizePerCPU.String())
+		case m.MinPerCPU != nil && m.MaxPerCPU != nil:
+			return fmt.Sprintf("memory %s is out of the range [%s, %s] per cpu core", normalizeMemory(memory), m.MinPerCPU.String(), m.MaxPerCPU.String())
+		default:
+			return fmt.Sprintf("memory %s is invalid", normalizeMemory(memory))
+		}
+	}
+	return ""
+}
+
 func registerFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
 	util.CheckErr(cmd.RegisterFlagCompletionFunc(
 		"cluster-definition",
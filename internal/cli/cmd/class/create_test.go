@@ -110,11 +110,18 @@ var _ = Describe("create", func() {
 			It("should fail if not conformed to constraint", func() {
 				By("memory not conformed to constraint")
 				fillResources(createOptions, "2", "9Gi")
-				Expect(createOptions.run()).Should(HaveOccurred())
+				err := createOptions.run()
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).Should(ContainSubstring("rule c1: memory 9Gi does not match 1Gi per cpu core"))
+				Expect(err.Error()).Should(ContainSubstring("rule c2: memory 9Gi does not match 16Gi per cpu core"))
 
 				By("CPU with invalid step")
 				fillResources(createOptions, "0.6", "0.6Gi")
-				Expect(createOptions.run()).Should(HaveOccurred())
+				err = createOptions.run()
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).Should(ContainSubstring("rule c1: cpu 600m is not a multiple of the step 500m"))
+				Expect(err.Error()).Should(ContainSubstring("rule c2: cpu 600m is less than the minimum 2"))
+				Expect(err.Error()).Should(ContainSubstring("rule c3: cpu 600m is not one of the slots [2,4,8,16,24,32,48,64,96,128]"))
 			})
 
 			It("should fail if class name is conflicted", func() {
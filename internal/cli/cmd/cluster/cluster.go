@@ -50,6 +50,7 @@ func NewClusterCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 				NewCreateCmd(f, streams),
 				NewConnectCmd(f, streams),
 				NewDescribeCmd(f, streams),
+				NewStatusCmd(f, streams),
 				NewListCmd(f, streams),
 				NewListInstancesCmd(f, streams),
 				NewListComponentsCmd(f, streams),
@@ -71,10 +72,12 @@ func NewClusterCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 				NewVerticalScalingCmd(f, streams),
 				NewHorizontalScalingCmd(f, streams),
 				NewPromoteCmd(f, streams),
+				NewDemoteCmd(f, streams),
 				NewDescribeOpsCmd(f, streams),
 				NewListOpsCmd(f, streams),
 				NewDeleteOpsCmd(f, streams),
 				NewExposeCmd(f, streams),
+				NewMonitorCmd(f, streams),
 				NewCancelCmd(f, streams),
 			},
 		},
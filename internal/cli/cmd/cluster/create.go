@@ -116,6 +116,11 @@ var clusterCreateExample = templates.Examples(`
 	cat << EOF | kbcli cluster create mycluster --cluster-definition apecloud-mysql --set-file -
 	- name: my-test ...
 
+	# Create the clusters defined in a manifest with multiple documents in the order of their dependencies,
+	# a cluster declares its dependencies by the annotation kubeblocks.io/depends-on, e.g. kubeblocks.io/depends-on: redis-cluster,
+	# and --wait waits for the dependencies to be Running before creating it
+	kbcli cluster create --file clusters.yaml --wait
+
 	# Create a cluster scattered by nodes
 	kbcli cluster create --cluster-definition apecloud-mysql --topology-keys kubernetes.io/hostname \
 		--pod-anti-affinity Required
@@ -234,6 +239,11 @@ type CreateOptions struct {
 	// backup config
 	BackupConfig *appsv1alpha1.ClusterBackup `json:"backupConfig,omitempty"`
 
+	// File is the manifest with one or more clusters to create
+	File    string        `json:"-"`
+	Wait    bool          `json:"-"`
+	Timeout time.Duration `json:"-"`
+
 	Cmd *cobra.Command `json:"-"`
 
 	UpdatableFlags
@@ -249,6 +259,10 @@ func NewCreateCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.CheckErr(o.CreateOptions.Complete())
+			if len(o.File) > 0 {
+				cmdutil.CheckErr(o.createClustersFromManifest())
+				return
+			}
 			cmdutil.CheckErr(o.Complete())
 			cmdutil.CheckErr(o.Validate())
 			cmdutil.CheckErr(o.Run())
@@ -263,6 +277,9 @@ func NewCreateCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 	cmd.Flags().StringVar(&o.Backup, "backup", "", "Set a source backup to restore data")
 	cmd.Flags().StringVar(&o.RestoreTime, "restore-to-time", "", "Set a time for point in time recovery")
 	cmd.Flags().StringVar(&o.RestoreManagementPolicy, "volume-restore-policy", "Parallel", "the volume claim restore policy, supported values: [Serial, Parallel]")
+	cmd.Flags().StringVar(&o.File, "file", "", "Use yaml file, URL, or stdin with one or more Cluster objects to create, the clusters are created in the order of their dependencies specified by the annotation "+types.ClusterDependsOnAnnotationKey)
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait for the dependencies of each cluster in --file to be Running before creating it. It will wait for a --timeout period")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 30*time.Minute, "Time to wait for each dependency to be Running, such as --timeout=10m")
 	cmd.Flags().BoolVar(&o.RBACEnabled, "rbac-enabled", false, "Specify whether rbac resources will be created by kbcli, otherwise KubeBlocks server will try to create rbac resources")
 	cmd.PersistentFlags().BoolVar(&o.EditBeforeCreate, "edit", o.EditBeforeCreate, "Edit the API resource before creating")
 	cmd.PersistentFlags().StringVar(&o.DryRun, "dry-run", "none", `Must be "client", or "server". If with client strategy, only print the object that would be sent, and no data is actually sent. If with server strategy, submit the server-side request, but no data is persistent.`)
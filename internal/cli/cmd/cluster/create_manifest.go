@@ -0,0 +1,222 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/cli/spinner"
+	"github.com/apecloud/kubeblocks/internal/cli/types"
+	"github.com/apecloud/kubeblocks/internal/cli/util"
+)
+
+// parseClustersManifest parses the Cluster objects from a manifest with one or more YAML or JSON documents.
+func parseClustersManifest(data []byte) ([]*appsv1alpha1.Cluster, error) {
+	var (
+		clusters []*appsv1alpha1.Cluster
+		names    = map[string]bool{}
+	)
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse the manifest: %v", err)
+		}
+		// skip the empty documents
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() != types.KindCluster {
+			return nil, fmt.Errorf("only the %s objects are supported in the manifest, but got %s %s", types.KindCluster, obj.GetKind(), obj.GetName())
+		}
+		cls := &appsv1alpha1.Cluster{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cls); err != nil {
+			return nil, err
+		}
+		if len(cls.Name) == 0 {
+			return nil, fmt.Errorf("the name of the cluster #%d in the manifest is empty", len(clusters)+1)
+		}
+		if names[cls.Name] {
+			return nil, fmt.Errorf("cluster %s is defined more than once in the manifest", cls.Name)
+		}
+		names[cls.Name] = true
+		clusters = append(clusters, cls)
+	}
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no cluster is found in the manifest")
+	}
+	return clusters, nil
+}
+
+// getClusterDependencies gets the names of the clusters which the cluster depends on by the annotation kubeblocks.io/depends-on.
+func getClusterDependencies(cls *appsv1alpha1.Cluster) []string {
+	var deps []string
+	for _, name := range strings.Split(cls.Annotations[types.ClusterDependsOnAnnotationKey], ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			deps = append(deps, name)
+		}
+	}
+	return deps
+}
+
+// sortClustersByDependencies sorts the clusters to make each cluster be created after its dependencies,
+// the clusters without dependencies between each other keep their order in the manifest.
+// The dependencies not defined in the manifest are supposed to exist already.
+func sortClustersByDependencies(clusters []*appsv1alpha1.Cluster) ([]*appsv1alpha1.Cluster, error) {
+	inManifest := map[string]bool{}
+	for _, cls := range clusters {
+		inManifest[cls.Name] = true
+	}
+	var (
+		sorted  []*appsv1alpha1.Cluster
+		created = map[string]bool{}
+	)
+	for len(sorted) < len(clusters) {
+		progressed := false
+		for _, cls := range clusters {
+			if created[cls.Name] {
+				continue
+			}
+			ready := true
+			for _, dep := range getClusterDependencies(cls) {
+				if dep == cls.Name {
+					return nil, fmt.Errorf("cluster %s depends on itself", cls.Name)
+				}
+				if inManifest[dep] && !created[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, cls)
+				created[cls.Name] = true
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			var cycle []string
+			for _, cls := range clusters {
+				if !created[cls.Name] {
+					cycle = append(cycle, cls.Name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle detected among clusters: %s", strings.Join(cycle, ", "))
+		}
+	}
+	return sorted, nil
+}
+
+// createClustersFromManifest creates the clusters in the manifest in the order of their dependencies.
+// If --wait is specified, the dependencies are waited to be Running before creating the dependent cluster.
+func (o *CreateOptions) createClustersFromManifest() error {
+	data, err := MultipleSourceComponents(o.File, o.IOStreams.In)
+	if err != nil {
+		return err
+	}
+	clusters, err := parseClustersManifest(data)
+	if err != nil {
+		return err
+	}
+	if clusters, err = sortClustersByDependencies(clusters); err != nil {
+		return err
+	}
+
+	var created []string
+	for i, cls := range clusters {
+		if err = o.createClusterFromManifest(cls); err != nil {
+			var skipped []string
+			for _, c := range clusters[i+1:] {
+				skipped = append(skipped, c.Name)
+			}
+			printManifestCreationSummary(o.ErrOut, created, skipped)
+			return fmt.Errorf("failed to create cluster %s: %v", cls.Name, err)
+		}
+		created = append(created, cls.Name)
+	}
+	return nil
+}
+
+func (o *CreateOptions) createClusterFromManifest(cls *appsv1alpha1.Cluster) error {
+	if len(cls.Namespace) == 0 {
+		cls.Namespace = o.Namespace
+	}
+	if o.Wait {
+		for _, dep := range getClusterDependencies(cls) {
+			if err := o.waitForClusterRunning(client.ObjectKey{Namespace: cls.Namespace, Name: dep}); err != nil {
+				return fmt.Errorf("dependency %s is not running: %v", dep, err)
+			}
+		}
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cls)
+	if err != nil {
+		return err
+	}
+	resObj := &unstructured.Unstructured{Object: obj}
+	// the status is managed by the controller
+	unstructured.RemoveNestedField(resObj.Object, "status")
+	if _, err = o.Dynamic.Resource(types.ClusterGVR()).Namespace(cls.Namespace).Create(context.TODO(), resObj, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Cluster %s created\n", cls.Name)
+	return nil
+}
+
+// waitForClusterRunning waits for the cluster to be Running within the --timeout period.
+func (o *CreateOptions) waitForClusterRunning(key client.ObjectKey) error {
+	s := spinner.New(o.Out, spinner.WithMessage(fmt.Sprintf("%-50s", fmt.Sprintf("Wait for cluster %s to be Running", key.Name))))
+	if err := wait.PollImmediate(5*time.Second, o.Timeout, func() (bool, error) {
+		cls := &appsv1alpha1.Cluster{}
+		if err := util.GetResourceObjectFromGVR(types.ClusterGVR(), key, o.Dynamic, cls); err != nil {
+			return false, err
+		}
+		return cls.Status.Phase == appsv1alpha1.RunningClusterPhase, nil
+	}); err != nil {
+		s.Fail()
+		return err
+	}
+	s.Success()
+	return nil
+}
+
+func printManifestCreationSummary(out io.Writer, created, skipped []string) {
+	if len(created) > 0 {
+		fmt.Fprintf(out, "Created clusters: %s\n", strings.Join(created, ", "))
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(out, "Skipped clusters: %s\n", strings.Join(skipped, ", "))
+	}
+}
@@ -37,6 +37,7 @@ import (
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/class"
 	"github.com/apecloud/kubeblocks/internal/cli/cluster"
+	"github.com/apecloud/kubeblocks/internal/cli/create"
 	"github.com/apecloud/kubeblocks/internal/cli/testing"
 	"github.com/apecloud/kubeblocks/internal/cli/types"
 	"github.com/apecloud/kubeblocks/internal/cli/util"
@@ -727,4 +728,113 @@ var _ = Describe("create", func() {
 
 	})
 
+	Context("create clusters from manifest", func() {
+		const manifest = `
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: Cluster
+metadata:
+  name: proxy-cluster
+  annotations:
+    kubeblocks.io/depends-on: redis-cluster, mysql-cluster
+spec:
+  clusterDefinitionRef: proxy
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: Cluster
+metadata:
+  name: mysql-cluster
+  annotations:
+    kubeblocks.io/depends-on: redis-cluster
+spec:
+  clusterDefinitionRef: apecloud-mysql
+---
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: Cluster
+metadata:
+  name: redis-cluster
+spec:
+  clusterDefinitionRef: redis
+`
+		clusterNames := func(clusters []*appsv1alpha1.Cluster) []string {
+			var names []string
+			for _, cls := range clusters {
+				names = append(names, cls.Name)
+			}
+			return names
+		}
+
+		newCluster := func(name string, deps ...string) *appsv1alpha1.Cluster {
+			cls := &appsv1alpha1.Cluster{}
+			cls.Name = name
+			if len(deps) > 0 {
+				cls.Annotations = map[string]string{types.ClusterDependsOnAnnotationKey: strings.Join(deps, ",")}
+			}
+			return cls
+		}
+
+		It("parse the clusters from a multi-document manifest", func() {
+			clusters, err := parseClustersManifest([]byte(manifest))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(clusterNames(clusters)).Should(Equal([]string{"proxy-cluster", "mysql-cluster", "redis-cluster"}))
+			Expect(getClusterDependencies(clusters[0])).Should(Equal([]string{"redis-cluster", "mysql-cluster"}))
+
+			By("non-cluster object in the manifest")
+			_, err = parseClustersManifest([]byte(manifest + "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"))
+			Expect(err).Should(HaveOccurred())
+
+			By("cluster defined more than once")
+			_, err = parseClustersManifest([]byte(manifest + "---\napiVersion: apps.kubeblocks.io/v1alpha1\nkind: Cluster\nmetadata:\n  name: redis-cluster\n"))
+			Expect(err).Should(HaveOccurred())
+
+			By("empty manifest")
+			_, err = parseClustersManifest([]byte("---\n"))
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("sort the clusters by dependencies", func() {
+			clusters, err := parseClustersManifest([]byte(manifest))
+			Expect(err).ShouldNot(HaveOccurred())
+			sorted, err := sortClustersByDependencies(clusters)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(clusterNames(sorted)).Should(Equal([]string{"redis-cluster", "mysql-cluster", "proxy-cluster"}))
+
+			By("the clusters without dependencies keep their order, the external dependencies are ignored")
+			sorted, err = sortClustersByDependencies([]*appsv1alpha1.Cluster{
+				newCluster("c1", "c3"), newCluster("c2", "external"), newCluster("c3"), newCluster("c4")})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(clusterNames(sorted)).Should(Equal([]string{"c2", "c3", "c1", "c4"}))
+
+			By("dependency cycle")
+			_, err = sortClustersByDependencies([]*appsv1alpha1.Cluster{
+				newCluster("c1", "c3"), newCluster("c2", "c1"), newCluster("c3", "c2"), newCluster("c4")})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("dependency cycle detected among clusters: c1, c2, c3"))
+
+			By("depends on itself")
+			_, err = sortClustersByDependencies([]*appsv1alpha1.Cluster{newCluster("c1", "c1")})
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("create the clusters in order and report the skipped ones on failure", func() {
+			streams, in, out, errOut := genericclioptions.NewTestIOStreams()
+			in.WriteString(manifest)
+			o := &CreateOptions{
+				File: "-",
+				CreateOptions: create.CreateOptions{
+					Namespace: testing.Namespace,
+					// mysql-cluster exists already
+					Dynamic:   testing.FakeDynamicClient(testing.FakeCluster("mysql-cluster", testing.Namespace)),
+					IOStreams: streams,
+				},
+			}
+			err := o.createClustersFromManifest()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("failed to create cluster mysql-cluster"))
+			Expect(err.Error()).Should(ContainSubstring("already exists"))
+			Expect(out.String()).Should(ContainSubstring("Cluster redis-cluster created"))
+			Expect(errOut.String()).Should(ContainSubstring("Created clusters: redis-cluster"))
+			Expect(errOut.String()).Should(ContainSubstring("Skipped clusters: proxy-cluster"))
+		})
+	})
 })
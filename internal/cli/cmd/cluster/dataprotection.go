@@ -1043,12 +1043,16 @@ func realPrintPairStringToLine(name, value string, spaceCount ...int) {
 	}
 }
 
-// print the pod error logs if failure reason has occurred
-// TODO: the failure reason should be improved in the backup controller
+// print the pod error logs if failure reason has occurred, the failure reason set by the backup controller
+// may contain the logs excerpt of the failed job already.
 func (o *DescribeBackupOptions) enhancePrintFailureReason(backupName, failureReason string, spaceCount ...int) error {
 	if failureReason == "" {
 		return nil
 	}
+	if strings.Contains(failureReason, dptypes.FailureReasonLogsPrefix) {
+		printer.PrintPairStringToLine("Failure Reason", failureReason, spaceCount...)
+		return nil
+	}
 	ctx := context.Background()
 	// get the latest job log details.
 	labels := fmt.Sprintf("%s=%s",
@@ -48,6 +48,7 @@ import (
 	"github.com/apecloud/kubeblocks/internal/cli/types"
 	"github.com/apecloud/kubeblocks/internal/cli/util"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 )
 
 var _ = Describe("DataProtection", func() {
@@ -391,6 +392,15 @@ var _ = Describe("DataProtection", func() {
 		Expect(o.Complete(args)).Should(Succeed())
 		o.client = testing.FakeClientSet()
 		Expect(o.Run()).Should(Succeed())
+
+		By("test describe-backup with the logs excerpt in the failure reason")
+		backup1.Status.Phase = dpv1alpha1.BackupPhaseFailed
+		backup1.Status.FailureReason = fmt.Sprintf("action backup failed, container backup of pod %s-0 exited with code 1, %s\nno space left on device",
+			backupName, dptypes.FailureReasonLogsPrefix)
+		tf.FakeDynamicClient = testing.FakeDynamicClient(backup1)
+		Expect(o.Complete(args)).Should(Succeed())
+		o.client = testing.FakeClientSet()
+		Expect(o.Run()).Should(Succeed())
 	})
 
 	It("describe-backup-policy", func() {
@@ -20,12 +20,18 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package cluster
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/dynamic"
@@ -39,13 +45,24 @@ import (
 	"github.com/apecloud/kubeblocks/internal/cli/printer"
 	"github.com/apecloud/kubeblocks/internal/cli/types"
 	"github.com/apecloud/kubeblocks/internal/cli/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
 	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 )
 
 var (
 	describeExample = templates.Examples(`
 		# describe a specified cluster
-		kbcli cluster describe mycluster`)
+		kbcli cluster describe mycluster
+
+		# describe a specified cluster with the history of spec changes
+		kbcli cluster describe mycluster --history
+
+		# describe a specified cluster with the topology tree of components, pods and volumes
+		kbcli cluster describe mycluster --topology
+
+		# export the topology of a specified cluster as a Graphviz graph
+		kbcli cluster describe mycluster --output dot | dot -Tsvg > mycluster.svg`)
 
 	newTbl = func(out io.Writer, title string, header ...interface{}) *printer.TablePrinter {
 		fmt.Fprintln(out, title)
@@ -65,6 +82,15 @@ type describeOptions struct {
 	gvr   schema.GroupVersionResource
 	names []string
 
+	// showHistory shows the history of the cluster spec changes
+	showHistory bool
+
+	// showTopology shows the topology tree of the cluster
+	showTopology bool
+
+	// output is the output format, only the topology is exported in the DOT language if it's dot
+	output string
+
 	*cluster.ClusterObjects
 	genericclioptions.IOStreams
 }
@@ -89,6 +115,9 @@ func NewDescribeCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cob
 			util.CheckErr(o.run())
 		},
 	}
+	cmd.Flags().BoolVar(&o.showHistory, "history", false, "Show the history of the cluster spec changes")
+	cmd.Flags().BoolVar(&o.showTopology, "topology", false, "Show the topology tree of the components, pods and volumes")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output format, only \"dot\" is supported to export the topology as a Graphviz graph")
 	return cmd
 }
 
@@ -100,6 +129,10 @@ func (o *describeOptions) complete(args []string) error {
 	}
 	o.names = args
 
+	if len(o.output) > 0 && o.output != topologyOutputDOT {
+		return fmt.Errorf("unsupported output format %q, only %q is supported", o.output, topologyOutputDOT)
+	}
+
 	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
 		return err
 	}
@@ -143,14 +176,25 @@ func (o *describeOptions) describeCluster(name string) error {
 		return err
 	}
 
+	if o.output == topologyOutputDOT {
+		printTopologyDOT(buildClusterTopology(o.ClusterObjects), o.Out)
+		return nil
+	}
+
 	// cluster summary
 	showCluster(o.Cluster, o.Out)
 
+	// components
+	showComponents(o.Cluster, o.Out)
+
 	// show endpoints
 	showEndpoints(o.Cluster, o.Services, o.Out)
 
 	// topology
 	showTopology(o.ClusterObjects.GetInstanceInfo(), o.Out)
+	if o.showTopology {
+		showTopologyTree(buildClusterTopology(o.ClusterObjects), o.Out)
+	}
 
 	comps := o.ClusterObjects.GetComponentInfo()
 	// resources
@@ -162,6 +206,13 @@ func (o *describeOptions) describeCluster(name string) error {
 	// data protection info
 	showDataProtection(o.BackupPolicies, o.Backups, o.Out)
 
+	// spec history
+	if o.showHistory {
+		if err = o.showSpecHistory(o.Cluster); err != nil {
+			return err
+		}
+	}
+
 	// events
 	showEvents(o.Cluster.Name, o.Cluster.Namespace, o.Out)
 	fmt.Fprintln(o.Out)
@@ -179,6 +230,79 @@ func showCluster(c *appsv1alpha1.Cluster, out io.Writer) {
 	tbl.Print()
 }
 
+func showComponents(c *appsv1alpha1.Cluster, out io.Writer) {
+	if c == nil {
+		return
+	}
+	tbl := newTbl(out, "\nComponents:", "COMPONENT", "STATUS", "OBSERVED-GENERATION", "LEADER", "PODS", "NODES")
+	for _, comp := range c.Spec.ComponentSpecs {
+		status := c.Status.Components[comp.Name]
+		tbl.AddRow(comp.Name, string(status.Phase), strconv.FormatInt(status.ObservedGeneration, 10), util.CheckEmpty(status.LeaderPod),
+			util.CheckEmpty(status.PodsSummary), util.CheckEmpty(buildNodesInfo(status)))
+	}
+	tbl.Print()
+}
+
+// buildNodesInfo lists the nodes hosting the pods of the component, the nodes hosting multiple pods are marked
+// as shared, which usually means the pod anti-affinity is not satisfied.
+func buildNodesInfo(status appsv1alpha1.ClusterComponentStatus) string {
+	podsOnNode := map[string]int{}
+	for _, node := range status.PodNodes {
+		podsOnNode[node]++
+	}
+	for _, node := range status.SharedNodes {
+		if podsOnNode[node] < 2 {
+			podsOnNode[node] = 2
+		}
+	}
+	nodes := make([]string, 0, len(podsOnNode))
+	for node := range podsOnNode {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for i, node := range nodes {
+		if podsOnNode[node] > 1 {
+			nodes[i] = fmt.Sprintf("%s(shared by %d pods)", node, podsOnNode[node])
+		}
+	}
+	return strings.Join(nodes, ",")
+}
+
+func (o *describeOptions) showSpecHistory(c *appsv1alpha1.Cluster) error {
+	secret, err := o.client.CoreV1().Secrets(c.Namespace).Get(context.TODO(), component.GenerateSpecHistoryName(c.Name), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	var history []component.SpecHistoryEntry
+	if secret != nil && len(secret.Data[constant.SpecHistoryKey]) > 0 {
+		if err = json.Unmarshal(secret.Data[constant.SpecHistoryKey], &history); err != nil {
+			return err
+		}
+	}
+	showHistory(history, o.Out)
+	return nil
+}
+
+func showHistory(history []component.SpecHistoryEntry, out io.Writer) {
+	if len(history) == 0 {
+		fmt.Fprintln(out, "\nHistory: <none>")
+		return
+	}
+	tbl := newTbl(out, "\nHistory:", "TIME", "GENERATION", "CHANGES")
+	// show the latest change first
+	for i := len(history) - 1; i >= 0; i-- {
+		entry := history[i]
+		for j, change := range entry.Changes {
+			if j == 0 {
+				tbl.AddRow(util.TimeFormat(&entry.Timestamp), strconv.FormatInt(entry.Generation, 10), change)
+			} else {
+				tbl.AddRow("", "", change)
+			}
+		}
+	}
+	tbl.Print()
+}
+
 func showTopology(instances []*cluster.InstanceInfo, out io.Writer) {
 	tbl := newTbl(out, "\nTopology:", "COMPONENT", "INSTANCE", "ROLE", "STATUS", "AZ", "NODE", "CREATED-TIME")
 	for _, ins := range instances {
@@ -37,9 +37,11 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/cli/testing"
 	"github.com/apecloud/kubeblocks/internal/cli/types"
+	"github.com/apecloud/kubeblocks/internal/controller/component"
 )
 
 var _ = Describe("Expose", func() {
@@ -107,6 +109,54 @@ var _ = Describe("Expose", func() {
 		Expect(o.run()).Should(Succeed())
 	})
 
+	It("showComponents", func() {
+		out := &bytes.Buffer{}
+		c := testing.FakeCluster(clusterName, namespace)
+		c.Generation = 3
+		c.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{
+			testing.ComponentName: {
+				Phase:              appsv1alpha1.RunningClusterCompPhase,
+				ObservedGeneration: 2,
+				LeaderPod:          "test-pod-0",
+				PodsSummary:        "2/3 pods ready, 1 ImagePullBackOff",
+			},
+		}
+		showComponents(c, out)
+		Expect(out.String()).Should(ContainSubstring("OBSERVED-GENERATION"))
+		Expect(out.String()).Should(MatchRegexp(testing.ComponentName + `\s+Running\s+2\s+test-pod-0\s+2/3 pods ready, 1 ImagePullBackOff`))
+
+		By("show the node placement and flag the co-located pods")
+		out.Reset()
+		c.Status.Components[testing.ComponentName] = appsv1alpha1.ClusterComponentStatus{
+			Phase:       appsv1alpha1.RunningClusterCompPhase,
+			PodNodes:    map[string]string{"test-pod-0": "node-b", "test-pod-1": "node-a", "test-pod-2": "node-b"},
+			SharedNodes: []string{"node-b"},
+		}
+		showComponents(c, out)
+		Expect(out.String()).Should(ContainSubstring("NODES"))
+		Expect(out.String()).Should(ContainSubstring("node-a,node-b(shared by 2 pods)"))
+	})
+
+	It("showHistory", func() {
+		out := &bytes.Buffer{}
+		showHistory(nil, out)
+		Expect(out.String()).Should(ContainSubstring("History: <none>"))
+
+		out.Reset()
+		now := metav1.Now()
+		showHistory([]component.SpecHistoryEntry{
+			{Timestamp: now, Generation: 2, Changes: []string{"componentSpecs[mysql].replicas: 3 -> 5"}},
+			{Timestamp: now, Generation: 3, Changes: []string{
+				"componentSpecs[mysql].resources.requests.memory: 4Gi -> 8Gi",
+				"terminationPolicy: Delete -> WipeOut",
+			}},
+		}, out)
+		Expect(out.String()).Should(ContainSubstring("componentSpecs[mysql].replicas: 3 -> 5"))
+		Expect(out.String()).Should(ContainSubstring("terminationPolicy: Delete -> WipeOut"))
+		// the latest change is shown first
+		Expect(strings.Index(out.String(), "4Gi -> 8Gi")).Should(BeNumerically("<", strings.Index(out.String(), "3 -> 5")))
+	})
+
 	It("showEvents", func() {
 		out := &bytes.Buffer{}
 		showEvents("test-cluster", namespace, out)
@@ -0,0 +1,276 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/cli/cluster"
+	"github.com/apecloud/kubeblocks/internal/cli/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+// topologyOutputDOT is the output format to export the topology of the cluster in the Graphviz DOT language.
+const topologyOutputDOT = "dot"
+
+// clusterTopology is the structure of the cluster built from its status and the objects it owns.
+type clusterTopology struct {
+	name       string
+	phase      string
+	components []*topologyComponent
+	services   []corev1.Service
+}
+
+type topologyComponent struct {
+	name  string
+	phase string
+	pods  []*topologyPod
+	// dependencies are the names of the vars of the component, keyed by the components they are resolved from.
+	dependencies map[string][]string
+}
+
+type topologyPod struct {
+	name    string
+	phase   string
+	role    string
+	zone    string
+	node    string
+	labels  map[string]string
+	volumes []topologyVolume
+}
+
+// topologyVolume is a persistent volume of the pod and the PVC claiming it.
+type topologyVolume struct {
+	name string
+	pvc  string
+	size string
+}
+
+func buildClusterTopology(objs *cluster.ClusterObjects) *clusterTopology {
+	c := objs.Cluster
+	topology := &clusterTopology{name: c.Name, phase: string(c.Status.Phase)}
+	pvcs := map[string]corev1.PersistentVolumeClaim{}
+	if objs.PVCs != nil {
+		for _, pvc := range objs.PVCs.Items {
+			pvcs[pvc.Name] = pvc
+		}
+	}
+	for _, compSpec := range c.Spec.ComponentSpecs {
+		comp := &topologyComponent{
+			name:         compSpec.Name,
+			phase:        string(c.Status.Components[compSpec.Name].Phase),
+			dependencies: getComponentDependencies(objs, compSpec),
+		}
+		if objs.Pods != nil {
+			for i := range objs.Pods.Items {
+				pod := &objs.Pods.Items[i]
+				if pod.Labels[constant.KBAppComponentLabelKey] != compSpec.Name {
+					continue
+				}
+				comp.pods = append(comp.pods, buildTopologyPod(pod, objs.Nodes, pvcs))
+			}
+		}
+		sort.Slice(comp.pods, func(i, j int) bool {
+			return comp.pods[i].name < comp.pods[j].name
+		})
+		topology.components = append(topology.components, comp)
+	}
+	if objs.Services != nil {
+		topology.services = append(topology.services, objs.Services.Items...)
+		sort.Slice(topology.services, func(i, j int) bool {
+			return topology.services[i].Name < topology.services[j].Name
+		})
+	}
+	return topology
+}
+
+func buildTopologyPod(pod *corev1.Pod, nodes []*corev1.Node, pvcs map[string]corev1.PersistentVolumeClaim) *topologyPod {
+	p := &topologyPod{
+		name:   pod.Name,
+		phase:  string(pod.Status.Phase),
+		role:   pod.Labels[constant.RoleLabelKey],
+		node:   pod.Spec.NodeName,
+		labels: pod.Labels,
+	}
+	if node := util.GetNodeByName(nodes, pod.Spec.NodeName); node != nil {
+		p.zone = node.Labels[constant.ZoneLabelKey]
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		v := topologyVolume{name: volume.Name, pvc: volume.PersistentVolumeClaim.ClaimName}
+		if pvc, ok := pvcs[v.pvc]; ok {
+			size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			v.size = size.String()
+		}
+		p.volumes = append(p.volumes, v)
+	}
+	return p
+}
+
+// getComponentDependencies gets the vars and the component refs of the component resolved from the other components.
+func getComponentDependencies(objs *cluster.ClusterObjects, compSpec appsv1alpha1.ClusterComponentSpec) map[string][]string {
+	if objs.ClusterDef == nil {
+		return nil
+	}
+	compDef := objs.ClusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+	if compDef == nil {
+		return nil
+	}
+	dependencies := map[string][]string{}
+	addDependency := func(compDefName, name string) {
+		for _, spec := range objs.Cluster.Spec.ComponentSpecs {
+			if spec.ComponentDefRef == compDefName && spec.Name != compSpec.Name {
+				dependencies[spec.Name] = append(dependencies[spec.Name], name)
+			}
+		}
+	}
+	for _, v := range compDef.Vars {
+		switch {
+		case v.ValueFrom.ServiceRef != nil:
+			addDependency(v.ValueFrom.ServiceRef.CompDef, v.Name)
+		case v.ValueFrom.CredentialRef != nil:
+			addDependency(v.ValueFrom.CredentialRef.CompDef, v.Name)
+		case v.ValueFrom.MetaRef != nil:
+			addDependency(v.ValueFrom.MetaRef.CompDef, v.Name)
+		}
+	}
+	for _, ref := range compDef.ComponentDefRef {
+		for _, env := range ref.ComponentRefEnvs {
+			addDependency(ref.ComponentDefName, env.Name)
+		}
+	}
+	return dependencies
+}
+
+// showTopologyTree prints the topology as a tree of components, pods and the volumes of pods.
+func showTopologyTree(topology *clusterTopology, out io.Writer) {
+	fmt.Fprintln(out, "\nTopology Tree:")
+	fmt.Fprintf(out, "Cluster %s (%s)\n", topology.name, util.CheckEmpty(topology.phase))
+	for i, comp := range topology.components {
+		compPrefix := printTreeBranch(out, "", i == len(topology.components)-1,
+			fmt.Sprintf("Component %s (%s)", comp.name, util.CheckEmpty(comp.phase)))
+		for j, pod := range comp.pods {
+			podPrefix := printTreeBranch(out, compPrefix, j == len(comp.pods)-1, pod.describe())
+			for k, v := range pod.volumes {
+				printTreeBranch(out, podPrefix, k == len(pod.volumes)-1, v.describe())
+			}
+		}
+	}
+}
+
+// printTreeBranch prints the line of a tree node, and returns the prefix of the lines of its children.
+func printTreeBranch(out io.Writer, prefix string, last bool, line string) string {
+	branch, indent := "├── ", "│   "
+	if last {
+		branch, indent = "└── ", "    "
+	}
+	fmt.Fprintf(out, "%s%s%s\n", prefix, branch, line)
+	return prefix + indent
+}
+
+func (p *topologyPod) describe() string {
+	var attrs []string
+	for _, attr := range []struct{ key, value string }{{"role", p.role}, {"zone", p.zone}, {"node", p.node}} {
+		if len(attr.value) > 0 {
+			attrs = append(attrs, fmt.Sprintf("%s: %s", attr.key, attr.value))
+		}
+	}
+	line := fmt.Sprintf("Pod %s (%s)", p.name, util.CheckEmpty(p.phase))
+	if len(attrs) > 0 {
+		line += " [" + strings.Join(attrs, ", ") + "]"
+	}
+	return line
+}
+
+func (v *topologyVolume) describe() string {
+	return fmt.Sprintf("Volume %s: %s (%s)", v.name, v.pvc, util.CheckEmpty(v.size))
+}
+
+// printTopologyDOT exports the topology in the Graphviz DOT language, including the pods selected by the services
+// and the dependencies between components by vars.
+func printTopologyDOT(topology *clusterTopology, out io.Writer) {
+	node := func(kind, name, label, shape string) {
+		fmt.Fprintf(out, "  %q [label=%q, shape=%s];\n", kind+"/"+name, label, shape)
+	}
+	edge := func(from, to string, attrs string) {
+		if len(attrs) > 0 {
+			fmt.Fprintf(out, "  %q -> %q [%s];\n", from, to, attrs)
+		} else {
+			fmt.Fprintf(out, "  %q -> %q;\n", from, to)
+		}
+	}
+
+	clusterID := "cluster/" + topology.name
+	fmt.Fprintf(out, "digraph %q {\n", topology.name)
+	fmt.Fprintln(out, "  rankdir=LR;")
+	node("cluster", topology.name, topology.name+"\n"+topology.phase, "box3d")
+	for _, comp := range topology.components {
+		compID := "component/" + comp.name
+		node("component", comp.name, comp.name+"\n"+comp.phase, "box")
+		edge(clusterID, compID, "")
+		for _, pod := range comp.pods {
+			label := pod.name
+			if len(pod.role) > 0 {
+				label += "\n" + pod.role
+			}
+			node("pod", pod.name, label, "ellipse")
+			edge(compID, "pod/"+pod.name, "")
+			for _, v := range pod.volumes {
+				node("pvc", v.pvc, v.pvc+"\n"+v.size, "cylinder")
+				edge("pod/"+pod.name, "pvc/"+v.pvc, "")
+			}
+		}
+	}
+	for _, svc := range topology.services {
+		node("service", svc.Name, svc.Name+"\n"+string(svc.Spec.Type), "hexagon")
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, comp := range topology.components {
+			for _, pod := range comp.pods {
+				if selector.Matches(labels.Set(pod.labels)) {
+					edge("service/"+svc.Name, "pod/"+pod.name, "style=dashed")
+				}
+			}
+		}
+	}
+	for _, comp := range topology.components {
+		deps := make([]string, 0, len(comp.dependencies))
+		for dep := range comp.dependencies {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			edge("component/"+comp.name, "component/"+dep,
+				fmt.Sprintf("label=%q, style=dotted", strings.Join(comp.dependencies[dep], "\n")))
+		}
+	}
+	fmt.Fprintln(out, "}")
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/cli/cluster"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+var _ = Describe("describe topology", func() {
+	const (
+		clusterName = "mycluster"
+		mysqlComp   = "mysql"
+		proxyComp   = "proxy"
+	)
+
+	// fakeTopologyObjects mocks a cluster of a mysql component with 2 replicas and a proxy component
+	// resolving the service host of mysql by var.
+	fakeTopologyObjects := func() *cluster.ClusterObjects {
+		objs := cluster.NewClusterObjects()
+		objs.Cluster = &appsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: "default"},
+			Spec: appsv1alpha1.ClusterSpec{
+				ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{
+					{Name: mysqlComp, ComponentDefRef: mysqlComp},
+					{Name: proxyComp, ComponentDefRef: proxyComp},
+				},
+			},
+			Status: appsv1alpha1.ClusterStatus{
+				Phase: appsv1alpha1.RunningClusterPhase,
+				Components: map[string]appsv1alpha1.ClusterComponentStatus{
+					mysqlComp: {Phase: appsv1alpha1.RunningClusterCompPhase},
+					proxyComp: {Phase: appsv1alpha1.UpdatingClusterCompPhase},
+				},
+			},
+		}
+		objs.ClusterDef = &appsv1alpha1.ClusterDefinition{
+			Spec: appsv1alpha1.ClusterDefinitionSpec{
+				ComponentDefs: []appsv1alpha1.ClusterComponentDefinition{
+					{Name: mysqlComp},
+					{
+						Name: proxyComp,
+						Vars: []appsv1alpha1.ComponentVar{{
+							Name:      "MYSQL_HOST",
+							ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlComp}},
+						}},
+					},
+				},
+			},
+		}
+		for i, zone := range []string{"zone-a", "zone-b"} {
+			node := &corev1.Node{}
+			node.Name = fmt.Sprintf("node-%d", i)
+			node.Labels = map[string]string{constant.ZoneLabelKey: zone}
+			objs.Nodes = append(objs.Nodes, node)
+		}
+		newPod := func(comp string, index int, role string) corev1.Pod {
+			pod := corev1.Pod{}
+			pod.Name = fmt.Sprintf("%s-%s-%d", clusterName, comp, index)
+			pod.Labels = map[string]string{
+				constant.AppInstanceLabelKey:    clusterName,
+				constant.KBAppComponentLabelKey: comp,
+			}
+			if len(role) > 0 {
+				pod.Labels[constant.RoleLabelKey] = role
+			}
+			pod.Spec.NodeName = fmt.Sprintf("node-%d", index)
+			pod.Status.Phase = corev1.PodRunning
+			return pod
+		}
+		objs.Pods = &corev1.PodList{}
+		objs.PVCs = &corev1.PersistentVolumeClaimList{}
+		for i, role := range []string{"leader", "follower"} {
+			pod := newPod(mysqlComp, i, role)
+			pvc := corev1.PersistentVolumeClaim{}
+			pvc.Name = "data-" + pod.Name
+			pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}
+			pod.Spec.Volumes = []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+					},
+				},
+				{
+					Name:         "scripts",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}},
+				},
+			}
+			objs.Pods.Items = append(objs.Pods.Items, pod)
+			objs.PVCs.Items = append(objs.PVCs.Items, pvc)
+		}
+		proxyPod := newPod(proxyComp, 0, "")
+		proxyPod.Status.Phase = corev1.PodPending
+		objs.Pods.Items = append(objs.Pods.Items, proxyPod)
+
+		newService := func(name string, selector map[string]string) corev1.Service {
+			svc := corev1.Service{}
+			svc.Name = name
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+			svc.Spec.Selector = selector
+			return svc
+		}
+		objs.Services = &corev1.ServiceList{Items: []corev1.Service{
+			newService(clusterName+"-mysql", map[string]string{
+				constant.KBAppComponentLabelKey: mysqlComp,
+				constant.RoleLabelKey:           "leader",
+			}),
+			newService(clusterName+"-proxy", map[string]string{constant.KBAppComponentLabelKey: proxyComp}),
+		}}
+		return objs
+	}
+
+	It("should print the topology tree as the golden file", func() {
+		out := &bytes.Buffer{}
+		showTopologyTree(buildClusterTopology(fakeTopologyObjects()), out)
+		golden, err := os.ReadFile(filepath.Join("testdata", "describe_topology.golden"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.String()).Should(Equal(string(golden)))
+	})
+
+	It("should export the topology in the DOT language", func() {
+		out := &bytes.Buffer{}
+		printTopologyDOT(buildClusterTopology(fakeTopologyObjects()), out)
+		dot := out.String()
+		Expect(dot).Should(HavePrefix(`digraph "mycluster" {`))
+		Expect(dot).Should(HaveSuffix("}\n"))
+		Expect(dot).Should(ContainSubstring(`"component/mysql" -> "pod/mycluster-mysql-0";`))
+		Expect(dot).Should(ContainSubstring(`"pod/mycluster-mysql-1" -> "pvc/data-mycluster-mysql-1";`))
+		By("check the service edges follow the selectors")
+		Expect(dot).Should(ContainSubstring(`"service/mycluster-mysql" -> "pod/mycluster-mysql-0" [style=dashed];`))
+		Expect(dot).ShouldNot(ContainSubstring(`"service/mycluster-mysql" -> "pod/mycluster-mysql-1"`))
+		Expect(dot).Should(ContainSubstring(`"service/mycluster-proxy" -> "pod/mycluster-proxy-0" [style=dashed];`))
+		By("check the var dependencies between components")
+		Expect(dot).Should(ContainSubstring(`"component/proxy" -> "component/mysql" [label="MYSQL_HOST", style=dotted];`))
+	})
+
+	It("should reject the unsupported output format", func() {
+		o := &describeOptions{output: "yaml"}
+		Expect(o.complete([]string{clusterName})).Should(HaveOccurred())
+	})
+})
@@ -40,6 +40,7 @@ import (
 	"github.com/apecloud/kubeblocks/internal/cli/exec"
 	"github.com/apecloud/kubeblocks/internal/cli/types"
 	"github.com/apecloud/kubeblocks/internal/cli/util"
+	"github.com/apecloud/kubeblocks/internal/cli/util/flags"
 	"github.com/apecloud/kubeblocks/internal/constant"
 )
 
@@ -51,6 +52,9 @@ var (
 		# Display only the most recent 20 lines from cluster mycluster with default primary instance (stdout)
 		kbcli cluster logs mycluster --tail=20
 
+		# Begin streaming the logs of the last 10 minutes from the primary instance of component mycomponent (stdout)
+		kbcli cluster logs mycluster --component mycomponent --since=10m -f
+
 		# Display stdout info of specific instance my-instance-0 (cluster name comes from annotation app.kubernetes.io/instance)
 		kbcli cluster logs --instance my-instance-0
 
@@ -77,9 +81,10 @@ var (
 
 // LogsOptions declares the arguments accepted by the logs command
 type LogsOptions struct {
-	clusterName string
-	fileType    string
-	filePath    string
+	clusterName   string
+	componentName string
+	fileType      string
+	filePath      string
 	*exec.ExecOptions
 	logOptions cmdlogs.LogsOptions
 }
@@ -110,6 +115,7 @@ func NewLogsCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.C
 
 func (o *LogsOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.PodName, "instance", "i", "", "Instance name.")
+	flags.AddComponentFlag(o.Factory, cmd, &o.componentName, "Component name. If instance is not specified, the primary instance of this component is used.")
 	cmd.Flags().StringVarP(&o.logOptions.Container, "container", "c", "", "Container name.")
 	cmd.Flags().BoolVarP(&o.logOptions.Follow, "follow", "f", false, "Specify if the logs should be streamed.")
 	cmd.Flags().Int64Var(&o.logOptions.Tail, "tail", -1, "Lines of recent log file to display. Defaults to -1 for showing all log lines.")
@@ -126,6 +132,7 @@ func (o *LogsOptions) addFlags(cmd *cobra.Command) {
 
 	cmd.MarkFlagsMutuallyExclusive("file-path", "file-type")
 	cmd.MarkFlagsMutuallyExclusive("since", "since-time")
+	cmd.MarkFlagsMutuallyExclusive("instance", "component")
 }
 
 // run customs logic for logs
@@ -144,9 +151,13 @@ func (o *LogsOptions) complete(args []string) error {
 	if len(args) > 0 {
 		o.clusterName = args[0]
 	}
-	// podName not set, find the default pod of cluster
+	if len(o.componentName) > 0 && len(o.clusterName) == 0 {
+		return fmt.Errorf("component name is valid only when cluster name is specified")
+	}
+	// podName not set, find the default pod of cluster or component,
+	// the leader or primary instance is always the first one
 	if len(o.PodName) == 0 {
-		infos := cluster.GetSimpleInstanceInfos(o.Dynamic, o.clusterName, o.Namespace)
+		infos := cluster.GetSimpleInstanceInfosForComponent(o.Dynamic, o.clusterName, o.componentName, o.Namespace)
 		if len(infos) == 0 || infos[0].Name == constant.ComponentStatusDefaultPodName {
 			return fmt.Errorf("failed to find the default instance, please check cluster status")
 		}
@@ -41,6 +41,7 @@ import (
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/cli/cluster"
 	"github.com/apecloud/kubeblocks/internal/cli/exec"
+	"github.com/apecloud/kubeblocks/internal/cli/testing"
 	"github.com/apecloud/kubeblocks/internal/constant"
 )
 
@@ -142,6 +143,61 @@ var _ = Describe("logs", func() {
 
 	})
 
+	It("resolve default instance and pass through log options", func() {
+		tf := cmdtesting.NewTestFactory().WithNamespace("test")
+		defer tf.Cleanup()
+		codec := scheme.Codecs.LegacyCodec(scheme.Scheme.PrioritizedVersionsAllGroups()...)
+		tf.Client = &fake.RESTClient{
+			GroupVersion:         schema.GroupVersion{Group: "", Version: "v1"},
+			NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+			Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				body := cmdtesting.ObjBody(codec, mockPod())
+				return &http.Response{StatusCode: http.StatusOK, Header: cmdtesting.DefaultHeader(), Body: body}, nil
+			}),
+		}
+		tf.ClientConfigVal = &restclient.Config{APIPath: "/api", ContentConfig: restclient.ContentConfig{NegotiatedSerializer: scheme.Codecs, GroupVersion: &schema.GroupVersion{Version: "v1"}}}
+
+		stream := genericclioptions.NewTestIOStreamsDiscard()
+		newOptions := func() *LogsOptions {
+			l := &LogsOptions{
+				ExecOptions: exec.NewExecOptions(tf, stream),
+				logOptions: cmdlogs.LogsOptions{
+					IOStreams: stream,
+				},
+			}
+			l.Namespace = "test"
+			l.Client, _ = tf.KubernetesClientSet()
+			l.Dynamic = testing.FakeDynamicClient(testing.FakeCluster("cluster-name", "test"))
+			return l
+		}
+
+		By("component is only valid with cluster name")
+		l := newOptions()
+		l.componentName = testing.ComponentName
+		l.PodName = "foo"
+		Expect(l.complete(nil)).Should(MatchError(ContainSubstring("component name is valid only when cluster name is specified")))
+
+		By("default to the leader instance of the specified component")
+		l = newOptions()
+		l.componentName = testing.ComponentName
+		l.logOptions.Follow = true
+		l.logOptions.Tail = 20
+		l.logOptions.SinceSeconds = 10 * time.Minute
+		Expect(l.complete([]string{"cluster-name"})).Should(Succeed())
+		Expect(l.PodName).Should(Equal("cluster-name-pod-0"))
+		Expect(l.validate()).Should(Succeed())
+		podLogOptions, ok := l.logOptions.Options.(*corev1.PodLogOptions)
+		Expect(ok).Should(BeTrue())
+		Expect(podLogOptions.Follow).Should(BeTrue())
+		Expect(*podLogOptions.TailLines).Should(Equal(int64(20)))
+		Expect(*podLogOptions.SinceSeconds).Should(Equal(int64(600)))
+
+		By("no instance found for an unknown component")
+		l = newOptions()
+		l.componentName = "unknown"
+		Expect(l.complete([]string{"cluster-name"})).Should(HaveOccurred())
+	})
+
 	It("createFileTypeCommand Test", func() {
 		pod := &corev1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
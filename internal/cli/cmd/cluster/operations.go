@@ -22,33 +22,44 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	// register the cluster phases the OpsRequests are allowed in
+	_ "github.com/apecloud/kubeblocks/controllers/apps/operations"
 	"github.com/apecloud/kubeblocks/internal/class"
 	"github.com/apecloud/kubeblocks/internal/cli/cluster"
 	"github.com/apecloud/kubeblocks/internal/cli/create"
 	"github.com/apecloud/kubeblocks/internal/cli/printer"
+	"github.com/apecloud/kubeblocks/internal/cli/spinner"
 	"github.com/apecloud/kubeblocks/internal/cli/types"
 	"github.com/apecloud/kubeblocks/internal/cli/util"
 	"github.com/apecloud/kubeblocks/internal/cli/util/flags"
 	"github.com/apecloud/kubeblocks/internal/cli/util/prompt"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 )
 
 type OperationsOptions struct {
@@ -92,13 +103,29 @@ type OperationsOptions struct {
 	Storage  string   `json:"storage"`
 
 	// Expose options
-	ExposeType    string                                 `json:"-"`
-	ExposeEnabled string                                 `json:"-"`
-	Services      []appsv1alpha1.ClusterComponentService `json:"services,omitempty"`
+	ExposeType     string `json:"-"`
+	ExposeEnabled  string `json:"-"`
+	ExposeInternet bool   `json:"-"`
+	// ExposeComponents are the services of the components to expose
+	ExposeComponents []appsv1alpha1.Expose `json:"exposeComponents,omitempty"`
+	exposeSpec       *exposeSpec
+
+	// Monitor options
+	MonitorEnabled bool `json:"monitorEnabled"`
+
+	// Restart options
+	// restartConfirmMessage warns that restarting the consensus components may switch their leaders over.
+	restartConfirmMessage string
 
 	// Switchover options
 	Component string `json:"component"`
 	Instance  string `json:"instance"`
+	// Demote when set true, the switchover demotes the current primary or leader instance,
+	// and the new primary or leader is determined by the system.
+	Demote         bool          `json:"-"`
+	DemoteInstance string        `json:"-"`
+	Wait           bool          `json:"-"`
+	Timeout        time.Duration `json:"-"`
 }
 
 func newBaseOperationsOptions(f cmdutil.Factory, streams genericclioptions.IOStreams,
@@ -198,6 +225,10 @@ func (o *OperationsOptions) validateVolumeExpansion() error {
 	if len(o.Storage) == 0 {
 		return fmt.Errorf("missing storage")
 	}
+	targetStorage, err := resource.ParseQuantity(o.Storage)
+	if err != nil {
+		return fmt.Errorf("cannot parse '%v', %v", o.Storage, err)
+	}
 
 	for _, cName := range o.ComponentNames {
 		for _, vctName := range o.VCTNames {
@@ -217,9 +248,9 @@ func (o *OperationsOptions) validateVolumeExpansion() error {
 			pvc := pvcs.Items[0]
 			specStorage := pvc.Spec.Resources.Requests.Storage()
 			statusStorage := pvc.Status.Capacity.Storage()
-			targetStorage, err := resource.ParseQuantity(o.Storage)
-			if err != nil {
-				return fmt.Errorf("cannot parse '%v', %v", o.Storage, err)
+			// reject the storage decrease as the webhook does, shrinking volumes is not supported
+			if err = appsv1alpha1.ValidateVolumeExpansionStorage(vctName, targetStorage, *statusStorage); err != nil {
+				return err
 			}
 			// determine whether the opsRequest is a recovery action for volume expansion failure
 			if specStorage.Cmp(targetStorage) > 0 &&
@@ -298,6 +329,67 @@ func (o *OperationsOptions) validateVScale(cluster *appsv1alpha1.Cluster) error
 	return nil
 }
 
+// restartAnnotationWindow is the window within which a component won't be restarted again, so that the repeated
+// invocations, such as the retries of scripts, don't restart the pods twice.
+const restartAnnotationWindow = time.Minute
+
+// validateRestart checks the components exist, skips the components restarted within the restartAnnotationWindow,
+// and warns that restarting the consensus components may switch their leaders over.
+func (o *OperationsOptions) validateRestart(clusterObj *appsv1alpha1.Cluster) error {
+	clusterDef, err := cluster.GetClusterDefByName(o.Dynamic, clusterObj.Spec.ClusterDefRef)
+	if err != nil {
+		return err
+	}
+	var (
+		componentNames []string
+		consensusComps []string
+	)
+	for _, compName := range o.ComponentNames {
+		compSpec := clusterObj.Spec.GetComponentByName(compName)
+		if compSpec == nil {
+			return fmt.Errorf("component %s not found in cluster %s", compName, o.Name)
+		}
+		restarted, err := o.isComponentRestartedRecently(compName)
+		if err != nil {
+			return err
+		}
+		if restarted {
+			fmt.Fprintf(o.Out, "Component %s has been restarted within %s, skip it\n", compName, restartAnnotationWindow)
+			continue
+		}
+		componentNames = append(componentNames, compName)
+		compDef := clusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef != nil && compDef.WorkloadType == appsv1alpha1.Consensus {
+			consensusComps = append(consensusComps, compName)
+		}
+	}
+	o.ComponentNames = componentNames
+	if len(consensusComps) > 0 {
+		o.restartConfirmMessage = fmt.Sprintf("Restarting the consensus components [%s] may switch their leaders over.",
+			strings.Join(consensusComps, ","))
+	}
+	return nil
+}
+
+// isComponentRestartedRecently checks whether the restart annotation of the component workloads is bumped
+// within the restartAnnotationWindow.
+func (o *OperationsOptions) isComponentRestartedRecently(compName string) (bool, error) {
+	objs, err := o.Dynamic.Resource(types.RSMGVR()).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", constant.AppInstanceLabelKey, o.Name, constant.KBAppComponentLabelKey, compName),
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, obj := range objs.Items {
+		annotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
+		lastRestart, err := time.Parse(time.RFC3339, annotations[constant.RestartAnnotationKey])
+		if err == nil && time.Since(lastRestart) < restartAnnotationWindow {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Validate command flags or args is legal
 func (o *OperationsOptions) Validate() error {
 	if o.Name == "" {
@@ -319,6 +411,11 @@ func (o *OperationsOptions) Validate() error {
 		return fmt.Errorf(`missing components, please specify the "--components" flag for multi-components cluster`)
 	}
 
+	// pre-validate the cluster phase as the webhook and the controller do, instead of creating an OpsRequest to be rejected
+	if err = appsv1alpha1.ValidateOpsRequestClusterPhase(o.OpsType, cluster.Status.Phase); err != nil {
+		return err
+	}
+
 	switch o.OpsType {
 	case appsv1alpha1.VolumeExpansionType:
 		if err = o.validateVolumeExpansion(); err != nil {
@@ -336,65 +433,75 @@ func (o *OperationsOptions) Validate() error {
 		if err = o.validateExpose(); err != nil {
 			return err
 		}
+	case appsv1alpha1.RestartType:
+		if err = o.validateRestart(&cluster); err != nil {
+			return err
+		}
+		// all components are restarted recently, nothing to confirm
+		if len(o.ComponentNames) == 0 {
+			return nil
+		}
 	case appsv1alpha1.SwitchoverType:
-		if err = o.validatePromote(&cluster); err != nil {
+		if o.Demote {
+			err = o.validateDemote(&cluster)
+		} else {
+			err = o.validatePromote(&cluster)
+		}
+		if err != nil {
 			return err
 		}
 	}
 	if !o.autoApprove && o.DryRun == "none" {
-		return prompt.Confirm([]string{o.Name}, o.In, "", "")
+		return prompt.Confirm([]string{o.Name}, o.In, o.restartConfirmMessage, "")
 	}
 	return nil
 }
 
-func (o *OperationsOptions) validatePromote(cluster *appsv1alpha1.Cluster) error {
-	var (
-		clusterDefObj = appsv1alpha1.ClusterDefinition{}
-		podObj        = &corev1.Pod{}
-		componentName string
-	)
+// switchoverAction returns the action name of the switchover command for the messages.
+func (o *OperationsOptions) switchoverAction() string {
+	if o.Demote {
+		return "demote"
+	}
+	return "promote"
+}
 
+// getSwitchoverComponentName gets the component name to switchover, the component can be omitted
+// when the cluster has only one component.
+func (o *OperationsOptions) getSwitchoverComponentName(cluster *appsv1alpha1.Cluster) (string, error) {
 	if len(cluster.Spec.ComponentSpecs) == 0 {
-		return fmt.Errorf("cluster.Spec.ComponentSpecs cannot be empty")
+		return "", fmt.Errorf("cluster.Spec.ComponentSpecs cannot be empty")
 	}
-
 	if o.Component != "" {
-		componentName = o.Component
-	} else {
-		if len(cluster.Spec.ComponentSpecs) > 1 {
-			return fmt.Errorf("there are multiple components in cluster, please use --component to specify the component for promote")
-		}
-		componentName = cluster.Spec.ComponentSpecs[0].Name
+		return o.Component, nil
+	}
+	if len(cluster.Spec.ComponentSpecs) > 1 {
+		return "", fmt.Errorf("there are multiple components in cluster, please use --component to specify the component for %s", o.switchoverAction())
 	}
+	return cluster.Spec.ComponentSpecs[0].Name, nil
+}
 
-	if o.Instance != "" {
-		// checks the validity of the instance whether it belongs to the current component and ensure it is not the primary or leader instance currently.
-		podKey := client.ObjectKey{
-			Namespace: cluster.Namespace,
-			Name:      o.Instance,
-		}
-		if err := util.GetResourceObjectFromGVR(types.PodGVR(), podKey, o.Dynamic, podObj); err != nil || podObj == nil {
-			return fmt.Errorf("instance %s not found, please check the validity of the instance using \"kbcli cluster list-instances\"", o.Instance)
-		}
-		v, ok := podObj.Labels[constant.RoleLabelKey]
-		if !ok || v == "" {
-			return fmt.Errorf("instance %s cannot be promoted because it had a invalid role label", o.Instance)
-		}
-		if v == constant.Primary || v == constant.Leader {
-			return fmt.Errorf("instance %s cannot be promoted because it is already the primary or leader instance", o.Instance)
-		}
-		if !strings.HasPrefix(podObj.Name, fmt.Sprintf("%s-%s", cluster.Name, componentName)) {
-			return fmt.Errorf("instance %s does not belong to the current component, please check the validity of the instance using \"kbcli cluster list-instances\"", o.Instance)
-		}
+// getSwitchoverInstance gets the instance pod of the cluster.
+func (o *OperationsOptions) getSwitchoverInstance(cluster *appsv1alpha1.Cluster, instance string) (*corev1.Pod, error) {
+	podObj := &corev1.Pod{}
+	podKey := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      instance,
 	}
+	if err := util.GetResourceObjectFromGVR(types.PodGVR(), podKey, o.Dynamic, podObj); err != nil || podObj == nil {
+		return nil, fmt.Errorf("instance %s not found, please check the validity of the instance using \"kbcli cluster list-instances\"", instance)
+	}
+	return podObj, nil
+}
 
-	// check clusterDefinition switchoverSpec exist
+// getSwitchoverSpec gets the switchoverSpec of the component definition.
+func (o *OperationsOptions) getSwitchoverSpec(cluster *appsv1alpha1.Cluster, componentName string) (*appsv1alpha1.SwitchoverSpec, error) {
+	clusterDefObj := appsv1alpha1.ClusterDefinition{}
 	clusterDefKey := client.ObjectKey{
 		Namespace: "",
 		Name:      cluster.Spec.ClusterDefRef,
 	}
 	if err := util.GetResourceObjectFromGVR(types.ClusterDefGVR(), clusterDefKey, o.Dynamic, &clusterDefObj); err != nil {
-		return err
+		return nil, err
 	}
 	var compDefObj *appsv1alpha1.ClusterComponentDefinition
 	for _, compDef := range clusterDefObj.Spec.ComponentDefs {
@@ -404,62 +511,191 @@ func (o *OperationsOptions) validatePromote(cluster *appsv1alpha1.Cluster) error
 		}
 	}
 	if compDefObj == nil {
-		return fmt.Errorf("cluster component %s is invalid", componentName)
+		return nil, fmt.Errorf("cluster component %s is invalid", componentName)
 	}
 	if compDefObj.SwitchoverSpec == nil {
-		return fmt.Errorf("cluster component %s does not support switchover", componentName)
+		return nil, fmt.Errorf("cluster component %s does not support switchover", componentName)
+	}
+	return compDefObj.SwitchoverSpec, nil
+}
+
+func (o *OperationsOptions) validatePromote(cluster *appsv1alpha1.Cluster) error {
+	componentName, err := o.getSwitchoverComponentName(cluster)
+	if err != nil {
+		return err
+	}
+
+	if o.Instance != "" {
+		// checks the validity of the instance whether it belongs to the current component and ensure it is not the primary or leader instance currently.
+		podObj, err := o.getSwitchoverInstance(cluster, o.Instance)
+		if err != nil {
+			return err
+		}
+		v, ok := podObj.Labels[constant.RoleLabelKey]
+		if !ok || v == "" {
+			return fmt.Errorf("instance %s cannot be promoted because it had a invalid role label", o.Instance)
+		}
+		if v == constant.Primary || v == constant.Leader {
+			return fmt.Errorf("instance %s cannot be promoted because it is already the primary or leader instance", o.Instance)
+		}
+		if !strings.HasPrefix(podObj.Name, fmt.Sprintf("%s-%s", cluster.Name, componentName)) {
+			return fmt.Errorf("instance %s does not belong to the current component, please check the validity of the instance using \"kbcli cluster list-instances\"", o.Instance)
+		}
+		if !intctrlutil.PodIsReady(podObj) {
+			return fmt.Errorf("instance %s cannot be promoted because it is not ready", o.Instance)
+		}
+	}
+
+	// check clusterDefinition switchoverSpec exist
+	switchoverSpec, err := o.getSwitchoverSpec(cluster, componentName)
+	if err != nil {
+		return err
 	}
 	switch o.Instance {
 	case "":
-		if compDefObj.SwitchoverSpec.WithoutCandidate == nil {
+		if switchoverSpec.WithoutCandidate == nil {
 			return fmt.Errorf("cluster component %s does not support promote without specifying an instance. Please specify a specific instance for the promotion", componentName)
 		}
 	default:
-		if compDefObj.SwitchoverSpec.WithCandidate == nil {
+		if switchoverSpec.WithCandidate == nil {
 			return fmt.Errorf("cluster component %s does not support specifying an instance for promote. If you want to perform a promote operation, please do not specify an instance", componentName)
 		}
 	}
 	return nil
 }
 
-func (o *OperationsOptions) validateExpose() error {
-	switch util.ExposeType(o.ExposeType) {
-	case "", util.ExposeToVPC, util.ExposeToInternet:
-	default:
-		return fmt.Errorf("invalid expose type %q", o.ExposeType)
+func (o *OperationsOptions) validateDemote(cluster *appsv1alpha1.Cluster) error {
+	componentName, err := o.getSwitchoverComponentName(cluster)
+	if err != nil {
+		return err
 	}
 
-	switch strings.ToLower(o.ExposeEnabled) {
-	case util.EnableValue, util.DisableValue:
-	default:
-		return fmt.Errorf("invalid value for enable flag: %s", o.ExposeEnabled)
+	if o.DemoteInstance != "" {
+		// checks the validity of the instance whether it belongs to the current component and ensure it is the primary or leader instance currently.
+		podObj, err := o.getSwitchoverInstance(cluster, o.DemoteInstance)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(podObj.Name, fmt.Sprintf("%s-%s", cluster.Name, componentName)) {
+			return fmt.Errorf("instance %s does not belong to the current component, please check the validity of the instance using \"kbcli cluster list-instances\"", o.DemoteInstance)
+		}
+		if v := podObj.Labels[constant.RoleLabelKey]; v != constant.Primary && v != constant.Leader {
+			return fmt.Errorf("instance %s cannot be demoted because it is not the primary or leader instance", o.DemoteInstance)
+		}
 	}
-	return nil
-}
 
-func (o *OperationsOptions) fillExpose() error {
-	version, err := util.GetK8sVersion(o.Client.Discovery())
+	// demote is a switchover without candidate, the new primary or leader is determined by the system.
+	switchoverSpec, err := o.getSwitchoverSpec(cluster, componentName)
 	if err != nil {
 		return err
 	}
-	provider, err := util.GetK8sProvider(version, o.Client)
-	if err != nil {
-		return err
+	if switchoverSpec.WithoutCandidate == nil {
+		return fmt.Errorf("cluster component %s does not support demote without specifying an instance, please use \"kbcli cluster promote\" with the instance instead", componentName)
 	}
-	if provider == util.UnknownProvider {
-		return fmt.Errorf("unknown k8s provider")
+	return nil
+}
+
+// exposeServiceNames are the names of the services created by the expose command, the expose type is used
+// as the service name.
+var exposeServiceNames = []string{string(util.ExposeToVPC), string(util.ExposeToInternet), string(util.ExposeNodePort)}
+
+// exposeSpec is the service to expose resolved from the flags of the expose command.
+type exposeSpec struct {
+	// serviceType is empty if the exposure is removed.
+	serviceType corev1.ServiceType
+	// lbType selects the annotations of the LoadBalancer service, it's vpc or internet.
+	lbType      util.ExposeType
+	serviceName string
+	// replaced are the names of the services to be replaced by the exposed one.
+	replaced []string
+}
+
+func (o *OperationsOptions) validateExpose() error {
+	_, err := o.resolveExpose()
+	return err
+}
+
+// resolveExpose resolves the service to expose from the flags. The types 'vpc' and 'internet' toggle the
+// LoadBalancer service of their own by the enable flag, and the types 'loadbalancer', 'nodeport' and 'none'
+// replace all services created by the expose command.
+func (o *OperationsOptions) resolveExpose() (*exposeSpec, error) {
+	exposeType := util.ExposeType(strings.ToLower(o.ExposeType))
+	switch exposeType {
+	case "", util.ExposeToVPC, util.ExposeToInternet:
+		if exposeType == "" && o.ExposeEnabled == "" {
+			return nil, fmt.Errorf(`missing the expose type, please specify the "--type" flag`)
+		}
+		switch strings.ToLower(o.ExposeEnabled) {
+		case util.EnableValue, util.DisableValue:
+		default:
+			return nil, fmt.Errorf("invalid value for enable flag: %s", o.ExposeEnabled)
+		}
+		// default expose to internet
+		if exposeType == "" {
+			exposeType = util.ExposeToInternet
+		}
+		spec := &exposeSpec{
+			lbType:      exposeType,
+			serviceName: string(exposeType),
+			replaced:    []string{string(exposeType)},
+		}
+		if strings.ToLower(o.ExposeEnabled) == util.EnableValue {
+			spec.serviceType = corev1.ServiceTypeLoadBalancer
+		}
+		return spec, nil
+	case util.ExposeLoadBalancer, util.ExposeNodePort, util.ExposeNone:
+		if o.ExposeEnabled != "" {
+			return nil, fmt.Errorf(`the "--enable" flag is not supported by the expose type %s`, exposeType)
+		}
+		if o.ExposeInternet && exposeType != util.ExposeLoadBalancer {
+			return nil, fmt.Errorf(`the "--internet" flag is only supported by the expose type %s`, util.ExposeLoadBalancer)
+		}
+	default:
+		return nil, fmt.Errorf("invalid expose type %q", o.ExposeType)
 	}
 
-	// default expose to internet
-	exposeType := util.ExposeType(o.ExposeType)
-	if exposeType == "" {
-		exposeType = util.ExposeToInternet
+	spec := &exposeSpec{replaced: exposeServiceNames}
+	switch exposeType {
+	case util.ExposeLoadBalancer:
+		spec.serviceType = corev1.ServiceTypeLoadBalancer
+		spec.lbType = util.ExposeToVPC
+		if o.ExposeInternet {
+			spec.lbType = util.ExposeToInternet
+		}
+		// keep the service name the same as the one exposed by the type vpc or internet
+		spec.serviceName = string(spec.lbType)
+	case util.ExposeNodePort:
+		spec.serviceType = corev1.ServiceTypeNodePort
+		spec.serviceName = string(util.ExposeNodePort)
 	}
+	return spec, nil
+}
 
-	annotations, err := util.GetExposeAnnotations(provider, exposeType)
+func (o *OperationsOptions) fillExpose() error {
+	spec, err := o.resolveExpose()
 	if err != nil {
 		return err
 	}
+	o.exposeSpec = spec
+
+	// the annotations of the LoadBalancer depend on the k8s provider
+	var annotations map[string]string
+	if spec.serviceType == corev1.ServiceTypeLoadBalancer {
+		version, err := util.GetK8sVersion(o.Client.Discovery())
+		if err != nil {
+			return err
+		}
+		provider, err := util.GetK8sProvider(version, o.Client)
+		if err != nil {
+			return err
+		}
+		if provider == util.UnknownProvider {
+			return fmt.Errorf("unknown k8s provider")
+		}
+		if annotations, err = util.GetExposeAnnotations(provider, spec.lbType); err != nil {
+			return err
+		}
+	}
 
 	gvr := schema.GroupVersionResource{Group: types.AppsAPIGroup, Version: types.AppsAPIVersion, Resource: types.ResourceClusters}
 	unstructuredObj, err := o.Dynamic.Resource(gvr).Namespace(o.Namespace).Get(context.TODO(), o.Name, metav1.GetOptions{})
@@ -470,46 +706,164 @@ func (o *OperationsOptions) fillExpose() error {
 	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.UnstructuredContent(), &cluster); err != nil {
 		return err
 	}
+	o.ExposeComponents, err = buildExposeComponents(&cluster, o.ComponentNames, spec, annotations)
+	return err
+}
 
+// buildExposeComponents builds the services of the components for the Expose OpsRequest. As the OpsRequest
+// overrides all services of a component, the services not replaced by the exposed one are kept.
+func buildExposeComponents(cluster *appsv1alpha1.Cluster, componentNames []string,
+	spec *exposeSpec, annotations map[string]string) ([]appsv1alpha1.Expose, error) {
 	compMap := make(map[string]appsv1alpha1.ClusterComponentSpec)
 	for _, compSpec := range cluster.Spec.ComponentSpecs {
 		compMap[compSpec.Name] = compSpec
 	}
 
-	var (
-		// currently, we use the expose type as service name
-		svcName = string(exposeType)
-		enabled = strings.ToLower(o.ExposeEnabled) == util.EnableValue
-	)
-	for _, name := range o.ComponentNames {
+	var exposes []appsv1alpha1.Expose
+	for _, name := range componentNames {
 		comp, ok := compMap[name]
 		if !ok {
-			return fmt.Errorf("component %s not found", name)
+			return nil, fmt.Errorf("component %s not found", name)
 		}
 
+		expose := appsv1alpha1.Expose{
+			ComponentOps: appsv1alpha1.ComponentOps{ComponentName: name},
+			Services:     []appsv1alpha1.ClusterComponentService{},
+		}
 		for _, svc := range comp.Services {
-			if svc.Name != svcName {
-				o.Services = append(o.Services, svc)
+			if !slices.Contains(spec.replaced, svc.Name) {
+				expose.Services = append(expose.Services, svc)
 			}
 		}
-
-		if enabled {
-			o.Services = append(o.Services, appsv1alpha1.ClusterComponentService{
-				Name:        svcName,
-				ServiceType: corev1.ServiceTypeLoadBalancer,
+		if spec.serviceType != "" {
+			expose.Services = append(expose.Services, appsv1alpha1.ClusterComponentService{
+				Name:        spec.serviceName,
+				ServiceType: spec.serviceType,
 				Annotations: annotations,
 			})
 		}
+		exposes = append(exposes, expose)
+	}
+	return exposes, nil
+}
+
+// runExpose creates the Expose OpsRequest, and waits for the exposed services to get their external addresses
+// and prints the connection strings if required.
+func (o *OperationsOptions) runExpose() error {
+	clusterName := o.Name
+	if err := o.Run(); err != nil {
+		return err
+	}
+	dryRunStrategy, err := o.GetDryRunStrategy()
+	if err != nil {
+		return err
+	}
+	if !o.Wait || dryRunStrategy != create.DryRunNone || o.exposeSpec.serviceType == "" {
+		return nil
+	}
+	// the name is replaced with the OpsRequest name after created
+	if err = o.waitOpsCompleted(o.Name); err != nil {
+		return err
+	}
+	for _, compName := range o.ComponentNames {
+		// the service is named as <cluster>-<component>-<service>
+		svcName := fmt.Sprintf("%s-%s-%s", clusterName, compName, o.exposeSpec.serviceName)
+		addrs, err := o.waitExposedAddresses(svcName)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Component %s is exposed, you can connect to it by:\n", compName)
+		for _, addr := range addrs {
+			fmt.Fprintf(o.Out, "\t%s\n", addr)
+		}
 	}
 	return nil
 }
 
+// waitExposedAddresses waits for the external addresses of the exposed service.
+func (o *OperationsOptions) waitExposedAddresses(svcName string) ([]string, error) {
+	s := spinner.New(o.Out, spinner.WithMessage(fmt.Sprintf("%-50s", fmt.Sprintf("Wait for the external address of service %s", svcName))))
+	var addrs []string
+	if err := wait.PollImmediate(2*time.Second, o.Timeout, func() (bool, error) {
+		svc, err := o.Client.CoreV1().Services(o.Namespace).Get(context.TODO(), svcName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		var nodes *corev1.NodeList
+		if svc.Spec.Type == corev1.ServiceTypeNodePort {
+			if nodes, err = o.Client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{}); err != nil {
+				return false, err
+			}
+		}
+		addrs = getExposedAddresses(svc, nodes)
+		return len(addrs) > 0, nil
+	}); err != nil {
+		s.Fail()
+		return nil, err
+	}
+	s.Success()
+	return addrs, nil
+}
+
+// getExposedAddresses returns the external addresses of the service ports, which are built from the service spec
+// of the component definition. The address of the NodePort service is the address of any node, the external IP
+// is preferred.
+func getExposedAddresses(svc *corev1.Service, nodes *corev1.NodeList) []string {
+	var host string
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		host = cluster.GetExternalAddr(svc)
+	case corev1.ServiceTypeNodePort:
+		if nodes == nil {
+			return nil
+		}
+		for _, addrType := range []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP} {
+			for _, node := range nodes.Items {
+				for _, addr := range node.Status.Addresses {
+					if host == "" && addr.Type == addrType {
+						host = addr.Address
+					}
+				}
+			}
+		}
+	}
+	if host == "" {
+		return nil
+	}
+
+	var addrs []string
+	for _, port := range svc.Spec.Ports {
+		p := port.Port
+		if svc.Spec.Type == corev1.ServiceTypeNodePort {
+			if port.NodePort == 0 {
+				continue
+			}
+			p = port.NodePort
+		}
+		addr := net.JoinHostPort(host, strconv.Itoa(int(p)))
+		if port.Name != "" {
+			addr = fmt.Sprintf("%s: %s", port.Name, addr)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 var restartExample = templates.Examples(`
 		# restart all components
 		kbcli cluster restart mycluster
 
 		# specified component to restart, separate with commas for multiple components
-		kbcli cluster restart mycluster --components=mysql
+		kbcli cluster restart mycluster --component=mysql
+
+		# restart the consensus component without the confirmation
+		kbcli cluster restart mycluster --component=mysql --yes
+
+		# restart the component and wait for the pods to be restarted
+		kbcli cluster restart mycluster --component=mysql --wait
 `)
 
 // NewRestartCmd creates a restart command
@@ -526,14 +880,39 @@ func NewRestartCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 			cmdutil.CheckErr(o.Complete())
 			cmdutil.CheckErr(o.CompleteRestartOps())
 			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			cmdutil.CheckErr(o.runRestart())
 		},
 	}
 	o.addCommonFlags(cmd, f)
+	cmd.Flags().StringSliceVar(&o.ComponentNames, "component", nil, "Component names to restart, all components will be restarted if not specified")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before restarting the cluster")
+	cmd.Flags().BoolVar(&o.autoApprove, "yes", false, "Skip interactive approval before restarting the cluster, the same as --auto-approve")
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait for the restart to complete and report the progress. It will wait for a --timeout period")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 10*time.Minute, "Time to wait for the restart to complete, such as --timeout=10m")
 	return cmd
 }
 
+// runRestart creates the Restart OpsRequest for the components not restarted recently,
+// and waits for it to complete if required.
+func (o *OperationsOptions) runRestart() error {
+	if len(o.ComponentNames) == 0 {
+		fmt.Fprintf(o.Out, "No component of cluster %s to restart\n", o.Name)
+		return nil
+	}
+	if err := o.Run(); err != nil {
+		return err
+	}
+	dryRunStrategy, err := o.GetDryRunStrategy()
+	if err != nil {
+		return err
+	}
+	if !o.Wait || dryRunStrategy != create.DryRunNone {
+		return nil
+	}
+	// the name is replaced with the OpsRequest name after created
+	return o.waitOpsCompleted(o.Name)
+}
+
 var upgradeExample = templates.Examples(`
 		# upgrade the cluster to the target version 
 		kbcli cluster upgrade mycluster --cluster-version=ac-mysql-8.0.30
@@ -661,6 +1040,18 @@ func NewVolumeExpansionCmd(f cmdutil.Factory, streams genericclioptions.IOStream
 
 var (
 	exposeExamples = templates.Examples(`
+		# Expose a component with an internal LoadBalancer in the vpc, and print the connection string
+		kbcli cluster expose mycluster --components mysql --type loadbalancer
+
+		# Expose a component with an internet-facing LoadBalancer
+		kbcli cluster expose mycluster --components mysql --type loadbalancer --internet
+
+		# Expose a component by the NodePort
+		kbcli cluster expose mycluster --components mysql --type nodeport
+
+		# Stop exposing a component
+		kbcli cluster expose mycluster --components mysql --type none
+
 		# Expose a cluster to vpc
 		kbcli cluster expose mycluster --type vpc --enable=true
 
@@ -676,7 +1067,7 @@ var (
 func NewExposeCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	o := newBaseOperationsOptions(f, streams, appsv1alpha1.ExposeType, true)
 	cmd := &cobra.Command{
-		Use:               "expose NAME --enable=[true|false] --type=[vpc|internet]",
+		Use:               "expose NAME --type=[loadbalancer|nodeport|none|vpc|internet]",
 		Short:             "Expose a cluster with a new endpoint, the new endpoint can be found by executing 'kbcli cluster describe NAME'.",
 		Example:           exposeExamples,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
@@ -687,22 +1078,80 @@ func NewExposeCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 			cmdutil.CheckErr(o.CompleteComponentsFlag())
 			cmdutil.CheckErr(o.fillExpose())
 			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			cmdutil.CheckErr(o.runExpose())
 		},
 	}
 	o.addCommonFlags(cmd, f)
-	cmd.Flags().StringVar(&o.ExposeType, "type", "", "Expose type, currently supported types are 'vpc', 'internet'")
-	cmd.Flags().StringVar(&o.ExposeEnabled, "enable", "", "Enable or disable the expose, values can be true or false")
+	cmd.Flags().StringVar(&o.ExposeType, "type", "", "Expose type, currently supported types are 'loadbalancer', 'nodeport', 'none', and the legacy 'vpc', 'internet' which work with --enable")
+	cmd.Flags().StringVar(&o.ExposeEnabled, "enable", "", "Enable or disable the expose of the type 'vpc' or 'internet', values can be true or false")
+	cmd.Flags().BoolVar(&o.ExposeInternet, "internet", false, "Expose to the internet with an internet-facing LoadBalancer, otherwise an internal LoadBalancer in the vpc is used, only for the type 'loadbalancer'")
+	cmd.Flags().BoolVar(&o.Wait, "wait", true, "Wait for the external address of the exposed service and print the connection string. It will wait for a --timeout period")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 300*time.Second, "Time to wait for the external address, such as --timeout=10m")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before exposing the cluster")
 
 	util.CheckErr(cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{string(util.ExposeToVPC), string(util.ExposeToInternet)}, cobra.ShellCompDirectiveNoFileComp
+		return []string{string(util.ExposeLoadBalancer), string(util.ExposeNodePort), string(util.ExposeNone),
+			string(util.ExposeToVPC), string(util.ExposeToInternet)}, cobra.ShellCompDirectiveNoFileComp
 	}))
 	util.CheckErr(cmd.RegisterFlagCompletionFunc("enable", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp
 	}))
+	return cmd
+}
+
+var (
+	monitorEnableExample = templates.Examples(`
+		# enable the monitoring of all components
+		kbcli cluster monitor enable mycluster
+
+		# enable the monitoring of specified components, separate with commas for multiple components
+		kbcli cluster monitor enable mycluster --components=mysql
+	`)
+
+	monitorDisableExample = templates.Examples(`
+		# disable the monitoring of all components
+		kbcli cluster monitor disable mycluster
+
+		# disable the monitoring of specified components, separate with commas for multiple components
+		kbcli cluster monitor disable mycluster --components=mysql
+	`)
+)
 
-	_ = cmd.MarkFlagRequired("enable")
+// NewMonitorCmd creates a monitor command
+func NewMonitorCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Enable or disable the monitoring of the specified components in the cluster.",
+	}
+	cmd.AddCommand(newMonitorToggleCmd(f, streams, true), newMonitorToggleCmd(f, streams, false))
+	return cmd
+}
+
+func newMonitorToggleCmd(f cmdutil.Factory, streams genericclioptions.IOStreams, enabled bool) *cobra.Command {
+	o := newBaseOperationsOptions(f, streams, appsv1alpha1.MonitorType, true)
+	o.MonitorEnabled = enabled
+	cmd := &cobra.Command{
+		Use:               "enable NAME",
+		Short:             "Enable the monitoring of the specified components in the cluster, the pods will be restarted.",
+		Example:           monitorEnableExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Args = args
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			cmdutil.CheckErr(o.Complete())
+			// toggle the monitoring of all components if not specified
+			cmdutil.CheckErr(o.CompleteRestartOps())
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	if !enabled {
+		cmd.Use = "disable NAME"
+		cmd.Short = "Disable the monitoring of the specified components in the cluster, the pods will be restarted."
+		cmd.Example = monitorDisableExample
+	}
+	o.addCommonFlags(cmd, f)
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before updating the monitoring of the cluster")
 	return cmd
 }
 
@@ -849,12 +1298,139 @@ func NewPromoteCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 			cmdutil.CheckErr(o.Complete())
 			cmdutil.CheckErr(o.CompleteComponentsFlag())
 			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			cmdutil.CheckErr(o.runSwitchover())
 		},
 	}
 	cmd.Flags().StringVar(&o.Component, "component", "", "Specify the component name of the cluster, if the cluster has multiple components, you need to specify a component")
 	cmd.Flags().StringVar(&o.Instance, "instance", "", "Specify the instance name as the new primary or leader of the cluster, you can get the instance name by running \"kbcli cluster list-instances\"")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before promote the instance")
+	o.addSwitchoverFlags(cmd)
 	o.addCommonFlags(cmd, f)
 	return cmd
 }
+
+var demoteExample = templates.Examples(`
+		# Demote the primary or leader instance mycluster-mysql-0, the new primary or leader is determined by the system.
+		kbcli cluster demote mycluster --instance mycluster-mysql-0
+
+		# Demote the current primary or leader instance of the cluster.
+		kbcli cluster demote mycluster
+
+		# If the cluster has multiple components, you need to specify a component, otherwise an error will be reported.
+		kbcli cluster demote mycluster --component=mysql --instance mycluster-mysql-0
+`)
+
+// NewDemoteCmd creates a demote command
+func NewDemoteCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newBaseOperationsOptions(f, streams, appsv1alpha1.SwitchoverType, false)
+	o.Demote = true
+	cmd := &cobra.Command{
+		Use:               "demote NAME [--component=<comp-name>] [--instance <instance-name>]",
+		Short:             "Demote the primary or leader instance of the cluster, the new primary or leader is determined by the system",
+		Example:           demoteExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Args = args
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			cmdutil.CheckErr(o.Complete())
+			cmdutil.CheckErr(o.CompleteComponentsFlag())
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.runSwitchover())
+		},
+	}
+	cmd.Flags().StringVar(&o.Component, "component", "", "Specify the component name of the cluster, if the cluster has multiple components, you need to specify a component")
+	cmd.Flags().StringVar(&o.DemoteInstance, "instance", "", "Specify the current primary or leader instance to demote, you can get the instance name by running \"kbcli cluster list-instances\"")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before demote the instance")
+	o.addSwitchoverFlags(cmd)
+	o.addCommonFlags(cmd, f)
+	return cmd
+}
+
+// addSwitchoverFlags adds the flags to wait for the switchover to complete
+func (o *OperationsOptions) addSwitchoverFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.Wait, "wait", true, "Wait for the switchover to complete and print the new topology of the component. It will wait for a --timeout period")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 300*time.Second, "Time to wait for the switchover to complete, such as --timeout=10m")
+}
+
+// runSwitchover creates the switchover OpsRequest, and waits for it to complete and prints
+// the new topology of the component if required.
+func (o *OperationsOptions) runSwitchover() error {
+	clusterName := o.Name
+	if err := o.Run(); err != nil {
+		return err
+	}
+	dryRunStrategy, err := o.GetDryRunStrategy()
+	if err != nil {
+		return err
+	}
+	if !o.Wait || dryRunStrategy != create.DryRunNone {
+		return nil
+	}
+	// the name is replaced with the OpsRequest name after created
+	if err = o.waitOpsCompleted(o.Name); err != nil {
+		return err
+	}
+
+	cluster := &appsv1alpha1.Cluster{}
+	clusterKey := client.ObjectKey{
+		Namespace: o.Namespace,
+		Name:      clusterName,
+	}
+	if err = util.GetResourceObjectFromGVR(types.ClusterGVR(), clusterKey, o.Dynamic, cluster); err != nil {
+		return err
+	}
+	componentName := o.Component
+	if componentName == "" && len(o.ComponentNames) > 0 {
+		componentName = o.ComponentNames[0]
+	}
+	printSwitchoverTopology(o.Out, cluster, componentName)
+	return nil
+}
+
+// waitOpsCompleted waits for the OpsRequest to succeed, or returns an error if it failed or was cancelled.
+func (o *OperationsOptions) waitOpsCompleted(opsName string) error {
+	s := spinner.New(o.Out, spinner.WithMessage(fmt.Sprintf("%-50s", fmt.Sprintf("Wait for OpsRequest %s to complete", opsName))))
+	opsKey := client.ObjectKey{
+		Namespace: o.Namespace,
+		Name:      opsName,
+	}
+	if err := wait.PollImmediate(2*time.Second, o.Timeout, func() (bool, error) {
+		ops := &appsv1alpha1.OpsRequest{}
+		if err := util.GetResourceObjectFromGVR(types.OpsGVR(), opsKey, o.Dynamic, ops); err != nil {
+			return false, err
+		}
+		if ops.Status.Progress != "" {
+			s.SetMessage(fmt.Sprintf("%-50s", fmt.Sprintf("Wait for OpsRequest %s to complete (%s)", opsName, ops.Status.Progress)))
+		}
+		switch ops.Status.Phase {
+		case appsv1alpha1.OpsSucceedPhase:
+			return true, nil
+		case appsv1alpha1.OpsFailedPhase, appsv1alpha1.OpsCancelledPhase:
+			return false, fmt.Errorf("OpsRequest %s is %s, you can view the details by running \"kbcli cluster describe-ops %s -n %s\"",
+				opsName, ops.Status.Phase, opsName, o.Namespace)
+		}
+		return false, nil
+	}); err != nil {
+		s.Fail()
+		return err
+	}
+	s.Success()
+	return nil
+}
+
+// printSwitchoverTopology prints the roles of the component members from the cluster status.
+func printSwitchoverTopology(out io.Writer, cluster *appsv1alpha1.Cluster, componentName string) {
+	compStatus, ok := cluster.Status.Components[componentName]
+	if !ok || len(compStatus.MembersStatus) == 0 {
+		fmt.Fprintf(out, "No members status found for component %s of cluster %s\n", componentName, cluster.Name)
+		return
+	}
+	fmt.Fprintf(out, "Topology of component %s:\n", componentName)
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("INSTANCE", "ROLE", "ACCESSMODE", "LEADER")
+	for _, member := range compStatus.MembersStatus {
+		tbl.AddRow(member.PodName, member.ReplicaRole.Name, member.ReplicaRole.AccessMode, member.ReplicaRole.IsLeader)
+	}
+	tbl.SortBy(1)
+	tbl.Print()
+}
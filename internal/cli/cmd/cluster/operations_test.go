@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -35,7 +36,9 @@ import (
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/cli/testing"
+	"github.com/apecloud/kubeblocks/internal/cli/util"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 )
@@ -135,6 +138,15 @@ var _ = Describe("operations", func() {
 		Expect(o.Validate()).Should(Succeed())
 	})
 
+	It("pre-validate the cluster phase", func() {
+		o := initCommonOperationOps(appsv1alpha1.StartType, clusterName, false)
+		Expect(o.Validate()).To(MatchError(ContainSubstring("OpsRequest.spec.type=Start is forbidden when Cluster.status.phase=Running")))
+
+		o = initCommonOperationOps(appsv1alpha1.StopType, clusterName, false)
+		in.Write([]byte(o.Name + "\n"))
+		Expect(o.Validate()).Should(Succeed())
+	})
+
 	It("VolumeExpand Ops", func() {
 		compName := "replicasets"
 		vctName := "data"
@@ -176,6 +188,10 @@ var _ = Describe("operations", func() {
 		o.VCTNames = []string{vctName}
 		Expect(o.Validate()).To(MatchError("missing storage"))
 
+		By("validate the storage decrease which is rejected as the webhook does")
+		o.Storage = "500Mi"
+		Expect(o.Validate()).To(MatchError(`requested storage size of volumeClaimTemplate "data" can not less than status.capacity.storage "1Gi" `))
+
 		By("validate recovery from volume expansion failure")
 		o.Storage = "2Gi"
 		Expect(o.Validate()).Should(Succeed())
@@ -272,6 +288,69 @@ var _ = Describe("operations", func() {
 		restartCmd.Run(restartCmd, []string{clusterName})
 		capturedOutput, _ := done()
 		Expect(testing.ContainExpectStrings(capturedOutput, "kbcli cluster describe-ops")).Should(BeTrue())
+		Expect(restartCmd.Flags().Lookup("component")).ShouldNot(BeNil())
+		Expect(restartCmd.Flags().Lookup("yes")).ShouldNot(BeNil())
+	})
+
+	It("Restart ops skips the components restarted recently and warns the consensus components", func() {
+		newRSM := func(compName string, lastRestart time.Time) *workloads.ReplicatedStateMachine {
+			rsm := &workloads.ReplicatedStateMachine{}
+			rsm.SetGroupVersionKind(workloads.GroupVersion.WithKind(constant.RSMKind))
+			rsm.Name = clusterName + "-" + compName
+			rsm.Namespace = testing.Namespace
+			rsm.Labels = map[string]string{
+				constant.AppInstanceLabelKey:    clusterName,
+				constant.KBAppComponentLabelKey: compName,
+			}
+			rsm.Spec.Template.Annotations = map[string]string{
+				constant.RestartAnnotationKey: lastRestart.Format(time.RFC3339),
+			}
+			return rsm
+		}
+		clusterDef := testing.FakeClusterDef()
+		clusterDef.Spec.ComponentDefs[0].WorkloadType = appsv1alpha1.Consensus
+		tf.FakeDynamicClient = testing.FakeDynamicClient(clusterDef, testing.FakeCluster(clusterName, testing.Namespace),
+			newRSM(testing.ComponentName, time.Now()), newRSM(testing.ComponentName+"-1", time.Now().Add(-time.Hour)))
+
+		o := initCommonOperationOps(appsv1alpha1.RestartType, clusterName, true)
+		o.ComponentNames = []string{testing.ComponentName, testing.ComponentName + "-1"}
+		o.autoApprove = true
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.ComponentNames).Should(Equal([]string{testing.ComponentName + "-1"}))
+		Expect(o.restartConfirmMessage).Should(ContainSubstring(testing.ComponentName + "-1"))
+
+		By("expect for no OpsRequest if all components are restarted recently")
+		o = initCommonOperationOps(appsv1alpha1.RestartType, clusterName, true)
+		o.ComponentNames = []string{testing.ComponentName}
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.ComponentNames).Should(BeEmpty())
+		Expect(o.runRestart()).Should(Succeed())
+
+		By("expect for component not found")
+		o = initCommonOperationOps(appsv1alpha1.RestartType, clusterName, true)
+		o.ComponentNames = []string{"not-exist"}
+		Expect(o.Validate()).Should(HaveOccurred())
+	})
+
+	It("Monitor ops", func() {
+		for _, enabled := range []bool{true, false} {
+			By(fmt.Sprintf("test Monitor command with enabled=%t", enabled))
+			monitorCmd := newMonitorToggleCmd(tf, streams, enabled)
+			_, _ = in.Write([]byte(clusterName + "\n"))
+			done := testing.Capture()
+			monitorCmd.Run(monitorCmd, []string{clusterName})
+			capturedOutput, _ := done()
+			Expect(testing.ContainExpectStrings(capturedOutput, "kbcli cluster describe-ops")).Should(BeTrue())
+		}
+
+		By("expect for the monitor flag rendered into the opsRequest")
+		monitorCmd := newMonitorToggleCmd(tf, streams, true)
+		Expect(monitorCmd.Flags().Set("dry-run", "client")).Should(Succeed())
+		Expect(monitorCmd.Flags().Set("output", "yaml")).Should(Succeed())
+		done := testing.Capture()
+		monitorCmd.Run(monitorCmd, []string{clusterName})
+		capturedOutput, _ := done()
+		Expect(capturedOutput).Should(ContainSubstring("enabled: true"))
 	})
 
 	It("cancel ops", func() {
@@ -369,5 +448,217 @@ var _ = Describe("operations", func() {
 		o.Component = testing.ComponentName
 		Expect(o.Validate()).ShouldNot(Succeed())
 		Expect(testing.ContainExpectStrings(o.Validate().Error(), "does not support switchover")).Should(BeTrue())
+
+		By("validate failed because o.Instance is not ready")
+		notReadyPod := testing.FakePods(2, testing.Namespace, clusterName1).Items[1]
+		notReadyPod.Name = fmt.Sprintf("%s-%s-%d", clusterName1, testing.ComponentName, 1)
+		tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeClusterDef(), testing.FakeCluster(clusterName1, testing.Namespace), &notReadyPod)
+		o.Dynamic = tf.FakeDynamicClient
+		o.Name = clusterName1
+		o.Instance = notReadyPod.Name
+		Expect(o.Validate()).ShouldNot(Succeed())
+		Expect(testing.ContainExpectStrings(o.Validate().Error(), "cannot be promoted because it is not ready")).Should(BeTrue())
+	})
+
+	It("Demote ops", func() {
+		o := initCommonOperationOps(appsv1alpha1.SwitchoverType, clusterName, false)
+		o.Demote = true
+		o.autoApprove = true
+
+		By("validate failed because there are multi-components in cluster and not specify the component")
+		Expect(o.Validate()).ShouldNot(Succeed())
+		Expect(testing.ContainExpectStrings(o.Validate().Error(), "please use --component to specify the component for demote")).Should(BeTrue())
+
+		pods := testing.FakePods(2, testing.Namespace, clusterName1)
+		leaderPod, followerPod := pods.Items[0], pods.Items[1]
+		leaderPod.Name = fmt.Sprintf("%s-%s-%d", clusterName1, testing.ComponentName, 0)
+		followerPod.Name = fmt.Sprintf("%s-%s-%d", clusterName1, testing.ComponentName, 1)
+		o.Dynamic = testing.FakeDynamicClient(testing.FakeClusterDef(), testing.FakeCluster(clusterName1, testing.Namespace),
+			&leaderPod, &followerPod, &pods.Items[1])
+		o.Name = clusterName1
+		o.Component = testing.ComponentName
+
+		By("validate failed because o.DemoteInstance is not found")
+		o.DemoteInstance = fmt.Sprintf("%s-%s-%d", clusterName1, testing.ComponentName, 5)
+		Expect(o.Validate()).ShouldNot(Succeed())
+		Expect(testing.ContainExpectStrings(o.Validate().Error(), "not found")).Should(BeTrue())
+
+		By("validate failed because o.DemoteInstance does not belong to the current component")
+		o.DemoteInstance = pods.Items[1].Name
+		Expect(o.Validate()).ShouldNot(Succeed())
+		Expect(testing.ContainExpectStrings(o.Validate().Error(), "does not belong to the current component")).Should(BeTrue())
+
+		By("validate failed because o.DemoteInstance is not the leader")
+		o.DemoteInstance = followerPod.Name
+		Expect(o.Validate()).ShouldNot(Succeed())
+		Expect(testing.ContainExpectStrings(o.Validate().Error(), "cannot be demoted because it is not the primary or leader instance")).Should(BeTrue())
+
+		By("validate failed because mock component has no switchoverSpec, does not support switchover")
+		o.DemoteInstance = leaderPod.Name
+		Expect(o.Validate()).ShouldNot(Succeed())
+		Expect(testing.ContainExpectStrings(o.Validate().Error(), "does not support switchover")).Should(BeTrue())
+	})
+
+	It("resolve the expose flags", func() {
+		o := initCommonOperationOps(appsv1alpha1.ExposeType, clusterName1, true)
+		resolve := func(exposeType, enabled string, internet bool) (*exposeSpec, error) {
+			o.ExposeType, o.ExposeEnabled, o.ExposeInternet = exposeType, enabled, internet
+			return o.resolveExpose()
+		}
+
+		By("the legacy types toggle their own service by the enable flag")
+		spec, err := resolve("vpc", "true", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec.serviceType).Should(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(spec.lbType).Should(Equal(util.ExposeToVPC))
+		Expect(spec.replaced).Should(Equal([]string{"vpc"}))
+		spec, err = resolve("", "false", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec.serviceType).Should(BeEmpty())
+		Expect(spec.replaced).Should(Equal([]string{"internet"}))
+		_, err = resolve("vpc", "", false)
+		Expect(err).Should(HaveOccurred())
+		_, err = resolve("", "", false)
+		Expect(err).Should(HaveOccurred())
+
+		By("the loadbalancer type selects the vpc or internet annotations by the internet flag")
+		spec, err = resolve("loadbalancer", "", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec.serviceType).Should(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(spec.serviceName).Should(Equal("vpc"))
+		spec, err = resolve("LoadBalancer", "", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec.serviceName).Should(Equal("internet"))
+		Expect(spec.lbType).Should(Equal(util.ExposeToInternet))
+		Expect(spec.replaced).Should(Equal(exposeServiceNames))
+
+		By("the nodeport and none types")
+		spec, err = resolve("nodeport", "", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec.serviceType).Should(Equal(corev1.ServiceTypeNodePort))
+		Expect(spec.serviceName).Should(Equal("nodeport"))
+		spec, err = resolve("none", "", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec.serviceType).Should(BeEmpty())
+		Expect(spec.replaced).Should(Equal(exposeServiceNames))
+
+		By("invalid flags")
+		_, err = resolve("nodeport", "", true)
+		Expect(err).Should(HaveOccurred())
+		_, err = resolve("none", "true", false)
+		Expect(err).Should(HaveOccurred())
+		_, err = resolve("ingress", "", false)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("build the services of the Expose OpsRequest", func() {
+		cluster := testing.FakeCluster(clusterName, testing.Namespace)
+		cluster.Spec.ComponentSpecs[0].Services = []appsv1alpha1.ClusterComponentService{
+			{Name: "svc", ServiceType: corev1.ServiceTypeClusterIP, SessionAffinity: corev1.ServiceAffinityClientIP},
+			{Name: "vpc", ServiceType: corev1.ServiceTypeLoadBalancer},
+		}
+		compName, anotherCompName := cluster.Spec.ComponentSpecs[0].Name, cluster.Spec.ComponentSpecs[1].Name
+		annotations := map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "false"}
+		serviceNames := func(expose appsv1alpha1.Expose) []string {
+			var names []string
+			for _, svc := range expose.Services {
+				names = append(names, svc.Name)
+			}
+			return names
+		}
+
+		By("expose the components by the internet LoadBalancer, the other services are kept")
+		spec := &exposeSpec{serviceType: corev1.ServiceTypeLoadBalancer, lbType: util.ExposeToInternet,
+			serviceName: "internet", replaced: exposeServiceNames}
+		exposes, err := buildExposeComponents(cluster, []string{compName, anotherCompName}, spec, annotations)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(exposes).Should(HaveLen(2))
+		Expect(exposes[0].ComponentName).Should(Equal(compName))
+		Expect(serviceNames(exposes[0])).Should(Equal([]string{"svc", "internet"}))
+		Expect(exposes[0].Services[0].SessionAffinity).Should(Equal(corev1.ServiceAffinityClientIP))
+		Expect(exposes[0].Services[1].Annotations).Should(Equal(annotations))
+		Expect(exposes[1].ComponentName).Should(Equal(anotherCompName))
+		Expect(serviceNames(exposes[1])).Should(Equal([]string{"internet"}))
+
+		By("the legacy type only replaces its own service")
+		spec = &exposeSpec{serviceType: corev1.ServiceTypeLoadBalancer, lbType: util.ExposeToInternet,
+			serviceName: "internet", replaced: []string{"internet"}}
+		exposes, err = buildExposeComponents(cluster, []string{compName}, spec, annotations)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(serviceNames(exposes[0])).Should(Equal([]string{"svc", "vpc", "internet"}))
+
+		By("remove the exposure")
+		exposes, err = buildExposeComponents(cluster, []string{compName}, &exposeSpec{replaced: exposeServiceNames}, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(serviceNames(exposes[0])).Should(Equal([]string{"svc"}))
+
+		By("unknown component")
+		_, err = buildExposeComponents(cluster, []string{"unknown"}, spec, nil)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("get the exposed addresses", func() {
+		svc := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Name: "mysql", Port: 3306, NodePort: 31306}},
+			},
+		}
+		By("no address before the LoadBalancer is provisioned")
+		Expect(getExposedAddresses(svc, nil)).Should(BeEmpty())
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "mysql.elb.amazonaws.com"}}
+		Expect(getExposedAddresses(svc, nil)).Should(Equal([]string{"mysql: mysql.elb.amazonaws.com:3306"}))
+
+		By("the NodePort service prefers the external IP of the nodes")
+		svc.Spec.Type = corev1.ServiceTypeNodePort
+		nodes := &corev1.NodeList{Items: []corev1.Node{
+			{Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "192.168.0.1"}}}},
+			{Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "192.168.0.2"},
+				{Type: corev1.NodeExternalIP, Address: "1.2.3.4"},
+			}}},
+		}}
+		Expect(getExposedAddresses(svc, nodes)).Should(Equal([]string{"mysql: 1.2.3.4:31306"}))
+		nodes.Items = nodes.Items[:1]
+		Expect(getExposedAddresses(svc, nodes)).Should(Equal([]string{"mysql: 192.168.0.1:31306"}))
+	})
+
+	It("print switchover topology", func() {
+		cluster := testing.FakeCluster(clusterName1, testing.Namespace)
+		out := &bytes.Buffer{}
+
+		By("print nothing but a hint when there is no members status")
+		printSwitchoverTopology(out, cluster, testing.ComponentName)
+		Expect(out.String()).Should(ContainSubstring("No members status found for component"))
+
+		By("print the members with their roles")
+		out.Reset()
+		cluster.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{
+			testing.ComponentName: {
+				MembersStatus: []workloads.MemberStatus{
+					{
+						PodName: "cluster-ops1-pod-1",
+						ReplicaRole: workloads.ReplicaRole{
+							Name:       "leader",
+							AccessMode: workloads.ReadWriteMode,
+							IsLeader:   true,
+						},
+					},
+					{
+						PodName: "cluster-ops1-pod-0",
+						ReplicaRole: workloads.ReplicaRole{
+							Name:       "follower",
+							AccessMode: workloads.ReadonlyMode,
+						},
+					},
+				},
+			},
+		}
+		printSwitchoverTopology(out, cluster, testing.ComponentName)
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).Should(HaveLen(4))
+		Expect(lines[1]).Should(ContainSubstring("INSTANCE"))
+		Expect(lines[2]).Should(MatchRegexp(`cluster-ops1-pod-0\s+follower\s+Readonly\s+false`))
+		Expect(lines[3]).Should(MatchRegexp(`cluster-ops1-pod-1\s+leader\s+ReadWrite\s+true`))
 	})
 })
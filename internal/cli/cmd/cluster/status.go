@@ -0,0 +1,189 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/cli/cluster"
+	"github.com/apecloud/kubeblocks/internal/cli/types"
+	"github.com/apecloud/kubeblocks/internal/cli/util"
+)
+
+var statusExample = templates.Examples(`
+		# show the phases of a specified cluster and its components
+		kbcli cluster status mycluster
+
+		# watch the phases of a specified cluster until it reaches a terminal phase
+		kbcli cluster status mycluster --watch`)
+
+type statusOptions struct {
+	factory   cmdutil.Factory
+	dynamic   dynamic.Interface
+	namespace string
+	name      string
+
+	// watch refreshes the status on the changes of the cluster until it reaches a terminal phase
+	watch bool
+
+	genericclioptions.IOStreams
+}
+
+func NewStatusCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &statusOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "status NAME",
+		Short:             "Show the phases of a cluster and its components.",
+		Example:           statusExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.watch, "watch", "w", false, "Watch the changes of the cluster and refresh the phases until it reaches a terminal phase")
+	return cmd
+}
+
+func (o *statusOptions) complete(args []string) error {
+	var err error
+	if len(args) == 0 {
+		return fmt.Errorf("cluster name should be specified")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only one cluster name is allowed")
+	}
+	o.name = args[0]
+
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *statusOptions) run() error {
+	// stop watching on Ctrl-C
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return o.showStatus(ctx)
+}
+
+func (o *statusOptions) showStatus(ctx context.Context) error {
+	c, err := cluster.GetClusterByName(o.dynamic, o.name, o.namespace)
+	if err != nil {
+		return err
+	}
+	printClusterStatus(c, o.Out)
+	if !o.watch || isClusterPhaseTerminal(c.Status.Phase) {
+		return nil
+	}
+
+	w, err := o.dynamic.Resource(types.ClusterGVR()).Namespace(o.namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", o.name).String(),
+		ResourceVersion: c.ResourceVersion,
+	})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("the watch of cluster %s is closed unexpectedly", o.name)
+			}
+			switch event.Type {
+			case watch.Error:
+				return apierrors.FromObject(event.Object)
+			case watch.Deleted:
+				fmt.Fprintf(o.Out, "Cluster %s is deleted\n", o.name)
+				return nil
+			case watch.Added, watch.Modified:
+				obj, ok := event.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				c = &appsv1alpha1.Cluster{}
+				if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, c); err != nil {
+					return err
+				}
+				printClusterStatus(c, o.Out)
+				if isClusterPhaseTerminal(c.Status.Phase) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// isClusterPhaseTerminal checks if the cluster phase won't change until the cluster is updated again.
+func isClusterPhaseTerminal(phase appsv1alpha1.ClusterPhase) bool {
+	switch phase {
+	case appsv1alpha1.RunningClusterPhase, appsv1alpha1.StoppedClusterPhase, appsv1alpha1.FailedClusterPhase:
+		return true
+	default:
+		return false
+	}
+}
+
+func printClusterStatus(c *appsv1alpha1.Cluster, out io.Writer) {
+	tbl := newTbl(out, fmt.Sprintf("Cluster %s: %s", c.Name, util.CheckEmpty(string(c.Status.Phase))),
+		"COMPONENT", "PHASE", "MESSAGE")
+	for _, comp := range c.Spec.ComponentSpecs {
+		status := c.Status.Components[comp.Name]
+		tbl.AddRow(comp.Name, util.CheckEmpty(string(status.Phase)), util.CheckEmpty(getComponentStatusMessage(status)))
+	}
+	tbl.Print()
+	fmt.Fprintln(out)
+}
+
+func getComponentStatusMessage(status appsv1alpha1.ClusterComponentStatus) string {
+	var messages []string
+	for _, msg := range status.Message {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clienttesting "k8s.io/client-go/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/cli/testing"
+)
+
+var _ = Describe("cluster status", func() {
+	const (
+		namespace   = "test"
+		clusterName = "test"
+	)
+
+	var (
+		out       *bytes.Buffer
+		streams   genericclioptions.IOStreams
+		fakeWatch *watch.FakeWatcher
+		o         *statusOptions
+	)
+
+	newCluster := func(phase appsv1alpha1.ClusterPhase, compPhase appsv1alpha1.ClusterComponentPhase) *appsv1alpha1.Cluster {
+		c := testing.FakeCluster(clusterName, namespace)
+		c.Status.Phase = phase
+		c.Status.Components = map[string]appsv1alpha1.ClusterComponentStatus{
+			testing.ComponentName: {Phase: compPhase},
+		}
+		return c
+	}
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericclioptions.NewTestIOStreams()
+		dynamic := testing.FakeDynamicClient(newCluster(appsv1alpha1.CreatingClusterPhase, appsv1alpha1.CreatingClusterCompPhase))
+		fakeWatch = watch.NewFake()
+		dynamic.PrependWatchReactor("clusters", clienttesting.DefaultWatchReactor(fakeWatch, nil))
+		o = &statusOptions{
+			dynamic:   dynamic,
+			namespace: namespace,
+			name:      clusterName,
+			IOStreams: streams,
+		}
+	})
+
+	It("should render the phases of the cluster and components", func() {
+		Expect(o.showStatus(context.Background())).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("Cluster test: Creating"))
+		Expect(out.String()).Should(MatchRegexp(`COMPONENT\s+PHASE\s+MESSAGE`))
+		Expect(out.String()).Should(MatchRegexp(testing.ComponentName + `\s+Creating`))
+	})
+
+	It("should refresh on the update and exit once the cluster is running", func() {
+		o.watch = true
+		go func() {
+			defer GinkgoRecover()
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(
+				newCluster(appsv1alpha1.RunningClusterPhase, appsv1alpha1.RunningClusterCompPhase))
+			Expect(err).ShouldNot(HaveOccurred())
+			fakeWatch.Modify(&unstructured.Unstructured{Object: obj})
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		Expect(o.showStatus(ctx)).Should(Succeed())
+		Expect(ctx.Err()).Should(BeNil())
+		Expect(out.String()).Should(MatchRegexp(`(?s)Cluster test: Creating.*Cluster test: Running`))
+		Expect(out.String()).Should(MatchRegexp(testing.ComponentName + `\s+Running`))
+	})
+
+	It("should require exactly one cluster name", func() {
+		Expect(o.complete(nil)).Should(HaveOccurred())
+		Expect(o.complete([]string{"c1", "c2"})).Should(HaveOccurred())
+	})
+})
@@ -79,6 +79,8 @@ type InstallOptions struct {
 	Quiet           bool
 	CreateNamespace bool
 	Check           bool
+	// CheckOnly only checks the kubernetes environment without installing KubeBlocks
+	CheckOnly bool
 	// autoApprove for KubeBlocks upgrade
 	autoApprove bool
 	ValueOpts   values.Options
@@ -105,6 +107,9 @@ var (
 	# Install KubeBlocks with specified version
 	kbcli kubeblocks install --version=0.4.0
 
+	# Only check whether the kubernetes environment meets the requirements of KubeBlocks
+	kbcli kubeblocks install --check-only
+
 	# Install KubeBlocks with ignoring preflight checks
 	kbcli kubeblocks install --force
 
@@ -143,13 +148,18 @@ func newInstallCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 			util.CheckErr(o.PreCheck())
 			util.CheckErr(o.CompleteInstallOptions())
 			util.CheckErr(p.Preflight(f, args, o.ValueOpts))
+			if o.CheckOnly {
+				fmt.Fprintf(o.Out, "\nThe kubernetes environment is ready for KubeBlocks %s\n", o.Version)
+				return
+			}
 			util.CheckErr(o.Install())
 		},
 	}
 
-	cmd.Flags().StringVar(&o.Version, "version", version.DefaultKubeBlocksVersion, "KubeBlocks version")
+	cmd.Flags().StringVar(&o.Version, "version", version.DefaultKubeBlocksVersion, "KubeBlocks version to install, use \"kbcli kubeblocks list-versions\" to show the published versions")
 	cmd.Flags().BoolVar(&o.CreateNamespace, "create-namespace", false, "Create the namespace if not present")
 	cmd.Flags().BoolVar(&o.Check, "check", true, "Check kubernetes environment before installation")
+	cmd.Flags().BoolVar(&o.CheckOnly, "check-only", false, "Only check kubernetes environment and the version to install, without installing KubeBlocks")
 	cmd.Flags().DurationVar(&o.Timeout, "timeout", 300*time.Second, "Time to wait for installing KubeBlocks, such as --timeout=10m")
 	cmd.Flags().BoolVar(&o.Wait, "wait", true, "Wait for KubeBlocks to be ready, including all the auto installed add-ons. It will wait for a --timeout period")
 	cmd.Flags().BoolVar(&p.force, flagForce, p.force, "If present, just print fail item and continue with the following steps")
@@ -203,6 +213,12 @@ func (o *Options) Complete(f cmdutil.Factory, cmd *cobra.Command) error {
 }
 
 func (o *InstallOptions) PreCheck() error {
+	// the published chart versions have no "v" prefix
+	o.Version = strings.TrimPrefix(o.Version, "v")
+	if o.CheckOnly {
+		o.Check = true
+	}
+
 	// check if KubeBlocks has been installed
 	v, err := util.GetVersionInfo(o.Client)
 	if err != nil {
@@ -227,7 +243,9 @@ func (o *InstallOptions) PreCheck() error {
 	if err = o.checkVersion(v); err != nil {
 		return err
 	}
-	return nil
+
+	// check the default storage class and the CRDs conflicting with KubeBlocks
+	return o.checkEnvironment()
 }
 
 // CompleteInstallOptions complete options for real installation of kubeblocks
@@ -268,25 +286,26 @@ func (o *InstallOptions) CompleteInstallOptions() error {
 func (o *InstallOptions) Install() error {
 	var err error
 	// add helm repo
-	s := spinner.New(o.Out, spinnerMsg("Add and update repo "+types.KubeBlocksRepoName))
+	step := "Add and update repo " + types.KubeBlocksRepoName
+	s := spinner.New(o.Out, spinnerMsg(step))
 	defer s.Fail()
 	// Add repo, if exists, will update it
 	if err = helm.AddRepo(newHelmRepoEntry()); err != nil {
-		return err
+		return newInstallStepError(step, err)
 	}
 	s.Success()
 
 	// install KubeBlocks
-	s = spinner.New(o.Out, spinnerMsg("Install KubeBlocks "+o.Version))
+	step = "Install KubeBlocks " + o.Version
+	s = spinner.New(o.Out, spinnerMsg(step))
 	defer s.Fail()
 	if err = o.installChart(); err != nil {
-		return err
+		return newInstallStepError(step, err)
 	}
 	s.Success()
 
-	// wait for auto-install addons to be ready
-	if err = o.waitAddonsEnabled(); err != nil {
-		fmt.Fprintf(o.Out, "Failed to wait for auto-install addons to be enabled, run \"kbcli kubeblocks status\" to check the status\n")
+	// wait for the manager, webhooks and auto-install addons to be ready
+	if err = o.verifyInstallation(); err != nil {
 		return err
 	}
 
@@ -397,12 +416,15 @@ func (o *InstallOptions) checkVersion(v util.Version) error {
 		return nil
 	}
 
-	// check installing version exists
-	if exists, err := versionExists(o.Version); !exists {
+	// check installing version is published
+	if o.Version != "" {
+		published, err := getHelmChartVersions(types.KubeBlocksChartName)
 		if err != nil {
-			klog.V(1).Infof(err.Error())
+			return err
+		}
+		if err = checkPublishedVersion(o.Version, published); err != nil {
+			return err
 		}
-		return errors.Wrapf(err, "version %s does not exist, please use \"kbcli kubeblocks list-versions --devel\" to show the available versions", o.Version)
 	}
 
 	versionErr := fmt.Errorf("failed to get kubernetes version")
@@ -418,6 +440,9 @@ func (o *InstallOptions) checkVersion(v util.Version) error {
 
 	// output kubernetes version
 	fmt.Fprintf(o.Out, "Kubernetes version %s\n", ""+semVer)
+	if err := checkK8sVersion(semVer); err != nil {
+		return err
+	}
 
 	// disable or enable some features according to the kubernetes environment
 	provider, err := util.GetK8sProvider(k8sVersionStr, o.Client)
@@ -444,7 +469,10 @@ func (o *InstallOptions) checkNamespace() error {
 
 	// check if namespace exists
 	if !o.CreateNamespace {
-		_, err := o.Client.CoreV1().Namespaces().Get(context.TODO(), o.Namespace, metav1.GetOptions{})
+		_, err := o.Client.CoreV1().Namespaces().Get(context.TODO(), o.HelmCfg.Namespace(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("namespace \"%s\" does not exist, please use --create-namespace to create it", o.HelmCfg.Namespace())
+		}
 		return err
 	}
 	return nil
@@ -0,0 +1,295 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kubeblocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/util/storage"
+
+	"github.com/apecloud/kubeblocks/internal/cli/spinner"
+	"github.com/apecloud/kubeblocks/internal/cli/types"
+	"github.com/apecloud/kubeblocks/internal/cli/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+const (
+	// minK8sVersion is the minimum kubernetes version supported by KubeBlocks,
+	// it should be consistent with the kubeVersion of the KubeBlocks chart
+	minK8sVersion = "1.22.0"
+
+	// maxSuggestedVersions is the maximum number of the published versions listed
+	// when the specified version does not exist
+	maxSuggestedVersions = 5
+
+	helmReleaseNameAnnotationKey = "meta.helm.sh/release-name"
+
+	reportKubeBlocksHint = `run "kbcli report kubeblocks --with-logs" to collect the diagnostic information`
+)
+
+// newInstallStepError wraps the error of the failed installation step, and tells
+// the user how to collect the diagnostic information
+func newInstallStepError(step string, err error) error {
+	return fmt.Errorf("failed to %s%s: %s\n%s", strings.ToLower(step[:1]), step[1:], err.Error(), reportKubeBlocksHint)
+}
+
+// checkPublishedVersion checks whether the version is one of the published versions
+// of the KubeBlocks chart, if not, the latest published versions are listed
+func checkPublishedVersion(version string, published []*semver.Version) error {
+	for _, v := range published {
+		if v.String() == version {
+			return nil
+		}
+	}
+
+	versions := make([]*semver.Version, len(published))
+	copy(versions, published)
+	sort.Sort(sort.Reverse(semver.Collection(versions)))
+	var latest []string
+	for _, v := range versions {
+		if v.Prerelease() != "" || (len(latest) > 0 && latest[len(latest)-1] == v.String()) {
+			continue
+		}
+		latest = append(latest, v.String())
+		if len(latest) == maxSuggestedVersions {
+			break
+		}
+	}
+
+	msg := fmt.Sprintf("version %s does not exist", version)
+	if len(latest) > 0 {
+		msg += fmt.Sprintf(", the latest published versions are %s", strings.Join(latest, ", "))
+	}
+	return fmt.Errorf("%s, please use \"kbcli kubeblocks list-versions --devel\" to show all the available versions", msg)
+}
+
+// checkK8sVersion checks whether the kubernetes server version is supported by KubeBlocks
+func checkK8sVersion(semVer string) error {
+	v, err := semver.NewVersion(semVer)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse kubernetes version %s", semVer)
+	}
+	constraint, err := semver.NewConstraint(">= " + minK8sVersion)
+	if err != nil {
+		return err
+	}
+	if !constraint.Check(v) {
+		return fmt.Errorf("kubernetes version %s is not supported, KubeBlocks requires kubernetes %s or above", semVer, minK8sVersion)
+	}
+	return nil
+}
+
+// hasDefaultStorageClass checks whether there is a default storage class
+func hasDefaultStorageClass(client kubernetes.Interface) (bool, error) {
+	scs, err := client.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, sc := range scs.Items {
+		annotations := sc.GetAnnotations()
+		if annotations[storage.IsDefaultStorageClassAnnotation] == "true" ||
+			annotations[storage.BetaIsDefaultStorageClassAnnotation] == "true" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkConflictCRDs checks whether the KubeBlocks CRDs have been created by other
+// helm releases, such as another operator shipping the same CRDs.
+func checkConflictCRDs(dynamic dynamic.Interface) error {
+	crds, err := dynamic.Resource(types.CRDGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	var conflicts []string
+	for _, crd := range crds.Items {
+		if !strings.Contains(crd.GetName(), constant.APIGroup) {
+			continue
+		}
+		release := crd.GetAnnotations()[helmReleaseNameAnnotationKey]
+		if release == "" || release == types.KubeBlocksReleaseName {
+			continue
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s (managed by release %s)", crd.GetName(), release))
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+	return fmt.Errorf("there are CRDs conflicting with KubeBlocks, please remove them before installation\n  %s",
+		strings.Join(conflicts, "\n  "))
+}
+
+// checkEnvironment checks whether the kubernetes environment meets the requirements
+// of KubeBlocks, the missing default storage class is only a warning.
+func (o *InstallOptions) checkEnvironment() error {
+	if !o.Check {
+		return nil
+	}
+
+	found, err := hasDefaultStorageClass(o.Client)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the default storage class")
+	}
+	if !found {
+		fmt.Fprintf(o.Out, "Warning: no default storage class found, please specify the storage class when creating clusters\n")
+	}
+	return checkConflictCRDs(o.Dynamic)
+}
+
+// isKubeBlocksDeployAvailable checks whether the KubeBlocks manager deployment is available
+func isKubeBlocksDeployAvailable(client kubernetes.Interface) (bool, error) {
+	deploy, err := util.GetKubeBlocksDeploy(client)
+	if err != nil || deploy == nil {
+		return false, err
+	}
+
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	for _, c := range deploy.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable && c.Status == corev1.ConditionTrue {
+			return deploy.Status.AvailableReplicas >= replicas, nil
+		}
+	}
+	return false, nil
+}
+
+// isWebhooksServing checks whether the services of the KubeBlocks webhooks have
+// ready endpoints, if the webhooks are disabled, it is regarded as serving.
+func isWebhooksServing(client kubernetes.Interface) (bool, error) {
+	var (
+		ctx      = context.TODO()
+		opts     = metav1.ListOptions{LabelSelector: buildKubeBlocksSelectorLabels()}
+		services = map[string]string{}
+	)
+
+	addService := func(webhook string, svc *admissionregistrationv1.ServiceReference) {
+		if svc != nil {
+			services[svc.Namespace+"/"+svc.Name] = webhook
+		}
+	}
+	mutating, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range mutating.Items {
+		for _, w := range c.Webhooks {
+			addService(w.Name, w.ClientConfig.Service)
+		}
+	}
+	validating, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range validating.Items {
+		for _, w := range c.Webhooks {
+			addService(w.Name, w.ClientConfig.Service)
+		}
+	}
+
+	for key, webhook := range services {
+		ns, name, _ := strings.Cut(key, "/")
+		endpoints, err := client.CoreV1().Endpoints(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.V(1).Infof("Endpoints of webhook %s service %s not found", webhook, key)
+				return false, nil
+			}
+			return false, err
+		}
+		ready := false
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				ready = true
+				break
+			}
+		}
+		if !ready {
+			klog.V(1).Infof("Webhook %s service %s has no ready endpoints", webhook, key)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// verifyInstallation waits for the KubeBlocks manager deployment to be available,
+// the webhooks to be serving and the auto-installed addons to be enabled.
+func (o *InstallOptions) verifyInstallation() error {
+	if !o.Wait {
+		return nil
+	}
+
+	steps := []struct {
+		name      string
+		condition func() (bool, error)
+	}{
+		{
+			name:      "Wait for KubeBlocks manager to be available",
+			condition: func() (bool, error) { return isKubeBlocksDeployAvailable(o.Client) },
+		},
+		{
+			name:      "Wait for webhooks to be serving",
+			condition: func() (bool, error) { return isWebhooksServing(o.Client) },
+		},
+	}
+	for _, step := range steps {
+		if err := o.waitFor(step.name, step.condition); err != nil {
+			return newInstallStepError(step.name, err)
+		}
+	}
+
+	if err := o.waitAddonsEnabled(); err != nil {
+		return newInstallStepError("Wait for addons to be enabled", err)
+	}
+	return nil
+}
+
+// waitFor waits for the condition to be satisfied until timeout, the msg is shown in the spinner
+func (o *InstallOptions) waitFor(msg string, condition func() (bool, error)) error {
+	s := spinner.New(o.Out, spinnerMsg(msg))
+	defer s.Fail()
+	if err := wait.PollImmediate(5*time.Second, o.Timeout, condition); err != nil {
+		return err
+	}
+	s.Success()
+	return nil
+}
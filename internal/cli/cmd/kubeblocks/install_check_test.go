@@ -0,0 +1,262 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kubeblocks
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/apecloud/kubeblocks/internal/cli/testing"
+	"github.com/apecloud/kubeblocks/internal/cli/types"
+	"github.com/apecloud/kubeblocks/internal/cli/util/helm"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+var _ = Describe("kubeblocks install check", func() {
+	kbLabels := func() map[string]string {
+		return map[string]string{
+			constant.AppInstanceLabelKey: types.KubeBlocksReleaseName,
+			constant.AppNameLabelKey:     types.KubeBlocksChartName,
+		}
+	}
+
+	mockCRDWithRelease := func(name string, release string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "CustomResourceDefinition",
+				APIVersion: "apiextensions.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: types.AppsAPIGroup,
+			},
+		}
+		if release != "" {
+			crd.Annotations = map[string]string{helmReleaseNameAnnotationKey: release}
+		}
+		return crd
+	}
+
+	mockKBDeploy := func(available bool) *appsv1.Deployment {
+		deploy := testing.FakeKBDeploy("0.7.0")
+		deploy.Name = types.KubeBlocksChartName
+		deploy.Namespace = namespace
+		if available {
+			deploy.Status.AvailableReplicas = 1
+			deploy.Status.Conditions = []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			}
+		}
+		return deploy
+	}
+
+	mockWebhook := func(svcName string) *admissionregistrationv1.ValidatingWebhookConfiguration {
+		return &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "kubeblocks-validating-webhook-configuration",
+				Labels: kbLabels(),
+			},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{
+					Name: "vcluster.kb.io",
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{
+						Service: &admissionregistrationv1.ServiceReference{
+							Namespace: namespace,
+							Name:      svcName,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	mockEndpoints := func(svcName string, ready bool) *corev1.Endpoints {
+		endpoints := &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      svcName,
+				Namespace: namespace,
+			},
+			Subsets: []corev1.EndpointSubset{
+				{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+			},
+		}
+		if ready {
+			endpoints.Subsets[0].Addresses = endpoints.Subsets[0].NotReadyAddresses
+		}
+		return endpoints
+	}
+
+	It("check published version", func() {
+		var published []*semver.Version
+		for _, v := range []string{"0.5.0", "0.5.1", "0.5.2", "0.6.0", "0.6.0", "0.6.1", "0.7.0", "0.7.0-beta.1"} {
+			published = append(published, semver.MustParse(v))
+		}
+
+		By("version is published")
+		Expect(checkPublishedVersion("0.7.0", published)).Should(Succeed())
+		Expect(checkPublishedVersion("0.7.0-beta.1", published)).Should(Succeed())
+
+		By("version is not published, the latest stable versions are listed")
+		err := checkPublishedVersion("0.8.0", published)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("version 0.8.0 does not exist"))
+		Expect(err.Error()).Should(ContainSubstring("0.7.0, 0.6.1, 0.6.0, 0.5.2, 0.5.1,"))
+		Expect(err.Error()).ShouldNot(ContainSubstring("beta"))
+		Expect(err.Error()).Should(ContainSubstring("list-versions --devel"))
+	})
+
+	It("check kubernetes version", func() {
+		Expect(checkK8sVersion("1.21.14")).Should(HaveOccurred())
+		Expect(checkK8sVersion("1.22.0")).Should(Succeed())
+		Expect(checkK8sVersion("1.27.3")).Should(Succeed())
+		Expect(checkK8sVersion("invalid")).Should(HaveOccurred())
+	})
+
+	It("check environment", func() {
+		out := &bytes.Buffer{}
+		o := &InstallOptions{
+			Options: Options{
+				IOStreams: genericclioptions.IOStreams{Out: out, ErrOut: out},
+				Client:    testing.FakeClientSet(testing.FakeStorageClass("standard", false)),
+				Dynamic:   testing.FakeDynamicClient(mockCRDWithRelease("clusters.apps.kubeblocks.io", "")),
+			},
+			Check: true,
+		}
+
+		By("no default storage class is only a warning")
+		Expect(o.checkEnvironment()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("no default storage class found"))
+
+		By("default storage class exists")
+		out.Reset()
+		o.Client = testing.FakeClientSet(testing.FakeStorageClass("standard", true))
+		Expect(o.checkEnvironment()).Should(Succeed())
+		Expect(out.String()).Should(BeEmpty())
+
+		By("CRDs managed by other releases conflict with KubeBlocks")
+		o.Dynamic = testing.FakeDynamicClient(
+			mockCRDWithRelease("clusters.apps.kubeblocks.io", "other-operator"),
+			mockCRDWithRelease("clusterdefinitions.apps.kubeblocks.io", types.KubeBlocksReleaseName),
+		)
+		err := o.checkEnvironment()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("clusters.apps.kubeblocks.io (managed by release other-operator)"))
+		Expect(err.Error()).ShouldNot(ContainSubstring("clusterdefinitions"))
+
+		By("skip the check")
+		o.Check = false
+		Expect(o.checkEnvironment()).Should(Succeed())
+	})
+
+	It("check namespace", func() {
+		o := &InstallOptions{
+			Options: Options{
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+				HelmCfg:   helm.NewFakeConfig(namespace),
+				Client:    testing.FakeClientSet(),
+			},
+		}
+		err := o.checkNamespace()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("--create-namespace"))
+
+		o.CreateNamespace = true
+		Expect(o.checkNamespace()).Should(Succeed())
+
+		o.CreateNamespace = false
+		o.Client = testing.FakeClientSet(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+		Expect(o.checkNamespace()).Should(Succeed())
+	})
+
+	It("check KubeBlocks deployment available", func() {
+		available, err := isKubeBlocksDeployAvailable(testing.FakeClientSet())
+		Expect(err).Should(Succeed())
+		Expect(available).Should(BeFalse())
+
+		available, err = isKubeBlocksDeployAvailable(testing.FakeClientSet(mockKBDeploy(false)))
+		Expect(err).Should(Succeed())
+		Expect(available).Should(BeFalse())
+
+		available, err = isKubeBlocksDeployAvailable(testing.FakeClientSet(mockKBDeploy(true)))
+		Expect(err).Should(Succeed())
+		Expect(available).Should(BeTrue())
+	})
+
+	It("check webhooks serving", func() {
+		const svcName = "kubeblocks"
+		testCases := []struct {
+			desc    string
+			objects []runtime.Object
+			serving bool
+		}{
+			{"webhooks are disabled", nil, true},
+			{"endpoints not found", []runtime.Object{mockWebhook(svcName)}, false},
+			{"no ready endpoints", []runtime.Object{mockWebhook(svcName), mockEndpoints(svcName, false)}, false},
+			{"endpoints are ready", []runtime.Object{mockWebhook(svcName), mockEndpoints(svcName, true)}, true},
+		}
+		for _, c := range testCases {
+			By(c.desc)
+			serving, err := isWebhooksServing(testing.FakeClientSet(c.objects...))
+			Expect(err).Should(Succeed())
+			Expect(serving).Should(Equal(c.serving))
+		}
+	})
+
+	It("verify installation", func() {
+		o := &InstallOptions{
+			Options: Options{
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+				Client:    testing.FakeClientSet(mockKBDeploy(false)),
+				Dynamic:   testing.FakeDynamicClient(),
+				Timeout:   time.Second,
+			},
+		}
+
+		By("do not verify if not wait")
+		Expect(o.verifyInstallation()).Should(Succeed())
+
+		By("the failed step and the diagnostic hint are reported")
+		o.Wait = true
+		err := o.verifyInstallation()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("failed to wait for KubeBlocks manager to be available"))
+		Expect(err.Error()).Should(ContainSubstring("kbcli report kubeblocks"))
+
+		By("the manager and webhooks are ready, but no addons are enabled")
+		o.Client = testing.FakeClientSet(mockKBDeploy(true))
+		err = o.verifyInstallation()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("failed to wait for addons to be enabled"))
+	})
+})
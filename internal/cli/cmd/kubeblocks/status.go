@@ -29,6 +29,7 @@ import (
 	"github.com/containerd/stargz-snapshotter/estargz/errorutil"
 	tablePrinter "github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
+	troubleshoot "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 	corev1 "k8s.io/api/core/v1"
@@ -45,10 +46,13 @@ import (
 	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	preflightv1beta2 "github.com/apecloud/kubeblocks/externalapis/preflight/v1beta2"
 	"github.com/apecloud/kubeblocks/internal/cli/printer"
 	"github.com/apecloud/kubeblocks/internal/cli/types"
 	"github.com/apecloud/kubeblocks/internal/cli/util"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	kbpreflight "github.com/apecloud/kubeblocks/internal/preflight"
+	kbanalyzer "github.com/apecloud/kubeblocks/internal/preflight/analyzer"
 )
 
 var (
@@ -102,6 +106,16 @@ var (
 		types.SecretGVR(),
 	}
 	notAvailable = "N/A"
+
+	webhookAnalyze = &preflightv1beta2.ExtendAnalyze{
+		Webhook: &preflightv1beta2.KBWebhookAnalyze{
+			Outcomes: []*troubleshoot.Outcome{
+				{Fail: &troubleshoot.SingleOutcome{Message: "The KubeBlocks webhooks are not working."}},
+				{Warn: &troubleshoot.SingleOutcome{Message: "The KubeBlocks webhooks may not be working properly."}},
+				{Pass: &troubleshoot.SingleOutcome{Message: "The KubeBlocks webhooks are working."}},
+			},
+		},
+	}
 )
 
 type statusOptions struct {
@@ -176,6 +190,7 @@ func (o *statusOptions) run() error {
 	o.showK8sClusterInfos(ctx, &allErrs)
 	o.showWorkloads(ctx, &allErrs)
 	o.showAddons()
+	o.showWebhooks(ctx)
 
 	if o.showAll {
 		o.showKubeBlocksResources(ctx, &allErrs)
@@ -255,6 +270,36 @@ func (o *statusOptions) showAddons() {
 	tbl.Print()
 }
 
+// showWebhooks checks whether the KubeBlocks webhooks point at ready services, round-trip the admission
+// requests and have certificates far from expiry, the same as the webhook preflight analyzer.
+func (o *statusOptions) showWebhooks(ctx context.Context) {
+	fmt.Fprintln(o.Out, "\nKubeBlocks Webhooks:")
+	data := map[string][]byte{}
+	kbpreflight.CollectKubeBlocksWebhooks(ctx, o.client, data)
+	getFile := func(path string) ([]byte, error) {
+		if content, ok := data[path]; ok {
+			return content, nil
+		}
+		return nil, fmt.Errorf("%s is not collected", path)
+	}
+
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("CHECK", "STATUS", "MESSAGE")
+	for _, result := range kbanalyzer.KBAnalyze(ctx, webhookAnalyze, getFile, nil, nil) {
+		var status string
+		switch {
+		case result.IsFail:
+			status = text.FgRed.Sprint(kbanalyzer.FailType)
+		case result.IsWarn:
+			status = text.FgYellow.Sprint(kbanalyzer.WarnType)
+		default:
+			status = text.FgGreen.Sprint(kbanalyzer.PassType)
+		}
+		tbl.AddRow(result.Title, status, result.Message)
+	}
+	tbl.Print()
+}
+
 func (o *statusOptions) showKubeBlocksResources(ctx context.Context, allErrs *[]error) {
 	fmt.Fprintln(o.Out, "\nKubeBlocks Global Custom Resources:")
 	tblPrinter := printer.NewTablePrinter(o.Out)
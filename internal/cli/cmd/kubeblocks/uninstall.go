@@ -20,9 +20,9 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package kubeblocks
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"time"
@@ -33,6 +33,7 @@ import (
 	"helm.sh/helm/v3/pkg/repo"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8sapitypes "k8s.io/apimachinery/pkg/types"
@@ -44,6 +45,7 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/cli/printer"
 	"github.com/apecloud/kubeblocks/internal/cli/spinner"
@@ -55,9 +57,42 @@ import (
 var (
 	uninstallExample = templates.Examples(`
 		# uninstall KubeBlocks
-        kbcli kubeblocks uninstall`)
+        kbcli kubeblocks uninstall
+
+		# report the clusters, backups and ops requests which would be orphaned by uninstalling KubeBlocks
+		kbcli kubeblocks uninstall --dry-run
+
+		# uninstall KubeBlocks, and remove all the clusters, backups and ops requests along with their data,
+		# the clusters are deleted following their termination policies
+		kbcli kubeblocks uninstall --remove-all-data`)
 )
 
+// orphanedResourceGVRs are the custom resources stranded without the KubeBlocks controllers after uninstalling,
+// they are deleted in this order with --remove-all-data.
+var orphanedResourceGVRs = []schema.GroupVersionResource{
+	types.OpsGVR(),
+	types.BackupGVR(),
+	types.ClusterGVR(),
+}
+
+// definitionGVRs are deleted after the orphaned resources which refer to them.
+var definitionGVRs = []schema.GroupVersionResource{
+	types.ClusterVersionGVR(),
+	types.ClusterDefGVR(),
+}
+
+// orphanedResources are the existing orphaned resources across namespaces, keyed by the GVR.
+type orphanedResources map[schema.GroupVersionResource][]unstructured.Unstructured
+
+func (r orphanedResources) isEmpty() bool {
+	for _, objs := range r {
+		if len(objs) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 type UninstallOptions struct {
 	Factory cmdutil.Factory
 	Options
@@ -70,6 +105,10 @@ type UninstallOptions struct {
 	addons          []*extensionsv1alpha1.Addon
 	Quiet           bool
 	force           bool
+	removeAllData   bool
+	dryRun          bool
+
+	orphanedResources orphanedResources
 }
 
 func newUninstallCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
@@ -88,6 +127,9 @@ func newUninstallCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *co
 		Run: func(cmd *cobra.Command, args []string) {
 			util.CheckErr(o.Complete(f, cmd))
 			util.CheckErr(o.PreCheck())
+			if o.dryRun {
+				return
+			}
 			util.CheckErr(o.Uninstall())
 		},
 	}
@@ -96,26 +138,50 @@ func newUninstallCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *co
 	cmd.Flags().BoolVar(&o.removePVs, "remove-pvs", false, "Remove PersistentVolume or not")
 	cmd.Flags().BoolVar(&o.removePVCs, "remove-pvcs", false, "Remove PersistentVolumeClaim or not")
 	cmd.Flags().BoolVar(&o.RemoveNamespace, "remove-namespace", false, "Remove default created \"kb-system\" namespace or not")
+	cmd.Flags().BoolVar(&o.removeAllData, "remove-all-data", false, "Remove all the clusters, backups and ops requests along with their data before uninstalling KubeBlocks, the clusters with the termination policy DoNotTerminate need to be updated first")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only report the clusters, backups and ops requests which would be orphaned by uninstalling KubeBlocks")
 	cmd.Flags().DurationVar(&o.Timeout, "timeout", 300*time.Second, "Time to wait for uninstalling KubeBlocks, such as --timeout=5m")
 	cmd.Flags().BoolVar(&o.Wait, "wait", true, "Wait for KubeBlocks to be uninstalled, including all the add-ons. It will wait for a --timeout period")
 	return cmd
 }
 
 func (o *UninstallOptions) PreCheck() error {
+	// report the resources which would be orphaned before doing anything
+	resources, err := getOrphanedResources(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	if o.dryRun {
+		printOrphanedResources(o.Out, resources)
+		return nil
+	}
+
+	// refuse to strand the resources unless they are asked to be removed along with KubeBlocks
+	if !resources.isEmpty() && !o.removeAllData {
+		printOrphanedResources(o.Out, resources)
+		return errors.New("failed to uninstall, the resources above need to be removed first, or use --remove-all-data to remove them along with KubeBlocks")
+	}
+	// honor the termination policy of the clusters rather than forcing them to be deleted
+	if protected := getProtectedClusters(resources); len(protected) > 0 {
+		return fmt.Errorf("failed to uninstall, clusters %s are protected by the termination policy %s, "+
+			"please update their termination policy first, e.g. \"kbcli cluster update NAME -n NAMESPACE --termination-policy=Delete\"",
+			strings.Join(protected, ", "), appsv1alpha1.DoNotTerminate)
+	}
+	o.orphanedResources = resources
+
 	// wait user to confirm
 	if !o.AutoApprove {
-		printer.Warning(o.Out, "this action will remove all KubeBlocks resources.\n")
+		if resources.isEmpty() {
+			printer.Warning(o.Out, "this action will remove all KubeBlocks resources.\n")
+		} else {
+			printOrphanedResources(o.Out, resources)
+			printer.Warning(o.Out, "this action will remove all KubeBlocks resources, including the resources above and all their data.\n")
+		}
 		if err := confirmUninstall(o.In); err != nil {
 			return err
 		}
 	}
 
-	// check if there is any resource should be removed first, if so, return error
-	// and ask user to remove them manually
-	if err := checkResources(o.Dynamic); err != nil {
-		return err
-	}
-
 	// verify where kubeblocks is installed
 	kbNamespace, err := util.GetKubeBlocksNamespace(o.Client)
 	if err != nil {
@@ -148,6 +214,20 @@ func (o *UninstallOptions) Uninstall() error {
 		return spinner.New(o.Out, spinner.WithMessage(fmt.Sprintf("%-50s", msg)))
 	}
 
+	// remove the resources in dependency order while the controllers are still running to handle their finalizers,
+	// and the definitions at last since the resources refer to them.
+	if o.removeAllData && !o.orphanedResources.isEmpty() {
+		for _, gvr := range append(orphanedResourceGVRs, definitionGVRs...) {
+			s := newSpinner("Remove " + gvr.Resource)
+			err := deleteResourcesAndWait(o.Dynamic, gvr, o.Timeout)
+			printSpinner(s, err)
+			if err != nil {
+				fmt.Fprintf(o.Out, "Failed to remove %s, run \"kbcli kubeblocks uninstall --remove-all-data\" to retry.\n", gvr.Resource)
+				return err
+			}
+		}
+	}
+
 	// uninstall all KubeBlocks addons
 	if err := o.uninstallAddons(); err != nil {
 		fmt.Fprintf(o.Out, "Failed to uninstall addons, run \"kbcli kubeblocks uninstall\" to retry.\n")
@@ -325,37 +405,78 @@ func (o *UninstallOptions) uninstallAddons() error {
 	return utilerrors.NewAggregate(allErrs)
 }
 
-func checkResources(dynamic dynamic.Interface) error {
-	ctx := context.Background()
-	gvrList := []schema.GroupVersionResource{
-		types.ClusterGVR(),
-		types.BackupGVR(),
-	}
-
-	crs := map[string][]string{}
-	for _, gvr := range gvrList {
-		objList, err := dynamic.Resource(gvr).List(ctx, metav1.ListOptions{})
+// getOrphanedResources gets the resources across namespaces which would be orphaned by uninstalling KubeBlocks.
+func getOrphanedResources(dynamic dynamic.Interface) (orphanedResources, error) {
+	resources := orphanedResources{}
+	for _, gvr := range orphanedResourceGVRs {
+		objList, err := dynamic.Resource(gvr).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
 		if err != nil && !apierrors.IsNotFound(err) {
-			return err
+			return nil, err
 		}
-
-		if objList == nil {
+		if objList == nil || len(objList.Items) == 0 {
 			continue
 		}
+		resources[gvr] = objList.Items
+	}
+	return resources, nil
+}
 
-		for _, item := range objList.Items {
-			crs[gvr.Resource] = append(crs[gvr.Resource], item.GetName())
+func printOrphanedResources(out io.Writer, resources orphanedResources) {
+	if resources.isEmpty() {
+		fmt.Fprintln(out, "No clusters, backups or ops requests would be orphaned by uninstalling KubeBlocks.")
+		return
+	}
+	fmt.Fprintln(out, "The following resources would be orphaned by uninstalling KubeBlocks:")
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("RESOURCE", "NAMESPACE", "NAME")
+	for _, gvr := range orphanedResourceGVRs {
+		for _, obj := range resources[gvr] {
+			tbl.AddRow(gvr.Resource, obj.GetNamespace(), obj.GetName())
 		}
 	}
+	tbl.Print()
+}
 
-	if len(crs) > 0 {
-		errMsg := bytes.NewBufferString("failed to uninstall, the following resources need to be removed first\n")
-		for k, v := range crs {
-			errMsg.WriteString(fmt.Sprintf("  %s: %s\n", k, strings.Join(v, " ")))
+// getProtectedClusters returns the clusters with the termination policy DoNotTerminate, in the form of namespace/name.
+func getProtectedClusters(resources orphanedResources) []string {
+	var protected []string
+	for _, obj := range resources[types.ClusterGVR()] {
+		policy, _, _ := unstructured.NestedString(obj.Object, "spec", "terminationPolicy")
+		if policy == string(appsv1alpha1.DoNotTerminate) {
+			protected = append(protected, obj.GetNamespace()+"/"+obj.GetName())
 		}
-		return errors.Errorf(errMsg.String())
 	}
-	return nil
+	return protected
+}
+
+// deleteResourcesAndWait deletes all the resources of gvr across namespaces, and waits until they are gone, so their
+// finalizers are handled by the KubeBlocks controllers before the controllers are uninstalled. The clusters are
+// deleted following their termination policies.
+func deleteResourcesAndWait(dynamic dynamic.Interface, gvr schema.GroupVersionResource, timeout time.Duration) error {
+	ctx := context.TODO()
+	listResources := func() (*unstructured.UnstructuredList, error) {
+		objList, err := dynamic.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			return &unstructured.UnstructuredList{}, nil
+		}
+		return objList, err
+	}
+	objList, err := listResources()
+	if err != nil {
+		return err
+	}
+	for _, obj := range objList.Items {
+		if err = dynamic.Resource(gvr).Namespace(obj.GetNamespace()).Delete(ctx, obj.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		objList, err = listResources()
+		if err != nil {
+			return false, err
+		}
+		return len(objList.Items) == 0, nil
+	})
 }
 
 func disableAddon(dynamic dynamic.Interface, addon *extensionsv1alpha1.Addon) error {
@@ -20,15 +20,23 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package kubeblocks
 
 import (
+	"bytes"
+	"context"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/cli/testing"
+	"github.com/apecloud/kubeblocks/internal/cli/types"
 	"github.com/apecloud/kubeblocks/internal/cli/util/helm"
 )
 
@@ -79,8 +87,105 @@ var _ = Describe("kubeblocks uninstall", func() {
 		Expect(o.Uninstall()).Should(Succeed())
 	})
 
-	It("checkResources", func() {
-		fakeDynamic := testing.FakeDynamicClient()
-		Expect(checkResources(fakeDynamic)).Should(Succeed())
+	Context("orphaned resources", func() {
+		var (
+			out         *bytes.Buffer
+			fakeDynamic *dynamicfakeclient.FakeDynamicClient
+			o           *UninstallOptions
+		)
+
+		BeforeEach(func() {
+			streams, _, out, _ = genericclioptions.NewTestIOStreams()
+			ops := &appsv1alpha1.OpsRequest{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.OpsGVR().GroupVersion().String(),
+					Kind:       types.KindOps,
+				},
+				ObjectMeta: metav1.ObjectMeta{Name: "fake-ops", Namespace: testing.Namespace},
+			}
+			fakeDynamic = testing.FakeDynamicClient(ops, testing.FakeBackup("fake-backup"),
+				testing.FakeCluster("fake-cluster", testing.Namespace), testing.FakeClusterVersion(), testing.FakeClusterDef())
+			o = &UninstallOptions{
+				Options: Options{
+					IOStreams: streams,
+					HelmCfg:   helm.NewFakeConfig(namespace),
+					Namespace: "default",
+					Client:    testing.FakeClientSet(),
+					Dynamic:   fakeDynamic,
+					Timeout:   time.Second,
+				},
+				AutoApprove: true,
+			}
+		})
+
+		deletedResources := func() []string {
+			var resources []string
+			for _, action := range fakeDynamic.Actions() {
+				if action.GetVerb() == "delete" {
+					resources = append(resources, action.GetResource().Resource)
+				}
+			}
+			return resources
+		}
+
+		It("refuses to uninstall with the orphaned resources", func() {
+			Expect(o.PreCheck()).Should(HaveOccurred())
+			Expect(out.String()).Should(ContainSubstring("fake-ops"))
+			Expect(out.String()).Should(ContainSubstring("fake-backup"))
+			Expect(out.String()).Should(ContainSubstring("fake-cluster"))
+			Expect(deletedResources()).Should(BeEmpty())
+		})
+
+		It("reports the orphaned resources only with dry-run", func() {
+			o.dryRun = true
+			Expect(o.PreCheck()).Should(Succeed())
+			Expect(out.String()).Should(ContainSubstring("would be orphaned"))
+			Expect(out.String()).Should(ContainSubstring("fake-cluster"))
+			Expect(deletedResources()).Should(BeEmpty())
+
+			By("report nothing orphaned")
+			out.Reset()
+			o.Dynamic = testing.FakeDynamicClient()
+			Expect(o.PreCheck()).Should(Succeed())
+			Expect(out.String()).Should(ContainSubstring("No clusters, backups or ops requests"))
+		})
+
+		It("refuses to remove the clusters protected by the termination policy", func() {
+			cluster := testing.FakeCluster("protected-cluster", testing.Namespace)
+			cluster.Spec.TerminationPolicy = appsv1alpha1.DoNotTerminate
+			fakeDynamic = testing.FakeDynamicClient(cluster, testing.FakeClusterVersion(), testing.FakeClusterDef())
+			o.Dynamic = fakeDynamic
+			o.removeAllData = true
+			err := o.PreCheck()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(testing.Namespace + "/protected-cluster"))
+			Expect(err.Error()).Should(ContainSubstring(string(appsv1alpha1.DoNotTerminate)))
+			for _, action := range fakeDynamic.Actions() {
+				Expect(action.GetVerb()).Should(Equal("list"))
+			}
+		})
+
+		It("removes the orphaned resources in dependency order", func() {
+			o.removeAllData = true
+			Expect(o.PreCheck()).Should(Succeed())
+			Expect(o.Uninstall()).Should(Succeed())
+			// the other KubeBlocks objects are removed after the helm release is uninstalled.
+			Expect(len(deletedResources())).Should(BeNumerically(">=", 5))
+			Expect(deletedResources()[:5]).Should(Equal([]string{"opsrequests", "backups", "clusters",
+				"clusterversions", "clusterdefinitions"}))
+
+			By("the clusters are deleted following their termination policies")
+			for _, action := range fakeDynamic.Actions() {
+				if action.GetResource() == types.ClusterGVR() {
+					Expect(action.GetVerb()).ShouldNot(Equal("patch"))
+				}
+			}
+
+			for _, gvr := range append(orphanedResourceGVRs, definitionGVRs...) {
+				objList, err := fakeDynamic.Resource(gvr).List(context.TODO(), metav1.ListOptions{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(objList.Items).Should(BeEmpty())
+			}
+		})
 	})
 })
@@ -40,11 +40,12 @@ options: {
 	cfgTemplateName: string
 	cfgFile:         string
 	forceRestart:    bool
-	services: [
+	monitorEnabled:  bool
+	exposeComponents: [
 		...{
-			name:        string
-			serviceType: string
-			annotations: {...}
+			componentName: string
+			// the services are passed through to keep all fields of the existing services
+			services: [...{...}]
 		},
 	]
 	...
@@ -143,13 +144,15 @@ content: {
 			}
 		}
 		if options.type == "Expose" {
-			expose: [ for _, cName in options.componentNames {
+			expose: [ for _, e in options.exposeComponents {
+				componentName: e.componentName
+				services:      e.services
+			}]
+		}
+		if options.type == "Monitor" {
+			monitor: [ for _, cName in options.componentNames {
 				componentName: cName
-				services: [ for _, svc in options.services {
-					name:        svc.name
-					serviceType: svc.serviceType
-					annotations: svc.annotations
-				}]
+				enabled:       options.monitorEnabled
 			}]
 		}
 		if options.type == "Switchover" {
@@ -30,6 +30,7 @@ import (
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	workloadsv1alpha1 "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	kbfakeclient "github.com/apecloud/kubeblocks/pkg/client/clientset/versioned/fake"
 )
 
@@ -41,6 +42,7 @@ func FakeDynamicClient(objects ...runtime.Object) *dynamicfakeclient.FakeDynamic
 	_ = appsv1alpha1.AddToScheme(scheme.Scheme)
 	_ = extensionsv1alpha1.AddToScheme(scheme.Scheme)
 	_ = dpv1alpha1.AddToScheme(scheme.Scheme)
+	_ = workloadsv1alpha1.AddToScheme(scheme.Scheme)
 	return dynamicfakeclient.NewSimpleDynamicClient(scheme.Scheme, objects...)
 }
 
@@ -128,6 +128,7 @@ const (
 	ClassProviderLabelKey              = "class.kubeblocks.io/provider"
 	ResourceConstraintProviderLabelKey = "resourceconstraint.kubeblocks.io/provider"
 	ReloadConfigMapAnnotationKey       = "kubeblocks.io/reload-configmap" // mark an annotation to load configmap
+	ClusterDependsOnAnnotationKey      = "kubeblocks.io/depends-on"       // the comma-separated names of the clusters which the cluster depends on
 )
 
 // DataProtection API group
@@ -172,6 +173,13 @@ const (
 	ResourceCustomResourceDefinition   = "customresourcedefinitions"
 )
 
+// Workloads API group
+const (
+	WorkloadsAPIGroup   = "workloads.kubeblocks.io"
+	WorkloadsAPIVersion = "v1alpha1"
+	ResourceRSM         = "replicatedstatemachines"
+)
+
 // Kubebench API group
 const (
 	KubebenchAPIGroup   = "benchmark.apecloud.io"
@@ -297,6 +305,10 @@ func CRDGVR() schema.GroupVersionResource {
 	}
 }
 
+func RSMGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: WorkloadsAPIGroup, Version: WorkloadsAPIVersion, Resource: ResourceRSM}
+}
+
 func ConfigmapGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{Group: corev1.GroupName, Version: K8sCoreAPIVersion, Resource: ResourceConfigmaps}
 }
@@ -80,6 +80,10 @@ var (
 		ACKProvider: "v.*-aliyun.*",
 		TKEProvider: "v.*-tke.*",
 	}
+
+	// the providerID of the ACK nodes has no scheme, it's in the format of <region-id>.<instance-id>,
+	// e.g. cn-hangzhou.i-bp1a2b3c4d5e6f
+	ackProviderIDRegex = regexp.MustCompile(`^[a-z]+-[a-z0-9-]+\.i-[a-z0-9]+$`)
 )
 
 // GetK8sProvider returns the k8s provider
@@ -103,6 +107,9 @@ func GetK8sProviderFromNodes(nodes *corev1.NodeList) K8sProvider {
 	for _, node := range nodes.Items {
 		parts := strings.SplitN(node.Spec.ProviderID, ":", 2)
 		if len(parts) != 2 {
+			if ackProviderIDRegex.MatchString(node.Spec.ProviderID) {
+				return ACKProvider
+			}
 			continue
 		}
 		switch parts[0] {
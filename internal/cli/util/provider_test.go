@@ -116,6 +116,14 @@ var _ = Describe("provider util", func() {
 				true,
 				buildNodes(""),
 			},
+			{
+				"ACK with providerID in the format of <region-id>.<instance-id>",
+				"v1.24.6",
+				"1.24.6",
+				ACKProvider,
+				true,
+				&corev1.NodeList{Items: []corev1.Node{{Spec: corev1.NodeSpec{ProviderID: "cn-hangzhou.i-bp1a2b3c4d5e6f"}}}},
+			},
 			{
 				"AKS with providerID, as AKS don't have unique version identifier",
 				"v1.24.9",
@@ -666,6 +666,12 @@ const (
 	ExposeToVPC      ExposeType = "vpc"
 	ExposeToInternet ExposeType = "internet"
 
+	// ExposeLoadBalancer, ExposeNodePort and ExposeNone are the service types to expose, the annotations of
+	// the LoadBalancer are selected by ExposeToVPC or ExposeToInternet.
+	ExposeLoadBalancer ExposeType = "loadbalancer"
+	ExposeNodePort     ExposeType = "nodeport"
+	ExposeNone         ExposeType = "none"
+
 	EnableValue  string = "true"
 	DisableValue string = "false"
 )
@@ -150,6 +150,35 @@ var _ = Describe("util", func() {
 		Expect(TimeFormatWithDuration(&metav1Time, time.Millisecond)).Should(Equal("Jan 04,2023 01:00:00.000 UTC+0000"))
 	})
 
+	It("GetExposeAnnotations", func() {
+		annotations, err := GetExposeAnnotations(EKSProvider, ExposeToVPC)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(annotations).Should(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+		annotations, err = GetExposeAnnotations(EKSProvider, ExposeToInternet)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(annotations).Should(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "false"))
+
+		annotations, err = GetExposeAnnotations(GKEProvider, ExposeToVPC)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(annotations).Should(HaveKeyWithValue("networking.gke.io/load-balancer-type", "Internal"))
+		annotations, err = GetExposeAnnotations(GKEProvider, ExposeToInternet)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(annotations).Should(BeEmpty())
+
+		annotations, err = GetExposeAnnotations(ACKProvider, ExposeToVPC)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(annotations).Should(HaveKeyWithValue("service.beta.kubernetes.io/alibaba-cloud-loadbalancer-address-type", "intranet"))
+		annotations, err = GetExposeAnnotations(ACKProvider, ExposeToInternet)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(annotations).Should(HaveKeyWithValue("service.beta.kubernetes.io/alibaba-cloud-loadbalancer-address-type", "internet"))
+
+		By("the VPC LoadBalancer is unsupported on TKE")
+		_, err = GetExposeAnnotations(TKEProvider, ExposeToVPC)
+		Expect(err).Should(HaveOccurred())
+		_, err = GetExposeAnnotations(UnknownProvider, ExposeToInternet)
+		Expect(err).Should(HaveOccurred())
+	})
+
 	It("CheckEmpty", func() {
 		res := ""
 		Expect(CheckEmpty(res)).Should(Equal(types.None))
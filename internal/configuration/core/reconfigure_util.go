@@ -22,6 +22,7 @@ package core
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -191,3 +192,29 @@ func IsSchedulableConfigResource(object client.Object) bool {
 	}
 	return true
 }
+
+// GetPodConfigVersion gets the version of the config the pod is running with. The version is recorded by the
+// reconfigure policies, either by the label patched to the pod once the config-manager applies the config in place,
+// or by the annotation inherited from the pod template once the pod is restarted. The label takes precedence, as
+// the pod recreated by a restart doesn't keep it.
+func GetPodConfigVersion(pod *corev1.Pod, configSpecName string) string {
+	if version, ok := pod.GetLabels()[configSpecName]; ok && version != "" {
+		return version
+	}
+	return pod.GetAnnotations()[GenerateUniqKeyWithConfig(constant.UpgradeRestartAnnotationKey, configSpecName)]
+}
+
+// JoinConfigVersions joins the versions of the config specs of a component into the version of the component.
+func JoinConfigVersions(versions []string) string {
+	return strings.Join(versions, ",")
+}
+
+// IsConfigVersionSynced checks if the version of the config spec is contained in the joined versions.
+func IsConfigVersionSynced(joinedVersions, version string) bool {
+	for _, v := range strings.Split(joinedVersions, ",") {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
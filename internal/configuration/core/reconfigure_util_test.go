@@ -322,3 +322,26 @@ func TestValidateConfigPatch(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPodConfigVersion(t *testing.T) {
+	const configSpecName = "mysql-config"
+	restartKey := GenerateUniqKeyWithConfig(constant.UpgradeRestartAnnotationKey, configSpecName)
+
+	pod := &corev1.Pod{}
+	require.Equal(t, "", GetPodConfigVersion(pod, configSpecName))
+
+	pod.Annotations = map[string]string{restartKey: "v1"}
+	require.Equal(t, "v1", GetPodConfigVersion(pod, configSpecName))
+
+	pod.Labels = map[string]string{configSpecName: "v2"}
+	require.Equal(t, "v2", GetPodConfigVersion(pod, configSpecName))
+}
+
+func TestIsConfigVersionSynced(t *testing.T) {
+	joined := JoinConfigVersions([]string{"v1", "v2"})
+	require.Equal(t, "v1,v2", joined)
+	require.True(t, IsConfigVersionSynced(joined, "v1"))
+	require.True(t, IsConfigVersionSynced(joined, "v2"))
+	require.False(t, IsConfigVersionSynced(joined, "v3"))
+	require.False(t, IsConfigVersionSynced("", "v1"))
+}
@@ -43,9 +43,33 @@ const (
 	// data plane config key
 	CfgKeyDataPlaneTolerations = "DATA_PLANE_TOLERATIONS"
 	CfgKeyDataPlaneAffinity    = "DATA_PLANE_AFFINITY"
+	// the comma-separated taint keys applied to the nodes to be terminated, e.g. the spot instances which received
+	// the termination notice, the members on such nodes are moved off before the nodes are terminated.
+	CfgKeyDataPlaneNodeTerminationTaintKeys = "DATA_PLANE_NODE_TERMINATION_TAINT_KEYS"
 
 	// storage config keys
 	CfgKeyDefaultStorageClass = "DEFAULT_STORAGE_CLASS"
+	// the utilization percentage of the PVCs beyond which the cluster is warned to expand the volumes, 0 to disable.
+	CfgKeyVolumeUtilizationWarningThreshold = "VOLUME_UTILIZATION_WARNING_THRESHOLD"
+	// the utilization percentage of the PVCs beyond which the volumes are critical, and expanded automatically if
+	// the auto-expansion is enabled on the cluster, 0 to disable.
+	CfgKeyVolumeUtilizationCriticalThreshold = "VOLUME_UTILIZATION_CRITICAL_THRESHOLD"
+	// the max size the volumes are expanded to automatically, unlimited if empty.
+	CfgKeyVolumeAutoExpansionMaxSize = "VOLUME_AUTO_EXPANSION_MAX_SIZE"
+	// the min interval between two automatic expansions of the volumes of a cluster.
+	CfgKeyVolumeAutoExpansionCooldown = "VOLUME_AUTO_EXPANSION_COOLDOWN"
+	// the address of the Prometheus to collect the kubelet volume stats metrics from, the volume utilization is not checked if empty.
+	CfgKeyVolumeStatsPrometheusURL = "VOLUME_STATS_PROMETHEUS_URL"
+
+	// the policy to recreate the deleted connection credential secret of the clusters, Recover or Regenerate.
+	CfgKeyConnCredentialRecoveryPolicy = "CONN_CREDENTIAL_RECOVERY_POLICY"
+
+	// the JSON map from the OpsType to the cluster phases in which the OpsRequests of the type are allowed to be created,
+	// which overrides the defaults of the types.
+	CfgKeyOpsRequestAllowedClusterPhases = "OPS_REQUEST_ALLOWED_CLUSTER_PHASES"
+
+	// tracing config keys
+	CfgKeyTracingOTLPEndpoint = "TRACING_OTLP_ENDPOINT" // the OTLP gRPC endpoint to export the reconciliation traces to, tracing is disabled if empty.
 )
 
 const (
@@ -53,6 +77,7 @@ const (
 	KBComponentEnvCMPlaceHolder     = "$(COMP_ENV_CM_NAME)"
 	KBCompNamePlaceHolder           = "$(KB_COMP_NAME)"
 	KBClusterNamePlaceHolder        = "$(KB_CLUSTER_NAME)"
+	KBNamespacePlaceHolder          = "$(KB_NAMESPACE)"
 	KBClusterCompNamePlaceHolder    = "$(KB_CLUSTER_COMP_NAME)"
 	KBClusterUIDPostfix8PlaceHolder = "$(KB_CLUSTER_UID_POSTFIX_8)"
 	KBToolsImagePlaceHolder         = "$(KUBEBLOCKS_TOOLS_IMAGE)"
@@ -89,6 +114,7 @@ const (
 	RoleLabelKey                             = "kubeblocks.io/role"     // RoleLabelKey consensusSet and replicationSet role label key
 	ModeKey                                  = "kubeblocks.io/mode"     // ModeKey is in enum of standalone/replication/raftGroup
 	VolumeTypeLabelKey                       = "kubeblocks.io/volume-type"
+	NodePoolLabelKey                         = "kubeblocks.io/node-pool" // NodePoolLabelKey the node label and taint key that identifies a node pool
 	ClusterAccountLabelKey                   = "account.kubeblocks.io/name"
 	KBAppComponentLabelKey                   = "apps.kubeblocks.io/component-name"
 	KBAppComponentDefRefLabelKey             = "apps.kubeblocks.io/component-def-ref"
@@ -114,6 +140,7 @@ const (
 	AddonNameLabelKey                        = "extensions.kubeblocks.io/addon-name"
 	OpsRequestTypeLabelKey                   = "ops.kubeblocks.io/ops-type"
 	OpsRequestNameLabelKey                   = "ops.kubeblocks.io/ops-name"
+	OpsRequestParentLabelKey                 = "ops.kubeblocks.io/parent-ops-name"
 	ServiceDescriptorNameLabelKey            = "servicedescriptor.kubeblocks.io/name"
 	RestoreForHScaleLabelKey                 = "apps.kubeblocks.io/restore-for-hscale"
 
@@ -143,6 +170,41 @@ const (
 	KubeBlocksGenerationKey                     = "kubeblocks.io/generation"
 	ExtraEnvAnnotationKey                       = "kubeblocks.io/extra-env"
 	LastRoleSnapshotVersionAnnotationKey        = "apps.kubeblocks.io/last-role-snapshot-version"
+	MountedConfigChecksumAnnotationKey          = "apps.kubeblocks.io/mounted-config-checksum" // MountedConfigChecksumAnnotationKey the checksum of ConfigMaps/Secrets mounted by the pods
+	PausedComponentsAnnotationKey               = "kubeblocks.io/component-paused"             // PausedComponentsAnnotationKey the comma-separated names of the cluster components to pause
+	NodePoolAnnotationKey                       = "apps.kubeblocks.io/node-pool"               // NodePoolAnnotationKey the node pool of all cluster components, or the comma-separated <component>=<pool> pairs
+	RestartOnChangeAnnotationKey                = "kubeblocks.io/restart-on-change"            // RestartOnChangeAnnotationKey the comma-separated Secrets/ConfigMaps, as [<kind>/]<name>, to restart the pods on changes
+	PinImagesByDigestAnnotationKey              = "apps.kubeblocks.io/pin-images-by-digest"    // PinImagesByDigestAnnotationKey pins the images of the cluster components by digest if it's "true"
+	ResourceQuotaAnnotationKey                  = "apps.kubeblocks.io/resource-quota"          // ResourceQuotaAnnotationKey requests a ResourceQuota sized to the cluster components if it's "true"
+	// SafeToEvictAnnotationKey marks whether the pod can be evicted by the cluster autoscaler
+	SafeToEvictAnnotationKey = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+	// EvictionProtectionAnnotationsKey the comma-separated keys of the eviction protection annotations added to the pod
+	EvictionProtectionAnnotationsKey = "apps.kubeblocks.io/eviction-protection-annotations"
+	// ComponentSpecHashAnnotationKey the hash of the component spec applied to the workload
+	ComponentSpecHashAnnotationKey = "apps.kubeblocks.io/component-spec-hash"
+	// ComponentSpecGenerationAnnotationKey the cluster generation in which the component spec applied to the workload changed
+	ComponentSpecGenerationAnnotationKey = "apps.kubeblocks.io/component-spec-generation"
+	// CredentialRotatedAtAnnotationKey the time of the last rotation of the password of the credential secret
+	CredentialRotatedAtAnnotationKey = "apps.kubeblocks.io/credential-rotated-at"
+	// PreviousCredentialExpireAtAnnotationKey the time when the previous password of the credential secret expires
+	PreviousCredentialExpireAtAnnotationKey = "apps.kubeblocks.io/previous-credential-expire-at"
+	// CredentialRegenerateAtAnnotationKey the time the regeneration of the password of the credential secret is requested at
+	CredentialRegenerateAtAnnotationKey = "apps.kubeblocks.io/credential-regenerate-at"
+	// ComponentCreationConcurrencyAnnotationKey the max number of the cluster components to create concurrently
+	ComponentCreationConcurrencyAnnotationKey = "apps.kubeblocks.io/component-creation-concurrency"
+	// ConfigDriftPolicyAnnotationKey the policy to handle the out-of-band changes of the config ConfigMaps of the cluster, Flag or Revert
+	ConfigDriftPolicyAnnotationKey = "config.kubeblocks.io/config-drift-policy"
+	// ConfigTemplateUpgradePolicyAnnotationKey the policy to apply the config template changes brought by the ClusterVersion, UseExisting, ReplaceWithNew or Manual
+	ConfigTemplateUpgradePolicyAnnotationKey = "config.kubeblocks.io/config-template-upgrade-policy"
+	// ConfigTemplateRenamedParametersAnnotationKey the parameters renamed by the config template, in the format of old1=new1,old2=new2
+	ConfigTemplateRenamedParametersAnnotationKey = "config.kubeblocks.io/renamed-parameters"
+	// AdoptClusterDefGenerationAnnotationKey the generation of the ClusterDefinition the cluster adopts if its updatePolicy is Manual
+	AdoptClusterDefGenerationAnnotationKey = "apps.kubeblocks.io/adopt-cluster-definition-generation"
+	// VolumeAutoExpansionAnnotationKey expands the volumes of the cluster automatically by VolumeExpansion OpsRequests
+	// once their utilization exceeds the critical threshold if it's "true"
+	VolumeAutoExpansionAnnotationKey = "apps.kubeblocks.io/volume-auto-expansion"
+	// LastVolumeAutoExpansionAnnotationKey the time of the last automatic expansion of the volumes of the cluster, in RFC3339
+	LastVolumeAutoExpansionAnnotationKey = "apps.kubeblocks.io/last-volume-auto-expansion"
 
 	// kubeblocks.io well-known finalizers
 	DBClusterFinalizerName             = "cluster.kubeblocks.io/finalizer"
@@ -187,6 +249,18 @@ const (
 	ReasonRunTaskFailed = "RunTaskFailed"
 	// ReasonDeleteFailed delete failed
 	ReasonDeleteFailed = "DeleteFailed"
+	// ReasonAdoptedWorkload adopted the orphaned workload
+	ReasonAdoptedWorkload = "AdoptedWorkload"
+	// ReasonAdoptedPVC adopted the pre-existing PVC
+	ReasonAdoptedPVC = "AdoptedPVC"
+	// ReasonPVCAdoptionSkipped skipped to adopt the pre-existing PVC
+	ReasonPVCAdoptionSkipped = "PVCAdoptionSkipped"
+	// ReasonRemovedVolumeClaimDeleted deleted the PVC whose volume claim template is removed
+	ReasonRemovedVolumeClaimDeleted = "RemovedVolumeClaimDeleted"
+	// ReasonContainerOOMKilled the container is killed by the out-of-memory killer repeatedly
+	ReasonContainerOOMKilled = "ContainerOOMKilled"
+	// ReasonSplitBrainRecovered demoted the extra primaries of the split-brain
+	ReasonSplitBrainRecovered = "SplitBrainRecovered"
 )
 
 const (
@@ -201,6 +275,7 @@ const (
 	VolumeSnapshotKind        = "VolumeSnapshot"
 	ServiceKind               = "Service"
 	ConfigMapKind             = "ConfigMap"
+	SecretKind                = "Secret"
 	DaemonSetKind             = "DaemonSet"
 )
 
@@ -277,6 +352,35 @@ const (
 const (
 	AccountNameForSecret   = "username"
 	AccountPasswdForSecret = "password"
+	// AccountPreviousPasswdForSecret is the key of the previous password, which is kept valid for the grace period
+	// after the password is rotated.
+	AccountPreviousPasswdForSecret = "previous-password"
+)
+
+// SharedSecretTokenKey is the key of the token in the cluster-level shared secret.
+const SharedSecretTokenKey = "token"
+
+// SharedSecretVolumeName is the volume name of the cluster-level shared secret mounted into the components.
+const SharedSecretVolumeName = "kb-shared-secret"
+
+// key, volume and checksum annotation of the ConfigMap listing the backends of the components declaring backendDiscovery.
+const (
+	BackendsKey                   = "backends"
+	BackendsVolumeName            = "kb-backends"
+	BackendsChecksumAnnotationKey = "apps.kubeblocks.io/backends-checksum"
+)
+
+// keys and annotation of the cluster spec history Secret.
+const (
+	SpecHistoryKey                = "history"
+	SpecSnapshotKey               = "snapshot"
+	SpecSnapshotHashAnnotationKey = "kubeblocks.io/spec-snapshot-hash"
+)
+
+// key and annotation of the last applied cluster ConfigMap, which is referred by the objects preserved on halt.
+const (
+	LastAppliedClusterKey              = "cluster"
+	LastAppliedClusterRefAnnotationKey = "apps.kubeblocks.io/last-applied-cluster-ref"
 )
 
 const DefaultBackupPvcInitCapacity = "20Gi"
@@ -307,3 +411,10 @@ const (
 	VolumeManagementPolicyKeyForRestore = "managementPolicy"
 	RestoreTimeKeyForRestore            = "restoreTime"
 )
+
+// the PriorityClasses installed with KubeBlocks to rank the pods to evict under node pressure, they never preempt other pods.
+const (
+	HighEvictionPriorityClassName   = "kubeblocks-eviction-high"
+	MediumEvictionPriorityClassName = "kubeblocks-eviction-medium"
+	LowEvictionPriorityClassName    = "kubeblocks-eviction-low"
+)
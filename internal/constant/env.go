@@ -19,6 +19,9 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package constant
 
+// The env injected into every container of the components. KB_POD_NAME, KB_POD_UID, KB_NAMESPACE, KB_NODENAME and
+// KB_HOST_IP refer to the pod by the downward API, and KB_CLUSTER_NAME and KB_COMP_NAME are the names of the cluster
+// and the component the pod belongs to.
 const (
 	KBEnvNamespace            = "KB_NAMESPACE"
 	KBEnvHostIP               = "KB_HOST_IP"
@@ -26,6 +29,8 @@ const (
 	KBEnvPodName              = "KB_POD_NAME"
 	KBEnvPodUID               = "KB_POD_UID"
 	KBEnvVolumeProtectionSpec = "KB_VOLUME_PROTECTION_SPEC"
+	KBEnvInitScriptsDir       = "KB_INIT_SCRIPTS_DIR"
+	KBEnvCPULimit             = "KB_CPU_LIMIT"
 )
 
 const (
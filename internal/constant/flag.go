@@ -20,5 +20,6 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package constant
 
 const (
-	EnableRBACManager = "EnableRBACManager"
+	EnableRBACManager   = "EnableRBACManager"
+	EnableResourceQuota = "EnableResourceQuota"
 )
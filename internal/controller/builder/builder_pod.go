@@ -19,7 +19,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package builder
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
 
 type PodBuilder struct {
 	BaseBuilder[corev1.Pod, *corev1.Pod, PodBuilder]
@@ -31,6 +36,11 @@ func NewPodBuilder(namespace, name string) *PodBuilder {
 	return builder
 }
 
+func (builder *PodBuilder) SetPodSpec(podSpec corev1.PodSpec) *PodBuilder {
+	builder.get().Spec = podSpec
+	return builder
+}
+
 func (builder *PodBuilder) SetContainers(containers []corev1.Container) *PodBuilder {
 	builder.get().Spec.Containers = containers
 	return builder
@@ -62,3 +72,70 @@ func (builder *PodBuilder) AddTolerations(tolerations ...corev1.Toleration) *Pod
 	builder.get().Spec.Tolerations = append(builder.get().Spec.Tolerations, tolerations...)
 	return builder
 }
+
+// SetNodePool pins the pod to the nodes of the named pool, which are labeled and tainted
+// with constant.NodePoolLabelKey, by setting the node selector, the required node affinity
+// and the toleration of the pool taint together.
+func (builder *PodBuilder) SetNodePool(poolLabel string) *PodBuilder {
+	spec := &builder.get().Spec
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	spec.NodeSelector[constant.NodePoolLabelKey] = poolLabel
+
+	term := corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{
+				Key:      constant.NodePoolLabelKey,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{poolLabel},
+			},
+		},
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []corev1.NodeSelectorTerm{term}
+	} else {
+		// node selector terms are ORed, so the pool requirement must be added to each of them
+		for i := range required.NodeSelectorTerms {
+			required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, term.MatchExpressions...)
+		}
+	}
+
+	return builder.AddTolerations(corev1.Toleration{
+		Key:      constant.NodePoolLabelKey,
+		Operator: corev1.TolerationOpEqual,
+		Value:    poolLabel,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
+
+func (builder *PodBuilder) AddDNSSearchDomains(domains ...string) *PodBuilder {
+	addDNSSearchDomains(&builder.get().Spec, domains...)
+	return builder
+}
+
+// addDNSSearchDomains appends the search domains to the DNS config of the pod spec, the duplicated ones are skipped.
+// The DNS config is copied before changed, since it may be shared with the pod spec of the ClusterDefinition.
+func addDNSSearchDomains(podSpec *corev1.PodSpec, domains ...string) {
+	if podSpec.DNSConfig == nil {
+		podSpec.DNSConfig = &corev1.PodDNSConfig{}
+	} else {
+		podSpec.DNSConfig = podSpec.DNSConfig.DeepCopy()
+	}
+	for _, domain := range domains {
+		if !slices.Contains(podSpec.DNSConfig.Searches, domain) {
+			podSpec.DNSConfig.Searches = append(podSpec.DNSConfig.Searches, domain)
+		}
+	}
+}
@@ -24,6 +24,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/apecloud/kubeblocks/internal/constant"
 )
 
 var _ = Describe("pod builder", func() {
@@ -88,4 +90,34 @@ var _ = Describe("pod builder", func() {
 		Expect(pod.Spec.Tolerations).Should(HaveLen(1))
 		Expect(pod.Spec.Tolerations[0]).Should(Equal(tolerations[0]))
 	})
+
+	It("should pin the pod to a node pool", func() {
+		pool := "db-pool"
+		pod := NewPodBuilder("default", "foo").
+			AddTolerations(corev1.Toleration{
+				Key:      "node",
+				Operator: corev1.TolerationOpExists,
+			}).
+			SetNodePool(pool).
+			GetObject()
+
+		Expect(pod.Spec.NodeSelector).Should(HaveKeyWithValue(constant.NodePoolLabelKey, pool))
+		Expect(pod.Spec.Affinity).ShouldNot(BeNil())
+		Expect(pod.Spec.Affinity.NodeAffinity).ShouldNot(BeNil())
+		required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		Expect(required).ShouldNot(BeNil())
+		Expect(required.NodeSelectorTerms).Should(HaveLen(1))
+		Expect(required.NodeSelectorTerms[0].MatchExpressions).Should(ConsistOf(corev1.NodeSelectorRequirement{
+			Key:      constant.NodePoolLabelKey,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{pool},
+		}))
+		Expect(pod.Spec.Tolerations).Should(HaveLen(2))
+		taint := corev1.Taint{
+			Key:    constant.NodePoolLabelKey,
+			Value:  pool,
+			Effect: corev1.TaintEffectNoSchedule,
+		}
+		Expect(pod.Spec.Tolerations[1].ToleratesTaint(&taint)).Should(BeTrue())
+	})
 })
@@ -171,3 +171,28 @@ func (builder *ReplicatedStateMachineBuilder) SetCredential(credential workloads
 	builder.get().Spec.Credential = &credential
 	return builder
 }
+
+func (builder *ReplicatedStateMachineBuilder) SetOrdinals(ordinals *apps.StatefulSetOrdinals) *ReplicatedStateMachineBuilder {
+	builder.get().Spec.Ordinals = ordinals
+	return builder
+}
+
+func (builder *ReplicatedStateMachineBuilder) SetPodNamePrefix(prefix string) *ReplicatedStateMachineBuilder {
+	builder.get().Spec.PodNamePrefix = prefix
+	return builder
+}
+
+func (builder *ReplicatedStateMachineBuilder) SetSchedulerName(schedulerName string) *ReplicatedStateMachineBuilder {
+	builder.get().Spec.Template.Spec.SchedulerName = schedulerName
+	return builder
+}
+
+func (builder *ReplicatedStateMachineBuilder) SetPriorityClassName(priorityClassName string) *ReplicatedStateMachineBuilder {
+	builder.get().Spec.Template.Spec.PriorityClassName = priorityClassName
+	return builder
+}
+
+func (builder *ReplicatedStateMachineBuilder) AddDNSSearchDomains(domains ...string) *ReplicatedStateMachineBuilder {
+	addDNSSearchDomains(&builder.get().Spec.Template.Spec, domains...)
+	return builder
+}
@@ -158,6 +158,8 @@ var _ = Describe("replicated_state_machine builder", func() {
 			Username: workloads.CredentialVar{Value: "foo"},
 			Password: workloads.CredentialVar{Value: "bar"},
 		}
+		ordinals := apps.StatefulSetOrdinals{Start: 3}
+		schedulerName := "volcano"
 		rsm := NewReplicatedStateMachineBuilder(ns, name).
 			SetReplicas(replicas).
 			AddMatchLabel(selectorKey1, selectorValue1).
@@ -179,6 +181,8 @@ var _ = Describe("replicated_state_machine builder", func() {
 			SetService(service).
 			SetAlternativeServices(alternativeServices).
 			SetCredential(credential).
+			SetOrdinals(&ordinals).
+			SetSchedulerName(schedulerName).
 			GetObject()
 
 		Expect(rsm.Name).Should(Equal(name))
@@ -196,6 +200,7 @@ var _ = Describe("replicated_state_machine builder", func() {
 		Expect(rsm.Spec.Roles[0]).Should(Equal(role))
 		Expect(rsm.Spec.MembershipReconfiguration).ShouldNot(BeNil())
 		Expect(*rsm.Spec.MembershipReconfiguration).Should(Equal(reconfiguration))
+		template.Spec.SchedulerName = schedulerName
 		Expect(rsm.Spec.Template).Should(Equal(template))
 		Expect(rsm.Spec.VolumeClaimTemplates).Should(HaveLen(2))
 		Expect(rsm.Spec.VolumeClaimTemplates[0]).Should(Equal(vcs[0]))
@@ -220,5 +225,8 @@ var _ = Describe("replicated_state_machine builder", func() {
 		Expect(rsm.Spec.AlternativeServices).Should(Equal(alternativeServices))
 		Expect(rsm.Spec.Credential).ShouldNot(BeNil())
 		Expect(*rsm.Spec.Credential).Should(Equal(credential))
+		Expect(rsm.Spec.Ordinals).ShouldNot(BeNil())
+		Expect(*rsm.Spec.Ordinals).Should(Equal(ordinals))
+		Expect(rsm.Spec.Template.Spec.SchedulerName).Should(Equal(schedulerName))
 	})
 })
@@ -0,0 +1,39 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+type ResourceQuotaBuilder struct {
+	BaseBuilder[corev1.ResourceQuota, *corev1.ResourceQuota, ResourceQuotaBuilder]
+}
+
+func NewResourceQuotaBuilder(namespace, name string) *ResourceQuotaBuilder {
+	builder := &ResourceQuotaBuilder{}
+	builder.init(namespace, name, &corev1.ResourceQuota{}, builder)
+	return builder
+}
+
+func (builder *ResourceQuotaBuilder) SetHard(hard corev1.ResourceList) *ResourceQuotaBuilder {
+	builder.get().Spec.Hard = hard
+	return builder
+}
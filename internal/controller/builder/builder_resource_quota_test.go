@@ -0,0 +1,48 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("resource quota builder", func() {
+	It("should work well", func() {
+		const (
+			name = "foo"
+			ns   = "default"
+		)
+		hard := corev1.ResourceList{
+			corev1.ResourceLimitsCPU:    resource.MustParse("2"),
+			corev1.ResourceLimitsMemory: resource.MustParse("4Gi"),
+		}
+		quota := NewResourceQuotaBuilder(ns, name).
+			SetHard(hard).
+			GetObject()
+
+		Expect(quota.Name).Should(Equal(name))
+		Expect(quota.Namespace).Should(Equal(ns))
+		Expect(quota.Spec.Hard).Should(Equal(hard))
+	})
+})
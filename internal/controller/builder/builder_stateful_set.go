@@ -113,3 +113,23 @@ func (builder *StatefulSetBuilder) SetUpdateStrategyType(strategyType apps.State
 	builder.get().Spec.UpdateStrategy.Type = strategyType
 	return builder
 }
+
+func (builder *StatefulSetBuilder) SetOrdinals(ordinals *apps.StatefulSetOrdinals) *StatefulSetBuilder {
+	builder.get().Spec.Ordinals = ordinals
+	return builder
+}
+
+func (builder *StatefulSetBuilder) SetSchedulerName(schedulerName string) *StatefulSetBuilder {
+	builder.get().Spec.Template.Spec.SchedulerName = schedulerName
+	return builder
+}
+
+func (builder *StatefulSetBuilder) SetPriorityClassName(priorityClassName string) *StatefulSetBuilder {
+	builder.get().Spec.Template.Spec.PriorityClassName = priorityClassName
+	return builder
+}
+
+func (builder *StatefulSetBuilder) AddDNSSearchDomains(domains ...string) *StatefulSetBuilder {
+	addDNSSearchDomains(&builder.get().Spec.Template.Spec, domains...)
+	return builder
+}
@@ -101,6 +101,8 @@ var _ = Describe("stateful_set builder", func() {
 			},
 		}
 		strategyType := apps.OnDeleteStatefulSetStrategyType
+		ordinals := apps.StatefulSetOrdinals{Start: 3}
+		schedulerName := "volcano"
 		sts := NewStatefulSetBuilder(ns, name).
 			AddMatchLabel(selectorKey1, selectorValue1).
 			AddMatchLabels(selectorKey2, selectorValue2, selectorKey3, selectorValue3).
@@ -114,6 +116,8 @@ var _ = Describe("stateful_set builder", func() {
 			AddVolumeClaimTemplates(vc).
 			SetUpdateStrategy(strategy).
 			SetUpdateStrategyType(strategyType).
+			SetOrdinals(&ordinals).
+			SetSchedulerName(schedulerName).
 			GetObject()
 
 		Expect(sts.Name).Should(Equal(name))
@@ -128,6 +132,7 @@ var _ = Describe("stateful_set builder", func() {
 		Expect(sts.Spec.Replicas).ShouldNot(BeNil())
 		Expect(*sts.Spec.Replicas).Should(Equal(replicas))
 		Expect(sts.Spec.PodManagementPolicy).Should(Equal(policy))
+		template.Spec.SchedulerName = schedulerName
 		Expect(sts.Spec.Template).Should(Equal(template))
 		Expect(sts.Spec.VolumeClaimTemplates).Should(HaveLen(2))
 		Expect(sts.Spec.VolumeClaimTemplates[0]).Should(Equal(vcs[0]))
@@ -138,6 +143,9 @@ var _ = Describe("stateful_set builder", func() {
 		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).Should(Equal(partition))
 		Expect(sts.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable).ShouldNot(BeNil())
 		Expect(sts.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable).ShouldNot(Equal(maxUnavailable))
+		Expect(sts.Spec.Ordinals).ShouldNot(BeNil())
+		Expect(*sts.Spec.Ordinals).Should(Equal(ordinals))
+		Expect(sts.Spec.Template.Spec.SchedulerName).Should(Equal(schedulerName))
 
 		labelSelector := &metav1.LabelSelector{
 			MatchLabels: selectors,
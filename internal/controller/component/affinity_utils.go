@@ -21,8 +21,10 @@ package component
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -64,6 +66,125 @@ func BuildPodTopologySpreadConstraints(
 	return topologySpreadConstraints
 }
 
+// buildComponentAffinities translates the topology constraints declared on the cluster with the component as the source,
+// into the pod affinity (co-locate) or pod anti-affinity (spread) of the component, which refer to the pods of the
+// target components by their labels.
+func buildComponentAffinities(cluster *appsv1alpha1.Cluster, component *SynthesizedComponent) {
+	for _, affinity := range cluster.Spec.ComponentAffinities {
+		if affinity.ComponentName != component.Name || affinity.TargetComponentName == component.Name {
+			continue
+		}
+		applyComponentAffinity(component.PodSpec, cluster.Name, affinity)
+	}
+}
+
+func applyComponentAffinity(podSpec *corev1.PodSpec, clusterName string, affinity appsv1alpha1.ComponentAffinity) {
+	topologyKey := affinity.TopologyKey
+	if len(topologyKey) == 0 {
+		topologyKey = corev1.LabelHostname
+	}
+	term := corev1.PodAffinityTerm{
+		TopologyKey: topologyKey,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				constant.AppInstanceLabelKey:    clusterName,
+				constant.KBAppComponentLabelKey: affinity.TargetComponentName,
+			},
+		},
+	}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	switch affinity.Type {
+	case appsv1alpha1.CoLocateComponentAffinity:
+		if podSpec.Affinity.PodAffinity == nil {
+			podSpec.Affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		podAffinity := podSpec.Affinity.PodAffinity
+		addPodAffinityTerm(&podAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			&podAffinity.PreferredDuringSchedulingIgnoredDuringExecution, term, affinity.Required)
+	case appsv1alpha1.SpreadComponentAffinity:
+		if podSpec.Affinity.PodAntiAffinity == nil {
+			podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		podAntiAffinity := podSpec.Affinity.PodAntiAffinity
+		addPodAffinityTerm(&podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			&podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, term, affinity.Required)
+	}
+}
+
+// addPodAffinityTerm adds the term as a required one or a preferred one, the existing terms are not added again.
+func addPodAffinityTerm(required *[]corev1.PodAffinityTerm, preferred *[]corev1.WeightedPodAffinityTerm,
+	term corev1.PodAffinityTerm, isRequired bool) {
+	if isRequired {
+		if !slices.ContainsFunc(*required, func(t corev1.PodAffinityTerm) bool {
+			return reflect.DeepEqual(t, term)
+		}) {
+			*required = append(*required, term)
+		}
+		return
+	}
+	weightedTerm := corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term}
+	if !slices.ContainsFunc(*preferred, func(t corev1.WeightedPodAffinityTerm) bool {
+		return reflect.DeepEqual(t, weightedTerm)
+	}) {
+		*preferred = append(*preferred, weightedTerm)
+	}
+}
+
+// applySchedulingPolicy copies the scheduling policy of the component verbatim onto the pod spec, the topology spread
+// constraints of the policy take precedence over the ones derived from the affinity with the same topology key.
+func applySchedulingPolicy(component *SynthesizedComponent, policy *appsv1alpha1.SchedulingPolicy) {
+	if policy == nil {
+		return
+	}
+	if len(policy.NodeName) > 0 {
+		component.PodSpec.NodeName = policy.NodeName
+	}
+	component.PodSpec.TopologySpreadConstraints = mergeTopologySpreadConstraints(
+		component.PodSpec.TopologySpreadConstraints, policy.TopologySpreadConstraints)
+}
+
+// buildPriorityClassName gets the priority class of the pods of the component, the priorityClassName specified explicitly
+// takes precedence over the one translated from the eviction priority, which ranks the pods to evict under node pressure.
+func buildPriorityClassName(compSpec *appsv1alpha1.ClusterComponentSpec) string {
+	if len(compSpec.PriorityClassName) > 0 || compSpec.EvictionPolicy == nil {
+		return compSpec.PriorityClassName
+	}
+	switch compSpec.EvictionPolicy.Priority {
+	case appsv1alpha1.HighEvictionPriority:
+		return constant.HighEvictionPriorityClassName
+	case appsv1alpha1.MediumEvictionPriority:
+		return constant.MediumEvictionPriorityClassName
+	case appsv1alpha1.LowEvictionPriority:
+		return constant.LowEvictionPriorityClassName
+	default:
+		return ""
+	}
+}
+
+// mergeTopologySpreadConstraints replaces the constraints with the overrides of the same topology key.
+func mergeTopologySpreadConstraints(constraints, overrides []corev1.TopologySpreadConstraint) []corev1.TopologySpreadConstraint {
+	if len(overrides) == 0 {
+		return constraints
+	}
+	overriddenKeys := make(map[string]bool, len(overrides))
+	for _, constraint := range overrides {
+		overriddenKeys[constraint.TopologyKey] = true
+	}
+	var merged []corev1.TopologySpreadConstraint
+	for _, constraint := range constraints {
+		if !overriddenKeys[constraint.TopologyKey] {
+			merged = append(merged, constraint)
+		}
+	}
+	for _, constraint := range overrides {
+		merged = append(merged, *constraint.DeepCopy())
+	}
+	return merged
+}
+
 func BuildAffinity(cluster *appsv1alpha1.Cluster, clusterCompSpec *appsv1alpha1.ClusterComponentSpec) *appsv1alpha1.Affinity {
 	affinityTopoKey := func(policyType appsv1alpha1.AvailabilityPolicyType) string {
 		switch policyType {
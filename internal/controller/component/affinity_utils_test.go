@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
@@ -180,4 +181,76 @@ var _ = Describe("affinity utils", func() {
 			Expect(topologySpreadConstraints[0].TopologyKey).Should(Equal(topologyKey))
 		})
 	})
+
+	Context("with component affinities", func() {
+		const cacheCompName = "cache"
+
+		targetSelector := func(compName string) *metav1.LabelSelector {
+			return &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					constant.AppInstanceLabelKey:    clusterName,
+					constant.KBAppComponentLabelKey: compName,
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			buildObjs(appsv1alpha1.Preferred)
+			Expect(component).ShouldNot(BeNil())
+		})
+
+		It("should co-locate the pods with the ones of the target component", func() {
+			clusterObj.Spec.ComponentAffinities = []appsv1alpha1.ComponentAffinity{{
+				ComponentName:       mysqlCompName,
+				TargetComponentName: cacheCompName,
+				Type:                appsv1alpha1.CoLocateComponentAffinity,
+				Required:            true,
+			}}
+			buildComponentAffinities(clusterObj, component)
+
+			affinity := component.PodSpec.Affinity
+			Expect(affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution).Should(BeEmpty())
+			Expect(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).Should(Equal([]corev1.PodAffinityTerm{{
+				TopologyKey:   corev1.LabelHostname,
+				LabelSelector: targetSelector(cacheCompName),
+			}}))
+
+			By("the term is not added again")
+			buildComponentAffinities(clusterObj, component)
+			Expect(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).Should(HaveLen(1))
+		})
+
+		It("should spread the pods from the ones of the target component, and keep the anti-affinity within the component", func() {
+			clusterObj.Spec.ComponentAffinities = []appsv1alpha1.ComponentAffinity{{
+				ComponentName:       mysqlCompName,
+				TargetComponentName: cacheCompName,
+				Type:                appsv1alpha1.SpreadComponentAffinity,
+				TopologyKey:         corev1.LabelTopologyZone,
+			}}
+			inCompTerms := component.PodSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+			Expect(inCompTerms).ShouldNot(BeEmpty())
+			buildComponentAffinities(clusterObj, component)
+
+			antiAffinity := component.PodSpec.Affinity.PodAntiAffinity
+			Expect(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).Should(BeEmpty())
+			Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).Should(HaveLen(len(inCompTerms) + 1))
+			Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[len(inCompTerms)]).Should(Equal(corev1.WeightedPodAffinityTerm{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					TopologyKey:   corev1.LabelTopologyZone,
+					LabelSelector: targetSelector(cacheCompName),
+				},
+			}))
+		})
+
+		It("should not constrain the target component", func() {
+			clusterObj.Spec.ComponentAffinities = []appsv1alpha1.ComponentAffinity{{
+				ComponentName:       cacheCompName,
+				TargetComponentName: mysqlCompName,
+				Type:                appsv1alpha1.CoLocateComponentAffinity,
+			}}
+			buildComponentAffinities(clusterObj, component)
+			Expect(component.PodSpec.Affinity.PodAffinity).Should(BeNil())
+		})
+	})
 })
@@ -23,13 +23,16 @@ import (
 	"fmt"
 	"strings"
 
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/class"
 	cfgcore "github.com/apecloud/kubeblocks/internal/configuration/core"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/builder"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
@@ -190,36 +193,51 @@ func buildComponent(reqCtx intctrlutil.RequestCtx,
 	// make a copy of clusterCompDef
 	clusterCompDefObj := clusterCompDef.DeepCopy()
 	component := &SynthesizedComponent{
-		ClusterDefName:        clusterDef.Name,
-		ClusterName:           cluster.Name,
-		ClusterUID:            string(cluster.UID),
-		Name:                  clusterCompSpec.Name,
-		CompDefName:           clusterCompDefObj.Name,
-		CharacterType:         clusterCompDefObj.CharacterType,
-		WorkloadType:          clusterCompDefObj.WorkloadType,
-		StatelessSpec:         clusterCompDefObj.StatelessSpec,
-		StatefulSpec:          clusterCompDefObj.StatefulSpec,
-		ConsensusSpec:         clusterCompDefObj.ConsensusSpec,
-		ReplicationSpec:       clusterCompDefObj.ReplicationSpec,
-		RSMSpec:               clusterCompDefObj.RSMSpec,
-		PodSpec:               clusterCompDefObj.PodSpec,
-		Probes:                clusterCompDefObj.Probes,
-		LogConfigs:            clusterCompDefObj.LogConfigs,
-		HorizontalScalePolicy: clusterCompDefObj.HorizontalScalePolicy,
-		ConfigTemplates:       clusterCompDefObj.ConfigSpecs,
-		ScriptTemplates:       clusterCompDefObj.ScriptSpecs,
-		VolumeTypes:           clusterCompDefObj.VolumeTypes,
-		VolumeProtection:      clusterCompDefObj.VolumeProtectionSpec,
-		CustomLabelSpecs:      clusterCompDefObj.CustomLabelSpecs,
-		SwitchoverSpec:        clusterCompDefObj.SwitchoverSpec,
-		StatefulSetWorkload:   clusterCompDefObj.GetStatefulSetWorkload(),
-		MinAvailable:          clusterCompSpec.GetMinAvailable(clusterCompDefObj.GetMinAvailable()),
-		Replicas:              clusterCompSpec.Replicas,
-		EnabledLogs:           clusterCompSpec.EnabledLogs,
-		TLS:                   clusterCompSpec.TLS,
-		Issuer:                clusterCompSpec.Issuer,
-		ComponentDef:          clusterCompSpec.ComponentDefRef,
-		ServiceAccountName:    clusterCompSpec.ServiceAccountName,
+		ClusterDefName:             clusterDef.Name,
+		ClusterName:                cluster.Name,
+		ClusterUID:                 string(cluster.UID),
+		Name:                       clusterCompSpec.Name,
+		CompDefName:                clusterCompDefObj.Name,
+		CharacterType:              clusterCompDefObj.CharacterType,
+		WorkloadType:               clusterCompDefObj.WorkloadType,
+		StatelessSpec:              clusterCompDefObj.StatelessSpec,
+		StatefulSpec:               clusterCompDefObj.StatefulSpec,
+		ConsensusSpec:              clusterCompDefObj.ConsensusSpec,
+		ReplicationSpec:            clusterCompDefObj.ReplicationSpec,
+		RSMSpec:                    clusterCompDefObj.RSMSpec,
+		PodSpec:                    clusterCompDefObj.PodSpec,
+		Probes:                     clusterCompDefObj.Probes,
+		LogConfigs:                 clusterCompDefObj.LogConfigs,
+		HorizontalScalePolicy:      clusterCompDefObj.HorizontalScalePolicy,
+		ConfigTemplates:            clusterCompDefObj.ConfigSpecs,
+		ScriptTemplates:            clusterCompDefObj.ScriptSpecs,
+		VolumeTypes:                clusterCompDefObj.VolumeTypes,
+		VolumeProtection:           clusterCompDefObj.VolumeProtectionSpec,
+		CustomLabelSpecs:           clusterCompDefObj.CustomLabelSpecs,
+		SwitchoverSpec:             clusterCompDefObj.SwitchoverSpec,
+		BackendDiscovery:           clusterCompDefObj.BackendDiscovery,
+		StatefulSetWorkload:        clusterCompDefObj.GetStatefulSetWorkload(),
+		MinAvailable:               clusterCompSpec.GetMinAvailable(clusterCompDefObj.GetMinAvailable()),
+		MinReadySeconds:            clusterCompDefObj.MinReadySeconds,
+		Replicas:                   clusterCompSpec.Replicas,
+		OrdinalStart:               clusterCompSpec.OrdinalStart,
+		PodNamePrefix:              clusterCompSpec.PodNamePrefix,
+		EnabledLogs:                clusterCompSpec.EnabledLogs,
+		TLS:                        clusterCompSpec.TLS,
+		Issuer:                     clusterCompSpec.Issuer,
+		ComponentDef:               clusterCompSpec.ComponentDefRef,
+		ServiceAccountName:         clusterCompSpec.ServiceAccountName,
+		SchedulerName:              clusterCompSpec.SchedulerName,
+		PriorityClassName:          buildPriorityClassName(clusterCompSpec),
+		DNSSearchDomains:           clusterCompSpec.DNSSearchDomains,
+		WorkloadAnnotations:        clusterCompSpec.WorkloadAnnotations,
+		LightweightMode:            cluster.Spec.LightweightMode,
+		EvictionProtection:         clusterCompSpec.EvictionProtection,
+		InjectCPULimitEnv:          clusterCompSpec.InjectCPULimitEnv,
+		InitScriptConfigMap:        clusterCompSpec.InitScriptConfigMap,
+		InitScriptMountPath:        clusterCompSpec.InitScriptMountPath,
+		InitScriptContainers:       clusterCompSpec.InitScriptContainers,
+		VolumeClaimRetentionPolicy: clusterCompSpec.VolumeClaimRetentionPolicy,
 	}
 
 	if len(clusterCompVers) > 0 && clusterCompVers[0] != nil {
@@ -245,18 +263,48 @@ func buildComponent(reqCtx intctrlutil.RequestCtx,
 		return nil, err
 	}
 	component.PodSpec.TopologySpreadConstraints = BuildPodTopologySpreadConstraints(cluster, affinity, component)
+	buildComponentAffinities(cluster, component)
+	applySchedulingPolicy(component, clusterCompSpec.SchedulingPolicy)
 	if component.PodSpec.Tolerations, err = BuildTolerations(cluster, clusterCompSpec); err != nil {
 		reqCtx.Log.Error(err, "build pod tolerations failed.")
 		return nil, err
 	}
+	if pool := cluster.GetComponentNodePool(clusterCompSpec.Name); pool != "" {
+		pod := builder.NewPodBuilder("", "").SetPodSpec(*component.PodSpec).SetNodePool(pool).GetObject()
+		component.PodSpec = &pod.Spec
+	}
 
 	if clusterCompSpec.VolumeClaimTemplates != nil {
 		component.VolumeClaimTemplates = clusterCompSpec.ToVolumeClaimTemplates()
 	}
+	if err = buildEphemeralVolumes(clusterCompDefObj, clusterCompSpec, component); err != nil {
+		reqCtx.Log.Error(err, "build ephemeral volumes failed.")
+		return nil, err
+	}
+	if err = buildTmpfsVolumes(clusterCompSpec, component); err != nil {
+		reqCtx.Log.Error(err, "build tmpfs volumes failed.")
+		return nil, err
+	}
+	if err = buildVolumeSubPathMounts(clusterCompDefObj, component); err != nil {
+		reqCtx.Log.Error(err, "build volume subPath mounts failed.")
+		return nil, err
+	}
+	if err = buildPreStopHook(clusterCompDefObj, component); err != nil {
+		reqCtx.Log.Error(err, "build preStop hook failed.")
+		return nil, err
+	}
 
 	if clusterCompSpec.Resources.Requests != nil || clusterCompSpec.Resources.Limits != nil {
 		component.PodSpec.Containers[0].Resources = clusterCompSpec.Resources
 	}
+	// the kubelet holds the liveness probe of the main container until the startup probe succeeds.
+	if clusterCompSpec.StartupProbe != nil {
+		component.PodSpec.Containers[0].StartupProbe = clusterCompSpec.StartupProbe.DeepCopy()
+	}
+	if err = buildProbeOverrides(clusterCompSpec, component); err != nil {
+		reqCtx.Log.Error(err, "build probe overrides failed.")
+		return nil, err
+	}
 	if err = updateResources(cluster, component, *clusterCompSpec, clsMgr); err != nil {
 		reqCtx.Log.Error(err, "update class resources failed")
 		return nil, err
@@ -265,6 +313,8 @@ func buildComponent(reqCtx intctrlutil.RequestCtx,
 	if clusterCompDefObj.Service != nil {
 		service := corev1.Service{Spec: clusterCompDefObj.Service.ToSVCSpec()}
 		service.Spec.Type = corev1.ServiceTypeClusterIP
+		buildServiceSessionAffinity(&service, clusterCompSpec.SessionAffinity)
+		buildServiceRoleSelector(&service, clusterCompDefObj.Service, "")
 		component.Services = append(component.Services, service)
 		for _, item := range clusterCompSpec.Services {
 			service = corev1.Service{
@@ -272,15 +322,24 @@ func buildComponent(reqCtx intctrlutil.RequestCtx,
 					Name:        item.Name,
 					Annotations: item.Annotations,
 				},
-				Spec: service.Spec,
+				Spec: clusterCompDefObj.Service.ToSVCSpec(),
 			}
 			service.Spec.Type = item.ServiceType
+			buildServiceSessionAffinity(&service, item.SessionAffinity)
+			buildServiceRoleSelector(&service, clusterCompDefObj.Service, item.Name)
 			component.Services = append(component.Services, service)
 		}
 	}
 
 	buildMonitorConfig(clusterCompDefObj, clusterCompSpec, component)
 
+	// omit the probe and exporter sidecars in lightweight mode.
+	if component.LightweightMode {
+		omitExporterContainers(clusterCompDefObj, component)
+		disableMonitor(component)
+		component.Probes = nil
+	}
+
 	// lorry container requires a service account with adequate privileges.
 	// If lorry required and the serviceAccountName is not set,
 	// a default serviceAccountName will be assigned.
@@ -293,14 +352,23 @@ func buildComponent(reqCtx intctrlutil.RequestCtx,
 		reqCtx.Log.Error(err, "build probe container failed.")
 		return nil, err
 	}
+	buildSharedSecretMount(cluster, clusterCompDefObj, component)
+	buildBackendsMount(cluster, component)
 
 	replaceContainerPlaceholderTokens(component, GetEnvReplacementMapForConnCredential(cluster.GetName()))
 
+	overrideContainerImages(cluster, component)
+
 	if err = buildComponentRef(clusterDef, cluster, clusterCompDefObj, clusterCompSpec, component); err != nil {
 		reqCtx.Log.Error(err, "failed to merge componentRef")
 		return nil, err
 	}
 
+	if err = buildComponentVars(cluster, clusterDef, clusterCompDefObj, clusterCompSpec, component); err != nil {
+		reqCtx.Log.Error(err, "failed to resolve component vars")
+		return nil, err
+	}
+
 	if serviceReferences != nil {
 		component.ServiceReferences = serviceReferences
 	}
@@ -308,6 +376,239 @@ func buildComponent(reqCtx intctrlutil.RequestCtx,
 	return component, nil
 }
 
+// buildServiceSessionAffinity sets the session affinity of the component service, with the default timeout for ClientIP.
+func buildServiceSessionAffinity(service *corev1.Service, affinity corev1.ServiceAffinity) {
+	service.Spec.SessionAffinity = affinity
+	service.Spec.SessionAffinityConfig = nil
+	if affinity == corev1.ServiceAffinityClientIP {
+		service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{
+				TimeoutSeconds: pointer.Int32(corev1.DefaultClientIPServiceAffinitySeconds),
+			},
+		}
+	}
+}
+
+// buildServiceRoleSelector selects the pods of the role backing the service serviceName as declared by the component
+// definition, the services not declared are left to select the pods of the leader role.
+func buildServiceRoleSelector(service *corev1.Service, serviceSpec *appsv1alpha1.ServiceSpec, serviceName string) {
+	role := serviceSpec.GetRole(serviceName)
+	if len(role) == 0 {
+		return
+	}
+	if service.Spec.Selector == nil {
+		service.Spec.Selector = map[string]string{}
+	}
+	service.Spec.Selector[constant.RoleLabelKey] = role
+}
+
+// buildEphemeralVolumes adds the ephemeral volume claim templates into the pod spec as the pod volumes, they are
+// mounted by the containers as declared in the component definition. The data volumes of the stateful workloads
+// may not be ephemeral, as the data would be lost once the pods are re-created.
+func buildEphemeralVolumes(clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
+	clusterCompSpec *appsv1alpha1.ClusterComponentSpec,
+	component *SynthesizedComponent) error {
+	for _, vct := range clusterCompSpec.VolumeClaimTemplates {
+		if !vct.Ephemeral {
+			continue
+		}
+		if clusterCompDef.WorkloadType != appsv1alpha1.Stateless && slices.ContainsFunc(clusterCompDef.VolumeTypes,
+			func(volumeType appsv1alpha1.VolumeTypeSpec) bool {
+				return volumeType.Name == vct.Name && volumeType.Type == appsv1alpha1.VolumeTypeData
+			}) {
+			return fmt.Errorf("the data volume %s of %s workload can't be ephemeral", vct.Name, clusterCompDef.WorkloadType)
+		}
+		component.PodSpec.Volumes = append(component.PodSpec.Volumes, vct.ToEphemeralVolume())
+	}
+	return nil
+}
+
+// buildTmpfsVolumes adds the memory-medium emptyDir volumes into the pod spec, and mounts them into all the containers.
+func buildTmpfsVolumes(clusterCompSpec *appsv1alpha1.ClusterComponentSpec, component *SynthesizedComponent) error {
+	for _, tmpfs := range clusterCompSpec.TmpfsVolumes {
+		if tmpfs.SizeLimit.Sign() <= 0 {
+			return fmt.Errorf("the size limit of tmpfs volume %s should be positive, but got %s", tmpfs.Name, tmpfs.SizeLimit.String())
+		}
+		sizeLimit := tmpfs.SizeLimit.DeepCopy()
+		component.PodSpec.Volumes = append(component.PodSpec.Volumes, corev1.Volume{
+			Name: tmpfs.Name,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: &sizeLimit,
+				},
+			},
+		})
+		for i := range component.PodSpec.Containers {
+			component.PodSpec.Containers[i].VolumeMounts = append(component.PodSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      tmpfs.Name,
+				MountPath: tmpfs.MountPath,
+			})
+		}
+	}
+	return nil
+}
+
+// buildVolumeSubPathMounts mounts the subPaths of the volumes declared by the component definition into the containers,
+// all the subPaths of a volume are mounted from the same volume, i.e. the same PVC if it's claimed by a volumeClaimTemplate.
+func buildVolumeSubPathMounts(clusterCompDef *appsv1alpha1.ClusterComponentDefinition, component *SynthesizedComponent) error {
+	for _, subPath := range clusterCompDef.VolumeSubPaths {
+		mounted := false
+		for i := range component.PodSpec.Containers {
+			container := &component.PodSpec.Containers[i]
+			if len(subPath.Containers) > 0 && !slices.Contains(subPath.Containers, container.Name) {
+				continue
+			}
+			for _, volumeMount := range container.VolumeMounts {
+				if volumeMount.MountPath == subPath.MountPath {
+					return fmt.Errorf("the subPath %s of volume %s can't be mounted at %s in container %s, which is mounted by volume %s",
+						subPath.SubPath, subPath.VolumeName, subPath.MountPath, container.Name, volumeMount.Name)
+				}
+			}
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      subPath.VolumeName,
+				MountPath: subPath.MountPath,
+				SubPath:   subPath.SubPath,
+			})
+			mounted = true
+		}
+		if !mounted {
+			return fmt.Errorf("none of the containers %v to mount the subPath %s of volume %s is found",
+				subPath.Containers, subPath.SubPath, subPath.VolumeName)
+		}
+	}
+	return nil
+}
+
+// buildSharedSecretMount mounts the cluster-level shared secret into the containers of the component declaring it,
+// or all the containers if none is declared.
+func buildSharedSecretMount(cluster *appsv1alpha1.Cluster, clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
+	component *SynthesizedComponent) {
+	mount := clusterCompDef.SharedSecret
+	if mount == nil {
+		return
+	}
+	component.PodSpec.Volumes = append(component.PodSpec.Volumes, corev1.Volume{
+		Name: constant.SharedSecretVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: GenerateSharedSecretName(cluster.Name),
+			},
+		},
+	})
+	for i := range component.PodSpec.Containers {
+		container := &component.PodSpec.Containers[i]
+		if len(mount.Containers) > 0 && !slices.Contains(mount.Containers, container.Name) {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      constant.SharedSecretVolumeName,
+			MountPath: mount.MountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// buildBackendsMount mounts the ConfigMap listing the backends into the containers of the component declaring
+// backendDiscovery, or all the containers if none is declared.
+func buildBackendsMount(cluster *appsv1alpha1.Cluster, component *SynthesizedComponent) {
+	discovery := component.BackendDiscovery
+	if discovery == nil {
+		return
+	}
+	component.PodSpec.Volumes = append(component.PodSpec.Volumes, corev1.Volume{
+		Name: constant.BackendsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: GenerateBackendsConfigMapName(cluster.Name, component.Name)},
+			},
+		},
+	})
+	for i := range component.PodSpec.Containers {
+		container := &component.PodSpec.Containers[i]
+		if len(discovery.Containers) > 0 && !slices.Contains(discovery.Containers, container.Name) {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      constant.BackendsVolumeName,
+			MountPath: discovery.MountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// BuildBackendsConfigSpec builds the config spec of the ConfigMap listing the backends, with which the config manager
+// sidecar reloads the backends by the reloadOptions of the configConstraintRef once the file changes.
+// It returns nil if the component declares no backendDiscovery or configConstraintRef.
+func BuildBackendsConfigSpec(clusterName string, component *SynthesizedComponent) *appsv1alpha1.ComponentConfigSpec {
+	discovery := component.BackendDiscovery
+	if discovery == nil || len(discovery.ConfigConstraintRef) == 0 {
+		return nil
+	}
+	return &appsv1alpha1.ComponentConfigSpec{
+		ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{
+			Name:        constant.BackendsVolumeName,
+			TemplateRef: GenerateBackendsConfigMapName(clusterName, component.Name),
+			VolumeName:  constant.BackendsVolumeName,
+		},
+		ConfigConstraintRef: discovery.ConfigConstraintRef,
+	}
+}
+
+// buildPreStopHook sets the preStop hook declared by the component definition to the container, and raises the
+// termination grace period of the pod to cover the hook and the graceful shutdown.
+func buildPreStopHook(clusterCompDef *appsv1alpha1.ClusterComponentDefinition, component *SynthesizedComponent) error {
+	preStop := clusterCompDef.PreStop
+	if preStop == nil {
+		return nil
+	}
+	if len(component.PodSpec.Containers) == 0 {
+		return fmt.Errorf("no container to set the preStop hook")
+	}
+	index := 0
+	if preStop.ContainerName != "" {
+		if index, _ = intctrlutil.GetContainerByName(component.PodSpec.Containers, preStop.ContainerName); index < 0 {
+			return fmt.Errorf("the container %s to set the preStop hook is not found", preStop.ContainerName)
+		}
+	}
+	container := &component.PodSpec.Containers[index]
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+	container.Lifecycle.PreStop = &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{Command: preStop.Command},
+	}
+
+	gracePeriod := preStop.TerminationGracePeriodSeconds
+	if gracePeriod == nil {
+		return nil
+	}
+	podGracePeriod := int64(corev1.DefaultTerminationGracePeriodSeconds)
+	if component.PodSpec.TerminationGracePeriodSeconds != nil {
+		podGracePeriod = *component.PodSpec.TerminationGracePeriodSeconds
+	}
+	if *gracePeriod > podGracePeriod {
+		component.PodSpec.TerminationGracePeriodSeconds = pointer.Int64(*gracePeriod)
+	}
+	return nil
+}
+
+// buildProbeOverrides merges the timing overrides of the component over the probes of the containers,
+// the probes not defined are not added, as the handlers can't be overridden.
+func buildProbeOverrides(clusterCompSpec *appsv1alpha1.ClusterComponentSpec, component *SynthesizedComponent) error {
+	for _, override := range clusterCompSpec.ProbeOverrides {
+		index, _ := intctrlutil.GetContainerByName(component.PodSpec.Containers, override.ContainerName)
+		if index < 0 {
+			return fmt.Errorf("the container %s to override the probes is not found", override.ContainerName)
+		}
+		container := &component.PodSpec.Containers[index]
+		override.LivenessProbe.ApplyTo(container.LivenessProbe)
+		override.ReadinessProbe.ApplyTo(container.ReadinessProbe)
+		override.StartupProbe.ApplyTo(container.StartupProbe)
+	}
+	return nil
+}
+
 // appendOrOverrideContainerAttr appends targetContainer to compContainers or overrides the attributes of compContainers with a given targetContainer,
 // if targetContainer does not exist in compContainers, it will be appended. otherwise it will be updated with the attributes of the target container.
 func appendOrOverrideContainerAttr(compContainers []corev1.Container, targetContainer corev1.Container) []corev1.Container {
@@ -447,6 +748,39 @@ func GenerateConnCredential(clusterName string) string {
 	return fmt.Sprintf("%s-conn-credential", clusterName)
 }
 
+func GenerateConnCredentialSnapshotName(clusterName string) string {
+	return fmt.Sprintf("%s-conn-credential-snapshot", clusterName)
+}
+
+func GenerateSharedSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-shared-secret", clusterName)
+}
+
+func GenerateBackendsConfigMapName(clusterName, compName string) string {
+	return fmt.Sprintf("%s-%s-backends", clusterName, compName)
+}
+
+func GenerateSpecHistoryName(clusterName string) string {
+	return fmt.Sprintf("%s-spec-history", clusterName)
+}
+
+func GenerateLastAppliedClusterName(clusterName string) string {
+	return fmt.Sprintf("%s-last-applied-cluster", clusterName)
+}
+
+// GenerateComponentServiceName generates the name of the service of the component, the default service which is named
+// after the workload if svcName is empty, or the service declared in spec.components[*].services.
+func GenerateComponentServiceName(clusterName, compName, svcName string) string {
+	if len(svcName) == 0 {
+		return fmt.Sprintf("%s-%s", clusterName, compName)
+	}
+	return fmt.Sprintf("%s-%s-%s", clusterName, compName, svcName)
+}
+
+func GenerateRoleServiceName(clusterName, compName, role string) string {
+	return fmt.Sprintf("%s-%s-%s", clusterName, compName, role)
+}
+
 func GenerateDefaultServiceDescriptorName(clusterName string) string {
 	return fmt.Sprintf("kbsd-%s", GenerateConnCredential(clusterName))
 }
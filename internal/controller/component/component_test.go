@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package component
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -212,6 +214,663 @@ var _ = Describe("component module", func() {
 			Expect(component.PodSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey).Should(Equal("kubernetes.io/hostname"))
 		})
 
+		It("build scheduler name correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				SetSchedulerName("volcano").
+				GetObject()
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.SchedulerName).Should(Equal("volcano"))
+		})
+
+		It("build scheduling policy correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			zoneConstraint := corev1.TopologySpreadConstraint{
+				MaxSkew:           2,
+				TopologyKey:       corev1.LabelTopologyZone,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "mysql"},
+				},
+				MinDomains: pointer.Int32(3),
+			}
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				SetSchedulerName("volcano").
+				SetSchedulingPolicy(&appsv1alpha1.SchedulingPolicy{
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{zoneConstraint},
+					NodeName:                  "node-0",
+				}).
+				GetObject()
+			cluster.Spec.Affinity = &appsv1alpha1.Affinity{
+				TopologyKeys: []string{corev1.LabelHostname, corev1.LabelTopologyZone},
+			}
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+
+			By("the scheduler name of the component is kept")
+			Expect(component.SchedulerName).Should(Equal("volcano"))
+			Expect(component.PodSpec.NodeName).Should(Equal("node-0"))
+
+			By("the constraint of the policy replaces the derived one with the same topology key verbatim")
+			constraints := component.PodSpec.TopologySpreadConstraints
+			Expect(constraints).Should(HaveLen(2))
+			Expect(constraints[0].TopologyKey).Should(Equal(corev1.LabelHostname))
+			Expect(constraints[0].LabelSelector.MatchLabels).Should(HaveKeyWithValue(constant.KBAppComponentLabelKey, mysqlCompName))
+			Expect(constraints[1]).Should(Equal(zoneConstraint))
+		})
+
+		It("build eviction priority correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				SetEvictionPolicy(appsv1alpha1.HighEvictionPriority).
+				GetObject()
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PriorityClassName).Should(Equal(constant.HighEvictionPriorityClassName))
+
+			By("the priorityClassName specified explicitly takes precedence over the eviction priority")
+			cluster.Spec.ComponentSpecs[0].PriorityClassName = "critical-database"
+			component, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PriorityClassName).Should(Equal("critical-database"))
+		})
+
+		It("build node pool correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			const pool = "db-pool"
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddAnnotations(constant.NodePoolAnnotationKey, fmt.Sprintf("%s=%s,other=other-pool", mysqlCompName, pool)).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				GetObject()
+			cluster.Spec.Affinity = &appsv1alpha1.Affinity{
+				NodeLabels: map[string]string{"disk": "ssd"},
+			}
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+
+			By("the pod spec targets the pool via the node selector")
+			Expect(component.PodSpec.NodeSelector).Should(HaveKeyWithValue(constant.NodePoolLabelKey, pool))
+
+			By("the pool requirement is ANDed into the required node affinity")
+			required := component.PodSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+			Expect(required.NodeSelectorTerms).ShouldNot(BeEmpty())
+			for _, term := range required.NodeSelectorTerms {
+				Expect(term.MatchExpressions).Should(ContainElement(corev1.NodeSelectorRequirement{
+					Key:      constant.NodePoolLabelKey,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{pool},
+				}))
+				Expect(term.MatchExpressions).Should(ContainElement(HaveField("Key", "disk")))
+			}
+
+			By("the pod spec tolerates the taint of the pool")
+			Expect(component.PodSpec.Tolerations).Should(ContainElement(corev1.Toleration{
+				Key:      constant.NodePoolLabelKey,
+				Operator: corev1.TolerationOpEqual,
+				Value:    pool,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}))
+
+			By("the component not listed in the annotation isn't pinned to any pool")
+			cluster.Annotations[constant.NodePoolAnnotationKey] = "other=other-pool"
+			component, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PodSpec.NodeSelector).ShouldNot(HaveKey(constant.NodePoolLabelKey))
+
+			By("a pool without the component name applies to all components")
+			cluster.Annotations[constant.NodePoolAnnotationKey] = pool
+			component, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PodSpec.NodeSelector).Should(HaveKeyWithValue(constant.NodePoolLabelKey, pool))
+		})
+
+		It("build startup probe correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			livenessProbe := &corev1.Probe{
+				ProbeHandler:     corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(3306)}},
+				FailureThreshold: 3,
+			}
+			defaultStartupProbe := &corev1.Probe{
+				ProbeHandler:     corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(3306)}},
+				PeriodSeconds:    10,
+				FailureThreshold: 30,
+			}
+			clusterDef.Spec.ComponentDefs[0].PodSpec.Containers[0].LivenessProbe = livenessProbe
+			clusterDef.Spec.ComponentDefs[0].PodSpec.Containers[0].StartupProbe = defaultStartupProbe
+
+			By("defaulting from the clusterDefinition")
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PodSpec.Containers[0].StartupProbe).Should(Equal(defaultStartupProbe))
+
+			By("overriding by the component")
+			startupProbe := &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					Exec: &corev1.ExecAction{Command: []string{"mysqladmin", "ping"}},
+				},
+				PeriodSeconds:    10,
+				FailureThreshold: 60,
+			}
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				SetStartupProbe(startupProbe).
+				GetObject()
+			component, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			mainContainer := component.PodSpec.Containers[0]
+			Expect(mainContainer.StartupProbe).Should(Equal(startupProbe))
+			// the liveness probe is kept, which is held by the kubelet until the startup probe succeeds.
+			Expect(mainContainer.LivenessProbe).Should(Equal(livenessProbe))
+			for _, c := range component.PodSpec.Containers[1:] {
+				Expect(c.StartupProbe).ShouldNot(Equal(startupProbe))
+			}
+		})
+
+		It("build probe overrides correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			mainContainer := &clusterDef.Spec.ComponentDefs[0].PodSpec.Containers[0]
+			mainContainer.LivenessProbe = &corev1.Probe{
+				ProbeHandler:     corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(3306)}},
+				PeriodSeconds:    10,
+				TimeoutSeconds:   1,
+				FailureThreshold: 3,
+			}
+			mainContainer.ReadinessProbe = nil
+
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				AddProbeOverride(appsv1alpha1.ContainerProbeOverride{
+					ContainerName: mainContainer.Name,
+					LivenessProbe: &appsv1alpha1.ProbeTimingOverride{
+						TimeoutSeconds:   pointer.Int32(5),
+						FailureThreshold: pointer.Int32(6),
+					},
+					ReadinessProbe: &appsv1alpha1.ProbeTimingOverride{
+						PeriodSeconds: pointer.Int32(30),
+					},
+				}).
+				GetObject()
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			livenessProbe := component.PodSpec.Containers[0].LivenessProbe
+			Expect(livenessProbe).ShouldNot(BeNil())
+			Expect(livenessProbe.TCPSocket).ShouldNot(BeNil())
+			Expect(livenessProbe.PeriodSeconds).Should(Equal(int32(10)))
+			Expect(livenessProbe.TimeoutSeconds).Should(Equal(int32(5)))
+			Expect(livenessProbe.FailureThreshold).Should(Equal(int32(6)))
+			// the probe not defined is not added by the override.
+			Expect(component.PodSpec.Containers[0].ReadinessProbe).Should(BeNil())
+
+			By("overriding the probes of an unknown container")
+			cluster.Spec.ComponentSpecs[0].ProbeOverrides[0].ContainerName = "unknown"
+			_, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("build ephemeral volumes correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			storageClassName := "local-path"
+			cachePVCSpec := testapps.NewPVCSpec("2Gi")
+			cachePVCSpec.StorageClassName = &storageClassName
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				AddVolumeClaimTemplate(testapps.DataVolumeName, testapps.NewPVCSpec("1Gi")).
+				AddEphemeralVolumeClaimTemplate(testapps.LogVolumeName, testapps.NewPVCSpec("1Gi")).
+				AddEphemeralVolumeClaimTemplate("cache", cachePVCSpec).
+				GetObject()
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+
+			By("only the persistent volume is claimed by the PVC")
+			Expect(component.VolumeClaimTemplates).Should(HaveLen(1))
+			Expect(component.VolumeClaimTemplates[0].Name).Should(Equal(testapps.DataVolumeName))
+
+			By("the ephemeral volume without storage class is rendered as emptyDir")
+			sizeLimit := resource.MustParse("1Gi")
+			Expect(component.PodSpec.Volumes).Should(ContainElement(corev1.Volume{
+				Name: testapps.LogVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &sizeLimit},
+				},
+			}))
+
+			By("the ephemeral volume with storage class is rendered as generic ephemeral volume")
+			Expect(component.PodSpec.Volumes).Should(ContainElement(corev1.Volume{
+				Name: "cache",
+				VolumeSource: corev1.VolumeSource{
+					Ephemeral: &corev1.EphemeralVolumeSource{
+						VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+							Spec: cachePVCSpec.ToV1PersistentVolumeClaimSpec(),
+						},
+					},
+				},
+			}))
+
+			By("the data volume of stateful workload can't be ephemeral")
+			cluster.Spec.ComponentSpecs[0].VolumeClaimTemplates[0].Ephemeral = true
+			_, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("can't be ephemeral"))
+		})
+
+		It("build tmpfs volumes correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			sizeLimit := resource.MustParse("512Mi")
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				AddComponentTmpfsVolume("cache", sizeLimit, "/cache").
+				GetObject()
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PodSpec.Volumes).Should(ContainElement(corev1.Volume{
+				Name: "cache",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						Medium:    corev1.StorageMediumMemory,
+						SizeLimit: &sizeLimit,
+					},
+				},
+			}))
+			Expect(component.PodSpec.Containers[0].VolumeMounts).Should(ContainElement(corev1.VolumeMount{Name: "cache", MountPath: "/cache"}))
+
+			By("the size limit should be positive")
+			cluster.Spec.ComponentSpecs[0].TmpfsVolumes[0].SizeLimit = resource.MustParse("0")
+			_, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("mount the subPaths of one volume claim template", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			compDef := clusterDef.Spec.ComponentDefs[0].DeepCopy()
+			compDef.VolumeSubPaths = []appsv1alpha1.VolumeSubPathMount{
+				{
+					VolumeName: testapps.DataVolumeName,
+					SubPath:    "mysql-data",
+					MountPath:  "/data/mysql",
+					Containers: []string{testapps.DefaultMySQLContainerName},
+				},
+				{
+					VolumeName: testapps.DataVolumeName,
+					SubPath:    "mysql-log",
+					MountPath:  "/data/log",
+				},
+			}
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.VolumeClaimTemplates).Should(HaveLen(1))
+			Expect(component.VolumeClaimTemplates[0].Name).Should(Equal(testapps.DataVolumeName))
+			_, container := intctrlutil.GetContainerByName(component.PodSpec.Containers, testapps.DefaultMySQLContainerName)
+			Expect(container).ShouldNot(BeNil())
+			Expect(container.VolumeMounts).Should(ContainElements(
+				corev1.VolumeMount{Name: testapps.DataVolumeName, MountPath: "/data/mysql", SubPath: "mysql-data"},
+				corev1.VolumeMount{Name: testapps.DataVolumeName, MountPath: "/data/log", SubPath: "mysql-log"},
+			))
+
+			By("the mount path should not conflict with the existing mounts")
+			compDef.VolumeSubPaths[1].MountPath = container.VolumeMounts[0].MountPath
+			_, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(HaveOccurred())
+
+			By("the containers to mount should exist")
+			compDef.VolumeSubPaths = compDef.VolumeSubPaths[:1]
+			compDef.VolumeSubPaths[0].Containers = []string{"not-exist"}
+			_, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("mount the shared secret into the declared containers", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			const mountPath = "/etc/shared-secret"
+			sharedSecretMount := corev1.VolumeMount{Name: constant.SharedSecretVolumeName, MountPath: mountPath, ReadOnly: true}
+			compDef := clusterDef.Spec.ComponentDefs[0].DeepCopy()
+			compDef.SharedSecret = &appsv1alpha1.SharedSecretMount{MountPath: mountPath}
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PodSpec.Volumes).Should(ContainElement(corev1.Volume{
+				Name: constant.SharedSecretVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: GenerateSharedSecretName(clusterName)},
+				},
+			}))
+			for _, container := range component.PodSpec.Containers {
+				Expect(container.VolumeMounts).Should(ContainElement(sharedSecretMount))
+			}
+
+			By("mount into the declared containers only")
+			compDef.SharedSecret.Containers = []string{testapps.DefaultMySQLContainerName}
+			component, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			for _, container := range component.PodSpec.Containers {
+				if container.Name == testapps.DefaultMySQLContainerName {
+					Expect(container.VolumeMounts).Should(ContainElement(sharedSecretMount))
+				} else {
+					Expect(container.VolumeMounts).ShouldNot(ContainElement(sharedSecretMount))
+				}
+			}
+
+			By("not mount into the component not declaring it")
+			component, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				&clusterDef.Spec.ComponentDefs[0],
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			for _, volume := range component.PodSpec.Volumes {
+				Expect(volume.Name).ShouldNot(Equal(constant.SharedSecretVolumeName))
+			}
+		})
+
+		It("mount the backends into the declared containers", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			const mountPath = "/etc/proxy/backends"
+			backendsMount := corev1.VolumeMount{Name: constant.BackendsVolumeName, MountPath: mountPath, ReadOnly: true}
+			compDef := clusterDef.Spec.ComponentDefs[0].DeepCopy()
+			compDef.BackendDiscovery = &appsv1alpha1.BackendDiscovery{
+				CompDef:    mysqlCompDefName,
+				MountPath:  mountPath,
+				Containers: []string{testapps.DefaultMySQLContainerName},
+			}
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			backendsConfigMapName := GenerateBackendsConfigMapName(clusterName, mysqlCompName)
+			Expect(component.PodSpec.Volumes).Should(ContainElement(corev1.Volume{
+				Name: constant.BackendsVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: backendsConfigMapName},
+					},
+				},
+			}))
+			for _, container := range component.PodSpec.Containers {
+				if container.Name == testapps.DefaultMySQLContainerName {
+					Expect(container.VolumeMounts).Should(ContainElement(backendsMount))
+				} else {
+					Expect(container.VolumeMounts).ShouldNot(ContainElement(backendsMount))
+				}
+			}
+
+			By("reload the backends by the config manager only if the configConstraintRef is declared")
+			Expect(BuildBackendsConfigSpec(clusterName, component)).Should(BeNil())
+			component.BackendDiscovery.ConfigConstraintRef = "proxy-backends-reload"
+			configSpec := BuildBackendsConfigSpec(clusterName, component)
+			Expect(configSpec).ShouldNot(BeNil())
+			Expect(configSpec.VolumeName).Should(Equal(constant.BackendsVolumeName))
+			Expect(configSpec.TemplateRef).Should(Equal(backendsConfigMapName))
+			Expect(configSpec.ConfigConstraintRef).Should(Equal("proxy-backends-reload"))
+		})
+
+		It("build the preStop hook correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			compDef := clusterDef.Spec.ComponentDefs[0].DeepCopy()
+			compDef.PreStop = &appsv1alpha1.PreStopHook{
+				Command:                       []string{"mysqladmin", "shutdown"},
+				TerminationGracePeriodSeconds: pointer.Int64(120),
+			}
+			component, err := BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			container := component.PodSpec.Containers[0]
+			Expect(container.Lifecycle).ShouldNot(BeNil())
+			Expect(container.Lifecycle.PreStop).Should(Equal(&corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"mysqladmin", "shutdown"}},
+			}))
+			Expect(component.PodSpec.TerminationGracePeriodSeconds).Should(Equal(pointer.Int64(120)))
+
+			By("the termination grace period of the pod is kept if longer")
+			compDef.PodSpec.TerminationGracePeriodSeconds = pointer.Int64(300)
+			component, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.PodSpec.TerminationGracePeriodSeconds).Should(Equal(pointer.Int64(300)))
+
+			By("the container to set the hook should exist")
+			compDef.PreStop.ContainerName = "not-exist"
+			_, err = BuildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(HaveOccurred())
+		})
+
 		It("build monitor correctly", func() {
 			reqCtx := intctrlutil.RequestCtx{
 				Ctx: ctx,
@@ -257,6 +916,49 @@ var _ = Describe("component module", func() {
 			Expect(component.Monitor.Enable).Should(Equal(true))
 		})
 
+		It("build component in lightweight mode correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			By("add an exporter sidecar to clusterdefinition")
+			compDef := &clusterDef.Spec.ComponentDefs[0]
+			compDef.PodSpec.Containers = append(compDef.PodSpec.Containers, corev1.Container{
+				Name:  "exporter",
+				Image: "exporter",
+				Ports: []corev1.ContainerPort{{Name: "http-metrics", ContainerPort: 9104}},
+			})
+			compDef.Monitor = &appsv1alpha1.MonitorConfig{
+				BuiltIn: false,
+				Exporter: &appsv1alpha1.ExporterConfig{
+					ScrapePort: intstr.FromString("http-metrics"),
+					ScrapePath: "/metrics",
+				},
+			}
+			interval := intstr.Parse("10s")
+			cluster.Spec.Monitor.MonitoringInterval = &interval
+			By("enable lightweight mode")
+			cluster.Spec.LightweightMode = true
+			By("call build")
+			component, err := buildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component).ShouldNot(BeNil())
+			Expect(component.LightweightMode).Should(BeTrue())
+			Expect(component.Probes).Should(BeNil())
+			Expect(component.Monitor.Enable).Should(BeFalse())
+			for _, container := range component.PodSpec.Containers {
+				Expect(container.Name).ShouldNot(Equal("exporter"))
+			}
+		})
+
 		It("build network correctly", func() {
 			reqCtx := intctrlutil.RequestCtx{
 				Ctx: ctx,
@@ -288,6 +990,45 @@ var _ = Describe("component module", func() {
 			Expect(component.Services[1].Spec.Type).Should(BeEquivalentTo("LoadBalancer"))
 		})
 
+		It("build the role selectors of services correctly", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			By("declare the services backed by the custom roles")
+			compDef := clusterDef.Spec.ComponentDefs[0].DeepCopy()
+			compDef.Service.ServiceRoles = []appsv1alpha1.ServiceRole{
+				{Role: "writer"},
+				{ServiceName: "readonly", Role: "reader"},
+			}
+			cluster = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).
+				AddService("readonly", corev1.ServiceTypeClusterIP).
+				AddService("vpc", corev1.ServiceTypeLoadBalancer).
+				GetObject()
+			component, err := buildComponent(
+				reqCtx,
+				nil,
+				cluster,
+				clusterDef,
+				compDef,
+				&cluster.Spec.ComponentSpecs[0],
+				nil,
+				&clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(component.Services).Should(HaveLen(3))
+
+			By("the default service selects the writer")
+			Expect(component.Services[0].Spec.Selector).Should(HaveKeyWithValue(constant.RoleLabelKey, "writer"))
+			By("the readonly service selects the reader")
+			Expect(component.Services[1].Name).Should(Equal("readonly"))
+			Expect(component.Services[1].Spec.Selector).Should(HaveKeyWithValue(constant.RoleLabelKey, "reader"))
+			By("the service not declared is left to select the leader")
+			Expect(component.Services[2].Name).Should(Equal("vpc"))
+			Expect(component.Services[2].Spec.Selector).ShouldNot(HaveKey(constant.RoleLabelKey))
+		})
+
 		It("Test replace secretRef env placeholder token", func() {
 			By("mock connect credential and do replace placeholder token")
 			credentialMap := GetEnvReplacementMapForConnCredential(cluster.Name)
@@ -0,0 +1,282 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+// buildComponentVars resolves the vars declared in the component definition, and injects them into
+// all the containers of the component as env vars.
+func buildComponentVars(cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
+	clusterCompSpec *appsv1alpha1.ClusterComponentSpec,
+	component *SynthesizedComponent) error {
+	if len(clusterCompDef.Vars) == 0 {
+		return nil
+	}
+	vars, err := ResolveComponentVars(cluster, clusterDef, clusterCompDef, clusterCompSpec)
+	if err != nil {
+		return err
+	}
+	for i := range component.PodSpec.InitContainers {
+		component.PodSpec.InitContainers[i].Env = append(component.PodSpec.InitContainers[i].Env, vars...)
+	}
+	for i := range component.PodSpec.Containers {
+		component.PodSpec.Containers[i].Env = append(component.PodSpec.Containers[i].Env, vars...)
+	}
+	return nil
+}
+
+// ResolveComponentVars resolves the vars declared in the component definition to env vars.
+func ResolveComponentVars(cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
+	clusterCompSpec *appsv1alpha1.ClusterComponentSpec) ([]corev1.EnvVar, error) {
+	vars := make([]corev1.EnvVar, 0, len(clusterCompDef.Vars))
+	for _, v := range clusterCompDef.Vars {
+		env, err := resolveComponentVar(cluster, clusterDef, clusterCompSpec, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve var %s of component %s: %s", v.Name, clusterCompSpec.Name, err.Error())
+		}
+		vars = append(vars, *env)
+	}
+	return vars, nil
+}
+
+// GetComponentVarDependencies gets the names of the components whose services are referred by the vars of the component,
+// the workload of the component should be created after theirs.
+func GetComponentVarDependencies(cluster *appsv1alpha1.Cluster,
+	clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
+	compName string) []string {
+	var dependencies []string
+	for _, v := range clusterCompDef.Vars {
+		if v.ValueFrom.ServiceRef == nil {
+			continue
+		}
+		for _, comp := range cluster.Spec.GetDefNameMappingComponents()[v.ValueFrom.ServiceRef.CompDef] {
+			if comp.Name != compName && !slices.Contains(dependencies, comp.Name) {
+				dependencies = append(dependencies, comp.Name)
+			}
+		}
+	}
+	return dependencies
+}
+
+// GetComponentVarSecrets gets the names of the secrets referred by the credential vars of the component,
+// the workload of the component should be created after them.
+func GetComponentVarSecrets(cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	clusterCompDef *appsv1alpha1.ClusterComponentDefinition) []string {
+	var secrets []string
+	for _, v := range clusterCompDef.Vars {
+		if v.ValueFrom.CredentialRef == nil {
+			continue
+		}
+		secretName, err := getCredentialVarSecretName(cluster, clusterDef, v.ValueFrom.CredentialRef)
+		if err == nil && !slices.Contains(secrets, secretName) {
+			secrets = append(secrets, secretName)
+		}
+	}
+	return secrets
+}
+
+// ValidateComponentVars validates that the vars of all the components can be resolved,
+// and there are no circular dependencies between the components referring to each other's services.
+func ValidateComponentVars(cluster *appsv1alpha1.Cluster, clusterDef *appsv1alpha1.ClusterDefinition) error {
+	dependencies := make(map[string][]string)
+	for i := range cluster.Spec.ComponentSpecs {
+		compSpec := &cluster.Spec.ComponentSpecs[i]
+		compDef := clusterDef.GetComponentDefByName(compSpec.ComponentDefRef)
+		if compDef == nil || len(compDef.Vars) == 0 {
+			continue
+		}
+		if _, err := ResolveComponentVars(cluster, clusterDef, compDef, compSpec); err != nil {
+			return err
+		}
+		dependencies[compSpec.Name] = GetComponentVarDependencies(cluster, compDef, compSpec.Name)
+	}
+	return checkCircularVarDependencies(dependencies)
+}
+
+func checkCircularVarDependencies(dependencies map[string][]string) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[string]int)
+	var visit func(compName string, path []string) error
+	visit = func(compName string, path []string) error {
+		path = append(slices.Clone(path), compName)
+		switch states[compName] {
+		case visiting:
+			return fmt.Errorf("circular var dependencies found between components: %s", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+		states[compName] = visiting
+		for _, dependency := range dependencies[compName] {
+			if err := visit(dependency, path); err != nil {
+				return err
+			}
+		}
+		states[compName] = visited
+		return nil
+	}
+
+	compNames := make([]string, 0, len(dependencies))
+	for compName := range dependencies {
+		compNames = append(compNames, compName)
+	}
+	sort.Strings(compNames)
+	for _, compName := range compNames {
+		if err := visit(compName, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveComponentVar(cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	clusterCompSpec *appsv1alpha1.ClusterComponentSpec,
+	v appsv1alpha1.ComponentVar) (*corev1.EnvVar, error) {
+	var (
+		env = &corev1.EnvVar{Name: v.Name}
+		err error
+	)
+	source := v.ValueFrom
+	switch {
+	case source.ServiceRef != nil:
+		env.Value, err = resolveServiceVar(cluster, clusterDef, source.ServiceRef)
+	case source.CredentialRef != nil:
+		env.ValueFrom, err = resolveCredentialVar(cluster, clusterDef, source.CredentialRef)
+	case source.MetaRef != nil:
+		env.Value, err = resolveMetaVar(cluster, clusterCompSpec, source.MetaRef)
+	default:
+		err = fmt.Errorf("no source of the var is specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// getVarReferredComponent gets the only component referring to the componentDef in the cluster.
+func getVarReferredComponent(cluster *appsv1alpha1.Cluster, compDefName string) (*appsv1alpha1.ClusterComponentSpec, error) {
+	comps := cluster.Spec.GetDefNameMappingComponents()[compDefName]
+	if len(comps) != 1 {
+		return nil, fmt.Errorf("expect one component referring to componentDef %s but got %d", compDefName, len(comps))
+	}
+	return &comps[0], nil
+}
+
+func resolveServiceVar(cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	selector *appsv1alpha1.ServiceVarSelector) (string, error) {
+	comp, err := getVarReferredComponent(cluster, selector.CompDef)
+	if err != nil {
+		return "", err
+	}
+	compDef := clusterDef.GetComponentDefByName(selector.CompDef)
+	if compDef == nil || compDef.Service == nil {
+		return "", fmt.Errorf("componentDef %s does not have service", selector.CompDef)
+	}
+	if len(selector.Service) > 0 && !slices.ContainsFunc(comp.Services, func(svc appsv1alpha1.ClusterComponentService) bool {
+		return svc.Name == selector.Service
+	}) {
+		return "", fmt.Errorf("service %s is not found in component %s", selector.Service, comp.Name)
+	}
+	if len(selector.Port) == 0 {
+		return GenerateComponentServiceName(cluster.Name, comp.Name, selector.Service), nil
+	}
+	for _, port := range compDef.Service.Ports {
+		if port.Name == selector.Port {
+			return strconv.Itoa(int(port.Port)), nil
+		}
+	}
+	return "", fmt.Errorf("port %s is not found in the service of componentDef %s", selector.Port, selector.CompDef)
+}
+
+func resolveCredentialVar(cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	selector *appsv1alpha1.CredentialVarSelector) (*corev1.EnvVarSource, error) {
+	secretName, err := getCredentialVarSecretName(cluster, clusterDef, selector)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  selector.Key,
+		},
+	}, nil
+}
+
+// getCredentialVarSecretName gets the name of the secret the credential var is resolved from.
+func getCredentialVarSecretName(cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	selector *appsv1alpha1.CredentialVarSelector) (string, error) {
+	if len(selector.CompDef) == 0 {
+		return GenerateConnCredential(cluster.Name), nil
+	}
+	comp, err := getVarReferredComponent(cluster, selector.CompDef)
+	if err != nil {
+		return "", err
+	}
+	compDef := clusterDef.GetComponentDefByName(selector.CompDef)
+	if compDef == nil || compDef.SystemAccounts == nil || !slices.ContainsFunc(compDef.SystemAccounts.Accounts,
+		func(account appsv1alpha1.SystemAccountConfig) bool { return string(account.Name) == selector.Account }) {
+		return "", fmt.Errorf("system account %q is not found in componentDef %s", selector.Account, selector.CompDef)
+	}
+	return fmt.Sprintf("%s-%s-%s", cluster.Name, comp.Name, selector.Account), nil
+}
+
+func resolveMetaVar(cluster *appsv1alpha1.Cluster,
+	clusterCompSpec *appsv1alpha1.ClusterComponentSpec,
+	selector *appsv1alpha1.MetaVarSelector) (string, error) {
+	comp := clusterCompSpec
+	if len(selector.CompDef) > 0 {
+		var err error
+		if comp, err = getVarReferredComponent(cluster, selector.CompDef); err != nil {
+			return "", err
+		}
+	}
+	switch selector.Field {
+	case appsv1alpha1.ClusterNameMetaVarField:
+		return cluster.Name, nil
+	case appsv1alpha1.ClusterNamespaceMetaVarField:
+		return cluster.Namespace, nil
+	case appsv1alpha1.ComponentNameMetaVarField:
+		return comp.Name, nil
+	case appsv1alpha1.ReplicasMetaVarField:
+		return strconv.Itoa(int(comp.Replicas)), nil
+	}
+	return "", fmt.Errorf("unsupported metadata field %s", selector.Field)
+}
@@ -0,0 +1,290 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
+)
+
+var _ = Describe("Component Vars Tests", func() {
+	const (
+		clusterDefName     = "test-clusterdef"
+		clusterName        = "test-cluster"
+		clusterVersionName = "test-clusterversion"
+		clusterNamespace   = "test-vars"
+		mysqlCompDefName   = "mysql-def"
+		proxyCompDefName   = "proxy-def"
+		mysqlCompName      = "mysql"
+		proxyCompName      = "proxy"
+	)
+
+	var (
+		clusterDef *appsv1alpha1.ClusterDefinition
+		cluster    *appsv1alpha1.Cluster
+	)
+
+	// the componentDef returned by ClusterDefinition.GetComponentDefByName is a copy
+	getCompDef := func(compDefName string) *appsv1alpha1.ClusterComponentDefinition {
+		for i := range clusterDef.Spec.ComponentDefs {
+			if clusterDef.Spec.ComponentDefs[i].Name == compDefName {
+				return &clusterDef.Spec.ComponentDefs[i]
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		clusterDef = testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.StatefulMySQLComponent, mysqlCompDefName).
+			AddComponentDef(testapps.StatelessNginxComponent, proxyCompDefName).
+			GetObject()
+		mysqlCompDef := getCompDef(mysqlCompDefName)
+		mysqlCompDef.Service = &appsv1alpha1.ServiceSpec{
+			Ports: []appsv1alpha1.ServicePort{{Name: "mysql", Port: 3306}},
+		}
+		mysqlCompDef.SystemAccounts = &appsv1alpha1.SystemAccountSpec{
+			Accounts: []appsv1alpha1.SystemAccountConfig{{Name: appsv1alpha1.AdminAccount}},
+		}
+		cluster = testapps.NewClusterFactory(clusterNamespace, clusterName, clusterDefName, clusterVersionName).
+			AddComponent(mysqlCompName, mysqlCompDefName).SetReplicas(3).
+			AddComponent(proxyCompName, proxyCompDefName).SetReplicas(1).
+			GetObject()
+	})
+
+	setProxyVars := func(vars ...appsv1alpha1.ComponentVar) *appsv1alpha1.ClusterComponentDefinition {
+		proxyCompDef := getCompDef(proxyCompDefName)
+		proxyCompDef.Vars = vars
+		return proxyCompDef
+	}
+
+	resolveProxyVars := func(vars ...appsv1alpha1.ComponentVar) ([]corev1.EnvVar, error) {
+		return ResolveComponentVars(cluster, clusterDef, setProxyVars(vars...), cluster.Spec.GetComponentByName(proxyCompName))
+	}
+
+	Context("resolve vars", func() {
+		It("resolves the service vars", func() {
+			envs, err := resolveProxyVars(
+				appsv1alpha1.ComponentVar{
+					Name:      "MYSQL_HOST",
+					ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName}},
+				},
+				appsv1alpha1.ComponentVar{
+					Name:      "MYSQL_PORT",
+					ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName, Port: "mysql"}},
+				})
+			Expect(err).Should(Succeed())
+			Expect(envs).Should(Equal([]corev1.EnvVar{
+				{Name: "MYSQL_HOST", Value: clusterName + "-" + mysqlCompName},
+				{Name: "MYSQL_PORT", Value: "3306"},
+			}))
+
+			By("the service declared in the component is selected")
+			cluster.Spec.GetComponentByName(mysqlCompName).Services = []appsv1alpha1.ClusterComponentService{
+				{Name: "vpc", ServiceType: corev1.ServiceTypeLoadBalancer},
+			}
+			envs, err = resolveProxyVars(appsv1alpha1.ComponentVar{
+				Name:      "MYSQL_VPC_HOST",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName, Service: "vpc"}},
+			})
+			Expect(err).Should(Succeed())
+			Expect(envs).Should(Equal([]corev1.EnvVar{
+				{Name: "MYSQL_VPC_HOST", Value: GenerateComponentServiceName(clusterName, mysqlCompName, "vpc")},
+			}))
+
+			By("the service is not declared in the component")
+			_, err = resolveProxyVars(appsv1alpha1.ComponentVar{
+				Name:      "MYSQL_INTERNET_HOST",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName, Service: "internet"}},
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("service internet is not found"))
+
+			By("the port is not found")
+			_, err = resolveProxyVars(appsv1alpha1.ComponentVar{
+				Name:      "MYSQL_PORT",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName, Port: "paxos"}},
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("port paxos is not found"))
+
+			By("the componentDef has no service")
+			_, err = resolveProxyVars(appsv1alpha1.ComponentVar{
+				Name:      "PROXY_HOST",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: proxyCompDefName}},
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("does not have service"))
+		})
+
+		It("resolves the credential vars", func() {
+			envs, err := resolveProxyVars(
+				appsv1alpha1.ComponentVar{
+					Name:      "CONN_PASSWORD",
+					ValueFrom: appsv1alpha1.ComponentVarSource{CredentialRef: &appsv1alpha1.CredentialVarSelector{Key: "password"}},
+				},
+				appsv1alpha1.ComponentVar{
+					Name: "ADMIN_PASSWORD",
+					ValueFrom: appsv1alpha1.ComponentVarSource{CredentialRef: &appsv1alpha1.CredentialVarSelector{
+						CompDef: mysqlCompDefName,
+						Account: string(appsv1alpha1.AdminAccount),
+						Key:     "password",
+					}},
+				})
+			Expect(err).Should(Succeed())
+			Expect(envs).Should(HaveLen(2))
+			Expect(envs[0].ValueFrom.SecretKeyRef.Name).Should(Equal(GenerateConnCredential(clusterName)))
+			Expect(envs[0].ValueFrom.SecretKeyRef.Key).Should(Equal("password"))
+			Expect(envs[1].ValueFrom.SecretKeyRef.Name).Should(Equal(clusterName + "-" + mysqlCompName + "-" + string(appsv1alpha1.AdminAccount)))
+			Expect(envs[1].ValueFrom.SecretKeyRef.Key).Should(Equal("password"))
+
+			By("the system account is not found")
+			_, err = resolveProxyVars(appsv1alpha1.ComponentVar{
+				Name: "ADMIN_PASSWORD",
+				ValueFrom: appsv1alpha1.ComponentVarSource{CredentialRef: &appsv1alpha1.CredentialVarSelector{
+					CompDef: mysqlCompDefName,
+					Account: "foo",
+					Key:     "password",
+				}},
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(`system account "foo" is not found`))
+		})
+
+		It("resolves the meta vars", func() {
+			metaVar := func(name, compDef string, field appsv1alpha1.MetaVarField) appsv1alpha1.ComponentVar {
+				return appsv1alpha1.ComponentVar{
+					Name:      name,
+					ValueFrom: appsv1alpha1.ComponentVarSource{MetaRef: &appsv1alpha1.MetaVarSelector{CompDef: compDef, Field: field}},
+				}
+			}
+			envs, err := resolveProxyVars(
+				metaVar("CLUSTER_NAME", "", appsv1alpha1.ClusterNameMetaVarField),
+				metaVar("CLUSTER_NAMESPACE", "", appsv1alpha1.ClusterNamespaceMetaVarField),
+				metaVar("COMPONENT_NAME", "", appsv1alpha1.ComponentNameMetaVarField),
+				metaVar("REPLICAS", "", appsv1alpha1.ReplicasMetaVarField),
+				metaVar("MYSQL_COMPONENT_NAME", mysqlCompDefName, appsv1alpha1.ComponentNameMetaVarField),
+				metaVar("MYSQL_REPLICAS", mysqlCompDefName, appsv1alpha1.ReplicasMetaVarField))
+			Expect(err).Should(Succeed())
+			Expect(envs).Should(Equal([]corev1.EnvVar{
+				{Name: "CLUSTER_NAME", Value: clusterName},
+				{Name: "CLUSTER_NAMESPACE", Value: clusterNamespace},
+				{Name: "COMPONENT_NAME", Value: proxyCompName},
+				{Name: "REPLICAS", Value: "1"},
+				{Name: "MYSQL_COMPONENT_NAME", Value: mysqlCompName},
+				{Name: "MYSQL_REPLICAS", Value: "3"},
+			}))
+		})
+
+		It("fails to resolve the vars referring to absent components", func() {
+			_, err := resolveProxyVars(appsv1alpha1.ComponentVar{
+				Name:      "REDIS_HOST",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: "redis-def"}},
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("failed to resolve var REDIS_HOST of component proxy"))
+			Expect(err.Error()).Should(ContainSubstring("expect one component referring to componentDef redis-def but got 0"))
+
+			By("no source is specified")
+			_, err = resolveProxyVars(appsv1alpha1.ComponentVar{Name: "FOO"})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("no source of the var is specified"))
+		})
+
+		It("injects the vars into all the containers", func() {
+			proxyCompDef := setProxyVars(appsv1alpha1.ComponentVar{
+				Name:      "MYSQL_HOST",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName}},
+			})
+			component := &SynthesizedComponent{PodSpec: proxyCompDef.PodSpec.DeepCopy()}
+			Expect(component.PodSpec.Containers).ShouldNot(BeEmpty())
+			Expect(buildComponentVars(cluster, clusterDef, proxyCompDef, cluster.Spec.GetComponentByName(proxyCompName), component)).Should(Succeed())
+			for _, c := range component.PodSpec.Containers {
+				Expect(c.Env).Should(ContainElement(corev1.EnvVar{Name: "MYSQL_HOST", Value: clusterName + "-" + mysqlCompName}))
+			}
+		})
+	})
+
+	Context("var dependencies", func() {
+		It("gets the components referred by the service vars", func() {
+			proxyCompDef := setProxyVars(
+				appsv1alpha1.ComponentVar{
+					Name:      "MYSQL_HOST",
+					ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName}},
+				},
+				appsv1alpha1.ComponentVar{
+					Name:      "MYSQL_PORT",
+					ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName, Port: "mysql"}},
+				},
+				appsv1alpha1.ComponentVar{
+					Name:      "MYSQL_REPLICAS",
+					ValueFrom: appsv1alpha1.ComponentVarSource{MetaRef: &appsv1alpha1.MetaVarSelector{CompDef: mysqlCompDefName, Field: appsv1alpha1.ReplicasMetaVarField}},
+				})
+			Expect(GetComponentVarDependencies(cluster, proxyCompDef, proxyCompName)).Should(Equal([]string{mysqlCompName}))
+			Expect(ValidateComponentVars(cluster, clusterDef)).Should(Succeed())
+		})
+
+		It("gets the secrets referred by the credential vars", func() {
+			proxyCompDef := setProxyVars(
+				appsv1alpha1.ComponentVar{
+					Name:      "ROOT_PASSWORD",
+					ValueFrom: appsv1alpha1.ComponentVarSource{CredentialRef: &appsv1alpha1.CredentialVarSelector{Key: "password"}},
+				},
+				appsv1alpha1.ComponentVar{
+					Name:      "ROOT_USERNAME",
+					ValueFrom: appsv1alpha1.ComponentVarSource{CredentialRef: &appsv1alpha1.CredentialVarSelector{Key: "username"}},
+				},
+				appsv1alpha1.ComponentVar{
+					Name: "ADMIN_PASSWORD",
+					ValueFrom: appsv1alpha1.ComponentVarSource{CredentialRef: &appsv1alpha1.CredentialVarSelector{
+						CompDef: mysqlCompDefName, Account: string(appsv1alpha1.AdminAccount), Key: "password"}},
+				})
+			Expect(GetComponentVarSecrets(cluster, clusterDef, proxyCompDef)).Should(Equal([]string{
+				GenerateConnCredential(clusterName),
+				clusterName + "-" + mysqlCompName + "-" + string(appsv1alpha1.AdminAccount),
+			}))
+		})
+
+		It("detects the circular dependencies", func() {
+			proxyCompDef := getCompDef(proxyCompDefName)
+			proxyCompDef.Service = &appsv1alpha1.ServiceSpec{
+				Ports: []appsv1alpha1.ServicePort{{Name: "proxy", Port: 6033}},
+			}
+			setProxyVars(appsv1alpha1.ComponentVar{
+				Name:      "MYSQL_HOST",
+				ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: mysqlCompDefName}},
+			})
+			getCompDef(mysqlCompDefName).Vars = []appsv1alpha1.ComponentVar{
+				{
+					Name:      "PROXY_HOST",
+					ValueFrom: appsv1alpha1.ComponentVarSource{ServiceRef: &appsv1alpha1.ServiceVarSelector{CompDef: proxyCompDefName}},
+				},
+			}
+			err := ValidateComponentVars(cluster, clusterDef)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("circular var dependencies found between components: mysql -> proxy -> mysql"))
+		})
+	})
+})
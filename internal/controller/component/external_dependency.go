@@ -0,0 +1,94 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+// defaultExternalDependencyProbeTimeout is the timeout of the probes not specifying timeoutSeconds.
+const defaultExternalDependencyProbeTimeout = 3 * time.Second
+
+// ExternalDependencyProber probes whether an external dependency declared by the ClusterDefinition is reachable.
+type ExternalDependencyProber interface {
+	Probe(ctx context.Context, dependency appsv1alpha1.ExternalDependency) error
+}
+
+// NetExternalDependencyProber probes the external dependencies from the controller, by opening a TCP connection
+// or sending an HTTP GET request to them.
+type NetExternalDependencyProber struct {
+	client *http.Client
+}
+
+var _ ExternalDependencyProber = &NetExternalDependencyProber{}
+
+// NewNetExternalDependencyProber creates a NetExternalDependencyProber.
+func NewNetExternalDependencyProber() *NetExternalDependencyProber {
+	return &NetExternalDependencyProber{
+		client: &http.Client{
+			// the redirects are taken as reachable, the same as the HTTP probes of kubelet.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (p *NetExternalDependencyProber) Probe(ctx context.Context, dependency appsv1alpha1.ExternalDependency) error {
+	timeout := time.Duration(dependency.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultExternalDependencyProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case dependency.TCPSocket != nil:
+		address := net.JoinHostPort(dependency.TCPSocket.Host, strconv.Itoa(int(dependency.TCPSocket.Port)))
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case dependency.HTTPGet != nil:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, dependency.HTTPGet.URL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("unexpected status code %d of %s", resp.StatusCode, dependency.HTTPGet.URL)
+		}
+		return nil
+	default:
+		return fmt.Errorf("no probe is specified for the external dependency %s", dependency.Name)
+	}
+}
@@ -0,0 +1,422 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+const (
+	defaultRegistry     = "docker.io"
+	defaultRegistryHost = "registry-1.docker.io"
+	defaultImageTag     = "latest"
+	digestSeparator     = "@"
+	digestHeader        = "Docker-Content-Digest"
+)
+
+// manifestMediaTypes are the media types of the manifests accepted when resolving the digest,
+// the index types come first so the digest of multi-arch images is the one of the index.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ImageDigestResolver resolves the image referenced by tag to the digest of its manifest,
+// authenticating to the registry with the credentials if any.
+type ImageDigestResolver interface {
+	Resolve(ctx context.Context, image string, creds RegistryCredentials) (string, error)
+}
+
+// RegistryAuth is the credential to authenticate to a registry.
+type RegistryAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// RegistryCredentials are the credentials keyed by the registry hosts, e.g. "docker.io".
+type RegistryCredentials map[string]RegistryAuth
+
+// Get returns the credential of the registry, the anonymous one is returned if absent.
+func (c RegistryCredentials) Get(registry string) (RegistryAuth, bool) {
+	auth, ok := c[registry]
+	return auth, ok && (len(auth.Username) > 0 || len(auth.Password) > 0)
+}
+
+// ParseImagePullSecrets parses the credentials from the image pull secrets of type
+// kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg, the malformed ones are ignored.
+func ParseImagePullSecrets(secrets []corev1.Secret) RegistryCredentials {
+	creds := RegistryCredentials{}
+	for _, secret := range secrets {
+		auths := map[string]RegistryAuth{}
+		switch secret.Type {
+		case corev1.SecretTypeDockerConfigJson:
+			config := struct {
+				Auths map[string]RegistryAuth `json:"auths"`
+			}{}
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+				continue
+			}
+			auths = config.Auths
+		case corev1.SecretTypeDockercfg:
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+				continue
+			}
+		default:
+			continue
+		}
+		for server, auth := range auths {
+			if len(auth.Username) == 0 && len(auth.Auth) > 0 {
+				decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+				if err != nil {
+					continue
+				}
+				auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+			}
+			registry := normalizeRegistry(server)
+			// the credentials of the secrets listed first take precedence, as the kubelet does
+			if _, ok := creds[registry]; !ok {
+				creds[registry] = auth
+			}
+		}
+	}
+	return creds
+}
+
+// normalizeRegistry trims the scheme and path of the registry server in the docker config,
+// e.g. "https://index.docker.io/v1/" is normalized to "docker.io".
+func normalizeRegistry(server string) string {
+	if _, rest, found := strings.Cut(server, "://"); found {
+		server = rest
+	}
+	server, _, _ = strings.Cut(server, "/")
+	switch server {
+	case "index.docker.io", defaultRegistryHost:
+		return defaultRegistry
+	}
+	return server
+}
+
+// RegistryDigestResolver resolves the digests by the Registry HTTP API V2, with the anonymous token
+// or the token authorized by the credential of the registry.
+type RegistryDigestResolver struct {
+	Client *http.Client
+}
+
+var _ ImageDigestResolver = &RegistryDigestResolver{}
+
+// NewRegistryDigestResolver creates a RegistryDigestResolver with the given timeout for each request.
+func NewRegistryDigestResolver(timeout time.Duration) *RegistryDigestResolver {
+	return &RegistryDigestResolver{Client: &http.Client{Timeout: timeout}}
+}
+
+// Resolve heads the manifest of the image and returns the digest from the Docker-Content-Digest header.
+func (r *RegistryDigestResolver) Resolve(ctx context.Context, image string, creds RegistryCredentials) (string, error) {
+	registry, repository, tag := ParseImage(image)
+	host := registry
+	if host == defaultRegistry {
+		host = defaultRegistryHost
+	}
+	auth, hasAuth := creds.Get(registry)
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, repository, tag)
+	resp, err := r.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		var authorization string
+		challenge := resp.Header.Get("WWW-Authenticate")
+		switch {
+		case strings.HasPrefix(challenge, "Basic") && hasAuth:
+			authorization = "Basic " + basicAuth(auth)
+		default:
+			token, err := r.fetchToken(ctx, challenge, auth, hasAuth)
+			if err != nil {
+				return "", fmt.Errorf("failed to authorize for image %s: %w", image, err)
+			}
+			authorization = "Bearer " + token
+		}
+		if resp, err = r.headManifest(ctx, manifestURL, authorization); err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get the manifest of image %s: %s", image, resp.Status)
+	}
+	digest := resp.Header.Get(digestHeader)
+	if !strings.HasPrefix(digest, "sha256:") {
+		return "", fmt.Errorf("unexpected digest of image %s: %q", image, digest)
+	}
+	return digest, nil
+}
+
+func (r *RegistryDigestResolver) headManifest(ctx context.Context, manifestURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ","))
+	if len(authorization) > 0 {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// fetchToken requests a token from the realm of the bearer challenge, e.g.
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull",
+// the token is anonymous if there is no credential of the registry.
+func (r *RegistryDigestResolver) fetchToken(ctx context.Context, challenge string, auth RegistryAuth, hasAuth bool) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported auth challenge: %q", challenge)
+	}
+	params := parseChallengeParams(strings.TrimPrefix(challenge, "Bearer "))
+	realm, err := url.Parse(params["realm"])
+	if err != nil || len(realm.Host) == 0 {
+		return "", fmt.Errorf("invalid realm of auth challenge: %q", challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if len(params[key]) > 0 {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasAuth {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get the token: %s", resp.Status)
+	}
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if len(body.Token) > 0 {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+func (r *RegistryDigestResolver) client() *http.Client {
+	if r.Client == nil {
+		return http.DefaultClient
+	}
+	return r.Client
+}
+
+func basicAuth(auth RegistryAuth) string {
+	return base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+}
+
+// CachedDigestResolver caches the digests resolved and backs off the images failed to resolve, so the registries
+// are not requested in every reconciliation. The resolved digests are cached for the ttl, keyed by the image and
+// the user authenticated, as the pinned images are kept in the status of the clusters in the meantime.
+type CachedDigestResolver struct {
+	Resolver ImageDigestResolver
+	TTL      time.Duration
+	Backoff  *flowcontrol.Backoff
+
+	mu      sync.Mutex
+	digests map[string]cachedDigest
+}
+
+type cachedDigest struct {
+	digest   string
+	expireAt time.Time
+}
+
+var _ ImageDigestResolver = &CachedDigestResolver{}
+
+// NewCachedDigestResolver creates a CachedDigestResolver backing off the failed images from the initial to the max duration.
+func NewCachedDigestResolver(resolver ImageDigestResolver, ttl, initialBackoff, maxBackoff time.Duration) *CachedDigestResolver {
+	return &CachedDigestResolver{
+		Resolver: resolver,
+		TTL:      ttl,
+		Backoff:  flowcontrol.NewBackOff(initialBackoff, maxBackoff),
+		digests:  map[string]cachedDigest{},
+	}
+}
+
+// Resolve returns the digest cached, or the error without requesting the registry if the image is backing off.
+func (r *CachedDigestResolver) Resolve(ctx context.Context, image string, creds RegistryCredentials) (string, error) {
+	registry, _, _ := ParseImage(image)
+	auth, _ := creds.Get(registry)
+	key := image + "|" + auth.Username
+	now := r.Backoff.Clock.Now()
+
+	r.mu.Lock()
+	cached, ok := r.digests[key]
+	r.mu.Unlock()
+	if ok && now.Before(cached.expireAt) {
+		return cached.digest, nil
+	}
+	if r.Backoff.IsInBackOffSinceUpdate(key, now) {
+		return "", fmt.Errorf("failed to resolve the digest of image %s recently, backing off", image)
+	}
+
+	digest, err := r.Resolver.Resolve(ctx, image, creds)
+	if err != nil {
+		r.Backoff.Next(key, r.Backoff.Clock.Now())
+		return "", err
+	}
+	r.Backoff.Reset(key)
+	r.mu.Lock()
+	if r.digests == nil {
+		r.digests = map[string]cachedDigest{}
+	}
+	r.digests[key] = cachedDigest{digest: digest, expireAt: now.Add(r.TTL)}
+	r.mu.Unlock()
+	return digest, nil
+}
+
+func parseChallengeParams(s string) map[string]string {
+	params := map[string]string{}
+	for _, item := range strings.Split(s, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(item), "=")
+		if found {
+			params[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return params
+}
+
+// ParseImage splits the image referenced by tag into the registry, repository and tag,
+// with the defaults of docker hub, e.g. "mysql" is parsed as "docker.io", "library/mysql" and "latest".
+func ParseImage(image string) (string, string, string) {
+	image, _, _ = strings.Cut(image, digestSeparator)
+	registry := defaultRegistry
+	remainder := image
+	if first, rest, found := strings.Cut(image, "/"); found &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, remainder = first, rest
+	}
+	tag := defaultImageTag
+	if i := strings.LastIndex(remainder, ":"); i > 0 {
+		remainder, tag = remainder[:i], remainder[i+1:]
+	}
+	if registry == defaultRegistry && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+	return registry, remainder, tag
+}
+
+// IsPinImagesByDigestEnabled checks whether the images of the cluster are pinned by digest,
+// which is enabled by spec.pinImagesByDigest or the annotation apps.kubeblocks.io/pin-images-by-digest.
+func IsPinImagesByDigestEnabled(cluster *appsv1alpha1.Cluster) bool {
+	return cluster.Spec.PinImagesByDigest ||
+		strings.EqualFold(cluster.Annotations[constant.PinImagesByDigestAnnotationKey], "true")
+}
+
+// IsImagePinned checks whether the image is referenced by digest.
+func IsImagePinned(image string) bool {
+	return strings.Contains(image, digestSeparator)
+}
+
+// PinImage returns the image referenced by the digest, keeping the registry and repository as written.
+func PinImage(image, digest string) string {
+	name := image
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name = image[:i]
+	}
+	return name + digestSeparator + digest
+}
+
+// GetComponentImages returns the images of the containers of the component definition,
+// overridden by the component version if specified.
+func GetComponentImages(compDef *appsv1alpha1.ClusterComponentDefinition,
+	compVer *appsv1alpha1.ClusterComponentVersion) []string {
+	var images []string
+	appendImages := func(containers []corev1.Container) {
+		for _, c := range containers {
+			if len(c.Image) > 0 && !IsImagePinned(c.Image) {
+				images = append(images, c.Image)
+			}
+		}
+	}
+	if compDef.PodSpec != nil {
+		podSpec := compDef.PodSpec.DeepCopy()
+		if compVer != nil {
+			for _, c := range compVer.VersionsCtx.InitContainers {
+				podSpec.InitContainers = appendOrOverrideContainerAttr(podSpec.InitContainers, c)
+			}
+			for _, c := range compVer.VersionsCtx.Containers {
+				podSpec.Containers = appendOrOverrideContainerAttr(podSpec.Containers, c)
+			}
+		}
+		appendImages(podSpec.InitContainers)
+		appendImages(podSpec.Containers)
+	}
+	return images
+}
+
+// overrideContainerImages applies the image pull policy and the pinned images of the cluster to all containers.
+func overrideContainerImages(cluster *appsv1alpha1.Cluster, component *SynthesizedComponent) {
+	if component.PodSpec == nil {
+		return
+	}
+	override := func(containers []corev1.Container) {
+		for i := range containers {
+			if len(cluster.Spec.ImagePullPolicy) > 0 {
+				containers[i].ImagePullPolicy = cluster.Spec.ImagePullPolicy
+			}
+			if !IsPinImagesByDigestEnabled(cluster) {
+				continue
+			}
+			if pinned, ok := cluster.Status.PinnedImages[containers[i].Image]; ok {
+				containers[i].Image = pinned
+			}
+		}
+	}
+	override(component.PodSpec.InitContainers)
+	override(component.PodSpec.Containers)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseImage(t *testing.T) {
+	cases := []struct {
+		image      string
+		registry   string
+		repository string
+		tag        string
+	}{
+		{"nginx", "docker.io", "library/nginx", "latest"},
+		{"mysql:8.0.33", "docker.io", "library/mysql", "8.0.33"},
+		{"apecloud/apecloud-mysql-server:8.0.30", "docker.io", "apecloud/apecloud-mysql-server", "8.0.30"},
+		{"docker.io/apecloud/apecloud-mysql-server:latest", "docker.io", "apecloud/apecloud-mysql-server", "latest"},
+		{"localhost:5000/mysql", "localhost:5000", "mysql", "latest"},
+		{"registry.cn-hangzhou.aliyuncs.com/apecloud/mysql:8.0@sha256:abc", "registry.cn-hangzhou.aliyuncs.com", "apecloud/mysql", "8.0"},
+	}
+	for _, c := range cases {
+		registry, repository, tag := ParseImage(c.image)
+		if registry != c.registry || repository != c.repository || tag != c.tag {
+			t.Errorf("parse %s, expected %s %s %s, got %s %s %s", c.image, c.registry, c.repository, c.tag, registry, repository, tag)
+		}
+	}
+}
+
+func TestPinImage(t *testing.T) {
+	cases := map[string]string{
+		"nginx":                      "nginx@sha256:abc",
+		"mysql:8.0.33":               "mysql@sha256:abc",
+		"localhost:5000/mysql":       "localhost:5000/mysql@sha256:abc",
+		"localhost:5000/mysql:8.0.3": "localhost:5000/mysql@sha256:abc",
+	}
+	for image, expected := range cases {
+		if pinned := PinImage(image, "sha256:abc"); pinned != expected {
+			t.Errorf("pin %s, expected %s, got %s", image, expected, pinned)
+		}
+	}
+}
+
+func TestRegistryDigestResolver(t *testing.T) {
+	const (
+		digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		token  = "anonymous-token"
+	)
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if r.URL.Query().Get("scope") != "repository:apecloud/mysql:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"token": %q}`, token)
+		case r.URL.Path == "/v2/apecloud/mysql/manifests/8.0.30":
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.Header().Set("WWW-Authenticate",
+					fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:apecloud/mysql:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set(digestHeader, digest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := &RegistryDigestResolver{Client: server.Client()}
+	registry := strings.TrimPrefix(server.URL, "https://")
+	resolved, err := resolver.Resolve(context.Background(), registry+"/apecloud/mysql:8.0.30", nil)
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if resolved != digest {
+		t.Errorf("expected digest %s, got %s", digest, resolved)
+	}
+	if _, err = resolver.Resolve(context.Background(), registry+"/apecloud/mysql:unknown", nil); err == nil {
+		t.Errorf("expected error for the unknown tag")
+	}
+}
+
+func TestRegistryDigestResolverWithCredentials(t *testing.T) {
+	const (
+		digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		token  = "private-token"
+	)
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if user, passwd, ok := r.BasicAuth(); !ok || user != "user" || passwd != "passwd" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"access_token": %q}`, token)
+		case "/v2/private/mysql/manifests/8.0.30":
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.Header().Set("WWW-Authenticate",
+					fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:private/mysql:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set(digestHeader, digest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := &RegistryDigestResolver{Client: server.Client()}
+	registry := strings.TrimPrefix(server.URL, "https://")
+	image := registry + "/private/mysql:8.0.30"
+	if _, err := resolver.Resolve(context.Background(), image, nil); err == nil {
+		t.Errorf("expected error for the private image without credentials")
+	}
+	secrets := []corev1.Secret{
+		{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths":{"https://%s":{"username":"user","password":"passwd"}}}`, registry)),
+			},
+		},
+	}
+	resolved, err := resolver.Resolve(context.Background(), image, ParseImagePullSecrets(secrets))
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if resolved != digest {
+		t.Errorf("expected digest %s, got %s", digest, resolved)
+	}
+}
+
+func TestParseImagePullSecrets(t *testing.T) {
+	secrets := []corev1.Secret{
+		{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"dXNlcjpwYXNzd2Q="}}}`),
+			},
+		},
+		{
+			Type: corev1.SecretTypeDockercfg,
+			Data: map[string][]byte{
+				corev1.DockerConfigKey: []byte(`{"registry.example.com":{"username":"foo","password":"bar"},"docker.io":{"username":"shadowed"}}`),
+			},
+		},
+		{
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{"password": []byte("ignored")},
+		},
+	}
+	creds := ParseImagePullSecrets(secrets)
+	if auth, ok := creds.Get("docker.io"); !ok || auth.Username != "user" || auth.Password != "passwd" {
+		t.Errorf("unexpected credential of docker.io: %v", auth)
+	}
+	if auth, ok := creds.Get("registry.example.com"); !ok || auth.Username != "foo" || auth.Password != "bar" {
+		t.Errorf("unexpected credential of registry.example.com: %v", auth)
+	}
+	if _, ok := creds.Get("quay.io"); ok {
+		t.Errorf("unexpected credential of quay.io")
+	}
+}
+
+// countingDigestResolver counts the requests, and fails to resolve if err is set.
+type countingDigestResolver struct {
+	requests int
+	err      error
+}
+
+func (r *countingDigestResolver) Resolve(_ context.Context, _ string, _ RegistryCredentials) (string, error) {
+	r.requests++
+	if r.err != nil {
+		return "", r.err
+	}
+	return "sha256:abc", nil
+}
+
+func TestCachedDigestResolver(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	inner := &countingDigestResolver{err: fmt.Errorf("registry unavailable")}
+	resolver := &CachedDigestResolver{
+		Resolver: inner,
+		TTL:      time.Minute,
+		Backoff:  flowcontrol.NewFakeBackOff(10*time.Second, time.Minute, fakeClock),
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := resolver.Resolve(ctx, "mysql", nil); err == nil {
+			t.Fatalf("expected error while the registry is unavailable")
+		}
+	}
+	if inner.requests != 1 {
+		t.Errorf("expected the registry requested once while backing off, got %d", inner.requests)
+	}
+
+	inner.err = nil
+	fakeClock.Step(11 * time.Second)
+	for i := 0; i < 3; i++ {
+		if digest, err := resolver.Resolve(ctx, "mysql", nil); err != nil || digest != "sha256:abc" {
+			t.Fatalf("unexpected digest %s, err: %v", digest, err)
+		}
+	}
+	if inner.requests != 2 {
+		t.Errorf("expected the resolved digest cached, got %d requests", inner.requests)
+	}
+
+	fakeClock.Step(2 * time.Minute)
+	if _, err := resolver.Resolve(ctx, "mysql", nil); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if inner.requests != 3 {
+		t.Errorf("expected the digest resolved again after the ttl, got %d requests", inner.requests)
+	}
+}
@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package component
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -29,6 +30,13 @@ func buildMonitorConfig(
 	clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
 	clusterCompSpec *appsv1alpha1.ClusterComponentSpec,
 	component *SynthesizedComponent) {
+	component.Monitor = GetMonitorConfig(clusterCompDef, clusterCompSpec)
+}
+
+// GetMonitorConfig resolves the monitor config of the component, the scrape port of the exporter declared by
+// the port name is resolved to the container port.
+func GetMonitorConfig(clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
+	clusterCompSpec *appsv1alpha1.ClusterComponentSpec) *MonitorConfig {
 	monitorEnable := false
 	if clusterCompSpec != nil {
 		monitorEnable = clusterCompSpec.Monitor
@@ -36,45 +44,73 @@ func buildMonitorConfig(
 
 	monitorConfig := clusterCompDef.Monitor
 	if !monitorEnable || monitorConfig == nil {
-		disableMonitor(component)
-		return
+		return disabledMonitorConfig()
 	}
 
 	if !monitorConfig.BuiltIn {
 		if monitorConfig.Exporter == nil {
-			disableMonitor(component)
-			return
+			return disabledMonitorConfig()
 		}
-		component.Monitor = &MonitorConfig{
+		config := &MonitorConfig{
 			Enable:     true,
 			BuiltIn:    false,
 			ScrapePath: monitorConfig.Exporter.ScrapePath,
 			ScrapePort: monitorConfig.Exporter.ScrapePort.IntVal,
 		}
 
-		if monitorConfig.Exporter.ScrapePort.Type == intstr.String {
+		if monitorConfig.Exporter.ScrapePort.Type == intstr.String && clusterCompDef.PodSpec != nil {
 			portName := monitorConfig.Exporter.ScrapePort.StrVal
 			for _, c := range clusterCompDef.PodSpec.Containers {
 				for _, p := range c.Ports {
 					if p.Name == portName {
-						component.Monitor.ScrapePort = p.ContainerPort
+						config.ScrapePort = p.ContainerPort
 						break
 					}
 				}
 			}
 		}
-		return
+		return config
 	}
 
-	component.Monitor = &MonitorConfig{
+	return &MonitorConfig{
 		Enable:  true,
 		BuiltIn: true,
 	}
 }
 
 func disableMonitor(component *SynthesizedComponent) {
-	component.Monitor = &MonitorConfig{
+	component.Monitor = disabledMonitorConfig()
+}
+
+func disabledMonitorConfig() *MonitorConfig {
+	return &MonitorConfig{
 		Enable:  false,
 		BuiltIn: false,
 	}
 }
+
+// omitExporterContainers removes the exporter sidecars, which expose the scrape port of the exporter,
+// the first container is kept as it's the main container of the component.
+func omitExporterContainers(clusterCompDef *appsv1alpha1.ClusterComponentDefinition, component *SynthesizedComponent) {
+	if clusterCompDef.Monitor == nil || clusterCompDef.Monitor.BuiltIn || clusterCompDef.Monitor.Exporter == nil {
+		return
+	}
+	scrapePort := clusterCompDef.Monitor.Exporter.ScrapePort
+	isExporter := func(container corev1.Container) bool {
+		for _, port := range container.Ports {
+			if (scrapePort.Type == intstr.String && port.Name == scrapePort.StrVal) ||
+				(scrapePort.Type == intstr.Int && port.ContainerPort == scrapePort.IntVal) {
+				return true
+			}
+		}
+		return false
+	}
+	containers := component.PodSpec.Containers[:0]
+	for i, container := range component.PodSpec.Containers {
+		if i > 0 && isExporter(container) {
+			continue
+		}
+		containers = append(containers, container)
+	}
+	component.PodSpec.Containers = containers
+}
@@ -0,0 +1,192 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+const (
+	// DefaultSpecHistoryLimit is the default number of the latest spec changes kept in the history.
+	DefaultSpecHistoryLimit = 10
+
+	// maxSpecHistoryValueLen is the max length of a value recorded in the history, the larger ones are redacted.
+	maxSpecHistoryValueLen = 64
+
+	specHistoryNoneValue    = "<none>"
+	specHistoryChangedValue = "<changed>"
+)
+
+// sensitiveSpecFields are the field name fragments whose values are redacted in the history.
+var sensitiveSpecFields = []string{"secret", "password", "credential"}
+
+// SpecHistoryEntry records a spec change of the cluster.
+type SpecHistoryEntry struct {
+	Timestamp  metav1.Time `json:"timestamp"`
+	Generation int64       `json:"generation"`
+	Changes    []string    `json:"changes,omitempty"`
+}
+
+// DiffClusterSpec summarizes the field-level changes from the old spec to the new one,
+// e.g. "componentSpecs[mysql].replicas: 3 -> 5".
+func DiffClusterSpec(oldSpec, newSpec *appsv1alpha1.ClusterSpec) ([]string, error) {
+	oldFields, err := flattenSpec(oldSpec)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenSpec(newSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(newFields))
+	for path := range oldFields {
+		paths = append(paths, path)
+	}
+	for path := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []string
+	for _, path := range paths {
+		oldValue, ok := oldFields[path]
+		if !ok {
+			oldValue = specHistoryNoneValue
+		}
+		newValue, ok := newFields[path]
+		if !ok {
+			newValue = specHistoryNoneValue
+		}
+		if oldValue == newValue {
+			continue
+		}
+		if isSensitiveSpecField(path) || len(oldValue) > maxSpecHistoryValueLen || len(newValue) > maxSpecHistoryValueLen {
+			changes = append(changes, fmt.Sprintf("%s: %s", path, specHistoryChangedValue))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", path, oldValue, newValue))
+	}
+	return changes, nil
+}
+
+// AppendSpecHistory appends the entry to the history, and prunes the oldest ones to keep at most @limit entries.
+func AppendSpecHistory(history []SpecHistoryEntry, entry SpecHistoryEntry, limit int) []SpecHistoryEntry {
+	history = append(history, entry)
+	if limit > 0 && len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return history
+}
+
+// flattenSpec flattens the spec into a map from the field paths to the values,
+// the elements of lists are keyed by their names if they have.
+func flattenSpec(spec *appsv1alpha1.ClusterSpec) (map[string]string, error) {
+	fields := make(map[string]string)
+	if spec == nil {
+		return fields, nil
+	}
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err = json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	if err = flattenSpecValue("", obj, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flattenSpecValue(path string, value interface{}, fields map[string]string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			subPath := key
+			if len(path) > 0 {
+				subPath = path + "." + key
+			}
+			if err := flattenSpecValue(subPath, item, fields); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if !isNamedList(v) {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			fields[path] = string(b)
+			return nil
+		}
+		for _, item := range v {
+			name := item.(map[string]interface{})["name"].(string)
+			if err := flattenSpecValue(fmt.Sprintf("%s[%s]", path, name), item, fields); err != nil {
+				return err
+			}
+		}
+	case float64:
+		fields[path] = strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		// the null value is treated as absent.
+	default:
+		fields[path] = fmt.Sprintf("%v", v)
+	}
+	return nil
+}
+
+// isNamedList checks whether all the elements of the list are objects with a name.
+func isNamedList(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok = obj["name"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isSensitiveSpecField(path string) bool {
+	lowerPath := strings.ToLower(path)
+	for _, field := range sensitiveSpecFields {
+		if strings.Contains(lowerPath, field) {
+			return true
+		}
+	}
+	return false
+}
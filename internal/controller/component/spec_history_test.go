@@ -0,0 +1,125 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+var _ = Describe("Spec History Tests", func() {
+	newSpec := func() *appsv1alpha1.ClusterSpec {
+		return &appsv1alpha1.ClusterSpec{
+			ClusterDefRef:     "mysql",
+			TerminationPolicy: appsv1alpha1.Delete,
+			ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{
+				{
+					Name:            "mysql",
+					ComponentDefRef: "mysql",
+					Replicas:        3,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+				},
+				{
+					Name:            "proxy",
+					ComponentDefRef: "proxy",
+					Replicas:        1,
+				},
+			},
+		}
+	}
+
+	Context("diff cluster spec", func() {
+		It("summarizes the field-level changes", func() {
+			oldSpec := newSpec()
+			spec := newSpec()
+			spec.ComponentSpecs[0].Replicas = 5
+			spec.ComponentSpecs[0].Resources.Requests[corev1.ResourceMemory] = resource.MustParse("8Gi")
+			spec.TerminationPolicy = appsv1alpha1.WipeOut
+			changes, err := DiffClusterSpec(oldSpec, spec)
+			Expect(err).Should(Succeed())
+			Expect(changes).Should(Equal([]string{
+				"componentSpecs[mysql].replicas: 3 -> 5",
+				"componentSpecs[mysql].resources.requests.memory: 4Gi -> 8Gi",
+				"terminationPolicy: Delete -> WipeOut",
+			}))
+		})
+
+		It("records the added and removed fields", func() {
+			oldSpec := newSpec()
+			spec := newSpec()
+			spec.ComponentSpecs = spec.ComponentSpecs[:1]
+			spec.ComponentSpecs[0].ServiceAccountName = "mysql-sa"
+			changes, err := DiffClusterSpec(oldSpec, spec)
+			Expect(err).Should(Succeed())
+			Expect(changes).Should(ContainElements(
+				"componentSpecs[mysql].serviceAccountName: <none> -> mysql-sa",
+				"componentSpecs[proxy].replicas: 1 -> <none>",
+			))
+		})
+
+		It("redacts the secrets and large blobs", func() {
+			oldSpec := newSpec()
+			spec := newSpec()
+			spec.ComponentSpecs[0].Issuer = &appsv1alpha1.Issuer{
+				Name: appsv1alpha1.IssuerUserProvided,
+				SecretRef: &appsv1alpha1.TLSSecretRef{
+					Name: "tls-secret",
+				},
+			}
+			spec.ComponentSpecs[1].ServiceAccountName = fmt.Sprintf("%0100d", 0)
+			changes, err := DiffClusterSpec(oldSpec, spec)
+			Expect(err).Should(Succeed())
+			Expect(changes).Should(ContainElements(
+				"componentSpecs[mysql].issuer.secretRef.name: <changed>",
+				"componentSpecs[proxy].serviceAccountName: <changed>",
+			))
+			for _, change := range changes {
+				Expect(change).ShouldNot(ContainSubstring("tls-secret"))
+			}
+		})
+
+		It("has no changes for the same spec", func() {
+			changes, err := DiffClusterSpec(newSpec(), newSpec())
+			Expect(err).Should(Succeed())
+			Expect(changes).Should(BeEmpty())
+		})
+	})
+
+	Context("append spec history", func() {
+		It("keeps the latest entries only", func() {
+			var history []SpecHistoryEntry
+			for i := 1; i <= 5; i++ {
+				history = AppendSpecHistory(history, SpecHistoryEntry{Generation: int64(i)}, 3)
+			}
+			Expect(history).Should(HaveLen(3))
+			Expect(history[0].Generation).Should(BeEquivalentTo(3))
+			Expect(history[2].Generation).Should(BeEquivalentTo(5))
+		})
+	})
+})
@@ -34,41 +34,56 @@ type MonitorConfig struct {
 }
 
 type SynthesizedComponent struct {
-	ClusterDefName        string                                 `json:"clusterDefName,omitempty"`
-	ClusterName           string                                 `json:"clusterName,omitempty"`
-	ClusterUID            string                                 `json:"clusterUID,omitempty"`
-	Name                  string                                 `json:"name,omitempty"`
-	CompDefName           string                                 `json:"compDefName,omitempty"`
-	CharacterType         string                                 `json:"characterType,omitempty"`
-	MinAvailable          *intstr.IntOrString                    `json:"minAvailable,omitempty"`
-	Replicas              int32                                  `json:"replicas"`
-	WorkloadType          v1alpha1.WorkloadType                  `json:"workloadType,omitempty"`
-	StatelessSpec         *v1alpha1.StatelessSetSpec             `json:"statelessSpec,omitempty"`
-	StatefulSpec          *v1alpha1.StatefulSetSpec              `json:"statefulSpec,omitempty"`
-	ConsensusSpec         *v1alpha1.ConsensusSetSpec             `json:"consensusSpec,omitempty"`
-	ReplicationSpec       *v1alpha1.ReplicationSetSpec           `json:"replicationSpec,omitempty"`
-	RSMSpec               *v1alpha1.RSMSpec                      `json:"rsmSpec,omitempty"`
-	PodSpec               *corev1.PodSpec                        `json:"podSpec,omitempty"`
-	Services              []corev1.Service                       `json:"services,omitempty"`
-	Probes                *v1alpha1.ClusterDefinitionProbes      `json:"probes,omitempty"`
-	VolumeClaimTemplates  []corev1.PersistentVolumeClaimTemplate `json:"volumeClaimTemplates,omitempty"`
-	Monitor               *MonitorConfig                         `json:"monitor,omitempty"`
-	EnabledLogs           []string                               `json:"enabledLogs,omitempty"`
-	LogConfigs            []v1alpha1.LogConfig                   `json:"logConfigs,omitempty"`
-	ConfigTemplates       []v1alpha1.ComponentConfigSpec         `json:"configTemplates,omitempty"`
-	ScriptTemplates       []v1alpha1.ComponentTemplateSpec       `json:"scriptTemplates,omitempty"`
-	HorizontalScalePolicy *v1alpha1.HorizontalScalePolicy        `json:"horizontalScalePolicy,omitempty"`
-	TLS                   bool                                   `json:"tls"`
-	Issuer                *v1alpha1.Issuer                       `json:"issuer,omitempty"`
-	VolumeTypes           []v1alpha1.VolumeTypeSpec              `json:"volumeTypes,omitempty"`
-	VolumeProtection      *v1alpha1.VolumeProtectionSpec         `json:"volumeProtection,omitempty"`
-	CustomLabelSpecs      []v1alpha1.CustomLabelSpec             `json:"customLabelSpecs,omitempty"`
-	SwitchoverSpec        *v1alpha1.SwitchoverSpec               `json:"switchoverSpec,omitempty"`
-	ComponentDef          string                                 `json:"componentDef,omitempty"`
-	ServiceAccountName    string                                 `json:"serviceAccountName,omitempty"`
-	StatefulSetWorkload   v1alpha1.StatefulSetWorkload           `json:"statefulSetWorkload,omitempty"`
-	ComponentRefEnvs      []*corev1.EnvVar                       `json:"componentRefEnvs,omitempty"`
-	ServiceReferences     map[string]*v1alpha1.ServiceDescriptor `json:"serviceReferences,omitempty"`
+	ClusterDefName             string                                  `json:"clusterDefName,omitempty"`
+	ClusterName                string                                  `json:"clusterName,omitempty"`
+	ClusterUID                 string                                  `json:"clusterUID,omitempty"`
+	Name                       string                                  `json:"name,omitempty"`
+	CompDefName                string                                  `json:"compDefName,omitempty"`
+	CharacterType              string                                  `json:"characterType,omitempty"`
+	MinAvailable               *intstr.IntOrString                     `json:"minAvailable,omitempty"`
+	MinReadySeconds            int32                                   `json:"minReadySeconds,omitempty"`
+	Replicas                   int32                                   `json:"replicas"`
+	OrdinalStart               int32                                   `json:"ordinalStart,omitempty"`
+	PodNamePrefix              string                                  `json:"podNamePrefix,omitempty"`
+	WorkloadType               v1alpha1.WorkloadType                   `json:"workloadType,omitempty"`
+	StatelessSpec              *v1alpha1.StatelessSetSpec              `json:"statelessSpec,omitempty"`
+	StatefulSpec               *v1alpha1.StatefulSetSpec               `json:"statefulSpec,omitempty"`
+	ConsensusSpec              *v1alpha1.ConsensusSetSpec              `json:"consensusSpec,omitempty"`
+	ReplicationSpec            *v1alpha1.ReplicationSetSpec            `json:"replicationSpec,omitempty"`
+	RSMSpec                    *v1alpha1.RSMSpec                       `json:"rsmSpec,omitempty"`
+	PodSpec                    *corev1.PodSpec                         `json:"podSpec,omitempty"`
+	Services                   []corev1.Service                        `json:"services,omitempty"`
+	Probes                     *v1alpha1.ClusterDefinitionProbes       `json:"probes,omitempty"`
+	VolumeClaimTemplates       []corev1.PersistentVolumeClaimTemplate  `json:"volumeClaimTemplates,omitempty"`
+	VolumeClaimRetentionPolicy v1alpha1.VolumeClaimRetentionPolicyType `json:"volumeClaimRetentionPolicy,omitempty"`
+	Monitor                    *MonitorConfig                          `json:"monitor,omitempty"`
+	LightweightMode            bool                                    `json:"lightweightMode,omitempty"`
+	EvictionProtection         *v1alpha1.EvictionProtection            `json:"evictionProtection,omitempty"`
+	InjectCPULimitEnv          bool                                    `json:"injectCPULimitEnv,omitempty"`
+	InitScriptConfigMap        string                                  `json:"initScriptConfigMap,omitempty"`
+	InitScriptMountPath        string                                  `json:"initScriptMountPath,omitempty"`
+	InitScriptContainers       []string                                `json:"initScriptContainers,omitempty"`
+	EnabledLogs                []string                                `json:"enabledLogs,omitempty"`
+	LogConfigs                 []v1alpha1.LogConfig                    `json:"logConfigs,omitempty"`
+	ConfigTemplates            []v1alpha1.ComponentConfigSpec          `json:"configTemplates,omitempty"`
+	ScriptTemplates            []v1alpha1.ComponentTemplateSpec        `json:"scriptTemplates,omitempty"`
+	HorizontalScalePolicy      *v1alpha1.HorizontalScalePolicy         `json:"horizontalScalePolicy,omitempty"`
+	TLS                        bool                                    `json:"tls"`
+	Issuer                     *v1alpha1.Issuer                        `json:"issuer,omitempty"`
+	VolumeTypes                []v1alpha1.VolumeTypeSpec               `json:"volumeTypes,omitempty"`
+	VolumeProtection           *v1alpha1.VolumeProtectionSpec          `json:"volumeProtection,omitempty"`
+	CustomLabelSpecs           []v1alpha1.CustomLabelSpec              `json:"customLabelSpecs,omitempty"`
+	SwitchoverSpec             *v1alpha1.SwitchoverSpec                `json:"switchoverSpec,omitempty"`
+	ComponentDef               string                                  `json:"componentDef,omitempty"`
+	ServiceAccountName         string                                  `json:"serviceAccountName,omitempty"`
+	SchedulerName              string                                  `json:"schedulerName,omitempty"`
+	PriorityClassName          string                                  `json:"priorityClassName,omitempty"`
+	DNSSearchDomains           []string                                `json:"dnsSearchDomains,omitempty"`
+	WorkloadAnnotations        map[string]string                       `json:"workloadAnnotations,omitempty"`
+	StatefulSetWorkload        v1alpha1.StatefulSetWorkload            `json:"statefulSetWorkload,omitempty"`
+	ComponentRefEnvs           []*corev1.EnvVar                        `json:"componentRefEnvs,omitempty"`
+	ServiceReferences          map[string]*v1alpha1.ServiceDescriptor  `json:"serviceReferences,omitempty"`
+	BackendDiscovery           *v1alpha1.BackendDiscovery              `json:"backendDiscovery,omitempty"`
 }
 
 type CloudProvider string
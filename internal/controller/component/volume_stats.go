@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	volumeStatsUsedBytesMetric     = "kubelet_volume_stats_used_bytes"
+	volumeStatsCapacityBytesMetric = "kubelet_volume_stats_capacity_bytes"
+)
+
+// VolumeUsage is the usage of a PVC volume.
+type VolumeUsage struct {
+	UsedBytes     int64
+	CapacityBytes int64
+}
+
+// Utilization returns the used percentage of the volume, 0 if the capacity is unknown.
+func (u VolumeUsage) Utilization() int {
+	if u.CapacityBytes <= 0 {
+		return 0
+	}
+	return int(u.UsedBytes * 100 / u.CapacityBytes)
+}
+
+// VolumeStatsProvider provides the usages of the PVC volumes.
+type VolumeStatsProvider interface {
+	GetPVCUsage(pvc types.NamespacedName) (VolumeUsage, bool)
+}
+
+// PrometheusVolumeStatsProvider collects the usages of the PVC volumes from the kubelet volume stats metrics scraped
+// by Prometheus. The metrics of all the PVCs are queried at once every interval in the background, and the usages
+// are served from the cache, so the reconciliations never wait for the collection.
+type PrometheusVolumeStatsProvider struct {
+	api      promv1.API
+	interval time.Duration
+
+	mu     sync.RWMutex
+	usages map[types.NamespacedName]VolumeUsage
+}
+
+var _ VolumeStatsProvider = &PrometheusVolumeStatsProvider{}
+var _ manager.Runnable = &PrometheusVolumeStatsProvider{}
+
+// NewPrometheusVolumeStatsProvider creates a PrometheusVolumeStatsProvider querying the Prometheus at address every interval.
+func NewPrometheusVolumeStatsProvider(address string, interval time.Duration) (*PrometheusVolumeStatsProvider, error) {
+	cli, err := promapi.NewClient(promapi.Config{Address: address})
+	if err != nil {
+		return nil, err
+	}
+	return &PrometheusVolumeStatsProvider{
+		api:      promv1.NewAPI(cli),
+		interval: interval,
+		usages:   map[types.NamespacedName]VolumeUsage{},
+	}, nil
+}
+
+func (p *PrometheusVolumeStatsProvider) GetPVCUsage(pvc types.NamespacedName) (VolumeUsage, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	usage, ok := p.usages[pvc]
+	return usage, ok
+}
+
+// Start collects the usages every interval until the context is done.
+func (p *PrometheusVolumeStatsProvider) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("volume-stats-collector")
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := p.collect(ctx); err != nil {
+			// keep serving the last collected usages, they are refreshed next time.
+			logger.Error(err, "failed to collect the volume stats")
+		}
+	}, p.interval)
+	return nil
+}
+
+func (p *PrometheusVolumeStatsProvider) collect(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.interval)
+	defer cancel()
+	usedBytes, err := p.query(ctx, volumeStatsUsedBytesMetric)
+	if err != nil {
+		return err
+	}
+	capacityBytes, err := p.query(ctx, volumeStatsCapacityBytesMetric)
+	if err != nil {
+		return err
+	}
+	usages := make(map[types.NamespacedName]VolumeUsage, len(usedBytes))
+	for pvc, used := range usedBytes {
+		if capacity, ok := capacityBytes[pvc]; ok {
+			usages[pvc] = VolumeUsage{UsedBytes: used, CapacityBytes: capacity}
+		}
+	}
+
+	p.mu.Lock()
+	p.usages = usages
+	p.mu.Unlock()
+	return nil
+}
+
+// query queries the latest values of the metric by PVC, the max is taken if the PVC is reported by multiple series,
+// e.g. the kubelet is scraped by multiple jobs.
+func (p *PrometheusVolumeStatsProvider) query(ctx context.Context, metric string) (map[types.NamespacedName]int64, error) {
+	result, _, err := p.api.Query(ctx, fmt.Sprintf("max by (namespace, persistentvolumeclaim) (%s)", metric), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s of the metric %s", result.Type(), metric)
+	}
+	values := make(map[types.NamespacedName]int64, len(vector))
+	for _, sample := range vector {
+		pvc := types.NamespacedName{
+			Namespace: string(sample.Metric["namespace"]),
+			Name:      string(sample.Metric["persistentvolumeclaim"]),
+		}
+		values[pvc] = int64(sample.Value)
+	}
+	return values, nil
+}
@@ -83,6 +83,18 @@ func updateResourceAnnotationsWithTemplate(obj client.Object, allTemplateAnnotat
 	obj.SetAnnotations(annotations)
 }
 
+// getReloadConfigSpecs gets the config specs reloaded by the configmgr sidecar, including the one of the backends
+// listed for the component declaring backendDiscovery.
+func getReloadConfigSpecs(cluster *appsv1alpha1.Cluster, comp *component.SynthesizedComponent) []appsv1alpha1.ComponentConfigSpec {
+	backendsConfigSpec := component.BuildBackendsConfigSpec(cluster.Name, comp)
+	if backendsConfigSpec == nil {
+		return comp.ConfigTemplates
+	}
+	configSpecs := make([]appsv1alpha1.ComponentConfigSpec, 0, len(comp.ConfigTemplates)+1)
+	configSpecs = append(configSpecs, comp.ConfigTemplates...)
+	return append(configSpecs, *backendsConfigSpec)
+}
+
 // buildConfigManagerWithComponent build the configmgr sidecar container and update it
 // into PodSpec if configuration reload option is on
 func buildConfigManagerWithComponent(podSpec *corev1.PodSpec, configSpecs []appsv1alpha1.ComponentConfigSpec,
@@ -59,7 +59,15 @@ func (c *configOperator) Reconcile() error {
 	// Need to Merge configTemplateRef of ClusterVersion.Components[*].ConfigTemplateRefs and
 	// ClusterDefinition.Components[*].ConfigTemplateRefs
 	if len(component.ConfigTemplates) == 0 && len(component.ScriptTemplates) == 0 {
-		return c.UpdateConfiguration()
+		if len(getReloadConfigSpecs(c.Cluster, component)) == 0 {
+			return c.UpdateConfiguration()
+		}
+		// only the backends listed for the component declaring backendDiscovery are reloaded by the sidecar.
+		return NewCreatePipeline(c.ReconcileCtx).
+			UpdateConfiguration().
+			BuildConfigManagerSidecar().
+			UpdateConfigRelatedObject().
+			Complete()
 	}
 
 	return NewCreatePipeline(c.ReconcileCtx).
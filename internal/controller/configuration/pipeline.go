@@ -20,10 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package configuration
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -50,6 +54,8 @@ type ReconcileCtx struct {
 type pipeline struct {
 	// configuration *appsv1alpha1.Configuration
 	renderWrapper renderWrapper
+	// the config items waiting for a reconfiguring operation to apply the new config templates
+	pendingUpgrades []string
 
 	ctx ReconcileCtx
 	intctrlutil.ResourceFetcher[pipeline]
@@ -57,6 +63,7 @@ type pipeline struct {
 
 type updatePipeline struct {
 	reconcile     bool
+	forceRerender bool
 	renderWrapper renderWrapper
 
 	item       appsv1alpha1.ConfigurationItemDetail
@@ -149,6 +156,7 @@ func (p *pipeline) UpdateConfigurationStatus() *pipeline {
 		for _, item := range existing.Spec.ConfigItemDetails {
 			checkAndUpdateItemStatus(updated, item, reversion)
 		}
+		syncPendingConfigUpgradeCondition(updated, p.pendingUpgrades)
 		return p.ResourceFetcher.Client.Status().Patch(p.Context, updated, patch)
 	})
 }
@@ -186,7 +194,7 @@ func (p *pipeline) UpdatePodVolumes() *pipeline {
 
 func (p *pipeline) BuildConfigManagerSidecar() *pipeline {
 	return p.Wrap(func() error {
-		return buildConfigManagerWithComponent(p.ctx.PodSpec, p.ctx.Component.ConfigTemplates, p.Context, p.Client, p.ctx.Cluster, p.ctx.Component)
+		return buildConfigManagerWithComponent(p.ctx.PodSpec, getReloadConfigSpecs(p.ctx.Cluster, p.ctx.Component), p.Context, p.Client, p.ctx.Cluster, p.ctx.Component)
 	})
 }
 
@@ -243,19 +251,47 @@ func (p *pipeline) updateConfiguration(expected *appsv1alpha1.Configuration, exi
 		}
 	}
 
+	// apply the config templates changed by the ClusterVersion following the upgrade policy of the cluster
+	policy := intctrlutil.GetConfigTemplateUpgradePolicy(p.ctx.Cluster)
+	p.pendingUpgrades = nil
+	for i := range newConfigItems {
+		item := &newConfigItems[i]
+		expectedItem := expected.Spec.GetConfigurationItem(item.Name)
+		if expectedItem != nil && upgradeConfigurationItem(item, expectedItem.ConfigSpec, policy) {
+			p.pendingUpgrades = append(p.pendingUpgrades, item.Name)
+		}
+	}
+
 	patch := client.MergeFrom(existing)
 	updated := existing.DeepCopy()
 	updated.Spec.ConfigItemDetails = newConfigItems
 	return p.Client.Patch(p.Context, updated, patch)
 }
 
+// syncPendingConfigUpgradeCondition sets the PendingConfigUpgrade condition if any config template changed by the
+// ClusterVersion waits for a reconfiguring operation, or removes it.
+func syncPendingConfigUpgradeCondition(configuration *appsv1alpha1.Configuration, pendingUpgrades []string) {
+	if len(pendingUpgrades) == 0 {
+		meta.RemoveStatusCondition(&configuration.Status.Conditions, appsv1alpha1.ConditionTypePendingConfigUpgrade)
+		return
+	}
+	meta.SetStatusCondition(&configuration.Status.Conditions, metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypePendingConfigUpgrade,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: configuration.Generation,
+		Reason:             appsv1alpha1.ReasonConfigUpgradePending,
+		Message: fmt.Sprintf("the config templates [%s] are changed by the ClusterVersion, and wait for a reconfiguring operation to apply",
+			strings.Join(pendingUpgrades, ",")),
+	})
+}
+
 func (p *updatePipeline) isDone() bool {
 	return !p.reconcile
 }
 
 func (p *updatePipeline) PrepareForTemplate() *updatePipeline {
 	buildTemplate := func() (err error) {
-		p.reconcile = !intctrlutil.IsApplyConfigChanged(p.ConfigMapObj, p.item)
+		p.reconcile = p.forceRerender || !intctrlutil.IsApplyConfigChanged(p.ConfigMapObj, p.item)
 		if p.isDone() {
 			return
 		}
@@ -270,6 +306,15 @@ func (p *updatePipeline) PrepareForTemplate() *updatePipeline {
 	return p.Wrap(buildTemplate)
 }
 
+// ForceRerender renders the config template again even if the applied version is not changed,
+// it's used to overwrite the out-of-band changes of the ConfigMap.
+func (p *updatePipeline) ForceRerender() *updatePipeline {
+	return p.Wrap(func() error {
+		p.forceRerender = true
+		return nil
+	})
+}
+
 func (p *updatePipeline) ConfigSpec() *appsv1alpha1.ComponentConfigSpec {
 	return p.configSpec
 }
@@ -291,9 +336,32 @@ func (p *updatePipeline) RerenderTemplate() *updatePipeline {
 		if p.isDone() {
 			return
 		}
-		if intctrlutil.IsRerender(p.ConfigMapObj, p.item) {
+		switch {
+		case p.forceRerender && p.ConfigMapObj != nil:
+			var renderedCM *corev1.ConfigMap
+			if renderedCM, err = p.renderWrapper.rerenderConfigTemplate(p.ctx.Cluster, p.ctx.Component, *p.configSpec, &p.item); err != nil {
+				return
+			}
+			// keep the metadata of the running ConfigMap, only the data is overwritten.
+			p.newCM = p.ConfigMapObj.DeepCopy()
+			p.newCM.Data = renderedCM.Data
+		case isConfigTemplateUpgraded(p.ConfigMapObj, p.configSpec):
+			var renderedCM *corev1.ConfigMap
+			if renderedCM, err = p.renderWrapper.rerenderConfigTemplate(p.ctx.Cluster, p.ctx.Component, *p.configSpec, &p.item); err != nil {
+				return
+			}
+			tpl := &corev1.ConfigMap{}
+			if err = p.Client.Get(p.Context, client.ObjectKey{Namespace: p.configSpec.Namespace, Name: p.configSpec.TemplateRef}, tpl); err != nil {
+				return
+			}
+			// keep the metadata of the running ConfigMap, the data is merged following the upgrade policy.
+			p.newCM = p.ConfigMapObj.DeepCopy()
+			UpdateCMConfigSpecLabels(p.newCM, *p.configSpec)
+			p.newCM.Data, err = upgradeConfigTemplate(intctrlutil.GetConfigTemplateUpgradePolicy(p.ctx.Cluster),
+				p.ConfigMapObj.Data, renderedCM.Data, getRenamedParameters(tpl), p.ConfigConstraintObj, *p.configSpec)
+		case intctrlutil.IsRerender(p.ConfigMapObj, p.item):
 			p.newCM, err = p.renderWrapper.rerenderConfigTemplate(p.ctx.Cluster, p.ctx.Component, *p.configSpec, &p.item)
-		} else {
+		default:
 			p.newCM = p.ConfigMapObj.DeepCopy()
 		}
 		return
@@ -0,0 +1,166 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configuration
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/configuration/core"
+	"github.com/apecloud/kubeblocks/internal/configuration/validate"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+)
+
+// isConfigTemplateChanged checks whether the config template of the configSpec is replaced, e.g. by a new ClusterVersion.
+func isConfigTemplateChanged(origSpec, newSpec *appsv1alpha1.ComponentConfigSpec) bool {
+	if origSpec == nil || newSpec == nil {
+		return false
+	}
+	return origSpec.TemplateRef != newSpec.TemplateRef
+}
+
+// isConfigTemplateUpgraded checks whether the ConfigMap is rendered from another config template than the configSpec's.
+func isConfigTemplateUpgraded(cm *corev1.ConfigMap, configSpec *appsv1alpha1.ComponentConfigSpec) bool {
+	if cm == nil || configSpec == nil {
+		return false
+	}
+	templateRef, ok := cm.Labels[constant.CMConfigurationTemplateNameLabelKey]
+	return ok && templateRef != configSpec.TemplateRef
+}
+
+// upgradeConfigurationItem updates the configSpec of the item to the new one following the upgrade policy,
+// it returns true if the upgrade is pending for a reconfiguring operation.
+func upgradeConfigurationItem(item *appsv1alpha1.ConfigurationItemDetail,
+	configSpec *appsv1alpha1.ComponentConfigSpec,
+	policy appsv1alpha1.ConfigTemplateUpgradePolicy) bool {
+	if !isConfigTemplateChanged(item.ConfigSpec, configSpec) {
+		return false
+	}
+	switch policy {
+	case appsv1alpha1.ManualConfigTemplateUpgradePolicy:
+		return true
+	case appsv1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy:
+		// the parameters set by the reconfiguring operations are dropped with the full re-rendering.
+		item.ConfigFileParams = nil
+	}
+	item.ConfigSpec = configSpec.DeepCopy()
+	return false
+}
+
+// getRenamedParameters gets the parameters renamed by the config template from its annotation,
+// the old name is mapped to the new one.
+func getRenamedParameters(tpl *corev1.ConfigMap) map[string]string {
+	if tpl == nil {
+		return nil
+	}
+	renames := make(map[string]string)
+	for _, pair := range strings.Split(tpl.Annotations[constant.ConfigTemplateRenamedParametersAnnotationKey], ",") {
+		oldName, newName, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || oldName == "" || newName == "" {
+			continue
+		}
+		renames[strings.TrimSpace(oldName)] = strings.TrimSpace(newName)
+	}
+	return renames
+}
+
+// upgradeConfigTemplate merges the config files rendered from the new config template into the running ones.
+// ReplaceWithNew takes the rendered files as they are, while the others keep the running parameters,
+// including the ones set by the reconfiguring operations, and only add the parameters introduced by the new template.
+// The running values of the parameters renamed by the new template are carried to the new names.
+func upgradeConfigTemplate(policy appsv1alpha1.ConfigTemplateUpgradePolicy,
+	runningData, renderedData map[string]string,
+	renames map[string]string,
+	cc *appsv1alpha1.ConfigConstraint,
+	configSpec appsv1alpha1.ComponentConfigSpec) (map[string]string, error) {
+	if policy == appsv1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy {
+		return renderedData, nil
+	}
+
+	upgradedData := make(map[string]string, len(runningData))
+	for key, value := range runningData {
+		upgradedData[key] = value
+	}
+	keySelector := validate.WithKeySelector(configSpec.Keys)
+	upgradedParams := make([]core.ParamPairs, 0, len(renderedData))
+	for key, rendered := range renderedData {
+		running, ok := runningData[key]
+		switch {
+		case !ok:
+			// the config file is introduced by the new template
+			upgradedData[key] = rendered
+		case cc == nil || cc.Spec.FormatterConfig == nil || !keySelector(key):
+			// the parameters can't be told apart without the format, keep the running file
+			continue
+		default:
+			params, err := getUpgradedParameters(key, running, rendered, renames, cc.Spec.FormatterConfig)
+			if err != nil {
+				return nil, err
+			}
+			if len(params) != 0 {
+				upgradedParams = append(upgradedParams, core.ParamPairs{Key: key, UpdatedParams: params})
+			}
+		}
+	}
+	if len(upgradedParams) == 0 {
+		return upgradedData, nil
+	}
+	return intctrlutil.MergeAndValidateConfigs(cc.Spec, upgradedData, configSpec.Keys, upgradedParams)
+}
+
+// getUpgradedParameters returns the parameters of the rendered config file which are absent in the running one,
+// a parameter renamed by the new template takes the running value of its old name, and the old name is removed.
+func getUpgradedParameters(key, running, rendered string,
+	renames map[string]string,
+	formatter *appsv1alpha1.FormatterConfig) (map[string]interface{}, error) {
+	runningParams, err := core.TransformConfigFileToKeyValueMap(key, formatter, []byte(running))
+	if err != nil {
+		return nil, err
+	}
+	renderedParams, err := core.TransformConfigFileToKeyValueMap(key, formatter, []byte(rendered))
+	if err != nil {
+		return nil, err
+	}
+	upgradedParams := make(map[string]interface{})
+	for param, value := range renderedParams {
+		if _, ok := runningParams[param]; !ok {
+			upgradedParams[param] = value
+		}
+	}
+	for oldName, newName := range renames {
+		oldValue, ok := runningParams[oldName]
+		if !ok {
+			continue
+		}
+		if _, ok := renderedParams[oldName]; ok {
+			// the old name is still in use by the new template
+			continue
+		}
+		if _, ok := runningParams[newName]; !ok {
+			upgradedParams[newName] = oldValue
+		}
+		// a nil value removes the parameter
+		upgradedParams[oldName] = nil
+	}
+	return upgradedParams, nil
+}
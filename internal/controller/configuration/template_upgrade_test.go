@@ -0,0 +1,209 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configuration
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	cfgcore "github.com/apecloud/kubeblocks/internal/configuration/core"
+	cfgutil "github.com/apecloud/kubeblocks/internal/configuration/util"
+	"github.com/apecloud/kubeblocks/internal/constant"
+)
+
+var _ = Describe("TemplateUpgradeTest", func() {
+	const (
+		testConfigFile  = "postgresql.conf"
+		oldTemplateName = "pg-config-template-v1"
+		newTemplateName = "pg-config-template-v2"
+	)
+
+	// the new template renames max_connections to max_user_connections, changes the default of shared_buffers,
+	// and introduces work_mem.
+	runningConfig := `
+max_connections=2000
+shared_buffers=128MB
+`
+	renderedConfig := `
+max_user_connections=1000
+shared_buffers=256MB
+work_mem=4MB
+`
+
+	renames := map[string]string{"max_connections": "max_user_connections"}
+
+	var (
+		configConstraint *appsv1alpha1.ConfigConstraint
+		oldConfigSpec    appsv1alpha1.ComponentConfigSpec
+		newConfigSpec    appsv1alpha1.ComponentConfigSpec
+		item             appsv1alpha1.ConfigurationItemDetail
+	)
+
+	parseParams := func(data map[string]string) map[string]string {
+		params, err := cfgcore.TransformConfigFileToKeyValueMap(testConfigFile, configConstraint.Spec.FormatterConfig, []byte(data[testConfigFile]))
+		Expect(err).Should(Succeed())
+		return params
+	}
+
+	BeforeEach(func() {
+		configConstraint = &appsv1alpha1.ConfigConstraint{
+			Spec: appsv1alpha1.ConfigConstraintSpec{
+				FormatterConfig: &appsv1alpha1.FormatterConfig{
+					Format: appsv1alpha1.Properties,
+				},
+			},
+		}
+		oldConfigSpec = appsv1alpha1.ComponentConfigSpec{
+			ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{
+				Name:        configSpecName,
+				TemplateRef: oldTemplateName,
+				Namespace:   "default",
+				VolumeName:  "pg-config",
+			},
+			Keys: []string{testConfigFile},
+		}
+		newConfigSpec = *oldConfigSpec.DeepCopy()
+		newConfigSpec.TemplateRef = newTemplateName
+		item = appsv1alpha1.ConfigurationItemDetail{
+			Name:       configSpecName,
+			ConfigSpec: oldConfigSpec.DeepCopy(),
+			ConfigFileParams: map[string]appsv1alpha1.ConfigParams{
+				testConfigFile: {
+					Parameters: map[string]*string{"max_connections": cfgutil.ToPointer("2000")},
+				},
+			},
+		}
+	})
+
+	Context("upgrade the configuration item", func() {
+		It("should keep the item unchanged if the template is not changed", func() {
+			expected := item.DeepCopy()
+			Expect(upgradeConfigurationItem(&item, &oldConfigSpec, appsv1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy)).Should(BeFalse())
+			Expect(item).Should(Equal(*expected))
+		})
+
+		It("should drop the parameters with ReplaceWithNew policy", func() {
+			Expect(upgradeConfigurationItem(&item, &newConfigSpec, appsv1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy)).Should(BeFalse())
+			Expect(item.ConfigSpec.TemplateRef).Should(Equal(newTemplateName))
+			Expect(item.ConfigFileParams).Should(BeEmpty())
+		})
+
+		It("should keep the parameters with UseExisting policy", func() {
+			Expect(upgradeConfigurationItem(&item, &newConfigSpec, appsv1alpha1.UseExistingConfigTemplateUpgradePolicy)).Should(BeFalse())
+			Expect(item.ConfigSpec.TemplateRef).Should(Equal(newTemplateName))
+			Expect(item.ConfigFileParams).Should(HaveKey(testConfigFile))
+		})
+
+		It("should wait for the reconfiguring operation with Manual policy", func() {
+			expected := item.DeepCopy()
+			Expect(upgradeConfigurationItem(&item, &newConfigSpec, appsv1alpha1.ManualConfigTemplateUpgradePolicy)).Should(BeTrue())
+			Expect(item).Should(Equal(*expected))
+
+			By("set the pending condition")
+			configuration := &appsv1alpha1.Configuration{}
+			syncPendingConfigUpgradeCondition(configuration, []string{item.Name})
+			Expect(configuration.Status.Conditions).Should(HaveLen(1))
+			Expect(configuration.Status.Conditions[0].Type).Should(Equal(appsv1alpha1.ConditionTypePendingConfigUpgrade))
+			Expect(configuration.Status.Conditions[0].Message).Should(ContainSubstring(item.Name))
+
+			By("remove the pending condition")
+			syncPendingConfigUpgradeCondition(configuration, nil)
+			Expect(configuration.Status.Conditions).Should(BeEmpty())
+		})
+	})
+
+	Context("upgrade the config template", func() {
+		var runningCM *corev1.ConfigMap
+
+		BeforeEach(func() {
+			runningCM = &corev1.ConfigMap{
+				Data: map[string]string{testConfigFile: runningConfig},
+			}
+			UpdateCMConfigSpecLabels(runningCM, oldConfigSpec)
+		})
+
+		It("should check whether the ConfigMap is rendered from the template", func() {
+			Expect(isConfigTemplateUpgraded(runningCM, &oldConfigSpec)).Should(BeFalse())
+			Expect(isConfigTemplateUpgraded(runningCM, &newConfigSpec)).Should(BeTrue())
+			delete(runningCM.Labels, constant.CMConfigurationTemplateNameLabelKey)
+			Expect(isConfigTemplateUpgraded(runningCM, &newConfigSpec)).Should(BeFalse())
+		})
+
+		It("should get the renamed parameters from the template", func() {
+			tpl := &corev1.ConfigMap{}
+			Expect(getRenamedParameters(tpl)).Should(BeEmpty())
+			tpl.Annotations = map[string]string{
+				constant.ConfigTemplateRenamedParametersAnnotationKey: "max_connections=max_user_connections, invalid,=empty",
+			}
+			Expect(getRenamedParameters(tpl)).Should(Equal(map[string]string{"max_connections": "max_user_connections"}))
+		})
+
+		It("should take the rendered files with ReplaceWithNew policy", func() {
+			renderedData := map[string]string{testConfigFile: renderedConfig}
+			upgradedData, err := upgradeConfigTemplate(appsv1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy,
+				runningCM.Data, renderedData, renames, configConstraint, newConfigSpec)
+			Expect(err).Should(Succeed())
+			Expect(upgradedData).Should(Equal(renderedData))
+		})
+
+		It("should keep the running parameters with UseExisting and Manual policy", func() {
+			for _, policy := range []appsv1alpha1.ConfigTemplateUpgradePolicy{
+				appsv1alpha1.UseExistingConfigTemplateUpgradePolicy,
+				// the Manual policy is applied by the reconfiguring operation in the same way
+				appsv1alpha1.ManualConfigTemplateUpgradePolicy,
+			} {
+				upgradedData, err := upgradeConfigTemplate(policy, runningCM.Data,
+					map[string]string{testConfigFile: renderedConfig, "extra.conf": "for test"}, renames, configConstraint, newConfigSpec)
+				Expect(err).Should(Succeed())
+				Expect(upgradedData).Should(HaveKeyWithValue("extra.conf", "for test"))
+				Expect(parseParams(upgradedData)).Should(Equal(map[string]string{
+					// the value set by the user is carried to the new name of the parameter
+					"max_user_connections": "2000",
+					// the parameter set by the user is kept
+					"shared_buffers": "128MB",
+					// the parameter introduced by the new template is added
+					"work_mem": "4MB",
+				}))
+			}
+		})
+
+		It("should keep the old parameter if the rename is not declared", func() {
+			upgradedData, err := upgradeConfigTemplate(appsv1alpha1.UseExistingConfigTemplateUpgradePolicy, runningCM.Data,
+				map[string]string{testConfigFile: renderedConfig}, nil, configConstraint, newConfigSpec)
+			Expect(err).Should(Succeed())
+			Expect(parseParams(upgradedData)).Should(Equal(map[string]string{
+				"max_connections":      "2000",
+				"max_user_connections": "1000",
+				"shared_buffers":       "128MB",
+				"work_mem":             "4MB",
+			}))
+		})
+
+		It("should keep the running files without the config constraint", func() {
+			upgradedData, err := upgradeConfigTemplate(appsv1alpha1.UseExistingConfigTemplateUpgradePolicy,
+				runningCM.Data, map[string]string{testConfigFile: renderedConfig}, renames, nil, newConfigSpec)
+			Expect(err).Should(Succeed())
+			Expect(upgradedData).Should(Equal(runningCM.Data))
+		})
+	})
+})
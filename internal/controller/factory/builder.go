@@ -31,11 +31,14 @@ import (
 
 	"github.com/google/uuid"
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"golang.org/x/exp/slices"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 
@@ -59,11 +62,17 @@ const (
 	MountPath  = "/etc/pki/tls"
 )
 
+const (
+	InitScriptVolumeName       = "init-scripts"
+	DefaultInitScriptMountPath = "/kb-init-scripts"
+)
+
 func processContainersInjection(reqCtx intctrlutil.RequestCtx,
 	cluster *appsv1alpha1.Cluster,
 	component *component.SynthesizedComponent,
 	envConfigName string,
 	podSpec *corev1.PodSpec) error {
+	injectInitScriptVolume(component, podSpec)
 	for _, cc := range []*[]corev1.Container{
 		&podSpec.Containers,
 		&podSpec.InitContainers,
@@ -78,6 +87,69 @@ func processContainersInjection(reqCtx intctrlutil.RequestCtx,
 	return nil
 }
 
+// injectInitScriptVolume mounts the ConfigMap of the bootstrap scripts into the containers specified by the component,
+// or the main container by default, at the init script mount path of the component.
+func injectInitScriptVolume(component *component.SynthesizedComponent, podSpec *corev1.PodSpec) {
+	if len(component.InitScriptConfigMap) == 0 {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: InitScriptVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: component.InitScriptConfigMap},
+			},
+		},
+	})
+	mountPath := getInitScriptMountPath(component)
+	mount := func(c *corev1.Container) {
+		for _, volumeMount := range c.VolumeMounts {
+			// the path is occupied by the container itself
+			if volumeMount.MountPath == mountPath {
+				return
+			}
+		}
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      InitScriptVolumeName,
+			MountPath: mountPath,
+			ReadOnly:  true,
+		})
+	}
+	if len(component.InitScriptContainers) == 0 {
+		if len(podSpec.Containers) > 0 {
+			mount(&podSpec.Containers[0])
+		}
+		return
+	}
+	for _, cc := range []*[]corev1.Container{&podSpec.Containers, &podSpec.InitContainers} {
+		for i := range *cc {
+			if slices.Contains(component.InitScriptContainers, (*cc)[i].Name) {
+				mount(&(*cc)[i])
+			}
+		}
+	}
+}
+
+// getInitScriptMountPath returns the path to mount the bootstrap scripts of the component at.
+func getInitScriptMountPath(component *component.SynthesizedComponent) string {
+	if len(component.InitScriptMountPath) > 0 {
+		return component.InitScriptMountPath
+	}
+	return DefaultInitScriptMountPath
+}
+
+// isInitScriptMounted checks whether the bootstrap scripts are mounted into the container.
+func isInitScriptMounted(c *corev1.Container) bool {
+	for _, volumeMount := range c.VolumeMounts {
+		if volumeMount.Name == InitScriptVolumeName {
+			return true
+		}
+	}
+	return false
+}
+
+// injectEnvs injects the KubeBlocks built-in env into the container, i.e. the identity of the pod by the downward API,
+// e.g. KB_POD_NAME and KB_NAMESPACE, and the names of the cluster and the component, KB_CLUSTER_NAME and KB_COMP_NAME.
 func injectEnvs(cluster *appsv1alpha1.Cluster, component *component.SynthesizedComponent, envConfigName string, c *corev1.Container) error {
 	// can not use map, it is unordered
 	envFieldPathSlice := []struct {
@@ -135,6 +207,16 @@ func injectEnvs(cluster *appsv1alpha1.Cluster, component *component.SynthesizedC
 		}...)
 	}
 
+	if len(component.InitScriptConfigMap) > 0 && isInitScriptMounted(c) {
+		toInjectEnvs = append(toInjectEnvs, corev1.EnvVar{Name: constant.KBEnvInitScriptsDir, Value: getInitScriptMountPath(component)})
+	}
+
+	if component.InjectCPULimitEnv {
+		if cpuLimit, ok := getCPULimitCores(c); ok {
+			toInjectEnvs = append(toInjectEnvs, corev1.EnvVar{Name: constant.KBEnvCPULimit, Value: strconv.FormatInt(cpuLimit, 10)})
+		}
+	}
+
 	if udeValue, ok := cluster.Annotations[constant.ExtraEnvAnnotationKey]; ok {
 		udeMap := make(map[string]string)
 		if err := json.Unmarshal([]byte(udeValue), &udeMap); err != nil {
@@ -175,6 +257,19 @@ func injectEnvs(cluster *appsv1alpha1.Cluster, component *component.SynthesizedC
 	return nil
 }
 
+// getCPULimitCores returns the CPU limit of the container in whole cores, the fractional limit is rounded up.
+// The CPU request is used if the limit is not set, and it returns false if neither is set.
+func getCPULimitCores(c *corev1.Container) (int64, bool) {
+	cpu, ok := c.Resources.Limits[corev1.ResourceCPU]
+	if !ok || cpu.IsZero() {
+		cpu, ok = c.Resources.Requests[corev1.ResourceCPU]
+	}
+	if !ok || cpu.IsZero() {
+		return 0, false
+	}
+	return (cpu.MilliValue() + 999) / 1000, true
+}
+
 // BuildPersistentVolumeClaimLabels builds a pvc name label, and synchronize the labels from sts to pvc.
 func BuildPersistentVolumeClaimLabels(component *component.SynthesizedComponent, pvc *corev1.PersistentVolumeClaim,
 	pvcTplName string) {
@@ -214,6 +309,30 @@ func vctToPVC(vct corev1.PersistentVolumeClaimTemplate) corev1.PersistentVolumeC
 	}
 }
 
+// buildWorkloadAnnotations returns the custom annotations of the workload object, the keys managed by KubeBlocks
+// and the scoped ones of RSM are skipped, so they can't be overridden by the users.
+func buildWorkloadAnnotations(component *component.SynthesizedComponent) map[string]string {
+	annotations := map[string]string{}
+	for k, v := range component.WorkloadAnnotations {
+		if isManagedAnnotationKey(k) {
+			continue
+		}
+		annotations[k] = v
+	}
+	return annotations
+}
+
+func isManagedAnnotationKey(key string) bool {
+	if strings.HasSuffix(key, ".rsm") {
+		return true
+	}
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	return prefix == "kubeblocks.io" || strings.HasSuffix(prefix, ".kubeblocks.io")
+}
+
 func BuildSts(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster,
 	component *component.SynthesizedComponent, envConfigName string) (*appsv1.StatefulSet, error) {
 	commonLabels := BuildCommonLabels(cluster, component)
@@ -229,6 +348,7 @@ func BuildSts(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster,
 		Spec:       *component.PodSpec,
 	}
 	stsBuilder := builder.NewStatefulSetBuilder(cluster.Namespace, cluster.Name+"-"+component.Name).
+		AddAnnotationsInMap(buildWorkloadAnnotations(component)).
 		AddLabelsInMap(commonLabels).
 		AddLabels(constant.AppComponentLabelKey, component.CompDefName).
 		AddMatchLabelsInMap(commonLabels).
@@ -247,6 +367,22 @@ func BuildSts(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster,
 		stsBuilder.SetPodManagementPolicy(podManagementPolicy).SetUpdateStrategy(updateStrategy)
 	}
 
+	if component.OrdinalStart > 0 {
+		stsBuilder.SetOrdinals(&appsv1.StatefulSetOrdinals{Start: component.OrdinalStart})
+	}
+
+	if len(component.SchedulerName) > 0 {
+		stsBuilder.SetSchedulerName(component.SchedulerName)
+	}
+
+	if len(component.PriorityClassName) > 0 {
+		stsBuilder.SetPriorityClassName(component.PriorityClassName)
+	}
+
+	if len(component.DNSSearchDomains) > 0 {
+		stsBuilder.AddDNSSearchDomains(component.DNSSearchDomains...)
+	}
+
 	sts := stsBuilder.GetObject()
 
 	// update sts.spec.volumeClaimTemplates[].metadata.labels
@@ -324,6 +460,7 @@ func BuildRSM(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster,
 	}()
 	rsmName := fmt.Sprintf("%s-%s", cluster.Name, component.Name)
 	rsmBuilder := builder.NewReplicatedStateMachineBuilder(cluster.Namespace, rsmName).
+		AddAnnotationsInMap(buildWorkloadAnnotations(component)).
 		AddAnnotations(constant.KubeBlocksGenerationKey, strconv.FormatInt(cluster.Generation, 10)).
 		AddAnnotationsInMap(monitorAnnotations).
 		AddLabelsInMap(commonLabels).
@@ -344,6 +481,25 @@ func BuildRSM(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster,
 		rsmBuilder.SetPodManagementPolicy(podManagementPolicy).SetUpdateStrategy(updateStrategy)
 	}
 
+	if component.OrdinalStart > 0 {
+		rsmBuilder.SetOrdinals(&appsv1.StatefulSetOrdinals{Start: component.OrdinalStart})
+	}
+	if component.PodNamePrefix != "" {
+		rsmBuilder.SetPodNamePrefix(component.PodNamePrefix)
+	}
+
+	if len(component.SchedulerName) > 0 {
+		rsmBuilder.SetSchedulerName(component.SchedulerName)
+	}
+
+	if len(component.PriorityClassName) > 0 {
+		rsmBuilder.SetPriorityClassName(component.PriorityClassName)
+	}
+
+	if len(component.DNSSearchDomains) > 0 {
+		rsmBuilder.AddDNSSearchDomains(component.DNSSearchDomains...)
+	}
+
 	service, alternativeServices := separateServices(component.Services)
 	addCommonLabels(service)
 	for i := range alternativeServices {
@@ -416,7 +572,8 @@ func fixService(namespace, prefix string, component *component.SynthesizedCompon
 		if alternativeServices[i].Spec.Type == corev1.ServiceTypeLoadBalancer {
 			alternativeServices[i].Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
 		}
-		if len(leaderName) > 0 {
+		// the services backed by the roles declared in the component definition keep their role selectors.
+		if _, ok := alternativeServices[i].Spec.Selector[constant.RoleLabelKey]; !ok && len(leaderName) > 0 {
 			selector := alternativeServices[i].Spec.Selector
 			if selector == nil {
 				selector = make(map[string]string, 0)
@@ -660,6 +817,76 @@ func randomString(length int) string {
 	return rand.String(length)
 }
 
+// BuildSharedSecret builds the cluster-level shared secret with a random token, which is shared by the components
+// declaring it in the cluster definition.
+func BuildSharedSecret(clusterDefinition *appsv1alpha1.ClusterDefinition, cluster *appsv1alpha1.Cluster) *corev1.Secret {
+	wellKnownLabels := buildWellKnownLabels(clusterDefinition.Name, cluster.Name, "")
+	delete(wellKnownLabels, constant.KBAppComponentLabelKey)
+	return builder.NewSecretBuilder(cluster.Namespace, component.GenerateSharedSecretName(cluster.Name)).
+		AddLabelsInMap(wellKnownLabels).
+		SetStringData(map[string]string{
+			constant.SharedSecretTokenKey: randomString(32),
+		}).
+		GetObject()
+}
+
+func BuildSpecHistory(clusterDefinition *appsv1alpha1.ClusterDefinition, cluster *appsv1alpha1.Cluster,
+	snapshot, snapshotHash string, history []component.SpecHistoryEntry) (*corev1.Secret, error) {
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return nil, err
+	}
+	wellKnownLabels := buildWellKnownLabels(clusterDefinition.Name, cluster.Name, "")
+	delete(wellKnownLabels, constant.KBAppComponentLabelKey)
+	return builder.NewSecretBuilder(cluster.Namespace, component.GenerateSpecHistoryName(cluster.Name)).
+		AddLabelsInMap(wellKnownLabels).
+		AddAnnotations(constant.SpecSnapshotHashAnnotationKey, snapshotHash).
+		SetData(map[string][]byte{
+			constant.SpecSnapshotKey: []byte(snapshot),
+			constant.SpecHistoryKey:  historyJSON,
+		}).
+		GetObject(), nil
+}
+
+// BuildLastAppliedCluster builds the ConfigMap storing the last applied cluster, which is referred by the objects
+// preserved on halt and outlives the cluster.
+func BuildLastAppliedCluster(cluster *appsv1alpha1.Cluster, clusterJSON string) *corev1.ConfigMap {
+	wellKnownLabels := buildWellKnownLabels(cluster.Spec.ClusterDefRef, cluster.Name, "")
+	delete(wellKnownLabels, constant.KBAppComponentLabelKey)
+	return builder.NewConfigMapBuilder(cluster.Namespace, component.GenerateLastAppliedClusterName(cluster.Name)).
+		AddLabelsInMap(wellKnownLabels).
+		SetData(map[string]string{
+			constant.LastAppliedClusterKey: clusterJSON,
+		}).
+		GetObject()
+}
+
+// BuildBackendsConfigMap builds the ConfigMap listing the backends of the component declaring backendDiscovery,
+// the checksum of the backends is annotated to tell the changes.
+func BuildBackendsConfigMap(cluster *appsv1alpha1.Cluster, compName, backends, checksum string) *corev1.ConfigMap {
+	return builder.NewConfigMapBuilder(cluster.Namespace, component.GenerateBackendsConfigMapName(cluster.Name, compName)).
+		AddLabelsInMap(buildWellKnownLabels(cluster.Spec.ClusterDefRef, cluster.Name, compName)).
+		AddAnnotations(constant.BackendsChecksumAnnotationKey, checksum).
+		SetData(map[string]string{
+			constant.BackendsKey: backends,
+		}).
+		GetObject()
+}
+
+// BuildRoleService builds the service routing to the pods of the component playing the role.
+func BuildRoleService(cluster *appsv1alpha1.Cluster, compDef *appsv1alpha1.ClusterComponentDefinition,
+	compName, role string) *corev1.Service {
+	wellKnownLabels := buildWellKnownLabels(cluster.Spec.ClusterDefRef, cluster.Name, compName)
+	return builder.NewServiceBuilder(cluster.Namespace, component.GenerateRoleServiceName(cluster.Name, compName, role)).
+		AddLabelsInMap(wellKnownLabels).
+		AddLabels(constant.RoleLabelKey, role).
+		AddSelectorsInMap(wellKnownLabels).
+		AddSelector(constant.RoleLabelKey, role).
+		AddPorts(compDef.Service.ToSVCSpec().Ports...).
+		SetType(corev1.ServiceTypeClusterIP).
+		GetObject()
+}
+
 func BuildConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, cluster *appsv1alpha1.Cluster,
 	component *component.SynthesizedComponent) *corev1.Secret {
 	wellKnownLabels := buildWellKnownLabels(clusterDefinition.Name, cluster.Name, "")
@@ -983,6 +1210,16 @@ func BuildVolumeSnapshotClass(name string, driver string) *snapshotv1.VolumeSnap
 		GetObject()
 }
 
+// BuildResourceQuota builds the ResourceQuota of the cluster with the hard limits summed from its components.
+func BuildResourceQuota(cluster *appsv1alpha1.Cluster, hard corev1.ResourceList) *corev1.ResourceQuota {
+	wellKnownLabels := buildWellKnownLabels(cluster.Spec.ClusterDefRef, cluster.Name, "")
+	delete(wellKnownLabels, constant.KBAppComponentLabelKey)
+	return builder.NewResourceQuotaBuilder(cluster.Namespace, fmt.Sprintf("kb-%s-quota", cluster.Name)).
+		AddLabelsInMap(wellKnownLabels).
+		SetHard(hard).
+		GetObject()
+}
+
 func BuildServiceAccount(cluster *appsv1alpha1.Cluster) *corev1.ServiceAccount {
 	wellKnownLabels := buildWellKnownLabels(cluster.Spec.ClusterDefRef, cluster.Name, "")
 	delete(wellKnownLabels, constant.KBAppComponentLabelKey)
@@ -1026,3 +1263,86 @@ func BuildClusterRoleBinding(cluster *appsv1alpha1.Cluster) *rbacv1.ClusterRoleB
 		}).
 		GetObject()
 }
+
+// MonitorResourceGVK returns the GVK of the prometheus-operator resource of the kind.
+func MonitorResourceGVK(kind appsv1alpha1.MonitorResourceKind) schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: string(kind)}
+}
+
+// BuildMonitorResource builds the ServiceMonitor or PodMonitor scraping the exporter of the component. It's built as
+// an unstructured object, since the prometheus-operator API is required only if the monitor resource is requested.
+func BuildMonitorResource(cluster *appsv1alpha1.Cluster, compSpec *appsv1alpha1.ClusterComponentSpec,
+	monitor *component.MonitorConfig) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(MonitorResourceGVK(compSpec.MonitorResource))
+	obj.SetNamespace(cluster.Namespace)
+	obj.SetName(fmt.Sprintf("%s-%s", cluster.Name, compSpec.Name))
+	obj.SetLabels(buildWellKnownLabels(cluster.Spec.ClusterDefRef, cluster.Name, compSpec.Name))
+
+	endpoint := map[string]interface{}{
+		"targetPort": int64(monitor.ScrapePort),
+		"scheme":     "http",
+	}
+	if len(monitor.ScrapePath) > 0 {
+		endpoint["path"] = monitor.ScrapePath
+	}
+	if len(compSpec.MonitorScrapeInterval) > 0 {
+		endpoint["interval"] = compSpec.MonitorScrapeInterval
+	}
+	var relabelings []interface{}
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				constant.AppInstanceLabelKey:    cluster.Name,
+				constant.KBAppComponentLabelKey: compSpec.Name,
+			},
+		},
+	}
+	switch compSpec.MonitorResource {
+	case appsv1alpha1.PodMonitorKind:
+		spec["podMetricsEndpoints"] = []interface{}{endpoint}
+	default:
+		// the other services of the component select the same pods, only the targets of the headless service are kept.
+		relabelings = append(relabelings, map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_service_name"},
+			"regex":        fmt.Sprintf("%s-%s-headless", cluster.Name, compSpec.Name),
+			"action":       "keep",
+		})
+		spec["endpoints"] = []interface{}{endpoint}
+	}
+	for _, relabel := range compSpec.MonitorRelabelings {
+		relabelings = append(relabelings, buildMonitorRelabeling(relabel))
+	}
+	if len(relabelings) > 0 {
+		endpoint["relabelings"] = relabelings
+	}
+	obj.Object["spec"] = spec
+	return obj
+}
+
+// buildMonitorRelabeling converts the relabeling rule into the unstructured form, the fields not set are omitted.
+func buildMonitorRelabeling(relabel appsv1alpha1.MonitorRelabelConfig) map[string]interface{} {
+	rule := map[string]interface{}{}
+	if len(relabel.SourceLabels) > 0 {
+		sourceLabels := make([]interface{}, 0, len(relabel.SourceLabels))
+		for _, label := range relabel.SourceLabels {
+			sourceLabels = append(sourceLabels, label)
+		}
+		rule["sourceLabels"] = sourceLabels
+	}
+	for key, value := range map[string]string{
+		"separator":   relabel.Separator,
+		"targetLabel": relabel.TargetLabel,
+		"regex":       relabel.Regex,
+		"replacement": relabel.Replacement,
+		"action":      relabel.Action,
+	} {
+		if len(value) > 0 {
+			rule[key] = value
+		}
+	}
+	if relabel.Modulus > 0 {
+		rule["modulus"] = int64(relabel.Modulus)
+	}
+	return rule
+}
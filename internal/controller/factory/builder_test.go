@@ -22,6 +22,7 @@ package factory
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -29,6 +30,7 @@ import (
 	"golang.org/x/exp/slices"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -105,7 +107,9 @@ var _ = Describe("builder", func() {
 			AddAnnotationsInMap(newExtraEnvs()).
 			AddComponent(mysqlCompName, mysqlCompDefName).SetReplicas(1).
 			AddVolumeClaimTemplate(testapps.DataVolumeName, pvcSpec).
+			SetSessionAffinity(corev1.ServiceAffinityClientIP).
 			AddService(testapps.ServiceVPCName, corev1.ServiceTypeLoadBalancer).
+			SetServiceSessionAffinity(corev1.ServiceAffinityClientIP).
 			AddService(testapps.ServiceInternetName, corev1.ServiceTypeLoadBalancer).
 			GetObject()
 		key := client.ObjectKeyFromObject(clusterObj)
@@ -181,6 +185,20 @@ var _ = Describe("builder", func() {
 			Expect(pvc.Labels[constant.VolumeTypeLabelKey]).ShouldNot(BeEmpty())
 		})
 
+		It("builds shared secret correctly", func() {
+			var (
+				clusterDefObj          = testapps.NewClusterDefFactoryWithConnCredential("conn-cred").GetObject()
+				clusterDef, cluster, _ = newClusterObjs(clusterDefObj)
+			)
+			secret := BuildSharedSecret(clusterDef, cluster)
+			Expect(secret).ShouldNot(BeNil())
+			Expect(secret.Name).Should(Equal(component.GenerateSharedSecretName(cluster.Name)))
+			Expect(secret.Labels[constant.AppInstanceLabelKey]).Should(Equal(cluster.Name))
+			Expect(secret.StringData[constant.SharedSecretTokenKey]).Should(HaveLen(32))
+			Expect(BuildSharedSecret(clusterDef, cluster).StringData[constant.SharedSecretTokenKey]).
+				ShouldNot(Equal(secret.StringData[constant.SharedSecretTokenKey]))
+		})
+
 		It("builds Conn. Credential correctly", func() {
 			var (
 				clusterDefObj                             = testapps.NewClusterDefFactoryWithConnCredential("conn-cred").GetObject()
@@ -277,6 +295,48 @@ var _ = Describe("builder", func() {
 			}
 		})
 
+		It("builds RSM with the extra DNS search domains", func() {
+			reqCtx := newReqCtx()
+			clusterDef := allFieldsClusterDefObj(false)
+			clusterVersion := allFieldsClusterVersionObj(false)
+			cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).SetReplicas(1).
+				AddDNSSearchDomain("foo.svc.cluster.local").
+				AddDNSSearchDomain("bar.svc.cluster.local").
+				GetObject()
+			synthesizedComponent, err := component.BuildComponent(reqCtx, nil, cluster, clusterDef,
+				&clusterDef.Spec.ComponentDefs[0], &cluster.Spec.ComponentSpecs[0], nil, &clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+
+			rsm, err := BuildRSM(reqCtx, cluster, synthesizedComponent, "test-env-config-name")
+			Expect(err).Should(BeNil())
+			Expect(rsm.Spec.Template.Spec.DNSConfig).ShouldNot(BeNil())
+			Expect(rsm.Spec.Template.Spec.DNSConfig.Searches).Should(Equal([]string{"foo.svc.cluster.local", "bar.svc.cluster.local"}))
+		})
+
+		It("builds RSM with the custom workload annotations", func() {
+			reqCtx := newReqCtx()
+			clusterDef := allFieldsClusterDefObj(false)
+			clusterVersion := allFieldsClusterVersionObj(false)
+			cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).SetReplicas(1).
+				AddWorkloadAnnotation("argocd.argoproj.io/compare-options", "IgnoreExtraneous").
+				AddWorkloadAnnotation(constant.KubeBlocksGenerationKey, "100").
+				AddWorkloadAnnotation("monitor.kubeblocks.io/scrape", "false").
+				GetObject()
+			cluster.Generation = 1
+			synthesizedComponent, err := component.BuildComponent(reqCtx, nil, cluster, clusterDef,
+				&clusterDef.Spec.ComponentDefs[0], &cluster.Spec.ComponentSpecs[0], nil, &clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+
+			rsm, err := BuildRSM(reqCtx, cluster, synthesizedComponent, "test-env-config-name")
+			Expect(err).Should(BeNil())
+			Expect(rsm.Annotations).Should(HaveKeyWithValue("argocd.argoproj.io/compare-options", "IgnoreExtraneous"))
+			Expect(rsm.Annotations).Should(HaveKeyWithValue(constant.KubeBlocksGenerationKey, "1"))
+			Expect(rsm.Annotations).ShouldNot(HaveKey("monitor.kubeblocks.io/scrape"))
+			Expect(rsm.Spec.Template.Annotations).ShouldNot(HaveKey("argocd.argoproj.io/compare-options"))
+		})
+
 		It("builds RSM correctly", func() {
 			reqCtx := newReqCtx()
 			_, cluster, synthesizedComponent := newClusterObjs(nil)
@@ -295,6 +355,62 @@ var _ = Describe("builder", func() {
 			Expect(*rsm.Spec.Replicas).Should(Equal(int32(0)))
 			Expect(rsm.Spec.VolumeClaimTemplates[0].Labels[constant.VolumeTypeLabelKey]).
 				Should(Equal(string(appsv1alpha1.VolumeTypeData)))
+			Expect(rsm.Spec.Ordinals).Should(BeNil())
+
+			By("check the session affinity of services")
+			Expect(rsm.Spec.Service).ShouldNot(BeNil())
+			Expect(rsm.Spec.Service.Spec.SessionAffinity).Should(Equal(corev1.ServiceAffinityClientIP))
+			Expect(rsm.Spec.Service.Spec.SessionAffinityConfig).ShouldNot(BeNil())
+			Expect(rsm.Spec.AlternativeServices).ShouldNot(BeEmpty())
+			for _, svc := range rsm.Spec.AlternativeServices {
+				switch {
+				case strings.HasSuffix(svc.Name, "-"+testapps.ServiceVPCName):
+					Expect(svc.Spec.SessionAffinity).Should(Equal(corev1.ServiceAffinityClientIP))
+					Expect(svc.Spec.SessionAffinityConfig).ShouldNot(BeNil())
+					Expect(*svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds).
+						Should(Equal(corev1.DefaultClientIPServiceAffinitySeconds))
+				case strings.HasSuffix(svc.Name, "-"+testapps.ServiceInternetName):
+					Expect(svc.Spec.SessionAffinity).Should(BeEmpty())
+					Expect(svc.Spec.SessionAffinityConfig).Should(BeNil())
+				}
+			}
+
+			By("set ordinal start")
+			ordinalComponent := *synthesizedComponent
+			ordinalComponent.OrdinalStart = 3
+			rsm, err = BuildRSM(reqCtx, cluster, &ordinalComponent, envConfigName)
+			Expect(err).Should(BeNil())
+			Expect(rsm.Spec.Ordinals).ShouldNot(BeNil())
+			Expect(rsm.Spec.Ordinals.Start).Should(BeEquivalentTo(3))
+			Expect(rsm.Spec.Template.Spec.SchedulerName).Should(BeEmpty())
+			Expect(rsm.Spec.PodNamePrefix).Should(BeEmpty())
+
+			By("set pod name prefix")
+			prefixComponent := *synthesizedComponent
+			prefixComponent.PodNamePrefix = "mysql"
+			rsm, err = BuildRSM(reqCtx, cluster, &prefixComponent, envConfigName)
+			Expect(err).Should(BeNil())
+			Expect(rsm.Spec.PodNamePrefix).Should(Equal("mysql"))
+			Expect(rsm.Name).Should(Equal(cluster.Name + "-" + prefixComponent.Name))
+
+			By("set scheduler name")
+			schedulerComponent := *synthesizedComponent
+			schedulerComponent.SchedulerName = "volcano"
+			rsm, err = BuildRSM(reqCtx, cluster, &schedulerComponent, envConfigName)
+			Expect(err).Should(BeNil())
+			Expect(rsm.Spec.Template.Spec.SchedulerName).Should(Equal("volcano"))
+
+			By("set priority class name")
+			priorityComponent := *synthesizedComponent
+			priorityComponent.PodSpec = synthesizedComponent.PodSpec.DeepCopy()
+			priorityComponent.PodSpec.PriorityClassName = "default-priority"
+			rsm, err = BuildRSM(reqCtx, cluster, &priorityComponent, envConfigName)
+			Expect(err).Should(BeNil())
+			Expect(rsm.Spec.Template.Spec.PriorityClassName).Should(Equal("default-priority"))
+			priorityComponent.PriorityClassName = "high-priority"
+			rsm, err = BuildRSM(reqCtx, cluster, &priorityComponent, envConfigName)
+			Expect(err).Should(BeNil())
+			Expect(rsm.Spec.Template.Spec.PriorityClassName).Should(Equal("high-priority"))
 
 			By("set workload type to Replication")
 			replComponent := *synthesizedComponent
@@ -374,6 +490,159 @@ var _ = Describe("builder", func() {
 			Expect(*rsm.Spec.MemberUpdateStrategy).Should(BeEquivalentTo(workloads.BestEffortParallelUpdateStrategy))
 		})
 
+		It("builds RSM with the pod metadata env correctly", func() {
+			reqCtx := newReqCtx()
+			_, cluster, synthesizedComponent := newClusterObjs(nil)
+			envConfigName := "test-env-config-name"
+
+			rsm, err := BuildRSM(reqCtx, cluster, synthesizedComponent, envConfigName)
+			Expect(err).Should(BeNil())
+			podTemplate := rsm.Spec.Template
+			Expect(podTemplate.Spec.Containers).ShouldNot(BeEmpty())
+			for _, container := range append(podTemplate.Spec.InitContainers, podTemplate.Spec.Containers...) {
+				envs := map[string]corev1.EnvVar{}
+				for _, env := range container.Env {
+					envs[env.Name] = env
+				}
+				for name, fieldPath := range map[string]string{
+					constant.KBEnvPodName:   "metadata.name",
+					constant.KBEnvNamespace: "metadata.namespace",
+				} {
+					Expect(envs).Should(HaveKey(name))
+					Expect(envs[name].ValueFrom).ShouldNot(BeNil())
+					Expect(envs[name].ValueFrom.FieldRef).ShouldNot(BeNil())
+					Expect(envs[name].ValueFrom.FieldRef.FieldPath).Should(Equal(fieldPath))
+				}
+				Expect(envs[constant.KBEnvClusterName].Value).Should(Equal(cluster.Name))
+				Expect(envs[constant.KBEnvComponentName].Value).Should(Equal(synthesizedComponent.Name))
+			}
+		})
+
+		It("builds RSM with the CPU limit env", func() {
+			reqCtx := newReqCtx()
+			getCPULimitEnv := func(container corev1.Container) *corev1.EnvVar {
+				for i, env := range container.Env {
+					if env.Name == constant.KBEnvCPULimit {
+						return &container.Env[i]
+					}
+				}
+				return nil
+			}
+			buildRSM := func(inject bool, resources corev1.ResourceRequirements) corev1.Container {
+				_, cluster, synthesizedComponent := newClusterObjs(nil)
+				synthesizedComponent.InjectCPULimitEnv = inject
+				synthesizedComponent.PodSpec.Containers[0].Resources = resources
+				rsm, err := BuildRSM(reqCtx, cluster, synthesizedComponent, "test-env-config-name")
+				Expect(err).Should(BeNil())
+				return rsm.Spec.Template.Spec.Containers[0]
+			}
+			limits := func(cpu string) corev1.ResourceRequirements {
+				return corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				}
+			}
+
+			By("not injected unless opted in")
+			Expect(getCPULimitEnv(buildRSM(false, limits("2")))).Should(BeNil())
+
+			By("the whole cores of the limit")
+			env := getCPULimitEnv(buildRSM(true, limits("2")))
+			Expect(env).ShouldNot(BeNil())
+			Expect(env.Value).Should(Equal("2"))
+
+			By("the fractional limit is rounded up")
+			Expect(getCPULimitEnv(buildRSM(true, limits("1500m"))).Value).Should(Equal("2"))
+			Expect(getCPULimitEnv(buildRSM(true, limits("100m"))).Value).Should(Equal("1"))
+
+			By("the request is used if unlimited")
+			requests := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+			}
+			Expect(getCPULimitEnv(buildRSM(true, requests)).Value).Should(Equal("3"))
+
+			By("not injected if neither the limit nor the request is set")
+			Expect(getCPULimitEnv(buildRSM(true, corev1.ResourceRequirements{}))).Should(BeNil())
+		})
+
+		It("builds RSM with the init scripts mounted", func() {
+			reqCtx := newReqCtx()
+			initScriptConfigMap := "test-init-scripts"
+			clusterDef := allFieldsClusterDefObj(false)
+			clusterVersion := allFieldsClusterVersionObj(false)
+			cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDef.Name, clusterVersion.Name).
+				AddComponent(mysqlCompName, mysqlCompDefName).SetReplicas(1).
+				AddVolumeClaimTemplate(testapps.DataVolumeName, testapps.NewPVCSpec("1Gi")).
+				SetInitScriptConfigMap(initScriptConfigMap).
+				GetObject()
+			synthesizedComponent, err := component.BuildComponent(reqCtx, nil, cluster, clusterDef,
+				&clusterDef.Spec.ComponentDefs[0], &cluster.Spec.ComponentSpecs[0], nil, &clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			Expect(synthesizedComponent.InitScriptConfigMap).Should(Equal(initScriptConfigMap))
+
+			rsm, err := BuildRSM(reqCtx, cluster, synthesizedComponent, "test-env-config-name")
+			Expect(err).Should(BeNil())
+			podSpec := rsm.Spec.Template.Spec
+			Expect(podSpec.Volumes).Should(ContainElement(corev1.Volume{
+				Name: InitScriptVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: initScriptConfigMap},
+					},
+				},
+			}))
+			Expect(podSpec.Containers).ShouldNot(BeEmpty())
+			initScriptMount := corev1.VolumeMount{
+				Name:      InitScriptVolumeName,
+				MountPath: DefaultInitScriptMountPath,
+				ReadOnly:  true,
+			}
+			for i, container := range podSpec.Containers {
+				if i == 0 {
+					By("only the main container is mounted by default")
+					Expect(container.VolumeMounts).Should(ContainElement(initScriptMount))
+					Expect(container.Env).Should(ContainElement(corev1.EnvVar{
+						Name:  constant.KBEnvInitScriptsDir,
+						Value: DefaultInitScriptMountPath,
+					}))
+					continue
+				}
+				Expect(container.VolumeMounts).ShouldNot(ContainElement(initScriptMount))
+			}
+			for _, container := range podSpec.InitContainers {
+				Expect(container.VolumeMounts).ShouldNot(ContainElement(initScriptMount))
+			}
+
+			By("mount the init scripts at the specified path of the specified containers")
+			mountPath := "/scripts/init"
+			targetContainer := podSpec.Containers[len(podSpec.Containers)-1].Name
+			cluster.Spec.ComponentSpecs[0].InitScriptMountPath = mountPath
+			cluster.Spec.ComponentSpecs[0].InitScriptContainers = []string{targetContainer}
+			synthesizedComponent, err = component.BuildComponent(reqCtx, nil, cluster, clusterDef,
+				&clusterDef.Spec.ComponentDefs[0], &cluster.Spec.ComponentSpecs[0], nil, &clusterVersion.Spec.ComponentVersions[0])
+			Expect(err).Should(Succeed())
+			rsm, err = BuildRSM(reqCtx, cluster, synthesizedComponent, "test-env-config-name")
+			Expect(err).Should(BeNil())
+			for _, container := range rsm.Spec.Template.Spec.Containers {
+				mounted := false
+				for _, volumeMount := range container.VolumeMounts {
+					if volumeMount.Name == InitScriptVolumeName {
+						mounted = true
+						Expect(volumeMount.MountPath).Should(Equal(mountPath))
+					}
+				}
+				Expect(mounted).Should(Equal(container.Name == targetContainer))
+			}
+
+			By("no init scripts mounted if not specified")
+			_, cluster, synthesizedComponent = newClusterObjs(nil)
+			rsm, err = BuildRSM(reqCtx, cluster, synthesizedComponent, "test-env-config-name")
+			Expect(err).Should(BeNil())
+			for _, volume := range rsm.Spec.Template.Spec.Volumes {
+				Expect(volume.Name).ShouldNot(Equal(InitScriptVolumeName))
+			}
+		})
+
 		It("builds PDB correctly", func() {
 			_, cluster, synthesizedComponent := newClusterObjs(nil)
 			pdb := BuildPDB(cluster, synthesizedComponent)
@@ -22,11 +22,14 @@ package graph
 import (
 	"context"
 	"errors"
+	"reflect"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/apecloud/kubeblocks/internal/controller/client"
+	"github.com/apecloud/kubeblocks/internal/controller/tracing"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 )
 
@@ -38,6 +41,13 @@ type TransformContext interface {
 	GetLogger() logr.Logger
 }
 
+// ForcedTransformersContext is an optional interface of TransformContext.
+// It names the transformers which should still run after the chain is stopped prematurely,
+// which helps to diagnose a stuck reconciliation.
+type ForcedTransformersContext interface {
+	GetForcedTransformers() sets.Set[string]
+}
+
 // Transformer transforms a DAG to a new version
 type Transformer interface {
 	Transform(ctx TransformContext, dag *DAG) error
@@ -52,21 +62,73 @@ var ErrPrematureStop = errors.New("Premature-Stop")
 
 // ApplyTo applies TransformerChain t to dag
 func (r TransformerChain) ApplyTo(ctx TransformContext, dag *DAG) error {
+	forced := forcedTransformers(ctx)
+	stopped := false
 	var delayedError error
 	for _, transformer := range r {
-		if err := transformer.Transform(ctx, dag); err != nil {
-			if intctrlutil.IsDelayedRequeueError(err) {
+		if stopped {
+			// only the forced transformers run after the chain is stopped prematurely
+			if !forced.Has(TransformerName(transformer)) {
+				continue
+			}
+			ctx.GetLogger().Info("force running transformer", "transformer", TransformerName(transformer))
+		}
+		if err := transform(ctx, transformer, dag); err != nil {
+			switch {
+			case intctrlutil.IsDelayedRequeueError(err):
 				if delayedError == nil {
 					delayedError = err
 				}
-				continue
+			case err == ErrPrematureStop && forced.Len() > 0:
+				stopped = true
+			default:
+				return ignoredIfPrematureStop(err)
 			}
-			return ignoredIfPrematureStop(err)
 		}
 	}
+	if stopped {
+		return nil
+	}
 	return delayedError
 }
 
+// Names returns the names of the transformers in the chain.
+func (r TransformerChain) Names() sets.Set[string] {
+	names := sets.New[string]()
+	for _, transformer := range r {
+		names.Insert(TransformerName(transformer))
+	}
+	return names
+}
+
+func forcedTransformers(ctx TransformContext) sets.Set[string] {
+	if forcedCtx, ok := ctx.(ForcedTransformersContext); ok && forcedCtx.GetForcedTransformers() != nil {
+		return forcedCtx.GetForcedTransformers()
+	}
+	return sets.New[string]()
+}
+
+// transform runs the transformer within a span named by its type.
+func transform(ctx TransformContext, transformer Transformer, dag *DAG) error {
+	_, span := tracing.StartSpan(ctx.GetContext(), TransformerName(transformer))
+	err := transformer.Transform(ctx, dag)
+	if intctrlutil.IsDelayedRequeueError(err) || err == ErrPrematureStop {
+		tracing.EndSpan(span, nil)
+	} else {
+		tracing.EndSpan(span, err)
+	}
+	return err
+}
+
+// TransformerName returns the type name of the transformer, e.g. AssureMetaTransformer.
+func TransformerName(transformer Transformer) string {
+	t := reflect.TypeOf(transformer)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
 func ignoredIfPrematureStop(err error) error {
 	if err == ErrPrematureStop {
 		return nil
@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/apecloud/kubeblocks/internal/controller/client"
+	"github.com/apecloud/kubeblocks/internal/controller/tracing"
+)
+
+type testTransformContext struct {
+	ctx context.Context
+}
+
+func (c *testTransformContext) GetContext() context.Context       { return c.ctx }
+func (c *testTransformContext) GetClient() client.ReadonlyClient  { return nil }
+func (c *testTransformContext) GetRecorder() record.EventRecorder { return nil }
+func (c *testTransformContext) GetLogger() logr.Logger            { return logr.Discard() }
+
+type forcedTransformContext struct {
+	testTransformContext
+	forced sets.Set[string]
+}
+
+func (c *forcedTransformContext) GetForcedTransformers() sets.Set[string] { return c.forced }
+
+type passTransformer struct{}
+
+func (t *passTransformer) Transform(ctx TransformContext, dag *DAG) error {
+	return nil
+}
+
+type stopTransformer struct{}
+
+func (t *stopTransformer) Transform(ctx TransformContext, dag *DAG) error {
+	return ErrPrematureStop
+}
+
+type countTransformer struct {
+	count int
+}
+
+func (t *countTransformer) Transform(ctx TransformContext, dag *DAG) error {
+	t.count++
+	return nil
+}
+
+type failTransformer struct{}
+
+func (t *failTransformer) Transform(ctx TransformContext, dag *DAG) error {
+	return errors.New("mock error")
+}
+
+func TestTransformerChainSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	ctx, span := tracing.StartSpan(context.Background(), "Reconcile")
+	chain := TransformerChain{&passTransformer{}, &failTransformer{}, &passTransformer{}}
+	if err := chain.ApplyTo(&testTransformContext{ctx: ctx}, NewDAG()); err == nil {
+		t.Fatal("expected error from the chain")
+	}
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	root := spans[2]
+	for i, name := range []string{"passTransformer", "failTransformer"} {
+		if spans[i].Name != name {
+			t.Errorf("expected span %s, got %s", name, spans[i].Name)
+		}
+		if spans[i].Parent.SpanID() != root.SpanContext.SpanID() {
+			t.Errorf("expected span %s to be a child of the reconcile span", spans[i].Name)
+		}
+	}
+	if spans[0].Status.Code != codes.Unset {
+		t.Errorf("unexpected status of span passTransformer: %v", spans[0].Status)
+	}
+	if spans[1].Status.Code != codes.Error {
+		t.Errorf("unexpected status of span failTransformer: %v", spans[1].Status)
+	}
+}
+
+func TestTransformerChainForcedTransformers(t *testing.T) {
+	counter := &countTransformer{}
+	chain := TransformerChain{&stopTransformer{}, &passTransformer{}, counter}
+
+	if err := chain.ApplyTo(&testTransformContext{ctx: context.Background()}, NewDAG()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counter.count != 0 {
+		t.Fatalf("expected countTransformer to be skipped, but it ran %d times", counter.count)
+	}
+
+	ctx := &forcedTransformContext{
+		testTransformContext: testTransformContext{ctx: context.Background()},
+		forced:               sets.New("countTransformer"),
+	}
+	if err := chain.ApplyTo(ctx, NewDAG()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counter.count != 1 {
+		t.Fatalf("expected countTransformer to be forced to run once, but it ran %d times", counter.count)
+	}
+
+	ctx.forced = sets.New("countTransformer", "failTransformer")
+	chain = TransformerChain{&stopTransformer{}, &failTransformer{}, counter}
+	if err := chain.ApplyTo(ctx, NewDAG()); err == nil {
+		t.Fatal("expected error from the forced transformer")
+	}
+	if counter.count != 1 {
+		t.Fatalf("expected the chain to stop at the failed transformer, but countTransformer ran %d times", counter.count)
+	}
+}
+
+func TestTransformerChainNames(t *testing.T) {
+	chain := TransformerChain{&passTransformer{}, &failTransformer{}, &passTransformer{}}
+	names := chain.Names()
+	if !names.Equal(sets.New("passTransformer", "failTransformer")) {
+		t.Errorf("unexpected transformer names: %v", sets.List(names))
+	}
+}
@@ -34,7 +34,7 @@ type GraphWriter interface {
 	Create(dag *graph.DAG, obj client.Object)
 
 	// Delete deletes the given obj from the underlying DAG.
-	Delete(dag *graph.DAG, obj client.Object)
+	Delete(dag *graph.DAG, obj client.Object, options ...WriteOption)
 
 	// Update updates the given obj in the underlying DAG.
 	Update(dag *graph.DAG, objOld, objNew client.Object)
@@ -47,6 +47,17 @@ type GraphWriter interface {
 	DependOn(dag *graph.DAG, object client.Object, dependency ...client.Object)
 }
 
+// WriteOption customizes the vertex of the object to write.
+type WriteOption func(vertex *ObjectVertex)
+
+// WithPropagationPolicy sets the propagation policy used to delete the object,
+// e.g. the dependents of the object will be kept if the policy is Orphan.
+func WithPropagationPolicy(policy client.PropagationPolicy) WriteOption {
+	return func(vertex *ObjectVertex) {
+		vertex.PropagationPolicy = &policy
+	}
+}
+
 type GraphClient interface {
 	client.Reader
 	GraphWriter
@@ -74,8 +85,8 @@ func (r *realGraphClient) Update(dag *graph.DAG, objOld, objNew client.Object) {
 	r.doWrite(dag, objOld, objNew, ActionPtr(UPDATE))
 }
 
-func (r *realGraphClient) Delete(dag *graph.DAG, obj client.Object) {
-	r.doWrite(dag, nil, obj, ActionPtr(DELETE))
+func (r *realGraphClient) Delete(dag *graph.DAG, obj client.Object, options ...WriteOption) {
+	r.doWrite(dag, nil, obj, ActionPtr(DELETE), options...)
 }
 
 func (r *realGraphClient) Status(dag *graph.DAG, objOld, objNew client.Object) {
@@ -98,19 +109,23 @@ func (r *realGraphClient) DependOn(dag *graph.DAG, object client.Object, depende
 	}
 }
 
-func (r *realGraphClient) doWrite(dag *graph.DAG, objOld, objNew client.Object, action *Action) {
+func (r *realGraphClient) doWrite(dag *graph.DAG, objOld, objNew client.Object, action *Action, options ...WriteOption) {
+	var objVertex *ObjectVertex
 	vertex := r.findMatchedVertex(dag, objNew)
 	switch {
 	case vertex != nil:
-		objVertex, _ := vertex.(*ObjectVertex)
+		objVertex, _ = vertex.(*ObjectVertex)
 		objVertex.Action = action
 	default:
-		vertex = &ObjectVertex{
+		objVertex = &ObjectVertex{
 			Obj:    objNew,
 			OriObj: objOld,
 			Action: action,
 		}
-		dag.AddConnectRoot(vertex)
+		dag.AddConnectRoot(objVertex)
+	}
+	for _, option := range options {
+		option(objVertex)
 	}
 }
 
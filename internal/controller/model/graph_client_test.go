@@ -23,6 +23,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	"github.com/apecloud/kubeblocks/internal/controller/builder"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
 )
@@ -71,6 +74,14 @@ var _ = Describe("graph client test.", func() {
 			v1.Action = ActionPtr(UPDATE)
 			v2.Action = ActionPtr(DELETE)
 			Expect(dag.Equals(dagExpected, DefaultLess)).Should(BeTrue())
+
+			By("delete object with propagation policy")
+			graphCli.Delete(dag, obj2, WithPropagationPolicy(client.PropagationPolicy(metav1.DeletePropagationOrphan)))
+			vertex, ok := graphCli.(*realGraphClient).findMatchedVertex(dag, obj2).(*ObjectVertex)
+			Expect(ok).Should(BeTrue())
+			Expect(*vertex.Action).Should(Equal(DELETE))
+			Expect(vertex.PropagationPolicy).ShouldNot(BeNil())
+			Expect(*vertex.PropagationPolicy).Should(Equal(client.PropagationPolicy(metav1.DeletePropagationOrphan)))
 		})
 	})
 })
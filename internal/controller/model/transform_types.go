@@ -66,12 +66,14 @@ type GVKNObjKey struct {
 // as all its meta, spec and status can be updated in one reconciliation loop
 // Update is ignored when immutable=true
 // orphan object will be force deleted when action is DELETE
+// propagationPolicy is used to delete the object when action is DELETE, the default policy of the object is used if nil
 type ObjectVertex struct {
-	Obj       client.Object
-	OriObj    client.Object
-	Immutable bool
-	IsOrphan  bool
-	Action    *Action
+	Obj               client.Object
+	OriObj            client.Object
+	Immutable         bool
+	IsOrphan          bool
+	Action            *Action
+	PropagationPolicy *client.PropagationPolicy
 }
 
 func (v *ObjectVertex) String() string {
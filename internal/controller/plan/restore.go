@@ -120,14 +120,16 @@ func (r *RestoreManager) BuildPrepareDataRestore(comp *component.SynthesizedComp
 	if targetVolumes == nil {
 		return nil, nil
 	}
+	// only the name and UID of the cluster are recorded, which are enough for the halt recovering to identify
+	// the cluster owning the PVCs, and keep the annotation small for the clusters with many components.
 	getClusterJSON := func() string {
-		clusterSpec := r.Cluster.DeepCopy()
-		clusterSpec.ObjectMeta = metav1.ObjectMeta{
-			Name: clusterSpec.GetName(),
-			UID:  clusterSpec.GetUID(),
+		clusterMeta := &appsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: r.Cluster.GetName(),
+				UID:  r.Cluster.GetUID(),
+			},
 		}
-		clusterSpec.Status = appsv1alpha1.ClusterStatus{}
-		b, _ := json.Marshal(*clusterSpec)
+		b, _ := json.Marshal(clusterMeta)
 		return string(b)
 	}
 
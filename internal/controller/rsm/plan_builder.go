@@ -23,9 +23,11 @@ import (
 	"errors"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
@@ -124,6 +126,18 @@ func (b *PlanBuilder) rsmWalkFunc(v graph.Vertex) error {
 		err := b.cli.Update(b.transCtx.Context, vertex.Obj)
 		if err != nil && !apierrors.IsNotFound(err) {
 			b.transCtx.Logger.Error(err, fmt.Sprintf("update %T error: %s", vertex.Obj, vertex.OriObj.GetName()))
+			// the rejected update never succeeds by retrying immediately, e.g. changing the immutable fields.
+			if intctrlutil.IsUpdateRejected(err) {
+				kind := fmt.Sprintf("%T", vertex.Obj)
+				if gvk, gvkErr := apiutil.GVKForObject(vertex.Obj, b.cli.Scheme()); gvkErr == nil {
+					kind = gvk.Kind
+				}
+				rejectedErr := intctrlutil.NewUpdateRejectedError(kind, vertex.Obj.GetName(), nil, err)
+				if b.transCtx.EventRecorder != nil && b.transCtx.rsm != nil {
+					b.transCtx.EventRecorder.Event(b.transCtx.rsm, corev1.EventTypeWarning, "UpdateRejected", rejectedErr.Error())
+				}
+				return rejectedErr
+			}
 			return err
 		}
 	case model.DELETE:
@@ -136,7 +150,11 @@ func (b *PlanBuilder) rsmWalkFunc(v graph.Vertex) error {
 			}
 		}
 		if !model.IsObjectDeleting(vertex.Obj) {
-			err := b.cli.Delete(b.transCtx.Context, vertex.Obj)
+			var opts []client.DeleteOption
+			if vertex.PropagationPolicy != nil {
+				opts = append(opts, *vertex.PropagationPolicy)
+			}
+			err := b.cli.Delete(b.transCtx.Context, vertex.Obj, opts...)
 			if err != nil && !apierrors.IsNotFound(err) {
 				return err
 			}
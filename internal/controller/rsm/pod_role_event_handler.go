@@ -134,9 +134,8 @@ func handleRoleChangedEvent(cli client.Client, reqCtx intctrlutil.RequestCtx, re
 			}
 		}
 
-		name, _ := intctrlutil.GetParentNameAndOrdinal(pod)
 		rsm := &workloads.ReplicatedStateMachine{}
-		if err := cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, rsm); err != nil {
+		if err := cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: pod.Namespace, Name: GetRSMNameOfPod(pod)}, rsm); err != nil {
 			return "", err
 		}
 		reqCtx.Log.V(1).Info("handle role change event", "pod", pod.Name, "role", role, "originalRole", message.OriginalRole)
@@ -148,6 +147,21 @@ func handleRoleChangedEvent(cli client.Client, reqCtx intctrlutil.RequestCtx, re
 	return role, nil
 }
 
+// GetRSMNameOfPod gets the name of the rsm which the pod belongs to, the parent name of the pod differs from
+// the rsm name if the pod name prefix is specified.
+func GetRSMNameOfPod(pod *corev1.Pod) string {
+	if name, ok := pod.Labels[workloadsInstanceLabelKey]; ok {
+		return name
+	}
+	clusterName, ok1 := pod.Labels[constant.AppInstanceLabelKey]
+	compName, ok2 := pod.Labels[constant.KBAppComponentLabelKey]
+	if ok1 && ok2 {
+		return fmt.Sprintf("%s-%s", clusterName, compName)
+	}
+	name, _ := intctrlutil.GetParentNameAndOrdinal(pod)
+	return name
+}
+
 func parseGlobalRoleSnapshot(role string, event *corev1.Event) *common.GlobalRoleSnapshot {
 	snapshot := &common.GlobalRoleSnapshot{}
 	if err := json.Unmarshal([]byte(role), snapshot); err == nil {
@@ -146,4 +146,22 @@ var _ = Describe("pod role label event handler test", func() {
 			Expect(parseProbeEventMessage(reqCtx, event)).Should(BeNil())
 		})
 	})
+
+	Context("GetRSMNameOfPod function", func() {
+		It("should work well", func() {
+			By("the pod without labels is resolved by its parent")
+			pod := builder.NewPodBuilder(namespace, getPodName(name, 0)).GetObject()
+			Expect(GetRSMNameOfPod(pod)).Should(Equal(name))
+
+			By("the pod named after the pod name prefix is resolved by its labels")
+			pod = builder.NewPodBuilder(namespace, "foo-mysql-0").
+				AddLabels(constant.AppInstanceLabelKey, "foo", constant.KBAppComponentLabelKey, "mysql-comp").
+				GetObject()
+			Expect(GetRSMNameOfPod(pod)).Should(Equal("foo-mysql-comp"))
+			pod = builder.NewPodBuilder(namespace, "foo-mysql-0").
+				AddLabels(workloadsInstanceLabelKey, name).
+				GetObject()
+			Expect(GetRSMNameOfPod(pod)).Should(Equal(name))
+		})
+	})
 })
@@ -173,7 +173,7 @@ func cleanAction(transCtx *rsmTransformContext, dag *graph.DAG) error {
 
 func isActionDone(rsm *workloads.ReplicatedStateMachine, action *batchv1.Job) bool {
 	ordinal, _ := getActionOrdinal(action.Name)
-	podName := getPodName(rsm.Name, ordinal)
+	podName := getPodName(GetStatefulSetName(rsm), ordinal)
 	membersStatus := rsm.Status.MembersStatus
 	switch action.Labels[jobTypeLabel] {
 	case jobTypeSwitchover:
@@ -209,9 +209,9 @@ func createNextAction(transCtx *rsmTransformContext, dag *graph.DAG, rsm *worklo
 	leader := getLeaderPodName(rsm.Status.MembersStatus)
 	ordinal := nextActionInfo.ordinal
 	if nextActionInfo.actionType == jobTypeSwitchover {
-		ordinal = 0
+		ordinal = getOrdinalStart(rsm)
 	}
-	target := getPodName(rsm.Name, ordinal)
+	target := getPodName(GetStatefulSetName(rsm), ordinal)
 	actionName := getActionName(rsm.Name, int(rsm.Generation), nextActionInfo.ordinal, nextActionInfo.actionType)
 	nextAction := buildAction(rsm, actionName, nextActionInfo.actionType, jobScenarioMembership, leader, target)
 
@@ -227,6 +227,7 @@ func createNextAction(transCtx *rsmTransformContext, dag *graph.DAG, rsm *worklo
 func generateActionInfoList(rsm *workloads.ReplicatedStateMachine) []*actionInfo {
 	var actionInfoList []*actionInfo
 	memberReadyReplicas := int32(len(rsm.Status.MembersStatus))
+	ordinalStart := getOrdinalStart(rsm)
 
 	switch {
 	case memberReadyReplicas < *rsm.Spec.Replicas:
@@ -234,7 +235,7 @@ func generateActionInfoList(rsm *workloads.ReplicatedStateMachine) []*actionInfo
 		// members with ordinal less than 'spec.replicas' should in the active cluster
 		actionTypeList := []string{jobTypeMemberJoinNotifying, jobTypeLogSync, jobTypePromote}
 		for i := memberReadyReplicas; i < *rsm.Spec.Replicas; i++ {
-			actionInfos := generateActionInfos(rsm, int(i), actionTypeList)
+			actionInfos := generateActionInfos(rsm, ordinalStart+int(i), actionTypeList)
 			actionInfoList = append(actionInfoList, actionInfos...)
 		}
 	case memberReadyReplicas > *rsm.Spec.Replicas:
@@ -242,7 +243,7 @@ func generateActionInfoList(rsm *workloads.ReplicatedStateMachine) []*actionInfo
 		// members with ordinal greater than 'spec.replicas - 1' should not in the active cluster
 		actionTypeList := []string{jobTypeSwitchover, jobTypeMemberLeaveNotifying}
 		for i := memberReadyReplicas - 1; i >= *rsm.Spec.Replicas; i-- {
-			actionInfos := generateActionInfos(rsm, int(i), actionTypeList)
+			actionInfos := generateActionInfos(rsm, ordinalStart+int(i), actionTypeList)
 			actionInfoList = append(actionInfoList, actionInfos...)
 		}
 	}
@@ -327,13 +328,14 @@ func abnormalAnalysis(rsm *workloads.ReplicatedStateMachine, action *batchv1.Job
 		statusMap[status.PodName] = status
 	}
 	ordinal, _ := getActionOrdinal(action.Name)
-	currentMembers := ordinal
+	ordinalStart := getOrdinalStart(rsm)
+	currentMembers := ordinal - ordinalStart
 	if isPreAction(action.Labels[jobTypeLabel]) {
-		currentMembers = ordinal + 1
+		currentMembers++
 	}
 	var abnormalPodList, leaderPodList []string
 	for i := 0; i < currentMembers; i++ {
-		podName := getPodName(rsm.Name, i)
+		podName := getPodName(GetStatefulSetName(rsm), ordinalStart+i)
 		status, ok := statusMap[podName]
 		if !ok {
 			abnormalPodList = append(abnormalPodList, podName)
@@ -364,7 +366,7 @@ func abnormalAnalysis(rsm *workloads.ReplicatedStateMachine, action *batchv1.Job
 func generateActionInfos(rsm *workloads.ReplicatedStateMachine, ordinal int, actionTypeList []string) []*actionInfo {
 	var actionInfos []*actionInfo
 	leaderPodName := getLeaderPodName(rsm.Status.MembersStatus)
-	podName := getPodName(rsm.Name, ordinal)
+	podName := getPodName(GetStatefulSetName(rsm), ordinal)
 	for _, actionType := range actionTypeList {
 		checker := func() bool {
 			return podName == leaderPodName
@@ -0,0 +1,220 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package rsm
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/graph"
+	"github.com/apecloud/kubeblocks/internal/controller/model"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
+)
+
+// NodeTerminationTransformer moves the members off the nodes to be terminated, e.g. the spot instances which received
+// the termination notice, instead of waiting for them to be killed. The nodes are recognized by the taints applied by
+// the termination handlers, e.g. the aws-node-termination-handler. The leader is switched over first if it's on such
+// a node, and then the pods are deleted to be rescheduled to the other nodes.
+type NodeTerminationTransformer struct{}
+
+var _ graph.Transformer = &NodeTerminationTransformer{}
+
+func (t *NodeTerminationTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*rsmTransformContext)
+	rsm := transCtx.rsm
+	if model.IsObjectDeleting(transCtx.rsmOrig) {
+		return nil
+	}
+	taintKeys := GetNodeTerminationTaintKeys()
+	if len(taintKeys) == 0 {
+		return nil
+	}
+
+	// the underlying sts may not be created yet
+	stsObj := &apps.StatefulSet{}
+	if err := transCtx.Client.Get(transCtx.Context, types.NamespacedName{Namespace: rsm.Namespace, Name: GetStatefulSetName(rsm)}, stsObj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	pods, err := getPodsOfStatefulSet(transCtx.Context, transCtx.Client, stsObj)
+	if err != nil {
+		return err
+	}
+	podsToMove, err := getPodsOnTerminatingNodes(transCtx, pods, taintKeys)
+	if err != nil || len(podsToMove) == 0 {
+		return err
+	}
+
+	// do switchover if the leader is on a terminating node
+	switch shouldWaitNextLoop, err := doNodeTerminationSwitchoverIfNeeded(transCtx, dag, pods, podsToMove); {
+	case err != nil:
+		return err
+	case shouldWaitNextLoop:
+		return nil
+	}
+
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	podNames := make([]string, 0, len(podsToMove))
+	for _, pod := range podsToMove {
+		graphCli.Delete(dag, pod)
+		podNames = append(podNames, pod.Name)
+	}
+	transCtx.Logger.Info("move the pods off the terminating nodes", "pods", podNames)
+	return nil
+}
+
+// GetNodeTerminationTaintKeys returns the configured taint keys of the nodes to be terminated.
+func GetNodeTerminationTaintKeys() []string {
+	var taintKeys []string
+	for _, key := range strings.Split(viper.GetString(constant.CfgKeyDataPlaneNodeTerminationTaintKeys), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			taintKeys = append(taintKeys, key)
+		}
+	}
+	return taintKeys
+}
+
+// IsNodeTerminating checks whether the node is to be terminated by the termination taints. As the deleted pods may be
+// rescheduled to the same node, the node is taken as terminating only after it's cordoned, or the termination taint
+// forbids the scheduling.
+func IsNodeTerminating(node *corev1.Node, taintKeys []string) bool {
+	terminating, unschedulable := false, node.Spec.Unschedulable
+	for _, taint := range node.Spec.Taints {
+		if !slices.Contains(taintKeys, taint.Key) {
+			continue
+		}
+		terminating = true
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			unschedulable = true
+		}
+	}
+	return terminating && unschedulable
+}
+
+// getPodsOnTerminatingNodes returns the pods on the terminating nodes, the ones being deleted are excluded.
+func getPodsOnTerminatingNodes(transCtx *rsmTransformContext, pods []corev1.Pod, taintKeys []string) ([]*corev1.Pod, error) {
+	terminatingNodes := map[string]bool{}
+	var podsOnTerminatingNodes []*corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if len(pod.Spec.NodeName) == 0 || pod.DeletionTimestamp != nil {
+			continue
+		}
+		terminating, ok := terminatingNodes[pod.Spec.NodeName]
+		if !ok {
+			node := &corev1.Node{}
+			if err := transCtx.Client.Get(transCtx.Context, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					return nil, err
+				}
+				// the node is gone, so are the pods on it
+				terminatingNodes[pod.Spec.NodeName] = false
+				continue
+			}
+			terminating = IsNodeTerminating(node, taintKeys)
+			terminatingNodes[pod.Spec.NodeName] = terminating
+		}
+		if terminating {
+			podsOnTerminatingNodes = append(podsOnTerminatingNodes, pod)
+		}
+	}
+	return podsOnTerminatingNodes, nil
+}
+
+// return true means action created or in progress, should wait it to the termination state
+func doNodeTerminationSwitchoverIfNeeded(transCtx *rsmTransformContext, dag *graph.DAG, pods []corev1.Pod, podsToMove []*corev1.Pod) (bool, error) {
+	rsm := transCtx.rsm
+	if !shouldSwitchover(rsm, podsToMove, pods) {
+		return false, nil
+	}
+
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	actionList, err := getActionList(transCtx, jobScenarioNodeTermination)
+	if err != nil {
+		return true, err
+	}
+	if len(actionList) == 0 {
+		target := selectNodeTerminationSwitchoverTarget(pods, podsToMove)
+		if len(target) == 0 {
+			transCtx.Logger.Info("all members are on the terminating nodes, there is no member to switch over to")
+			return false, nil
+		}
+		return true, createNodeTerminationSwitchoverAction(dag, graphCli, rsm, pods, target)
+	}
+
+	// same as the switchover in the update, the pods are moved after the action terminated whether it succeeded
+	// or not, as the nodes are terminated anyway.
+	action := actionList[0]
+	switch {
+	case action.Status.Succeeded == 0 && action.Status.Failed == 0:
+		// action in progress, wait
+		return true, nil
+	case action.Status.Failed > 0:
+		emitActionFailedEvent(transCtx, jobTypeSwitchover, action.Name)
+		fallthrough
+	case action.Status.Succeeded > 0:
+		// clean up the action
+		doActionCleanup(dag, graphCli, action)
+	}
+	return false, nil
+}
+
+func createNodeTerminationSwitchoverAction(dag *graph.DAG, cli model.GraphClient, rsm *workloads.ReplicatedStateMachine, pods []corev1.Pod, target string) error {
+	leader := getLeaderPodName(rsm.Status.MembersStatus)
+	ordinal, _ := getPodOrdinal(leader)
+	// the leader may move to a terminating node again in the same generation, the uid of the leader pod tells
+	// the actions apart.
+	var leaderUID string
+	for _, pod := range pods {
+		if pod.Name == leader {
+			leaderUID = string(pod.UID)
+		}
+	}
+	if len(leaderUID) > 8 {
+		leaderUID = leaderUID[:8]
+	}
+	actionName := fmt.Sprintf("%s-%s", getActionName(rsm.Name, int(rsm.Generation), ordinal, jobScenarioNodeTermination), leaderUID)
+	action := buildAction(rsm, actionName, jobTypeSwitchover, jobScenarioNodeTermination, leader, target)
+	return createAction(dag, cli, rsm, action)
+}
+
+// selectNodeTerminationSwitchoverTarget selects the member not on the terminating nodes, the one with role is preferred.
+func selectNodeTerminationSwitchoverTarget(pods []corev1.Pod, podsToMove []*corev1.Pod) string {
+	var target string
+	for _, pod := range pods {
+		if slices.ContainsFunc(podsToMove, func(p *corev1.Pod) bool { return p.Name == pod.Name }) {
+			continue
+		}
+		if _, ok := pod.Labels[roleLabelKey]; ok {
+			return pod.Name
+		}
+		if len(target) == 0 {
+			target = pod.Name
+		}
+	}
+	return target
+}
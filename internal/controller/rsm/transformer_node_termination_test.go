@@ -0,0 +1,252 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package rsm
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/controller/builder"
+	viper "github.com/apecloud/kubeblocks/internal/viperx"
+)
+
+var _ = Describe("node termination transformer test.", func() {
+	const (
+		taintKey         = "aws-node-termination-handler/spot-itn"
+		terminatingNode  = "node-1"
+		leaderPodUID     = "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"
+		leaderPodUIDHead = "1b2c3d4e"
+	)
+
+	var pod0, pod1, pod2 *corev1.Pod
+
+	expectSts := func() {
+		k8sMock.EXPECT().
+			Get(gomock.Any(), gomock.Any(), &apps.StatefulSet{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, objKey client.ObjectKey, obj *apps.StatefulSet, _ ...client.GetOption) error {
+				Expect(obj).ShouldNot(BeNil())
+				obj.Namespace = objKey.Namespace
+				obj.Name = objKey.Name
+				obj.Spec.Replicas = rsm.Spec.Replicas
+				return nil
+			}).Times(1)
+	}
+
+	expectPods := func() {
+		k8sMock.EXPECT().
+			List(gomock.Any(), &corev1.PodList{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, list *corev1.PodList, _ ...client.ListOption) error {
+				Expect(list).ShouldNot(BeNil())
+				list.Items = []corev1.Pod{*pod0, *pod1, *pod2}
+				return nil
+			}).Times(1)
+	}
+
+	expectNodes := func(terminatingNodes ...string) {
+		k8sMock.EXPECT().
+			Get(gomock.Any(), gomock.Any(), &corev1.Node{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, objKey client.ObjectKey, obj *corev1.Node, _ ...client.GetOption) error {
+				Expect(obj).ShouldNot(BeNil())
+				obj.Name = objKey.Name
+				for _, node := range terminatingNodes {
+					if node == objKey.Name {
+						obj.Spec.Taints = []corev1.Taint{{Key: taintKey, Effect: corev1.TaintEffectNoSchedule}}
+					}
+				}
+				return nil
+			}).Times(3)
+	}
+
+	expectActions := func(actions ...batchv1.Job) {
+		k8sMock.EXPECT().
+			List(gomock.Any(), &batchv1.JobList{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, list *batchv1.JobList, _ ...client.ListOption) error {
+				Expect(list).ShouldNot(BeNil())
+				list.Items = actions
+				return nil
+			}).Times(1)
+	}
+
+	BeforeEach(func() {
+		viper.Set(constant.CfgKeyDataPlaneNodeTerminationTaintKeys, taintKey)
+
+		rsm = builder.NewReplicatedStateMachineBuilder(namespace, name).
+			SetUID(uid).
+			SetReplicas(3).
+			SetRoles(roles).
+			SetMembershipReconfiguration(&reconfiguration).
+			SetService(service).
+			GetObject()
+		rsm.Status.MembersStatus = []workloads.MemberStatus{
+			{
+				PodName:     getPodName(rsm.Name, 1),
+				ReplicaRole: workloads.ReplicaRole{Name: "leader", IsLeader: true},
+			},
+			{
+				PodName:     getPodName(rsm.Name, 0),
+				ReplicaRole: workloads.ReplicaRole{Name: "follower"},
+			},
+			{
+				PodName:     getPodName(rsm.Name, 2),
+				ReplicaRole: workloads.ReplicaRole{Name: "follower"},
+			},
+		}
+
+		pod0 = builder.NewPodBuilder(namespace, getPodName(name, 0)).
+			AddLabels(roleLabelKey, "follower").
+			GetObject()
+		pod0.Spec.NodeName = "node-0"
+		pod1 = builder.NewPodBuilder(namespace, getPodName(name, 1)).
+			SetUID(leaderPodUID).
+			AddLabels(roleLabelKey, "leader").
+			GetObject()
+		pod1.Spec.NodeName = "node-1"
+		pod2 = builder.NewPodBuilder(namespace, getPodName(name, 2)).
+			AddLabels(roleLabelKey, "follower").
+			GetObject()
+		pod2.Spec.NodeName = "node-2"
+
+		transCtx = &rsmTransformContext{
+			Context:       ctx,
+			Client:        graphCli,
+			EventRecorder: nil,
+			Logger:        logger,
+			rsmOrig:       rsm.DeepCopy(),
+			rsm:           rsm,
+		}
+
+		dag = mockDAG()
+		transformer = &NodeTerminationTransformer{}
+	})
+
+	AfterEach(func() {
+		viper.Set(constant.CfgKeyDataPlaneNodeTerminationTaintKeys, "")
+	})
+
+	Context("IsNodeTerminating", func() {
+		It("should work well", func() {
+			taintKeys := []string{taintKey}
+			node := &corev1.Node{}
+			Expect(IsNodeTerminating(node, taintKeys)).Should(BeFalse())
+
+			By("the taint doesn't forbid the scheduling")
+			node.Spec.Taints = []corev1.Taint{{Key: taintKey, Effect: corev1.TaintEffectPreferNoSchedule}}
+			Expect(IsNodeTerminating(node, taintKeys)).Should(BeFalse())
+
+			By("the node is cordoned")
+			node.Spec.Unschedulable = true
+			Expect(IsNodeTerminating(node, taintKeys)).Should(BeTrue())
+
+			By("the node is cordoned without the termination taint")
+			node.Spec.Taints = []corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoSchedule}}
+			Expect(IsNodeTerminating(node, taintKeys)).Should(BeFalse())
+
+			By("the taint forbids the scheduling")
+			node.Spec.Unschedulable = false
+			node.Spec.Taints = []corev1.Taint{{Key: taintKey, Effect: corev1.TaintEffectNoExecute}}
+			Expect(IsNodeTerminating(node, taintKeys)).Should(BeTrue())
+		})
+	})
+
+	Context("no node is terminating", func() {
+		It("should do nothing", func() {
+			expectSts()
+			expectPods()
+			expectNodes()
+			dagExpected := mockDAG()
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(dag.Equals(dagExpected, less)).Should(BeTrue())
+		})
+	})
+
+	Context("a follower is on the terminating node", func() {
+		It("should delete the pod directly", func() {
+			expectSts()
+			expectPods()
+			expectNodes("node-2")
+			dagExpected := mockDAG()
+			graphCli.Delete(dagExpected, pod2)
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(dag.Equals(dagExpected, less)).Should(BeTrue())
+		})
+	})
+
+	Context("the leader is on the terminating node", func() {
+		It("should switchover before deleting the pod", func() {
+			actionName := getActionName(rsm.Name, int(rsm.Generation), 1, jobScenarioNodeTermination) + "-" + leaderPodUIDHead
+
+			By("create the switchover action")
+			expectSts()
+			expectPods()
+			expectNodes(terminatingNode)
+			expectActions()
+			dagExpected := mockDAG()
+			action := builder.NewJobBuilder(namespace, actionName).GetObject()
+			graphCli.Create(dagExpected, action)
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(dag.Equals(dagExpected, less)).Should(BeTrue())
+
+			By("wait the switchover action to finish")
+			action = builder.NewJobBuilder(namespace, actionName).
+				AddLabelsInMap(map[string]string{
+					constant.AppInstanceLabelKey: rsm.Name,
+					constant.KBManagedByKey:      kindReplicatedStateMachine,
+					jobScenarioLabel:             jobScenarioNodeTermination,
+					jobTypeLabel:                 jobTypeSwitchover,
+					jobHandledLabel:              jobHandledFalse,
+				}).
+				SetSuspend(false).
+				GetObject()
+			expectSts()
+			expectPods()
+			expectNodes(terminatingNode)
+			expectActions(*action)
+			dagExpected = mockDAG()
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(dag.Equals(dagExpected, less)).Should(BeTrue())
+
+			By("delete the pod after the switchover succeeded")
+			action.Status.Succeeded = 1
+			expectSts()
+			expectPods()
+			expectNodes(terminatingNode)
+			expectActions(*action)
+			dagExpected = mockDAG()
+			graphCli.Update(dagExpected, action, action)
+			graphCli.Delete(dagExpected, pod1)
+			dag = mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			Expect(dag.Equals(dagExpected, less)).Should(BeTrue())
+		})
+	})
+})
@@ -21,6 +21,7 @@ package rsm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -30,6 +31,7 @@ import (
 	"golang.org/x/exp/slices"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -106,6 +108,49 @@ func (t *ObjectGenerationTransformer) Transform(ctx graph.TransformContext, dag
 			cli.Create(dag, newSnapshot[name])
 		}
 	}
+	// the selector of StatefulSet is immutable, recreate the StatefulSet if it's mismatched and the rsm allows that.
+	handleStsSelectorMismatch := func() error {
+		name, err := model.GetGVKName(sts)
+		if err != nil {
+			return err
+		}
+		oldSts, ok := oldSnapshot[*name].(*apps.StatefulSet)
+		if !ok || !updateSet.Has(*name) || !isStsSelectorMismatched(oldSts, sts) {
+			return nil
+		}
+		if rsm.Annotations[RecreateOnSelectorMismatchAnnotationKey] != "true" {
+			message := fmt.Sprintf("selector of StatefulSet %s mismatches, set annotation %s=true to recreate it", oldSts.Name, RecreateOnSelectorMismatchAnnotationKey)
+			if transCtx.EventRecorder != nil {
+				transCtx.EventRecorder.Event(rsm, corev1.EventTypeWarning, selectorMismatchEventReason, message)
+			}
+			return errors.New(message)
+		}
+		// relabel the pods to make sure they can be adopted by the new StatefulSet
+		pods, err := getPodsOfStatefulSet(transCtx.Context, transCtx.Client, oldSts)
+		if err != nil {
+			return err
+		}
+		for i := range pods {
+			pod := &pods[i]
+			podCopy := pod.DeepCopy()
+			for k, v := range sts.Spec.Selector.MatchLabels {
+				if podCopy.Labels == nil {
+					podCopy.Labels = make(map[string]string)
+				}
+				podCopy.Labels[k] = v
+			}
+			if !reflect.DeepEqual(pod.Labels, podCopy.Labels) {
+				cli.Update(dag, pod, podCopy)
+			}
+		}
+		// orphan the pods, the StatefulSet will be re-created in the next reconciliation.
+		cli.Delete(dag, oldSts, model.WithPropagationPolicy(client.PropagationPolicy(metav1.DeletePropagationOrphan)))
+		updateSet.Delete(*name)
+		if transCtx.EventRecorder != nil {
+			transCtx.EventRecorder.Eventf(rsm, corev1.EventTypeNormal, recreateStsEventReason, "recreating StatefulSet %s with the new selector", oldSts.Name)
+		}
+		return nil
+	}
 	updateObjects := func() {
 		for name := range updateSet {
 			oldObj := oldSnapshot[name]
@@ -128,6 +173,10 @@ func (t *ObjectGenerationTransformer) Transform(ctx graph.TransformContext, dag
 		cli.DependOn(dag, sts, svc, headLessSvc, envConfig)
 	}
 
+	// StatefulSet with mismatched selector
+	if err := handleStsSelectorMismatch(); err != nil {
+		return err
+	}
 	// objects to be created
 	createNewObjects()
 	// objects to be updated
@@ -167,6 +216,8 @@ func copyAndMerge(oldObj, newObj client.Object) client.Object {
 	copyAndMergeSts := func(oldSts, newSts *apps.StatefulSet) client.Object {
 		mergeMetadataMap(oldSts.Labels, &newSts.Labels)
 		oldSts.Labels = newSts.Labels
+		mergeMetadataMap(oldSts.Annotations, &newSts.Annotations)
+		oldSts.Annotations = newSts.Annotations
 		// if annotations exist and are replaced, the StatefulSet will be updated.
 		mergeMetadataMap(oldSts.Spec.Template.Annotations, &newSts.Spec.Template.Annotations)
 		oldSts.Spec.Template = newSts.Spec.Template
@@ -208,7 +259,11 @@ func buildSvc(rsm workloads.ReplicatedStateMachine) *corev1.Service {
 	annotations := ParseAnnotationsOfScope(ServiceScope, rsm.Annotations)
 	labels := getLabels(&rsm)
 	selectors := getSvcSelector(&rsm, false)
-	return builder.NewServiceBuilder(rsm.Namespace, rsm.Name).
+	// the service may be backed by a role other than the leader.
+	if role, ok := rsm.Spec.Service.Spec.Selector[constant.RoleLabelKey]; ok {
+		selectors[constant.RoleLabelKey] = role
+	}
+	svc := builder.NewServiceBuilder(rsm.Namespace, rsm.Name).
 		AddAnnotationsInMap(annotations).
 		AddLabelsInMap(rsm.Spec.Service.Labels).
 		AddLabelsInMap(labels).
@@ -216,6 +271,9 @@ func buildSvc(rsm workloads.ReplicatedStateMachine) *corev1.Service {
 		AddPorts(rsm.Spec.Service.Spec.Ports...).
 		SetType(rsm.Spec.Service.Spec.Type).
 		GetObject()
+	svc.Spec.SessionAffinity = rsm.Spec.Service.Spec.SessionAffinity
+	svc.Spec.SessionAffinityConfig = rsm.Spec.Service.Spec.SessionAffinityConfig.DeepCopy()
+	return svc
 }
 
 func buildAlternativeSvs(rsm workloads.ReplicatedStateMachine) []*corev1.Service {
@@ -282,7 +340,7 @@ func buildSts(rsm workloads.ReplicatedStateMachine, headlessSvcName string, envC
 	template := buildStsPodTemplate(rsm, envConfig)
 	annotations := ParseAnnotationsOfScope(RootScope, rsm.Annotations)
 	labels := getLabels(&rsm)
-	return builder.NewStatefulSetBuilder(rsm.Namespace, rsm.Name).
+	return builder.NewStatefulSetBuilder(rsm.Namespace, GetStatefulSetName(&rsm)).
 		AddLabelsInMap(labels).
 		AddLabels(rsmGenerationLabelKey, strconv.FormatInt(rsm.Generation, 10)).
 		AddAnnotationsInMap(annotations).
@@ -293,6 +351,7 @@ func buildSts(rsm workloads.ReplicatedStateMachine, headlessSvcName string, envC
 		SetVolumeClaimTemplates(rsm.Spec.VolumeClaimTemplates...).
 		SetTemplate(*template).
 		SetUpdateStrategy(rsm.Spec.UpdateStrategy).
+		SetOrdinals(rsm.Spec.Ordinals).
 		GetObject()
 }
 
@@ -621,10 +680,11 @@ func buildEnvConfigData(set workloads.ReplicatedStateMachine) map[string]string
 	svcName := getHeadlessSvcName(set)
 	uid := string(set.UID)
 	strReplicas := strconv.Itoa(int(*set.Spec.Replicas))
+	ordinalStart := getOrdinalStart(&set)
 	generateReplicaEnv := func(prefix string) {
 		for i := 0; i < int(*set.Spec.Replicas); i++ {
 			hostNameTplKey := prefix + strconv.Itoa(i) + "_HOSTNAME"
-			hostNameTplValue := set.Name + "-" + strconv.Itoa(i)
+			hostNameTplValue := getPodName(GetStatefulSetName(&set), ordinalStart+i)
 			envData[hostNameTplKey] = fmt.Sprintf("%s.%s", hostNameTplValue, svcName)
 		}
 	}
@@ -21,6 +21,7 @@ package rsm
 
 import (
 	"context"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -28,11 +29,14 @@ import (
 	"github.com/golang/mock/gomock"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	"github.com/apecloud/kubeblocks/internal/controller/builder"
+	"github.com/apecloud/kubeblocks/internal/controller/model"
 )
 
 var _ = Describe("object generation transformer test.", func() {
@@ -100,6 +104,133 @@ var _ = Describe("object generation transformer test.", func() {
 		})
 	})
 
+	Context("service backed by a custom role", func() {
+		It("should select the pods of the role", func() {
+			By("select the leader by default")
+			svc := buildSvc(*rsm)
+			Expect(svc.Spec.Selector[constant.RoleLabelKey]).Should(Equal("leader"))
+
+			By("select the role declared by the service")
+			rsm.Spec.Service = service.DeepCopy()
+			rsm.Spec.Service.Spec.Selector = map[string]string{constant.RoleLabelKey: "learner"}
+			svc = buildSvc(*rsm)
+			Expect(svc.Spec.Selector[constant.RoleLabelKey]).Should(Equal("learner"))
+		})
+	})
+
+	Context("service with session affinity", func() {
+		It("should keep the session affinity", func() {
+			rsm.Spec.Service = service.DeepCopy()
+			rsm.Spec.Service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+			rsm.Spec.Service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+				ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: pointer.Int32(corev1.DefaultClientIPServiceAffinitySeconds)},
+			}
+			svc := buildSvc(*rsm)
+			Expect(svc.Spec.SessionAffinity).Should(Equal(corev1.ServiceAffinityClientIP))
+			Expect(svc.Spec.SessionAffinityConfig).Should(Equal(rsm.Spec.Service.Spec.SessionAffinityConfig))
+		})
+	})
+
+	Context("pod name prefix", func() {
+		It("should name the StatefulSet and the pods after the prefix", func() {
+			rsm.Spec.PodNamePrefix = "foo-mysql"
+			headlessSvcName := getHeadlessSvcName(*rsm)
+			envConfig := buildEnvConfigMap(*rsm)
+
+			By("the StatefulSet is named after the prefix")
+			sts := buildSts(*rsm, headlessSvcName, *envConfig)
+			Expect(sts.Name).Should(Equal(rsm.Spec.PodNamePrefix))
+
+			By("the hostnames of the replicas are derived from the prefix")
+			hostnames := 0
+			for k, v := range envConfig.Data {
+				if !strings.HasSuffix(k, "_HOSTNAME") {
+					continue
+				}
+				hostnames++
+				Expect(v).Should(HavePrefix(rsm.Spec.PodNamePrefix + "-"))
+				Expect(v).Should(HaveSuffix("." + headlessSvcName))
+			}
+			Expect(hostnames).ShouldNot(BeZero())
+
+			By("the services keep being named after the rsm")
+			Expect(buildSvc(*rsm).Name).Should(Equal(rsm.Name))
+			Expect(buildHeadlessSvc(*rsm).Name).Should(Equal(headlessSvcName))
+		})
+	})
+
+	Context("StatefulSet selector mismatched", func() {
+		var oldSts *apps.StatefulSet
+
+		BeforeEach(func() {
+			oldSts = builder.NewStatefulSetBuilder(namespace, name).
+				AddMatchLabel("mismatched", "true").
+				GetObject()
+			k8sMock.EXPECT().
+				List(gomock.Any(), &apps.StatefulSetList{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, list *apps.StatefulSetList, _ ...client.ListOption) error {
+					list.Items = []apps.StatefulSet{*oldSts}
+					return nil
+				}).Times(1)
+			k8sMock.EXPECT().
+				List(gomock.Any(), &corev1.ServiceList{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, list *corev1.ServiceList, _ ...client.ListOption) error {
+					return nil
+				}).Times(1)
+			k8sMock.EXPECT().
+				List(gomock.Any(), &corev1.ConfigMapList{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, list *corev1.ConfigMapList, _ ...client.ListOption) error {
+					return nil
+				}).Times(1)
+		})
+
+		It("should be detected", func() {
+			Expect(isStsSelectorMismatched(oldSts, buildSts(*rsm, getHeadlessSvcName(*rsm), corev1.ConfigMap{}))).Should(BeTrue())
+			Expect(isStsSelectorMismatched(oldSts, oldSts.DeepCopy())).Should(BeFalse())
+		})
+
+		It("should fail without the recreation annotation", func() {
+			dag := mockDAG()
+			err := transformer.Transform(transCtx, dag)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring(RecreateOnSelectorMismatchAnnotationKey))
+		})
+
+		It("should orphan delete the StatefulSet with the recreation annotation", func() {
+			rsm.Annotations = map[string]string{RecreateOnSelectorMismatchAnnotationKey: "true"}
+			pod := builder.NewPodBuilder(namespace, getPodName(name, 0)).GetObject()
+			k8sMock.EXPECT().
+				List(gomock.Any(), &corev1.PodList{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, list *corev1.PodList, _ ...client.ListOption) error {
+					list.Items = []corev1.Pod{*pod}
+					return nil
+				}).Times(1)
+
+			dag := mockDAG()
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+
+			var stsVertex, podVertex *model.ObjectVertex
+			for _, v := range dag.Vertices() {
+				objVertex, _ := v.(*model.ObjectVertex)
+				switch objVertex.Obj.(type) {
+				case *apps.StatefulSet:
+					stsVertex = objVertex
+				case *corev1.Pod:
+					podVertex = objVertex
+				}
+			}
+			Expect(stsVertex).ShouldNot(BeNil())
+			Expect(*stsVertex.Action).Should(Equal(model.DELETE))
+			Expect(stsVertex.PropagationPolicy).ShouldNot(BeNil())
+			Expect(*stsVertex.PropagationPolicy).Should(Equal(client.PropagationPolicy(metav1.DeletePropagationOrphan)))
+			Expect(podVertex).ShouldNot(BeNil())
+			Expect(*podVertex.Action).Should(Equal(model.UPDATE))
+			for k, v := range rsm.Spec.Selector.MatchLabels {
+				Expect(podVertex.Obj.GetLabels()).Should(HaveKeyWithValue(k, v))
+			}
+		})
+	})
+
 	Context("buildEnvConfigData function", func() {
 		It("should work well", func() {
 			By("build env config data")
@@ -23,7 +23,7 @@ import (
 	"strconv"
 
 	apps "k8s.io/api/apps/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
 	"github.com/apecloud/kubeblocks/internal/controller/model"
@@ -53,7 +53,7 @@ func (t *ObjectStatusTransformer) Transform(ctx graph.TransformContext, dag *gra
 	case model.IsObjectStatusUpdating(rsmOrig):
 		// read the underlying sts
 		sts := &apps.StatefulSet{}
-		if err := transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(rsm), sts); err != nil {
+		if err := transCtx.Client.Get(transCtx.Context, types.NamespacedName{Namespace: rsm.Namespace, Name: GetStatefulSetName(rsm)}, sts); err != nil {
 			return err
 		}
 		// keep rsm's ObservedGeneration to avoid override by sts's ObservedGeneration
@@ -22,7 +22,7 @@ package rsm
 import (
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/apimachinery/pkg/types"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
@@ -44,7 +44,7 @@ func (t *UpdateStrategyTransformer) Transform(ctx graph.TransformContext, dag *g
 
 	// read the underlying sts
 	stsObj := &apps.StatefulSet{}
-	if err := transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(rsm), stsObj); err != nil {
+	if err := transCtx.Client.Get(transCtx.Context, types.NamespacedName{Namespace: rsm.Namespace, Name: GetStatefulSetName(rsm)}, stsObj); err != nil {
 		return err
 	}
 	// read all pods belong to the sts, hence belong to the rsm
@@ -79,6 +79,12 @@ func (t *UpdateStrategyTransformer) Transform(ctx graph.TransformContext, dag *g
 	if err != nil {
 		return err
 	}
+	// update the pods in batches if necessary to keep the quorum, the leader is switched over and updated at last.
+	if podsInQuorum := keepQuorum(*rsm, pods, podsToBeUpdated); len(podsInQuorum) < len(podsToBeUpdated) {
+		transCtx.Logger.Info("wait for the updated pods to be ready to keep the quorum",
+			"pods to be updated", len(podsToBeUpdated), "pods to update now", len(podsInQuorum))
+		podsToBeUpdated = podsInQuorum
+	}
 
 	// do switchover if leader in pods to be updated
 	switch shouldWaitNextLoop, err := doSwitchoverIfNeeded(transCtx, dag, pods, podsToBeUpdated); {
@@ -145,7 +151,7 @@ func doSwitchoverIfNeeded(transCtx *rsmTransformContext, dag *graph.DAG, pods []
 func createSwitchoverAction(dag *graph.DAG, cli model.GraphClient, rsm *workloads.ReplicatedStateMachine, pods []corev1.Pod) error {
 	leader := getLeaderPodName(rsm.Status.MembersStatus)
 	targetOrdinal := selectSwitchoverTarget(rsm, pods)
-	target := getPodName(rsm.Name, targetOrdinal)
+	target := getPodName(GetStatefulSetName(rsm), targetOrdinal)
 	actionType := jobTypeSwitchover
 	ordinal, _ := getPodOrdinal(leader)
 	actionName := getActionName(rsm.Name, int(rsm.Generation), ordinal, actionType)
@@ -43,6 +43,11 @@ const (
 	// labels, owner ref and finalizer of secondary resources will be generated by (not copied from) rsm.
 	FeatureGateRSMCompatibilityMode = "RSM_COMPATIBILITY_MODE"
 
+	// RecreateOnSelectorMismatchAnnotationKey allows the rsm controller to recreate the underlying StatefulSet
+	// when its immutable selector mismatches the one of rsm.
+	// the StatefulSet will be deleted with orphan propagation policy, and the orphaned pods will be adopted by the new one.
+	RecreateOnSelectorMismatchAnnotationKey = "workloads.kubeblocks.io/recreate-on-selector-mismatch"
+
 	workloadsManagedByLabelKey = "workloads.kubeblocks.io/managed-by"
 	workloadsInstanceLabelKey  = "workloads.kubeblocks.io/instance"
 
@@ -68,6 +73,7 @@ const (
 	jobTypePromote              = "promote"
 	jobScenarioMembership       = "membership-reconfiguration"
 	jobScenarioUpdate           = "pod-update"
+	jobScenarioNodeTermination  = "node-termination"
 
 	roleProbeContainerName        = "kb-role-probe"
 	roleProbeBinaryName           = "lorry"
@@ -94,6 +100,8 @@ const (
 	readinessProbeEventFieldPath  = "spec.containers{" + roleProbeContainerName + "}"
 	legacyEventFieldPath          = "spec.containers{kb-checkrole}"
 	checkRoleEventReason          = "checkRole"
+	selectorMismatchEventReason   = "SelectorMismatch"
+	recreateStsEventReason        = "RecreateStatefulSet"
 
 	actionSvcPortBase = int32(36500)
 )
@@ -21,8 +21,10 @@ package rsm
 
 import (
 	"errors"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/controller/graph"
@@ -191,6 +193,63 @@ func (p *realUpdatePlan) execute() ([]*corev1.Pod, error) {
 	return p.podsToBeUpdated, nil
 }
 
+// keepQuorum trims the pods to be updated, so the available voting members always form a quorum during the update.
+// The pods are kept in the order of the plan, so the leader is still the last one to be updated.
+// The update isn't gated if the quorum is lost already, or there are less than 3 voting members,
+// as these groups can't tolerate any unavailable member, and blocking the update may block their recovery.
+func keepQuorum(rsm workloads.ReplicatedStateMachine, pods []corev1.Pod, podsToBeUpdated []*corev1.Pod) []*corev1.Pod {
+	votingRoles := sets.New[string]()
+	nonVotingRoles := sets.New[string]()
+	hasLeader := false
+	for _, role := range rsm.Spec.Roles {
+		roleName := strings.ToLower(role.Name)
+		if role.CanVote {
+			votingRoles.Insert(roleName)
+		} else {
+			nonVotingRoles.Insert(roleName)
+		}
+		if role.IsLeader {
+			hasLeader = true
+		}
+	}
+	if !hasLeader {
+		return podsToBeUpdated
+	}
+
+	isAvailableVoter := func(pod *corev1.Pod) bool {
+		return pod.DeletionTimestamp.IsZero() && intctrlutil.PodIsReadyWithLabel(*pod) && votingRoles.Has(getRoleName(*pod))
+	}
+	voters, availableVoters := 0, 0
+	for i := range pods {
+		// the pods without role are taken as voters, they may be the voters which are restarting.
+		if nonVotingRoles.Has(getRoleName(pods[i])) {
+			continue
+		}
+		voters++
+		if isAvailableVoter(&pods[i]) {
+			availableVoters++
+		}
+	}
+	quorum := voters/2 + 1
+	if voters < 3 || availableVoters < quorum {
+		return podsToBeUpdated
+	}
+
+	// the voters can be unavailable at the same time without losing the quorum.
+	budget := availableVoters - quorum
+	var podsInQuorum []*corev1.Pod
+	for _, pod := range podsToBeUpdated {
+		if isAvailableVoter(pod) {
+			if budget == 0 {
+				break
+			}
+			budget--
+		}
+		podsInQuorum = append(podsInQuorum, pod)
+	}
+	return podsInQuorum
+}
+
 func newUpdatePlan(rsm workloads.ReplicatedStateMachine, pods []corev1.Pod) updatePlan {
 	return &realUpdatePlan{
 		rsm:  rsm,
@@ -158,4 +158,67 @@ var _ = Describe("update plan test.", func() {
 			checkPlan(expectedPlan)
 		})
 	})
+
+	Context("quorum gate", func() {
+		It("should update the leader last and keep the quorum", func() {
+			strategy := workloads.ParallelUpdateStrategy
+			rsm.Spec.MemberUpdateStrategy = &strategy
+			readyCondition := corev1.PodCondition{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionTrue,
+			}
+			podMap := map[string]*corev1.Pod{}
+			var podNames []string
+			for i := 0; i < 5; i++ {
+				role := "follower"
+				if i == 2 {
+					role = "leader"
+				}
+				pod := builder.NewPodBuilder(namespace, getPodName(name, i)).
+					AddLabels(roleLabelKey, role).
+					AddLabels(apps.StatefulSetRevisionLabel, oldRevision).
+					GetObject()
+				pod.Status.Conditions = []corev1.PodCondition{readyCondition}
+				podMap[pod.Name] = pod
+				podNames = append(podNames, pod.Name)
+			}
+			buildPodList := func() []corev1.Pod {
+				var pods []corev1.Pod
+				for _, podName := range podNames {
+					pods = append(pods, *podMap[podName])
+				}
+				return pods
+			}
+
+			quorum := len(podNames)/2 + 1
+			var updateOrder []string
+			for len(updateOrder) < len(podNames) {
+				pods := buildPodList()
+				plan := newUpdatePlan(*rsm, pods)
+				podsToBeUpdated, err := plan.execute()
+				Expect(err).Should(BeNil())
+				podsToBeUpdated = keepQuorum(*rsm, pods, podsToBeUpdated)
+				Expect(podsToBeUpdated).ShouldNot(BeEmpty())
+
+				By("check the pods not updated form a quorum")
+				podsUpdating := sets.New[string]()
+				for _, pod := range podsToBeUpdated {
+					podsUpdating.Insert(pod.Name)
+				}
+				available := 0
+				for _, pod := range pods {
+					if !podsUpdating.Has(pod.Name) {
+						available++
+					}
+				}
+				Expect(available).Should(BeNumerically(">=", quorum))
+
+				for _, pod := range podsToBeUpdated {
+					updateOrder = append(updateOrder, pod.Name)
+					makePodUpdateReady(newRevision, podMap[pod.Name])
+				}
+			}
+			Expect(updateOrder[len(updateOrder)-1]).Should(Equal(getPodName(name, 2)))
+		})
+	})
 })
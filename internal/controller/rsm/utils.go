@@ -238,6 +238,23 @@ func getPodsOfStatefulSet(ctx context.Context, cli roclient.ReadonlyClient, stsO
 	return pods, nil
 }
 
+// isStsSelectorMismatched checks whether the selector of the running StatefulSet mismatches the desired one.
+// as the selector of StatefulSet is immutable, the mismatch can't be repaired by an update.
+func isStsSelectorMismatched(oldSts, newSts *appsv1.StatefulSet) bool {
+	if oldSts == nil || newSts == nil {
+		return false
+	}
+	oldSelector, err := metav1.LabelSelectorAsSelector(oldSts.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	newSelector, err := metav1.LabelSelectorAsSelector(newSts.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return oldSelector.String() != newSelector.String()
+}
+
 func getHeadlessSvcName(rsm workloads.ReplicatedStateMachine) string {
 	return strings.Join([]string{rsm.Name, "headless"}, "-")
 }
@@ -289,6 +306,22 @@ func getPodName(parent string, ordinal int) string {
 	return fmt.Sprintf("%s-%d", parent, ordinal)
 }
 
+// GetStatefulSetName returns the name of the underlying StatefulSet, which is also the prefix of the pod names.
+func GetStatefulSetName(rsm *workloads.ReplicatedStateMachine) string {
+	if rsm.Spec.PodNamePrefix != "" {
+		return rsm.Spec.PodNamePrefix
+	}
+	return rsm.Name
+}
+
+// getOrdinalStart returns the ordinal of the first replica, pods are named from it.
+func getOrdinalStart(rsm *workloads.ReplicatedStateMachine) int {
+	if rsm.Spec.Ordinals == nil {
+		return 0
+	}
+	return int(rsm.Spec.Ordinals.Start)
+}
+
 func getActionName(parent string, generation, ordinal int, actionType string) string {
 	return fmt.Sprintf("%s-%d-%d-%s", parent, generation, ordinal, actionType)
 }
@@ -662,8 +695,9 @@ func IsRSMReady(rsm *workloads.ReplicatedStateMachine) bool {
 	if len(membersStatus) != int(*rsm.Spec.Replicas) {
 		return false
 	}
+	ordinalStart := getOrdinalStart(rsm)
 	for i := 0; i < int(*rsm.Spec.Replicas); i++ {
-		podName := getPodName(rsm.Name, i)
+		podName := getPodName(GetStatefulSetName(rsm), ordinalStart+i)
 		if !isMemberReady(podName, membersStatus) {
 			return false
 		}
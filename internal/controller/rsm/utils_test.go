@@ -157,6 +157,32 @@ var _ = Describe("utils test", func() {
 		})
 	})
 
+	Context("setMembersStatus function with pod name prefix", func() {
+		It("should map the members to the prefixed pods", func() {
+			rsm.Spec.PodNamePrefix = "foo-mysql"
+			pods := []corev1.Pod{
+				*builder.NewPodBuilder(namespace, getPodName(GetStatefulSetName(rsm), 0)).AddLabels(roleLabelKey, "follower").GetObject(),
+				*builder.NewPodBuilder(namespace, getPodName(GetStatefulSetName(rsm), 1)).AddLabels(roleLabelKey, "leader").GetObject(),
+			}
+			readyCondition := corev1.PodCondition{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionTrue,
+			}
+			pods[0].Status.Conditions = append(pods[0].Status.Conditions, readyCondition)
+			pods[1].Status.Conditions = append(pods[1].Status.Conditions, readyCondition)
+			replicas := int32(2)
+			rsm.Spec.Replicas = &replicas
+			setMembersStatus(rsm, pods)
+
+			Expect(rsm.Status.MembersStatus).Should(HaveLen(2))
+			Expect(rsm.Status.MembersStatus[0].PodName).Should(Equal("foo-mysql-1"))
+			Expect(rsm.Status.MembersStatus[0].Name).Should(Equal("leader"))
+			Expect(rsm.Status.MembersStatus[1].PodName).Should(Equal("foo-mysql-0"))
+			Expect(rsm.Status.MembersStatus[1].Name).Should(Equal("follower"))
+			Expect(getLeaderPodName(rsm.Status.MembersStatus)).Should(Equal("foo-mysql-1"))
+		})
+	})
+
 	Context("getRoleName function", func() {
 		It("should work well", func() {
 			pod := builder.NewPodBuilder(namespace, name).AddLabels(roleLabelKey, "LEADER").GetObject()
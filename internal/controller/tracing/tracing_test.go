@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNoopTracerProvider(t *testing.T) {
+	shutdown, err := SetupTracerProvider(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		_ = shutdown(context.Background())
+	}()
+	_, span := StartSpan(context.Background(), "test")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("expected non-recording span without an endpoint configured")
+	}
+}
+
+func TestEndSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	_, child := StartSpan(ctx, "child")
+	EndSpan(child, errors.New("mock error"))
+	EndSpan(parent, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	childSpan, parentSpan := spans[0], spans[1]
+	if childSpan.Parent.SpanID() != parentSpan.SpanContext.SpanID() {
+		t.Error("expected the child span to be a child of the parent span")
+	}
+	if childSpan.Status.Code != codes.Error || childSpan.Status.Description != "mock error" {
+		t.Errorf("unexpected child span status: %v", childSpan.Status)
+	}
+	if parentSpan.Status.Code != codes.Unset {
+		t.Errorf("unexpected parent span status: %v", parentSpan.Status)
+	}
+}
+
+func BenchmarkNoopSpan(b *testing.B) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		_, span := StartSpan(ctx, "noop")
+		EndSpan(span, nil)
+	}
+}
@@ -154,6 +154,41 @@ func IsRerender(configMap *corev1.ConfigMap, item v1alpha1.ConfigurationItemDeta
 	return false
 }
 
+// IsConfigDrifted checks if the ConfigMap is edited out of band, whose data differs from the rendered version.
+func IsConfigDrifted(configMap *corev1.ConfigMap) bool {
+	if configMap == nil {
+		return false
+	}
+	renderedHash, ok := configMap.Annotations[constant.CMInsCurrentConfigurationHashLabelKey]
+	if !ok {
+		return false
+	}
+	hash, err := util.ComputeHash(configMap.Data)
+	return err == nil && hash != renderedHash
+}
+
+// GetConfigDriftPolicy gets the policy to handle the out-of-band changes of the config ConfigMaps, it defaults to Flag.
+func GetConfigDriftPolicy(cluster *v1alpha1.Cluster) v1alpha1.ConfigDriftPolicy {
+	if cluster != nil && cluster.Annotations[constant.ConfigDriftPolicyAnnotationKey] == string(v1alpha1.RevertConfigDriftPolicy) {
+		return v1alpha1.RevertConfigDriftPolicy
+	}
+	return v1alpha1.FlagConfigDriftPolicy
+}
+
+// GetConfigTemplateUpgradePolicy gets the policy to apply the config template changes brought by the ClusterVersion,
+// it defaults to ReplaceWithNew.
+func GetConfigTemplateUpgradePolicy(cluster *v1alpha1.Cluster) v1alpha1.ConfigTemplateUpgradePolicy {
+	if cluster == nil {
+		return v1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy
+	}
+	switch policy := v1alpha1.ConfigTemplateUpgradePolicy(cluster.Annotations[constant.ConfigTemplateUpgradePolicyAnnotationKey]); policy {
+	case v1alpha1.UseExistingConfigTemplateUpgradePolicy, v1alpha1.ManualConfigTemplateUpgradePolicy:
+		return policy
+	default:
+		return v1alpha1.ReplaceWithNewConfigTemplateUpgradePolicy
+	}
+}
+
 // GetConfigSpecReconcilePhase gets the configuration phase
 func GetConfigSpecReconcilePhase(configMap *corev1.ConfigMap,
 	item v1alpha1.ConfigurationItemDetail,
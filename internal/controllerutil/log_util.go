@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// the standard fields of the structured logs, they are used by all controllers to correlate the logs of
+// the same object and the same reconciliation.
+const (
+	LogFieldCluster     = "cluster"
+	LogFieldComponent   = "component"
+	LogFieldOpsRequest  = "opsRequest"
+	LogFieldReconcileID = "reconcileID"
+)
+
+type reconcileIDKey struct{}
+
+// NewReconcileLogger derives the logger from the one in ctx with the given key-value pairs, and injects it into
+// the returned context, so the loggers derived from the context later carry the same fields.
+// A reconcileID is attached if there is none in the context yet, the controllers driven by the controller-runtime
+// have theirs already.
+func NewReconcileLogger(ctx context.Context, keysAndValues ...interface{}) (context.Context, logr.Logger) {
+	logger := log.FromContext(ctx)
+	if controller.ReconcileIDFromContext(ctx) == "" && ctx.Value(reconcileIDKey{}) == nil {
+		reconcileID := uuid.NewString()
+		ctx = context.WithValue(ctx, reconcileIDKey{}, reconcileID)
+		logger = logger.WithValues(LogFieldReconcileID, reconcileID)
+	}
+	if len(keysAndValues) > 0 {
+		logger = logger.WithValues(keysAndValues...)
+	}
+	return log.IntoContext(ctx, logger), logger
+}
+
+// WithLogValues returns a copy of the RequestCtx whose logger, and the logger in the context, carry the given
+// key-value pairs.
+func (r RequestCtx) WithLogValues(keysAndValues ...interface{}) RequestCtx {
+	if r.Ctx == nil {
+		r.Log = r.Log.WithValues(keysAndValues...)
+		return r
+	}
+	r.Ctx, r.Log = NewReconcileLogger(log.IntoContext(r.Ctx, r.Log), keysAndValues...)
+	return r
+}
+
+// ParseLogLevels parses the per-logger verbosity in the format of "name=level,name=level", the name is the
+// name of a controller, e.g. cluster, or the name of a logger, and the level is one of 'debug', 'info', 'error',
+// or an integer value > 0 which corresponds to the custom debug levels of increasing verbosity.
+func ParseLogLevels(value string) (map[string]zapcore.Level, error) {
+	levels := map[string]zapcore.Level{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, levelStr, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid log level %q, expect the format of name=level", item)
+		}
+		level := zapcore.InfoLevel
+		if err := level.UnmarshalText([]byte(levelStr)); err != nil {
+			v, err := strconv.Atoi(levelStr)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("invalid log level %q of %s", levelStr, name)
+			}
+			level = zapcore.Level(-v)
+		}
+		levels[name] = level
+	}
+	return levels, nil
+}
+
+// NewLevelOverrideCore wraps the core to override the verbosity of the logs of the given controllers and loggers.
+// The logs of a controller are recognized by the "controller" field attached by the controller-runtime, and the
+// logs of a logger are recognized by the logger name and its descendants. The others are filtered by the base level.
+// The level of the wrapped core should be no higher than the lowest of all levels.
+func NewLevelOverrideCore(core zapcore.Core, base zapcore.LevelEnabler, overrides map[string]zapcore.Level) zapcore.Core {
+	return &levelOverrideCore{Core: core, base: base, overrides: overrides}
+}
+
+// MinLogLevel returns the lowest of the base level and the overrides, it's the level the wrapped core should use.
+func MinLogLevel(base zapcore.Level, overrides map[string]zapcore.Level) zapcore.Level {
+	for _, level := range overrides {
+		if level < base {
+			base = level
+		}
+	}
+	return base
+}
+
+type levelOverrideCore struct {
+	zapcore.Core
+	base      zapcore.LevelEnabler
+	overrides map[string]zapcore.Level
+	// level is resolved from the "controller" field of the logger, it takes precedence over the logger name.
+	level *zapcore.Level
+}
+
+var _ zapcore.Core = &levelOverrideCore{}
+
+// Enabled is permissive as the logger name is unknown here, the entries are filtered in Check.
+func (c *levelOverrideCore) Enabled(lvl zapcore.Level) bool {
+	if c.level != nil {
+		return lvl >= *c.level
+	}
+	return c.Core.Enabled(lvl)
+}
+
+func (c *levelOverrideCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &levelOverrideCore{
+		Core:      c.Core.With(fields),
+		base:      c.base,
+		overrides: c.overrides,
+		level:     c.level,
+	}
+	for _, f := range fields {
+		if f.Key != "controller" || f.Type != zapcore.StringType {
+			continue
+		}
+		if level, ok := c.overrides[f.String]; ok {
+			clone.level = &level
+		}
+	}
+	return clone
+}
+
+func (c *levelOverrideCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levelEnabled(ent) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *levelOverrideCore) levelEnabled(ent zapcore.Entry) bool {
+	if c.level != nil {
+		return ent.Level >= *c.level
+	}
+	// the longest matched logger name wins.
+	name := ent.LoggerName
+	for name != "" {
+		if level, ok := c.overrides[name]; ok {
+			return ent.Level >= level
+		}
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			break
+		}
+		name = name[:idx]
+	}
+	return c.base.Enabled(ent.Level)
+}
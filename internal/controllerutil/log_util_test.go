@@ -0,0 +1,132 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/apecloud/kubeblocks/internal/testutil"
+)
+
+func TestNewReconcileLogger(t *testing.T) {
+	sink, logger := testutil.NewLogSink()
+	ctx := log.IntoContext(context.Background(), logger)
+
+	ctx, clusterLogger := NewReconcileLogger(ctx, LogFieldCluster, "default/mycluster")
+	clusterLogger.Info("reconcile cluster")
+	reqCtx := RequestCtx{Ctx: ctx, Log: clusterLogger}.WithLogValues(LogFieldComponent, "mysql")
+	reqCtx.Log.Info("reconcile component")
+	log.FromContext(reqCtx.Ctx).Info("from context")
+
+	records := sink.Records()
+	if len(records) != 3 {
+		t.Fatalf("expect 3 records, but got %d", len(records))
+	}
+	reconcileID := records[0].Fields[LogFieldReconcileID]
+	if reconcileID == "" {
+		t.Fatalf("expect the reconcileID attached")
+	}
+	for _, record := range records {
+		if record.Fields[LogFieldReconcileID] != reconcileID {
+			t.Errorf("expect the same reconcileID %s in record %q, but got %s", reconcileID, record.Message, record.Fields[LogFieldReconcileID])
+		}
+		if record.Fields[LogFieldCluster] != "default/mycluster" {
+			t.Errorf("expect the cluster field in record %q", record.Message)
+		}
+	}
+	for _, record := range records[1:] {
+		if record.Fields[LogFieldComponent] != "mysql" {
+			t.Errorf("expect the component field in record %q", record.Message)
+		}
+	}
+
+	// a new reconciliation has a new reconcileID.
+	_, anotherLogger := NewReconcileLogger(log.IntoContext(context.Background(), logger))
+	anotherLogger.Info("another reconcile")
+	records = sink.Records()
+	if id := records[len(records)-1].Fields[LogFieldReconcileID]; id == "" || id == reconcileID {
+		t.Errorf("expect a new reconcileID, but got %q", id)
+	}
+}
+
+func TestParseLogLevels(t *testing.T) {
+	levels, err := ParseLogLevels("cluster=debug, opsrequest=2,setup=error")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]zapcore.Level{
+		"cluster":    zapcore.DebugLevel,
+		"opsrequest": zapcore.Level(-2),
+		"setup":      zapcore.ErrorLevel,
+	}
+	if len(levels) != len(expected) {
+		t.Fatalf("expect %d levels, but got %d", len(expected), len(levels))
+	}
+	for name, level := range expected {
+		if levels[name] != level {
+			t.Errorf("expect level %s of %s, but got %s", level, name, levels[name])
+		}
+	}
+	if MinLogLevel(zapcore.InfoLevel, levels) != zapcore.Level(-2) {
+		t.Errorf("expect the min level -2")
+	}
+
+	for _, invalid := range []string{"cluster", "=debug", "cluster=verbose", "cluster=0"} {
+		if _, err := ParseLogLevels(invalid); err == nil {
+			t.Errorf("expect error for %q", invalid)
+		}
+	}
+}
+
+func TestLevelOverrideCore(t *testing.T) {
+	overrides := map[string]zapcore.Level{
+		"cluster": zapcore.Level(-2),
+		"setup":   zapcore.ErrorLevel,
+	}
+	core, logs := observer.New(MinLogLevel(zapcore.InfoLevel, overrides))
+	logger := zap.New(NewLevelOverrideCore(core, zapcore.InfoLevel, overrides))
+
+	logger.With(zap.String("controller", "cluster")).Check(zapcore.Level(-2), "cluster verbose").Write()
+	logger.With(zap.String("controller", "opsrequest")).Debug("opsrequest debug")
+	logger.With(zap.String("controller", "opsrequest")).Info("opsrequest info")
+	logger.Named("setup").Info("setup info")
+	logger.Named("setup").Named("sub").Error("setup error")
+	logger.Named("other").Debug("other debug")
+
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	expected := []string{"cluster verbose", "opsrequest info", "setup error"}
+	if len(messages) != len(expected) {
+		t.Fatalf("expect messages %v, but got %v", expected, messages)
+	}
+	for i := range expected {
+		if messages[i] != expected[i] {
+			t.Errorf("expect message %q, but got %q", expected[i], messages[i])
+		}
+	}
+}
@@ -189,6 +189,38 @@ func getContainerWithVolumeMount(containers []corev1.Container, volumeName strin
 	return mountContainers
 }
 
+// PodSpecRefersToSecret checks whether the pod refers to the secret by the envs or the volumes.
+func PodSpecRefersToSecret(podSpec *corev1.PodSpec, secretName string) bool {
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == secretName {
+			return true
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if source.Secret != nil && source.Secret.Name == secretName {
+				return true
+			}
+		}
+	}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for _, container := range containers {
+			for _, env := range container.Env {
+				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == secretName {
+					return true
+				}
+			}
+			for _, envFrom := range container.EnvFrom {
+				if envFrom.SecretRef != nil && envFrom.SecretRef.Name == secretName {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func GetVolumeMountByVolume(container *corev1.Container, volumeName string) *corev1.VolumeMount {
 	for _, volume := range container.VolumeMounts {
 		if volume.Name == volumeName {
@@ -20,10 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package controllerutil
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
+// updateRejectedRequeueDuration is the interval to retry the updates rejected by the API server.
+const updateRejectedRequeueDuration = time.Minute
+
 type RequeueError interface {
 	RequeueAfter() time.Duration
 	Reason() string
@@ -87,3 +93,66 @@ func (r *requeueError) Reason() string {
 }
 
 func (r *delayedRequeueError) Delayed() {}
+
+// UpdateRejectedError is returned if the API server rejects the update of an object as invalid or forbidden,
+// e.g. changing the immutable fields of a StatefulSet. Retrying immediately never succeeds,
+// so it's a RequeueError which retries at a fixed interval until the spec is corrected.
+type UpdateRejectedError struct {
+	Kind   string
+	Name   string
+	Fields []string
+	Err    error
+}
+
+var _ RequeueError = &UpdateRejectedError{}
+
+// NewUpdateRejectedError creates an UpdateRejectedError, the fields default to the ones reported by the API server.
+func NewUpdateRejectedError(kind, name string, fields []string, err error) *UpdateRejectedError {
+	if len(fields) == 0 {
+		fields = GetInvalidFieldPaths(err)
+	}
+	return &UpdateRejectedError{Kind: kind, Name: name, Fields: fields, Err: err}
+}
+
+func (e *UpdateRejectedError) Error() string {
+	return fmt.Sprintf("the update of %s %s is rejected, fields: %v, error: %s", e.Kind, e.Name, e.Fields, e.Err.Error())
+}
+
+func (e *UpdateRejectedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UpdateRejectedError) RequeueAfter() time.Duration {
+	return updateRejectedRequeueDuration
+}
+
+func (e *UpdateRejectedError) Reason() string {
+	return e.Error()
+}
+
+// IsUpdateRejectedError checks whether the error is an UpdateRejectedError, which is a RequeueError
+// but should be surfaced to the users.
+func IsUpdateRejectedError(err error) bool {
+	var rejectedErr *UpdateRejectedError
+	return errors.As(err, &rejectedErr)
+}
+
+// IsUpdateRejected checks whether the update is rejected by the API server as invalid or forbidden.
+func IsUpdateRejected(err error) bool {
+	return apierrors.IsInvalid(err) || apierrors.IsForbidden(err)
+}
+
+// GetInvalidFieldPaths gets the paths of the invalid fields reported by the API server.
+func GetInvalidFieldPaths(err error) []string {
+	var paths []string
+	if status, ok := err.(apierrors.APIStatus); ok || errors.As(err, &status) {
+		if details := status.Status().Details; details != nil {
+			for _, cause := range details.Causes {
+				if len(cause.Field) > 0 {
+					paths = append(paths, cause.Field)
+				}
+			}
+		}
+	}
+	return paths
+}
@@ -22,7 +22,9 @@ package controllerutil
 import (
 	"context"
 	"reflect"
+	"strings"
 
+	"github.com/sethvargo/go-password/password"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -106,3 +108,18 @@ func IsNil(i interface{}) bool {
 	}
 	return false
 }
+
+// GeneratePassword generates the password w.r.t the passwordConfig.
+func GeneratePassword(passwdConfig appsv1alpha1.PasswordConfig) (string, error) {
+	passwd, err := password.Generate(int(passwdConfig.Length), int(passwdConfig.NumDigits), int(passwdConfig.NumSymbols), false, false)
+	if err != nil {
+		return "", err
+	}
+	switch passwdConfig.LetterCase {
+	case appsv1alpha1.UpperCases:
+		passwd = strings.ToUpper(passwd)
+	case appsv1alpha1.LowerCases:
+		passwd = strings.ToLower(passwd)
+	}
+	return passwd, nil
+}
@@ -48,4 +48,18 @@ type Context struct {
 
 	Scheme           *runtime.Scheme
 	RestClientConfig *rest.Config
+
+	// PodLogProvider provides the logs of the failed job pods, the pod log API
+	// is used by default.
+	PodLogProvider PodLogProvider
+}
+
+func (c Context) getPodLogProvider() PodLogProvider {
+	if c.PodLogProvider != nil {
+		return c.PodLogProvider
+	}
+	if c.RestClientConfig == nil {
+		return nil
+	}
+	return NewPodLogProvider(c.RestClientConfig)
 }
@@ -21,6 +21,8 @@ package action
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -35,6 +37,15 @@ import (
 	"github.com/apecloud/kubeblocks/internal/dataprotection/utils"
 )
 
+const (
+	// jobControllerUIDLabelKey is the label key of the job pods referring to the job.
+	jobControllerUIDLabelKey = "controller-uid"
+
+	failureLogsTailLines   = 50
+	failureLogsMaxBytes    = 4096
+	truncatedLogsIndicator = "...(truncated)"
+)
+
 // JobAction is an action that creates a batch job.
 type JobAction struct {
 	// Name is the Name of the action.
@@ -93,7 +104,7 @@ func (j *JobAction) Execute(ctx Context) (*dpv1alpha1.ActionStatus, error) {
 		case batchv1.JobFailed:
 			return sb.phase(dpv1alpha1.ActionPhaseFailed).
 				completionTimestamp(nil).
-				reason(msg).
+				reason(j.buildFailureReason(ctx, &original, msg)).
 				build(), nil
 		}
 		// job is running
@@ -121,6 +132,93 @@ func (j *JobAction) Execute(ctx Context) (*dpv1alpha1.ActionStatus, error) {
 	return handleErr(client.IgnoreAlreadyExists(ctx.Client.Create(ctx.Ctx, job)))
 }
 
+// buildFailureReason appends the exit code and the logs excerpt of the failed container to the failure
+// reason of the job, so that users needn't dig the logs out of the pods, which may be garbage-collected.
+// The logs are read from the pod log API, and the termination message is used if the logs are unavailable.
+func (j *JobAction) buildFailureReason(ctx Context, job *batchv1.Job, msg string) string {
+	if len(j.PodSpec.Containers) == 0 {
+		return msg
+	}
+	container := j.PodSpec.Containers[0].Name
+	pod, state := getFailedJobPod(ctx, job, container)
+	if pod == nil {
+		return msg
+	}
+
+	var logs string
+	if provider := ctx.getPodLogProvider(); provider != nil {
+		var err error
+		if logs, err = provider.GetLogs(ctx.Ctx, pod, container, failureLogsTailLines); err != nil {
+			logs = ""
+		}
+	}
+	if strings.TrimSpace(logs) == "" {
+		logs = state.Message
+	}
+	reason := fmt.Sprintf("%s, container %s of pod %s exited with code %d", msg, container, pod.Name, state.ExitCode)
+	if logs = truncateFailureLogs(logs, failureLogsTailLines, failureLogsMaxBytes); logs != "" {
+		reason = fmt.Sprintf("%s, %s\n%s", reason, types.FailureReasonLogsPrefix, logs)
+	}
+	return reason
+}
+
+// getFailedJobPod returns the latest pod of the job whose container terminated with a non-zero exit code.
+func getFailedJobPod(ctx Context, job *batchv1.Job, container string) (*corev1.Pod, *corev1.ContainerStateTerminated) {
+	podList := &corev1.PodList{}
+	if err := ctx.Client.List(ctx.Ctx, podList, client.InNamespace(job.Namespace),
+		client.MatchingLabels{jobControllerUIDLabelKey: string(job.UID)}); err != nil {
+		return nil, nil
+	}
+	var (
+		failedPod *corev1.Pod
+		state     *corev1.ContainerStateTerminated
+	)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if failedPod != nil && pod.CreationTimestamp.Before(&failedPod.CreationTimestamp) {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != container || status.State.Terminated == nil || status.State.Terminated.ExitCode == 0 {
+				continue
+			}
+			failedPod, state = pod, status.State.Terminated
+		}
+	}
+	return failedPod, state
+}
+
+// truncateFailureLogs keeps the last maxLines lines of the logs, and no more than maxBytes bytes.
+func truncateFailureLogs(logs string, maxLines int, maxBytes int) string {
+	logs = strings.TrimRight(logs, "\n")
+	if logs == "" {
+		return ""
+	}
+	lines := strings.Split(logs, "\n")
+	truncated := false
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+		truncated = true
+	}
+	logs = strings.Join(lines, "\n")
+	if len(logs) > maxBytes {
+		logs = logs[len(logs)-maxBytes:]
+		// drop the partial line, or the partial rune if it's a long line
+		if i := strings.Index(logs, "\n"); i >= 0 {
+			logs = logs[i+1:]
+		} else {
+			for len(logs) > 0 && !utf8.RuneStart(logs[0]) {
+				logs = logs[1:]
+			}
+		}
+		truncated = true
+	}
+	if truncated {
+		logs = truncatedLogsIndicator + "\n" + logs
+	}
+	return logs
+}
+
 func (j *JobAction) validate() error {
 	if j.ObjectMeta.Name == "" {
 		return fmt.Errorf("name is required")
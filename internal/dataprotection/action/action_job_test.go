@@ -20,6 +20,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package action_test
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -31,12 +35,25 @@ import (
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	"github.com/apecloud/kubeblocks/internal/dataprotection/action"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 	"github.com/apecloud/kubeblocks/internal/generics"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 	testdp "github.com/apecloud/kubeblocks/internal/testutil/dataprotection"
 	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
 
+type fakePodLogProvider struct {
+	logs      string
+	err       error
+	pod       string
+	container string
+}
+
+func (p *fakePodLogProvider) GetLogs(_ context.Context, pod *corev1.Pod, container string, _ int64) (string, error) {
+	p.pod, p.container = pod.Name, container
+	return p.logs, p.err
+}
+
 var _ = Describe("JobAction Test", func() {
 	const (
 		actionName = "test-job-action"
@@ -51,6 +68,7 @@ var _ = Describe("JobAction Test", func() {
 		By("clean resources")
 		inNS := client.InNamespace(testCtx.DefaultNamespace)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.JobSignature, true, inNS)
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS)
 	}
 
@@ -116,5 +134,86 @@ var _ = Describe("JobAction Test", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseCompleted))
 		})
+
+		It("should capture the logs excerpt of the failed job", func() {
+			act := &action.JobAction{
+				Name: actionName,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      actionName,
+					Namespace: testCtx.DefaultNamespace,
+				},
+				PodSpec: &corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    container,
+							Image:   testdp.KBToolImage,
+							Command: command,
+						},
+					},
+					RestartPolicy: corev1.RestartPolicyNever,
+				},
+				Owner: testdp.NewFakeBackup(&testCtx, nil),
+			}
+			_, err := act.Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			job := &batchv1.Job{}
+			key := client.ObjectKey{Name: actionName, Namespace: testCtx.DefaultNamespace}
+			Eventually(testapps.CheckObjExists(&testCtx, key, job, true)).Should(Succeed())
+
+			By("mock the failed pod of the job")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      actionName + "-pod",
+					Namespace: testCtx.DefaultNamespace,
+					Labels:    map[string]string{"controller-uid": string(job.UID)},
+				},
+				Spec: *act.PodSpec,
+			}
+			Expect(testCtx.Create(testCtx.Ctx, pod)).Should(Succeed())
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(pod), func(fetched *corev1.Pod) {
+				fetched.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name: container,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 2,
+						Message:  "failed to connect to the database",
+					}},
+				}}
+			})).Should(Succeed())
+			testdp.PatchK8sJobStatus(&testCtx, key, batchv1.JobFailed)
+
+			By("the failure reason should contain the last lines of the logs")
+			var lines []string
+			for i := 1; i <= 100; i++ {
+				lines = append(lines, fmt.Sprintf("line-%d", i))
+			}
+			logProvider := &fakePodLogProvider{logs: strings.Join(lines, "\n")}
+			actionCtx := buildActionCtx()
+			actionCtx.PodLogProvider = logProvider
+			status, err := act.Execute(actionCtx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseFailed))
+			Expect(status.FailureReason).Should(ContainSubstring("exited with code 2"))
+			Expect(status.FailureReason).Should(ContainSubstring(dptypes.FailureReasonLogsPrefix))
+			Expect(status.FailureReason).Should(ContainSubstring("...(truncated)\nline-51\n"))
+			Expect(status.FailureReason).Should(HaveSuffix("line-100"))
+			Expect(status.FailureReason).ShouldNot(ContainSubstring("line-50\n"))
+			Expect(logProvider.pod).Should(Equal(pod.Name))
+			Expect(logProvider.container).Should(Equal(container))
+
+			By("the long logs should be truncated by bytes")
+			logProvider.logs = strings.Repeat("x", 100) + "\n" + strings.Repeat("y", 8192)
+			status, err = act.Execute(actionCtx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.FailureReason).ShouldNot(ContainSubstring("xxx"))
+			Expect(status.FailureReason).Should(ContainSubstring("...(truncated)\n" + strings.Repeat("y", 4096)))
+			Expect(len(status.FailureReason)).Should(BeNumerically("<", 4096+512))
+
+			By("the termination message is used if the logs are unavailable")
+			logProvider.err = fmt.Errorf("pod not found")
+			status, err = act.Execute(actionCtx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.FailureReason).Should(ContainSubstring("exited with code 2"))
+			Expect(status.FailureReason).Should(HaveSuffix("failed to connect to the database"))
+		})
 	})
 })
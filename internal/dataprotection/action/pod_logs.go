@@ -0,0 +1,62 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package action
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// PodLogProvider provides the logs of the pod containers.
+type PodLogProvider interface {
+	// GetLogs returns the last tailLines lines of the logs of the container.
+	GetLogs(ctx context.Context, pod *corev1.Pod, container string, tailLines int64) (string, error)
+}
+
+// restPodLogProvider reads the logs by the pod log API.
+type restPodLogProvider struct {
+	config *rest.Config
+}
+
+var _ PodLogProvider = &restPodLogProvider{}
+
+// NewPodLogProvider returns a PodLogProvider reading the logs by the pod log API.
+func NewPodLogProvider(config *rest.Config) PodLogProvider {
+	return &restPodLogProvider{config: config}
+}
+
+func (p *restPodLogProvider) GetLogs(ctx context.Context, pod *corev1.Pod, container string, tailLines int64) (string, error) {
+	clientset, err := corev1client.NewForConfig(p.config)
+	if err != nil {
+		return "", err
+	}
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	}
+	data, err := clientset.Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
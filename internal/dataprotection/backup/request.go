@@ -57,6 +57,9 @@ type Request struct {
 	TargetPods    []*corev1.Pod
 	BackupRepoPVC *corev1.PersistentVolumeClaim
 	BackupRepo    *dpv1alpha1.BackupRepo
+
+	// TargetPodFallbackReason is why the target pod isn't of the role selected by the backup policy.
+	TargetPodFallbackReason string
 }
 
 func (r *Request) GetBackupType() string {
@@ -285,6 +288,7 @@ func (r *Request) buildJobActionPodSpec(name string, job *dpv1alpha1.JobActionSp
 			},
 		}
 		envVars = append(envVars, utils.BuildEnvByCredential(targetPod, r.BackupPolicy.Spec.Target.ConnectionCredential)...)
+		envVars = append(envVars, utils.BuildEncryptionEnv(r.Status.Encryption)...)
 		if r.ActionSet != nil {
 			envVars = append(envVars, r.ActionSet.Spec.Env...)
 		}
@@ -292,15 +296,22 @@ func (r *Request) buildJobActionPodSpec(name string, job *dpv1alpha1.JobActionSp
 	}
 
 	buildVolumes := func() []corev1.Volume {
-		return append([]corev1.Volume{
-			buildBackupRepoVolume(r.BackupRepoPVC.Name),
-		}, getVolumesByVolumeInfo(targetPod, r.BackupMethod.TargetVolumes)...)
+		volumes := []corev1.Volume{buildBackupRepoVolume(r.BackupRepoPVC.Name)}
+		// project the encryption key recorded in the backup status, so the key
+		// rotation of the backup repo does not affect this backup.
+		if encryption := r.Status.Encryption; encryption != nil {
+			secretName := utils.GetEncryptionKeySecretName(r.Status.BackupRepoName, encryption.KeyFingerprint)
+			volumes = append(volumes, utils.BuildEncryptionKeyVolume(secretName))
+		}
+		return append(volumes, getVolumesByVolumeInfo(targetPod, r.BackupMethod.TargetVolumes)...)
 	}
 
 	buildVolumeMounts := func() []corev1.VolumeMount {
-		return append([]corev1.VolumeMount{
-			buildBackupRepoVolumeMount(r.BackupRepoPVC.Name),
-		}, getVolumeMountsByVolumeInfo(targetPod, r.BackupMethod.TargetVolumes)...)
+		volumeMounts := []corev1.VolumeMount{buildBackupRepoVolumeMount(r.BackupRepoPVC.Name)}
+		if r.Status.Encryption != nil {
+			volumeMounts = append(volumeMounts, utils.BuildEncryptionKeyVolumeMount())
+		}
+		return append(volumeMounts, getVolumeMountsByVolumeInfo(targetPod, r.BackupMethod.TargetVolumes)...)
 	}
 
 	runAsUser := int64(0)
@@ -51,9 +51,18 @@ func GetActionSet(ctx context.Context, cli client.Client, namespace, name string
 	return actionSet, nil
 }
 
+// isEphemeralVolume checks whether the volume lives and dies with the pod, such volumes are skipped by the backups,
+// as they are not the persistent data, and mounting them into the backup workload makes no sense.
+func isEphemeralVolume(volume corev1.Volume) bool {
+	return volume.EmptyDir != nil || volume.Ephemeral != nil
+}
+
 func getVolumesByNames(pod *corev1.Pod, volumeNames []string) []corev1.Volume {
 	var volumes []corev1.Volume
 	for _, v := range pod.Spec.Volumes {
+		if isEphemeralVolume(v) {
+			continue
+		}
 		for _, name := range volumeNames {
 			if v.Name == name {
 				volumes = append(volumes, v)
@@ -66,6 +75,9 @@ func getVolumesByNames(pod *corev1.Pod, volumeNames []string) []corev1.Volume {
 func getVolumesByMounts(pod *corev1.Pod, mounts []corev1.VolumeMount) []corev1.Volume {
 	var volumes []corev1.Volume
 	for _, v := range pod.Spec.Volumes {
+		if isEphemeralVolume(v) {
+			continue
+		}
 		for _, m := range mounts {
 			if v.Name == m.Name {
 				volumes = append(volumes, v)
@@ -97,6 +109,9 @@ func getVolumeMountsByVolumeInfo(pod *corev1.Pod, info *dpv1alpha1.TargetVolumeI
 	}
 	var mounts []corev1.VolumeMount
 	for _, v := range pod.Spec.Volumes {
+		if isEphemeralVolume(v) {
+			continue
+		}
 		for _, m := range info.VolumeMounts {
 			if v.Name == m.Name {
 				mounts = append(mounts, m)
@@ -33,6 +33,8 @@ const (
 	ErrorTypeBackupNotCompleted intctrlutil.ErrorType = "BackupNotCompleted"
 	// ErrorTypeBackupPVCNameIsEmpty pvc name for backup is empty
 	ErrorTypeBackupPVCNameIsEmpty intctrlutil.ErrorType = "BackupPVCNameIsEmpty"
+	// ErrorTypeBackupEncryptionKeyNotReady encryption key of backup repo is not ready
+	ErrorTypeBackupEncryptionKeyNotReady intctrlutil.ErrorType = "BackupEncryptionKeyNotReady"
 	// ErrorTypeBackupJobFailed backup job failed
 	ErrorTypeBackupJobFailed intctrlutil.ErrorType = "BackupJobFailed"
 	// ErrorTypeStorageNotMatch storage not match
@@ -64,6 +66,11 @@ func NewBackupPVCNameIsEmpty(backupRepo, backupPolicyName string) *intctrlutil.E
 	return intctrlutil.NewErrorf(ErrorTypeBackupPVCNameIsEmpty, `the persistentVolumeClaim name of %s is empty in BackupPolicy "%s"`, backupRepo, backupPolicyName)
 }
 
+// NewBackupEncryptionKeyNotReady returns a new Error with ErrorTypeBackupEncryptionKeyNotReady.
+func NewBackupEncryptionKeyNotReady(backupRepo string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeBackupEncryptionKeyNotReady, `the encryption key of BackupRepo "%s" is not ready`, backupRepo)
+}
+
 // NewBackupJobFailed returns a new Error with ErrorTypeBackupJobFailed.
 func NewBackupJobFailed(jobName string) *intctrlutil.Error {
 	return intctrlutil.NewErrorf(ErrorTypeBackupJobFailed, `backup job "%s" failed`, jobName)
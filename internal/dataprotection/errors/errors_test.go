@@ -65,6 +65,10 @@ func TestNewErrors(t *testing.T) {
 	if !intctrlutil.IsTargetError(pvsIsEmpty, ErrorTypeBackupPVCNameIsEmpty) {
 		t.Error("should be error of BackupPVCNameIsEmpty")
 	}
+	encryptionKeyNotReady := NewBackupEncryptionKeyNotReady("repo")
+	if !intctrlutil.IsTargetError(encryptionKeyNotReady, ErrorTypeBackupEncryptionKeyNotReady) {
+		t.Error("should be error of BackupEncryptionKeyNotReady")
+	}
 	jobFailed := NewBackupJobFailed("jobName")
 	if !intctrlutil.IsTargetError(jobFailed, ErrorTypeBackupJobFailed) {
 		t.Error("should be error of BackupJobFailed")
@@ -93,7 +93,7 @@ func (r *restoreJobBuilder) buildPVCVolumeAndMount(
 		claim.VolumeSource, r.backupSet.Backup.Name))
 }
 
-// addBackupVolumeAndMount adds the volume and volumeMount of backup pvc to common volumes and volumeMounts slice.
+// addBackupVolumeAndMount adds the volume and volumeMount of backup pvc and encryption key to common volumes and volumeMounts slice.
 func (r *restoreJobBuilder) addBackupVolumeAndMount() *restoreJobBuilder {
 	if r.backupSet.Backup.Status.PersistentVolumeClaimName != "" {
 		backupName := r.backupSet.Backup.Name
@@ -108,6 +108,11 @@ func (r *restoreJobBuilder) addBackupVolumeAndMount() *restoreJobBuilder {
 			MountPath: "/" + backupName,
 		})
 	}
+	if encryption := r.backupSet.Backup.Status.Encryption; encryption != nil {
+		secretName := utils.GetEncryptionKeySecretName(r.backupSet.Backup.Status.BackupRepoName, encryption.KeyFingerprint)
+		r.commonVolumes = append(r.commonVolumes, utils.BuildEncryptionKeyVolume(secretName))
+		r.commonVolumeMounts = append(r.commonVolumeMounts, utils.BuildEncryptionKeyVolumeMount())
+	}
 	return r
 }
 
@@ -182,6 +187,8 @@ func (r *restoreJobBuilder) addCommonEnv() *restoreJobBuilder {
 		r.env = append(r.env, corev1.EnvVar{Name: dptypes.DPBackupDIR, Value: fmt.Sprintf("/%s%s", backupName, filePath)})
 		// TODO: add continuous file path env
 	}
+	// add encryption env
+	r.env = append(r.env, utils.BuildEncryptionEnv(r.backupSet.Backup.Status.Encryption)...)
 	// add time env
 	actionSetEnv := r.backupSet.ActionSet.Spec.Env
 	timeFormat := getTimeFormat(r.backupSet.ActionSet.Spec.Env)
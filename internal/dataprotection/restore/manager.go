@@ -36,6 +36,7 @@ import (
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 	"github.com/apecloud/kubeblocks/internal/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/internal/dataprotection/utils/boolptr"
 )
@@ -82,6 +83,9 @@ func (r *RestoreManager) GetBackupActionSetByNamespaced(reqCtx intctrlutil.Reque
 	if backupMethod == nil {
 		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`status.backupMethod of backup "%s" is empty`, backupName))
 	}
+	if err := r.validateEncryptionKey(reqCtx, cli, backup); err != nil {
+		return nil, err
+	}
 	useVolumeSnapshot := backupMethod.SnapshotVolumes != nil && *backupMethod.SnapshotVolumes
 	actionSet, err := utils.GetActionSetByName(reqCtx, cli, backup.Status.BackupMethod.ActionSetName)
 	if err != nil {
@@ -90,6 +94,30 @@ func (r *RestoreManager) GetBackupActionSetByNamespaced(reqCtx intctrlutil.Reque
 	return &BackupActionSet{Backup: backup, ActionSet: actionSet, UseVolumeSnapshot: useVolumeSnapshot}, nil
 }
 
+// validateEncryptionKey checks if the key secret of the backup holds the key used to encrypt the backup data,
+// by comparing its fingerprint with the one recorded in the backup status at backup time, so that the restore
+// fails fast if it has a wrong key. The key rotation of the backup repo doesn't affect the existing backups.
+func (r *RestoreManager) validateEncryptionKey(reqCtx intctrlutil.RequestCtx, cli client.Client, backup *dpv1alpha1.Backup) error {
+	encryption := backup.Status.Encryption
+	if encryption == nil {
+		return nil
+	}
+	// the key secrets are created in the namespaces of the backups by the backup repo controller.
+	secret := &corev1.Secret{}
+	secretName := utils.GetEncryptionKeySecretName(backup.Status.BackupRepoName, encryption.KeyFingerprint)
+	if err := cli.Get(reqCtx.Ctx, types.NamespacedName{Namespace: backup.Namespace, Name: secretName}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			err = intctrlutil.NewFatalError(fmt.Sprintf(`the encryption key secret "%s" of backup "%s" is not found`, secretName, backup.Name))
+		}
+		return err
+	}
+	if utils.GetEncryptionKeyFingerprint(secret.Data[dptypes.EncryptionKeySecretKey]) != encryption.KeyFingerprint {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`the encryption key in secret "%s" mismatches the one used by backup "%s"`,
+			secretName, backup.Name))
+	}
+	return nil
+}
+
 // BuildDifferentialBackupActionSets builds the backupActionSets for specified incremental backup.
 func (r *RestoreManager) BuildDifferentialBackupActionSets(reqCtx intctrlutil.RequestCtx, cli client.Client, sourceBackupSet BackupActionSet) error {
 	parentBackupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, sourceBackupSet.Backup.Spec.ParentBackupName, sourceBackupSet.Backup.Namespace)
@@ -0,0 +1,167 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
+	"github.com/apecloud/kubeblocks/internal/dataprotection/utils"
+	mock_client "github.com/apecloud/kubeblocks/internal/testutil/k8s/mocks"
+)
+
+func newTestRestoreManager() *RestoreManager {
+	restore := &dpv1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-restore", Namespace: "default"},
+	}
+	return NewRestoreManager(restore, record.NewFakeRecorder(10), nil)
+}
+
+func newEncryptedBackup(key string) *dpv1alpha1.Backup {
+	return &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-backup", Namespace: "default"},
+		Status: dpv1alpha1.BackupStatus{
+			BackupRepoName:            "test-repo",
+			PersistentVolumeClaimName: "test-pvc",
+			Path:                      "/default/test-backup",
+			Encryption: &dpv1alpha1.BackupEncryptionStatus{
+				Algorithm:      dpv1alpha1.EncryptionAlgorithmAES256CFB,
+				KeyFingerprint: utils.GetEncryptionKeyFingerprint([]byte(key)),
+			},
+		},
+	}
+}
+
+func mockGetEncryptionKeySecret(cli *mock_client.MockClient, backup *dpv1alpha1.Backup, key string) {
+	secretKey := client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      utils.GetEncryptionKeySecretName("test-repo", backup.Status.Encryption.KeyFingerprint),
+	}
+	cli.EXPECT().
+		Get(gomock.Any(), secretKey, &corev1.Secret{}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ client.ObjectKey, secret *corev1.Secret, _ ...client.GetOption) error {
+			secret.Data = map[string][]byte{dptypes.EncryptionKeySecretKey: []byte(key)}
+			return nil
+		}).Times(1)
+}
+
+func TestValidateEncryptionKeyWithMismatchedKey(t *testing.T) {
+	cli := mock_client.NewMockClient(gomock.NewController(t))
+	restoreMgr := newTestRestoreManager()
+	backup := newEncryptedBackup("old-key")
+	mockGetEncryptionKeySecret(cli, backup, "new-key")
+
+	err := restoreMgr.validateEncryptionKey(intctrlutil.RequestCtx{Ctx: context.Background()}, cli, backup)
+	if !intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+		t.Errorf("expect a fatal error for the mismatched encryption key, but got: %v", err)
+	}
+}
+
+func TestValidateEncryptionKeyWithMatchedKey(t *testing.T) {
+	cli := mock_client.NewMockClient(gomock.NewController(t))
+	restoreMgr := newTestRestoreManager()
+	// the restore is in another namespace, the key secret is looked up in the namespace of the backup.
+	restoreMgr.Restore.Namespace = "restore-ns"
+	backup := newEncryptedBackup("key")
+	mockGetEncryptionKeySecret(cli, backup, "key")
+
+	if err := restoreMgr.validateEncryptionKey(intctrlutil.RequestCtx{Ctx: context.Background()}, cli, backup); err != nil {
+		t.Errorf("expect no error, but got: %v", err)
+	}
+}
+
+func TestValidateEncryptionKeyWithMissingSecret(t *testing.T) {
+	cli := mock_client.NewMockClient(gomock.NewController(t))
+	restoreMgr := newTestRestoreManager()
+	backup := newEncryptedBackup("key")
+	cli.EXPECT().Get(gomock.Any(), gomock.Any(), &corev1.Secret{}, gomock.Any()).
+		Return(apierrors.NewNotFound(corev1.Resource("secrets"), "test-repo-encryption-key")).Times(1)
+
+	err := restoreMgr.validateEncryptionKey(intctrlutil.RequestCtx{Ctx: context.Background()}, cli, backup)
+	if !intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+		t.Errorf("expect a fatal error for the missing encryption key secret, but got: %v", err)
+	}
+}
+
+func TestValidateEncryptionKeyWithoutEncryption(t *testing.T) {
+	cli := mock_client.NewMockClient(gomock.NewController(t))
+	restoreMgr := newTestRestoreManager()
+	backup := &dpv1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Name: "test-backup", Namespace: "default"}}
+
+	if err := restoreMgr.validateEncryptionKey(intctrlutil.RequestCtx{Ctx: context.Background()}, cli, backup); err != nil {
+		t.Errorf("expect no error, but got: %v", err)
+	}
+}
+
+func TestRestoreJobWithEncryptionKey(t *testing.T) {
+	restoreMgr := newTestRestoreManager()
+	restoreMgr.Restore.UID = "00000000-0000-0000-0000-000000000000"
+	backup := newEncryptedBackup("key")
+	backupSet := BackupActionSet{Backup: backup, ActionSet: &dpv1alpha1.ActionSet{}}
+	job := newRestoreJobBuilder(restoreMgr.Restore, backupSet, dpv1alpha1.PostReady).
+		addBackupVolumeAndMount().
+		addCommonEnv().
+		build(0)
+
+	podSpec := job.Spec.Template.Spec
+	secretName := utils.GetEncryptionKeySecretName("test-repo", backup.Status.Encryption.KeyFingerprint)
+	var keyVolume *corev1.Volume
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == dptypes.EncryptionKeyVolumeName {
+			keyVolume = &podSpec.Volumes[i]
+		}
+	}
+	if keyVolume == nil || keyVolume.Projected == nil ||
+		keyVolume.Projected.Sources[0].Secret.Name != secretName {
+		t.Fatalf("expect a projected volume of secret %s, but got: %v", secretName, keyVolume)
+	}
+
+	container := podSpec.Containers[0]
+	var mounted bool
+	for _, mount := range container.VolumeMounts {
+		if mount.Name == dptypes.EncryptionKeyVolumeName && mount.MountPath == dptypes.EncryptionKeyMountPath && mount.ReadOnly {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Errorf("expect the encryption key to be mounted read-only at %s", dptypes.EncryptionKeyMountPath)
+	}
+	envs := map[string]string{}
+	for _, env := range container.Env {
+		envs[env.Name] = env.Value
+	}
+	if envs[dptypes.DPEncryptionAlgorithm] != string(dpv1alpha1.EncryptionAlgorithmAES256CFB) {
+		t.Errorf("expect env %s to be %s, but got: %s", dptypes.DPEncryptionAlgorithm,
+			dpv1alpha1.EncryptionAlgorithmAES256CFB, envs[dptypes.DPEncryptionAlgorithm])
+	}
+	if envs[dptypes.DPEncryptionKeyFile] != dptypes.EncryptionKeyMountPath+"/"+dptypes.EncryptionKeySecretKey {
+		t.Errorf("expect env %s to point to the mounted key, but got: %s", dptypes.DPEncryptionKeyFile, envs[dptypes.DPEncryptionKeyFile])
+	}
+}
@@ -24,6 +24,9 @@ const (
 	DataProtectionFinalizerName = "dataprotection.kubeblocks.io/finalizer"
 )
 
+// FailureReasonLogsPrefix is the prefix of the logs excerpt of the failed job in the failure reason.
+const FailureReasonLogsPrefix = "the last lines of the logs:"
+
 // annotation keys
 const (
 	// DefaultBackupPolicyAnnotationKey specifies the default backup policy.
@@ -96,9 +99,24 @@ const (
 	DPBaseBackupStartTimestamp = "BASE_BACKUP_START_TIMESTAMP" // base backup start timestamp for pitr
 	// DPBackupStopTime backup stop time
 	DPBackupStopTime = "BACKUP_STOP_TIME" // backup stop time
+	// DPEncryptionAlgorithm the algorithm used to encrypt the backup data
+	DPEncryptionAlgorithm = "DP_ENCRYPTION_ALGORITHM"
+	// DPEncryptionKeyFile the file which contains the key used to encrypt the backup data
+	DPEncryptionKeyFile = "DP_ENCRYPTION_KEY_FILE"
+)
+
+// encryption
+const (
+	// EncryptionKeySecretKey is the key of the encryption key in the secret.
+	EncryptionKeySecretKey = "key"
+	// EncryptionKeyVolumeName is the name of the volume which projects the encryption key.
+	EncryptionKeyVolumeName = "dp-encryption-key"
+	// EncryptionKeyMountPath is the path where the encryption key is mounted in the backup and restore containers.
+	EncryptionKeyMountPath = "/dp-encryption"
 )
 
 const (
+	BackupKind             = "Backup"
 	RestoreKind            = "Restore"
 	DataprotectionAPIGroup = "dataprotection.kubeblocks.io"
 )
@@ -0,0 +1,105 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
+)
+
+const encryptionKeyFingerprintPrefix = "sha256:"
+
+// GetEncryptionKeyFingerprint returns the fingerprint of the encryption key.
+func GetEncryptionKeyFingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return encryptionKeyFingerprintPrefix + hex.EncodeToString(sum[:])
+}
+
+// GetEncryptionKeySecretName returns the name of the secret which holds the encryption key
+// of the backup repo in the namespace of backups. The name changes with the key, so the
+// rotation of the key never affects the existing backups.
+func GetEncryptionKeySecretName(repoName, fingerprint string) string {
+	digest := strings.TrimPrefix(fingerprint, encryptionKeyFingerprintPrefix)
+	if len(digest) > 8 {
+		digest = digest[:8]
+	}
+	return fmt.Sprintf("%s-encryption-key-%s", repoName, digest)
+}
+
+// BuildEncryptionKeyVolume builds a projected volume of the encryption key secret.
+func BuildEncryptionKeyVolume(secretName string) corev1.Volume {
+	mode := int32(0400)
+	return corev1.Volume{
+		Name: dptypes.EncryptionKeyVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Items: []corev1.KeyToPath{
+								{
+									Key:  dptypes.EncryptionKeySecretKey,
+									Path: dptypes.EncryptionKeySecretKey,
+								},
+							},
+						},
+					},
+				},
+				DefaultMode: &mode,
+			},
+		},
+	}
+}
+
+// BuildEncryptionKeyVolumeMount builds the volume mount of the encryption key.
+func BuildEncryptionKeyVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      dptypes.EncryptionKeyVolumeName,
+		MountPath: dptypes.EncryptionKeyMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// BuildEncryptionEnv builds the envs to encrypt or decrypt the backup data, the key itself
+// is only accessible from the mounted file.
+func BuildEncryptionEnv(encryption *dpv1alpha1.BackupEncryptionStatus) []corev1.EnvVar {
+	if encryption == nil {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{
+			Name:  dptypes.DPEncryptionAlgorithm,
+			Value: string(encryption.Algorithm),
+		},
+		{
+			Name:  dptypes.DPEncryptionKeyFile,
+			Value: filepath.Join(dptypes.EncryptionKeyMountPath, dptypes.EncryptionKeySecretKey),
+		},
+	}
+}
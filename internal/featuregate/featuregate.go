@@ -0,0 +1,216 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package featuregate gates the experimental behaviors of the controllers, which ship disabled
+// before becoming default. It's modeled on the feature gates of k8s.io/component-base.
+package featuregate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Feature is the name of a gated behavior.
+type Feature string
+
+// Stage is the maturity of a feature.
+type Stage string
+
+const (
+	Alpha Stage = "ALPHA"
+	Beta  Stage = "BETA"
+	GA    Stage = "GA"
+)
+
+// FeatureSpec is the specification of a feature.
+type FeatureSpec struct {
+	// Default is the enablement of the feature if not set.
+	Default bool
+	// PreRelease is the maturity of the feature.
+	PreRelease Stage
+	// LockToDefault rejects to change the enablement of the feature, usually for the GA features.
+	LockToDefault bool
+}
+
+// FeatureGate queries the enablement of the features.
+type FeatureGate interface {
+	// Enabled returns whether the feature is enabled, the unknown features are disabled.
+	Enabled(f Feature) bool
+	// KnownFeatures returns the descriptions of all known features, sorted by name.
+	KnownFeatures() []string
+}
+
+// MutableFeatureGate is a FeatureGate which can register and set the features.
+type MutableFeatureGate interface {
+	FeatureGate
+	// Add registers the features.
+	Add(features map[Feature]FeatureSpec) error
+	// Set parses the comma-separated key=value pairs, such as "A=true,B=false", and sets the features.
+	Set(value string) error
+	// SetFromMap sets the features from the map of the feature names to the enablement.
+	SetFromMap(m map[string]bool) error
+}
+
+// featureEnabled reports the enablement of each feature.
+var featureEnabled = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kubeblocks_feature_enabled",
+		Help: "Whether the feature is enabled, 1 if enabled and 0 otherwise.",
+	},
+	[]string{"name", "stage"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(featureEnabled)
+}
+
+type featureGate struct {
+	lock    sync.RWMutex
+	known   map[Feature]FeatureSpec
+	enabled map[Feature]bool
+	// gauge reports the enablement of the features, it's nil if not reported.
+	gauge *prometheus.GaugeVec
+}
+
+var _ MutableFeatureGate = &featureGate{}
+
+// NewFeatureGate creates a feature gate without any features registered.
+func NewFeatureGate() MutableFeatureGate {
+	return &featureGate{
+		known:   map[Feature]FeatureSpec{},
+		enabled: map[Feature]bool{},
+	}
+}
+
+func (g *featureGate) Enabled(f Feature) bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if enabled, ok := g.enabled[f]; ok {
+		return enabled
+	}
+	return g.known[f].Default
+}
+
+func (g *featureGate) KnownFeatures() []string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	var features []string
+	for f, spec := range g.known {
+		features = append(features, fmt.Sprintf("%s=true|false (%s - default=%t)", f, spec.PreRelease, spec.Default))
+	}
+	sort.Strings(features)
+	return features
+}
+
+func (g *featureGate) Add(features map[Feature]FeatureSpec) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for f, spec := range features {
+		if existing, ok := g.known[f]; ok {
+			if existing == spec {
+				continue
+			}
+			return fmt.Errorf("feature gate %s is registered with a different spec", f)
+		}
+		g.known[f] = spec
+		g.report(f)
+	}
+	return nil
+}
+
+func (g *featureGate) Set(value string) error {
+	m := map[string]bool{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("missing bool value for feature gate %s", s)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature gate %s: %s", kv[0], kv[1])
+		}
+		m[strings.TrimSpace(kv[0])] = enabled
+	}
+	return g.SetFromMap(m)
+}
+
+func (g *featureGate) SetFromMap(m map[string]bool) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	// validate all features before setting any of them, so a bad value leaves the gate unchanged.
+	for name, enabled := range m {
+		spec, ok := g.known[Feature(name)]
+		if !ok {
+			return fmt.Errorf("unrecognized feature gate: %s", name)
+		}
+		if spec.LockToDefault && spec.Default != enabled {
+			return fmt.Errorf("cannot set feature gate %s to %t, it's locked to %t", name, enabled, spec.Default)
+		}
+	}
+	for name, enabled := range m {
+		g.enabled[Feature(name)] = enabled
+		g.report(Feature(name))
+	}
+	return nil
+}
+
+// report updates the gauge of the feature, the caller must hold the lock.
+func (g *featureGate) report(f Feature) {
+	if g.gauge == nil {
+		return
+	}
+	value := 0.0
+	enabled, ok := g.enabled[f]
+	if !ok {
+		enabled = g.known[f].Default
+	}
+	if enabled {
+		value = 1
+	}
+	g.gauge.WithLabelValues(string(f), string(g.known[f].PreRelease)).Set(value)
+}
+
+var (
+	// DefaultMutableFeatureGate is the feature gate of the controllers, the features are registered at startup
+	// and set by the operator flag --feature-gates or the env FEATURE_GATES.
+	DefaultMutableFeatureGate MutableFeatureGate = &featureGate{
+		known:   map[Feature]FeatureSpec{},
+		enabled: map[Feature]bool{},
+		gauge:   featureEnabled,
+	}
+
+	// DefaultFeatureGate is the read-only view of DefaultMutableFeatureGate, which is queried by
+	// the transformers and the builders.
+	DefaultFeatureGate FeatureGate = DefaultMutableFeatureGate
+)
+
+// Enabled returns whether the feature is enabled in the DefaultFeatureGate.
+func Enabled(f Feature) bool {
+	return DefaultFeatureGate.Enabled(f)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package featuregate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const (
+	alphaFeature  Feature = "AlphaFeature"
+	betaFeature   Feature = "BetaFeature"
+	lockedFeature Feature = "LockedFeature"
+)
+
+func newTestFeatureGate(t *testing.T) MutableFeatureGate {
+	g := NewFeatureGate()
+	if err := g.Add(map[Feature]FeatureSpec{
+		alphaFeature:  {Default: false, PreRelease: Alpha},
+		betaFeature:   {Default: true, PreRelease: Beta},
+		lockedFeature: {Default: true, PreRelease: GA, LockToDefault: true},
+	}); err != nil {
+		t.Fatalf("failed to add features: %v", err)
+	}
+	return g
+}
+
+func TestFeatureGateSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expectErr     bool
+		expectEnabled map[Feature]bool
+	}{
+		{
+			name:          "defaults",
+			value:         "",
+			expectEnabled: map[Feature]bool{alphaFeature: false, betaFeature: true, "UnknownFeature": false},
+		},
+		{
+			name:          "enable alpha and disable beta",
+			value:         "AlphaFeature=true, BetaFeature=false",
+			expectEnabled: map[Feature]bool{alphaFeature: true, betaFeature: false},
+		},
+		{
+			name:          "unknown feature",
+			value:         "AlphaFeature=true,UnknownFeature=true",
+			expectErr:     true,
+			expectEnabled: map[Feature]bool{alphaFeature: false},
+		},
+		{
+			name:          "invalid value",
+			value:         "AlphaFeature=yes",
+			expectErr:     true,
+			expectEnabled: map[Feature]bool{alphaFeature: false},
+		},
+		{
+			name:      "missing value",
+			value:     "AlphaFeature",
+			expectErr: true,
+		},
+		{
+			name:          "locked feature",
+			value:         "LockedFeature=false",
+			expectErr:     true,
+			expectEnabled: map[Feature]bool{lockedFeature: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestFeatureGate(t)
+			err := g.Set(tt.value)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			for f, expected := range tt.expectEnabled {
+				if g.Enabled(f) != expected {
+					t.Errorf("expected %s enabled %v", f, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestFeatureGateToggleAtRuntime(t *testing.T) {
+	g := newTestFeatureGate(t)
+	// the gated behavior queries the gate on each call, so both code paths are selectable after startup.
+	behavior := func() string {
+		if g.Enabled(alphaFeature) {
+			return "new"
+		}
+		return "old"
+	}
+	for _, enabled := range []bool{false, true, false} {
+		if err := g.SetFromMap(map[string]bool{string(alphaFeature): enabled}); err != nil {
+			t.Fatalf("failed to set feature: %v", err)
+		}
+		expected := "old"
+		if enabled {
+			expected = "new"
+		}
+		if actual := behavior(); actual != expected {
+			t.Errorf("expected the %s code path with %s=%v, got %s", expected, alphaFeature, enabled, actual)
+		}
+	}
+
+	// the same feature can be registered again, but not with a different spec.
+	if err := g.Add(map[Feature]FeatureSpec{alphaFeature: {Default: false, PreRelease: Alpha}}); err != nil {
+		t.Errorf("expected to add the same feature again, got %v", err)
+	}
+	if err := g.Add(map[Feature]FeatureSpec{alphaFeature: {Default: true, PreRelease: Beta}}); err == nil {
+		t.Error("expected error to add the feature with a different spec")
+	}
+}
+
+func TestKnownFeatures(t *testing.T) {
+	known := newTestFeatureGate(t).KnownFeatures()
+	if len(known) != 3 || !strings.HasPrefix(known[0], "AlphaFeature=true|false (ALPHA - default=false)") {
+		t.Errorf("unexpected known features: %v", known)
+	}
+}
+
+func TestDefaultFeatureGateMetrics(t *testing.T) {
+	defer func() {
+		_ = DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(ResourceQuota): false})
+	}()
+	if value := testutil.ToFloat64(featureEnabled.WithLabelValues(string(ResourceQuota), string(Alpha))); value != 0 {
+		t.Errorf("expected the feature %s reported disabled by default, got %v", ResourceQuota, value)
+	}
+	if err := DefaultMutableFeatureGate.Set("ResourceQuota=true"); err != nil {
+		t.Fatalf("failed to set feature: %v", err)
+	}
+	if !Enabled(ResourceQuota) {
+		t.Errorf("expected the feature %s enabled", ResourceQuota)
+	}
+	if value := testutil.ToFloat64(featureEnabled.WithLabelValues(string(ResourceQuota), string(Alpha))); value != 1 {
+		t.Errorf("expected the feature %s reported enabled, got %v", ResourceQuota, value)
+	}
+}
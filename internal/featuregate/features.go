@@ -0,0 +1,43 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package featuregate
+
+import (
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+)
+
+const (
+	// ResourceQuota reconciles a ResourceQuota sized to the components of the cluster requesting it.
+	ResourceQuota Feature = "ResourceQuota"
+
+	// RecoverVolumeExpansionFailure shrinks the requests of the PVCs in place to recover from the expansion failures,
+	// instead of re-creating the PVCs. It requires the same feature gate enabled in the k8s cluster.
+	RecoverVolumeExpansionFailure Feature = "RecoverVolumeExpansionFailure"
+)
+
+// defaultFeatureGates are the known features of the controllers.
+var defaultFeatureGates = map[Feature]FeatureSpec{
+	ResourceQuota:                 {Default: false, PreRelease: Alpha},
+	RecoverVolumeExpansionFailure: {Default: false, PreRelease: Alpha},
+}
+
+func init() {
+	utilruntime.Must(DefaultMutableFeatureGate.Add(defaultFeatureGates))
+}
@@ -47,6 +47,10 @@ func GetAnalyzer(analyzer *preflightv1beta2.ExtendAnalyze, options *values.Optio
 		return &AnalyzeStorageClassByKb{analyzer: analyzer.StorageClass}, true
 	case analyzer.Taint != nil:
 		return &AnalyzeTaintClassByKb{analyzer: analyzer.Taint, HelmOpts: options}, true
+	case analyzer.VolumeSnapshotClass != nil:
+		return &AnalyzeVolumeSnapshotClassByKb{analyzer: analyzer.VolumeSnapshotClass}, true
+	case analyzer.Webhook != nil:
+		return &AnalyzeWebhookByKb{analyzer: analyzer.Webhook}, true
 	default:
 		return nil, false
 	}
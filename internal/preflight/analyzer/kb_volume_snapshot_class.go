@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	storagev1beta1 "k8s.io/api/storage/v1beta1"
+	"k8s.io/kubectl/pkg/util/storage"
+
+	preflightv1beta2 "github.com/apecloud/kubeblocks/externalapis/preflight/v1beta2"
+	"github.com/apecloud/kubeblocks/internal/preflight/util"
+)
+
+const (
+	VolumeSnapshotClassPath = "cluster-resources/volume-snapshot-classes.json"
+)
+
+type AnalyzeVolumeSnapshotClassByKb struct {
+	analyzer *preflightv1beta2.KBVolumeSnapshotClassAnalyze
+}
+
+func (a *AnalyzeVolumeSnapshotClassByKb) Title() string {
+	return util.TitleOrDefault(a.analyzer.AnalyzeMeta, "KubeBlocks Volume Snapshot Class")
+}
+
+func (a *AnalyzeVolumeSnapshotClassByKb) GetAnalyzer() *preflightv1beta2.KBVolumeSnapshotClassAnalyze {
+	return a.analyzer
+}
+
+func (a *AnalyzeVolumeSnapshotClassByKb) IsExcluded() (bool, error) {
+	return util.IsExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeVolumeSnapshotClassByKb) Analyze(getFile GetCollectedFileContents, findFiles GetChildCollectedFileContents) ([]*analyze.AnalyzeResult, error) {
+	result, err := a.analyzeVolumeSnapshotClass(a.analyzer, getFile)
+	if err != nil {
+		return []*analyze.AnalyzeResult{result}, err
+	}
+	result.Strict = a.analyzer.Strict.BoolOrDefaultFalse()
+	return []*analyze.AnalyzeResult{result}, nil
+}
+
+func (a *AnalyzeVolumeSnapshotClassByKb) analyzeVolumeSnapshotClass(analyzer *preflightv1beta2.KBVolumeSnapshotClassAnalyze, getFile GetCollectedFileContents) (*analyze.AnalyzeResult, error) {
+	volumeSnapshotClassesData, err := getFile(VolumeSnapshotClassPath)
+	// the volume snapshot classes are not collected if the CRD is absent
+	if err != nil || len(volumeSnapshotClassesData) == 0 {
+		return newAnalyzeResult(a.Title(), WarnType, a.analyzer.Outcomes), nil
+	}
+
+	var volumeSnapshotClasses snapshotv1.VolumeSnapshotClassList
+	if err = json.Unmarshal(volumeSnapshotClassesData, &volumeSnapshotClasses); err != nil {
+		return newWarnResultWithMessage(a.Title(), fmt.Sprintf("unmarshal jsonfile failed, err:%v", err)), err
+	}
+	if len(volumeSnapshotClasses.Items) == 0 {
+		return newAnalyzeResult(a.Title(), WarnType, a.analyzer.Outcomes), nil
+	}
+
+	provisioner := getStorageClassProvisioner(analyzer.StorageClassName, getFile)
+	// any VolumeSnapshotClass is fine if the provisioner is unknown
+	if provisioner == "" {
+		return newAnalyzeResult(a.Title(), PassType, a.analyzer.Outcomes), nil
+	}
+	for _, volumeSnapshotClass := range volumeSnapshotClasses.Items {
+		if volumeSnapshotClass.Driver == provisioner {
+			return newAnalyzeResult(a.Title(), PassType, a.analyzer.Outcomes), nil
+		}
+	}
+	return newAnalyzeResult(a.Title(), WarnType, a.analyzer.Outcomes), nil
+}
+
+// getStorageClassProvisioner gets the provisioner of the specified StorageClass, or the default one if the name is empty.
+func getStorageClassProvisioner(storageClassName string, getFile GetCollectedFileContents) string {
+	storageClassesData, err := getFile(StorageClassPath)
+	if err != nil {
+		return ""
+	}
+	var storageClasses storagev1beta1.StorageClassList
+	if err = json.Unmarshal(storageClassesData, &storageClasses); err != nil {
+		return ""
+	}
+	for _, storageClass := range storageClasses.Items {
+		if storageClassName == "" && storageClass.Annotations[storage.IsDefaultStorageClassAnnotation] == "true" {
+			return storageClass.Provisioner
+		}
+		if storageClassName != "" && storageClass.Name == storageClassName {
+			return storageClass.Provisioner
+		}
+	}
+	return ""
+}
+
+var _ KBAnalyzer = &AnalyzeVolumeSnapshotClassByKb{}
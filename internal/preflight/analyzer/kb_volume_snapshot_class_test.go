@@ -0,0 +1,133 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	troubleshoot "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	storagev1beta1 "k8s.io/api/storage/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/util/storage"
+
+	preflightv1beta2 "github.com/apecloud/kubeblocks/externalapis/preflight/v1beta2"
+)
+
+var _ = Describe("kb_volume_snapshot_class_test", func() {
+	var (
+		analyzer AnalyzeVolumeSnapshotClassByKb
+	)
+
+	mockGetFile := func(volumeSnapshotClasses *snapshotv1.VolumeSnapshotClassList, storageClasses *storagev1beta1.StorageClassList) GetCollectedFileContents {
+		return func(filename string) ([]byte, error) {
+			switch {
+			case filename == VolumeSnapshotClassPath && volumeSnapshotClasses != nil:
+				return json.Marshal(volumeSnapshotClasses)
+			case filename == StorageClassPath && storageClasses != nil:
+				return json.Marshal(storageClasses)
+			}
+			return nil, errors.New("file not collected")
+		}
+	}
+
+	Context("analyze volume snapshot class test", func() {
+		BeforeEach(func() {
+			analyzer = AnalyzeVolumeSnapshotClassByKb{
+				analyzer: &preflightv1beta2.KBVolumeSnapshotClassAnalyze{
+					Outcomes: []*troubleshoot.Outcome{
+						{
+							Pass: &troubleshoot.SingleOutcome{
+								Message: "analyze volume snapshot class success",
+							},
+							Warn: &troubleshoot.SingleOutcome{
+								Message: "analyze volume snapshot class warn",
+							},
+						},
+					}}}
+		})
+
+		It("Analyze test, and the volume snapshot classes are not collected", func() {
+			Expect(analyzer.IsExcluded()).Should(BeFalse())
+			res, err := analyzer.Analyze(mockGetFile(nil, nil), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsWarn).Should(BeTrue())
+			Expect(res[0].IsPass).Should(BeFalse())
+		})
+
+		It("Analyze test, and no volume snapshot class is present", func() {
+			res, err := analyzer.Analyze(mockGetFile(&snapshotv1.VolumeSnapshotClassList{}, nil), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsWarn).Should(BeTrue())
+			Expect(res[0].IsPass).Should(BeFalse())
+		})
+
+		It("Analyze test, and the return of get file is not volume snapshot classes", func() {
+			getCollectedFileContents := func(filename string) ([]byte, error) {
+				return []byte("test"), nil
+			}
+			res, err := analyzer.Analyze(getCollectedFileContents, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(res[0].IsWarn).Should(BeTrue())
+		})
+
+		It("Analyze test, and the volume snapshot class matches the provisioner of storage class", func() {
+			volumeSnapshotClasses := &snapshotv1.VolumeSnapshotClassList{
+				Items: []snapshotv1.VolumeSnapshotClass{
+					{Driver: "ebs.csi.aws.com"},
+				},
+			}
+			storageClasses := &storagev1beta1.StorageClassList{
+				Items: []storagev1beta1.StorageClass{
+					{
+						ObjectMeta:  metav1.ObjectMeta{Name: "gp3", Annotations: map[string]string{storage.IsDefaultStorageClassAnnotation: "true"}},
+						Provisioner: "ebs.csi.aws.com",
+					},
+					{
+						ObjectMeta:  metav1.ObjectMeta{Name: "local-path"},
+						Provisioner: "rancher.io/local-path",
+					},
+				},
+			}
+
+			By("the default storage class")
+			res, err := analyzer.Analyze(mockGetFile(volumeSnapshotClasses, storageClasses), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsPass).Should(BeTrue())
+			Expect(res[0].IsWarn).Should(BeFalse())
+
+			By("the specified storage class without matched volume snapshot class")
+			analyzer.analyzer.StorageClassName = "local-path"
+			res, err = analyzer.Analyze(mockGetFile(volumeSnapshotClasses, storageClasses), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsPass).Should(BeFalse())
+			Expect(res[0].IsWarn).Should(BeTrue())
+
+			By("the storage classes are not collected")
+			res, err = analyzer.Analyze(mockGetFile(volumeSnapshotClasses, nil), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsPass).Should(BeTrue())
+		})
+	})
+})
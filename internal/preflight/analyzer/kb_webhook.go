@@ -0,0 +1,256 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package analyzer
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	preflightv1beta2 "github.com/apecloud/kubeblocks/externalapis/preflight/v1beta2"
+	"github.com/apecloud/kubeblocks/internal/preflight/util"
+)
+
+const (
+	MutatingWebhookConfigurationsPath   = "cluster-resources/kubeblocks-mutating-webhook-configurations.json"
+	ValidatingWebhookConfigurationsPath = "cluster-resources/kubeblocks-validating-webhook-configurations.json"
+	WebhookEndpointsPath                = "cluster-resources/kubeblocks-webhook-endpoints.json"
+	WebhookServingCertPath              = "cluster-resources/kubeblocks-webhook-serving-cert.pem"
+	WebhookDryRunErrorsPath             = "cluster-resources/kubeblocks-webhook-dry-run-errors.json"
+
+	// WebhookCertExpiryWarningPeriod is the period before the expiry of the webhook certificates to warn
+	WebhookCertExpiryWarningPeriod = 30 * 24 * time.Hour
+)
+
+type AnalyzeWebhookByKb struct {
+	analyzer *preflightv1beta2.KBWebhookAnalyze
+}
+
+func (a *AnalyzeWebhookByKb) Title() string {
+	return util.TitleOrDefault(a.analyzer.AnalyzeMeta, "KubeBlocks Webhooks")
+}
+
+func (a *AnalyzeWebhookByKb) GetAnalyzer() *preflightv1beta2.KBWebhookAnalyze {
+	return a.analyzer
+}
+
+func (a *AnalyzeWebhookByKb) IsExcluded() (bool, error) {
+	return util.IsExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeWebhookByKb) Analyze(getFile GetCollectedFileContents, findFiles GetChildCollectedFileContents) ([]*analyze.AnalyzeResult, error) {
+	result, err := a.analyzeWebhook(getFile)
+	if err != nil {
+		return []*analyze.AnalyzeResult{result}, err
+	}
+	result.Strict = a.analyzer.Strict.BoolOrDefaultFalse()
+	return []*analyze.AnalyzeResult{result}, nil
+}
+
+// webhookFindings accumulates the problems found in the webhooks, the result type is the most severe one.
+type webhookFindings struct {
+	resultType string
+	messages   []string
+}
+
+func (f *webhookFindings) add(resultType, message string) {
+	if resultType == FailType || (resultType == WarnType && f.resultType != FailType) {
+		f.resultType = resultType
+	}
+	f.messages = append(f.messages, message)
+}
+
+func (a *AnalyzeWebhookByKb) analyzeWebhook(getFile GetCollectedFileContents) (*analyze.AnalyzeResult, error) {
+	mutatingData, err := getFile(MutatingWebhookConfigurationsPath)
+	if err != nil {
+		return newWarnResultWithMessage(a.Title(), "the KubeBlocks webhook configurations are not collected"), nil
+	}
+	var mutating admissionregistrationv1.MutatingWebhookConfigurationList
+	if err = json.Unmarshal(mutatingData, &mutating); err != nil {
+		return newWarnResultWithMessage(a.Title(), fmt.Sprintf("unmarshal jsonfile failed, err:%v", err)), err
+	}
+	var validating admissionregistrationv1.ValidatingWebhookConfigurationList
+	if validatingData, err := getFile(ValidatingWebhookConfigurationsPath); err == nil {
+		if err = json.Unmarshal(validatingData, &validating); err != nil {
+			return newWarnResultWithMessage(a.Title(), fmt.Sprintf("unmarshal jsonfile failed, err:%v", err)), err
+		}
+	}
+
+	// webhooks are disabled or KubeBlocks is not installed yet
+	clientConfigs := map[string]admissionregistrationv1.WebhookClientConfig{}
+	var webhookNames []string
+	for _, c := range mutating.Items {
+		for _, w := range c.Webhooks {
+			clientConfigs[w.Name] = w.ClientConfig
+			webhookNames = append(webhookNames, w.Name)
+		}
+	}
+	for _, c := range validating.Items {
+		for _, w := range c.Webhooks {
+			clientConfigs[w.Name] = w.ClientConfig
+			webhookNames = append(webhookNames, w.Name)
+		}
+	}
+	if len(webhookNames) == 0 {
+		return newAnalyzeResult(a.Title(), PassType, a.analyzer.Outcomes), nil
+	}
+
+	findings := &webhookFindings{resultType: PassType}
+	a.analyzeWebhookServices(webhookNames, clientConfigs, getFile, findings)
+	a.analyzeWebhookDryRun(getFile, findings)
+	a.analyzeWebhookCerts(webhookNames, clientConfigs, getFile, findings)
+
+	result := newAnalyzeResult(a.Title(), findings.resultType, a.analyzer.Outcomes)
+	if len(findings.messages) > 0 {
+		result.Message += " " + strings.Join(findings.messages, "; ")
+	}
+	return result, nil
+}
+
+// analyzeWebhookServices checks whether the services the webhooks point at have ready endpoints.
+func (a *AnalyzeWebhookByKb) analyzeWebhookServices(webhookNames []string,
+	clientConfigs map[string]admissionregistrationv1.WebhookClientConfig,
+	getFile GetCollectedFileContents,
+	findings *webhookFindings) {
+	endpointsData, err := getFile(WebhookEndpointsPath)
+	if err != nil {
+		findings.add(WarnType, "the endpoints of the webhook services are not collected")
+		return
+	}
+	var endpointsList corev1.EndpointsList
+	if err = json.Unmarshal(endpointsData, &endpointsList); err != nil {
+		findings.add(WarnType, fmt.Sprintf("unmarshal endpoints failed, err:%v", err))
+		return
+	}
+	readyServices := map[string]bool{}
+	for _, endpoints := range endpointsList.Items {
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				readyServices[endpoints.Namespace+"/"+endpoints.Name] = true
+				break
+			}
+		}
+	}
+
+	reported := map[string]bool{}
+	for _, name := range webhookNames {
+		svc := clientConfigs[name].Service
+		if svc == nil {
+			continue
+		}
+		key := svc.Namespace + "/" + svc.Name
+		if readyServices[key] || reported[key] {
+			continue
+		}
+		reported[key] = true
+		findings.add(FailType, fmt.Sprintf("the webhook service %s has no ready endpoints", key))
+	}
+}
+
+// analyzeWebhookDryRun checks whether the dry-run creation of a Cluster round-trips the admission webhooks,
+// being denied by the webhooks means they are serving.
+func (a *AnalyzeWebhookByKb) analyzeWebhookDryRun(getFile GetCollectedFileContents, findings *webhookFindings) {
+	dryRunData, err := getFile(WebhookDryRunErrorsPath)
+	// the dry-run is not performed
+	if err != nil {
+		return
+	}
+	var dryRunErrors []string
+	if err = json.Unmarshal(dryRunData, &dryRunErrors); err != nil {
+		findings.add(WarnType, fmt.Sprintf("unmarshal dry-run errors failed, err:%v", err))
+		return
+	}
+	for _, msg := range dryRunErrors {
+		switch {
+		case strings.Contains(msg, "failed calling webhook"):
+			findings.add(FailType, fmt.Sprintf("the admission request failed to round-trip: %s", msg))
+		case strings.Contains(msg, "denied the request"), strings.Contains(msg, "already exists"):
+			continue
+		default:
+			findings.add(WarnType, fmt.Sprintf("unable to verify the admission round-trip: %s", msg))
+		}
+	}
+}
+
+// analyzeWebhookCerts checks the expiry of the CA bundles of the webhooks and the serving certificate,
+// the earliest notAfter is reported.
+func (a *AnalyzeWebhookByKb) analyzeWebhookCerts(webhookNames []string,
+	clientConfigs map[string]admissionregistrationv1.WebhookClientConfig,
+	getFile GetCollectedFileContents,
+	findings *webhookFindings) {
+	var certs []*x509.Certificate
+	for _, name := range webhookNames {
+		certs = append(certs, parsePEMCertificates(clientConfigs[name].CABundle)...)
+	}
+	if servingCert, err := getFile(WebhookServingCertPath); err == nil {
+		certs = append(certs, parsePEMCertificates(servingCert)...)
+	}
+	if len(certs) == 0 {
+		return
+	}
+
+	earliest := certs[0]
+	for _, cert := range certs[1:] {
+		if cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+	notAfter := earliest.NotAfter.UTC().Format(time.RFC3339)
+	remaining := time.Until(earliest.NotAfter)
+	switch {
+	case remaining <= 0:
+		findings.add(FailType, fmt.Sprintf("the webhook certificate %q expired at %s", earliest.Subject.CommonName, notAfter))
+	case remaining < WebhookCertExpiryWarningPeriod:
+		findings.add(WarnType, fmt.Sprintf("the webhook certificate %q expires at %s, in less than %d days",
+			earliest.Subject.CommonName, notAfter, int(WebhookCertExpiryWarningPeriod.Hours()/24)))
+	default:
+		findings.add(PassType, fmt.Sprintf("the webhook certificates are valid until %s", notAfter))
+	}
+}
+
+// parsePEMCertificates parses the certificates in PEM format, the malformed ones are ignored.
+func parsePEMCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for len(data) > 0 {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+var _ KBAnalyzer = &AnalyzeWebhookByKb{}
@@ -0,0 +1,207 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package analyzer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	troubleshoot "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	preflightv1beta2 "github.com/apecloud/kubeblocks/externalapis/preflight/v1beta2"
+)
+
+var _ = Describe("kb_webhook_test", func() {
+	const (
+		namespace   = "kb-system"
+		serviceName = "kubeblocks"
+	)
+
+	var (
+		analyzer AnalyzeWebhookByKb
+		files    map[string][]byte
+	)
+
+	newCert := func(notAfter time.Time) []byte {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: serviceName + "." + namespace + ".svc"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	mockWebhooks := func(caBundle []byte) {
+		clientConfig := admissionregistrationv1.WebhookClientConfig{
+			Service:  &admissionregistrationv1.ServiceReference{Namespace: namespace, Name: serviceName},
+			CABundle: caBundle,
+		}
+		mutating := &admissionregistrationv1.MutatingWebhookConfigurationList{
+			Items: []admissionregistrationv1.MutatingWebhookConfiguration{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "kubeblocks-mutating-webhook-configuration"},
+					Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mcluster.kb.io", ClientConfig: clientConfig}},
+				},
+			},
+		}
+		validating := &admissionregistrationv1.ValidatingWebhookConfigurationList{
+			Items: []admissionregistrationv1.ValidatingWebhookConfiguration{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "kubeblocks-validating-webhook-configuration"},
+					Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vcluster.kb.io", ClientConfig: clientConfig}},
+				},
+			},
+		}
+		files[MutatingWebhookConfigurationsPath], _ = json.Marshal(mutating)
+		files[ValidatingWebhookConfigurationsPath], _ = json.Marshal(validating)
+	}
+
+	mockEndpoints := func(ready bool) {
+		endpoints := corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: serviceName}}
+		if ready {
+			endpoints.Subsets = []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}}
+		}
+		files[WebhookEndpointsPath], _ = json.Marshal(&corev1.EndpointsList{Items: []corev1.Endpoints{endpoints}})
+	}
+
+	mockDryRunErrors := func(errs ...string) {
+		if errs == nil {
+			errs = []string{}
+		}
+		files[WebhookDryRunErrorsPath], _ = json.Marshal(errs)
+	}
+
+	getFile := func(filename string) ([]byte, error) {
+		if data, ok := files[filename]; ok {
+			return data, nil
+		}
+		return nil, errors.New("file not collected")
+	}
+
+	Context("analyze webhook test", func() {
+		BeforeEach(func() {
+			files = map[string][]byte{}
+			analyzer = AnalyzeWebhookByKb{
+				analyzer: &preflightv1beta2.KBWebhookAnalyze{
+					Outcomes: []*troubleshoot.Outcome{
+						{
+							Fail: &troubleshoot.SingleOutcome{
+								Message: "analyze webhook fail",
+							},
+							Warn: &troubleshoot.SingleOutcome{
+								Message: "analyze webhook warn",
+							},
+							Pass: &troubleshoot.SingleOutcome{
+								Message: "analyze webhook success",
+							},
+						},
+					}}}
+		})
+
+		It("Analyze test, and the webhook configurations are not collected", func() {
+			Expect(analyzer.IsExcluded()).Should(BeFalse())
+			res, err := analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsWarn).Should(BeTrue())
+		})
+
+		It("Analyze test, and there is no webhook", func() {
+			files[MutatingWebhookConfigurationsPath], _ = json.Marshal(&admissionregistrationv1.MutatingWebhookConfigurationList{})
+			files[ValidatingWebhookConfigurationsPath], _ = json.Marshal(&admissionregistrationv1.ValidatingWebhookConfigurationList{})
+			res, err := analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsPass).Should(BeTrue())
+		})
+
+		It("Analyze test, and the webhooks are healthy", func() {
+			mockWebhooks(newCert(time.Now().Add(365 * 24 * time.Hour)))
+			mockEndpoints(true)
+			mockDryRunErrors(`admission webhook "vcluster.kb.io" denied the request: ClusterDefinition.apps.kubeblocks.io "kb-webhook-check" not found`)
+			res, err := analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsPass).Should(BeTrue())
+			Expect(res[0].Message).Should(ContainSubstring("valid until"))
+		})
+
+		It("Analyze test, and the webhook service has no ready endpoints", func() {
+			mockWebhooks(newCert(time.Now().Add(365 * 24 * time.Hour)))
+			mockEndpoints(false)
+			mockDryRunErrors()
+			res, err := analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsFail).Should(BeTrue())
+			Expect(res[0].Message).Should(ContainSubstring(namespace + "/" + serviceName))
+		})
+
+		It("Analyze test, and the admission request fails to round-trip", func() {
+			mockWebhooks(newCert(time.Now().Add(365 * 24 * time.Hour)))
+			mockEndpoints(true)
+			mockDryRunErrors(`Internal error occurred: failed calling webhook "mcluster.kb.io": failed to call webhook: x509: certificate has expired or is not yet valid`)
+			res, err := analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsFail).Should(BeTrue())
+
+			By("the dry-run fails for other reasons")
+			mockDryRunErrors(`clusters.apps.kubeblocks.io is forbidden: User "test" cannot create resource "clusters"`)
+			res, err = analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsWarn).Should(BeTrue())
+		})
+
+		It("Analyze test, and the webhook certificate is about to expire", func() {
+			mockWebhooks(newCert(time.Now().Add(365 * 24 * time.Hour)))
+			mockEndpoints(true)
+			mockDryRunErrors()
+
+			By("the serving certificate expires in less than 30 days")
+			files[WebhookServingCertPath] = newCert(time.Now().Add(10 * 24 * time.Hour))
+			res, err := analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsWarn).Should(BeTrue())
+			Expect(res[0].Message).Should(ContainSubstring("less than 30 days"))
+
+			By("the serving certificate has expired")
+			files[WebhookServingCertPath] = newCert(time.Now().Add(-time.Minute))
+			res, err = analyzer.Analyze(getFile, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res[0].IsFail).Should(BeTrue())
+			Expect(res[0].Message).Should(ContainSubstring("expired"))
+		})
+	})
+})
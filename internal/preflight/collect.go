@@ -24,6 +24,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -32,6 +34,8 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/logger"
 	"github.com/replicatedhq/troubleshoot/pkg/preflight"
 	"helm.sh/helm/v3/pkg/cli/values"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
@@ -39,7 +43,11 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	preflightv1beta2 "github.com/apecloud/kubeblocks/externalapis/preflight/v1beta2"
+	"github.com/apecloud/kubeblocks/internal/cli/types"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	kbanalyzer "github.com/apecloud/kubeblocks/internal/preflight/analyzer"
 	kbcollector "github.com/apecloud/kubeblocks/internal/preflight/collector"
 	viper "github.com/apecloud/kubeblocks/internal/viperx"
 )
@@ -47,6 +55,11 @@ import (
 const (
 	StorageClassPath       = "cluster-resources/storage-classes.json"
 	StorageClassErrorsPath = "cluster-resources/storage-classes-errors.json"
+
+	VolumeSnapshotClassPath = "cluster-resources/volume-snapshot-classes.json"
+
+	// webhookCheckClusterName is the name of the Cluster created in dry-run mode to check the webhooks
+	webhookCheckClusterName = "kb-webhook-check"
 )
 
 func CollectPreflight(f cmdutil.Factory, helmOpts *values.Options, ctx context.Context, kbPreflight *preflightv1beta2.Preflight, kbHostPreflight *preflightv1beta2.HostPreflight, progressCh chan interface{}) ([]preflight.CollectResult, error) {
@@ -313,6 +326,8 @@ func CollectCluster(ctx context.Context,
 		}
 	}
 	retryErrorCausedByMetricsUnavailable(ctx, opts, client, allCollectedData)
+	collectVolumeSnapshotClasses(ctx, kbPreflight, client, allCollectedData)
+	collectKubeBlocksWebhooks(ctx, kbPreflight, client, allCollectedData)
 	collectResult.AllCollectedData = allCollectedData
 	return collectResult, nil
 }
@@ -353,6 +368,156 @@ func handleStorageClassError(ctx context.Context, _ preflight.CollectOpts, clien
 	data[StorageClassPath] = scBytes
 }
 
+// collectVolumeSnapshotClasses collects the VolumeSnapshotClasses if they are required by the analyzers,
+// nothing is collected if the VolumeSnapshotClass CRD is absent.
+func collectVolumeSnapshotClasses(ctx context.Context, kbPreflight *preflightv1beta2.Preflight, client *kubernetes.Clientset, data map[string][]byte) {
+	required := false
+	for _, analyzer := range kbPreflight.Spec.ExtendAnalyzers {
+		if analyzer.VolumeSnapshotClass != nil {
+			required = true
+			break
+		}
+	}
+	if !required {
+		return
+	}
+	volumeSnapshotClasses, err := client.StorageV1().RESTClient().Get().
+		AbsPath("/apis/snapshot.storage.k8s.io/v1/volumesnapshotclasses").
+		DoRaw(ctx)
+	if err != nil {
+		return
+	}
+	data[VolumeSnapshotClassPath] = volumeSnapshotClasses
+}
+
+// collectKubeBlocksWebhooks collects the data of the KubeBlocks webhooks if they are required by the analyzers.
+func collectKubeBlocksWebhooks(ctx context.Context, kbPreflight *preflightv1beta2.Preflight, client *kubernetes.Clientset, data map[string][]byte) {
+	required := false
+	for _, analyzer := range kbPreflight.Spec.ExtendAnalyzers {
+		if analyzer.Webhook != nil {
+			required = true
+			break
+		}
+	}
+	if !required {
+		return
+	}
+	CollectKubeBlocksWebhooks(ctx, client, data)
+}
+
+// CollectKubeBlocksWebhooks collects the KubeBlocks webhook configurations, the endpoints of the webhook services,
+// the serving certificate and the errors of a dry-run creation of Cluster, which round-trips the admission webhooks.
+// Nothing but the webhook configurations is collected if there is no KubeBlocks webhook.
+func CollectKubeBlocksWebhooks(ctx context.Context, client kubernetes.Interface, data map[string][]byte) {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s,%s=%s",
+		constant.AppInstanceLabelKey, types.KubeBlocksReleaseName,
+		constant.AppNameLabelKey, types.KubeBlocksChartName)}
+	mutating, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, opts)
+	if err != nil {
+		return
+	}
+	validating, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, opts)
+	if err != nil {
+		return
+	}
+	if data[kbanalyzer.MutatingWebhookConfigurationsPath], err = json.Marshal(mutating); err != nil {
+		return
+	}
+	if data[kbanalyzer.ValidatingWebhookConfigurationsPath], err = json.Marshal(validating); err != nil {
+		return
+	}
+
+	services := map[string]*admissionregistrationv1.ServiceReference{}
+	for _, c := range mutating.Items {
+		for _, w := range c.Webhooks {
+			if w.ClientConfig.Service != nil {
+				services[w.ClientConfig.Service.Namespace+"/"+w.ClientConfig.Service.Name] = w.ClientConfig.Service
+			}
+		}
+	}
+	for _, c := range validating.Items {
+		for _, w := range c.Webhooks {
+			if w.ClientConfig.Service != nil {
+				services[w.ClientConfig.Service.Namespace+"/"+w.ClientConfig.Service.Name] = w.ClientConfig.Service
+			}
+		}
+	}
+	if len(mutating.Items) == 0 && len(validating.Items) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(services))
+	for key := range services {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	namespace := metav1.NamespaceDefault
+	endpointsList := &corev1.EndpointsList{}
+	for _, key := range keys {
+		svc := services[key]
+		namespace = svc.Namespace
+		endpoints, err := client.CoreV1().Endpoints(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		endpointsList.Items = append(endpointsList.Items, *endpoints)
+
+		// the serving certificate is mounted from the tls-pair secret in the namespace of the webhook service
+		if _, ok := data[kbanalyzer.WebhookServingCertPath]; ok {
+			continue
+		}
+		secrets, err := client.CoreV1().Secrets(svc.Namespace).List(ctx, opts)
+		if err != nil {
+			continue
+		}
+		for _, secret := range secrets.Items {
+			if secret.Type == corev1.SecretTypeTLS && strings.HasSuffix(secret.Name, ".svc.tls-pair") {
+				data[kbanalyzer.WebhookServingCertPath] = secret.Data[corev1.TLSCertKey]
+				break
+			}
+		}
+	}
+	if data[kbanalyzer.WebhookEndpointsPath], err = json.Marshal(endpointsList); err != nil {
+		return
+	}
+
+	dryRunErrors := []string{}
+	if err = dryRunCreateCluster(ctx, client, namespace); err != nil {
+		dryRunErrors = append(dryRunErrors, err.Error())
+	}
+	data[kbanalyzer.WebhookDryRunErrorsPath], _ = json.Marshal(dryRunErrors)
+}
+
+// dryRunCreateCluster creates a minimal Cluster in dry-run mode, the request is admitted by the KubeBlocks webhooks
+// but never persisted.
+func dryRunCreateCluster(ctx context.Context, client kubernetes.Interface, namespace string) error {
+	cluster := &appsv1alpha1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1alpha1.GroupVersion.String(),
+			Kind:       appsv1alpha1.ClusterKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      webhookCheckClusterName,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.ClusterSpec{
+			ClusterDefRef:     webhookCheckClusterName,
+			TerminationPolicy: appsv1alpha1.Delete,
+		},
+	}
+	body, err := json.Marshal(cluster)
+	if err != nil {
+		return err
+	}
+	return client.CoreV1().RESTClient().Post().
+		AbsPath("/apis", appsv1alpha1.GroupVersion.Group, appsv1alpha1.GroupVersion.Version, "namespaces", namespace, "clusters").
+		Param("dryRun", metav1.DryRunAll).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		Do(ctx).
+		Error()
+}
+
 func CollectRemoteData(ctx context.Context, preflightSpec *preflightv1beta2.HostPreflight, f cmdutil.Factory, progressCh chan interface{}) (*preflight.CollectResult, error) {
 	v := viper.GetViper()
 
@@ -20,9 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"encoding/json"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
 )
 
 type MockClusterFactory struct {
@@ -84,6 +89,67 @@ func (factory *MockClusterFactory) SetServiceAccountName(serviceAccountName stri
 	return factory
 }
 
+func (factory *MockClusterFactory) SetSchedulerName(schedulerName string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].SchedulerName = schedulerName
+	}
+	return factory
+}
+
+func (factory *MockClusterFactory) SetSchedulingPolicy(policy *appsv1alpha1.SchedulingPolicy) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].SchedulingPolicy = policy
+	}
+	return factory
+}
+
+func (factory *MockClusterFactory) AddDNSSearchDomain(domain string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].DNSSearchDomains = append(comps[len(comps)-1].DNSSearchDomains, domain)
+	}
+	return factory
+}
+
+func (factory *MockClusterFactory) AddProbeOverride(override appsv1alpha1.ContainerProbeOverride) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].ProbeOverrides = append(comps[len(comps)-1].ProbeOverrides, override)
+	}
+	return factory
+}
+
+func (factory *MockClusterFactory) AddWorkloadAnnotation(key, value string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		if comps[len(comps)-1].WorkloadAnnotations == nil {
+			comps[len(comps)-1].WorkloadAnnotations = map[string]string{}
+		}
+		comps[len(comps)-1].WorkloadAnnotations[key] = value
+	}
+	return factory
+}
+
+func (factory *MockClusterFactory) SetPriorityClassName(priorityClassName string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].PriorityClassName = priorityClassName
+	}
+	return factory
+}
+
+func (factory *MockClusterFactory) SetEvictionPolicy(priority appsv1alpha1.EvictionPriority) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].EvictionPolicy = &appsv1alpha1.ComponentEvictionPolicy{
+			Priority: priority,
+		}
+	}
+	return factory
+}
+
 func (factory *MockClusterFactory) SetResources(resources corev1.ResourceRequirements) *MockClusterFactory {
 	comps := factory.Get().Spec.ComponentSpecs
 	if len(comps) > 0 {
@@ -120,6 +186,37 @@ func (factory *MockClusterFactory) SetClassDefRef(classDefRef *appsv1alpha1.Clas
 	return factory
 }
 
+func (factory *MockClusterFactory) AddComponentTmpfsVolume(name string, sizeLimit resource.Quantity, mountPath string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].TmpfsVolumes = append(comps[len(comps)-1].TmpfsVolumes, appsv1alpha1.ClusterComponentTmpfsVolume{
+			Name:      name,
+			SizeLimit: sizeLimit,
+			MountPath: mountPath,
+		})
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetInitScriptConfigMap(name string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].InitScriptConfigMap = name
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetVolumeClaimRetentionPolicy(policy appsv1alpha1.VolumeClaimRetentionPolicyType) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].VolumeClaimRetentionPolicy = policy
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
 func (factory *MockClusterFactory) AddComponentToleration(toleration corev1.Toleration) *MockClusterFactory {
 	comps := factory.Get().Spec.ComponentSpecs
 	if len(comps) > 0 {
@@ -152,6 +249,17 @@ func (factory *MockClusterFactory) AddVolumeClaimTemplate(volumeName string,
 	return factory
 }
 
+func (factory *MockClusterFactory) AddEphemeralVolumeClaimTemplate(volumeName string,
+	pvcSpec appsv1alpha1.PersistentVolumeClaimSpec) *MockClusterFactory {
+	factory.AddVolumeClaimTemplate(volumeName, pvcSpec)
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		vcts := comps[len(comps)-1].VolumeClaimTemplates
+		vcts[len(vcts)-1].Ephemeral = true
+	}
+	return factory
+}
+
 func (factory *MockClusterFactory) SetMonitor(monitor bool) *MockClusterFactory {
 	comps := factory.Get().Spec.ComponentSpecs
 	if len(comps) > 0 {
@@ -161,6 +269,39 @@ func (factory *MockClusterFactory) SetMonitor(monitor bool) *MockClusterFactory
 	return factory
 }
 
+func (factory *MockClusterFactory) SetMonitorResource(kind appsv1alpha1.MonitorResourceKind) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].MonitorResource = kind
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetMonitorScrapeInterval(d string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].MonitorScrapeInterval = d
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) AddMonitorRelabel(cfg appsv1alpha1.MonitorRelabelConfig) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comp := &comps[len(comps)-1]
+		comp.MonitorRelabelings = append(comp.MonitorRelabelings, cfg)
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetLightweightMode(lightweight bool) *MockClusterFactory {
+	factory.Get().Spec.LightweightMode = lightweight
+	return factory
+}
+
 func (factory *MockClusterFactory) SetSwitchPolicy(switchPolicy *appsv1alpha1.ClusterSwitchPolicy) *MockClusterFactory {
 	comps := factory.Get().Spec.ComponentSpecs
 	if len(comps) > 0 {
@@ -179,6 +320,33 @@ func (factory *MockClusterFactory) SetTLS(tls bool) *MockClusterFactory {
 	return factory
 }
 
+func (factory *MockClusterFactory) SetRoleServices(roleServices bool) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].RoleServices = roleServices
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetStartupProbe(probe *corev1.Probe) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].StartupProbe = probe
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetCredentialRotation(period string) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].CredentialRotation = &appsv1alpha1.CredentialRotation{Period: period}
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
 func (factory *MockClusterFactory) SetIssuer(issuer *appsv1alpha1.Issuer) *MockClusterFactory {
 	comps := factory.Get().Spec.ComponentSpecs
 	if len(comps) > 0 {
@@ -203,11 +371,57 @@ func (factory *MockClusterFactory) AddService(serviceName string, serviceType co
 	return factory
 }
 
-func (factory *MockClusterFactory) SetBackup(backup *appsv1alpha1.ClusterBackup) *MockClusterFactory {
+func (factory *MockClusterFactory) SetSessionAffinity(affinity corev1.ServiceAffinity) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comps[len(comps)-1].SessionAffinity = affinity
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetServiceSessionAffinity(affinity corev1.ServiceAffinity) *MockClusterFactory {
+	comps := factory.Get().Spec.ComponentSpecs
+	if len(comps) > 0 {
+		comp := comps[len(comps)-1]
+		if len(comp.Services) > 0 {
+			comp.Services[len(comp.Services)-1].SessionAffinity = affinity
+		}
+		comps[len(comps)-1] = comp
+	}
+	factory.Get().Spec.ComponentSpecs = comps
+	return factory
+}
+
+func (factory *MockClusterFactory) SetBackupSpec(backup *appsv1alpha1.ClusterBackup) *MockClusterFactory {
 	factory.Get().Spec.Backup = backup
 	return factory
 }
 
+func (factory *MockClusterFactory) SetBackup(enabled bool, retention string) *MockClusterFactory {
+	factory.Get().Spec.Backup = &appsv1alpha1.ClusterBackup{
+		Enabled:         &enabled,
+		RetentionPeriod: dpv1alpha1.RetentionPeriod(retention),
+	}
+	return factory
+}
+
+// SetRestoreFromBackup sets the annotation to restore the component from the backup in the namespace of the cluster,
+// it can be called for each component to restore.
+func (factory *MockClusterFactory) SetRestoreFromBackup(backupName string, componentName string) *MockClusterFactory {
+	cluster := factory.Get()
+	backupMap := map[string]map[string]string{}
+	if value, ok := cluster.Annotations[constant.RestoreFromBackupAnnotationKey]; ok {
+		_ = json.Unmarshal([]byte(value), &backupMap)
+	}
+	backupMap[componentName] = map[string]string{
+		constant.BackupNameKeyForRestore:      backupName,
+		constant.BackupNamespaceKeyForRestore: cluster.Namespace,
+	}
+	b, _ := json.Marshal(backupMap)
+	return factory.AddAnnotations(constant.RestoreFromBackupAnnotationKey, string(b))
+}
+
 func (factory *MockClusterFactory) SetServiceRefs(serviceRefs []appsv1alpha1.ServiceRef) *MockClusterFactory {
 	comps := factory.Get().Spec.ComponentSpecs
 	if len(comps) > 0 {
@@ -77,13 +77,21 @@ func CheckedCreateK8sResource(testCtx *testutil.TestContext, obj client.Object)
 	return obj
 }
 
+// GetClusterComponentStatus gets the whole component status of testing cluster for verification,
+// e.g. the phase, message and observedGeneration.
+func GetClusterComponentStatus(testCtx *testutil.TestContext, clusterKey client.ObjectKey, componentName string) func(g gomega.Gomega) appsv1alpha1.ClusterComponentStatus {
+	return func(g gomega.Gomega) appsv1alpha1.ClusterComponentStatus {
+		tmpCluster := &appsv1alpha1.Cluster{}
+		g.Expect(testCtx.Cli.Get(context.Background(), clusterKey, tmpCluster)).Should(gomega.Succeed())
+		return tmpCluster.Status.Components[componentName]
+	}
+}
+
 // GetClusterComponentPhase gets the component phase of testing cluster for verification.
 func GetClusterComponentPhase(testCtx *testutil.TestContext, clusterKey types.NamespacedName, componentName string) func(g gomega.Gomega) appsv1alpha1.ClusterComponentPhase {
+	getStatus := GetClusterComponentStatus(testCtx, clusterKey, componentName)
 	return func(g gomega.Gomega) appsv1alpha1.ClusterComponentPhase {
-		tmpCluster := &appsv1alpha1.Cluster{}
-		g.Expect(testCtx.Cli.Get(context.Background(), client.ObjectKey{Name: clusterKey.Name,
-			Namespace: clusterKey.Namespace}, tmpCluster)).Should(gomega.Succeed())
-		return tmpCluster.Status.Components[componentName].Phase
+		return getStatus(g).Phase
 	}
 }
 
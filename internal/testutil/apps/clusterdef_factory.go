@@ -222,6 +222,24 @@ func (factory *MockClusterDefFactory) AddServiceRefDeclarations(serviceRefDeclar
 	return factory
 }
 
+func (factory *MockClusterDefFactory) SetSharedSecret(sharedSecret *appsv1alpha1.SharedSecretMount) *MockClusterDefFactory {
+	comp := factory.getLastCompDef()
+	if comp == nil {
+		return factory
+	}
+	comp.SharedSecret = sharedSecret
+	return factory
+}
+
+func (factory *MockClusterDefFactory) SetBackendDiscovery(backendDiscovery *appsv1alpha1.BackendDiscovery) *MockClusterDefFactory {
+	comp := factory.getLastCompDef()
+	if comp == nil {
+		return factory
+	}
+	comp.BackendDiscovery = backendDiscovery
+	return factory
+}
+
 func (factory *MockClusterDefFactory) AddInitContainerVolumeMounts(containerName string, volumeMounts []corev1.VolumeMount) *MockClusterDefFactory {
 	comp := factory.getLastCompDef()
 	if comp == nil {
@@ -24,7 +24,10 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
+	dptypes "github.com/apecloud/kubeblocks/internal/dataprotection/types"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 )
 
@@ -51,6 +54,32 @@ func (f *MockBackupFactory) SetBackupMethod(backupMethod string) *MockBackupFact
 	return f
 }
 
+func (f *MockBackupFactory) SetBackupType(backupType dpv1alpha1.BackupType) *MockBackupFactory {
+	return f.AddLabels(dptypes.DataProtectionLabelBackupTypeKey, string(backupType))
+}
+
+// SetTargetCluster labels the backup with the cluster and component it belongs to.
+func (f *MockBackupFactory) SetTargetCluster(cluster *appsv1alpha1.Cluster, compName string) *MockBackupFactory {
+	return f.AddLabels(constant.AppInstanceLabelKey, cluster.Name,
+		constant.KBAppComponentLabelKey, compName,
+		dptypes.DataProtectionLabelClusterUIDKey, string(cluster.UID))
+}
+
+func (f *MockBackupFactory) SetDeletionPolicy(deletionPolicy dpv1alpha1.BackupDeletionPolicy) *MockBackupFactory {
+	f.Get().Spec.DeletionPolicy = deletionPolicy
+	return f
+}
+
+func (f *MockBackupFactory) SetRetentionPeriod(retentionPeriod dpv1alpha1.RetentionPeriod) *MockBackupFactory {
+	f.Get().Spec.RetentionPeriod = retentionPeriod
+	return f
+}
+
+func (f *MockBackupFactory) SetParentBackupName(parentBackupName string) *MockBackupFactory {
+	f.Get().Spec.ParentBackupName = parentBackupName
+	return f
+}
+
 func (f *MockBackupFactory) SetLabels(labels map[string]string) *MockBackupFactory {
 	f.Get().SetLabels(labels)
 	return f
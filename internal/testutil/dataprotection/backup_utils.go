@@ -24,11 +24,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	storagev1alpha1 "github.com/apecloud/kubeblocks/apis/storage/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/constant"
+	"github.com/apecloud/kubeblocks/internal/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/internal/dataprotection/utils/boolptr"
 	"github.com/apecloud/kubeblocks/internal/testutil"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
@@ -220,3 +222,48 @@ func MockBackupStatusMethod(backup *dpv1alpha1.Backup, backupMethodName, targetV
 		},
 	}
 }
+
+// MockBackupCompleted mocks the status of a completed backup with the total size.
+func MockBackupCompleted(backup *dpv1alpha1.Backup, totalSize string) {
+	now := metav1.Now()
+	backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+	backup.Status.TotalSize = totalSize
+	if backup.Status.StartTimestamp == nil {
+		backup.Status.StartTimestamp = &now
+	}
+	backup.Status.CompletionTimestamp = &now
+	backup.Status.Duration = &metav1.Duration{Duration: now.Sub(backup.Status.StartTimestamp.Time)}
+}
+
+// NewFakeCompletedBackup creates a completed backup of the target volume as the source of the restore.
+// The backup data is kept in a PVC of the backup repo, or in a VolumeSnapshot if useVolumeSnapshot is true.
+func NewFakeCompletedBackup(testCtx *testutil.TestContext, backupName, targetVolume string, useVolumeSnapshot bool) *dpv1alpha1.Backup {
+	backupMethod := BackupMethodName
+	if useVolumeSnapshot {
+		backupMethod = VSBackupMethodName
+	}
+	backup := NewBackupFactory(testCtx.DefaultNamespace, backupName).
+		SetBackupPolicyName(BackupPolicyName).
+		SetBackupMethod(backupMethod).
+		Create(testCtx).GetObject()
+	if useVolumeSnapshot {
+		NewVolumeSnapshotFactory(testCtx.DefaultNamespace, utils.GetBackupVolumeSnapshotName(backupName, targetVolume)).
+			SetSourcePVCName(targetVolume).
+			Create(testCtx)
+	}
+	Eventually(testapps.GetAndChangeObjStatus(testCtx, client.ObjectKeyFromObject(backup), func(backup *dpv1alpha1.Backup) {
+		if !useVolumeSnapshot {
+			backup.Status.PersistentVolumeClaimName = "backup-pvc"
+		}
+		MockBackupStatusMethod(backup, backupMethod, targetVolume, ActionSetName)
+		MockBackupCompleted(backup, "1Gi")
+	})).Should(Succeed())
+	Expect(testCtx.Cli.Get(testCtx.Ctx, client.ObjectKeyFromObject(backup), backup)).Should(Succeed())
+	return backup
+}
+
+// MockBackupPolicyAvailable mocks the status of an available backup policy.
+func MockBackupPolicyAvailable(backupPolicy *dpv1alpha1.BackupPolicy) {
+	backupPolicy.Status.Phase = dpv1alpha1.AvailablePhase
+	backupPolicy.Status.ObservedGeneration = backupPolicy.Generation
+}
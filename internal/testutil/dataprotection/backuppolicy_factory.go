@@ -23,7 +23,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/internal/constant"
 	testapps "github.com/apecloud/kubeblocks/internal/testutil/apps"
 )
 
@@ -96,6 +98,17 @@ func (f *MockBackupPolicyFactory) SetTarget(keyAndValues ...string) *MockBackupP
 	return f
 }
 
+// SetTargetCluster selects the pods of the cluster component as the backup target.
+func (f *MockBackupPolicyFactory) SetTargetCluster(cluster *appsv1alpha1.Cluster, compName string) *MockBackupPolicyFactory {
+	return f.SetTarget(constant.AppInstanceLabelKey, cluster.Name,
+		constant.KBAppComponentLabelKey, compName)
+}
+
+func (f *MockBackupPolicyFactory) SetTargetPodSelectionStrategy(strategy dpv1alpha1.PodSelectionStrategy) *MockBackupPolicyFactory {
+	f.Get().Spec.Target.PodSelector.Strategy = strategy
+	return f
+}
+
 func (f *MockBackupPolicyFactory) SetTargetConnectionCredential(secretName string) *MockBackupPolicyFactory {
 	f.Get().Spec.Target.ConnectionCredential = &dpv1alpha1.ConnectionCredential{
 		SecretName:  secretName,
@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package testutil
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// LogRecord is a log record captured by the LogSink.
+type LogRecord struct {
+	Name    string
+	Level   int
+	Message string
+	Error   error
+	// Fields are the key-value pairs of the record, including the ones attached to the logger.
+	Fields map[string]string
+}
+
+// LogSink is a logr.LogSink capturing the log records of all verbosity levels for the tests.
+type LogSink struct {
+	name          string
+	keysAndValues []interface{}
+	records       *logRecords
+}
+
+type logRecords struct {
+	sync.Mutex
+	items []LogRecord
+}
+
+var _ logr.LogSink = &LogSink{}
+
+// NewLogSink returns a LogSink, and a logger writing to it.
+func NewLogSink() (*LogSink, logr.Logger) {
+	sink := &LogSink{records: &logRecords{}}
+	return sink, logr.New(sink)
+}
+
+// Records returns the records captured so far.
+func (s *LogSink) Records() []LogRecord {
+	s.records.Lock()
+	defer s.records.Unlock()
+	return append([]LogRecord{}, s.records.items...)
+}
+
+func (s *LogSink) Init(logr.RuntimeInfo) {}
+
+func (s *LogSink) Enabled(int) bool {
+	return true
+}
+
+func (s *LogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.record(level, msg, nil, keysAndValues)
+}
+
+func (s *LogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.record(0, msg, err, keysAndValues)
+}
+
+func (s *LogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	clone := *s
+	clone.keysAndValues = append(append([]interface{}{}, s.keysAndValues...), keysAndValues...)
+	return &clone
+}
+
+func (s *LogSink) WithName(name string) logr.LogSink {
+	clone := *s
+	if clone.name == "" {
+		clone.name = name
+	} else {
+		clone.name = clone.name + "." + name
+	}
+	return &clone
+}
+
+func (s *LogSink) record(level int, msg string, err error, keysAndValues []interface{}) {
+	fields := map[string]string{}
+	kvs := append(append([]interface{}{}, s.keysAndValues...), keysAndValues...)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		fields[fmt.Sprint(kvs[i])] = fmt.Sprint(kvs[i+1])
+	}
+	s.records.Lock()
+	defer s.records.Unlock()
+	s.records.items = append(s.records.items, LogRecord{
+		Name:    s.name,
+		Level:   level,
+		Message: msg,
+		Error:   err,
+		Fields:  fields,
+	})
+}
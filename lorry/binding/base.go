@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -116,6 +117,10 @@ func (ops *BaseOperations) Init(properties component.Properties) {
 		UnlockOperation:       ops.UnlockOps,
 		JoinMemberOperation:   ops.JoinMemberOps,
 		LeaveMemberOperation:  ops.LeaveMemberOps,
+		GetDBStateOperation:   ops.GetDBStateOps,
+		DemoteOperation:       ops.DemoteOps,
+
+		CheckExternalDependencyOperation: ops.CheckExternalDependencyOps,
 	}
 
 	ops.DBAddress = ops.getAddress()
@@ -504,3 +509,120 @@ func (ops *BaseOperations) LeaveMemberOps(ctx context.Context, req *ProbeRequest
 	opsRes["message"] = "left of the current member is complete"
 	return opsRes, nil
 }
+
+// GetDBStateOps reports the state of the current member reported by the DB, e.g. the timestamp of the last operation
+// applied, which tells the member with the newest data among the ones claiming the leader.
+// - "OpsResult['opTimestamp']" is the timestamp of the last operation in decimal.
+func (ops *BaseOperations) GetDBStateOps(ctx context.Context, req *ProbeRequest, resp *ProbeResponse) (OpsResult, error) {
+	opsRes := OpsResult{}
+	manager, err := component.GetDefaultManager()
+	if manager == nil {
+		opsRes["event"] = OperationNotImplemented
+		opsRes["message"] = err.Error()
+		return opsRes, nil
+	}
+
+	dcsStore := dcs.GetStore()
+	var cluster *dcs.Cluster
+	cluster, err = dcsStore.GetCluster()
+	if err != nil {
+		opsRes["event"] = OperationFailed
+		opsRes["message"] = fmt.Sprintf("get cluster from dcs failed: %v", err)
+		return opsRes, err
+	}
+
+	dbState := manager.GetDBState(ctx, cluster)
+	if dbState == nil {
+		opsRes["event"] = OperationFailed
+		opsRes["message"] = "get the state of the current member failed"
+		return opsRes, nil
+	}
+	opsRes["event"] = OperationSuccess
+	// the timestamp is reported as a string, as it may exceed the precision of the json numbers.
+	opsRes["opTimestamp"] = strconv.FormatInt(dbState.OpTimestamp, 10)
+	return opsRes, nil
+}
+
+// DemoteOps demotes the current member if it's the leader, e.g. to recover the split-brain, where multiple members
+// claim the leader and only one of them is kept.
+func (ops *BaseOperations) DemoteOps(ctx context.Context, req *ProbeRequest, resp *ProbeResponse) (OpsResult, error) {
+	opsRes := OpsResult{}
+	manager, err := component.GetDefaultManager()
+	if manager == nil {
+		opsRes["event"] = OperationNotImplemented
+		opsRes["message"] = err.Error()
+		return opsRes, nil
+	}
+
+	if err = manager.Demote(ctx); err != nil {
+		opsRes["event"] = OperationFailed
+		opsRes["message"] = fmt.Sprintf("demote the current member failed: %v", err)
+		return opsRes, err
+	}
+	opsRes["event"] = OperationSuccess
+	opsRes["message"] = "demotion of the current member is complete"
+	return opsRes, nil
+}
+
+// CheckExternalDependencyOps checks whether an external dependency of the cluster is reachable from the current member,
+// which shares the network view of the DB, e.g. the egress policies and the DNS of the pod.
+// - "metadata['host']" and "metadata['port']" are the address to open a TCP connection to.
+// - "metadata['url']" is the URL to send an HTTP GET request to, any status code in [200, 400) indicates it's reachable.
+// - "metadata['timeoutSeconds']" is the number of seconds after which the check times out, 3 by default.
+func (ops *BaseOperations) CheckExternalDependencyOps(ctx context.Context, req *ProbeRequest, resp *ProbeResponse) (OpsResult, error) {
+	opsRes := OpsResult{}
+	opsRes["operation"] = CheckExternalDependencyOperation
+
+	timeout := defaultExternalDependencyTimeout
+	if seconds, err := strconv.Atoi(req.Metadata["timeoutSeconds"]); err == nil && seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case req.Metadata["host"] != "":
+		var conn net.Conn
+		address := net.JoinHostPort(req.Metadata["host"], req.Metadata["port"])
+		if conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", address); err == nil {
+			conn.Close()
+		}
+	case req.Metadata["url"] != "":
+		err = checkHTTPGet(ctx, req.Metadata["url"])
+	default:
+		opsRes["event"] = OperationInvalid
+		opsRes["message"] = "host or url must be set"
+		return opsRes, nil
+	}
+	if err != nil {
+		opsRes["event"] = OperationFailed
+		opsRes["message"] = err.Error()
+		return opsRes, nil
+	}
+	opsRes["event"] = OperationSuccess
+	opsRes["message"] = "the external dependency is reachable"
+	return opsRes, nil
+}
+
+func checkHTTPGet(ctx context.Context, url string) error {
+	client := &http.Client{
+		// the redirects are taken as reachable, the same as the HTTP probes of kubelet.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status code %d of %s", resp.StatusCode, url)
+	}
+	return nil
+}
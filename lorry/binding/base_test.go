@@ -24,6 +24,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 
@@ -253,6 +255,57 @@ func TestInvoke(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("CheckExternalDependency", func(t *testing.T) {
+		invoke := func(metadata map[string]string) OpsResult {
+			opsRes := OpsResult{}
+			req := &ProbeRequest{
+				Metadata:  metadata,
+				Operation: CheckExternalDependencyOperation,
+			}
+			resp, err := p.Invoke(context.Background(), req)
+			if err != nil {
+				t.Errorf("CheckExternalDependency error: %s", err)
+			}
+			if err = json.Unmarshal(resp.Data, &opsRes); err != nil {
+				t.Errorf("CheckExternalDependency failed: %s", err)
+			}
+			return opsRes
+		}
+
+		t.Run("HTTPGet", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/live" {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+			if opsRes := invoke(map[string]string{"url": server.URL + "/live"}); opsRes["event"] != OperationSuccess {
+				t.Errorf("unexpected response: %v", opsRes)
+			}
+			if opsRes := invoke(map[string]string{"url": server.URL + "/unavailable"}); opsRes["event"] != OperationFailed {
+				t.Errorf("unexpected response: %v", opsRes)
+			}
+		})
+
+		t.Run("TCPSocket", func(t *testing.T) {
+			server := p.startFooServer(t)
+			host, port := "127.0.0.1", strconv.Itoa(p.DBPort)
+			if opsRes := invoke(map[string]string{"host": host, "port": port}); opsRes["event"] != OperationSuccess {
+				t.Errorf("unexpected response: %v", opsRes)
+			}
+			stopFooServer(server)
+			if opsRes := invoke(map[string]string{"host": host, "port": port, "timeoutSeconds": "1"}); opsRes["event"] != OperationFailed {
+				t.Errorf("unexpected response: %v", opsRes)
+			}
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			if opsRes := invoke(map[string]string{}); opsRes["event"] != OperationInvalid {
+				t.Errorf("unexpected response: %v", opsRes)
+			}
+		})
+	})
 }
 
 func stopFooServer(server net.Listener) {
@@ -21,6 +21,7 @@ package binding
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/apecloud/kubeblocks/lorry/util"
 )
@@ -50,6 +51,7 @@ const (
 	defaultFailedEventReportFrequency = 1800
 	defaultRoleDetectionThreshold     = 300
 	defaultRoleProbeTimeoutSeconds    = 2
+	defaultExternalDependencyTimeout  = 3 * time.Second
 
 	rsmRoleUpdateMechanismVarName = "KB_RSM_ROLE_UPDATE_MECHANISM"
 	roleProbeTimeoutVarName       = "KB_RSM_ROLE_PROBE_TIMEOUT"
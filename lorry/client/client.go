@@ -23,16 +23,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/internal/cli/exec"
 	intctrlutil "github.com/apecloud/kubeblocks/internal/controllerutil"
 	. "github.com/apecloud/kubeblocks/lorry/util"
@@ -40,6 +44,8 @@ import (
 
 const (
 	urlTemplate = "http://%s:%d/v1.0/bindings/%s"
+
+	defaultExternalDependencyTimeoutSeconds = 3
 )
 
 type Client interface {
@@ -48,8 +54,21 @@ type Client interface {
 
 	// LeaveMember sends a Leave member operation request to Lorry, located on the target pod that is about to leave.
 	LeaveMember(ctx context.Context) error
+
+	// GetOpTimestamp gets the timestamp of the last operation applied by the DB from Lorry, located on the target pod.
+	GetOpTimestamp(ctx context.Context) (int64, error)
+
+	// Demote sends a demote operation request to Lorry, located on the target pod claiming the leader.
+	Demote(ctx context.Context) error
+
+	// CheckExternalDependency checks whether the external dependency is reachable from Lorry, located on the target pod,
+	// which shares the network view of the DB.
+	CheckExternalDependency(ctx context.Context, dependency appsv1alpha1.ExternalDependency) error
 }
 
+// ErrNotImplemented is returned if the operation is not implemented by Lorry, e.g. of an earlier version.
+var ErrNotImplemented = errors.New("the operation is not implemented by lorry")
+
 // HACK: for unit test only.
 var mockClient Client
 var mockClientError error
@@ -196,6 +215,75 @@ func (cli *OperationClient) LeaveMember(ctx context.Context) error {
 	return err
 }
 
+// GetOpTimestamp gets the timestamp of the last operation applied by the DB from Lorry, located on the target pod.
+func (cli *OperationClient) GetOpTimestamp(ctx context.Context) (int64, error) {
+	result, err := cli.Request(ctx, string(GetDBStateOperation))
+	if err != nil {
+		return 0, err
+	}
+	if result[RespTypEve] != OperationSuccess {
+		return 0, fmt.Errorf("get db state error: %v", result[RespTypMsg])
+	}
+	opTimestamp, _ := result["opTimestamp"].(string)
+	return strconv.ParseInt(opTimestamp, 10, 64)
+}
+
+// Demote sends a demote operation request to Lorry, located on the target pod claiming the leader.
+func (cli *OperationClient) Demote(ctx context.Context) error {
+	result, err := cli.Request(ctx, string(DemoteOperation))
+	if err != nil {
+		return err
+	}
+	if result[RespTypEve] != OperationSuccess {
+		return fmt.Errorf("demote error: %v", result[RespTypMsg])
+	}
+	return nil
+}
+
+// CheckExternalDependency checks whether the external dependency is reachable from Lorry, located on the target pod,
+// which shares the network view of the DB.
+func (cli *OperationClient) CheckExternalDependency(ctx context.Context, dependency appsv1alpha1.ExternalDependency) error {
+	query := neturl.Values{}
+	query.Set("operation", string(CheckExternalDependencyOperation))
+	switch {
+	case dependency.TCPSocket != nil:
+		query.Set("host", dependency.TCPSocket.Host)
+		query.Set("port", strconv.Itoa(int(dependency.TCPSocket.Port)))
+	case dependency.HTTPGet != nil:
+		query.Set("url", dependency.HTTPGet.URL)
+	}
+	timeoutSeconds := defaultExternalDependencyTimeoutSeconds
+	if dependency.TimeoutSeconds > 0 {
+		timeoutSeconds = int(dependency.TimeoutSeconds)
+	}
+	query.Set("timeoutSeconds", strconv.Itoa(timeoutSeconds))
+
+	// the dependency is checked by Lorry within its timeout, so wait a bit longer than that.
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds+1)*time.Second)
+	defer cancel()
+	resp, err := cli.InvokeComponentInRoutine(ctxWithTimeout, fmt.Sprintf("%s?%s", cli.URL, query.Encode()), http.MethodGet, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	result := map[string]any{}
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(buf, &result); err != nil {
+		return err
+	}
+	switch result[RespTypEve] {
+	case OperationSuccess:
+		return nil
+	case OperationNotImplemented:
+		return ErrNotImplemented
+	default:
+		return fmt.Errorf("%v", result[RespTypMsg])
+	}
+}
+
 func (cli *OperationClient) Request(ctx context.Context, operation string) (map[string]any, error) {
 	ctxWithReconcileTimeout, cancel := context.WithTimeout(ctx, cli.ReconcileTimeout)
 	defer cancel()
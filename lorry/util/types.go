@@ -63,6 +63,10 @@ const (
 
 	JoinMemberOperation  OperationKind = "joinMember"
 	LeaveMemberOperation OperationKind = "leaveMember"
+	GetDBStateOperation  OperationKind = "getDBState"
+	DemoteOperation      OperationKind = "demote"
+
+	CheckExternalDependencyOperation OperationKind = "checkExternalDependency"
 
 	OperationNotImplemented = "NotImplemented"
 	OperationInvalid        = "Invalid"